@@ -0,0 +1,21 @@
+-- ============================================================================
+-- Kyber Accounting System - Revert Commodity Codes
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Drops the registered commodities and restores three-character currency columns and
+-- BIGINT amounts. Fails if rows hold longer commodity codes or amounts beyond BIGINT.
+
+ALTER TABLE transactions ALTER COLUMN amount TYPE BIGINT;
+ALTER TABLE budget_tracking
+    ALTER COLUMN actual_amount TYPE BIGINT,
+    ALTER COLUMN budgeted_amount TYPE BIGINT,
+    ALTER COLUMN target_amount TYPE BIGINT;
+ALTER TABLE accounts ALTER COLUMN balance_amount TYPE BIGINT;
+
+DROP TABLE IF EXISTS commodities;
+
+ALTER TABLE budget_items ALTER COLUMN currency TYPE CHAR(3);
+ALTER TABLE accounts ALTER COLUMN currency TYPE CHAR(3);
+
+COMMENT ON COLUMN budget_items.currency IS NULL;
+COMMENT ON COLUMN accounts.currency IS NULL;
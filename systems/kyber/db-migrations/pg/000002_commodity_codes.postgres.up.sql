@@ -0,0 +1,37 @@
+-- ============================================================================
+-- Kyber Accounting System - Commodity Codes
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Widens currency columns so accounts and budget items can hold non-ISO commodities
+-- (crypto, loyalty points, securities) whose codes are longer than three characters,
+-- and stores the commodities registered at runtime so amounts in them can still be
+-- decoded after a restart. Ledger base currencies remain ISO 4217 fiat codes.
+-- Amount columns become NUMERIC(38,0) minor units so 18-decimal commodities fit.
+
+-- Commodities: Non-ISO units amounts can be held in, registered on startup
+CREATE TABLE commodities (
+    code VARCHAR(16) PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('CRYPTO', 'POINTS', 'SECURITY')),
+    minor_units SMALLINT NOT NULL CHECK (minor_units BETWEEN 0 AND 18),
+    symbol VARCHAR(10),
+    name VARCHAR(255) NOT NULL CHECK (LENGTH(TRIM(name)) > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE commodities IS 'Crypto, loyalty points and securities registered alongside the ISO 4217 currencies';
+COMMENT ON COLUMN commodities.minor_units IS 'Decimal places of the minor unit amounts are stored in; up to 18 (wei) within NUMERIC(38,0) amount columns';
+COMMENT ON COLUMN commodities.symbol IS 'NULL when no symbol is commonly used';
+
+ALTER TABLE accounts ALTER COLUMN currency TYPE VARCHAR(16);
+ALTER TABLE budget_items ALTER COLUMN currency TYPE VARCHAR(16);
+
+COMMENT ON COLUMN accounts.currency IS 'ISO 4217 currency code or registered commodity code (e.g. BTC, KF_MILES)';
+COMMENT ON COLUMN budget_items.currency IS 'ISO 4217 currency code or registered commodity code';
+
+-- Amounts: Minor units of up to 18 decimal places keep 10^20 whole units within 38 digits
+ALTER TABLE accounts ALTER COLUMN balance_amount TYPE NUMERIC(38, 0);
+ALTER TABLE budget_tracking
+    ALTER COLUMN target_amount TYPE NUMERIC(38, 0),
+    ALTER COLUMN budgeted_amount TYPE NUMERIC(38, 0),
+    ALTER COLUMN actual_amount TYPE NUMERIC(38, 0);
+ALTER TABLE transactions ALTER COLUMN amount TYPE NUMERIC(38, 0);
@@ -0,0 +1,7 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Journal Entries
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP TABLE IF EXISTS journal_postings;
+DROP TABLE IF EXISTS journal_entries;
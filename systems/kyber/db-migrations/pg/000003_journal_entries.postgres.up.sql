@@ -0,0 +1,47 @@
+-- ============================================================================
+-- Kyber Accounting System - Journal Entries
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds double-entry journal entries. Each entry holds two or more postings against
+-- an account or a budget item; debits equal credits per currency (enforced by the domain).
+
+-- Journal Entries: Balanced records of a single economic event
+CREATE TABLE journal_entries (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE,
+    source_transaction_id UUID REFERENCES transactions(id) ON DELETE CASCADE,
+    description VARCHAR(500) NOT NULL CHECK (LENGTH(TRIM(description)) > 0),
+    entry_date TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Journal entries indexes
+CREATE INDEX idx_journal_entries_ledger_date ON journal_entries(ledger_id, entry_date DESC);
+CREATE UNIQUE INDEX idx_journal_entries_source_transaction ON journal_entries(source_transaction_id)
+    WHERE source_transaction_id IS NOT NULL;
+
+-- Journal entries comment
+COMMENT ON TABLE journal_entries IS 'Double-entry journal entries; postings balance per currency';
+
+-- Journal Postings: Debit or credit lines of a journal entry
+CREATE TABLE journal_postings (
+    journal_entry_id UUID NOT NULL REFERENCES journal_entries(id) ON DELETE CASCADE,
+    line_number INTEGER NOT NULL CHECK (line_number >= 0),
+    account_id UUID REFERENCES accounts(id) ON DELETE RESTRICT,
+    item_id UUID REFERENCES budget_items(id) ON DELETE RESTRICT,
+    side VARCHAR(6) NOT NULL CHECK (side IN ('DEBIT', 'CREDIT')),
+    amount NUMERIC(38, 0) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(16) NOT NULL CHECK (LENGTH(TRIM(currency)) > 0),
+    memo VARCHAR(500),
+
+    PRIMARY KEY (journal_entry_id, line_number),
+    CHECK ((account_id IS NULL) <> (item_id IS NULL))
+);
+
+-- Journal postings indexes
+CREATE INDEX idx_journal_postings_account ON journal_postings(account_id) WHERE account_id IS NOT NULL;
+CREATE INDEX idx_journal_postings_item ON journal_postings(item_id) WHERE item_id IS NOT NULL;
+
+-- Journal postings comment
+COMMENT ON TABLE journal_postings IS 'Postings of journal entries; amount is in minor units of currency, side gives direction';
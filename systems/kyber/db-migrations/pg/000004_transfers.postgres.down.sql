@@ -0,0 +1,7 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Transfers
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS transfer_id;
+DROP TABLE IF EXISTS transfers;
@@ -0,0 +1,29 @@
+-- ============================================================================
+-- Kyber Accounting System - Transfers
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds transfers between accounts. A transfer links an outgoing (negative) transaction
+-- on the source account and an incoming (positive) one on the destination account.
+
+-- Transfers: Links a pair of transactions moving money between two accounts
+CREATE TABLE transfers (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE,
+    fx_rate NUMERIC(28, 12) CHECK (fx_rate IS NULL OR fx_rate > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Transfers indexes
+CREATE INDEX idx_transfers_ledger_id ON transfers(ledger_id);
+
+-- Transfers comment
+COMMENT ON TABLE transfers IS 'Transfers between accounts; fx_rate is destination units per source unit when currencies differ';
+
+-- Transactions belonging to a transfer are removed with it
+ALTER TABLE transactions
+    ADD COLUMN transfer_id UUID REFERENCES transfers(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_transactions_transfer_id ON transactions(transfer_id) WHERE transfer_id IS NOT NULL;
+
+COMMENT ON COLUMN transactions.transfer_id IS 'Transfer this transaction is one side of; edited and deleted together with its pair';
@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Reconciliations
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP INDEX IF EXISTS idx_transactions_unreconciled;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS locked,
+    DROP COLUMN IF EXISTS reconciliation_id,
+    DROP COLUMN IF EXISTS status;
+DROP TABLE IF EXISTS reconciliations;
@@ -0,0 +1,41 @@
+-- ============================================================================
+-- Kyber Accounting System - Reconciliations
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds statement reconciliations. Transactions carry a cleared status; finishing a
+-- reconciliation marks the cleared ones as reconciled and locks them against edits.
+
+-- Reconciliations: An account matched against a bank statement
+CREATE TABLE reconciliations (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    statement_date DATE NOT NULL,
+    opening_balance NUMERIC(38, 0) NOT NULL,
+    closing_balance NUMERIC(38, 0) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('IN_PROGRESS', 'FINISHED')),
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CHECK ((status = 'FINISHED') = (finished_at IS NOT NULL))
+);
+
+-- Reconciliations indexes
+CREATE INDEX idx_reconciliations_ledger_id ON reconciliations(ledger_id);
+CREATE UNIQUE INDEX idx_reconciliations_in_progress ON reconciliations(account_id) WHERE status = 'IN_PROGRESS';
+CREATE INDEX idx_reconciliations_latest ON reconciliations(account_id, statement_date DESC) WHERE status = 'FINISHED';
+
+-- Reconciliations comment
+COMMENT ON TABLE reconciliations IS 'Statement reconciliations; balances are in minor units of the account currency';
+
+-- Transaction cleared status and reconciliation lock
+ALTER TABLE transactions
+    ADD COLUMN status VARCHAR(12) NOT NULL DEFAULT 'UNCLEARED' CHECK (status IN ('UNCLEARED', 'CLEARED', 'RECONCILED')),
+    ADD COLUMN reconciliation_id UUID REFERENCES reconciliations(id) ON DELETE SET NULL,
+    ADD COLUMN locked BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_transactions_unreconciled ON transactions(account_id, transaction_date) WHERE status <> 'RECONCILED';
+
+COMMENT ON COLUMN transactions.status IS 'UNCLEARED, CLEARED (seen on a statement) or RECONCILED';
+COMMENT ON COLUMN transactions.locked IS 'Reconciled transactions are locked: amount and date cannot change until unlocked';
@@ -0,0 +1,6 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Transaction Splits
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP TABLE IF EXISTS transaction_splits;
@@ -0,0 +1,24 @@
+-- ============================================================================
+-- Kyber Accounting System - Transaction Splits
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds split lines so one transaction can be spread across several budget items.
+-- The domain guarantees a split's lines sum to the transaction amount; transactions.item_id
+-- holds the first line's item.
+
+-- Transaction splits: Part of a transaction's amount assigned to a budget item
+CREATE TABLE transaction_splits (
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    line_no SMALLINT NOT NULL CHECK (line_no >= 0),
+    item_id UUID NOT NULL REFERENCES budget_items(id) ON DELETE RESTRICT,
+    amount NUMERIC(38, 0) NOT NULL CHECK (amount != 0),
+    note VARCHAR(500),
+
+    PRIMARY KEY (transaction_id, line_no)
+);
+
+-- Transaction splits indexes
+CREATE INDEX idx_transaction_splits_item_id ON transaction_splits(item_id);
+
+-- Transaction splits comment
+COMMENT ON TABLE transaction_splits IS 'Split lines of transactions spread across budget items; amounts are in minor units with the transaction sign';
@@ -0,0 +1,7 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Scheduled Transactions
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP TABLE IF EXISTS scheduled_occurrences;
+DROP TABLE IF EXISTS scheduled_transactions;
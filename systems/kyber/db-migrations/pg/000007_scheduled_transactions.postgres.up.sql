@@ -0,0 +1,49 @@
+-- ============================================================================
+-- Kyber Accounting System - Scheduled Transactions
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds scheduled transactions: templates repeating on an RFC 5545 RRULE that generate
+-- real transactions when they come due. Only occurrences that were skipped, modified or
+-- generated are stored; the rest are expanded from the rule.
+
+-- Scheduled transactions: A recurring transaction template, e.g. rent or salary
+CREATE TABLE scheduled_transactions (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES budget_items(id) ON DELETE RESTRICT,
+    counterparty_id UUID REFERENCES counterparties(id) ON DELETE SET NULL,
+    amount NUMERIC(38, 0) NOT NULL CHECK (amount != 0),
+    description VARCHAR(500) NOT NULL CHECK (LENGTH(TRIM(description)) > 0),
+    notes VARCHAR(2000),
+    rrule VARCHAR(500) NOT NULL,
+    start_date TIMESTAMPTZ NOT NULL,
+    last_generated TIMESTAMPTZ,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Scheduled transactions indexes
+CREATE INDEX idx_scheduled_transactions_ledger_active ON scheduled_transactions(ledger_id) WHERE is_active;
+CREATE INDEX idx_scheduled_transactions_account_id ON scheduled_transactions(account_id);
+
+-- Scheduled occurrences: A skipped, modified or generated occurrence of a scheduled transaction
+CREATE TABLE scheduled_occurrences (
+    schedule_id UUID NOT NULL REFERENCES scheduled_transactions(id) ON DELETE CASCADE,
+    occurrence_date TIMESTAMPTZ NOT NULL,
+    amount NUMERIC(38, 0) NOT NULL CHECK (amount != 0),
+    description VARCHAR(500) NOT NULL,
+    skipped BOOLEAN NOT NULL DEFAULT FALSE,
+    modified BOOLEAN NOT NULL DEFAULT FALSE,
+    transaction_id UUID UNIQUE REFERENCES transactions(id) ON DELETE SET NULL,
+
+    PRIMARY KEY (schedule_id, occurrence_date),
+    CHECK (NOT (skipped AND transaction_id IS NOT NULL))
+);
+
+-- Scheduled transactions comments
+COMMENT ON TABLE scheduled_transactions IS 'Recurring transaction templates; amount is in minor units of the account currency';
+COMMENT ON COLUMN scheduled_transactions.rrule IS 'RFC 5545 RRULE value; COUNT or UNTIL is the end condition';
+COMMENT ON COLUMN scheduled_transactions.last_generated IS 'Latest occurrence a transaction was generated for';
+COMMENT ON TABLE scheduled_occurrences IS 'Occurrences that differ from the template; the primary key makes generation idempotent per occurrence';
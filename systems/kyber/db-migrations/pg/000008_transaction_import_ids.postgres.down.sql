@@ -0,0 +1,7 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Transaction Import IDs
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP INDEX IF EXISTS idx_transactions_import_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS import_id;
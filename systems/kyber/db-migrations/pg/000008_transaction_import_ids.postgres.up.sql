@@ -0,0 +1,12 @@
+-- ============================================================================
+-- Kyber Accounting System - Transaction Import IDs
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds the bank-assigned ID (e.g. OFX FITID) of imported transactions so importing the
+-- same statement twice does not record its transactions twice.
+
+ALTER TABLE transactions ADD COLUMN import_id VARCHAR(255);
+
+CREATE UNIQUE INDEX idx_transactions_import_id ON transactions(account_id, import_id) WHERE import_id IS NOT NULL;
+
+COMMENT ON COLUMN transactions.import_id IS 'Bank-assigned ID of an imported transaction, unique per account';
@@ -0,0 +1,6 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop CSV Import Profiles
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP TABLE IF EXISTS csv_import_profiles;
@@ -0,0 +1,39 @@
+-- ============================================================================
+-- Kyber Accounting System - CSV Import Profiles
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds saved CSV layouts per account, so a bank's CSV export is mapped once and
+-- reused for every import. Column numbers are 0-based.
+
+-- CSV import profiles: How a bank lays out the CSV export of an account
+CREATE TABLE csv_import_profiles (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL CHECK (LENGTH(TRIM(name)) > 0),
+    delimiter VARCHAR(1) NOT NULL,
+    skip_rows INT NOT NULL DEFAULT 0 CHECK (skip_rows >= 0),
+    date_format VARCHAR(50) NOT NULL,
+    decimal_separator VARCHAR(1) NOT NULL CHECK (decimal_separator IN ('.', ',')),
+    thousands_separator VARCHAR(1),
+    sign_convention VARCHAR(20) NOT NULL CHECK (sign_convention IN ('INFLOW_POSITIVE', 'OUTFLOW_POSITIVE')),
+    date_column INT NOT NULL CHECK (date_column >= 0),
+    amount_column INT CHECK (amount_column >= 0),
+    debit_column INT CHECK (debit_column >= 0),
+    credit_column INT CHECK (credit_column >= 0),
+    payee_column INT CHECK (payee_column >= 0),
+    memo_column INT CHECK (memo_column >= 0),
+    reference_column INT CHECK (reference_column >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CHECK ((amount_column IS NULL) != (debit_column IS NULL AND credit_column IS NULL))
+);
+
+-- CSV import profiles indexes
+CREATE INDEX idx_csv_import_profiles_account_id ON csv_import_profiles(account_id);
+
+-- CSV import profiles comments
+COMMENT ON TABLE csv_import_profiles IS 'Saved CSV layouts of account statement exports';
+COMMENT ON COLUMN csv_import_profiles.date_format IS 'Date pattern such as DD/MM/YYYY or D MMM YY';
+COMMENT ON COLUMN csv_import_profiles.thousands_separator IS 'NULL when amounts have no thousands separator';
@@ -0,0 +1,9 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Transaction Rules
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP TABLE IF EXISTS transaction_rules;
+
+DROP INDEX IF EXISTS idx_transactions_tags;
+ALTER TABLE transactions DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,51 @@
+-- ============================================================================
+-- Kyber Accounting System - Transaction Rules
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds categorisation rules applied to transactions as they are created or imported,
+-- and free-form tags on transactions that rules can add.
+
+ALTER TABLE transactions ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_transactions_tags ON transactions USING GIN (tags);
+
+-- Transaction rules: Conditions a transaction must meet and the actions then applied to it
+CREATE TABLE transaction_rules (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL CHECK (LENGTH(TRIM(name)) > 0),
+    priority INT NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    description_contains VARCHAR(500),
+    description_pattern VARCHAR(500),
+    min_amount NUMERIC(38, 0),
+    max_amount NUMERIC(38, 0),
+    amount_currency VARCHAR(16),
+    account_id UUID REFERENCES accounts(id) ON DELETE CASCADE,
+    counterparty_type VARCHAR(30),
+    day_from INT CHECK (day_from BETWEEN 1 AND 31),
+    day_to INT CHECK (day_to BETWEEN 1 AND 31),
+    item_id UUID REFERENCES budget_items(id) ON DELETE RESTRICT,
+    counterparty_id UUID REFERENCES counterparties(id) ON DELETE CASCADE,
+    tag VARCHAR(100),
+    note VARCHAR(2000),
+    transfer_account_id UUID REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CHECK ((min_amount IS NULL AND max_amount IS NULL) = (amount_currency IS NULL)),
+    CHECK ((day_from IS NULL) = (day_to IS NULL)),
+    CHECK (transfer_account_id IS NULL OR item_id IS NOT NULL)
+);
+
+-- Transaction rules indexes
+CREATE INDEX idx_transaction_rules_ledger_priority ON transaction_rules(ledger_id, priority) WHERE enabled;
+
+-- Transaction rules comments
+COMMENT ON TABLE transaction_rules IS 'Categorisation rules; lowest priority is applied first';
+COMMENT ON COLUMN transaction_rules.min_amount IS 'Inclusive, in minor units of amount_currency; outflows are negative';
+COMMENT ON COLUMN transaction_rules.max_amount IS 'Inclusive, in minor units of amount_currency; outflows are negative';
+COMMENT ON COLUMN transaction_rules.description_pattern IS 'Go RE2 regular expression matched against the description';
+COMMENT ON COLUMN transaction_rules.day_from IS 'Day of month range; day_from > day_to wraps around the month end';
+COMMENT ON COLUMN transaction_rules.transfer_account_id IS 'Marks matching transactions as transfers with this account';
+COMMENT ON COLUMN transactions.tags IS 'Free-form lower case labels';
@@ -0,0 +1,7 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Transaction Search
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP INDEX IF EXISTS idx_transactions_search_vector;
+ALTER TABLE transactions DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,14 @@
+-- ============================================================================
+-- Kyber Accounting System - Transaction Search
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds full-text search over the description and notes of transactions. The 'simple'
+-- configuration is used as descriptions are mostly merchant names in any language,
+-- which stemming would mangle.
+
+ALTER TABLE transactions ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', description || ' ' || COALESCE(notes, ''))) STORED;
+
+CREATE INDEX idx_transactions_search_vector ON transactions USING GIN (search_vector);
+
+COMMENT ON COLUMN transactions.search_vector IS 'Full-text index of description and notes; queried with websearch_to_tsquery(''simple'', ...)';
@@ -0,0 +1,14 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Attachments
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP TRIGGER IF EXISTS trg_ledgers_attachments_owner_deleted ON ledgers;
+DROP TRIGGER IF EXISTS trg_counterparties_attachments_owner_deleted ON counterparties;
+DROP TRIGGER IF EXISTS trg_transactions_attachments_owner_deleted ON transactions;
+DROP FUNCTION IF EXISTS mark_ledger_attachments_owner_deleted();
+DROP FUNCTION IF EXISTS mark_counterparty_attachments_owner_deleted();
+DROP FUNCTION IF EXISTS mark_transaction_attachments_owner_deleted();
+
+DROP TABLE IF EXISTS attachments;
+DROP TABLE IF EXISTS attachment_contents;
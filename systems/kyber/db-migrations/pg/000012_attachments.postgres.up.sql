@@ -0,0 +1,88 @@
+-- ============================================================================
+-- Kyber Accounting System - Attachments
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds receipts, invoices and other documents attached to transactions and counterparties.
+-- File contents live in blob storage; these tables hold their metadata.
+-- They do not reference ledgers: deleting a ledger marks its attachments as having a deleted owner,
+-- so they and their files are purged after the retention period like those of any deleted owner.
+
+-- Attachment contents: Files stored once per ledger, addressed by their SHA-256 digest
+CREATE TABLE attachment_contents (
+    ledger_id UUID NOT NULL,
+    sha256 CHAR(64) NOT NULL CHECK (sha256 ~ '^[0-9a-f]{64}$'),
+    size BIGINT NOT NULL CHECK (size > 0),
+    content_type VARCHAR(100) NOT NULL CHECK (LENGTH(TRIM(content_type)) > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    PRIMARY KEY (ledger_id, sha256)
+);
+
+-- Attachment contents indexes
+CREATE INDEX idx_attachment_contents_deleted_at ON attachment_contents(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Attachments: A stored file attached to a transaction or counterparty
+CREATE TABLE attachments (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL,
+    owner_type VARCHAR(20) NOT NULL CHECK (owner_type IN ('TRANSACTION', 'COUNTERPARTY')),
+    owner_id UUID NOT NULL,
+    file_name VARCHAR(255) NOT NULL CHECK (LENGTH(TRIM(file_name)) > 0),
+    sha256 CHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    owner_deleted_at TIMESTAMPTZ,
+
+    FOREIGN KEY (ledger_id, sha256) REFERENCES attachment_contents(ledger_id, sha256)
+);
+
+-- Attachments indexes
+CREATE INDEX idx_attachments_owner ON attachments(ledger_id, owner_type, owner_id);
+CREATE INDEX idx_attachments_content ON attachments(ledger_id, sha256);
+CREATE INDEX idx_attachments_owner_deleted_at ON attachments(owner_deleted_at) WHERE owner_deleted_at IS NOT NULL;
+
+-- Owners are polymorphic so deletions are tracked by triggers rather than foreign keys. This also
+-- covers transactions removed with their transfer or account.
+CREATE FUNCTION mark_transaction_attachments_owner_deleted() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE attachments SET owner_deleted_at = NOW()
+    WHERE owner_type = 'TRANSACTION' AND owner_id = OLD.id AND owner_deleted_at IS NULL;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION mark_counterparty_attachments_owner_deleted() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE attachments SET owner_deleted_at = NOW()
+    WHERE owner_type = 'COUNTERPARTY' AND owner_id = OLD.id AND owner_deleted_at IS NULL;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION mark_ledger_attachments_owner_deleted() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE attachments SET owner_deleted_at = NOW()
+    WHERE ledger_id = OLD.id AND owner_deleted_at IS NULL;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_transactions_attachments_owner_deleted
+    AFTER DELETE ON transactions
+    FOR EACH ROW EXECUTE FUNCTION mark_transaction_attachments_owner_deleted();
+
+CREATE TRIGGER trg_counterparties_attachments_owner_deleted
+    AFTER DELETE ON counterparties
+    FOR EACH ROW EXECUTE FUNCTION mark_counterparty_attachments_owner_deleted();
+
+CREATE TRIGGER trg_ledgers_attachments_owner_deleted
+    AFTER DELETE ON ledgers
+    FOR EACH ROW EXECUTE FUNCTION mark_ledger_attachments_owner_deleted();
+
+-- Attachments comments
+COMMENT ON TABLE attachment_contents IS 'Attached files, stored in blob storage under ledgers/<ledger_id>/<sha256>';
+COMMENT ON COLUMN attachment_contents.content_type IS 'Sniffed from the file, not taken from the uploader';
+COMMENT ON COLUMN attachment_contents.deleted_at IS 'When the last attachment of the file was removed; the purge job then deletes the file and this row';
+COMMENT ON TABLE attachments IS 'Files attached to transactions and counterparties';
+COMMENT ON COLUMN attachments.owner_id IS 'ID of the transaction or counterparty named by owner_type';
+COMMENT ON COLUMN attachments.owner_deleted_at IS 'When the owner was deleted; purged after the retention period';
@@ -0,0 +1,12 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Transaction Holds
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP INDEX IF EXISTS idx_transactions_pending;
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS chk_transactions_hold_amount,
+    DROP CONSTRAINT IF EXISTS chk_transactions_hold_uncleared,
+    DROP COLUMN IF EXISTS hold_amount,
+    DROP COLUMN IF EXISTS posting_status;
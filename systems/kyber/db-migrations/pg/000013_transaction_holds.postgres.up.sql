@@ -0,0 +1,19 @@
+-- ============================================================================
+-- Kyber Accounting System - Transaction Holds
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds the pending -> posted -> voided lifecycle of card authorisation holds. Only posted
+-- transactions are part of the account balance; existing transactions are posted.
+
+ALTER TABLE transactions
+    ADD COLUMN posting_status VARCHAR(20) NOT NULL DEFAULT 'POSTED'
+        CHECK (posting_status IN ('PENDING', 'POSTED', 'VOIDED')),
+    ADD COLUMN hold_amount NUMERIC(38, 0),
+    ADD CONSTRAINT chk_transactions_hold_uncleared CHECK (posting_status = 'POSTED' OR status = 'UNCLEARED'),
+    ADD CONSTRAINT chk_transactions_hold_amount CHECK (posting_status = 'POSTED' OR hold_amount IS NOT NULL);
+
+CREATE INDEX idx_transactions_pending ON transactions(ledger_id, account_id, transaction_date)
+    WHERE posting_status = 'PENDING';
+
+COMMENT ON COLUMN transactions.posting_status IS 'PENDING holds count against the available balance only; VOIDED holds count nowhere';
+COMMENT ON COLUMN transactions.hold_amount IS 'Amount authorised by the hold the transaction started as, in minor units';
@@ -0,0 +1,6 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Credit Cards
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP TABLE IF EXISTS credit_cards;
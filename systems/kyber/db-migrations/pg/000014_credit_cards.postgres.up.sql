@@ -0,0 +1,32 @@
+-- ============================================================================
+-- Kyber Accounting System - Credit Cards
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds the billing terms of credit card accounts. Statement cycles, amounts due and
+-- overdue cycles are derived from the account's transactions with these terms.
+
+-- Credit cards: Billing terms of CREDIT_CARD accounts, one row per account
+CREATE TABLE credit_cards (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    ledger_id UUID NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE,
+    closing_day INT NOT NULL CHECK (closing_day BETWEEN 1 AND 31),
+    payment_due_days INT NOT NULL CHECK (payment_due_days BETWEEN 1 AND 28),
+    minimum_payment_percent NUMERIC(7, 4) NOT NULL CHECK (minimum_payment_percent BETWEEN 0 AND 100),
+    minimum_payment_floor NUMERIC(38, 0) NOT NULL CHECK (minimum_payment_floor >= 0),
+    credit_limit NUMERIC(38, 0) NOT NULL CHECK (credit_limit >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CHECK (minimum_payment_percent > 0 OR minimum_payment_floor > 0)
+);
+
+-- Credit cards indexes
+CREATE INDEX idx_credit_cards_ledger_id ON credit_cards(ledger_id);
+
+-- Credit cards comments
+COMMENT ON TABLE credit_cards IS 'Statement closing day, payment terms and credit limit of credit card accounts';
+COMMENT ON COLUMN credit_cards.closing_day IS 'Day of the month statements close on; the last day in shorter months';
+COMMENT ON COLUMN credit_cards.payment_due_days IS 'Days from the closing date to the payment due date';
+COMMENT ON COLUMN credit_cards.minimum_payment_percent IS 'Percentage points of the statement balance due at minimum, rounded up';
+COMMENT ON COLUMN credit_cards.minimum_payment_floor IS 'Smallest minimum payment in minor units of the account currency';
+COMMENT ON COLUMN credit_cards.credit_limit IS 'Credit limit in minor units of the account currency';
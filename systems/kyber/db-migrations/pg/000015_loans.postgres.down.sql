@@ -0,0 +1,7 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Loans
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP TABLE IF EXISTS loan_rates;
+DROP TABLE IF EXISTS loans;
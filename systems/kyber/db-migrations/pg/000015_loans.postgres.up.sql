@@ -0,0 +1,46 @@
+-- ============================================================================
+-- Kyber Accounting System - Loans
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds the terms of loan, mortgage and installment accounts. Amortisation schedules and
+-- prepayment what-ifs are derived from these terms and the rates in force over time.
+
+-- Loans: Terms of LOAN, MORTGAGE and INSTALLMENT accounts, one row per account
+CREATE TABLE loans (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    ledger_id UUID NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE,
+    principal NUMERIC(38, 0) NOT NULL CHECK (principal > 0),
+    rate_type VARCHAR(20) NOT NULL CHECK (rate_type IN ('FIXED', 'VARIABLE')),
+    term_months INT NOT NULL CHECK (term_months BETWEEN 1 AND 600),
+    payment_frequency VARCHAR(20) NOT NULL
+        CHECK (payment_frequency IN ('WEEKLY', 'FORTNIGHTLY', 'MONTHLY', 'QUARTERLY')),
+    start_date DATE NOT NULL,
+    rounding_mode VARCHAR(20) NOT NULL
+        CHECK (rounding_mode IN ('HALF_UP', 'HALF_EVEN', 'HALF_DOWN', 'CEILING', 'FLOOR', 'TRUNCATE')),
+    accrued_from DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CHECK (accrued_from >= start_date)
+);
+
+-- Loan rates: Annual interest rates of a loan from their effective dates; the first one takes effect on the start date
+CREATE TABLE loan_rates (
+    account_id UUID NOT NULL REFERENCES loans(account_id) ON DELETE CASCADE,
+    effective_date DATE NOT NULL,
+    annual_rate NUMERIC(7, 4) NOT NULL CHECK (annual_rate BETWEEN 0 AND 100),
+
+    PRIMARY KEY (account_id, effective_date)
+);
+
+-- Loans indexes
+CREATE INDEX idx_loans_ledger_id ON loans(ledger_id);
+
+-- Loans comments
+COMMENT ON TABLE loans IS 'Principal, interest, term and repayment frequency of loan accounts';
+COMMENT ON COLUMN loans.principal IS 'Amount borrowed in minor units of the account currency';
+COMMENT ON COLUMN loans.rate_type IS 'FIXED loans keep their first rate; VARIABLE loans have dated rate changes';
+COMMENT ON COLUMN loans.rounding_mode IS 'How interest and payments are rounded to the currency minor units';
+COMMENT ON COLUMN loans.accrued_from IS 'Date interest accrues from: the last recorded repayment, or the start date';
+COMMENT ON TABLE loan_rates IS 'Annual interest rates of loans from their effective dates';
+COMMENT ON COLUMN loan_rates.annual_rate IS 'Annual interest rate in percentage points';
@@ -0,0 +1,6 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Investment Activities
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+DROP TABLE IF EXISTS investment_activities;
@@ -0,0 +1,39 @@
+-- ============================================================================
+-- Kyber Accounting System - Investment Activities
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Adds the buys, sells, dividends, splits and fees of investment accounts. Holdings, lots and
+-- realised gains are derived from these activities; the account balance stays the cash balance.
+
+-- Investment activities: What happened to the holdings and cash of INVESTMENT accounts
+CREATE TABLE investment_activities (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    activity_type VARCHAR(20) NOT NULL CHECK (activity_type IN ('BUY', 'SELL', 'DIVIDEND', 'SPLIT', 'FEE')),
+    security VARCHAR(16),
+    quantity NUMERIC(38, 0) NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    amount NUMERIC(38, 0) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    fee NUMERIC(38, 0) NOT NULL DEFAULT 0 CHECK (fee >= 0),
+    split_ratio NUMERIC(20, 10),
+    transaction_id UUID UNIQUE REFERENCES transactions(id) ON DELETE SET NULL,
+    activity_date DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CHECK ((activity_type = 'FEE') = (security IS NULL)),
+    CHECK ((activity_type IN ('BUY', 'SELL')) = (quantity > 0)),
+    CHECK ((activity_type = 'SPLIT') = (split_ratio IS NOT NULL))
+);
+
+-- Investment activities indexes
+CREATE INDEX idx_investment_activities_account_date ON investment_activities(account_id, activity_date);
+CREATE INDEX idx_investment_activities_ledger_id ON investment_activities(ledger_id);
+
+-- Investment activities comments
+COMMENT ON TABLE investment_activities IS 'Buys, sells, dividends, splits and fees of investment accounts';
+COMMENT ON COLUMN investment_activities.security IS 'Code of the security commodity; NULL for account fees';
+COMMENT ON COLUMN investment_activities.quantity IS 'Units bought or sold in minor units of the security';
+COMMENT ON COLUMN investment_activities.amount IS 'Trade cost or proceeds before the fee, or the dividend or fee, in minor units of the account currency';
+COMMENT ON COLUMN investment_activities.fee IS 'Brokerage of a trade in minor units of the account currency';
+COMMENT ON COLUMN investment_activities.split_ratio IS 'Units held after a split per unit held before it';
+COMMENT ON COLUMN investment_activities.transaction_id IS 'Transaction recording the cash side of the activity; NULL for splits';
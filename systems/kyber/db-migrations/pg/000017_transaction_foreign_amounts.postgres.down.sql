@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Kyber Accounting System - Drop Transaction Foreign Amounts
+-- ============================================================================
+-- Database: PostgreSQL 12+
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS chk_transactions_foreign_amount,
+    DROP COLUMN IF EXISTS fx_fee,
+    DROP COLUMN IF EXISTS fx_rate,
+    DROP COLUMN IF EXISTS original_currency,
+    DROP COLUMN IF EXISTS original_amount;
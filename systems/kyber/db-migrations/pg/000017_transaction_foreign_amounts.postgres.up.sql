@@ -0,0 +1,22 @@
+-- ============================================================================
+-- Kyber Accounting System - Transaction Foreign Amounts
+-- ============================================================================
+-- Database: PostgreSQL 12+
+-- Records what transactions charged in another currency than their account's were charged,
+-- e.g. USD on an SGD card. The amount stays the settled amount in the account currency.
+
+ALTER TABLE transactions
+    ADD COLUMN original_amount NUMERIC(38, 0) CHECK (original_amount IS NULL OR original_amount != 0),
+    ADD COLUMN original_currency VARCHAR(16),
+    ADD COLUMN fx_rate NUMERIC(28, 12) CHECK (fx_rate IS NULL OR fx_rate > 0),
+    ADD COLUMN fx_fee NUMERIC(38, 0),
+    ADD CONSTRAINT chk_transactions_foreign_amount CHECK (
+        (original_amount IS NULL) = (original_currency IS NULL)
+        AND (original_amount IS NULL) = (fx_rate IS NULL)
+        AND (original_amount IS NULL) = (fx_fee IS NULL)
+    );
+
+COMMENT ON COLUMN transactions.original_amount IS 'Amount charged in the foreign currency in its minor units; NULL if charged in the account currency';
+COMMENT ON COLUMN transactions.original_currency IS 'Foreign currency the transaction was charged in';
+COMMENT ON COLUMN transactions.fx_rate IS 'Effective account currency units per original currency unit, before the FX fee';
+COMMENT ON COLUMN transactions.fx_fee IS 'FX fee in minor units of the account currency, included in amount';
@@ -41,6 +41,14 @@ func NewAccount(
 		return nil, fmt.Errorf("account currency cannot be empty")
 	}
 
+	if err := currency.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid account currency: %w", err)
+	}
+
+	if !accountType.HoldsCommodityKind(currency.Kind()) {
+		return nil, fmt.Errorf("%s account cannot hold %s commodity %s", accountType, currency.Kind(), currency)
+	}
+
 	// Initialize with zero balance
 	balance, err := money.Zero(currency)
 	if err != nil {
@@ -219,3 +227,72 @@ func (a *Account) CreditBalance(amount money.Money) error {
 	a.UpdatedAt = time.Now()
 	return nil
 }
+
+// ApplyTransaction adds a newly recorded or newly posted transaction of this account to the balance.
+// Pending and voided transactions are not part of the balance and leave it unchanged.
+func (a *Account) ApplyTransaction(tx *Transaction) error {
+	if !tx.AccountID.Equals(a.ID) {
+		return fmt.Errorf("transaction %s belongs to another account", tx.ID)
+	}
+
+	if !tx.PostingStatus.IsPosted() {
+		return nil
+	}
+
+	if tx.Amount.IsNegative() {
+		return a.DebitBalance(tx.Amount.Abs())
+	}
+	return a.CreditBalance(tx.Amount)
+}
+
+// ApplyStatementTransaction adds a transaction taken from a bank statement to the balance. The statement
+// is the bank's own record of money that already moved, so unlike ApplyTransaction an asset account may
+// go overdrawn: its lines are not refused because they arrive in an unlucky order.
+func (a *Account) ApplyStatementTransaction(tx *Transaction) error {
+	if !tx.AccountID.Equals(a.ID) {
+		return fmt.Errorf("transaction %s belongs to another account", tx.ID)
+	}
+
+	if !tx.PostingStatus.IsPosted() {
+		return nil
+	}
+
+	return a.adjustBalance(tx.Amount)
+}
+
+// adjustBalance adds a signed amount to the balance without checking for an overdraft
+func (a *Account) adjustBalance(amount money.Money) error {
+	newBalance, err := a.Balance.Add(amount)
+	if err != nil {
+		return fmt.Errorf("failed to adjust balance: %w", err)
+	}
+
+	a.Balance = newBalance
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+// RecalculateBalance replaces the balance with one derived from the postings of the given entries
+func (a *Account) RecalculateBalance(entries []*JournalEntry) error {
+	balance, err := DeriveAccountBalance(a.ID, a.Currency, entries)
+	if err != nil {
+		return fmt.Errorf("failed to derive balance: %w", err)
+	}
+
+	a.Balance = balance
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+// TotalBalance sums the balances of the given accounts into per-currency subtotals
+func TotalBalance(accounts ...*Account) (money.Bag, error) {
+	var total money.Bag
+	for _, a := range accounts {
+		var err error
+		total, err = total.Add(a.Balance)
+		if err != nil {
+			return money.Bag{}, fmt.Errorf("failed to add balance of account %s: %w", a.ID, err)
+		}
+	}
+	return total, nil
+}
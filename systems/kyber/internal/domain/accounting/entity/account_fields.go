@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/kneadCODE/coruscant/shared/golib/id"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
 )
 
 // AccountID represents a unique identifier for an account using UUIDv7
@@ -106,6 +108,36 @@ func (a AccountType) IsLiability() bool {
 	}
 }
 
+// IsLoan checks if the account type is repaid in installments under loan terms
+func (a AccountType) IsLoan() bool {
+	switch a {
+	case AccountTypeInstallment,
+		AccountTypeLoan,
+		AccountTypeMortgage:
+		return true
+	default:
+		return false
+	}
+}
+
+// HoldsCommodityKind checks if the account type can be denominated in the commodity kind.
+// Investment accounts hold securities and crypto, digital wallets hold crypto and points,
+// and every other account type holds fiat only.
+func (a AccountType) HoldsCommodityKind(kind money.CommodityKind) bool {
+	switch kind {
+	case money.CommodityKindFiat:
+		return true
+	case money.CommodityKindSecurity:
+		return a == AccountTypeInvestment
+	case money.CommodityKindCrypto:
+		return a == AccountTypeInvestment || a == AccountTypeDigitalWallet
+	case money.CommodityKindPoints:
+		return a == AccountTypeDigitalWallet
+	default:
+		return false
+	}
+}
+
 // func (a AccountType) IsEquity() bool {
 // }
 
@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
 )
 
 func TestAccountID_NewAccountID(t *testing.T) {
@@ -309,6 +311,41 @@ func TestAccountType_String(t *testing.T) {
 	}
 }
 
+func TestAccountType_HoldsCommodityKind(t *testing.T) {
+	tests := []struct {
+		accountType AccountType
+		kind        money.CommodityKind
+		expected    bool
+	}{
+		{AccountTypeChecking, money.CommodityKindFiat, true},
+		{AccountTypeCreditCard, money.CommodityKindFiat, true},
+		{AccountTypeChecking, money.CommodityKindCrypto, false},
+		{AccountTypeSavings, money.CommodityKindPoints, false},
+		{AccountTypeInvestment, money.CommodityKindSecurity, true},
+		{AccountTypeInvestment, money.CommodityKindCrypto, true},
+		{AccountTypeInvestment, money.CommodityKindPoints, false},
+		{AccountTypeDigitalWallet, money.CommodityKindCrypto, true},
+		{AccountTypeDigitalWallet, money.CommodityKindPoints, true},
+		{AccountTypeDigitalWallet, money.CommodityKindSecurity, false},
+		{AccountTypeInvestment, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.accountType)+"/"+string(tt.kind), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.accountType.HoldsCommodityKind(tt.kind))
+		})
+	}
+}
+
+func TestAccountType_IsLoan(t *testing.T) {
+	for _, accountType := range []AccountType{AccountTypeLoan, AccountTypeMortgage, AccountTypeInstallment} {
+		assert.True(t, accountType.IsLoan(), accountType)
+		assert.True(t, accountType.IsLiability(), accountType)
+	}
+	assert.False(t, AccountTypeCreditCard.IsLoan())
+	assert.False(t, AccountTypeChecking.IsLoan())
+}
+
 func TestAccountStatus_IsActive(t *testing.T) {
 	tests := []struct {
 		name     string
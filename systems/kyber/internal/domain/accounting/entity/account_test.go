@@ -64,6 +64,44 @@ func TestNewAccount(t *testing.T) {
 			wantErr:     true,
 			errContains: "account currency cannot be empty",
 		},
+		{
+			name:        "unsupported currency",
+			ledgerID:    ledgerID,
+			accountName: "Test Account",
+			description: "Test description",
+			accountType: AccountTypeChecking,
+			currency:    "XYZ",
+			wantErr:     true,
+			errContains: "unsupported currency",
+		},
+		{
+			name:        "valid crypto investment account",
+			ledgerID:    ledgerID,
+			accountName: "Cold Wallet",
+			description: "Bitcoin savings",
+			accountType: AccountTypeInvestment,
+			currency:    money.CurrencyBTC,
+			wantErr:     false,
+		},
+		{
+			name:        "crypto in checking account",
+			ledgerID:    ledgerID,
+			accountName: "Checking Account",
+			description: "Test description",
+			accountType: AccountTypeChecking,
+			currency:    money.CurrencyBTC,
+			wantErr:     true,
+			errContains: "CHECKING account cannot hold CRYPTO commodity BTC",
+		},
+		{
+			name:        "valid zero-decimal currency account",
+			ledgerID:    ledgerID,
+			accountName: "Yen Wallet",
+			description: "Travel cash",
+			accountType: AccountTypeCash,
+			currency:    "JPY",
+			wantErr:     false,
+		},
 		{
 			name:        "valid credit card account",
 			ledgerID:    ledgerID,
@@ -463,6 +501,30 @@ func TestAccount_CreditBalance(t *testing.T) {
 	assert.Equal(t, expectedBalance, account.Balance)
 }
 
+func TestTotalBalance(t *testing.T) {
+	usd := createTestAccount(t)
+	require.NoError(t, usd.CreditBalance(mustMoney(t, "100.00", "USD")))
+
+	usd2 := createTestAccount(t)
+	require.NoError(t, usd2.CreditBalance(mustMoney(t, "25.50", "USD")))
+
+	ledgerID, err := entity.NewLedgerID()
+	require.NoError(t, err)
+	card, err := NewAccount(ledgerID, "Card", "", AccountTypeCreditCard, "SGD")
+	require.NoError(t, err)
+	require.NoError(t, card.DebitBalance(mustMoney(t, "40.00", "SGD")))
+
+	total, err := TotalBalance(usd, usd2, card)
+	require.NoError(t, err)
+	assert.Equal(t, []money.Currency{money.CurrencySGD, money.CurrencyUSD}, total.Currencies())
+	assert.True(t, total.Get(money.CurrencyUSD).Equals(mustMoney(t, "125.50", "USD")))
+	assert.True(t, total.Get(money.CurrencySGD).Equals(mustMoney(t, "-40.00", "SGD")))
+
+	empty, err := TotalBalance()
+	require.NoError(t, err)
+	assert.True(t, empty.IsZero())
+}
+
 // Helper functions
 
 func createTestAccount(t *testing.T) *Account {
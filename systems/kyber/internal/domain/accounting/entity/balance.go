@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// BalanceEntry is a transaction together with the account balance right after it
+type BalanceEntry struct {
+	Transaction *Transaction
+	Balance     money.Money
+}
+
+// RunningBalances applies the transactions, oldest first, to the opening balance.
+// Pending and voided transactions are listed without changing the balance.
+func RunningBalances(opening money.Money, transactions []*Transaction) ([]BalanceEntry, error) {
+	entries := make([]BalanceEntry, 0, len(transactions))
+	balance := opening
+	for _, tx := range transactions {
+		if tx.PostingStatus.IsPosted() {
+			var err error
+			balance, err = balance.Add(tx.Amount)
+			if err != nil {
+				return nil, fmt.Errorf("failed to apply transaction %s: %w", tx.ID, err)
+			}
+		}
+		entries = append(entries, BalanceEntry{Transaction: tx, Balance: balance})
+	}
+	return entries, nil
+}
+
+// AccountBalances are an account's balances as a bank reports them
+type AccountBalances struct {
+	AccountID AccountID
+	Current   money.Money // Posted transactions only
+	Pending   money.Money // Sum of pending holds
+	Available money.Money // Current less pending outflows; pending inflows are not available until they post
+}
+
+// NewAccountBalances derives the available balance from the current balance and the account's pending holds
+func NewAccountBalances(accountID AccountID, current money.Money, pending []*Transaction) (AccountBalances, error) {
+	zero, err := money.Zero(current.Currency)
+	if err != nil {
+		return AccountBalances{}, err
+	}
+	balances := AccountBalances{AccountID: accountID, Current: current, Pending: zero, Available: current}
+
+	for _, tx := range pending {
+		if !tx.PostingStatus.IsPending() || !tx.AccountID.Equals(accountID) {
+			continue
+		}
+
+		if balances.Pending, err = balances.Pending.Add(tx.Amount); err != nil {
+			return AccountBalances{}, fmt.Errorf("failed to apply hold %s: %w", tx.ID, err)
+		}
+
+		if tx.Amount.IsNegative() {
+			if balances.Available, err = balances.Available.Add(tx.Amount); err != nil {
+				return AccountBalances{}, fmt.Errorf("failed to apply hold %s: %w", tx.ID, err)
+			}
+		}
+	}
+	return balances, nil
+}
+
+// BalanceDrift compares an account's stored balance with the one derived from its transactions
+type BalanceDrift struct {
+	AccountID  AccountID
+	Stored     money.Money
+	Derived    money.Money
+	Difference money.Money // Stored minus derived
+}
+
+// HasDrift checks if the stored balance differs from the derived one
+func (d BalanceDrift) HasDrift() bool {
+	return !d.Difference.IsZero()
+}
+
+// CheckDrift compares the stored balance with one derived from the account's transactions
+func (a *Account) CheckDrift(derived money.Money) (BalanceDrift, error) {
+	difference, err := a.Balance.Subtract(derived)
+	if err != nil {
+		return BalanceDrift{}, fmt.Errorf("failed to compare balance of account %s: %w", a.ID, err)
+	}
+
+	return BalanceDrift{
+		AccountID:  a.ID,
+		Stored:     a.Balance,
+		Derived:    derived,
+		Difference: difference,
+	}, nil
+}
@@ -0,0 +1,125 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunningBalances(t *testing.T) {
+	account := createTestAccount(t)
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var transactions []*Transaction
+	for i, amount := range []string{"100.00", "-30.50", "-80.00", "12.25"} {
+		tx, err := NewTransaction(account.LedgerID, account.ID, createTestTransaction(t).ItemID,
+			mustMoney(t, amount, "USD"), "Test", base.AddDate(0, 0, i))
+		require.NoError(t, err)
+		transactions = append(transactions, tx)
+	}
+
+	entries, err := RunningBalances(mustMoney(t, "10.00", "USD"), transactions)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	want := []string{"110.00 USD", "79.50 USD", "-0.50 USD", "11.75 USD"}
+	for i, entry := range entries {
+		assert.Same(t, transactions[i], entry.Transaction)
+		assert.Equal(t, want[i], entry.Balance.String())
+	}
+
+	entries, err = RunningBalances(mustMoney(t, "10.00", "USD"), nil)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = RunningBalances(mustMoney(t, "10.00", "EUR"), transactions)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to apply transaction")
+}
+
+func TestRunningBalances_Pending(t *testing.T) {
+	account := createTestAccount(t)
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var transactions []*Transaction
+	for i, amount := range []string{"100.00", "-30.00", "-20.00"} {
+		tx, err := NewTransaction(account.LedgerID, account.ID, createTestTransaction(t).ItemID,
+			mustMoney(t, amount, "USD"), "Test", base.AddDate(0, 0, i))
+		require.NoError(t, err)
+		transactions = append(transactions, tx)
+	}
+	require.NoError(t, transactions[1].MarkPending())
+	require.NoError(t, transactions[2].MarkPending())
+	require.NoError(t, transactions[2].Void())
+
+	entries, err := RunningBalances(mustMoney(t, "0.00", "USD"), transactions)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		assert.Equal(t, "100.00 USD", entry.Balance.String())
+	}
+}
+
+func TestNewAccountBalances(t *testing.T) {
+	account := createTestAccount(t)
+
+	var pending []*Transaction
+	for _, amount := range []string{"-30.00", "-20.00", "15.00", "-5.00"} {
+		tx, err := NewTransaction(account.LedgerID, account.ID, createTestTransaction(t).ItemID,
+			mustMoney(t, amount, "USD"), "Test", time.Now())
+		require.NoError(t, err)
+		require.NoError(t, tx.MarkPending())
+		pending = append(pending, tx)
+	}
+	require.NoError(t, pending[3].Void())
+
+	balances, err := NewAccountBalances(account.ID, mustMoney(t, "100.00", "USD"), pending)
+	require.NoError(t, err)
+	assert.True(t, balances.AccountID.Equals(account.ID))
+	assert.Equal(t, "100.00 USD", balances.Current.String())
+	assert.Equal(t, "-35.00 USD", balances.Pending.String())
+	assert.Equal(t, "50.00 USD", balances.Available.String(), "pending inflows are not available")
+
+	pending[0].Amount = mustMoney(t, "-30.00", "EUR")
+	_, err = NewAccountBalances(account.ID, mustMoney(t, "100.00", "USD"), pending)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to apply hold")
+}
+
+func TestAccount_CheckDrift(t *testing.T) {
+	tests := []struct {
+		name           string
+		stored         string
+		derived        string
+		currency       string
+		wantErr        bool
+		wantDrift      bool
+		wantDifference string
+	}{
+		{name: "in sync", stored: "250.00", derived: "250.00", currency: "USD", wantDifference: "0.00 USD"},
+		{name: "stored too high", stored: "250.00", derived: "200.00", currency: "USD", wantDrift: true, wantDifference: "50.00 USD"},
+		{name: "stored too low", stored: "-10.00", derived: "5.00", currency: "USD", wantDrift: true, wantDifference: "-15.00 USD"},
+		{name: "currency mismatch", stored: "1.00", derived: "1.00", currency: "EUR", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := createTestAccount(t)
+			account.Balance = mustMoney(t, tt.stored, "USD")
+
+			drift, err := account.CheckDrift(mustMoney(t, tt.derived, tt.currency))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, drift.AccountID.Equals(account.ID))
+			assert.Equal(t, tt.wantDrift, drift.HasDrift())
+			assert.Equal(t, tt.wantDifference, drift.Difference.String())
+			assert.True(t, drift.Stored.Equals(account.Balance))
+		})
+	}
+}
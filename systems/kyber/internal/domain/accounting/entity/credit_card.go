@@ -0,0 +1,330 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// maxPaymentDueDays keeps the due date before the next statement closes, even after a 28-day February cycle,
+// so every payment made between two closing dates pays the earlier statement
+const maxPaymentDueDays = 28
+
+// CreditCard holds the billing terms of a credit card account. Its statement cycles are derived from
+// the account's transactions rather than stored, see Cycles.
+type CreditCard struct {
+	AccountID      AccountID
+	LedgerID       ledgerEntity.LedgerID
+	ClosingDay     int // Day of the month statements close on; the month's last day in shorter months
+	PaymentDueDays int // Days from the closing date to the payment due date
+	MinimumPayment MinimumPayment
+	CreditLimit    money.Money
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// CreditCardCycle is one statement period of a credit card and what is left to pay of its statement.
+// A card balance is negative while money is owed; cycle amounts are positive when owed.
+type CreditCardCycle struct {
+	AccountID        AccountID
+	StartDate        time.Time   // First day of the period
+	ClosingDate      time.Time   // Last day of the period, the statement date
+	DueDate          time.Time   // Last day to pay the statement
+	StatementBalance money.Money // Owed at closing, or so far while open; negative when the card is in credit
+	MinimumPayment   money.Money // Zero while open
+	Payments         []*Transaction
+	Paid             money.Money // Transfers into the card after closing, up to the next closing date
+	PaidByDueDate    money.Money
+	AmountDue        money.Money // Statement balance less payments, never negative
+	Status           CreditCardCycleStatus
+}
+
+// NewCreditCard sets up the billing terms of a credit card account
+func NewCreditCard(
+	account *Account,
+	closingDay, paymentDueDays int,
+	minimum MinimumPayment,
+	creditLimit money.Money,
+) (*CreditCard, error) {
+	if account.Type != AccountTypeCreditCard {
+		return nil, fmt.Errorf("%s account %s is not a credit card", account.Type, account.Name)
+	}
+
+	if err := validateCreditCardTerms(account.Currency, closingDay, paymentDueDays, minimum, creditLimit); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return &CreditCard{
+		AccountID:      account.ID,
+		LedgerID:       account.LedgerID,
+		ClosingDay:     closingDay,
+		PaymentDueDays: paymentDueDays,
+		MinimumPayment: minimum,
+		CreditLimit:    creditLimit,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// ReconstructCreditCard reconstructs a CreditCard from stored data
+func ReconstructCreditCard(
+	accountID AccountID,
+	ledgerID ledgerEntity.LedgerID,
+	closingDay, paymentDueDays int,
+	minimum MinimumPayment,
+	creditLimit money.Money,
+	createdAt, updatedAt time.Time,
+) *CreditCard {
+	return &CreditCard{
+		AccountID:      accountID,
+		LedgerID:       ledgerID,
+		ClosingDay:     closingDay,
+		PaymentDueDays: paymentDueDays,
+		MinimumPayment: minimum,
+		CreditLimit:    creditLimit,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	}
+}
+
+// UpdateTerms changes the card's billing terms; cycles are derived again with the new terms
+func (c *CreditCard) UpdateTerms(closingDay, paymentDueDays int, minimum MinimumPayment, creditLimit money.Money) error {
+	if err := validateCreditCardTerms(c.CreditLimit.Currency, closingDay, paymentDueDays, minimum, creditLimit); err != nil {
+		return err
+	}
+
+	c.ClosingDay = closingDay
+	c.PaymentDueDays = paymentDueDays
+	c.MinimumPayment = minimum
+	c.CreditLimit = creditLimit
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+func validateCreditCardTerms(
+	currency money.Currency,
+	closingDay, paymentDueDays int,
+	minimum MinimumPayment,
+	creditLimit money.Money,
+) error {
+	if closingDay < 1 || closingDay > 31 {
+		return fmt.Errorf("statement closing day must be between 1 and 31, got %d", closingDay)
+	}
+
+	if paymentDueDays < 1 || paymentDueDays > maxPaymentDueDays {
+		return fmt.Errorf("payment due days must be between 1 and %d, got %d", maxPaymentDueDays, paymentDueDays)
+	}
+
+	if err := minimum.Validate(currency); err != nil {
+		return err
+	}
+
+	if creditLimit.Currency != currency {
+		return fmt.Errorf("currency mismatch: card uses %s, credit limit uses %s", currency, creditLimit.Currency)
+	}
+
+	if creditLimit.IsNegative() {
+		return fmt.Errorf("credit limit cannot be negative")
+	}
+
+	if err := creditLimit.ValidatePrecision(); err != nil {
+		return fmt.Errorf("invalid credit limit: %w", err)
+	}
+	return nil
+}
+
+// AvailableCredit returns how much of the credit limit is left at the given card balance,
+// negative when the card is over its limit
+func (c *CreditCard) AvailableCredit(balance money.Money) (money.Money, error) {
+	return c.CreditLimit.Add(balance)
+}
+
+// CycleAt returns the first and last day of the statement period that includes the day of t
+func (c *CreditCard) CycleAt(t time.Time) (start, closing time.Time) {
+	year, month, day := t.Date()
+	closing = c.closingDate(year, month, t.Location())
+	if day > closing.Day() {
+		closing = c.closingDate(year, month+1, t.Location())
+	}
+
+	year, month, _ = closing.Date()
+	return c.closingDate(year, month-1, t.Location()).AddDate(0, 0, 1), closing
+}
+
+// DueDate returns the payment due date of the statement closing on the given day
+func (c *CreditCard) DueDate(closing time.Time) time.Time {
+	return closing.AddDate(0, 0, c.PaymentDueDays)
+}
+
+// closingDate returns the closing date in the month, normalising months outside 1 to 12
+func (c *CreditCard) closingDate(year int, month time.Month, loc *time.Location) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+	return time.Date(year, month, min(c.ClosingDay, lastDay), 0, 0, 0, 0, loc)
+}
+
+// Cycles derives the card's statement cycles from the one that includes from up to the open one that includes now.
+// opening is the card balance before that first cycle starts and transactions are the card's transactions
+// dated since then; only posted ones count. Transfers into the card between two closing dates are the
+// payments of the earlier statement.
+func (c *CreditCard) Cycles(
+	opening money.Money,
+	from time.Time,
+	transactions []*Transaction,
+	now time.Time,
+) ([]*CreditCardCycle, error) {
+	if opening.Currency != c.CreditLimit.Currency {
+		return nil, fmt.Errorf("currency mismatch: card uses %s, opening balance uses %s",
+			c.CreditLimit.Currency, opening.Currency)
+	}
+
+	if now.Before(from) {
+		return nil, fmt.Errorf("cycles cannot start after now")
+	}
+
+	posted := make([]*Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if !tx.AccountID.Equals(c.AccountID) {
+			return nil, fmt.Errorf("transaction %s belongs to another account", tx.ID)
+		}
+		if tx.PostingStatus.IsPosted() {
+			posted = append(posted, tx)
+		}
+	}
+	slices.SortStableFunc(posted, func(a, b *Transaction) int { return a.TransactionDate.Compare(b.TransactionDate) })
+
+	start, closing := c.CycleAt(from)
+	if len(posted) > 0 && posted[0].TransactionDate.Before(start) {
+		return nil, fmt.Errorf("transaction %s is dated before the first cycle", posted[0].ID)
+	}
+
+	var (
+		cycles   []*CreditCardCycle
+		previous *CreditCardCycle
+		balance  = opening
+	)
+	for {
+		cycle, err := c.newCycle(start, closing)
+		if err != nil {
+			return nil, err
+		}
+
+		cutoff := closing.AddDate(0, 0, 1)
+		for len(posted) > 0 && posted[0].TransactionDate.Before(cutoff) {
+			tx := posted[0]
+			posted = posted[1:]
+
+			if balance, err = balance.Add(tx.Amount); err != nil {
+				return nil, fmt.Errorf("failed to apply transaction %s: %w", tx.ID, err)
+			}
+
+			if previous != nil && tx.IsTransfer() && tx.IsCredit() {
+				if err := previous.addPayment(tx); err != nil {
+					return nil, err
+				}
+			}
+		}
+		cycle.StatementBalance = balance.Negate()
+		cycles = append(cycles, cycle)
+
+		if now.Before(cutoff) {
+			break
+		}
+
+		if cycle.MinimumPayment, err = c.MinimumPayment.Amount(cycle.StatementBalance); err != nil {
+			return nil, err
+		}
+		previous = cycle
+		start, closing = c.CycleAt(cutoff)
+	}
+
+	for _, cycle := range cycles[:len(cycles)-1] {
+		if err := cycle.settle(now); err != nil {
+			return nil, err
+		}
+	}
+	return cycles, nil
+}
+
+func (c *CreditCard) newCycle(start, closing time.Time) (*CreditCardCycle, error) {
+	zero, err := money.Zero(c.CreditLimit.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreditCardCycle{
+		AccountID:      c.AccountID,
+		StartDate:      start,
+		ClosingDate:    closing,
+		DueDate:        c.DueDate(closing),
+		MinimumPayment: zero,
+		Paid:           zero,
+		PaidByDueDate:  zero,
+		AmountDue:      zero,
+		Status:         CreditCardCycleStatusOpen,
+	}, nil
+}
+
+// addPayment matches a payment made after the cycle closed to it
+func (c *CreditCardCycle) addPayment(tx *Transaction) error {
+	var err error
+	if c.Paid, err = c.Paid.Add(tx.Amount); err != nil {
+		return fmt.Errorf("failed to apply payment %s: %w", tx.ID, err)
+	}
+
+	if tx.TransactionDate.Before(c.DueDate.AddDate(0, 0, 1)) {
+		if c.PaidByDueDate, err = c.PaidByDueDate.Add(tx.Amount); err != nil {
+			return fmt.Errorf("failed to apply payment %s: %w", tx.ID, err)
+		}
+	}
+
+	c.Payments = append(c.Payments, tx)
+	return nil
+}
+
+// settle works out what is left to pay of a closed cycle and its status at now
+func (c *CreditCardCycle) settle(now time.Time) error {
+	left, err := c.StatementBalance.Subtract(c.Paid)
+	if err != nil {
+		return err
+	}
+	if left.IsPositive() {
+		c.AmountDue = left
+	}
+
+	switch {
+	case c.AmountDue.IsZero():
+		c.Status = CreditCardCycleStatusPaid
+	case now.Before(c.DueDate.AddDate(0, 0, 1)):
+		c.Status = CreditCardCycleStatusDue
+	case !c.PaidByDueDate.Amount.LessThan(c.MinimumPayment.Amount):
+		c.Status = CreditCardCycleStatusMinimumPaid
+	default:
+		c.Status = CreditCardCycleStatusOverdue
+	}
+	return nil
+}
+
+// OverdueCycles returns the cycles, as derived by Cycles, that went overdue and still have part of their amount due
+// left after the payments made towards the statements that followed them
+func OverdueCycles(cycles []*CreditCardCycle) []*CreditCardCycle {
+	var (
+		overdue   []*CreditCardCycle
+		paidSince decimal.Decimal
+	)
+	for i := len(cycles) - 1; i >= 0; i-- {
+		cycle := cycles[i]
+		if cycle.Status.IsOverdue() && paidSince.LessThan(cycle.AmountDue.Amount) {
+			overdue = append(overdue, cycle)
+		}
+		paidSince = paidSince.Add(cycle.Paid.Amount)
+	}
+	slices.Reverse(overdue)
+	return overdue
+}
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// MinimumPayment is how a card issuer works out the minimum payment of a statement:
+// Percent of the statement balance rounded up to the currency's minor units, but at least Floor.
+// It never exceeds the statement balance.
+type MinimumPayment struct {
+	Percent money.Percent
+	Floor   money.Money
+}
+
+// Validate checks that the formula is usable for a card in the given currency
+func (m MinimumPayment) Validate(currency money.Currency) error {
+	if m.Percent.IsNegative() || m.Percent.Compare(money.NewPercentFromRatio(decimal.NewFromInt(1))) > 0 {
+		return fmt.Errorf("minimum payment percent must be between 0%% and 100%%, got %s", m.Percent)
+	}
+
+	if m.Floor.Currency != currency {
+		return fmt.Errorf("currency mismatch: card uses %s, minimum payment floor uses %s", currency, m.Floor.Currency)
+	}
+
+	if m.Floor.IsNegative() {
+		return fmt.Errorf("minimum payment floor cannot be negative")
+	}
+
+	if err := m.Floor.ValidatePrecision(); err != nil {
+		return fmt.Errorf("invalid minimum payment floor: %w", err)
+	}
+
+	if m.Percent.IsZero() && m.Floor.IsZero() {
+		return fmt.Errorf("minimum payment needs a percent or a floor")
+	}
+	return nil
+}
+
+// Amount returns the minimum payment of a statement balance owed (positive); nothing is due when nothing is owed
+func (m MinimumPayment) Amount(owed money.Money) (money.Money, error) {
+	if owed.Currency != m.Floor.Currency {
+		return money.Money{}, fmt.Errorf("currency mismatch: minimum payment uses %s, statement uses %s",
+			m.Floor.Currency, owed.Currency)
+	}
+
+	if !owed.IsPositive() {
+		return money.Zero(owed.Currency)
+	}
+
+	minimum := owed.MultiplyAndRound(m.Percent.Ratio(), money.RoundCeiling)
+	if minimum.Amount.LessThan(m.Floor.Amount) {
+		minimum = m.Floor
+	}
+	if minimum.Amount.GreaterThan(owed.Amount) {
+		minimum = owed
+	}
+	return minimum, nil
+}
+
+// CreditCardCycleStatus represents where a statement cycle is in its billing lifecycle
+type CreditCardCycleStatus string
+
+// Credit card cycle status constants
+const (
+	CreditCardCycleStatusOpen        CreditCardCycleStatus = "OPEN"         // Statement not closed yet
+	CreditCardCycleStatusDue         CreditCardCycleStatus = "DUE"          // Closed with an amount due, due date not passed
+	CreditCardCycleStatusPaid        CreditCardCycleStatus = "PAID"         // Closed and nothing left to pay
+	CreditCardCycleStatusMinimumPaid CreditCardCycleStatus = "MINIMUM_PAID" // Due date passed with at least the minimum paid; the rest carries over
+	CreditCardCycleStatusOverdue     CreditCardCycleStatus = "OVERDUE"      // Due date passed without the minimum paid
+)
+
+// String returns the string representation of CreditCardCycleStatus
+func (s CreditCardCycleStatus) String() string {
+	return string(s)
+}
+
+// IsOpen checks if the cycle's statement has not closed yet
+func (s CreditCardCycleStatus) IsOpen() bool {
+	return s == CreditCardCycleStatusOpen
+}
+
+// IsOverdue checks if the cycle passed its due date without the minimum payment
+func (s CreditCardCycleStatus) IsOverdue() bool {
+	return s == CreditCardCycleStatusOverdue
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestMinimumPayment_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		percent     string
+		floor       string
+		currency    string
+		errContains string
+	}{
+		{name: "percent and floor", percent: "2", floor: "25.00", currency: "USD"},
+		{name: "percent only", percent: "100", floor: "0.00", currency: "USD"},
+		{name: "floor only", percent: "0", floor: "25.00", currency: "USD"},
+		{name: "negative percent", percent: "-1", floor: "25.00", currency: "USD", errContains: "between 0% and 100%"},
+		{name: "percent above 100", percent: "100.5", floor: "25.00", currency: "USD", errContains: "between 0% and 100%"},
+		{name: "currency mismatch", percent: "2", floor: "25.00", currency: "EUR", errContains: "currency mismatch"},
+		{name: "negative floor", percent: "2", floor: "-1.00", currency: "USD", errContains: "floor cannot be negative"},
+		{name: "neither", percent: "0", floor: "0.00", currency: "USD", errContains: "needs a percent or a floor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, err := money.NewPercent(tt.percent)
+			require.NoError(t, err)
+
+			minimum := MinimumPayment{Percent: percent, Floor: mustMoney(t, tt.floor, tt.currency)}
+			err = minimum.Validate(money.CurrencyUSD)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMinimumPayment_Amount(t *testing.T) {
+	tests := []struct {
+		name    string
+		percent string
+		floor   string
+		owed    string
+		want    string
+	}{
+		{name: "percent", percent: "2", floor: "25.00", owed: "2000.00", want: "40.00 USD"},
+		{name: "floor", percent: "2", floor: "25.00", owed: "1234.56", want: "25.00 USD"},
+		{name: "capped at balance", percent: "2", floor: "25.00", owed: "10.00", want: "10.00 USD"},
+		{name: "rounded up", percent: "3", floor: "0.00", owed: "100.01", want: "3.01 USD"},
+		{name: "nothing owed", percent: "2", floor: "25.00", owed: "0.00", want: "0.00 USD"},
+		{name: "in credit", percent: "2", floor: "25.00", owed: "-5.00", want: "0.00 USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, err := money.NewPercent(tt.percent)
+			require.NoError(t, err)
+
+			minimum := MinimumPayment{Percent: percent, Floor: mustMoney(t, tt.floor, "USD")}
+			amount, err := minimum.Amount(mustMoney(t, tt.owed, "USD"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, amount.String())
+		})
+	}
+
+	minimum := MinimumPayment{Floor: mustMoney(t, "25.00", "USD")}
+	_, err := minimum.Amount(mustMoney(t, "100.00", "EUR"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "currency mismatch")
+}
+
+func TestCreditCardCycleStatus(t *testing.T) {
+	assert.True(t, CreditCardCycleStatusOpen.IsOpen())
+	assert.False(t, CreditCardCycleStatusDue.IsOpen())
+	assert.True(t, CreditCardCycleStatusOverdue.IsOverdue())
+	assert.False(t, CreditCardCycleStatusMinimumPaid.IsOverdue())
+	assert.Equal(t, "MINIMUM_PAID", CreditCardCycleStatusMinimumPaid.String())
+}
@@ -0,0 +1,222 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestNewCreditCard(t *testing.T) {
+	card, account := createTestCreditCard(t, 15, 25)
+	assert.True(t, card.AccountID.Equals(account.ID))
+	assert.True(t, card.LedgerID.Equals(account.LedgerID))
+	assert.Equal(t, "5000.00 USD", card.CreditLimit.String())
+
+	tests := []struct {
+		name           string
+		closingDay     int
+		paymentDueDays int
+		creditLimit    money.Money
+		errContains    string
+	}{
+		{name: "closing day too low", closingDay: 0, paymentDueDays: 25, creditLimit: mustMoney(t, "100.00", "USD"), errContains: "closing day must be between 1 and 31"},
+		{name: "closing day too high", closingDay: 32, paymentDueDays: 25, creditLimit: mustMoney(t, "100.00", "USD"), errContains: "closing day must be between 1 and 31"},
+		{name: "due after next closing", closingDay: 1, paymentDueDays: 29, creditLimit: mustMoney(t, "100.00", "USD"), errContains: "payment due days must be between 1 and 28"},
+		{name: "limit currency", closingDay: 1, paymentDueDays: 25, creditLimit: mustMoney(t, "100.00", "EUR"), errContains: "currency mismatch"},
+		{name: "negative limit", closingDay: 1, paymentDueDays: 25, creditLimit: mustMoney(t, "-100.00", "USD"), errContains: "credit limit cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCreditCard(account, tt.closingDay, tt.paymentDueDays, card.MinimumPayment, tt.creditLimit)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+
+			err = card.UpdateTerms(tt.closingDay, tt.paymentDueDays, card.MinimumPayment, tt.creditLimit)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+
+	_, err := NewCreditCard(createTestAccount(t), 15, 25, card.MinimumPayment, card.CreditLimit)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a credit card")
+
+	require.NoError(t, card.UpdateTerms(28, 21, card.MinimumPayment, mustMoney(t, "8000.00", "USD")))
+	assert.Equal(t, 28, card.ClosingDay)
+	assert.Equal(t, 21, card.PaymentDueDays)
+	assert.Equal(t, "8000.00 USD", card.CreditLimit.String())
+}
+
+func TestCreditCard_AvailableCredit(t *testing.T) {
+	card, _ := createTestCreditCard(t, 15, 25)
+
+	available, err := card.AvailableCredit(mustMoney(t, "-1200.00", "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, "3800.00 USD", available.String())
+
+	available, err = card.AvailableCredit(mustMoney(t, "-5100.00", "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, "-100.00 USD", available.String(), "over the limit")
+}
+
+func TestCreditCard_CycleAt(t *testing.T) {
+	tests := []struct {
+		name        string
+		closingDay  int
+		day         time.Time
+		wantStart   string
+		wantClosing string
+	}{
+		{name: "before closing", closingDay: 15, day: date(2024, 1, 10), wantStart: "2023-12-16", wantClosing: "2024-01-15"},
+		{name: "on closing", closingDay: 15, day: date(2024, 1, 15).Add(23 * time.Hour), wantStart: "2023-12-16", wantClosing: "2024-01-15"},
+		{name: "after closing", closingDay: 15, day: date(2024, 1, 16), wantStart: "2024-01-16", wantClosing: "2024-02-15"},
+		{name: "end of year", closingDay: 15, day: date(2024, 12, 20), wantStart: "2024-12-16", wantClosing: "2025-01-15"},
+		{name: "short month", closingDay: 31, day: date(2024, 2, 10), wantStart: "2024-02-01", wantClosing: "2024-02-29"},
+		{name: "after short month", closingDay: 31, day: date(2024, 3, 1), wantStart: "2024-03-01", wantClosing: "2024-03-31"},
+		{name: "after short month with later closing", closingDay: 30, day: date(2023, 3, 1), wantStart: "2023-03-01", wantClosing: "2023-03-30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card, _ := createTestCreditCard(t, tt.closingDay, 25)
+			start, closing := card.CycleAt(tt.day)
+			assert.Equal(t, tt.wantStart, start.Format(time.DateOnly))
+			assert.Equal(t, tt.wantClosing, closing.Format(time.DateOnly))
+		})
+	}
+}
+
+func TestCreditCard_Cycles(t *testing.T) {
+	card, account := createTestCreditCard(t, 15, 25)
+	transferID, err := NewTransferID()
+	require.NoError(t, err)
+
+	newTransaction := func(t *testing.T, amount string, day time.Time, transfer bool) *Transaction {
+		tx, err := NewTransaction(account.LedgerID, account.ID, mustItemID(t), mustMoney(t, amount, "USD"), "Test", day)
+		require.NoError(t, err)
+		if transfer {
+			tx.TransferID = optional.Some(transferID)
+		}
+		return tx
+	}
+
+	hold := newTransaction(t, "-300.00", date(2024, 3, 30), false)
+	require.NoError(t, hold.MarkPending())
+
+	transactions := []*Transaction{
+		newTransaction(t, "-1000.00", date(2024, 1, 20), false),
+		newTransaction(t, "-100.00", date(2024, 1, 10), false),
+		newTransaction(t, "100.00", date(2024, 2, 1), true),  // Pays the January statement
+		newTransaction(t, "30.00", date(2024, 2, 5), false),  // A refund is not a payment
+		newTransaction(t, "500.00", date(2024, 3, 20), true), // Pays the March statement; February's went unpaid
+		hold,
+	}
+
+	opening := mustMoney(t, "0.00", "USD")
+	cycles, err := card.Cycles(opening, date(2024, 1, 10), transactions, date(2024, 4, 1))
+	require.NoError(t, err)
+	require.Len(t, cycles, 4)
+
+	want := []struct {
+		closing, due, statement, minimum, paid, amountDue string
+		status                                            CreditCardCycleStatus
+	}{
+		{closing: "2024-01-15", due: "2024-02-09", statement: "100.00 USD", minimum: "25.00 USD", paid: "100.00 USD", amountDue: "0.00 USD", status: CreditCardCycleStatusPaid},
+		{closing: "2024-02-15", due: "2024-03-11", statement: "970.00 USD", minimum: "25.00 USD", paid: "0.00 USD", amountDue: "970.00 USD", status: CreditCardCycleStatusOverdue},
+		{closing: "2024-03-15", due: "2024-04-09", statement: "970.00 USD", minimum: "25.00 USD", paid: "500.00 USD", amountDue: "470.00 USD", status: CreditCardCycleStatusDue},
+		{closing: "2024-04-15", due: "2024-05-10", statement: "470.00 USD", minimum: "0.00 USD", paid: "0.00 USD", amountDue: "0.00 USD", status: CreditCardCycleStatusOpen},
+	}
+	for i, cycle := range cycles {
+		assert.Equal(t, want[i].closing, cycle.ClosingDate.Format(time.DateOnly))
+		assert.Equal(t, want[i].due, cycle.DueDate.Format(time.DateOnly))
+		assert.Equal(t, want[i].statement, cycle.StatementBalance.String(), "cycle %d", i)
+		assert.Equal(t, want[i].minimum, cycle.MinimumPayment.String(), "cycle %d", i)
+		assert.Equal(t, want[i].paid, cycle.Paid.String(), "cycle %d", i)
+		assert.Equal(t, want[i].amountDue, cycle.AmountDue.String(), "cycle %d", i)
+		assert.Equal(t, want[i].status, cycle.Status, "cycle %d", i)
+	}
+	assert.Equal(t, "2023-12-16", cycles[0].StartDate.Format(time.DateOnly))
+	require.Len(t, cycles[0].Payments, 1)
+	assert.Same(t, transactions[2], cycles[0].Payments[0])
+
+	// Once the March due date passes, the payment made before it covers the minimum
+	cycles, err = card.Cycles(opening, date(2024, 1, 10), transactions, date(2024, 4, 20))
+	require.NoError(t, err)
+	require.Len(t, cycles, 5)
+	assert.Equal(t, CreditCardCycleStatusMinimumPaid, cycles[2].Status)
+	assert.Equal(t, "500.00 USD", cycles[2].PaidByDueDate.String())
+
+	_, err = card.Cycles(opening, date(2024, 2, 1), transactions, date(2024, 4, 1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dated before the first cycle")
+
+	_, err = card.Cycles(mustMoney(t, "0.00", "EUR"), date(2024, 1, 10), transactions, date(2024, 4, 1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "currency mismatch")
+
+	other := createTestTransaction(t)
+	_, err = card.Cycles(opening, date(2024, 1, 10), []*Transaction{other}, date(2024, 4, 1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "belongs to another account")
+}
+
+func TestOverdueCycles(t *testing.T) {
+	card, account := createTestCreditCard(t, 15, 25)
+	transferID, err := NewTransferID()
+	require.NoError(t, err)
+
+	newTransaction := func(t *testing.T, amount string, day time.Time, transfer bool) *Transaction {
+		tx, err := NewTransaction(account.LedgerID, account.ID, mustItemID(t), mustMoney(t, amount, "USD"), "Test", day)
+		require.NoError(t, err)
+		if transfer {
+			tx.TransferID = optional.Some(transferID)
+		}
+		return tx
+	}
+
+	transactions := []*Transaction{
+		newTransaction(t, "-1000.00", date(2024, 1, 20), false), // February's statement goes unpaid
+		newTransaction(t, "-200.00", date(2024, 2, 20), false),  // March's statement goes unpaid too
+		newTransaction(t, "300.00", date(2024, 4, 12), true),    // Pays part of the March statement late
+	}
+	opening := mustMoney(t, "0.00", "USD")
+
+	cycles, err := card.Cycles(opening, date(2024, 1, 10), transactions, date(2024, 5, 1))
+	require.NoError(t, err)
+	overdue := OverdueCycles(cycles)
+	require.Len(t, overdue, 2)
+	assert.Equal(t, "2024-02-15", overdue[0].ClosingDate.Format(time.DateOnly))
+	assert.Equal(t, "2024-03-15", overdue[1].ClosingDate.Format(time.DateOnly))
+
+	transactions = append(transactions, newTransaction(t, "700.00", date(2024, 4, 20), true))
+	cycles, err = card.Cycles(opening, date(2024, 1, 10), transactions, date(2024, 5, 1))
+	require.NoError(t, err)
+	overdue = OverdueCycles(cycles)
+	require.Len(t, overdue, 1, "later payments cover February's 1000.00 but not the 900.00 left of March's")
+	assert.Equal(t, "2024-03-15", overdue[0].ClosingDate.Format(time.DateOnly))
+}
+
+func createTestCreditCard(t *testing.T, closingDay, paymentDueDays int) (*CreditCard, *Account) {
+	t.Helper()
+
+	account, err := NewAccount(createTestAccount(t).LedgerID, "Visa", "", AccountTypeCreditCard, "USD")
+	require.NoError(t, err)
+
+	percent, err := money.NewPercent("2")
+	require.NoError(t, err)
+
+	minimum := MinimumPayment{Percent: percent, Floor: mustMoney(t, "25.00", "USD")}
+	card, err := NewCreditCard(account, closingDay, paymentDueDays, minimum, mustMoney(t, "5000.00", "USD"))
+	require.NoError(t, err)
+	return card, account
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
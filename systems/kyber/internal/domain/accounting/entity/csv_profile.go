@@ -0,0 +1,405 @@
+package entity
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// CSVProfile is a saved layout of an account's CSV statement export, reused for every import from that bank
+type CSVProfile struct {
+	ID        CSVProfileID
+	LedgerID  ledgerEntity.LedgerID
+	AccountID AccountID
+	Name      string
+	Format    CSVFormat
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CSVFormat describes how a bank lays out its CSV export
+type CSVFormat struct {
+	Delimiter          rune // Field separator, e.g. ',' or ';'
+	SkipRows           int  // Rows before the first transaction, e.g. headers and account details
+	DateFormat         string
+	DecimalSeparator   rune // '.' or ','
+	ThousandsSeparator rune // 0 when amounts have none
+	SignConvention     CSVSignConvention
+	Columns            CSVColumns
+}
+
+// dateTokens maps DateFormat tokens (e.g. "DD/MM/YYYY") to Go layout elements, longest first
+var dateTokens = []struct{ token, layout string }{
+	{token: "YYYY", layout: "2006"},
+	{token: "YY", layout: "06"},
+	{token: "MMM", layout: "Jan"},
+	{token: "MM", layout: "01"},
+	{token: "M", layout: "1"},
+	{token: "DD", layout: "02"},
+	{token: "D", layout: "2"},
+}
+
+// NewCSVProfile creates a new CSVProfile
+func NewCSVProfile(ledgerID ledgerEntity.LedgerID, accountID AccountID, name string, format CSVFormat) (*CSVProfile, error) {
+	if !ledgerID.IsValid() {
+		return nil, fmt.Errorf("ledger ID is invalid")
+	}
+
+	if !accountID.IsValid() {
+		return nil, fmt.Errorf("account ID is invalid")
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("CSV profile name cannot be empty")
+	}
+
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	id, err := NewCSVProfileID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CSV profile ID: %w", err)
+	}
+
+	now := time.Now()
+
+	return &CSVProfile{
+		ID:        id,
+		LedgerID:  ledgerID,
+		AccountID: accountID,
+		Name:      name,
+		Format:    format,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// ReconstructCSVProfile reconstructs a CSVProfile from stored data
+func ReconstructCSVProfile(
+	id CSVProfileID,
+	ledgerID ledgerEntity.LedgerID,
+	accountID AccountID,
+	name string,
+	format CSVFormat,
+	createdAt, updatedAt time.Time,
+) *CSVProfile {
+	return &CSVProfile{
+		ID:        id,
+		LedgerID:  ledgerID,
+		AccountID: accountID,
+		Name:      name,
+		Format:    format,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}
+}
+
+// Update replaces the profile's name and format
+func (p *CSVProfile) Update(name string, format CSVFormat) error {
+	if name == "" {
+		return fmt.Errorf("CSV profile name cannot be empty")
+	}
+
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	p.Name = name
+	p.Format = format
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// Validate checks the format can be used to read a file
+func (f CSVFormat) Validate() error {
+	if f.Delimiter == 0 || f.Delimiter == '"' || f.Delimiter == '\r' || f.Delimiter == '\n' {
+		return fmt.Errorf("invalid CSV delimiter %q", f.Delimiter)
+	}
+
+	if f.SkipRows < 0 {
+		return fmt.Errorf("rows to skip cannot be negative")
+	}
+
+	if _, err := dateLayout(f.DateFormat); err != nil {
+		return err
+	}
+
+	if f.DecimalSeparator != '.' && f.DecimalSeparator != ',' {
+		return fmt.Errorf("decimal separator must be '.' or ','")
+	}
+
+	switch f.ThousandsSeparator {
+	case 0, ',', '.', ' ', '\'', ' ':
+	default:
+		return fmt.Errorf("invalid thousands separator %q", f.ThousandsSeparator)
+	}
+	if f.ThousandsSeparator == f.DecimalSeparator {
+		return fmt.Errorf("thousands and decimal separators must differ")
+	}
+
+	if _, err := NewCSVSignConvention(f.SignConvention.String()); err != nil {
+		return err
+	}
+
+	c := f.Columns
+	if c.Amount.IsSome() == (c.Debit.IsSome() || c.Credit.IsSome()) {
+		return fmt.Errorf("CSV profile needs either an amount column or debit/credit columns")
+	}
+
+	for _, col := range []optional.Option[int]{
+		optional.Some(c.Date), c.Amount, c.Debit, c.Credit, c.Payee, c.Memo, c.Reference,
+	} {
+		if col.IsSome() && col.Unwrap() < 0 {
+			return fmt.Errorf("column numbers cannot be negative")
+		}
+	}
+
+	return nil
+}
+
+// ParseStatement reads a CSV export laid out as the profile describes. Rows that cannot be
+// read are returned in Statement.Rejected with their line number instead of failing the file.
+func (p *CSVProfile) ParseStatement(r io.Reader, currency money.Currency) (Statement, error) {
+	layout, err := dateLayout(p.Format.DateFormat)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = p.Format.Delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	statement := Statement{Balance: optional.None[money.Money](), OpeningBalance: optional.None[money.Money]()}
+
+	for row := 0; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			statement.Rejected = append(statement.Rejected, RejectedLine{Line: parseErr.Line, Reason: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return Statement{}, fmt.Errorf("failed to read CSV file: %w", err)
+		}
+
+		if row < p.Format.SkipRows || isBlankRecord(record) {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		parsed, err := p.parseRow(record, layout, currency)
+		if err != nil {
+			statement.Rejected = append(statement.Rejected, RejectedLine{Line: line, Reason: err.Error()})
+			continue
+		}
+
+		statement.Lines = append(statement.Lines, parsed)
+	}
+
+	statement.AssignMissingImportIDs("csv:")
+	return statement, nil
+}
+
+func (p *CSVProfile) parseRow(record []string, layout string, currency money.Currency) (StatementLine, error) {
+	cols := p.Format.Columns
+
+	field := func(col int) (string, error) {
+		if col >= len(record) {
+			return "", fmt.Errorf("row has %d columns, expected at least %d", len(record), col+1)
+		}
+		return strings.TrimSpace(record[col]), nil
+	}
+
+	optionalField := func(col optional.Option[int]) (string, error) {
+		if col.IsNone() {
+			return "", nil
+		}
+		return field(col.Unwrap())
+	}
+
+	dateStr, err := field(cols.Date)
+	if err != nil {
+		return StatementLine{}, err
+	}
+	date, err := time.Parse(layout, dateStr)
+	if err != nil {
+		return StatementLine{}, fmt.Errorf("invalid date %q: expected %s", dateStr, p.Format.DateFormat)
+	}
+
+	amount, err := p.rowAmount(optionalField, currency)
+	if err != nil {
+		return StatementLine{}, err
+	}
+
+	line := StatementLine{Date: date, Amount: amount}
+	if line.Payee, err = optionalField(cols.Payee); err != nil {
+		return StatementLine{}, err
+	}
+	if line.Memo, err = optionalField(cols.Memo); err != nil {
+		return StatementLine{}, err
+	}
+	if line.ImportID, err = optionalField(cols.Reference); err != nil {
+		return StatementLine{}, err
+	}
+
+	return line, nil
+}
+
+// rowAmount returns the signed amount of a row from its amount or debit/credit columns
+func (p *CSVProfile) rowAmount(
+	field func(col optional.Option[int]) (string, error),
+	currency money.Currency,
+) (money.Money, error) {
+	cols := p.Format.Columns
+
+	if cols.Amount.IsSome() {
+		value, err := field(cols.Amount)
+		if err != nil {
+			return money.Money{}, err
+		}
+		amount, err := p.Format.parseAmount(value, currency)
+		if err != nil {
+			return money.Money{}, err
+		}
+		if p.Format.SignConvention.IsOutflowPositive() {
+			amount = amount.Negate()
+		}
+		return amount, nil
+	}
+
+	debitStr, err := field(cols.Debit)
+	if err != nil {
+		return money.Money{}, err
+	}
+	creditStr, err := field(cols.Credit)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	switch {
+	case debitStr != "" && creditStr != "":
+		return money.Money{}, fmt.Errorf("row has both a debit (%s) and a credit (%s)", debitStr, creditStr)
+	case debitStr != "":
+		debit, err := p.Format.parseAmount(debitStr, currency)
+		if err != nil {
+			return money.Money{}, err
+		}
+		return debit.Abs().Negate(), nil
+	case creditStr != "":
+		credit, err := p.Format.parseAmount(creditStr, currency)
+		if err != nil {
+			return money.Money{}, err
+		}
+		return credit.Abs(), nil
+	default:
+		return money.Money{}, fmt.Errorf("row has no amount")
+	}
+}
+
+// parseAmount reads an amount such as "1.234,56", "(12.50)", "12.50-" or "$-1,000.00"
+func (f CSVFormat) parseAmount(value string, currency money.Currency) (money.Money, error) {
+	s := strings.TrimSpace(value)
+	if s == "" {
+		return money.Money{}, fmt.Errorf("amount is empty")
+	}
+
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+
+	// Drop currency symbols or codes around the number
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '-' && r != '+' && r != f.DecimalSeparator
+	})
+	if strings.HasSuffix(s, "-") {
+		negative = true
+		s = strings.TrimSuffix(s, "-")
+	}
+	if strings.HasPrefix(s, "-") {
+		negative = true
+		s = strings.TrimPrefix(s, "-")
+	}
+	s = strings.TrimPrefix(s, "+")
+	s = strings.TrimLeftFunc(s, func(r rune) bool { return !unicode.IsDigit(r) && r != f.DecimalSeparator })
+
+	if f.ThousandsSeparator != 0 {
+		s = strings.ReplaceAll(s, string(f.ThousandsSeparator), "")
+	}
+	if f.DecimalSeparator != '.' {
+		if strings.Contains(s, ".") {
+			return money.Money{}, fmt.Errorf("invalid amount %q", value)
+		}
+		s = strings.Replace(s, string(f.DecimalSeparator), ".", 1)
+	}
+	if negative {
+		s = "-" + s
+	}
+
+	amount, err := money.NewMoney(s, currency)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+// dateLayout converts a date format such as "DD/MM/YYYY" or "D MMM YY" into a Go time layout
+func dateLayout(format string) (string, error) {
+	var (
+		layout strings.Builder
+		has    = make(map[byte]bool)
+	)
+
+	for i := 0; i < len(format); {
+		matched := false
+		for _, t := range dateTokens {
+			if strings.HasPrefix(format[i:], t.token) {
+				layout.WriteString(t.layout)
+				has[t.token[0]] = true
+				i += len(t.token)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		c := format[i]
+		if unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) {
+			return "", fmt.Errorf("invalid date format %q: unsupported %q", format, c)
+		}
+		layout.WriteByte(c)
+		i++
+	}
+
+	if !has['Y'] || !has['M'] || !has['D'] {
+		return "", fmt.Errorf("invalid date format %q: needs year, month and day, e.g. DD/MM/YYYY", format)
+	}
+	return layout.String(), nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
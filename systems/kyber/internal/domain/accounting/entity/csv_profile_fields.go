@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/kneadCODE/coruscant/shared/golib/id"
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+)
+
+// CSVProfileID represents a unique identifier for a CSV import profile using UUIDv7
+type CSVProfileID struct {
+	id.EntityID
+}
+
+// NewCSVProfileID creates a new CSVProfileID using UUIDv7
+func NewCSVProfileID() (CSVProfileID, error) {
+	base, err := id.NewEntityID()
+	if err != nil {
+		return CSVProfileID{}, fmt.Errorf("failed to create CSV profile ID: %w", err)
+	}
+	return CSVProfileID{EntityID: base}, nil
+}
+
+// NewCSVProfileIDFromString creates a CSVProfileID from an existing string
+func NewCSVProfileIDFromString(idStr string) (CSVProfileID, error) {
+	base, err := id.NewEntityIDFromString(idStr)
+	if err != nil {
+		return CSVProfileID{}, fmt.Errorf("failed to create CSV profile ID: %w", err)
+	}
+	return CSVProfileID{EntityID: base}, nil
+}
+
+// Equals checks if two CSVProfileIDs are equal
+func (t CSVProfileID) Equals(other CSVProfileID) bool {
+	return t.EntityID.Equals(other.EntityID)
+}
+
+// CSVSignConvention is how a single amount column signs money leaving the account
+type CSVSignConvention string
+
+// CSV sign convention constants
+const (
+	CSVSignInflowPositive  CSVSignConvention = "INFLOW_POSITIVE"  // Deposits positive, payments negative (most bank accounts)
+	CSVSignOutflowPositive CSVSignConvention = "OUTFLOW_POSITIVE" // Payments positive, refunds negative (many card exports)
+)
+
+// NewCSVSignConvention creates a new CSVSignConvention from string
+func NewCSVSignConvention(convention string) (CSVSignConvention, error) {
+	switch CSVSignConvention(convention) {
+	case CSVSignInflowPositive, CSVSignOutflowPositive:
+		return CSVSignConvention(convention), nil
+	default:
+		return "", fmt.Errorf("invalid CSV sign convention: %s", convention)
+	}
+}
+
+// String returns the string representation of CSVSignConvention
+func (c CSVSignConvention) String() string {
+	return string(c)
+}
+
+// IsOutflowPositive checks if payments are positive and must be negated
+func (c CSVSignConvention) IsOutflowPositive() bool {
+	return c == CSVSignOutflowPositive
+}
+
+// CSVColumns maps columns of a CSV file (0-based) to what they hold. Either Amount, or
+// Debit and/or Credit must be set.
+type CSVColumns struct {
+	Date      int
+	Amount    optional.Option[int] // Signed amount, interpreted with the profile's sign convention
+	Debit     optional.Option[int] // Money leaving the account, as a positive number
+	Credit    optional.Option[int] // Money entering the account, as a positive number
+	Payee     optional.Option[int]
+	Memo      optional.Option[int]
+	Reference optional.Option[int] // Bank reference unique per account; used to detect duplicates when present
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVProfileID_NewCSVProfileID(t *testing.T) {
+	id, err := NewCSVProfileID()
+
+	require.NoError(t, err)
+	assert.True(t, id.IsValid())
+	assert.NotEmpty(t, id.String())
+}
+
+func TestCSVProfileID_NewCSVProfileIDFromString(t *testing.T) {
+	validID, err := NewCSVProfileID()
+	require.NoError(t, err)
+
+	parsed, err := NewCSVProfileIDFromString(validID.String())
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(validID))
+
+	_, err = NewCSVProfileIDFromString("invalid-uuid")
+	assert.Error(t, err)
+}
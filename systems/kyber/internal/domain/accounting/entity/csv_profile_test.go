@@ -0,0 +1,268 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestNewCSVProfile(t *testing.T) {
+	account := createTestAccount(t)
+
+	tests := []struct {
+		name        string
+		profileName string
+		format      func(f *CSVFormat)
+		errContains string
+	}{
+		{
+			name:        "valid",
+			profileName: "Bank export",
+			format:      func(*CSVFormat) {},
+		},
+		{
+			name:        "empty name",
+			format:      func(*CSVFormat) {},
+			errContains: "name cannot be empty",
+		},
+		{
+			name:        "no amount columns",
+			profileName: "Bank export",
+			format:      func(f *CSVFormat) { f.Columns.Amount = optional.None[int]() },
+			errContains: "needs either an amount column or debit/credit columns",
+		},
+		{
+			name:        "amount and debit columns",
+			profileName: "Bank export",
+			format:      func(f *CSVFormat) { f.Columns.Debit = optional.Some(4) },
+			errContains: "needs either an amount column or debit/credit columns",
+		},
+		{
+			name:        "same separators",
+			profileName: "Bank export",
+			format:      func(f *CSVFormat) { f.ThousandsSeparator = '.' },
+			errContains: "must differ",
+		},
+		{
+			name:        "date format without year",
+			profileName: "Bank export",
+			format:      func(f *CSVFormat) { f.DateFormat = "DD/MM" },
+			errContains: "needs year, month and day",
+		},
+		{
+			name:        "unsupported date token",
+			profileName: "Bank export",
+			format:      func(f *CSVFormat) { f.DateFormat = "YYYY-MM-DDTHH" },
+			errContains: "unsupported",
+		},
+		{
+			name:        "invalid sign convention",
+			profileName: "Bank export",
+			format:      func(f *CSVFormat) { f.SignConvention = "UPSIDE_DOWN" },
+			errContains: "invalid CSV sign convention",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format := testCSVFormat()
+			tt.format(&format)
+
+			profile, err := NewCSVProfile(account.LedgerID, account.ID, tt.profileName, format)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, profile.ID.IsValid())
+			assert.True(t, profile.AccountID.Equals(account.ID))
+			assert.Equal(t, format, profile.Format)
+		})
+	}
+}
+
+func TestCSVProfile_ParseStatement(t *testing.T) {
+	account := createTestAccount(t)
+
+	tests := []struct {
+		name     string
+		format   func(f *CSVFormat)
+		file     string
+		lines    []StatementLine
+		rejected []RejectedLine
+	}{
+		{
+			name:   "signed amount with header",
+			format: func(*CSVFormat) {},
+			file: "Date,Payee,Memo,Amount,Reference\n" +
+				"05/01/2024,Whole Foods,Groceries,-42.50,R1\n" +
+				"15/01/2024,ACME Payroll,,\"2,500.00\",R2\n",
+			lines: []StatementLine{
+				{ImportID: "R1", Date: utcDate(2024, 1, 5), Amount: mustMoney(t, "-42.50", "USD"), Payee: "Whole Foods", Memo: "Groceries"},
+				{ImportID: "R2", Date: utcDate(2024, 1, 15), Amount: mustMoney(t, "2500.00", "USD"), Payee: "ACME Payroll"},
+			},
+		},
+		{
+			name: "european layout with debit and credit columns",
+			format: func(f *CSVFormat) {
+				f.Delimiter = ';'
+				f.SkipRows = 2
+				f.DateFormat = "D.M.YY"
+				f.DecimalSeparator = ','
+				f.ThousandsSeparator = '.'
+				f.Columns.Amount = optional.None[int]()
+				f.Columns.Debit = optional.Some(3)
+				f.Columns.Credit = optional.Some(4)
+				f.Columns.Reference = optional.None[int]()
+			},
+			file: "Konto;DE0012345\n" +
+				"Datum;Empfänger;Zweck;Soll;Haben\n" +
+				"5.1.24;Rewe;Einkauf;42,50;\n" +
+				"15.1.24;ACME;Gehalt;;2.500,00\n",
+			lines: []StatementLine{
+				{Date: utcDate(2024, 1, 5), Amount: mustMoney(t, "-42.50", "USD"), Payee: "Rewe", Memo: "Einkauf"},
+				{Date: utcDate(2024, 1, 15), Amount: mustMoney(t, "2500.00", "USD"), Payee: "ACME", Memo: "Gehalt"},
+			},
+		},
+		{
+			name: "outflow positive with symbols, parentheses and trailing minus",
+			format: func(f *CSVFormat) {
+				f.SkipRows = 0
+				f.DateFormat = "MMM D, YYYY"
+				f.SignConvention = CSVSignOutflowPositive
+			},
+			file: "\"Jan 5, 2024\",Coffee,,$4.50,A\n" +
+				"\"Jan 6, 2024\",Refund,,($10.00),B\n" +
+				"\"Jan 7, 2024\",Reversal,,3.00-,C\n",
+			lines: []StatementLine{
+				{ImportID: "A", Date: utcDate(2024, 1, 5), Amount: mustMoney(t, "-4.50", "USD"), Payee: "Coffee"},
+				{ImportID: "B", Date: utcDate(2024, 1, 6), Amount: mustMoney(t, "10.00", "USD"), Payee: "Refund"},
+				{ImportID: "C", Date: utcDate(2024, 1, 7), Amount: mustMoney(t, "3.00", "USD"), Payee: "Reversal"},
+			},
+		},
+		{
+			name:   "rejected rows keep their line numbers",
+			format: func(*CSVFormat) {},
+			file: "Date,Payee,Memo,Amount,Reference\n" +
+				"2024-01-05,Shop,,-1.00,R1\n" +
+				"\n" +
+				"05/01/2024,Shop,,abc,R2\n" +
+				"05/01/2024,Short\n" +
+				"06/01/2024,Shop,,-2.00,R4\n",
+			lines: []StatementLine{
+				{ImportID: "R4", Date: utcDate(2024, 1, 6), Amount: mustMoney(t, "-2.00", "USD"), Payee: "Shop"},
+			},
+			rejected: []RejectedLine{
+				{Line: 2, Reason: `invalid date "2024-01-05": expected DD/MM/YYYY`},
+				{Line: 4, Reason: `invalid amount "abc"`},
+				{Line: 5, Reason: "row has 2 columns, expected at least 4"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format := testCSVFormat()
+			tt.format(&format)
+
+			profile, err := NewCSVProfile(account.LedgerID, account.ID, "Bank export", format)
+			require.NoError(t, err)
+
+			statement, err := profile.ParseStatement(strings.NewReader(tt.file), money.CurrencyUSD)
+			require.NoError(t, err)
+			assert.Equal(t, tt.rejected, statement.Rejected)
+			require.Len(t, statement.Lines, len(tt.lines))
+
+			for i, want := range tt.lines {
+				got := statement.Lines[i]
+				if want.ImportID == "" {
+					assert.True(t, strings.HasPrefix(got.ImportID, "csv:"), got.ImportID)
+				} else {
+					assert.Equal(t, want.ImportID, got.ImportID)
+				}
+				assert.Equal(t, want.Date, got.Date)
+				assert.Equal(t, want.Amount.String(), got.Amount.String())
+				assert.Equal(t, want.Payee, got.Payee)
+				assert.Equal(t, want.Memo, got.Memo)
+			}
+		})
+	}
+}
+
+func TestCSVProfile_ParseStatement_SyntheticImportIDs(t *testing.T) {
+	account := createTestAccount(t)
+	format := testCSVFormat()
+	format.Columns.Reference = optional.None[int]()
+
+	profile, err := NewCSVProfile(account.LedgerID, account.ID, "Bank export", format)
+	require.NoError(t, err)
+
+	file := "Date,Payee,Memo,Amount\n" +
+		"05/01/2024,Coffee,,-4.50\n" +
+		"05/01/2024,Coffee,,-4.50\n" +
+		"06/01/2024,Coffee,,-4.50\n"
+
+	first, err := profile.ParseStatement(strings.NewReader(file), money.CurrencyUSD)
+	require.NoError(t, err)
+	require.Len(t, first.Lines, 3)
+
+	// Identical rows on the same day are distinct transactions
+	assert.NotEqual(t, first.Lines[0].ImportID, first.Lines[1].ImportID)
+	assert.NotEqual(t, first.Lines[0].ImportID, first.Lines[2].ImportID)
+
+	// Re-reading the file gives the same IDs, so it is recognised as already imported
+	second, err := profile.ParseStatement(strings.NewReader(file), money.CurrencyUSD)
+	require.NoError(t, err)
+	for i := range first.Lines {
+		assert.Equal(t, first.Lines[i].ImportID, second.Lines[i].ImportID)
+	}
+}
+
+func TestCSVProfile_Update(t *testing.T) {
+	account := createTestAccount(t)
+	profile, err := NewCSVProfile(account.LedgerID, account.ID, "Bank export", testCSVFormat())
+	require.NoError(t, err)
+
+	format := testCSVFormat()
+	format.Delimiter = ';'
+	require.NoError(t, profile.Update("Renamed", format))
+	assert.Equal(t, "Renamed", profile.Name)
+	assert.Equal(t, ';', profile.Format.Delimiter)
+
+	format.DecimalSeparator = ' '
+	require.Error(t, profile.Update("Renamed", format))
+	assert.Equal(t, '.', profile.Format.DecimalSeparator)
+}
+
+// Helper functions
+
+// testCSVFormat lays out "Date,Payee,Memo,Amount,Reference" with a header row
+func testCSVFormat() CSVFormat {
+	return CSVFormat{
+		Delimiter:          ',',
+		SkipRows:           1,
+		DateFormat:         "DD/MM/YYYY",
+		DecimalSeparator:   '.',
+		ThousandsSeparator: ',',
+		SignConvention:     CSVSignInflowPositive,
+		Columns: CSVColumns{
+			Date:      0,
+			Payee:     optional.Some(1),
+			Memo:      optional.Some(2),
+			Amount:    optional.Some(3),
+			Reference: optional.Some(4),
+		},
+	}
+}
+
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
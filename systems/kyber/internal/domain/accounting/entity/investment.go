@@ -0,0 +1,274 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// InvestmentActivity is a buy, sell, dividend, split or fee of an investment account. Securities are
+// commodities registered with the money package, so units are held as Money in the security's code
+// while cash amounts are in the account's currency. Holdings are derived from the activities, see BuildPortfolio.
+type InvestmentActivity struct {
+	ID            InvestmentActivityID
+	LedgerID      ledgerEntity.LedgerID
+	AccountID     AccountID
+	Type          InvestmentActivityType
+	Security      money.Currency                 // Empty for account fees
+	Quantity      money.Money                    // Units bought or sold; zero in the security for dividends and splits
+	Amount        money.Money                    // Cash cost or proceeds of a trade before its fee, or the dividend or fee
+	Fee           money.Money                    // Brokerage paid on a trade
+	SplitRatio    decimal.Decimal                // Units held after a split per unit held before it; zero for other activities
+	TransactionID optional.Option[TransactionID] // Cash side of the activity on the account; none for splits
+	Date          time.Time
+	CreatedAt     time.Time
+}
+
+// NewInvestmentBuy records units of a security bought for cost plus a fee
+func NewInvestmentBuy(account *Account, quantity, cost, fee money.Money, date time.Time) (*InvestmentActivity, error) {
+	return newInvestmentTrade(account, InvestmentActivityTypeBuy, quantity, cost, fee, date)
+}
+
+// NewInvestmentSell records units of a security sold for proceeds less a fee
+func NewInvestmentSell(account *Account, quantity, proceeds, fee money.Money, date time.Time) (*InvestmentActivity, error) {
+	return newInvestmentTrade(account, InvestmentActivityTypeSell, quantity, proceeds, fee, date)
+}
+
+// NewInvestmentDividend records a cash dividend paid by a security
+func NewInvestmentDividend(
+	account *Account,
+	security money.Currency,
+	amount money.Money,
+	date time.Time,
+) (*InvestmentActivity, error) {
+	a, err := newInvestmentActivity(account, InvestmentActivityTypeDividend, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.setSecurity(account, security); err != nil {
+		return nil, err
+	}
+
+	if err := a.setCash(account, amount, "dividend"); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// NewInvestmentSplit records a split of a security's units by ratio, e.g. 2 for a 2-for-1 split or
+// 0.1 for a 1-for-10 reverse split
+func NewInvestmentSplit(
+	account *Account,
+	security money.Currency,
+	ratio decimal.Decimal,
+	date time.Time,
+) (*InvestmentActivity, error) {
+	a, err := newInvestmentActivity(account, InvestmentActivityTypeSplit, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.setSecurity(account, security); err != nil {
+		return nil, err
+	}
+
+	if !ratio.IsPositive() || ratio.Equal(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("split ratio must be positive and not 1, got %s", ratio)
+	}
+	a.SplitRatio = ratio
+	return a, nil
+}
+
+// NewInvestmentFee records a fee charged to the account rather than to a trade
+func NewInvestmentFee(account *Account, amount money.Money, date time.Time) (*InvestmentActivity, error) {
+	a, err := newInvestmentActivity(account, InvestmentActivityTypeFee, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.setCash(account, amount, "fee"); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ReconstructInvestmentActivity reconstructs an InvestmentActivity from stored data
+func ReconstructInvestmentActivity(
+	activityID InvestmentActivityID,
+	ledgerID ledgerEntity.LedgerID,
+	accountID AccountID,
+	activityType InvestmentActivityType,
+	security money.Currency,
+	quantity, amount, fee money.Money,
+	splitRatio decimal.Decimal,
+	transactionID optional.Option[TransactionID],
+	date, createdAt time.Time,
+) *InvestmentActivity {
+	return &InvestmentActivity{
+		ID:            activityID,
+		LedgerID:      ledgerID,
+		AccountID:     accountID,
+		Type:          activityType,
+		Security:      security,
+		Quantity:      quantity,
+		Amount:        amount,
+		Fee:           fee,
+		SplitRatio:    splitRatio,
+		TransactionID: transactionID,
+		Date:          date,
+		CreatedAt:     createdAt,
+	}
+}
+
+func newInvestmentTrade(
+	account *Account,
+	activityType InvestmentActivityType,
+	quantity, amount, fee money.Money,
+	date time.Time,
+) (*InvestmentActivity, error) {
+	a, err := newInvestmentActivity(account, activityType, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.setSecurity(account, quantity.Currency); err != nil {
+		return nil, err
+	}
+
+	if !quantity.IsPositive() {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+
+	if err := quantity.ValidatePrecision(); err != nil {
+		return nil, fmt.Errorf("invalid quantity: %w", err)
+	}
+	a.Quantity = quantity
+
+	if err := a.setCash(account, amount, "trade"); err != nil {
+		return nil, err
+	}
+
+	if fee.Currency != account.Currency {
+		return nil, fmt.Errorf("currency mismatch: account uses %s, fee uses %s", account.Currency, fee.Currency)
+	}
+
+	if fee.IsNegative() {
+		return nil, fmt.Errorf("fee cannot be negative")
+	}
+
+	if err := fee.ValidatePrecision(); err != nil {
+		return nil, fmt.Errorf("invalid fee: %w", err)
+	}
+	a.Fee = fee
+	return a, nil
+}
+
+func newInvestmentActivity(account *Account, activityType InvestmentActivityType, date time.Time) (*InvestmentActivity, error) {
+	if account.Type != AccountTypeInvestment {
+		return nil, fmt.Errorf("%s account %s is not an investment account", account.Type, account.Name)
+	}
+
+	if !account.Currency.Kind().IsFiat() {
+		return nil, fmt.Errorf("investment account %s must hold cash in a fiat currency, not %s", account.Name, account.Currency)
+	}
+
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	activityID, err := NewInvestmentActivityID()
+	if err != nil {
+		return nil, err
+	}
+
+	zero, err := money.Zero(account.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InvestmentActivity{
+		ID:            activityID,
+		LedgerID:      account.LedgerID,
+		AccountID:     account.ID,
+		Type:          activityType,
+		Amount:        zero,
+		Fee:           zero,
+		SplitRatio:    decimal.Zero,
+		TransactionID: optional.None[TransactionID](),
+		Date:          date,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// setSecurity checks that the account can hold the security and sets it with a zero quantity
+func (a *InvestmentActivity) setSecurity(account *Account, security money.Currency) error {
+	if err := security.Validate(); err != nil {
+		return fmt.Errorf("invalid security: %w", err)
+	}
+
+	if kind := security.Kind(); kind.IsFiat() || !account.Type.HoldsCommodityKind(kind) {
+		return fmt.Errorf("%s is not a security an investment account can hold", security)
+	}
+
+	zero, err := money.Zero(security)
+	if err != nil {
+		return err
+	}
+
+	a.Security = security
+	a.Quantity = zero
+	return nil
+}
+
+func (a *InvestmentActivity) setCash(account *Account, amount money.Money, what string) error {
+	if amount.Currency != account.Currency {
+		return fmt.Errorf("currency mismatch: account uses %s, %s amount uses %s", account.Currency, what, amount.Currency)
+	}
+
+	if !amount.IsPositive() {
+		return fmt.Errorf("%s amount must be positive", what)
+	}
+
+	if err := amount.ValidatePrecision(); err != nil {
+		return fmt.Errorf("invalid %s amount: %w", what, err)
+	}
+
+	a.Amount = amount
+	return nil
+}
+
+// CashAmount returns how the activity changes the account's cash balance: negative for buys and fees,
+// positive for sales and dividends, zero for splits
+func (a *InvestmentActivity) CashAmount() (money.Money, error) {
+	switch a.Type {
+	case InvestmentActivityTypeBuy:
+		total, err := a.Amount.Add(a.Fee)
+		return total.Negate(), err
+	case InvestmentActivityTypeSell:
+		return a.Amount.Subtract(a.Fee)
+	case InvestmentActivityTypeDividend:
+		return a.Amount, nil
+	case InvestmentActivityTypeFee:
+		return a.Amount.Negate(), nil
+	default:
+		return money.Zero(a.Amount.Currency)
+	}
+}
+
+// Description returns a transaction description for the cash side of the activity, e.g. "BUY 10 VWRA"
+func (a *InvestmentActivity) Description() string {
+	switch a.Type {
+	case InvestmentActivityTypeBuy, InvestmentActivityTypeSell:
+		return fmt.Sprintf("%s %s %s", a.Type, a.Quantity.Amount, a.Security)
+	case InvestmentActivityTypeFee:
+		return a.Type.String()
+	default:
+		return fmt.Sprintf("%s %s", a.Type, a.Security)
+	}
+}
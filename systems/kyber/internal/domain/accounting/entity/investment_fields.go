@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/kneadCODE/coruscant/shared/golib/id"
+)
+
+// InvestmentActivityID represents a unique identifier for an investment activity using UUIDv7
+type InvestmentActivityID struct {
+	id.EntityID
+}
+
+// NewInvestmentActivityID creates a new InvestmentActivityID using UUIDv7
+func NewInvestmentActivityID() (InvestmentActivityID, error) {
+	base, err := id.NewEntityID()
+	if err != nil {
+		return InvestmentActivityID{}, fmt.Errorf("failed to create investment activity ID: %w", err)
+	}
+	return InvestmentActivityID{EntityID: base}, nil
+}
+
+// NewInvestmentActivityIDFromString creates an InvestmentActivityID from an existing string
+func NewInvestmentActivityIDFromString(idStr string) (InvestmentActivityID, error) {
+	base, err := id.NewEntityIDFromString(idStr)
+	if err != nil {
+		return InvestmentActivityID{}, fmt.Errorf("failed to create investment activity ID: %w", err)
+	}
+	return InvestmentActivityID{EntityID: base}, nil
+}
+
+// Equals checks if two InvestmentActivityIDs are equal
+func (i InvestmentActivityID) Equals(other InvestmentActivityID) bool {
+	return i.EntityID.Equals(other.EntityID)
+}
+
+// InvestmentActivityType represents what happened to a holding of an investment account
+type InvestmentActivityType string
+
+// Investment activity type constants
+const (
+	InvestmentActivityTypeBuy      InvestmentActivityType = "BUY"      // Units bought for cash, opening a lot
+	InvestmentActivityTypeSell     InvestmentActivityType = "SELL"     // Units sold for cash, realising a gain or loss
+	InvestmentActivityTypeDividend InvestmentActivityType = "DIVIDEND" // Cash paid out by a security
+	InvestmentActivityTypeSplit    InvestmentActivityType = "SPLIT"    // Units multiplied by a ratio at no cost
+	InvestmentActivityTypeFee      InvestmentActivityType = "FEE"      // Cash charged to the account, e.g. a platform fee
+)
+
+// NewInvestmentActivityType creates a new InvestmentActivityType from string
+func NewInvestmentActivityType(activityType string) (InvestmentActivityType, error) {
+	switch InvestmentActivityType(activityType) {
+	case InvestmentActivityTypeBuy,
+		InvestmentActivityTypeSell,
+		InvestmentActivityTypeDividend,
+		InvestmentActivityTypeSplit,
+		InvestmentActivityTypeFee:
+		return InvestmentActivityType(activityType), nil
+	default:
+		return "", fmt.Errorf("invalid investment activity type: %s", activityType)
+	}
+}
+
+// String returns the string representation of InvestmentActivityType
+func (t InvestmentActivityType) String() string {
+	return string(t)
+}
+
+// IsTrade checks if the activity buys or sells units
+func (t InvestmentActivityType) IsTrade() bool {
+	return t == InvestmentActivityTypeBuy || t == InvestmentActivityTypeSell
+}
+
+// CostBasisMethod represents how the cost of units sold is worked out
+type CostBasisMethod string
+
+// Cost basis method constants
+const (
+	CostBasisMethodFIFO        CostBasisMethod = "FIFO"         // Units sold come from the oldest lots, at their cost
+	CostBasisMethodAverageCost CostBasisMethod = "AVERAGE_COST" // Units sold cost the average of all units held
+)
+
+// NewCostBasisMethod creates a new CostBasisMethod from string
+func NewCostBasisMethod(method string) (CostBasisMethod, error) {
+	switch CostBasisMethod(method) {
+	case CostBasisMethodFIFO, CostBasisMethodAverageCost:
+		return CostBasisMethod(method), nil
+	default:
+		return "", fmt.Errorf("invalid cost basis method: %s", method)
+	}
+}
+
+// String returns the string representation of CostBasisMethod
+func (m CostBasisMethod) String() string {
+	return string(m)
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInvestmentActivityType(t *testing.T) {
+	activityType, err := NewInvestmentActivityType("SELL")
+	require.NoError(t, err)
+	assert.Equal(t, InvestmentActivityTypeSell, activityType)
+	assert.True(t, activityType.IsTrade())
+	assert.False(t, InvestmentActivityTypeDividend.IsTrade())
+
+	_, err = NewInvestmentActivityType("SHORT")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid investment activity type")
+}
+
+func TestNewCostBasisMethod(t *testing.T) {
+	method, err := NewCostBasisMethod("AVERAGE_COST")
+	require.NoError(t, err)
+	assert.Equal(t, CostBasisMethodAverageCost, method)
+
+	_, err = NewCostBasisMethod("LIFO")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid cost basis method")
+}
@@ -0,0 +1,354 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// Lot is the units of a security left from one buy and what they cost
+type Lot struct {
+	ActivityID   InvestmentActivityID // Buy that opened the lot
+	AcquiredDate time.Time
+	Quantity     money.Money
+	Cost         money.Money // Fees included; under average cost, the lot's share of the holding's cost
+}
+
+// Holding is what an investment account holds of one security
+type Holding struct {
+	Security  money.Currency
+	Quantity  money.Money
+	Cost      money.Money // Cost of the units held, fees included
+	Dividends money.Money // Dividends received over time
+	Lots      []Lot       // Oldest first
+}
+
+// RealisedGain is the gain or loss of one sale
+type RealisedGain struct {
+	ActivityID InvestmentActivityID
+	Security   money.Currency
+	Date       time.Time
+	Quantity   money.Money
+	Proceeds   money.Money // Net of the fee
+	CostBasis  money.Money
+	Gain       money.Money // Negative for a loss
+}
+
+// Portfolio is the holdings of an investment account and the gains realised by its sales,
+// derived from its activities with a cost basis method
+type Portfolio struct {
+	AccountID     AccountID
+	Method        CostBasisMethod
+	Holdings      []*Holding // By security, including ones sold out that paid dividends
+	RealisedGains []RealisedGain
+}
+
+// HoldingValuation is a holding at its market value
+type HoldingValuation struct {
+	Security       money.Currency
+	Quantity       money.Money
+	Cost           money.Money
+	MarketValue    money.Money
+	UnrealisedGain money.Money // Negative for a loss
+}
+
+// PortfolioValuation is an investment account at market value: its cash plus its holdings at their prices
+type PortfolioValuation struct {
+	AccountID      AccountID
+	AsOf           time.Time
+	Cash           money.Money // Account balance
+	Holdings       []HoldingValuation
+	MarketValue    money.Money // Cash plus the market value of the holdings
+	UnrealisedGain money.Money
+}
+
+// BuildPortfolio derives the holdings of the investment account from its activities in date order.
+// Sales take units from the oldest lots; their cost is the cost of those units under FIFO, or the
+// average cost of all units held under average cost. Selling more units than held fails.
+func BuildPortfolio(account *Account, method CostBasisMethod, activities []*InvestmentActivity) (*Portfolio, error) {
+	if _, err := NewCostBasisMethod(method.String()); err != nil {
+		return nil, err
+	}
+
+	sorted := slices.Clone(activities)
+	slices.SortStableFunc(sorted, func(a, b *InvestmentActivity) int {
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	p := &Portfolio{AccountID: account.ID, Method: method}
+	for _, a := range sorted {
+		if !a.AccountID.Equals(account.ID) {
+			return nil, fmt.Errorf("investment activity %s belongs to another account", a.ID)
+		}
+
+		if err := p.apply(account.Currency, a); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(p.Holdings, func(a, b *Holding) int {
+		switch {
+		case a.Security < b.Security:
+			return -1
+		case a.Security > b.Security:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return p, nil
+}
+
+func (p *Portfolio) apply(currency money.Currency, a *InvestmentActivity) error {
+	if a.Type == InvestmentActivityTypeFee {
+		return nil
+	}
+
+	h, err := p.holding(currency, a.Security)
+	if err != nil {
+		return err
+	}
+
+	switch a.Type {
+	case InvestmentActivityTypeBuy:
+		return h.buy(a)
+	case InvestmentActivityTypeSell:
+		gain, err := h.sell(a, p.Method)
+		if err != nil {
+			return err
+		}
+		p.RealisedGains = append(p.RealisedGains, gain)
+		return nil
+	case InvestmentActivityTypeDividend:
+		h.Dividends, err = h.Dividends.Add(a.Amount)
+		return err
+	case InvestmentActivityTypeSplit:
+		return h.split(a)
+	default:
+		return fmt.Errorf("invalid investment activity type: %s", a.Type)
+	}
+}
+
+// holding returns the holding of the security, adding an empty one if there is none yet
+func (p *Portfolio) holding(currency, security money.Currency) (*Holding, error) {
+	for _, h := range p.Holdings {
+		if h.Security == security {
+			return h, nil
+		}
+	}
+
+	quantity, err := money.Zero(security)
+	if err != nil {
+		return nil, err
+	}
+
+	zero, err := money.Zero(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Holding{Security: security, Quantity: quantity, Cost: zero, Dividends: zero}
+	p.Holdings = append(p.Holdings, h)
+	return h, nil
+}
+
+func (h *Holding) buy(a *InvestmentActivity) error {
+	cost, err := a.Amount.Add(a.Fee)
+	if err != nil {
+		return err
+	}
+
+	if h.Quantity, err = h.Quantity.Add(a.Quantity); err != nil {
+		return err
+	}
+
+	if h.Cost, err = h.Cost.Add(cost); err != nil {
+		return err
+	}
+
+	h.Lots = append(h.Lots, Lot{ActivityID: a.ID, AcquiredDate: a.Date, Quantity: a.Quantity, Cost: cost})
+	return nil
+}
+
+func (h *Holding) sell(a *InvestmentActivity, method CostBasisMethod) (RealisedGain, error) {
+	if a.Quantity.Amount.GreaterThan(h.Quantity.Amount) {
+		return RealisedGain{}, fmt.Errorf("cannot sell %s on %s: only %s held",
+			a.Quantity, a.Date.Format(time.DateOnly), h.Quantity)
+	}
+
+	remaining := a.Quantity.Amount
+	lotsCost, err := money.Zero(h.Cost.Currency)
+	if err != nil {
+		return RealisedGain{}, err
+	}
+
+	for remaining.IsPositive() {
+		lot := &h.Lots[0]
+		taken := lot.Cost
+		if remaining.LessThan(lot.Quantity.Amount) {
+			taken = lot.Cost.MultiplyAndRound(remaining.Div(lot.Quantity.Amount), money.RoundHalfEven)
+			lot.Quantity.Amount = lot.Quantity.Amount.Sub(remaining)
+			if lot.Cost, err = lot.Cost.Subtract(taken); err != nil {
+				return RealisedGain{}, err
+			}
+			remaining = decimal.Zero
+		} else {
+			remaining = remaining.Sub(lot.Quantity.Amount)
+			h.Lots = h.Lots[1:]
+		}
+
+		if lotsCost, err = lotsCost.Add(taken); err != nil {
+			return RealisedGain{}, err
+		}
+	}
+
+	costBasis := lotsCost
+	if method == CostBasisMethodAverageCost {
+		if costBasis, err = h.averageCost(a.Quantity); err != nil {
+			return RealisedGain{}, err
+		}
+	}
+
+	if h.Quantity, err = h.Quantity.Subtract(a.Quantity); err != nil {
+		return RealisedGain{}, err
+	}
+
+	if h.Cost, err = h.Cost.Subtract(costBasis); err != nil {
+		return RealisedGain{}, err
+	}
+
+	if method == CostBasisMethodAverageCost {
+		if err := h.spreadCost(); err != nil {
+			return RealisedGain{}, err
+		}
+	}
+
+	proceeds, err := a.Amount.Subtract(a.Fee)
+	if err != nil {
+		return RealisedGain{}, err
+	}
+
+	gain, err := proceeds.Subtract(costBasis)
+	if err != nil {
+		return RealisedGain{}, err
+	}
+
+	return RealisedGain{
+		ActivityID: a.ID,
+		Security:   a.Security,
+		Date:       a.Date,
+		Quantity:   a.Quantity,
+		Proceeds:   proceeds,
+		CostBasis:  costBasis,
+		Gain:       gain,
+	}, nil
+}
+
+// averageCost returns the cost of quantity units at the average cost of the units held
+func (h *Holding) averageCost(quantity money.Money) (money.Money, error) {
+	if quantity.Equals(h.Quantity) {
+		return h.Cost, nil
+	}
+	return h.Cost.MultiplyAndRound(quantity.Amount.Div(h.Quantity.Amount), money.RoundHalfEven), nil
+}
+
+// spreadCost shares the holding's cost across its lots by quantity, so every unit costs the average
+func (h *Holding) spreadCost() error {
+	if len(h.Lots) == 0 {
+		return nil
+	}
+
+	ratios := make([]decimal.Decimal, len(h.Lots))
+	for i, lot := range h.Lots {
+		ratios[i] = lot.Quantity.Amount
+	}
+
+	costs, err := h.Cost.Allocate(ratios...)
+	if err != nil {
+		return fmt.Errorf("failed to spread the cost of %s: %w", h.Security, err)
+	}
+
+	for i := range h.Lots {
+		h.Lots[i].Cost = costs[i]
+	}
+	return nil
+}
+
+// split multiplies the units of every lot by the ratio, dropping fractions the security cannot hold
+func (h *Holding) split(a *InvestmentActivity) error {
+	quantity, err := money.Zero(h.Security)
+	if err != nil {
+		return err
+	}
+
+	for i := range h.Lots {
+		h.Lots[i].Quantity = h.Lots[i].Quantity.MultiplyAndRound(a.SplitRatio, money.RoundFloor)
+		if quantity, err = quantity.Add(h.Lots[i].Quantity); err != nil {
+			return err
+		}
+	}
+
+	h.Quantity = quantity
+	return nil
+}
+
+// Value values the portfolio with the market value of each security held in the account's currency
+// and the account's cash balance
+func (p *Portfolio) Value(
+	cash money.Money,
+	asOf time.Time,
+	marketValues map[money.Currency]money.Money,
+) (*PortfolioValuation, error) {
+	zero, err := money.Zero(cash.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &PortfolioValuation{
+		AccountID:      p.AccountID,
+		AsOf:           asOf,
+		Cash:           cash,
+		MarketValue:    cash,
+		UnrealisedGain: zero,
+	}
+
+	for _, h := range p.Holdings {
+		if h.Quantity.IsZero() {
+			continue
+		}
+
+		marketValue, ok := marketValues[h.Security]
+		if !ok {
+			return nil, fmt.Errorf("no market value for %s", h.Security)
+		}
+
+		gain, err := marketValue.Subtract(h.Cost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to value %s: %w", h.Security, err)
+		}
+
+		v.Holdings = append(v.Holdings, HoldingValuation{
+			Security:       h.Security,
+			Quantity:       h.Quantity,
+			Cost:           h.Cost,
+			MarketValue:    marketValue,
+			UnrealisedGain: gain,
+		})
+
+		if v.MarketValue, err = v.MarketValue.Add(marketValue); err != nil {
+			return nil, err
+		}
+
+		if v.UnrealisedGain, err = v.UnrealisedGain.Add(gain); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,153 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestBuildPortfolio(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        CostBasisMethod
+		wantCostBasis string
+		wantGain      string
+		wantCost      string
+		wantValueGain string
+	}{
+		{
+			name:          "FIFO sells the oldest lot first",
+			method:        CostBasisMethodFIFO,
+			wantCostBasis: "1615.00 USD", // 1010.00 for the first lot and half of the second's 1210.00
+			wantGain:      "325.00 USD",
+			wantCost:      "605.00 USD",
+			wantValueGain: "95.00 USD",
+		},
+		{
+			name:          "average cost",
+			method:        CostBasisMethodAverageCost,
+			wantCostBasis: "1665.00 USD", // 15 of 20 units costing 2220.00
+			wantGain:      "275.00 USD",
+			wantCost:      "555.00 USD",
+			wantValueGain: "145.00 USD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, activities := createTestActivities(t)
+
+			portfolio, err := BuildPortfolio(account, tt.method, activities)
+			require.NoError(t, err)
+
+			require.Len(t, portfolio.RealisedGains, 1)
+			gain := portfolio.RealisedGains[0]
+			assert.Equal(t, "15.0000 VWRA", gain.Quantity.String())
+			assert.Equal(t, "1940.00 USD", gain.Proceeds.String())
+			assert.Equal(t, tt.wantCostBasis, gain.CostBasis.String())
+			assert.Equal(t, tt.wantGain, gain.Gain.String())
+
+			require.Len(t, portfolio.Holdings, 1)
+			holding := portfolio.Holdings[0]
+			assert.Equal(t, "10.0000 VWRA", holding.Quantity.String(), "5 units left, doubled by the split")
+			assert.Equal(t, tt.wantCost, holding.Cost.String())
+			assert.Equal(t, "12.34 USD", holding.Dividends.String())
+			require.Len(t, holding.Lots, 1)
+			assert.Equal(t, date(2026, time.February, 10), holding.Lots[0].AcquiredDate)
+			assert.Equal(t, "10.0000 VWRA", holding.Lots[0].Quantity.String())
+			assert.Equal(t, tt.wantCost, holding.Lots[0].Cost.String())
+
+			valuation, err := portfolio.Value(mustMoney(t, "1000.00", "USD"), date(2026, time.June, 1),
+				map[money.Currency]money.Money{"VWRA": mustMoney(t, "700.00", "USD")})
+			require.NoError(t, err)
+			assert.Equal(t, "1700.00 USD", valuation.MarketValue.String())
+			assert.Equal(t, tt.wantValueGain, valuation.UnrealisedGain.String())
+			require.Len(t, valuation.Holdings, 1)
+			assert.Equal(t, tt.wantValueGain, valuation.Holdings[0].UnrealisedGain.String())
+		})
+	}
+}
+
+func TestBuildPortfolio_AverageCostSpreadsLots(t *testing.T) {
+	account := createTestInvestmentAccount(t)
+	activities := []*InvestmentActivity{
+		mustBuy(t, account, "10", "1000.00", "0", date(2026, time.January, 10)),
+		mustBuy(t, account, "10", "2000.00", "0", date(2026, time.February, 10)),
+		mustBuy(t, account, "10", "3000.00", "0", date(2026, time.March, 10)),
+	}
+
+	sell, err := NewInvestmentSell(account, mustUnits(t, "15", "VWRA"), mustMoney(t, "3000.00", "USD"), mustMoney(t, "0", "USD"), date(2026, time.April, 1))
+	require.NoError(t, err)
+	activities = append(activities, sell)
+
+	portfolio, err := BuildPortfolio(account, CostBasisMethodAverageCost, activities)
+	require.NoError(t, err)
+
+	holding := portfolio.Holdings[0]
+	assert.Equal(t, "3000.00 USD", holding.Cost.String())
+	require.Len(t, holding.Lots, 2)
+	assert.Equal(t, "5.0000 VWRA", holding.Lots[0].Quantity.String())
+	assert.Equal(t, "1000.00 USD", holding.Lots[0].Cost.String())
+	assert.Equal(t, "2000.00 USD", holding.Lots[1].Cost.String())
+}
+
+func TestBuildPortfolio_Errors(t *testing.T) {
+	account, activities := createTestActivities(t)
+
+	_, err := BuildPortfolio(account, "LIFO", activities)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid cost basis method")
+
+	oversell, err := NewInvestmentSell(account, mustUnits(t, "10.5", "VWRA"), mustMoney(t, "100.00", "USD"), mustMoney(t, "0", "USD"), date(2026, time.June, 1))
+	require.NoError(t, err)
+
+	_, err = BuildPortfolio(account, CostBasisMethodFIFO, append(activities, oversell))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot sell 10.5000 VWRA on 2026-06-01: only 10.0000 VWRA held")
+
+	portfolio, err := BuildPortfolio(account, CostBasisMethodFIFO, activities)
+	require.NoError(t, err)
+
+	_, err = portfolio.Value(mustMoney(t, "0", "USD"), date(2026, time.June, 1), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no market value for VWRA")
+}
+
+// createTestActivities buys 10 VWRA for 1010.00 and 10 for 1210.00 (fees included), sells 15 for 1940.00
+// net of fees, splits 2-for-1, then records a dividend and an account fee, all given out of order
+func createTestActivities(t *testing.T) (*Account, []*InvestmentActivity) {
+	t.Helper()
+
+	account := createTestInvestmentAccount(t)
+
+	sell, err := NewInvestmentSell(account, mustUnits(t, "15", "VWRA"), mustMoney(t, "1950.00", "USD"), mustMoney(t, "10.00", "USD"), date(2026, time.March, 10))
+	require.NoError(t, err)
+	split, err := NewInvestmentSplit(account, "VWRA", decimal.NewFromInt(2), date(2026, time.April, 1))
+	require.NoError(t, err)
+	dividend, err := NewInvestmentDividend(account, "VWRA", mustMoney(t, "12.34", "USD"), date(2026, time.May, 1))
+	require.NoError(t, err)
+	fee, err := NewInvestmentFee(account, mustMoney(t, "5.00", "USD"), date(2026, time.May, 1))
+	require.NoError(t, err)
+
+	return account, []*InvestmentActivity{
+		dividend,
+		mustBuy(t, account, "10", "1200.00", "10.00", date(2026, time.February, 10)),
+		sell,
+		fee,
+		mustBuy(t, account, "10", "1000.00", "10.00", date(2026, time.January, 10)),
+		split,
+	}
+}
+
+func mustBuy(t *testing.T, account *Account, quantity, cost, fee string, on time.Time) *InvestmentActivity {
+	t.Helper()
+
+	buy, err := NewInvestmentBuy(account, mustUnits(t, quantity, "VWRA"), mustMoney(t, cost, "USD"), mustMoney(t, fee, "USD"), on)
+	require.NoError(t, err)
+	return buy
+}
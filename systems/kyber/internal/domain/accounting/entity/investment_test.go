@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestNewInvestmentActivity(t *testing.T) {
+	account := createTestInvestmentAccount(t)
+
+	buy, err := NewInvestmentBuy(account, mustUnits(t, "10", "VWRA"), mustMoney(t, "1000.00", "USD"), mustMoney(t, "9.99", "USD"), date(2026, time.January, 10))
+	require.NoError(t, err)
+	assert.Equal(t, InvestmentActivityTypeBuy, buy.Type)
+	assert.Equal(t, money.Currency("VWRA"), buy.Security)
+	assert.True(t, buy.TransactionID.IsNone())
+	assert.Equal(t, "BUY 10 VWRA", buy.Description())
+
+	tests := []struct {
+		name        string
+		create      func() (*InvestmentActivity, error)
+		errContains string
+	}{
+		{
+			name: "not an investment account",
+			create: func() (*InvestmentActivity, error) {
+				return NewInvestmentFee(createTestAccount(t), mustMoney(t, "1.00", "USD"), time.Time{})
+			},
+			errContains: "is not an investment account",
+		},
+		{
+			name: "fiat security",
+			create: func() (*InvestmentActivity, error) {
+				return NewInvestmentDividend(account, "EUR", mustMoney(t, "1.00", "USD"), time.Time{})
+			},
+			errContains: "EUR is not a security",
+		},
+		{
+			name: "unregistered security",
+			create: func() (*InvestmentActivity, error) {
+				return NewInvestmentSplit(account, "NOPE", decimal.NewFromInt(2), time.Time{})
+			},
+			errContains: "invalid security",
+		},
+		{
+			name: "cash currency",
+			create: func() (*InvestmentActivity, error) {
+				return NewInvestmentSell(account, mustUnits(t, "1", "VWRA"), mustMoney(t, "100.00", "EUR"), mustMoney(t, "0", "USD"), time.Time{})
+			},
+			errContains: "currency mismatch",
+		},
+		{
+			name: "zero quantity",
+			create: func() (*InvestmentActivity, error) {
+				return NewInvestmentBuy(account, mustUnits(t, "0", "VWRA"), mustMoney(t, "100.00", "USD"), mustMoney(t, "0", "USD"), time.Time{})
+			},
+			errContains: "quantity must be positive",
+		},
+		{
+			name: "quantity precision",
+			create: func() (*InvestmentActivity, error) {
+				return NewInvestmentBuy(account, mustUnits(t, "0.00001", "VWRA"), mustMoney(t, "100.00", "USD"), mustMoney(t, "0", "USD"), time.Time{})
+			},
+			errContains: "invalid quantity",
+		},
+		{
+			name: "negative fee",
+			create: func() (*InvestmentActivity, error) {
+				return NewInvestmentBuy(account, mustUnits(t, "1", "VWRA"), mustMoney(t, "100.00", "USD"), mustMoney(t, "-1.00", "USD"), time.Time{})
+			},
+			errContains: "fee cannot be negative",
+		},
+		{
+			name: "zero dividend",
+			create: func() (*InvestmentActivity, error) {
+				return NewInvestmentDividend(account, "VWRA", mustMoney(t, "0", "USD"), time.Time{})
+			},
+			errContains: "dividend amount must be positive",
+		},
+		{
+			name: "split ratio of one",
+			create: func() (*InvestmentActivity, error) {
+				return NewInvestmentSplit(account, "VWRA", decimal.NewFromInt(1), time.Time{})
+			},
+			errContains: "split ratio must be positive and not 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.create()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestInvestmentActivity_CashAmount(t *testing.T) {
+	account := createTestInvestmentAccount(t)
+
+	buy, err := NewInvestmentBuy(account, mustUnits(t, "10", "VWRA"), mustMoney(t, "1000.00", "USD"), mustMoney(t, "10.00", "USD"), time.Time{})
+	require.NoError(t, err)
+	sell, err := NewInvestmentSell(account, mustUnits(t, "5", "VWRA"), mustMoney(t, "600.00", "USD"), mustMoney(t, "10.00", "USD"), time.Time{})
+	require.NoError(t, err)
+	dividend, err := NewInvestmentDividend(account, "VWRA", mustMoney(t, "12.34", "USD"), time.Time{})
+	require.NoError(t, err)
+	split, err := NewInvestmentSplit(account, "VWRA", decimal.NewFromInt(2), time.Time{})
+	require.NoError(t, err)
+	fee, err := NewInvestmentFee(account, mustMoney(t, "5.00", "USD"), time.Time{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		activity *InvestmentActivity
+		want     string
+	}{
+		{activity: buy, want: "-1010.00 USD"},
+		{activity: sell, want: "590.00 USD"},
+		{activity: dividend, want: "12.34 USD"},
+		{activity: split, want: "0.00 USD"},
+		{activity: fee, want: "-5.00 USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.activity.Type.String(), func(t *testing.T) {
+			cash, err := tt.activity.CashAmount()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cash.StringFixed(2))
+		})
+	}
+}
+
+func mustUnits(t *testing.T, quantity string, security money.Currency) money.Money {
+	t.Helper()
+
+	m, err := money.NewMoney(quantity, security)
+	require.NoError(t, err)
+	return m
+}
+
+func createTestInvestmentAccount(t *testing.T) *Account {
+	t.Helper()
+
+	require.NoError(t, money.RegisterCommodity(money.CurrencyInfo{
+		Code: "VWRA", Kind: money.CommodityKindSecurity, MinorUnits: 4, Name: "Vanguard FTSE All-World",
+	}))
+
+	account, err := NewAccount(createTestAccount(t).LedgerID, "Brokerage", "", AccountTypeInvestment, "USD")
+	require.NoError(t, err)
+	return account
+}
@@ -0,0 +1,417 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// Posting is a single debit or credit line of a journal entry against either an account or a budget item
+type Posting struct {
+	AccountID optional.Option[AccountID]
+	ItemID    optional.Option[budgetEntity.ItemID]
+	Side      PostingSide
+	Amount    money.Money // Always positive; the side gives the direction
+	Memo      string
+}
+
+// NewAccountPosting creates a Posting against an account
+func NewAccountPosting(accountID AccountID, side PostingSide, amount money.Money) (Posting, error) {
+	p := Posting{
+		AccountID: optional.Some(accountID),
+		ItemID:    optional.None[budgetEntity.ItemID](),
+		Side:      side,
+		Amount:    amount,
+	}
+	if err := p.validate(); err != nil {
+		return Posting{}, err
+	}
+	return p, nil
+}
+
+// NewItemPosting creates a Posting against a budget item
+func NewItemPosting(itemID budgetEntity.ItemID, side PostingSide, amount money.Money) (Posting, error) {
+	p := Posting{
+		AccountID: optional.None[AccountID](),
+		ItemID:    optional.Some(itemID),
+		Side:      side,
+		Amount:    amount,
+	}
+	if err := p.validate(); err != nil {
+		return Posting{}, err
+	}
+	return p, nil
+}
+
+// WithMemo returns a copy of the posting with the memo set
+func (p Posting) WithMemo(memo string) Posting {
+	p.Memo = memo
+	return p
+}
+
+// IsAccount checks if the posting is against an account
+func (p Posting) IsAccount() bool {
+	return p.AccountID.IsSome()
+}
+
+// IsItem checks if the posting is against a budget item
+func (p Posting) IsItem() bool {
+	return p.ItemID.IsSome()
+}
+
+// IsForAccount checks if the posting is against the given account
+func (p Posting) IsForAccount(accountID AccountID) bool {
+	return p.AccountID.IsSome() && p.AccountID.Unwrap().Equals(accountID)
+}
+
+// IsForItem checks if the posting is against the given budget item
+func (p Posting) IsForItem(itemID budgetEntity.ItemID) bool {
+	return p.ItemID.IsSome() && p.ItemID.Unwrap().Equals(itemID)
+}
+
+// SignedAmount returns the amount as a balance change: positive for debits, negative for credits
+func (p Posting) SignedAmount() money.Money {
+	if p.Side.IsCredit() {
+		return p.Amount.Negate()
+	}
+	return p.Amount
+}
+
+func (p Posting) validate() error {
+	if p.AccountID.IsSome() == p.ItemID.IsSome() {
+		return fmt.Errorf("posting must target exactly one of an account or a budget item")
+	}
+
+	if p.AccountID.IsSome() && !p.AccountID.Unwrap().IsValid() {
+		return fmt.Errorf("posting account ID is invalid")
+	}
+
+	if p.ItemID.IsSome() && !p.ItemID.Unwrap().IsValid() {
+		return fmt.Errorf("posting item ID is invalid")
+	}
+
+	if _, err := NewPostingSide(string(p.Side)); err != nil {
+		return err
+	}
+
+	if !p.Amount.IsPositive() {
+		return fmt.Errorf("posting amount must be positive")
+	}
+
+	if err := p.Amount.ValidatePrecision(); err != nil {
+		return fmt.Errorf("invalid posting amount: %w", err)
+	}
+
+	return nil
+}
+
+// JournalEntry is a balanced set of postings recording one economic event.
+// Debits and credits must be equal for every currency in the entry.
+type JournalEntry struct {
+	ID                  JournalEntryID
+	LedgerID            ledgerEntity.LedgerID
+	SourceTransactionID optional.Option[TransactionID] // Set when mapped from a single-account Transaction
+	Postings            []Posting
+	Description         string
+	EntryDate           time.Time // When the event occurred
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// NewJournalEntry creates a new JournalEntry from two or more balanced postings
+func NewJournalEntry(
+	ledgerID ledgerEntity.LedgerID,
+	description string,
+	entryDate time.Time,
+	postings ...Posting,
+) (*JournalEntry, error) {
+	if !ledgerID.IsValid() {
+		return nil, fmt.Errorf("ledger ID is invalid")
+	}
+
+	if description == "" {
+		return nil, fmt.Errorf("journal entry description cannot be empty")
+	}
+
+	if err := validatePostings(postings); err != nil {
+		return nil, err
+	}
+
+	if entryDate.IsZero() {
+		entryDate = time.Now()
+	}
+
+	id, err := NewJournalEntryID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate journal entry ID: %w", err)
+	}
+
+	now := time.Now()
+
+	return &JournalEntry{
+		ID:                  id,
+		LedgerID:            ledgerID,
+		SourceTransactionID: optional.None[TransactionID](),
+		Postings:            append([]Posting(nil), postings...),
+		Description:         description,
+		EntryDate:           entryDate,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}, nil
+}
+
+// NewJournalEntryFromTransaction maps a single-account Transaction onto a balanced entry:
+// one posting against the account and an opposite posting against the budget item, or one
+// against each item of a split. Money flowing into the account debits the account and credits the item.
+func NewJournalEntryFromTransaction(tx *Transaction) (*JournalEntry, error) {
+	postings, err := transactionPostings(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := NewJournalEntry(tx.LedgerID, tx.Description, tx.TransactionDate, postings...)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.SourceTransactionID = optional.Some(tx.ID)
+	return entry, nil
+}
+
+// ReconstructJournalEntry reconstructs a JournalEntry from stored data
+func ReconstructJournalEntry(
+	id JournalEntryID,
+	ledgerID ledgerEntity.LedgerID,
+	sourceTransactionID optional.Option[TransactionID],
+	postings []Posting,
+	description string,
+	entryDate, createdAt, updatedAt time.Time,
+) *JournalEntry {
+	return &JournalEntry{
+		ID:                  id,
+		LedgerID:            ledgerID,
+		SourceTransactionID: sourceTransactionID,
+		Postings:            postings,
+		Description:         description,
+		EntryDate:           entryDate,
+		CreatedAt:           createdAt,
+		UpdatedAt:           updatedAt,
+	}
+}
+
+// UpdateInfo updates the journal entry's description
+func (e *JournalEntry) UpdateInfo(description string) error {
+	if description == "" {
+		return fmt.Errorf("journal entry description cannot be empty")
+	}
+
+	e.Description = description
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+// UpdateEntryDate updates when the event occurred
+func (e *JournalEntry) UpdateEntryDate(entryDate time.Time) {
+	e.EntryDate = entryDate
+	e.UpdatedAt = time.Now()
+}
+
+// ReplacePostings replaces all postings, keeping the entry balanced
+func (e *JournalEntry) ReplacePostings(postings ...Posting) error {
+	if err := validatePostings(postings); err != nil {
+		return err
+	}
+
+	e.Postings = append([]Posting(nil), postings...)
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+// UpdateFromTransaction maps the entry's source transaction onto it again after the transaction changed
+func (e *JournalEntry) UpdateFromTransaction(tx *Transaction) error {
+	if e.SourceTransactionID.IsNone() || !e.SourceTransactionID.Unwrap().Equals(tx.ID) {
+		return fmt.Errorf("journal entry %s is not recorded from transaction %s", e.ID, tx.ID)
+	}
+
+	postings, err := transactionPostings(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := e.ReplacePostings(postings...); err != nil {
+		return err
+	}
+	if err := e.UpdateInfo(tx.Description); err != nil {
+		return err
+	}
+	e.UpdateEntryDate(tx.TransactionDate)
+	return nil
+}
+
+// IsBalanced checks that debits equal credits for every currency
+func (e *JournalEntry) IsBalanced() bool {
+	imbalance, err := postingsImbalance(e.Postings)
+	return err == nil && imbalance.IsZero()
+}
+
+// AccountChange returns the net balance change the entry makes to the account, per currency
+func (e *JournalEntry) AccountChange(accountID AccountID) (money.Bag, error) {
+	return netPostings(e.Postings, func(p Posting) bool { return p.IsForAccount(accountID) })
+}
+
+// ItemChange returns the net debit (spending minus income) the entry records against the item, per currency
+func (e *JournalEntry) ItemChange(itemID budgetEntity.ItemID) (money.Bag, error) {
+	return netPostings(e.Postings, func(p Posting) bool { return p.IsForItem(itemID) })
+}
+
+// DeriveAccountBalance computes an account's balance from the postings of the given entries
+func DeriveAccountBalance(accountID AccountID, currency money.Currency, entries []*JournalEntry) (money.Money, error) {
+	balance, err := money.Zero(currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	for _, e := range entries {
+		for _, p := range e.Postings {
+			if !p.IsForAccount(accountID) {
+				continue
+			}
+
+			balance, err = balance.Add(p.SignedAmount())
+			if err != nil {
+				return money.Money{}, fmt.Errorf("failed to apply posting of journal entry %s: %w", e.ID, err)
+			}
+		}
+	}
+
+	return balance, nil
+}
+
+// DeriveItemActuals recomputes the item's monthly ActualAmount from the postings of the given entries.
+// Expense and transfer items accumulate net debits; income items accumulate net credits.
+// Months with existing tracking but no postings are reset to zero.
+func DeriveItemActuals(item *budgetEntity.Item, entries []*JournalEntry) error {
+	type month struct{ year, month int }
+
+	actuals := make(map[month]money.Money)
+	for _, e := range entries {
+		for _, p := range e.Postings {
+			if !p.IsForItem(item.ID) {
+				continue
+			}
+
+			amount := p.SignedAmount()
+			if item.Type.IsIncome() {
+				amount = amount.Negate()
+			}
+
+			key := month{year: e.EntryDate.Year(), month: int(e.EntryDate.Month())}
+			current, ok := actuals[key]
+			if !ok {
+				actuals[key] = amount
+				continue
+			}
+
+			total, err := current.Add(amount)
+			if err != nil {
+				return fmt.Errorf("failed to apply posting of journal entry %s: %w", e.ID, err)
+			}
+			actuals[key] = total
+		}
+	}
+
+	zero, err := money.Zero(item.Currency)
+	if err != nil {
+		return fmt.Errorf("failed to create zero actual: %w", err)
+	}
+
+	for _, tracking := range item.MonthlyBudgets {
+		key := month{year: tracking.Year, month: tracking.Month}
+		if _, ok := actuals[key]; !ok {
+			actuals[key] = zero
+		}
+	}
+
+	for key, actual := range actuals {
+		if err := item.SetActualAmount(key.year, key.month, actual); err != nil {
+			return fmt.Errorf("failed to set actual amount for %04d-%02d: %w", key.year, key.month, err)
+		}
+	}
+
+	return nil
+}
+
+// transactionPostings returns the account posting of the transaction followed by one item posting per line
+func transactionPostings(tx *Transaction) ([]Posting, error) {
+	accountSide := PostingSideDebit
+	if tx.Amount.IsNegative() {
+		accountSide = PostingSideCredit
+	}
+
+	accountPosting, err := NewAccountPosting(tx.AccountID, accountSide, tx.Amount.Abs())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account posting: %w", err)
+	}
+
+	postings := []Posting{accountPosting}
+	for _, line := range tx.Lines() {
+		// A split line can run against the transaction, e.g. a refund netted off a purchase
+		itemSide := PostingSideCredit
+		if line.Amount.IsNegative() {
+			itemSide = PostingSideDebit
+		}
+
+		itemPosting, err := NewItemPosting(line.ItemID, itemSide, line.Amount.Abs())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create item posting: %w", err)
+		}
+		postings = append(postings, itemPosting.WithMemo(line.Note))
+	}
+	return postings, nil
+}
+
+func validatePostings(postings []Posting) error {
+	if len(postings) < 2 {
+		return fmt.Errorf("journal entry requires at least two postings")
+	}
+
+	for i, p := range postings {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("invalid posting %d: %w", i, err)
+		}
+	}
+
+	imbalance, err := postingsImbalance(postings)
+	if err != nil {
+		return err
+	}
+	if !imbalance.IsZero() {
+		return fmt.Errorf("journal entry is unbalanced: debits exceed credits by %s", imbalance)
+	}
+
+	return nil
+}
+
+func postingsImbalance(postings []Posting) (money.Bag, error) {
+	return netPostings(postings, func(Posting) bool { return true })
+}
+
+func netPostings(postings []Posting, include func(Posting) bool) (money.Bag, error) {
+	var net money.Bag
+	for _, p := range postings {
+		if !include(p) {
+			continue
+		}
+
+		var err error
+		net, err = net.Add(p.SignedAmount())
+		if err != nil {
+			return money.Bag{}, err
+		}
+	}
+	return net, nil
+}
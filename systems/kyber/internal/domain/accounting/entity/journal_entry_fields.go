@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/kneadCODE/coruscant/shared/golib/id"
+)
+
+// JournalEntryID represents a unique identifier for a journal entry using UUIDv7
+type JournalEntryID struct {
+	id.EntityID
+}
+
+// NewJournalEntryID creates a new JournalEntryID using UUIDv7
+func NewJournalEntryID() (JournalEntryID, error) {
+	base, err := id.NewEntityID()
+	if err != nil {
+		return JournalEntryID{}, fmt.Errorf("failed to create journal entry ID: %w", err)
+	}
+	return JournalEntryID{EntityID: base}, nil
+}
+
+// NewJournalEntryIDFromString creates a JournalEntryID from an existing string
+func NewJournalEntryIDFromString(idStr string) (JournalEntryID, error) {
+	base, err := id.NewEntityIDFromString(idStr)
+	if err != nil {
+		return JournalEntryID{}, fmt.Errorf("failed to create journal entry ID: %w", err)
+	}
+	return JournalEntryID{EntityID: base}, nil
+}
+
+// Equals checks if two JournalEntryIDs are equal
+func (j JournalEntryID) Equals(other JournalEntryID) bool {
+	return j.EntityID.Equals(other.EntityID)
+}
+
+// PostingSide represents the side of a posting in double-entry bookkeeping.
+// A debit increases an account's balance and a credit decreases it (liability
+// balances are negative). For budget items a debit records spending and a credit
+// records income. Note this is the reverse of the bank-statement wording used by
+// Transaction.IsCredit, where money flowing into the account is a "credit".
+type PostingSide string
+
+// Posting side constants
+const (
+	PostingSideDebit  PostingSide = "DEBIT"
+	PostingSideCredit PostingSide = "CREDIT"
+)
+
+// NewPostingSide creates a new PostingSide from string
+func NewPostingSide(side string) (PostingSide, error) {
+	switch PostingSide(side) {
+	case PostingSideDebit, PostingSideCredit:
+		return PostingSide(side), nil
+	default:
+		return "", fmt.Errorf("invalid posting side: %s", side)
+	}
+}
+
+// String returns the string representation of PostingSide
+func (p PostingSide) String() string {
+	return string(p)
+}
+
+// IsDebit checks if the side is a debit
+func (p PostingSide) IsDebit() bool {
+	return p == PostingSideDebit
+}
+
+// IsCredit checks if the side is a credit
+func (p PostingSide) IsCredit() bool {
+	return p == PostingSideCredit
+}
+
+// Opposite returns the other side
+func (p PostingSide) Opposite() PostingSide {
+	if p.IsDebit() {
+		return PostingSideCredit
+	}
+	return PostingSideDebit
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournalEntryID_NewJournalEntryID(t *testing.T) {
+	id, err := NewJournalEntryID()
+
+	require.NoError(t, err)
+	assert.True(t, id.IsValid())
+	assert.NotEmpty(t, id.String())
+}
+
+func TestJournalEntryID_NewJournalEntryIDFromString(t *testing.T) {
+	validID, err := NewJournalEntryID()
+	require.NoError(t, err)
+
+	parsed, err := NewJournalEntryIDFromString(validID.String())
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(validID))
+
+	_, err = NewJournalEntryIDFromString("invalid-uuid")
+	assert.Error(t, err)
+}
+
+func TestPostingSide(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		isDebit  bool
+		opposite PostingSide
+	}{
+		{input: "DEBIT", isDebit: true, opposite: PostingSideCredit},
+		{input: "CREDIT", isDebit: false, opposite: PostingSideDebit},
+		{input: "debit", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			side, err := NewPostingSide(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid posting side")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.input, side.String())
+			assert.Equal(t, tt.isDebit, side.IsDebit())
+			assert.Equal(t, !tt.isDebit, side.IsCredit())
+			assert.Equal(t, tt.opposite, side.Opposite())
+		})
+	}
+}
@@ -0,0 +1,505 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+func TestNewAccountPostingAndItemPosting(t *testing.T) {
+	accountID, err := NewAccountID()
+	require.NoError(t, err)
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		create      func() (Posting, error)
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "valid account posting",
+			create: func() (Posting, error) {
+				return NewAccountPosting(accountID, PostingSideDebit, mustMoney(t, "10.00", "USD"))
+			},
+		},
+		{
+			name: "valid item posting",
+			create: func() (Posting, error) {
+				return NewItemPosting(itemID, PostingSideCredit, mustMoney(t, "10.00", "USD"))
+			},
+		},
+		{
+			name: "invalid account ID",
+			create: func() (Posting, error) {
+				return NewAccountPosting(AccountID{}, PostingSideDebit, mustMoney(t, "10.00", "USD"))
+			},
+			wantErr:     true,
+			errContains: "posting account ID is invalid",
+		},
+		{
+			name: "invalid item ID",
+			create: func() (Posting, error) {
+				return NewItemPosting(budgetEntity.ItemID{}, PostingSideDebit, mustMoney(t, "10.00", "USD"))
+			},
+			wantErr:     true,
+			errContains: "posting item ID is invalid",
+		},
+		{
+			name:        "invalid side",
+			create:      func() (Posting, error) { return NewAccountPosting(accountID, "LEFT", mustMoney(t, "10.00", "USD")) },
+			wantErr:     true,
+			errContains: "invalid posting side",
+		},
+		{
+			name: "negative amount",
+			create: func() (Posting, error) {
+				return NewAccountPosting(accountID, PostingSideDebit, mustMoney(t, "-10.00", "USD"))
+			},
+			wantErr:     true,
+			errContains: "posting amount must be positive",
+		},
+		{
+			name: "zero amount",
+			create: func() (Posting, error) {
+				return NewAccountPosting(accountID, PostingSideDebit, mustMoney(t, "0", "USD"))
+			},
+			wantErr:     true,
+			errContains: "posting amount must be positive",
+		},
+		{
+			name: "excess precision",
+			create: func() (Posting, error) {
+				return NewAccountPosting(accountID, PostingSideDebit, mustMoney(t, "1.5", "JPY"))
+			},
+			wantErr:     true,
+			errContains: "invalid posting amount",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := tt.create()
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEqual(t, p.IsAccount(), p.IsItem())
+		})
+	}
+}
+
+func TestPosting_SignedAmount(t *testing.T) {
+	accountID, err := NewAccountID()
+	require.NoError(t, err)
+
+	debit := mustPosting(t)(NewAccountPosting(accountID, PostingSideDebit, mustMoney(t, "10.00", "USD")))
+	credit := mustPosting(t)(NewAccountPosting(accountID, PostingSideCredit, mustMoney(t, "10.00", "USD")))
+
+	assert.Equal(t, mustMoney(t, "10.00", "USD"), debit.SignedAmount())
+	assert.Equal(t, mustMoney(t, "-10.00", "USD"), credit.SignedAmount())
+	assert.True(t, debit.IsForAccount(accountID))
+	assert.Equal(t, "note", debit.WithMemo("note").Memo)
+	assert.Empty(t, debit.Memo)
+}
+
+func TestNewJournalEntry(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+	checking, err := NewAccountID()
+	require.NoError(t, err)
+	savings, err := NewAccountID()
+	require.NoError(t, err)
+	groceries, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+	household, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	p := mustPosting(t)
+	entryDate := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		ledgerID    ledgerEntity.LedgerID
+		description string
+		postings    []Posting
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:        "transfer between accounts",
+			ledgerID:    ledgerID,
+			description: "Move to savings",
+			postings: []Posting{
+				p(NewAccountPosting(savings, PostingSideDebit, mustMoney(t, "500.00", "USD"))),
+				p(NewAccountPosting(checking, PostingSideCredit, mustMoney(t, "500.00", "USD"))),
+			},
+		},
+		{
+			name:        "split purchase across items",
+			ledgerID:    ledgerID,
+			description: "Supermarket",
+			postings: []Posting{
+				p(NewItemPosting(groceries, PostingSideDebit, mustMoney(t, "80.00", "USD"))),
+				p(NewItemPosting(household, PostingSideDebit, mustMoney(t, "20.00", "USD"))),
+				p(NewAccountPosting(checking, PostingSideCredit, mustMoney(t, "100.00", "USD"))),
+			},
+		},
+		{
+			name:        "balanced in each of two currencies",
+			ledgerID:    ledgerID,
+			description: "Multi-currency",
+			postings: []Posting{
+				p(NewItemPosting(groceries, PostingSideDebit, mustMoney(t, "10.00", "USD"))),
+				p(NewAccountPosting(checking, PostingSideCredit, mustMoney(t, "10.00", "USD"))),
+				p(NewItemPosting(groceries, PostingSideDebit, mustMoney(t, "13.50", "SGD"))),
+				p(NewAccountPosting(savings, PostingSideCredit, mustMoney(t, "13.50", "SGD"))),
+			},
+		},
+		{
+			name:        "unbalanced",
+			ledgerID:    ledgerID,
+			description: "Broken",
+			postings: []Posting{
+				p(NewItemPosting(groceries, PostingSideDebit, mustMoney(t, "80.00", "USD"))),
+				p(NewAccountPosting(checking, PostingSideCredit, mustMoney(t, "79.99", "USD"))),
+			},
+			wantErr:     true,
+			errContains: "journal entry is unbalanced: debits exceed credits by 0.01 USD",
+		},
+		{
+			name:        "balanced overall but not per currency",
+			ledgerID:    ledgerID,
+			description: "Broken",
+			postings: []Posting{
+				p(NewItemPosting(groceries, PostingSideDebit, mustMoney(t, "10.00", "USD"))),
+				p(NewAccountPosting(checking, PostingSideCredit, mustMoney(t, "10.00", "SGD"))),
+			},
+			wantErr:     true,
+			errContains: "journal entry is unbalanced",
+		},
+		{
+			name:        "single posting",
+			ledgerID:    ledgerID,
+			description: "Broken",
+			postings: []Posting{
+				p(NewItemPosting(groceries, PostingSideDebit, mustMoney(t, "10.00", "USD"))),
+			},
+			wantErr:     true,
+			errContains: "at least two postings",
+		},
+		{
+			name:        "posting with no target",
+			ledgerID:    ledgerID,
+			description: "Broken",
+			postings: []Posting{
+				{Side: PostingSideDebit, Amount: mustMoney(t, "10.00", "USD")},
+				p(NewAccountPosting(checking, PostingSideCredit, mustMoney(t, "10.00", "USD"))),
+			},
+			wantErr:     true,
+			errContains: "invalid posting 0: posting must target exactly one",
+		},
+		{
+			name:        "invalid ledger ID",
+			ledgerID:    ledgerEntity.LedgerID{},
+			description: "Test",
+			wantErr:     true,
+			errContains: "ledger ID is invalid",
+		},
+		{
+			name:        "empty description",
+			ledgerID:    ledgerID,
+			description: "",
+			wantErr:     true,
+			errContains: "journal entry description cannot be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := NewJournalEntry(tt.ledgerID, tt.description, entryDate, tt.postings...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Nil(t, entry)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, entry.ID.IsValid())
+			assert.Equal(t, tt.ledgerID, entry.LedgerID)
+			assert.Equal(t, tt.postings, entry.Postings)
+			assert.Equal(t, entryDate, entry.EntryDate)
+			assert.True(t, entry.SourceTransactionID.IsNone())
+			assert.True(t, entry.IsBalanced())
+		})
+	}
+}
+
+func TestNewJournalEntryFromTransaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      string
+		accountSide PostingSide
+	}{
+		{name: "outflow credits the account", amount: "-42.50", accountSide: PostingSideCredit},
+		{name: "inflow debits the account", amount: "3000.00", accountSide: PostingSideDebit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := createTestTransaction(t)
+			require.NoError(t, tx.UpdateAmount(mustMoney(t, tt.amount, "USD")))
+
+			entry, err := NewJournalEntryFromTransaction(tx)
+			require.NoError(t, err)
+
+			assert.Equal(t, tx.LedgerID, entry.LedgerID)
+			assert.Equal(t, tx.Description, entry.Description)
+			assert.Equal(t, tx.TransactionDate, entry.EntryDate)
+			require.True(t, entry.SourceTransactionID.IsSome())
+			assert.True(t, entry.SourceTransactionID.Unwrap().Equals(tx.ID))
+
+			require.Len(t, entry.Postings, 2)
+			assert.True(t, entry.Postings[0].IsForAccount(tx.AccountID))
+			assert.Equal(t, tt.accountSide, entry.Postings[0].Side)
+			assert.True(t, entry.Postings[1].IsForItem(tx.ItemID))
+			assert.Equal(t, tt.accountSide.Opposite(), entry.Postings[1].Side)
+
+			change, err := entry.AccountChange(tx.AccountID)
+			require.NoError(t, err)
+			assert.True(t, change.Get("USD").Equals(tx.Amount), "account change should equal the signed transaction amount")
+		})
+	}
+}
+
+func TestNewJournalEntryFromTransaction_Split(t *testing.T) {
+	tx := createTestTransaction(t)
+	require.NoError(t, tx.UpdateAmount(mustMoney(t, "-80.00", "USD")))
+
+	household := mustItemID(t)
+	require.NoError(t, tx.Split([]SplitLine{
+		{ItemID: tx.ItemID, Amount: mustMoney(t, "-100.00", "USD")},
+		{ItemID: household, Amount: mustMoney(t, "20.00", "USD"), Note: "Returned bottle deposit"},
+	}))
+
+	entry, err := NewJournalEntryFromTransaction(tx)
+	require.NoError(t, err)
+	require.Len(t, entry.Postings, 3)
+	assert.True(t, entry.IsBalanced())
+
+	spent, err := entry.ItemChange(tx.ItemID)
+	require.NoError(t, err)
+	assert.Equal(t, "100.00 USD", spent.Get("USD").String())
+
+	refunded, err := entry.ItemChange(household)
+	require.NoError(t, err)
+	assert.Equal(t, "-20.00 USD", refunded.Get("USD").String())
+	assert.Equal(t, "Returned bottle deposit", entry.Postings[2].Memo)
+
+	balance, err := DeriveAccountBalance(tx.AccountID, "USD", []*JournalEntry{entry})
+	require.NoError(t, err)
+	assert.Equal(t, "-80.00 USD", balance.String())
+}
+
+func TestJournalEntry_UpdateFromTransaction(t *testing.T) {
+	tx := createTestTransaction(t)
+	require.NoError(t, tx.UpdateAmount(mustMoney(t, "-42.50", "USD")))
+
+	entry, err := NewJournalEntryFromTransaction(tx)
+	require.NoError(t, err)
+
+	require.NoError(t, tx.UpdateAmount(mustMoney(t, "-45.00", "USD")))
+	require.NoError(t, tx.UpdateInfo("Corner shop", ""))
+	require.NoError(t, entry.UpdateFromTransaction(tx))
+
+	assert.Equal(t, "Corner shop", entry.Description)
+	change, err := entry.AccountChange(tx.AccountID)
+	require.NoError(t, err)
+	assert.Equal(t, "-45.00 USD", change.Get("USD").String())
+
+	other := createTestTransaction(t)
+	assert.Error(t, entry.UpdateFromTransaction(other), "an entry only follows its own source transaction")
+}
+
+func TestJournalEntry_ReplacePostings(t *testing.T) {
+	entry, checking, groceries := createTestJournalEntry(t, "25.00")
+	before := entry.UpdatedAt
+	time.Sleep(time.Millisecond)
+
+	p := mustPosting(t)
+	err := entry.ReplacePostings(
+		p(NewItemPosting(groceries, PostingSideDebit, mustMoney(t, "30.00", "USD"))),
+		p(NewAccountPosting(checking, PostingSideCredit, mustMoney(t, "25.00", "USD"))),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, mustMoney(t, "25.00", "USD"), entry.Postings[0].Amount, "postings should be unchanged on error")
+
+	err = entry.ReplacePostings(
+		p(NewItemPosting(groceries, PostingSideDebit, mustMoney(t, "30.00", "USD"))),
+		p(NewAccountPosting(checking, PostingSideCredit, mustMoney(t, "30.00", "USD"))),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, mustMoney(t, "30.00", "USD"), entry.Postings[0].Amount)
+	assert.True(t, entry.UpdatedAt.After(before))
+}
+
+func TestJournalEntry_UpdateInfoAndDate(t *testing.T) {
+	entry, _, _ := createTestJournalEntry(t, "25.00")
+
+	assert.Error(t, entry.UpdateInfo(""))
+	require.NoError(t, entry.UpdateInfo("Updated"))
+	assert.Equal(t, "Updated", entry.Description)
+
+	newDate := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	entry.UpdateEntryDate(newDate)
+	assert.Equal(t, newDate, entry.EntryDate)
+}
+
+func TestReconstructJournalEntry(t *testing.T) {
+	entry, _, _ := createTestJournalEntry(t, "25.00")
+
+	reconstructed := ReconstructJournalEntry(
+		entry.ID,
+		entry.LedgerID,
+		optional.None[TransactionID](),
+		entry.Postings,
+		entry.Description,
+		entry.EntryDate,
+		entry.CreatedAt,
+		entry.UpdatedAt,
+	)
+
+	assert.Equal(t, entry, reconstructed)
+}
+
+func TestDeriveAccountBalance(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+	account, err := NewAccount(ledgerID, "Checking", "", AccountTypeChecking, "USD")
+	require.NoError(t, err)
+	salary, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+	rent, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	p := mustPosting(t)
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	income, err := NewJournalEntry(ledgerID, "Salary", date,
+		p(NewAccountPosting(account.ID, PostingSideDebit, mustMoney(t, "3000.00", "USD"))),
+		p(NewItemPosting(salary, PostingSideCredit, mustMoney(t, "3000.00", "USD"))),
+	)
+	require.NoError(t, err)
+
+	expense, err := NewJournalEntry(ledgerID, "Rent", date,
+		p(NewItemPosting(rent, PostingSideDebit, mustMoney(t, "1200.00", "USD"))),
+		p(NewAccountPosting(account.ID, PostingSideCredit, mustMoney(t, "1200.00", "USD"))),
+	)
+	require.NoError(t, err)
+
+	entries := []*JournalEntry{income, expense}
+
+	balance, err := DeriveAccountBalance(account.ID, "USD", entries)
+	require.NoError(t, err)
+	assert.True(t, balance.Equals(mustMoney(t, "1800.00", "USD")))
+
+	require.NoError(t, account.RecalculateBalance(entries))
+	assert.True(t, account.Balance.Equals(mustMoney(t, "1800.00", "USD")))
+
+	_, err = DeriveAccountBalance(account.ID, "SGD", entries)
+	assert.Error(t, err)
+}
+
+func TestDeriveItemActuals(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+	checking, err := NewAccountID()
+	require.NoError(t, err)
+
+	groceries, err := budgetEntity.NewItem(ledgerID, "Groceries", "", budgetEntity.ItemTypeExpense, "USD")
+	require.NoError(t, err)
+	salary, err := budgetEntity.NewItem(ledgerID, "Salary", "", budgetEntity.ItemTypeIncome, "USD")
+	require.NoError(t, err)
+
+	// Stale actual for a month that no longer has postings
+	require.NoError(t, groceries.AddActualAmount(2024, 1, mustMoney(t, "99.00", "USD")))
+
+	p := mustPosting(t)
+	march := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)
+
+	entries := []*JournalEntry{
+		mustJournalEntry(t, ledgerID, march,
+			p(NewItemPosting(groceries.ID, PostingSideDebit, mustMoney(t, "120.00", "USD"))),
+			p(NewAccountPosting(checking, PostingSideCredit, mustMoney(t, "120.00", "USD"))),
+		),
+		mustJournalEntry(t, ledgerID, march,
+			p(NewAccountPosting(checking, PostingSideDebit, mustMoney(t, "20.00", "USD"))),
+			p(NewItemPosting(groceries.ID, PostingSideCredit, mustMoney(t, "20.00", "USD"))),
+		),
+		mustJournalEntry(t, ledgerID, april,
+			p(NewItemPosting(groceries.ID, PostingSideDebit, mustMoney(t, "55.00", "USD"))),
+			p(NewAccountPosting(checking, PostingSideCredit, mustMoney(t, "55.00", "USD"))),
+		),
+		mustJournalEntry(t, ledgerID, march,
+			p(NewAccountPosting(checking, PostingSideDebit, mustMoney(t, "3000.00", "USD"))),
+			p(NewItemPosting(salary.ID, PostingSideCredit, mustMoney(t, "3000.00", "USD"))),
+		),
+	}
+
+	require.NoError(t, DeriveItemActuals(groceries, entries))
+	assert.True(t, groceries.GetMonthlyBudget(2024, 1).ActualAmount.IsZero(), "stale month should be reset")
+	assert.True(t, groceries.GetMonthlyBudget(2024, 3).ActualAmount.Equals(mustMoney(t, "100.00", "USD")), "refund should reduce spending")
+	assert.True(t, groceries.GetMonthlyBudget(2024, 4).ActualAmount.Equals(mustMoney(t, "55.00", "USD")))
+
+	require.NoError(t, DeriveItemActuals(salary, entries))
+	assert.True(t, salary.GetMonthlyBudget(2024, 3).ActualAmount.Equals(mustMoney(t, "3000.00", "USD")), "income should be positive")
+}
+
+// Helper functions
+
+func createTestJournalEntry(t *testing.T, amount string) (*JournalEntry, AccountID, budgetEntity.ItemID) {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+	accountID, err := NewAccountID()
+	require.NoError(t, err)
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	p := mustPosting(t)
+	entry := mustJournalEntry(t, ledgerID, time.Now(),
+		p(NewItemPosting(itemID, PostingSideDebit, mustMoney(t, amount, "USD"))),
+		p(NewAccountPosting(accountID, PostingSideCredit, mustMoney(t, amount, "USD"))),
+	)
+	return entry, accountID, itemID
+}
+
+func mustJournalEntry(t *testing.T, ledgerID ledgerEntity.LedgerID, date time.Time, postings ...Posting) *JournalEntry {
+	t.Helper()
+
+	entry, err := NewJournalEntry(ledgerID, "Test entry", date, postings...)
+	require.NoError(t, err)
+	return entry
+}
+
+func mustPosting(t *testing.T) func(Posting, error) Posting {
+	t.Helper()
+
+	return func(p Posting, err error) Posting {
+		require.NoError(t, err)
+		return p
+	}
+}
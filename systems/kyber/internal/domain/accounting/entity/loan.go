@@ -0,0 +1,444 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+const (
+	// maxLoanTermMonths caps loan terms at 50 years
+	maxLoanTermMonths = 600
+	// daysPerYear is the day count used to accrue interest between repayments (actual/365)
+	daysPerYear = 365
+	// annuityPrecision is the number of decimal places (1+r)^n is worked out to
+	annuityPrecision = 24
+)
+
+// Loan holds the terms of a loan, mortgage or installment account. Its amortisation schedule is
+// derived from the terms rather than stored, see Schedule. The account balance is negative while
+// money is owed.
+type Loan struct {
+	AccountID   AccountID
+	LedgerID    ledgerEntity.LedgerID
+	Principal   money.Money // Amount borrowed
+	RateType    LoanRateType
+	Rates       []LoanRate // Oldest first; the first one takes effect on StartDate
+	TermMonths  int
+	Frequency   PaymentFrequency
+	StartDate   time.Time          // Date the money was borrowed; the first payment is due one period later
+	Rounding    money.RoundingMode // How interest and payments are rounded to the currency's minor units
+	AccruedFrom time.Time          // Date interest accrues from: the last recorded repayment, or StartDate
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewLoan sets up the terms of a loan, mortgage or installment account
+func NewLoan(
+	account *Account,
+	principal money.Money,
+	rateType LoanRateType,
+	annualRate money.Percent,
+	termMonths int,
+	frequency PaymentFrequency,
+	startDate time.Time,
+	rounding money.RoundingMode,
+) (*Loan, error) {
+	if !account.Type.IsLoan() {
+		return nil, fmt.Errorf("%s account %s is not a loan", account.Type, account.Name)
+	}
+
+	if principal.Currency != account.Currency {
+		return nil, fmt.Errorf("currency mismatch: account uses %s, principal uses %s", account.Currency, principal.Currency)
+	}
+
+	if !principal.IsPositive() {
+		return nil, fmt.Errorf("loan principal must be positive")
+	}
+
+	if err := principal.ValidatePrecision(); err != nil {
+		return nil, fmt.Errorf("invalid loan principal: %w", err)
+	}
+
+	if _, err := NewLoanRateType(rateType.String()); err != nil {
+		return nil, err
+	}
+
+	if err := validateAnnualRate(annualRate); err != nil {
+		return nil, err
+	}
+
+	if termMonths < 1 || termMonths > maxLoanTermMonths {
+		return nil, fmt.Errorf("loan term must be between 1 and %d months, got %d", maxLoanTermMonths, termMonths)
+	}
+
+	if _, err := NewPaymentFrequency(frequency.String()); err != nil {
+		return nil, err
+	}
+
+	if startDate.IsZero() {
+		return nil, fmt.Errorf("loan start date is required")
+	}
+
+	if !rounding.IsValid() {
+		return nil, fmt.Errorf("invalid rounding mode: %s", rounding)
+	}
+
+	now := time.Now()
+
+	return &Loan{
+		AccountID:   account.ID,
+		LedgerID:    account.LedgerID,
+		Principal:   principal,
+		RateType:    rateType,
+		Rates:       []LoanRate{{EffectiveDate: startDate, AnnualRate: annualRate}},
+		TermMonths:  termMonths,
+		Frequency:   frequency,
+		StartDate:   startDate,
+		Rounding:    rounding,
+		AccruedFrom: startDate,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// ReconstructLoan reconstructs a Loan from stored data
+func ReconstructLoan(
+	accountID AccountID,
+	ledgerID ledgerEntity.LedgerID,
+	principal money.Money,
+	rateType LoanRateType,
+	rates []LoanRate,
+	termMonths int,
+	frequency PaymentFrequency,
+	startDate time.Time,
+	rounding money.RoundingMode,
+	accruedFrom time.Time,
+	createdAt, updatedAt time.Time,
+) *Loan {
+	return &Loan{
+		AccountID:   accountID,
+		LedgerID:    ledgerID,
+		Principal:   principal,
+		RateType:    rateType,
+		Rates:       rates,
+		TermMonths:  termMonths,
+		Frequency:   frequency,
+		StartDate:   startDate,
+		Rounding:    rounding,
+		AccruedFrom: accruedFrom,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+func validateAnnualRate(rate money.Percent) error {
+	if rate.IsNegative() || rate.Compare(money.NewPercentFromRatio(decimal.NewFromInt(1))) > 0 {
+		return fmt.Errorf("annual interest rate must be between 0%% and 100%%, got %s", rate)
+	}
+	return nil
+}
+
+// AddRateChange sets the annual rate of a variable rate loan from the effective date on,
+// replacing any change already set for that date
+func (l *Loan) AddRateChange(effectiveDate time.Time, annualRate money.Percent) error {
+	if !l.RateType.IsVariable() {
+		return fmt.Errorf("cannot change the rate of a %s rate loan", l.RateType)
+	}
+
+	if !effectiveDate.After(l.StartDate) {
+		return fmt.Errorf("rate change must take effect after the loan starts")
+	}
+
+	if err := validateAnnualRate(annualRate); err != nil {
+		return err
+	}
+
+	rate := LoanRate{EffectiveDate: effectiveDate, AnnualRate: annualRate}
+	i, found := slices.BinarySearchFunc(l.Rates, effectiveDate, func(r LoanRate, t time.Time) int {
+		return r.EffectiveDate.Compare(t)
+	})
+	if found {
+		l.Rates[i] = rate
+	} else {
+		l.Rates = slices.Insert(l.Rates, i, rate)
+	}
+
+	l.UpdatedAt = time.Now()
+	return nil
+}
+
+// RateAt returns the annual rate in force on the date
+func (l *Loan) RateAt(t time.Time) money.Percent {
+	rate := l.Rates[0].AnnualRate
+	for _, r := range l.Rates[1:] {
+		if r.EffectiveDate.After(t) {
+			break
+		}
+		rate = r.AnnualRate
+	}
+	return rate
+}
+
+// NumberOfPayments returns how many payments the term spans at the loan's frequency
+func (l *Loan) NumberOfPayments() int {
+	return max((l.TermMonths*l.Frequency.PeriodsPerYear()+6)/12, 1)
+}
+
+// Schedule returns the loan's amortisation schedule: equal payments of principal and interest that repay
+// the principal over the term. Each period is charged the rate in force when it starts; when the rate
+// changes the payment is worked out again over the remaining periods. The last payment clears what is left.
+func (l *Loan) Schedule() ([]LoanInstallment, error) {
+	zero, err := money.Zero(l.Principal.Currency)
+	if err != nil {
+		return nil, err
+	}
+	return l.amortise(nil, Prepayment{PerPayment: zero, LumpSum: zero})
+}
+
+// WhatIfPrepayment works out how paying extra principal on top of the scheduled payments shortens the loan
+// and how much interest it saves. Scheduled payments stay as they are; the loan is repaid sooner.
+func (l *Loan) WhatIfPrepayment(p Prepayment) (*PrepaymentImpact, error) {
+	for _, extra := range []money.Money{p.PerPayment, p.LumpSum} {
+		if extra.Currency != l.Principal.Currency {
+			return nil, fmt.Errorf("currency mismatch: loan uses %s, prepayment uses %s", l.Principal.Currency, extra.Currency)
+		}
+
+		if extra.IsNegative() {
+			return nil, fmt.Errorf("prepayment cannot be negative")
+		}
+
+		if err := extra.ValidatePrecision(); err != nil {
+			return nil, fmt.Errorf("invalid prepayment: %w", err)
+		}
+	}
+
+	base, err := l.Schedule()
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]money.Money, len(base))
+	for i, installment := range base {
+		payments[i] = installment.Payment
+	}
+
+	schedule, err := l.amortise(payments, p)
+	if err != nil {
+		return nil, err
+	}
+
+	baseInterest, err := totalInterest(base, l.Principal.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	newInterest, err := totalInterest(schedule, l.Principal.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := baseInterest.Subtract(newInterest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrepaymentImpact{
+		Schedule:        schedule,
+		OriginalEndDate: base[len(base)-1].DueDate,
+		NewEndDate:      schedule[len(schedule)-1].DueDate,
+		PaymentsSaved:   len(base) - len(schedule),
+		InterestSaved:   saved,
+	}, nil
+}
+
+// amortise builds the schedule. With no payments given, the payment is the annuity that repays the balance
+// over the remaining periods, worked out again whenever the rate changes; otherwise payments[i] is paid
+// with installment i+1. Prepayments go straight to principal.
+func (l *Loan) amortise(payments []money.Money, p Prepayment) ([]LoanInstallment, error) {
+	zero, err := money.Zero(l.Principal.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		n            = l.NumberOfPayments()
+		schedule     = make([]LoanInstallment, 0, n)
+		balance      = l.Principal
+		payment      money.Money
+		rate         money.Percent
+		lumpSumSpent = p.LumpSum.IsZero()
+	)
+	for i := 1; i <= n && balance.IsPositive(); i++ {
+		periodRate := l.RateAt(l.Frequency.PaymentDate(l.StartDate, i-1))
+		periodic := l.periodicRate(periodRate)
+
+		switch {
+		case payments != nil:
+			payment = payments[i-1]
+		case i == 1 || !periodRate.Equals(rate):
+			if payment, err = l.annuity(balance, periodic, n-i+1); err != nil {
+				return nil, err
+			}
+		}
+		rate = periodRate
+
+		installment := LoanInstallment{
+			Number:   i,
+			DueDate:  l.Frequency.PaymentDate(l.StartDate, i),
+			Rate:     rate,
+			Payment:  payment,
+			Interest: balance.MultiplyAndRound(periodic, l.Rounding),
+			Extra:    zero,
+		}
+
+		if installment.Principal, err = payment.Subtract(installment.Interest); err != nil {
+			return nil, err
+		}
+		if i == n || !installment.Principal.Amount.LessThan(balance.Amount) {
+			installment.Principal = balance
+			if installment.Payment, err = balance.Add(installment.Interest); err != nil {
+				return nil, err
+			}
+		}
+		if installment.Principal.IsNegative() {
+			return nil, fmt.Errorf("payment %d of %s does not cover its interest of %s", i, payment, installment.Interest)
+		}
+
+		if balance, err = balance.Subtract(installment.Principal); err != nil {
+			return nil, err
+		}
+
+		extra := p.PerPayment
+		if !lumpSumSpent && !installment.DueDate.Before(p.LumpSumDate) {
+			if extra, err = extra.Add(p.LumpSum); err != nil {
+				return nil, err
+			}
+			lumpSumSpent = true
+		}
+		if extra.Amount.GreaterThan(balance.Amount) {
+			extra = balance
+		}
+		installment.Extra = extra
+
+		if balance, err = balance.Subtract(extra); err != nil {
+			return nil, err
+		}
+		installment.Balance = balance
+
+		schedule = append(schedule, installment)
+	}
+	return schedule, nil
+}
+
+// annuity returns the equal payment that repays balance over periods at the periodic rate:
+// balance * r * (1+r)^n / ((1+r)^n - 1), or balance / n without interest
+func (l *Loan) annuity(balance money.Money, periodic decimal.Decimal, periods int) (money.Money, error) {
+	if periodic.IsZero() {
+		return balance.DivideAndRound(decimal.NewFromInt(int64(periods)), l.Rounding)
+	}
+
+	growth, err := decimal.NewFromInt(1).Add(periodic).PowWithPrecision(decimal.NewFromInt(int64(periods)), annuityPrecision)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("failed to work out the loan payment: %w", err)
+	}
+
+	factor := periodic.Mul(growth).DivRound(growth.Sub(decimal.NewFromInt(1)), annuityPrecision)
+	return balance.MultiplyAndRound(factor, l.Rounding), nil
+}
+
+// periodicRate returns the interest rate of one payment period
+func (l *Loan) periodicRate(annual money.Percent) decimal.Decimal {
+	return annual.Ratio().DivRound(decimal.NewFromInt(int64(l.Frequency.PeriodsPerYear())), annuityPrecision)
+}
+
+func totalInterest(schedule []LoanInstallment, currency money.Currency) (money.Money, error) {
+	total, err := money.Zero(currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	for _, installment := range schedule {
+		if total, err = total.Add(installment.Interest); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// AccruedInterest returns the interest owed (positive) accrued daily on owed from AccruedFrom to the date,
+// at the rates in force on each day
+func (l *Loan) AccruedInterest(owed money.Money, date time.Time) (money.Money, error) {
+	if owed.Currency != l.Principal.Currency {
+		return money.Money{}, fmt.Errorf("currency mismatch: loan uses %s, amount owed uses %s", l.Principal.Currency, owed.Currency)
+	}
+
+	if date.Before(l.AccruedFrom) {
+		return money.Money{}, fmt.Errorf("interest already accrued up to %s", l.AccruedFrom.Format(time.DateOnly))
+	}
+
+	ratio := decimal.Zero
+	from := l.AccruedFrom
+	for from.Before(date) {
+		to := date
+		for _, r := range l.Rates {
+			if r.EffectiveDate.After(from) && r.EffectiveDate.Before(to) {
+				to = r.EffectiveDate
+				break
+			}
+		}
+
+		days := decimal.NewFromInt(int64(daysBetween(from, to)))
+		ratio = ratio.Add(l.RateAt(from).Ratio().Mul(days).DivRound(decimal.NewFromInt(daysPerYear), annuityPrecision))
+		from = to
+	}
+
+	return owed.MultiplyAndRound(ratio, l.Rounding), nil
+}
+
+// SplitRepayment splits a repayment made on the date into the principal it repays and the interest
+// accrued on owed since the last repayment, then accrues interest from the date on. A repayment that
+// does not cover the accrued interest is all interest.
+func (l *Loan) SplitRepayment(owed, amount money.Money, date time.Time) (principal, interest money.Money, err error) {
+	if amount.Currency != l.Principal.Currency {
+		return money.Money{}, money.Money{}, fmt.Errorf("currency mismatch: loan uses %s, repayment uses %s",
+			l.Principal.Currency, amount.Currency)
+	}
+
+	if !amount.IsPositive() {
+		return money.Money{}, money.Money{}, fmt.Errorf("repayment must be positive")
+	}
+
+	if interest, err = l.AccruedInterest(owed, date); err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+
+	if !amount.Amount.GreaterThan(interest.Amount) {
+		interest = amount
+	}
+
+	if principal, err = amount.Subtract(interest); err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+
+	if principal.Amount.GreaterThan(owed.Amount) {
+		return money.Money{}, money.Money{}, fmt.Errorf("repayment of %s exceeds the %s owed plus %s interest",
+			amount, owed, interest)
+	}
+
+	l.AccruedFrom = date
+	l.UpdatedAt = time.Now()
+	return principal, interest, nil
+}
+
+// daysBetween returns the number of calendar days from one date to another
+func daysBetween(from, to time.Time) int {
+	fy, fm, fd := from.Date()
+	ty, tm, td := to.Date()
+	return int(time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC).Sub(time.Date(fy, fm, fd, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+}
@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// LoanRateType represents whether a loan's interest rate can change over its term
+type LoanRateType string
+
+// Loan rate type constants
+const (
+	LoanRateTypeFixed    LoanRateType = "FIXED"    // One rate for the whole term
+	LoanRateTypeVariable LoanRateType = "VARIABLE" // Dated rate changes; payments are recalculated when the rate changes
+)
+
+// NewLoanRateType creates a new LoanRateType from string
+func NewLoanRateType(rateType string) (LoanRateType, error) {
+	switch LoanRateType(rateType) {
+	case LoanRateTypeFixed, LoanRateTypeVariable:
+		return LoanRateType(rateType), nil
+	default:
+		return "", fmt.Errorf("invalid loan rate type: %s", rateType)
+	}
+}
+
+// String returns the string representation of LoanRateType
+func (t LoanRateType) String() string {
+	return string(t)
+}
+
+// IsVariable checks if the loan's rate can change over its term
+func (t LoanRateType) IsVariable() bool {
+	return t == LoanRateTypeVariable
+}
+
+// PaymentFrequency represents how often a loan is repaid
+type PaymentFrequency string
+
+// Payment frequency constants
+const (
+	PaymentFrequencyWeekly      PaymentFrequency = "WEEKLY"
+	PaymentFrequencyFortnightly PaymentFrequency = "FORTNIGHTLY"
+	PaymentFrequencyMonthly     PaymentFrequency = "MONTHLY"
+	PaymentFrequencyQuarterly   PaymentFrequency = "QUARTERLY"
+)
+
+// NewPaymentFrequency creates a new PaymentFrequency from string
+func NewPaymentFrequency(frequency string) (PaymentFrequency, error) {
+	switch PaymentFrequency(frequency) {
+	case PaymentFrequencyWeekly, PaymentFrequencyFortnightly, PaymentFrequencyMonthly, PaymentFrequencyQuarterly:
+		return PaymentFrequency(frequency), nil
+	default:
+		return "", fmt.Errorf("invalid payment frequency: %s", frequency)
+	}
+}
+
+// String returns the string representation of PaymentFrequency
+func (f PaymentFrequency) String() string {
+	return string(f)
+}
+
+// PeriodsPerYear returns the number of payments a year
+func (f PaymentFrequency) PeriodsPerYear() int {
+	switch f {
+	case PaymentFrequencyWeekly:
+		return 52
+	case PaymentFrequencyFortnightly:
+		return 26
+	case PaymentFrequencyQuarterly:
+		return 4
+	default:
+		return 12
+	}
+}
+
+// PaymentDate returns the date of the n-th payment after start; monthly and quarterly payments
+// fall on the start day, or the last day of shorter months
+func (f PaymentFrequency) PaymentDate(start time.Time, n int) time.Time {
+	switch f {
+	case PaymentFrequencyWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case PaymentFrequencyFortnightly:
+		return start.AddDate(0, 0, 14*n)
+	case PaymentFrequencyQuarterly:
+		return addMonthsClamped(start, 3*n)
+	default:
+		return addMonthsClamped(start, n)
+	}
+}
+
+// addMonthsClamped adds months to t, keeping its day unless the target month is shorter
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	lastDay := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	return time.Date(year, month+time.Month(months), min(day, lastDay), hour, minute, sec, t.Nanosecond(), t.Location())
+}
+
+// LoanRate is an annual interest rate in force from its effective date
+type LoanRate struct {
+	EffectiveDate time.Time
+	AnnualRate    money.Percent
+}
+
+// LoanInstallment is one scheduled repayment of a loan
+type LoanInstallment struct {
+	Number    int // 1-based
+	DueDate   time.Time
+	Rate      money.Percent // Annual rate charged for the period
+	Payment   money.Money   // Principal plus interest
+	Principal money.Money
+	Interest  money.Money
+	Extra     money.Money // Prepaid principal on top of the payment, in what-if schedules
+	Balance   money.Money // Owed after the payment
+}
+
+// Prepayment describes extra principal paid on top of the scheduled payments
+type Prepayment struct {
+	PerPayment  money.Money // Paid with every installment
+	LumpSum     money.Money // Paid once, with the first installment due on or after LumpSumDate
+	LumpSumDate time.Time
+}
+
+// PrepaymentImpact compares a loan's schedule with and without prepayments
+type PrepaymentImpact struct {
+	Schedule        []LoanInstallment // With the prepayments
+	OriginalEndDate time.Time
+	NewEndDate      time.Time
+	PaymentsSaved   int
+	InterestSaved   money.Money
+}
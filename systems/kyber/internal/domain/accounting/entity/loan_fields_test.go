@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLoanRateType(t *testing.T) {
+	rateType, err := NewLoanRateType("VARIABLE")
+	require.NoError(t, err)
+	assert.Equal(t, LoanRateTypeVariable, rateType)
+	assert.True(t, rateType.IsVariable())
+	assert.False(t, LoanRateTypeFixed.IsVariable())
+
+	_, err = NewLoanRateType("floating")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid loan rate type")
+}
+
+func TestNewPaymentFrequency(t *testing.T) {
+	frequency, err := NewPaymentFrequency("FORTNIGHTLY")
+	require.NoError(t, err)
+	assert.Equal(t, PaymentFrequencyFortnightly, frequency)
+
+	_, err = NewPaymentFrequency("DAILY")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid payment frequency")
+}
+
+func TestPaymentFrequency_PaymentDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		frequency PaymentFrequency
+		start     time.Time
+		n         int
+		want      time.Time
+		perYear   int
+	}{
+		{name: "weekly", frequency: PaymentFrequencyWeekly, start: date(2026, time.January, 1), n: 2, want: date(2026, time.January, 15), perYear: 52},
+		{name: "fortnightly", frequency: PaymentFrequencyFortnightly, start: date(2026, time.January, 1), n: 3, want: date(2026, time.February, 12), perYear: 26},
+		{name: "monthly", frequency: PaymentFrequencyMonthly, start: date(2026, time.January, 15), n: 13, want: date(2027, time.February, 15), perYear: 12},
+		{name: "monthly clamped", frequency: PaymentFrequencyMonthly, start: date(2026, time.January, 31), n: 1, want: date(2026, time.February, 28), perYear: 12},
+		{name: "monthly keeps day", frequency: PaymentFrequencyMonthly, start: date(2026, time.January, 31), n: 2, want: date(2026, time.March, 31), perYear: 12},
+		{name: "quarterly", frequency: PaymentFrequencyQuarterly, start: date(2026, time.November, 30), n: 1, want: date(2027, time.February, 28), perYear: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.frequency.PaymentDate(tt.start, tt.n))
+			assert.Equal(t, tt.perYear, tt.frequency.PeriodsPerYear())
+		})
+	}
+}
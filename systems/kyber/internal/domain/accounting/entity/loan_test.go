@@ -0,0 +1,282 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestNewLoan(t *testing.T) {
+	loan, account := createTestLoan(t, LoanRateTypeFixed, "1200.00", "12", 12)
+	assert.True(t, loan.AccountID.Equals(account.ID))
+	assert.True(t, loan.LedgerID.Equals(account.LedgerID))
+	assert.Equal(t, loan.StartDate, loan.AccruedFrom)
+	require.Len(t, loan.Rates, 1)
+	assert.Equal(t, loan.StartDate, loan.Rates[0].EffectiveDate)
+	assert.Equal(t, 12, loan.NumberOfPayments())
+
+	tests := []struct {
+		name        string
+		principal   money.Money
+		rate        string
+		termMonths  int
+		frequency   PaymentFrequency
+		start       time.Time
+		errContains string
+	}{
+		{name: "currency", principal: mustMoney(t, "100.00", "EUR"), rate: "5", termMonths: 12, frequency: PaymentFrequencyMonthly, start: date(2026, time.January, 1), errContains: "currency mismatch"},
+		{name: "zero principal", principal: mustMoney(t, "0", "USD"), rate: "5", termMonths: 12, frequency: PaymentFrequencyMonthly, start: date(2026, time.January, 1), errContains: "principal must be positive"},
+		{name: "negative rate", principal: mustMoney(t, "100.00", "USD"), rate: "-1", termMonths: 12, frequency: PaymentFrequencyMonthly, start: date(2026, time.January, 1), errContains: "between 0% and 100%"},
+		{name: "no term", principal: mustMoney(t, "100.00", "USD"), rate: "5", termMonths: 0, frequency: PaymentFrequencyMonthly, start: date(2026, time.January, 1), errContains: "loan term must be between 1 and 600"},
+		{name: "frequency", principal: mustMoney(t, "100.00", "USD"), rate: "5", termMonths: 12, frequency: "DAILY", start: date(2026, time.January, 1), errContains: "invalid payment frequency"},
+		{name: "no start", principal: mustMoney(t, "100.00", "USD"), rate: "5", termMonths: 12, frequency: PaymentFrequencyMonthly, errContains: "start date is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := money.NewPercent(tt.rate)
+			require.NoError(t, err)
+
+			_, err = NewLoan(account, tt.principal, LoanRateTypeFixed, rate, tt.termMonths, tt.frequency, tt.start, money.RoundHalfUp)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+
+	_, err := NewLoan(createTestAccount(t), loan.Principal, LoanRateTypeFixed, loan.Rates[0].AnnualRate,
+		12, PaymentFrequencyMonthly, loan.StartDate, money.RoundHalfUp)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a loan")
+}
+
+func TestLoan_Schedule(t *testing.T) {
+	t.Run("fixed rate", func(t *testing.T) {
+		loan, _ := createTestLoan(t, LoanRateTypeFixed, "1200.00", "12", 12)
+
+		schedule, err := loan.Schedule()
+		require.NoError(t, err)
+		require.Len(t, schedule, 12)
+
+		first := schedule[0]
+		assert.Equal(t, 1, first.Number)
+		assert.Equal(t, date(2026, time.February, 15), first.DueDate)
+		assert.Equal(t, "106.62 USD", first.Payment.String())
+		assert.Equal(t, "12.00 USD", first.Interest.String())
+		assert.Equal(t, "94.62 USD", first.Principal.String())
+		assert.Equal(t, "1105.38 USD", first.Balance.String())
+
+		last := schedule[11]
+		assert.Equal(t, date(2027, time.January, 15), last.DueDate)
+		assert.True(t, last.Balance.IsZero())
+		assert.Equal(t, "106.60 USD", last.Payment.String())
+
+		principal, err := money.Zero("USD")
+		require.NoError(t, err)
+		for _, installment := range schedule {
+			principal, err = principal.Add(installment.Principal)
+			require.NoError(t, err)
+		}
+		assert.True(t, principal.Equals(loan.Principal))
+
+		interest, err := totalInterest(schedule, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, "79.42 USD", interest.String())
+	})
+
+	t.Run("thirty year mortgage", func(t *testing.T) {
+		loan, _ := createTestLoan(t, LoanRateTypeFixed, "100000.00", "6", 360)
+
+		schedule, err := loan.Schedule()
+		require.NoError(t, err)
+		require.Len(t, schedule, 360)
+		assert.Equal(t, "599.55 USD", schedule[0].Payment.String())
+		assert.Equal(t, "500.00 USD", schedule[0].Interest.String())
+		assert.True(t, schedule[359].Balance.IsZero())
+	})
+
+	t.Run("interest free", func(t *testing.T) {
+		loan, _ := createTestLoan(t, LoanRateTypeFixed, "1000.00", "0", 3)
+
+		schedule, err := loan.Schedule()
+		require.NoError(t, err)
+		require.Len(t, schedule, 3)
+		assert.Equal(t, "333.33 USD", schedule[0].Payment.String())
+		assert.True(t, schedule[0].Interest.IsZero())
+		assert.Equal(t, "333.34 USD", schedule[2].Payment.String())
+	})
+
+	t.Run("variable rate recalculates the payment", func(t *testing.T) {
+		loan, _ := createTestLoan(t, LoanRateTypeVariable, "1200.00", "12", 12)
+		rate, err := money.NewPercent("24")
+		require.NoError(t, err)
+		require.NoError(t, loan.AddRateChange(date(2026, time.July, 1), rate))
+
+		schedule, err := loan.Schedule()
+		require.NoError(t, err)
+		require.Len(t, schedule, 12)
+
+		// The period starting 15 June is charged the old rate, the one starting 15 July the new one
+		assert.Equal(t, "106.62 USD", schedule[5].Payment.String())
+		assert.True(t, schedule[5].Rate.Equals(loan.Rates[0].AnnualRate))
+		assert.True(t, schedule[6].Rate.Equals(rate))
+		assert.True(t, schedule[6].Payment.Amount.GreaterThan(schedule[5].Payment.Amount))
+		assert.True(t, schedule[6].Payment.Equals(schedule[7].Payment))
+		assert.True(t, schedule[11].Balance.IsZero())
+	})
+}
+
+func TestLoan_AddRateChange(t *testing.T) {
+	rate, err := money.NewPercent("7")
+	require.NoError(t, err)
+
+	fixed, _ := createTestLoan(t, LoanRateTypeFixed, "1200.00", "12", 12)
+	err = fixed.AddRateChange(date(2026, time.June, 1), rate)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot change the rate of a FIXED rate loan")
+
+	loan, _ := createTestLoan(t, LoanRateTypeVariable, "1200.00", "12", 12)
+
+	err = loan.AddRateChange(loan.StartDate, rate)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must take effect after the loan starts")
+
+	later, err := money.NewPercent("8")
+	require.NoError(t, err)
+	require.NoError(t, loan.AddRateChange(date(2026, time.September, 1), later))
+	require.NoError(t, loan.AddRateChange(date(2026, time.June, 1), rate))
+	require.Len(t, loan.Rates, 3)
+	assert.Equal(t, date(2026, time.June, 1), loan.Rates[1].EffectiveDate)
+
+	replaced, err := money.NewPercent("9")
+	require.NoError(t, err)
+	require.NoError(t, loan.AddRateChange(date(2026, time.September, 1), replaced))
+	require.Len(t, loan.Rates, 3)
+
+	assert.True(t, loan.RateAt(date(2026, time.May, 31)).Equals(loan.Rates[0].AnnualRate))
+	assert.True(t, loan.RateAt(date(2026, time.June, 1)).Equals(rate))
+	assert.True(t, loan.RateAt(date(2027, time.January, 1)).Equals(replaced))
+}
+
+func TestLoan_WhatIfPrepayment(t *testing.T) {
+	loan, _ := createTestLoan(t, LoanRateTypeFixed, "1200.00", "12", 12)
+
+	t.Run("no prepayment", func(t *testing.T) {
+		impact, err := loan.WhatIfPrepayment(Prepayment{
+			PerPayment: mustMoney(t, "0", "USD"),
+			LumpSum:    mustMoney(t, "0", "USD"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, impact.OriginalEndDate, impact.NewEndDate)
+		assert.Zero(t, impact.PaymentsSaved)
+		assert.True(t, impact.InterestSaved.IsZero())
+	})
+
+	t.Run("extra with every payment", func(t *testing.T) {
+		impact, err := loan.WhatIfPrepayment(Prepayment{
+			PerPayment: mustMoney(t, "100.00", "USD"),
+			LumpSum:    mustMoney(t, "0", "USD"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, date(2027, time.January, 15), impact.OriginalEndDate)
+		assert.True(t, impact.NewEndDate.Before(impact.OriginalEndDate))
+		assert.Equal(t, 12-len(impact.Schedule), impact.PaymentsSaved)
+		assert.Positive(t, impact.PaymentsSaved)
+		assert.True(t, impact.InterestSaved.IsPositive())
+		assert.Equal(t, "100.00 USD", impact.Schedule[0].Extra.String())
+		assert.Equal(t, "1005.38 USD", impact.Schedule[0].Balance.String())
+		assert.True(t, impact.Schedule[len(impact.Schedule)-1].Balance.IsZero())
+	})
+
+	t.Run("lump sum", func(t *testing.T) {
+		impact, err := loan.WhatIfPrepayment(Prepayment{
+			PerPayment:  mustMoney(t, "0", "USD"),
+			LumpSum:     mustMoney(t, "500.00", "USD"),
+			LumpSumDate: date(2026, time.March, 1),
+		})
+		require.NoError(t, err)
+		assert.True(t, impact.Schedule[0].Extra.IsZero())
+		assert.Equal(t, "500.00 USD", impact.Schedule[1].Extra.String())
+		assert.True(t, impact.Schedule[2].Extra.IsZero())
+		assert.Positive(t, impact.PaymentsSaved)
+		assert.True(t, impact.InterestSaved.IsPositive())
+	})
+
+	t.Run("lump sum beyond what is owed", func(t *testing.T) {
+		impact, err := loan.WhatIfPrepayment(Prepayment{
+			PerPayment:  mustMoney(t, "0", "USD"),
+			LumpSum:     mustMoney(t, "5000.00", "USD"),
+			LumpSumDate: loan.StartDate,
+		})
+		require.NoError(t, err)
+		require.Len(t, impact.Schedule, 1)
+		assert.Equal(t, "1105.38 USD", impact.Schedule[0].Extra.String())
+		assert.Equal(t, 11, impact.PaymentsSaved)
+	})
+
+	_, err := loan.WhatIfPrepayment(Prepayment{
+		PerPayment: mustMoney(t, "-1.00", "USD"),
+		LumpSum:    mustMoney(t, "0", "USD"),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "prepayment cannot be negative")
+}
+
+func TestLoan_SplitRepayment(t *testing.T) {
+	loan, _ := createTestLoan(t, LoanRateTypeVariable, "36500.00", "10", 12)
+	owed := loan.Principal
+
+	// 31 days at 10% on 36500.00 accrues 310.00
+	principal, interest, err := loan.SplitRepayment(owed, mustMoney(t, "1000.00", "USD"), date(2026, time.February, 15))
+	require.NoError(t, err)
+	assert.Equal(t, "310.00 USD", interest.String())
+	assert.Equal(t, "690.00 USD", principal.String())
+	assert.Equal(t, date(2026, time.February, 15), loan.AccruedFrom)
+
+	// A rate change part way through accrues each part at its own rate: 10 days at 10%, 18 days at 20%
+	rate, err := money.NewPercent("20")
+	require.NoError(t, err)
+	require.NoError(t, loan.AddRateChange(date(2026, time.February, 25), rate))
+
+	principal, interest, err = loan.SplitRepayment(owed, mustMoney(t, "1000.00", "USD"), date(2026, time.March, 15))
+	require.NoError(t, err)
+	assert.Equal(t, "460.00 USD", interest.String())
+	assert.Equal(t, "540.00 USD", principal.String())
+
+	// A repayment short of the interest is all interest
+	principal, interest, err = loan.SplitRepayment(owed, mustMoney(t, "50.00", "USD"), date(2026, time.March, 25))
+	require.NoError(t, err)
+	assert.Equal(t, "50.00 USD", interest.String())
+	assert.True(t, principal.IsZero())
+
+	_, _, err = loan.SplitRepayment(owed, mustMoney(t, "100.00", "USD"), date(2026, time.March, 1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "interest already accrued up to 2026-03-25")
+
+	_, _, err = loan.SplitRepayment(mustMoney(t, "100.00", "USD"), mustMoney(t, "500.00", "USD"), date(2026, time.March, 26))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the 100.00 USD owed")
+	assert.Equal(t, date(2026, time.March, 25), loan.AccruedFrom)
+
+	_, _, err = loan.SplitRepayment(owed, mustMoney(t, "0", "USD"), date(2026, time.March, 26))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "repayment must be positive")
+}
+
+func createTestLoan(t *testing.T, rateType LoanRateType, principal, rate string, termMonths int) (*Loan, *Account) {
+	t.Helper()
+
+	account, err := NewAccount(createTestAccount(t).LedgerID, "Car Loan", "", AccountTypeLoan, "USD")
+	require.NoError(t, err)
+
+	annualRate, err := money.NewPercent(rate)
+	require.NoError(t, err)
+
+	loan, err := NewLoan(account, mustMoney(t, principal, "USD"), rateType, annualRate, termMonths,
+		PaymentFrequencyMonthly, date(2026, time.January, 15), money.RoundHalfUp)
+	require.NoError(t, err)
+	return loan, account
+}
@@ -0,0 +1,245 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// Reconciliation matches an account's cleared transactions against a bank statement.
+// The session is balanced once the opening balance plus all cleared transactions equals
+// the statement's closing balance; finishing it reconciles and locks those transactions.
+type Reconciliation struct {
+	ID             ReconciliationID
+	LedgerID       ledgerEntity.LedgerID
+	AccountID      AccountID
+	StatementDate  time.Time   // Last day covered by the statement
+	OpeningBalance money.Money // Closing balance of the previous reconciliation
+	ClosingBalance money.Money // Closing balance printed on the statement
+	Status         ReconciliationStatus
+	FinishedAt     optional.Option[time.Time]
+	Transactions   []*Transaction // Unreconciled transactions up to the statement date, while in progress
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// StartReconciliation starts reconciling the account against a statement.
+// previous is the account's last finished reconciliation, or nil if there is none.
+func (a *Account) StartReconciliation(
+	statementDate time.Time,
+	closingBalance money.Money,
+	previous *Reconciliation,
+) (*Reconciliation, error) {
+	if !a.Status.IsActive() {
+		return nil, fmt.Errorf("account is not active")
+	}
+
+	if statementDate.IsZero() {
+		return nil, fmt.Errorf("statement date cannot be empty")
+	}
+
+	if closingBalance.Currency != a.Currency {
+		return nil, fmt.Errorf("currency mismatch: account uses %s, statement uses %s", a.Currency, closingBalance.Currency)
+	}
+
+	if err := closingBalance.ValidatePrecision(); err != nil {
+		return nil, fmt.Errorf("invalid closing balance: %w", err)
+	}
+
+	opening, err := money.Zero(a.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize opening balance: %w", err)
+	}
+
+	if previous != nil {
+		if !previous.AccountID.Equals(a.ID) {
+			return nil, fmt.Errorf("previous reconciliation belongs to another account")
+		}
+
+		if !previous.Status.IsFinished() {
+			return nil, fmt.Errorf("previous reconciliation is not finished")
+		}
+
+		if !statementDate.After(previous.StatementDate) {
+			return nil, fmt.Errorf("statement date must be after the last reconciled statement date %s",
+				previous.StatementDate.Format(time.DateOnly))
+		}
+
+		opening = previous.ClosingBalance
+	}
+
+	id, err := NewReconciliationID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate reconciliation ID: %w", err)
+	}
+
+	now := time.Now()
+
+	return &Reconciliation{
+		ID:             id,
+		LedgerID:       a.LedgerID,
+		AccountID:      a.ID,
+		StatementDate:  statementDate,
+		OpeningBalance: opening,
+		ClosingBalance: closingBalance,
+		Status:         ReconciliationStatusInProgress,
+		FinishedAt:     optional.None[time.Time](),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// ReconstructReconciliation reconstructs a Reconciliation from stored data.
+// Transactions are attached separately with LoadTransactions.
+func ReconstructReconciliation(
+	id ReconciliationID,
+	ledgerID ledgerEntity.LedgerID,
+	accountID AccountID,
+	statementDate time.Time,
+	openingBalance, closingBalance money.Money,
+	status ReconciliationStatus,
+	finishedAt optional.Option[time.Time],
+	createdAt, updatedAt time.Time,
+) *Reconciliation {
+	return &Reconciliation{
+		ID:             id,
+		LedgerID:       ledgerID,
+		AccountID:      accountID,
+		StatementDate:  statementDate,
+		OpeningBalance: openingBalance,
+		ClosingBalance: closingBalance,
+		Status:         status,
+		FinishedAt:     finishedAt,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	}
+}
+
+// StatementCutoff returns the end of the statement date; transactions dated before it are on the statement
+func (r *Reconciliation) StatementCutoff() time.Time {
+	year, month, day := r.StatementDate.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, r.StatementDate.Location())
+}
+
+// LoadTransactions attaches the account's unreconciled transactions dated up to the statement date
+func (r *Reconciliation) LoadTransactions(transactions []*Transaction) error {
+	cutoff := r.StatementCutoff()
+	for _, tx := range transactions {
+		if !tx.AccountID.Equals(r.AccountID) {
+			return fmt.Errorf("transaction %s belongs to another account", tx.ID)
+		}
+
+		if tx.Status.IsReconciled() {
+			return fmt.Errorf("transaction %s is already reconciled", tx.ID)
+		}
+
+		if !tx.TransactionDate.Before(cutoff) {
+			return fmt.Errorf("transaction %s is dated after the statement date", tx.ID)
+		}
+	}
+
+	r.Transactions = transactions
+	return nil
+}
+
+// SetCleared ticks a transaction off (or un-ticks it) and returns it for saving
+func (r *Reconciliation) SetCleared(transactionID TransactionID, cleared bool) (*Transaction, error) {
+	if !r.Status.IsInProgress() {
+		return nil, fmt.Errorf("reconciliation is not in progress")
+	}
+
+	for _, tx := range r.Transactions {
+		if !tx.ID.Equals(transactionID) {
+			continue
+		}
+
+		var err error
+		if cleared {
+			err = tx.Clear()
+		} else {
+			err = tx.Unclear()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		r.UpdatedAt = time.Now()
+		return tx, nil
+	}
+
+	return nil, fmt.Errorf("transaction %s is not part of this reconciliation", transactionID)
+}
+
+// ClearedBalance returns the opening balance plus all cleared transactions
+func (r *Reconciliation) ClearedBalance() (money.Money, error) {
+	balance := r.OpeningBalance
+	for _, tx := range r.Transactions {
+		if !tx.Status.IsCleared() {
+			continue
+		}
+
+		var err error
+		balance, err = balance.Add(tx.Amount)
+		if err != nil {
+			return money.Money{}, fmt.Errorf("failed to apply transaction %s: %w", tx.ID, err)
+		}
+	}
+	return balance, nil
+}
+
+// Difference returns what is left to match: the statement closing balance minus the cleared balance
+func (r *Reconciliation) Difference() (money.Money, error) {
+	cleared, err := r.ClearedBalance()
+	if err != nil {
+		return money.Money{}, err
+	}
+	return r.ClosingBalance.Subtract(cleared)
+}
+
+// IsBalanced checks if the cleared balance matches the statement closing balance
+func (r *Reconciliation) IsBalanced() bool {
+	difference, err := r.Difference()
+	return err == nil && difference.IsZero()
+}
+
+// Finish reconciles and locks all cleared transactions once balanced, returning them for saving
+func (r *Reconciliation) Finish() ([]*Transaction, error) {
+	if !r.Status.IsInProgress() {
+		return nil, fmt.Errorf("reconciliation is not in progress")
+	}
+
+	difference, err := r.Difference()
+	if err != nil {
+		return nil, err
+	}
+	if !difference.IsZero() {
+		return nil, fmt.Errorf("reconciliation is off by %s", difference)
+	}
+
+	var reconciled []*Transaction
+	for _, tx := range r.Transactions {
+		if tx.Status.IsCleared() {
+			tx.markReconciled(r.ID)
+			reconciled = append(reconciled, tx)
+		}
+	}
+
+	now := time.Now()
+	r.Status = ReconciliationStatusFinished
+	r.FinishedAt = optional.Some(now)
+	r.UpdatedAt = now
+	return reconciled, nil
+}
+
+// IsInProgress checks if transactions are still being ticked off
+func (r *Reconciliation) IsInProgress() bool {
+	return r.Status.IsInProgress()
+}
+
+// IsFinished checks if the reconciliation has been completed
+func (r *Reconciliation) IsFinished() bool {
+	return r.Status.IsFinished()
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/kneadCODE/coruscant/shared/golib/id"
+)
+
+// ReconciliationID represents a unique identifier for a reconciliation using UUIDv7
+type ReconciliationID struct {
+	id.EntityID
+}
+
+// NewReconciliationID creates a new ReconciliationID using UUIDv7
+func NewReconciliationID() (ReconciliationID, error) {
+	base, err := id.NewEntityID()
+	if err != nil {
+		return ReconciliationID{}, fmt.Errorf("failed to create reconciliation ID: %w", err)
+	}
+	return ReconciliationID{EntityID: base}, nil
+}
+
+// NewReconciliationIDFromString creates a ReconciliationID from an existing string
+func NewReconciliationIDFromString(idStr string) (ReconciliationID, error) {
+	base, err := id.NewEntityIDFromString(idStr)
+	if err != nil {
+		return ReconciliationID{}, fmt.Errorf("failed to create reconciliation ID: %w", err)
+	}
+	return ReconciliationID{EntityID: base}, nil
+}
+
+// Equals checks if two ReconciliationIDs are equal
+func (t ReconciliationID) Equals(other ReconciliationID) bool {
+	return t.EntityID.Equals(other.EntityID)
+}
+
+// ReconciliationStatus represents the state of a statement reconciliation
+type ReconciliationStatus string
+
+// Reconciliation status constants
+const (
+	ReconciliationStatusInProgress ReconciliationStatus = "IN_PROGRESS" // Transactions are being ticked off
+	ReconciliationStatusFinished   ReconciliationStatus = "FINISHED"    // Balanced and cleared transactions reconciled
+)
+
+// NewReconciliationStatus creates a new ReconciliationStatus from string
+func NewReconciliationStatus(status string) (ReconciliationStatus, error) {
+	switch ReconciliationStatus(status) {
+	case ReconciliationStatusInProgress, ReconciliationStatusFinished:
+		return ReconciliationStatus(status), nil
+	default:
+		return "", fmt.Errorf("invalid reconciliation status: %s", status)
+	}
+}
+
+// String returns the string representation of ReconciliationStatus
+func (s ReconciliationStatus) String() string {
+	return string(s)
+}
+
+// IsInProgress checks if the reconciliation is still being worked on
+func (s ReconciliationStatus) IsInProgress() bool {
+	return s == ReconciliationStatusInProgress
+}
+
+// IsFinished checks if the reconciliation has been finished
+func (s ReconciliationStatus) IsFinished() bool {
+	return s == ReconciliationStatusFinished
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconciliationID_NewReconciliationID(t *testing.T) {
+	id, err := NewReconciliationID()
+
+	require.NoError(t, err)
+	assert.True(t, id.IsValid())
+	assert.NotEmpty(t, id.String())
+}
+
+func TestReconciliationID_NewReconciliationIDFromString(t *testing.T) {
+	validID, err := NewReconciliationID()
+	require.NoError(t, err)
+
+	parsed, err := NewReconciliationIDFromString(validID.String())
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(validID))
+
+	_, err = NewReconciliationIDFromString("invalid-uuid")
+	assert.Error(t, err)
+}
+
+func TestReconciliationStatus(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErr    bool
+		inProgress bool
+	}{
+		{input: "IN_PROGRESS", inProgress: true},
+		{input: "FINISHED", inProgress: false},
+		{input: "DONE", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			status, err := NewReconciliationStatus(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid reconciliation status")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.input, status.String())
+			assert.Equal(t, tt.inProgress, status.IsInProgress())
+			assert.Equal(t, !tt.inProgress, status.IsFinished())
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+func TestAccount_StartReconciliation(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	account := fundedAccount(ledgerID, AccountTypeChecking, "USD", "0")(t)
+	other := fundedAccount(ledgerID, AccountTypeSavings, "USD", "0")(t)
+
+	finished := func(a *Account, statementDate time.Time, closing string) *Reconciliation {
+		r, err := a.StartReconciliation(statementDate, mustMoney(t, closing, "USD"), nil)
+		require.NoError(t, err)
+		r.Status = ReconciliationStatusFinished
+		return r
+	}
+
+	statementDate := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		statementDate  time.Time
+		closingBalance string
+		currency       string
+		previous       *Reconciliation
+		wantErr        bool
+		errContains    string
+		wantOpening    string
+	}{
+		{
+			name:           "first reconciliation starts from zero",
+			statementDate:  statementDate,
+			closingBalance: "250.00",
+			currency:       "USD",
+			wantOpening:    "0.00 USD",
+		},
+		{
+			name:           "continues from previous closing balance",
+			statementDate:  statementDate,
+			closingBalance: "250.00",
+			currency:       "USD",
+			previous:       finished(account, statementDate.AddDate(0, -1, 0), "120.00"),
+			wantOpening:    "120.00 USD",
+		},
+		{
+			name:           "empty statement date",
+			closingBalance: "250.00",
+			currency:       "USD",
+			wantErr:        true,
+			errContains:    "statement date cannot be empty",
+		},
+		{
+			name:           "currency mismatch",
+			statementDate:  statementDate,
+			closingBalance: "250.00",
+			currency:       "EUR",
+			wantErr:        true,
+			errContains:    "currency mismatch",
+		},
+		{
+			name:           "previous reconciliation of another account",
+			statementDate:  statementDate,
+			closingBalance: "250.00",
+			currency:       "USD",
+			previous:       finished(other, statementDate.AddDate(0, -1, 0), "120.00"),
+			wantErr:        true,
+			errContains:    "belongs to another account",
+		},
+		{
+			name:           "statement date not after previous",
+			statementDate:  statementDate,
+			closingBalance: "250.00",
+			currency:       "USD",
+			previous:       finished(account, statementDate, "120.00"),
+			wantErr:        true,
+			errContains:    "must be after the last reconciled statement date 2024-03-31",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciliation, err := account.StartReconciliation(tt.statementDate, mustMoney(t, tt.closingBalance, tt.currency), tt.previous)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Nil(t, reconciliation)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, reconciliation.IsInProgress())
+			assert.True(t, reconciliation.AccountID.Equals(account.ID))
+			assert.Equal(t, tt.wantOpening, reconciliation.OpeningBalance.String())
+			assert.True(t, reconciliation.FinishedAt.IsNone())
+		})
+	}
+}
+
+func TestReconciliation_LoadTransactions(t *testing.T) {
+	reconciliation, account := createTestReconciliation(t, "0.00")
+
+	onStatementDay := reconciliationTransaction(t, account, "10.00", reconciliation.StatementDate.Add(23*time.Hour))
+	require.NoError(t, reconciliation.LoadTransactions([]*Transaction{onStatementDay}))
+	assert.Len(t, reconciliation.Transactions, 1)
+
+	afterStatement := reconciliationTransaction(t, account, "10.00", reconciliation.StatementCutoff())
+	err := reconciliation.LoadTransactions([]*Transaction{afterStatement})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dated after the statement date")
+
+	other := fundedAccount(account.LedgerID, AccountTypeSavings, "USD", "0")(t)
+	err = reconciliation.LoadTransactions([]*Transaction{reconciliationTransaction(t, other, "10.00", reconciliation.StatementDate)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "belongs to another account")
+}
+
+func TestReconciliation_Flow(t *testing.T) {
+	reconciliation, account := createTestReconciliation(t, "150.00")
+
+	salary := reconciliationTransaction(t, account, "200.00", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	rent := reconciliationTransaction(t, account, "-50.00", time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC))
+	pending := reconciliationTransaction(t, account, "-20.00", time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, reconciliation.LoadTransactions([]*Transaction{salary, rent, pending}))
+
+	difference, err := reconciliation.Difference()
+	require.NoError(t, err)
+	assert.Equal(t, "150.00 USD", difference.String())
+	assert.False(t, reconciliation.IsBalanced())
+
+	_, err = reconciliation.SetCleared(salary.ID, true)
+	require.NoError(t, err)
+
+	difference, err = reconciliation.Difference()
+	require.NoError(t, err)
+	assert.Equal(t, "-50.00 USD", difference.String())
+
+	_, err = reconciliation.Finish()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reconciliation is off by -50.00 USD")
+
+	cleared, err := reconciliation.SetCleared(rent.ID, true)
+	require.NoError(t, err)
+	assert.Same(t, rent, cleared)
+	assert.True(t, reconciliation.IsBalanced())
+
+	unknownID, err := NewTransactionID()
+	require.NoError(t, err)
+	_, err = reconciliation.SetCleared(unknownID, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not part of this reconciliation")
+
+	reconciled, err := reconciliation.Finish()
+	require.NoError(t, err)
+	assert.Equal(t, []*Transaction{salary, rent}, reconciled)
+	assert.True(t, reconciliation.IsFinished())
+	assert.True(t, reconciliation.FinishedAt.IsSome())
+
+	for _, tx := range reconciled {
+		assert.True(t, tx.Status.IsReconciled())
+		assert.True(t, tx.IsLocked())
+		assert.True(t, tx.ReconciliationID.Unwrap().Equals(reconciliation.ID))
+	}
+	assert.Equal(t, TransactionStatusUncleared, pending.Status)
+	assert.False(t, pending.IsLocked())
+
+	_, err = reconciliation.SetCleared(pending.ID, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not in progress")
+
+	_, err = reconciliation.Finish()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not in progress")
+}
+
+// Helper functions
+
+func createTestReconciliation(t *testing.T, closingBalance string) (*Reconciliation, *Account) {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	account := fundedAccount(ledgerID, AccountTypeChecking, "USD", "0")(t)
+
+	reconciliation, err := account.StartReconciliation(
+		time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), mustMoney(t, closingBalance, "USD"), nil)
+	require.NoError(t, err)
+
+	return reconciliation, account
+}
+
+func reconciliationTransaction(t *testing.T, account *Account, amount string, date time.Time) *Transaction {
+	t.Helper()
+
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	tx, err := NewTransaction(account.LedgerID, account.ID, itemID, mustMoney(t, amount, "USD"), "Test", date)
+	require.NoError(t, err)
+	return tx
+}
@@ -0,0 +1,374 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// Rule categorises the transactions of a ledger as they are created or imported: when a transaction meets
+// all of the rule's conditions, the rule's actions are applied to it
+type Rule struct {
+	ID         RuleID
+	LedgerID   ledgerEntity.LedgerID
+	Name       string
+	Priority   int  // Rules are evaluated lowest priority first
+	Enabled    bool // Disabled rules are kept but not applied
+	Conditions RuleConditions
+	Actions    RuleActions
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	pattern *regexp.Regexp // Compiled Conditions.DescriptionPattern
+}
+
+// RuleConditions are what a transaction must meet for a rule to apply. Conditions that are not set
+// match any transaction, but at least one must be set.
+type RuleConditions struct {
+	DescriptionContains string                       // Matched ignoring case
+	DescriptionPattern  string                       // Regular expression, e.g. (?i)^amzn mktp
+	MinAmount           optional.Option[money.Money] // Inclusive; amounts are signed, so outflows are negative
+	MaxAmount           optional.Option[money.Money] // Inclusive
+	AccountID           optional.Option[AccountID]
+	CounterpartyType    optional.Option[counterpartyEntity.CounterpartyType]
+	DayOfMonth          optional.Option[DayRange]
+}
+
+// RuleActions are what a rule changes on a matching transaction. At least one must be set.
+type RuleActions struct {
+	ItemID         optional.Option[budgetEntity.ItemID]
+	CounterpartyID optional.Option[counterpartyEntity.CounterpartyID]
+	Tag            string // Added to the transaction's tags
+	Note           string // Appended to the transaction's notes
+	// TransferAccountID marks the transaction as a transfer with another account of the ledger.
+	// ItemID must then be set to a transfer item.
+	TransferAccountID optional.Option[AccountID]
+}
+
+// RuleOutcome is what applying a ledger's rules did to a transaction
+type RuleOutcome struct {
+	Matched           []RuleID                   // Rules that matched, in the order they were applied
+	TransferAccountID optional.Option[AccountID] // Set when a rule marked the transaction as a transfer
+}
+
+// NewRule creates a new, enabled Rule
+func NewRule(
+	ledgerID ledgerEntity.LedgerID,
+	name string,
+	priority int,
+	conditions RuleConditions,
+	actions RuleActions,
+) (*Rule, error) {
+	if !ledgerID.IsValid() {
+		return nil, fmt.Errorf("ledger ID is invalid")
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("rule name cannot be empty")
+	}
+
+	pattern, err := validateRule(conditions, actions)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := NewRuleID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate rule ID: %w", err)
+	}
+
+	now := time.Now()
+
+	return &Rule{
+		ID:         id,
+		LedgerID:   ledgerID,
+		Name:       name,
+		Priority:   priority,
+		Enabled:    true,
+		Conditions: conditions,
+		Actions:    actions,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		pattern:    pattern,
+	}, nil
+}
+
+// NewRuleFromTransaction creates a rule that repeats a manual correction: transactions of the same account
+// whose description contains tx's description get tx's budget item and counterparty. For one side of a
+// transfer, transferAccountID is the account on the other side. A split transaction's item is not repeated,
+// as no single item describes it.
+func NewRuleFromTransaction(
+	tx *Transaction,
+	transferAccountID optional.Option[AccountID],
+	name string,
+	priority int,
+) (*Rule, error) {
+	conditions := RuleConditions{
+		DescriptionContains: tx.Description,
+		AccountID:           optional.Some(tx.AccountID),
+	}
+
+	actions := RuleActions{CounterpartyID: tx.CounterpartyID, TransferAccountID: transferAccountID}
+	if !tx.IsSplit() {
+		actions.ItemID = optional.Some(tx.ItemID)
+	}
+
+	return NewRule(tx.LedgerID, name, priority, conditions, actions)
+}
+
+// ReconstructRule reconstructs a Rule from stored data
+func ReconstructRule(
+	id RuleID,
+	ledgerID ledgerEntity.LedgerID,
+	name string,
+	priority int,
+	enabled bool,
+	conditions RuleConditions,
+	actions RuleActions,
+	createdAt, updatedAt time.Time,
+) *Rule {
+	return &Rule{
+		ID:         id,
+		LedgerID:   ledgerID,
+		Name:       name,
+		Priority:   priority,
+		Enabled:    enabled,
+		Conditions: conditions,
+		Actions:    actions,
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+	}
+}
+
+// Update replaces the rule's name, priority, conditions and actions
+func (r *Rule) Update(name string, priority int, conditions RuleConditions, actions RuleActions) error {
+	if name == "" {
+		return fmt.Errorf("rule name cannot be empty")
+	}
+
+	pattern, err := validateRule(conditions, actions)
+	if err != nil {
+		return err
+	}
+
+	r.Name = name
+	r.Priority = priority
+	r.Conditions = conditions
+	r.Actions = actions
+	r.pattern = pattern
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Enable makes the rule apply to new transactions
+func (r *Rule) Enable() {
+	r.Enabled = true
+	r.UpdatedAt = time.Now()
+}
+
+// Disable stops the rule applying to new transactions
+func (r *Rule) Disable() {
+	r.Enabled = false
+	r.UpdatedAt = time.Now()
+}
+
+// Matches checks if the transaction meets all of the rule's conditions.
+// counterpartyType is the type of the transaction's counterparty, if it has one.
+func (r *Rule) Matches(tx *Transaction, counterpartyType optional.Option[counterpartyEntity.CounterpartyType]) bool {
+	c := r.Conditions
+
+	if c.DescriptionContains != "" &&
+		!strings.Contains(strings.ToLower(tx.Description), strings.ToLower(c.DescriptionContains)) {
+		return false
+	}
+
+	if c.DescriptionPattern != "" {
+		pattern := r.descriptionPattern()
+		if pattern == nil || !pattern.MatchString(tx.Description) {
+			return false
+		}
+	}
+
+	if c.MinAmount.IsSome() {
+		if below, err := tx.Amount.LessThan(c.MinAmount.Unwrap()); err != nil || below {
+			return false
+		}
+	}
+
+	if c.MaxAmount.IsSome() {
+		if above, err := tx.Amount.GreaterThan(c.MaxAmount.Unwrap()); err != nil || above {
+			return false
+		}
+	}
+
+	if c.AccountID.IsSome() && !c.AccountID.Unwrap().Equals(tx.AccountID) {
+		return false
+	}
+
+	if c.CounterpartyType.IsSome() &&
+		(counterpartyType.IsNone() || counterpartyType.Unwrap() != c.CounterpartyType.Unwrap()) {
+		return false
+	}
+
+	if c.DayOfMonth.IsSome() && !c.DayOfMonth.Unwrap().Contains(tx.TransactionDate) {
+		return false
+	}
+
+	return true
+}
+
+// IsTransferRule checks if the rule marks matching transactions as transfers
+func (r *Rule) IsTransferRule() bool {
+	return r.Actions.TransferAccountID.IsSome()
+}
+
+// ApplyRules applies the rules that tx matches, lowest priority first. The budget item, counterparty and
+// transfer account come from the first matching rule that sets them, except that the first matching transfer
+// rule also sets its transfer item, so a transfer is never left with an ordinary item. Every matching rule adds
+// its tag and note. Counterparty type conditions see a counterparty set by an earlier rule. A transaction that
+// cannot become that transfer, such as a split, pending or foreign one, ignores the rule's transfer account and
+// item, so later rules still set its item. Callers pass the rules that are enabled.
+func ApplyRules(
+	tx *Transaction,
+	rules []*Rule,
+	counterparties []*counterpartyEntity.Counterparty,
+) (RuleOutcome, error) {
+	outcome := RuleOutcome{TransferAccountID: optional.None[AccountID]()}
+
+	types := make(map[string]counterpartyEntity.CounterpartyType, len(counterparties))
+	for _, counterparty := range counterparties {
+		types[counterparty.ID.String()] = counterparty.Type
+	}
+
+	ordered := slices.Clone(rules)
+	slices.SortStableFunc(ordered, func(a, b *Rule) int { return a.Priority - b.Priority })
+
+	var itemSet, counterpartySet bool
+	for _, rule := range ordered {
+		counterpartyType := optional.None[counterpartyEntity.CounterpartyType]()
+		if id, ok := tx.GetCounterparty(); ok {
+			if t, known := types[id.String()]; known {
+				counterpartyType = optional.Some(t)
+			}
+		}
+
+		if !rule.Matches(tx, counterpartyType) {
+			continue
+		}
+		outcome.Matched = append(outcome.Matched, rule.ID)
+
+		a := rule.Actions
+		transfer := a.TransferAccountID.IsSome()
+		if transfer && !canBecomeTransfer(tx, a.TransferAccountID.Unwrap()) {
+			// The rule's item is its transfer item, which an ordinary transaction must not take
+			a.ItemID = optional.None[budgetEntity.ItemID]()
+		} else if transfer && outcome.TransferAccountID.IsNone() {
+			// The transfer item replaces one set by an earlier rule: the transfer must be recorded with it
+			outcome.TransferAccountID = a.TransferAccountID
+			itemSet = false
+		}
+
+		if a.ItemID.IsSome() && !itemSet && !tx.IsSplit() {
+			itemSet = true
+			if !tx.ItemID.Equals(a.ItemID.Unwrap()) {
+				tx.UpdateItem(a.ItemID.Unwrap())
+			}
+		}
+
+		if a.CounterpartyID.IsSome() && !counterpartySet {
+			counterpartySet = true
+			if !tx.GetCounterpartyID().Equal(a.CounterpartyID, counterpartyEntity.CounterpartyID.Equals) {
+				tx.SetCounterparty(a.CounterpartyID.Unwrap())
+			}
+		}
+
+		if a.Tag != "" {
+			if err := tx.AddTag(a.Tag); err != nil {
+				return RuleOutcome{}, fmt.Errorf("failed to apply rule %s: %w", rule.Name, err)
+			}
+		}
+		tx.AppendNote(a.Note)
+	}
+
+	return outcome, nil
+}
+
+// canBecomeTransfer checks if tx can be recorded as a transfer to the other account. Transfers cannot be
+// split, pending or charged in a foreign currency, and a rule without an account condition can match the
+// destination side of its own transfer.
+func canBecomeTransfer(tx *Transaction, other AccountID) bool {
+	return !tx.IsSplit() && !tx.PostingStatus.IsPending() && !tx.IsForeign() && !tx.AccountID.Equals(other)
+}
+
+func (r *Rule) descriptionPattern() *regexp.Regexp {
+	if r.pattern == nil && r.Conditions.DescriptionPattern != "" {
+		// Stored patterns were validated when the rule was saved; one that no longer compiles matches nothing
+		r.pattern, _ = regexp.Compile(r.Conditions.DescriptionPattern)
+	}
+	return r.pattern
+}
+
+// validateRule checks the conditions and actions of a rule, returning its compiled description pattern
+func validateRule(conditions RuleConditions, actions RuleActions) (*regexp.Regexp, error) {
+	c := conditions
+	if c.DescriptionContains == "" && c.DescriptionPattern == "" && c.MinAmount.IsNone() && c.MaxAmount.IsNone() &&
+		c.AccountID.IsNone() && c.CounterpartyType.IsNone() && c.DayOfMonth.IsNone() {
+		return nil, fmt.Errorf("rule needs at least one condition")
+	}
+
+	var pattern *regexp.Regexp
+	if c.DescriptionPattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(c.DescriptionPattern); err != nil {
+			return nil, fmt.Errorf("invalid description pattern: %w", err)
+		}
+	}
+
+	if c.MinAmount.IsSome() && c.MaxAmount.IsSome() {
+		greater, err := c.MinAmount.Unwrap().GreaterThan(c.MaxAmount.Unwrap())
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount range: %w", err)
+		}
+		if greater {
+			return nil, fmt.Errorf("invalid amount range: minimum %s is above maximum %s",
+				c.MinAmount.Unwrap(), c.MaxAmount.Unwrap())
+		}
+	}
+
+	if c.CounterpartyType.IsSome() {
+		if _, err := counterpartyEntity.NewCounterpartyType(c.CounterpartyType.Unwrap().String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.DayOfMonth.IsSome() {
+		if err := c.DayOfMonth.Unwrap().Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	a := actions
+	if a.ItemID.IsNone() && a.CounterpartyID.IsNone() && strings.TrimSpace(a.Tag) == "" &&
+		strings.TrimSpace(a.Note) == "" && a.TransferAccountID.IsNone() {
+		return nil, fmt.Errorf("rule needs at least one action")
+	}
+
+	if a.TransferAccountID.IsSome() {
+		if a.ItemID.IsNone() {
+			return nil, fmt.Errorf("a rule marking transfers must set a transfer item")
+		}
+		if c.AccountID.IsSome() && c.AccountID.Unwrap().Equals(a.TransferAccountID.Unwrap()) {
+			return nil, fmt.Errorf("a rule cannot mark transfers with the account it matches")
+		}
+	}
+
+	return pattern, nil
+}
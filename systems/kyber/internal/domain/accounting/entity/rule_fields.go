@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/id"
+)
+
+// RuleID represents a unique identifier for a categorisation rule using UUIDv7
+type RuleID struct {
+	id.EntityID
+}
+
+// NewRuleID creates a new RuleID using UUIDv7
+func NewRuleID() (RuleID, error) {
+	base, err := id.NewEntityID()
+	if err != nil {
+		return RuleID{}, fmt.Errorf("failed to create rule ID: %w", err)
+	}
+	return RuleID{EntityID: base}, nil
+}
+
+// NewRuleIDFromString creates a RuleID from an existing string
+func NewRuleIDFromString(idStr string) (RuleID, error) {
+	base, err := id.NewEntityIDFromString(idStr)
+	if err != nil {
+		return RuleID{}, fmt.Errorf("failed to create rule ID: %w", err)
+	}
+	return RuleID{EntityID: base}, nil
+}
+
+// Equals checks if two RuleIDs are equal
+func (t RuleID) Equals(other RuleID) bool {
+	return t.EntityID.Equals(other.EntityID)
+}
+
+// DayRange is an inclusive range of days of the month. A range whose From is after its To wraps around
+// the end of the month, e.g. 28 to 3 for payments made around the 1st.
+type DayRange struct {
+	From int // 1 to 31
+	To   int // 1 to 31
+}
+
+// NewDayRange creates a new DayRange
+func NewDayRange(from, to int) (DayRange, error) {
+	r := DayRange{From: from, To: to}
+	if err := r.Validate(); err != nil {
+		return DayRange{}, err
+	}
+	return r, nil
+}
+
+// Validate checks that both days are days of a month
+func (r DayRange) Validate() error {
+	if r.From < 1 || r.From > 31 || r.To < 1 || r.To > 31 {
+		return fmt.Errorf("invalid day range %d-%d: days must be between 1 and 31", r.From, r.To)
+	}
+	return nil
+}
+
+// Contains checks if the date falls within the range. Days past the end of a short month stand for
+// its last day, so a range ending on the 31st includes 30 April and 28 February.
+func (r DayRange) Contains(date time.Time) bool {
+	last := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	from, to, day := min(r.From, last), min(r.To, last), date.Day()
+
+	if from <= to {
+		return day >= from && day <= to
+	}
+	return day >= from || day <= to
+}
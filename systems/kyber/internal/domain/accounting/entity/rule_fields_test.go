@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleID_NewRuleIDFromString(t *testing.T) {
+	validID, err := NewRuleID()
+	require.NoError(t, err)
+
+	parsed, err := NewRuleIDFromString(validID.String())
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(validID))
+
+	_, err = NewRuleIDFromString("invalid-uuid")
+	assert.Error(t, err)
+}
+
+func TestNewDayRange(t *testing.T) {
+	_, err := NewDayRange(1, 31)
+	require.NoError(t, err)
+
+	_, err = NewDayRange(0, 5)
+	require.Error(t, err)
+
+	_, err = NewDayRange(25, 32)
+	require.Error(t, err)
+}
+
+func TestDayRange_Contains(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        DayRange
+		date     time.Time
+		expected bool
+	}{
+		{name: "inside", r: DayRange{From: 10, To: 20}, date: utcDate(2024, 3, 15), expected: true},
+		{name: "first day", r: DayRange{From: 10, To: 20}, date: utcDate(2024, 3, 10), expected: true},
+		{name: "last day", r: DayRange{From: 10, To: 20}, date: utcDate(2024, 3, 20), expected: true},
+		{name: "outside", r: DayRange{From: 10, To: 20}, date: utcDate(2024, 3, 21), expected: false},
+		{name: "wrapping, end of month", r: DayRange{From: 28, To: 3}, date: utcDate(2024, 3, 30), expected: true},
+		{name: "wrapping, start of month", r: DayRange{From: 28, To: 3}, date: utcDate(2024, 4, 2), expected: true},
+		{name: "wrapping, outside", r: DayRange{From: 28, To: 3}, date: utcDate(2024, 4, 15), expected: false},
+		{name: "31st in a short month", r: DayRange{From: 31, To: 31}, date: utcDate(2024, 4, 30), expected: true},
+		{name: "31st in February", r: DayRange{From: 30, To: 31}, date: utcDate(2023, 2, 28), expected: true},
+		{name: "31st before month end", r: DayRange{From: 31, To: 31}, date: utcDate(2024, 4, 29), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.r.Contains(tt.date))
+		})
+	}
+}
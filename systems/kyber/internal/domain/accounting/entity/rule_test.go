@@ -0,0 +1,333 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+func TestNewRule(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+	accountID, err := NewAccountID()
+	require.NoError(t, err)
+
+	setItem := RuleActions{ItemID: optional.Some(itemID)}
+
+	tests := []struct {
+		name        string
+		ruleName    string
+		conditions  RuleConditions
+		actions     RuleActions
+		errContains string
+	}{
+		{
+			name:       "valid",
+			ruleName:   "Groceries",
+			conditions: RuleConditions{DescriptionContains: "whole foods"},
+			actions:    setItem,
+		},
+		{
+			name:        "empty name",
+			conditions:  RuleConditions{DescriptionContains: "whole foods"},
+			actions:     setItem,
+			errContains: "name cannot be empty",
+		},
+		{
+			name:        "no conditions",
+			ruleName:    "Everything",
+			actions:     setItem,
+			errContains: "at least one condition",
+		},
+		{
+			name:        "no actions",
+			ruleName:    "Nothing",
+			conditions:  RuleConditions{DescriptionContains: "whole foods"},
+			errContains: "at least one action",
+		},
+		{
+			name:        "invalid pattern",
+			ruleName:    "Broken",
+			conditions:  RuleConditions{DescriptionPattern: "(unclosed"},
+			actions:     setItem,
+			errContains: "invalid description pattern",
+		},
+		{
+			name:     "inverted amount range",
+			ruleName: "Range",
+			conditions: RuleConditions{
+				MinAmount: optional.Some(mustMoney(t, "10.00", "USD")),
+				MaxAmount: optional.Some(mustMoney(t, "-10.00", "USD")),
+			},
+			actions:     setItem,
+			errContains: "minimum 10.00 USD is above maximum",
+		},
+		{
+			name:     "amount range in two currencies",
+			ruleName: "Range",
+			conditions: RuleConditions{
+				MinAmount: optional.Some(mustMoney(t, "-10.00", "USD")),
+				MaxAmount: optional.Some(mustMoney(t, "10.00", "EUR")),
+			},
+			actions:     setItem,
+			errContains: "invalid amount range",
+		},
+		{
+			name:        "invalid counterparty type",
+			ruleName:    "Type",
+			conditions:  RuleConditions{CounterpartyType: optional.Some(counterpartyEntity.CounterpartyType("ALIEN"))},
+			actions:     setItem,
+			errContains: "invalid counterparty",
+		},
+		{
+			name:        "invalid day range",
+			ruleName:    "Days",
+			conditions:  RuleConditions{DayOfMonth: optional.Some(DayRange{From: 0, To: 40})},
+			actions:     setItem,
+			errContains: "invalid day range",
+		},
+		{
+			name:        "transfer without item",
+			ruleName:    "Transfer",
+			conditions:  RuleConditions{DescriptionContains: "savings"},
+			actions:     RuleActions{TransferAccountID: optional.Some(accountID)},
+			errContains: "must set a transfer item",
+		},
+		{
+			name:        "transfer with the matched account",
+			ruleName:    "Transfer",
+			conditions:  RuleConditions{AccountID: optional.Some(accountID)},
+			actions:     RuleActions{ItemID: optional.Some(itemID), TransferAccountID: optional.Some(accountID)},
+			errContains: "the account it matches",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := NewRule(ledgerID, tt.ruleName, 0, tt.conditions, tt.actions)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, rule.ID.IsValid())
+			assert.True(t, rule.Enabled)
+		})
+	}
+}
+
+func TestRule_Matches(t *testing.T) {
+	tx := createTestTransaction(t)
+	tx.Description = "AMZN Mktp US*2K3"
+	tx.Amount = mustMoney(t, "-25.00", "USD")
+	tx.TransactionDate = utcDate(2024, 3, 30)
+
+	otherAccountID, err := NewAccountID()
+	require.NoError(t, err)
+
+	business := optional.Some(counterpartyEntity.CounterpartyTypeBusiness)
+	none := optional.None[counterpartyEntity.CounterpartyType]()
+
+	tests := []struct {
+		name             string
+		conditions       RuleConditions
+		counterpartyType optional.Option[counterpartyEntity.CounterpartyType]
+		expected         bool
+	}{
+		{name: "contains ignoring case", conditions: RuleConditions{DescriptionContains: "amzn mktp"}, expected: true},
+		{name: "does not contain", conditions: RuleConditions{DescriptionContains: "ebay"}, expected: false},
+		{name: "pattern", conditions: RuleConditions{DescriptionPattern: `^AMZN Mktp US\*`}, expected: true},
+		{name: "pattern is case sensitive", conditions: RuleConditions{DescriptionPattern: `^amzn`}, expected: false},
+		{
+			name: "within amount range",
+			conditions: RuleConditions{
+				MinAmount: optional.Some(mustMoney(t, "-50.00", "USD")),
+				MaxAmount: optional.Some(mustMoney(t, "-25.00", "USD")),
+			},
+			expected: true,
+		},
+		{
+			name:       "below minimum",
+			conditions: RuleConditions{MinAmount: optional.Some(mustMoney(t, "-20.00", "USD"))},
+			expected:   false,
+		},
+		{
+			name:       "amount in another currency",
+			conditions: RuleConditions{MaxAmount: optional.Some(mustMoney(t, "0", "EUR"))},
+			expected:   false,
+		},
+		{name: "account", conditions: RuleConditions{AccountID: optional.Some(tx.AccountID)}, expected: true},
+		{name: "other account", conditions: RuleConditions{AccountID: optional.Some(otherAccountID)}, expected: false},
+		{
+			name:             "counterparty type",
+			conditions:       RuleConditions{CounterpartyType: business},
+			counterpartyType: business,
+			expected:         true,
+		},
+		{
+			name:             "no counterparty",
+			conditions:       RuleConditions{CounterpartyType: business},
+			counterpartyType: none,
+			expected:         false,
+		},
+		{
+			name:       "day of month",
+			conditions: RuleConditions{DayOfMonth: optional.Some(DayRange{From: 28, To: 2})},
+			expected:   true,
+		},
+		{
+			name: "all conditions must match",
+			conditions: RuleConditions{
+				DescriptionContains: "amzn",
+				DayOfMonth:          optional.Some(DayRange{From: 1, To: 5}),
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := ReconstructRule(RuleID{}, tx.LedgerID, "Rule", 0, true, tt.conditions, RuleActions{Tag: "x"},
+				tx.CreatedAt, tx.UpdatedAt)
+			assert.Equal(t, tt.expected, rule.Matches(tx, tt.counterpartyType))
+		})
+	}
+}
+
+func TestApplyRules(t *testing.T) {
+	tx := createTestTransaction(t)
+	tx.Description = "Whole Foods Market"
+
+	food, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+	household, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+	savingsID, err := NewAccountID()
+	require.NoError(t, err)
+
+	grocer, err := counterpartyEntity.NewCounterparty(tx.LedgerID, "Whole Foods", counterpartyEntity.CounterpartyTypeRetailer, "")
+	require.NoError(t, err)
+
+	newRule := func(name string, priority int, conditions RuleConditions, actions RuleActions) *Rule {
+		rule, err := NewRule(tx.LedgerID, name, priority, conditions, actions)
+		require.NoError(t, err)
+		return rule
+	}
+
+	// Given out of order: priority decides
+	retail := newRule("Retail", 20,
+		RuleConditions{CounterpartyType: optional.Some(counterpartyEntity.CounterpartyTypeRetailer)},
+		RuleActions{ItemID: optional.Some(household), Tag: "Shopping", Note: "Keep the receipt"})
+	grocery := newRule("Groceries", 10,
+		RuleConditions{DescriptionContains: "whole foods"},
+		RuleActions{ItemID: optional.Some(food), CounterpartyID: optional.Some(grocer.ID), Tag: "groceries"})
+	unrelated := newRule("Rent", 0,
+		RuleConditions{DescriptionContains: "rent"},
+		RuleActions{ItemID: optional.Some(household), TransferAccountID: optional.Some(savingsID)})
+
+	outcome, err := ApplyRules(tx, []*Rule{retail, unrelated, grocery}, []*counterpartyEntity.Counterparty{grocer})
+	require.NoError(t, err)
+
+	assert.Equal(t, []RuleID{grocery.ID, retail.ID}, outcome.Matched)
+	assert.True(t, outcome.TransferAccountID.IsNone())
+	assert.True(t, tx.ItemID.Equals(food), "the first matching rule's item wins")
+	assert.True(t, tx.CounterpartyID.Unwrap().Equals(grocer.ID))
+	assert.Equal(t, []string{"groceries", "shopping"}, tx.Tags)
+	assert.Equal(t, "Keep the receipt", tx.Notes)
+
+	// Applying again changes nothing more
+	_, err = ApplyRules(tx, []*Rule{retail, grocery}, []*counterpartyEntity.Counterparty{grocer})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"groceries", "shopping"}, tx.Tags)
+	assert.Equal(t, "Keep the receipt", tx.Notes)
+
+	tx.Description = "Rent March"
+	outcome, err = ApplyRules(tx, []*Rule{unrelated}, nil)
+	require.NoError(t, err)
+	assert.True(t, outcome.TransferAccountID.Unwrap().Equals(savingsID))
+
+	// A transfer rule's transfer item replaces the item of an earlier, ordinary rule
+	anyRent := newRule("Any rent", -10,
+		RuleConditions{DescriptionContains: "rent"},
+		RuleActions{ItemID: optional.Some(food)})
+	outcome, err = ApplyRules(tx, []*Rule{unrelated, anyRent}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []RuleID{anyRent.ID, unrelated.ID}, outcome.Matched)
+	assert.True(t, outcome.TransferAccountID.Unwrap().Equals(savingsID))
+	assert.True(t, tx.ItemID.Equals(household), "the transfer rule's item wins")
+
+	// A transaction that cannot become the transfer takes its item from the next matching rule
+	hold := createTestTransaction(t)
+	hold.Description = "Rent March"
+	require.NoError(t, hold.MarkPending())
+	anyRent.Priority = 10
+	outcome, err = ApplyRules(hold, []*Rule{unrelated, anyRent}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []RuleID{unrelated.ID, anyRent.ID}, outcome.Matched)
+	assert.True(t, outcome.TransferAccountID.IsNone(), "a pending transaction is not a transfer")
+	assert.True(t, hold.ItemID.Equals(food))
+
+	own := createTestTransaction(t)
+	own.Description = "Rent March"
+	toSelf := newRule("Rent to self", 0,
+		RuleConditions{DescriptionContains: "rent"},
+		RuleActions{ItemID: optional.Some(household), TransferAccountID: optional.Some(own.AccountID)})
+	outcome, err = ApplyRules(own, []*Rule{toSelf, anyRent}, nil)
+	require.NoError(t, err)
+	assert.True(t, outcome.TransferAccountID.IsNone(), "a transaction is not a transfer to its own account")
+	assert.True(t, own.ItemID.Equals(food))
+
+	// A split transaction keeps its split and is not marked as a transfer
+	require.NoError(t, tx.Split([]SplitLine{
+		{ItemID: food, Amount: mustMoney(t, "40.00", "USD")},
+		{ItemID: household, Amount: mustMoney(t, "60.00", "USD")},
+	}))
+	outcome, err = ApplyRules(tx, []*Rule{unrelated}, nil)
+	require.NoError(t, err)
+	assert.True(t, outcome.TransferAccountID.IsNone())
+	assert.True(t, tx.IsSplit())
+}
+
+func TestNewRuleFromTransaction(t *testing.T) {
+	tx := createTestTransaction(t)
+	counterpartyID, err := counterpartyEntity.NewCounterpartyID()
+	require.NoError(t, err)
+	tx.SetCounterparty(counterpartyID)
+
+	rule, err := NewRuleFromTransaction(tx, optional.None[AccountID](), "Test", 3)
+	require.NoError(t, err)
+	assert.Equal(t, "Test transaction", rule.Conditions.DescriptionContains)
+	assert.True(t, rule.Conditions.AccountID.Unwrap().Equals(tx.AccountID))
+	assert.True(t, rule.Actions.ItemID.Unwrap().Equals(tx.ItemID))
+	assert.True(t, rule.Actions.CounterpartyID.Unwrap().Equals(counterpartyID))
+	assert.Equal(t, 3, rule.Priority)
+	assert.True(t, rule.Matches(tx, optional.None[counterpartyEntity.CounterpartyType]()))
+}
+
+func TestRule_Update(t *testing.T) {
+	tx := createTestTransaction(t)
+	rule, err := NewRuleFromTransaction(tx, optional.None[AccountID](), "Test", 0)
+	require.NoError(t, err)
+
+	require.NoError(t, rule.Update("Renamed", 1, RuleConditions{DescriptionPattern: "^Test"}, RuleActions{Tag: "test"}))
+	assert.Equal(t, "Renamed", rule.Name)
+	assert.True(t, rule.Matches(tx, optional.None[counterpartyEntity.CounterpartyType]()))
+
+	require.Error(t, rule.Update("Renamed", 1, RuleConditions{DescriptionPattern: "["}, RuleActions{Tag: "test"}))
+	assert.Equal(t, "^Test", rule.Conditions.DescriptionPattern)
+
+	rule.Disable()
+	assert.False(t, rule.Enabled)
+	rule.Enable()
+	assert.True(t, rule.Enabled)
+}
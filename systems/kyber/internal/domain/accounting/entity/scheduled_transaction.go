@@ -0,0 +1,298 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/rrule"
+)
+
+// ScheduledTransaction is a template that generates a Transaction on each occurrence of its
+// recurrence rule, e.g. salary, rent or subscriptions. The rule's COUNT or UNTIL is the end condition.
+type ScheduledTransaction struct {
+	ID             ScheduledTransactionID
+	LedgerID       ledgerEntity.LedgerID
+	AccountID      AccountID
+	ItemID         budgetEntity.ItemID
+	CounterpartyID optional.Option[counterpartyEntity.CounterpartyID]
+	Amount         money.Money
+	Description    string
+	Notes          string
+	Rule           rrule.Rule
+	StartDate      time.Time                  // First possible occurrence; occurrences keep its time of day
+	Occurrences    map[string]*Occurrence     // Skipped, modified or generated occurrences, keyed by date
+	LastGenerated  optional.Option[time.Time] // Latest occurrence a transaction was generated for
+	IsActive       bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewScheduledTransaction creates a new ScheduledTransaction
+func NewScheduledTransaction(
+	ledgerID ledgerEntity.LedgerID,
+	accountID AccountID,
+	itemID budgetEntity.ItemID,
+	amount money.Money,
+	description string,
+	rule rrule.Rule,
+	startDate time.Time,
+) (*ScheduledTransaction, error) {
+	if !ledgerID.IsValid() {
+		return nil, fmt.Errorf("ledger ID is invalid")
+	}
+
+	if !accountID.IsValid() {
+		return nil, fmt.Errorf("account ID is invalid")
+	}
+
+	if !itemID.IsValid() {
+		return nil, fmt.Errorf("item ID is invalid")
+	}
+
+	if amount.IsZero() {
+		return nil, fmt.Errorf("scheduled amount cannot be zero")
+	}
+
+	if err := amount.ValidatePrecision(); err != nil {
+		return nil, fmt.Errorf("invalid scheduled amount: %w", err)
+	}
+
+	if description == "" {
+		return nil, fmt.Errorf("scheduled transaction description cannot be empty")
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	if startDate.IsZero() {
+		return nil, fmt.Errorf("start date cannot be empty")
+	}
+
+	id, err := NewScheduledTransactionID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate scheduled transaction ID: %w", err)
+	}
+
+	now := time.Now()
+
+	return &ScheduledTransaction{
+		ID:             id,
+		LedgerID:       ledgerID,
+		AccountID:      accountID,
+		ItemID:         itemID,
+		CounterpartyID: optional.None[counterpartyEntity.CounterpartyID](),
+		Amount:         amount,
+		Description:    description,
+		Rule:           rule,
+		StartDate:      startDate,
+		Occurrences:    make(map[string]*Occurrence),
+		LastGenerated:  optional.None[time.Time](),
+		IsActive:       true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// ReconstructScheduledTransaction reconstructs a ScheduledTransaction from stored data
+func ReconstructScheduledTransaction(
+	id ScheduledTransactionID,
+	ledgerID ledgerEntity.LedgerID,
+	accountID AccountID,
+	itemID budgetEntity.ItemID,
+	counterpartyID optional.Option[counterpartyEntity.CounterpartyID],
+	amount money.Money,
+	description, notes string,
+	rule rrule.Rule,
+	startDate time.Time,
+	occurrences []Occurrence,
+	lastGenerated optional.Option[time.Time],
+	isActive bool,
+	createdAt, updatedAt time.Time,
+) *ScheduledTransaction {
+	byDate := make(map[string]*Occurrence, len(occurrences))
+	for i := range occurrences {
+		byDate[occurrenceKey(occurrences[i].Date)] = &occurrences[i]
+	}
+
+	return &ScheduledTransaction{
+		ID:             id,
+		LedgerID:       ledgerID,
+		AccountID:      accountID,
+		ItemID:         itemID,
+		CounterpartyID: counterpartyID,
+		Amount:         amount,
+		Description:    description,
+		Notes:          notes,
+		Rule:           rule,
+		StartDate:      startDate,
+		Occurrences:    byDate,
+		LastGenerated:  lastGenerated,
+		IsActive:       isActive,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	}
+}
+
+// SetCounterparty sets who generated transactions are with
+func (s *ScheduledTransaction) SetCounterparty(counterpartyID counterpartyEntity.CounterpartyID) {
+	s.CounterpartyID = optional.Some(counterpartyID)
+	s.UpdatedAt = time.Now()
+}
+
+// Pause stops generating transactions until resumed
+func (s *ScheduledTransaction) Pause() {
+	s.IsActive = false
+	s.UpdatedAt = time.Now()
+}
+
+// Resume continues generating transactions
+func (s *ScheduledTransaction) Resume() {
+	s.IsActive = true
+	s.UpdatedAt = time.Now()
+}
+
+// Occurrence returns the occurrence on the given date with any change made to it
+func (s *ScheduledTransaction) Occurrence(date time.Time) (Occurrence, error) {
+	if !s.Rule.Includes(s.StartDate, date) {
+		return Occurrence{}, fmt.Errorf("%s is not an occurrence of scheduled transaction %s",
+			date.Format(time.DateOnly), s.Description)
+	}
+	return s.occurrence(date), nil
+}
+
+// SkipOccurrence prevents a single occurrence from generating a transaction
+func (s *ScheduledTransaction) SkipOccurrence(date time.Time) error {
+	o, err := s.pendingOccurrence(date)
+	if err != nil {
+		return err
+	}
+
+	o.Skipped = true
+	s.Occurrences[occurrenceKey(o.Date)] = &o
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// ModifyOccurrence changes the amount and description of a single occurrence
+func (s *ScheduledTransaction) ModifyOccurrence(date time.Time, amount money.Money, description string) error {
+	o, err := s.pendingOccurrence(date)
+	if err != nil {
+		return err
+	}
+
+	if amount.IsZero() {
+		return fmt.Errorf("scheduled amount cannot be zero")
+	}
+
+	if amount.Currency != s.Amount.Currency {
+		return fmt.Errorf("currency mismatch: schedule uses %s, occurrence uses %s", s.Amount.Currency, amount.Currency)
+	}
+
+	if err := amount.ValidatePrecision(); err != nil {
+		return fmt.Errorf("invalid scheduled amount: %w", err)
+	}
+
+	if description == "" {
+		description = s.Description
+	}
+
+	o.Amount = amount
+	o.Description = description
+	o.Skipped = false
+	o.Modified = true
+	s.Occurrences[occurrenceKey(o.Date)] = &o
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// Upcoming returns the occurrences within [from, to) that will still generate a transaction
+func (s *ScheduledTransaction) Upcoming(from, to time.Time) []Occurrence {
+	var upcoming []Occurrence
+	for _, date := range s.Rule.Between(s.StartDate, from, to) {
+		o := s.occurrence(date)
+		if !o.Skipped && !o.IsGenerated() {
+			upcoming = append(upcoming, o)
+		}
+	}
+	return upcoming
+}
+
+// Due returns the occurrences up to and including asOf whose transactions have not been generated yet
+func (s *ScheduledTransaction) Due(asOf time.Time) []Occurrence {
+	if !s.IsActive {
+		return nil
+	}
+
+	from := s.StartDate
+	if s.LastGenerated.IsSome() {
+		from = s.LastGenerated.Unwrap().Add(time.Nanosecond)
+	}
+
+	return s.Upcoming(from, asOf.Add(time.Nanosecond))
+}
+
+// Generate creates the transaction of a due occurrence and records it, so each occurrence generates at most once
+func (s *ScheduledTransaction) Generate(occurrence Occurrence) (*Transaction, error) {
+	if !s.IsActive {
+		return nil, fmt.Errorf("scheduled transaction %s is paused", s.Description)
+	}
+
+	o, err := s.pendingOccurrence(occurrence.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Skipped {
+		return nil, fmt.Errorf("occurrence %s was skipped", o.Date.Format(time.DateOnly))
+	}
+
+	tx, err := NewTransaction(s.LedgerID, s.AccountID, s.ItemID, o.Amount, o.Description, o.Date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction for %s: %w", o.Date.Format(time.DateOnly), err)
+	}
+	tx.CounterpartyID = s.CounterpartyID
+	tx.Notes = s.Notes
+
+	o.TransactionID = optional.Some(tx.ID)
+	s.Occurrences[occurrenceKey(o.Date)] = &o
+	if s.LastGenerated.IsNone() || o.Date.After(s.LastGenerated.Unwrap()) {
+		s.LastGenerated = optional.Some(o.Date)
+	}
+	s.UpdatedAt = time.Now()
+	return tx, nil
+}
+
+// occurrence returns the occurrence on a date produced by the rule
+func (s *ScheduledTransaction) occurrence(date time.Time) Occurrence {
+	if o, ok := s.Occurrences[occurrenceKey(date)]; ok {
+		return *o
+	}
+
+	return Occurrence{
+		ScheduleID:    s.ID,
+		Date:          date,
+		Amount:        s.Amount,
+		Description:   s.Description,
+		TransactionID: optional.None[TransactionID](),
+	}
+}
+
+// pendingOccurrence returns the occurrence on the date if its transaction has not been generated yet
+func (s *ScheduledTransaction) pendingOccurrence(date time.Time) (Occurrence, error) {
+	o, err := s.Occurrence(date)
+	if err != nil {
+		return Occurrence{}, err
+	}
+
+	if o.IsGenerated() {
+		return Occurrence{}, fmt.Errorf("occurrence %s has already been generated", o.Date.Format(time.DateOnly))
+	}
+
+	return o, nil
+}
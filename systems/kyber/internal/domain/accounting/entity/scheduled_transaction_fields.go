@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/id"
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// ScheduledTransactionID represents a unique identifier for a scheduled transaction using UUIDv7
+type ScheduledTransactionID struct {
+	id.EntityID
+}
+
+// NewScheduledTransactionID creates a new ScheduledTransactionID using UUIDv7
+func NewScheduledTransactionID() (ScheduledTransactionID, error) {
+	base, err := id.NewEntityID()
+	if err != nil {
+		return ScheduledTransactionID{}, fmt.Errorf("failed to create scheduled transaction ID: %w", err)
+	}
+	return ScheduledTransactionID{EntityID: base}, nil
+}
+
+// NewScheduledTransactionIDFromString creates a ScheduledTransactionID from an existing string
+func NewScheduledTransactionIDFromString(idStr string) (ScheduledTransactionID, error) {
+	base, err := id.NewEntityIDFromString(idStr)
+	if err != nil {
+		return ScheduledTransactionID{}, fmt.Errorf("failed to create scheduled transaction ID: %w", err)
+	}
+	return ScheduledTransactionID{EntityID: base}, nil
+}
+
+// Equals checks if two ScheduledTransactionIDs are equal
+func (t ScheduledTransactionID) Equals(other ScheduledTransactionID) bool {
+	return t.EntityID.Equals(other.EntityID)
+}
+
+// Occurrence is one due date of a ScheduledTransaction, with any change made to it
+type Occurrence struct {
+	ScheduleID    ScheduledTransactionID
+	Date          time.Time   // As produced by the recurrence rule
+	Amount        money.Money // The schedule's amount unless modified
+	Description   string      // The schedule's description unless modified
+	Skipped       bool
+	Modified      bool
+	TransactionID optional.Option[TransactionID] // Set once the transaction has been generated
+}
+
+// IsGenerated checks if the occurrence's transaction has been created
+func (o Occurrence) IsGenerated() bool {
+	return o.TransactionID.IsSome()
+}
+
+// occurrenceKey identifies an occurrence of a schedule by its date
+func occurrenceKey(date time.Time) string {
+	return date.Format(time.DateOnly)
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledTransactionID_NewScheduledTransactionID(t *testing.T) {
+	id, err := NewScheduledTransactionID()
+
+	require.NoError(t, err)
+	assert.True(t, id.IsValid())
+	assert.NotEmpty(t, id.String())
+}
+
+func TestScheduledTransactionID_NewScheduledTransactionIDFromString(t *testing.T) {
+	validID, err := NewScheduledTransactionID()
+	require.NoError(t, err)
+
+	parsed, err := NewScheduledTransactionIDFromString(validID.String())
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(validID))
+
+	_, err = NewScheduledTransactionIDFromString("invalid-uuid")
+	assert.Error(t, err)
+}
@@ -0,0 +1,178 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/rrule"
+)
+
+func TestNewScheduledTransaction(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	accountID, err := NewAccountID()
+	require.NoError(t, err)
+
+	itemID := mustItemID(t)
+	monthly, err := rrule.Parse("FREQ=MONTHLY")
+	require.NoError(t, err)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		amount      string
+		description string
+		rule        rrule.Rule
+		start       time.Time
+		wantErr     bool
+		errContains string
+	}{
+		{name: "valid", amount: "-1500.00", description: "Rent", rule: monthly, start: start},
+		{name: "zero amount", amount: "0", description: "Rent", rule: monthly, start: start,
+			wantErr: true, errContains: "cannot be zero"},
+		{name: "empty description", amount: "-1500.00", rule: monthly, start: start,
+			wantErr: true, errContains: "description cannot be empty"},
+		{name: "invalid rule", amount: "-1500.00", description: "Rent", rule: rrule.Rule{}, start: start,
+			wantErr: true, errContains: "invalid recurrence rule"},
+		{name: "no start date", amount: "-1500.00", description: "Rent", rule: monthly,
+			wantErr: true, errContains: "start date cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := NewScheduledTransaction(
+				ledgerID, accountID, itemID, mustMoney(t, tt.amount, "USD"), tt.description, tt.rule, tt.start)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, schedule.IsActive)
+			assert.True(t, schedule.LastGenerated.IsNone())
+			assert.Empty(t, schedule.Occurrences)
+		})
+	}
+}
+
+func TestScheduledTransaction_SkipAndModifyOccurrence(t *testing.T) {
+	schedule := createTestSchedule(t, "FREQ=MONTHLY;COUNT=4")
+	feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, schedule.SkipOccurrence(feb))
+	require.NoError(t, schedule.ModifyOccurrence(mar, mustMoney(t, "-1600.00", "USD"), ""))
+
+	err := schedule.SkipOccurrence(time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not an occurrence")
+
+	err = schedule.ModifyOccurrence(mar, mustMoney(t, "-1600.00", "EUR"), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "currency mismatch")
+
+	upcoming := schedule.Upcoming(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.Len(t, upcoming, 3)
+	assert.Equal(t, "2024-01-01", upcoming[0].Date.Format(time.DateOnly))
+	assert.Equal(t, "2024-03-01", upcoming[1].Date.Format(time.DateOnly))
+	assert.True(t, upcoming[1].Modified)
+	assert.Equal(t, "-1600.00 USD", upcoming[1].Amount.String())
+	assert.Equal(t, "Rent", upcoming[1].Description)
+	assert.Equal(t, "2024-04-01", upcoming[2].Date.Format(time.DateOnly))
+}
+
+func TestScheduledTransaction_Generate(t *testing.T) {
+	schedule := createTestSchedule(t, "FREQ=MONTHLY")
+	require.NoError(t, schedule.SkipOccurrence(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)))
+
+	due := schedule.Due(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.Len(t, due, 2)
+
+	for _, occurrence := range due {
+		tx, err := schedule.Generate(occurrence)
+		require.NoError(t, err)
+		assert.True(t, tx.AccountID.Equals(schedule.AccountID))
+		assert.True(t, tx.ItemID.Equals(schedule.ItemID))
+		assert.Equal(t, "-1500.00 USD", tx.Amount.String())
+		assert.True(t, tx.TransactionDate.Equal(occurrence.Date))
+	}
+
+	// Each occurrence generates at most once
+	assert.Empty(t, schedule.Due(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+	_, err := schedule.Generate(due[0])
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already been generated")
+
+	err = schedule.SkipOccurrence(due[1].Date)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already been generated")
+
+	assert.Equal(t, "2024-03-01", schedule.LastGenerated.Unwrap().Format(time.DateOnly))
+	assert.Len(t, schedule.Due(time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)), 1)
+
+	schedule.Pause()
+	assert.Empty(t, schedule.Due(time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestAccount_ApplyTransaction(t *testing.T) {
+	account := createTestAccount(t)
+	account.Balance = mustMoney(t, "100.00", "USD")
+
+	tx, err := NewTransaction(account.LedgerID, account.ID, mustItemID(t), mustMoney(t, "-40.00", "USD"), "Groceries", time.Now())
+	require.NoError(t, err)
+	require.NoError(t, account.ApplyTransaction(tx))
+	assert.Equal(t, "60.00 USD", account.Balance.String())
+
+	tx, err = NewTransaction(account.LedgerID, account.ID, mustItemID(t), mustMoney(t, "25.00", "USD"), "Refund", time.Now())
+	require.NoError(t, err)
+	require.NoError(t, account.ApplyTransaction(tx))
+	assert.Equal(t, "85.00 USD", account.Balance.String())
+
+	other := createTestTransaction(t)
+	err = account.ApplyTransaction(other)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "belongs to another account")
+}
+
+func TestAccount_ApplyStatementTransaction(t *testing.T) {
+	account := createTestAccount(t)
+	account.Balance = mustMoney(t, "10.00", "USD")
+
+	tx, err := NewTransaction(account.LedgerID, account.ID, mustItemID(t), mustMoney(t, "-40.00", "USD"), "Groceries", time.Now())
+	require.NoError(t, err)
+	require.Error(t, account.ApplyTransaction(tx), "a manual entry cannot overdraw the account")
+	require.NoError(t, account.ApplyStatementTransaction(tx))
+	assert.Equal(t, "-30.00 USD", account.Balance.String())
+
+	other := createTestTransaction(t)
+	err = account.ApplyStatementTransaction(other)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "belongs to another account")
+}
+
+// Helper functions
+
+func createTestSchedule(t *testing.T, rule string) *ScheduledTransaction {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	accountID, err := NewAccountID()
+	require.NoError(t, err)
+
+	parsed, err := rrule.Parse(rule)
+	require.NoError(t, err)
+
+	schedule, err := NewScheduledTransaction(ledgerID, accountID, mustItemID(t), mustMoney(t, "-1500.00", "USD"),
+		"Rent", parsed, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	return schedule
+}
@@ -0,0 +1,281 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// importedDescription is used for statement lines that have neither a payee nor a memo
+const importedDescription = "Imported transaction"
+
+// DefaultEntryMatchWindow is how far apart a statement line and a transaction entered by hand may be
+// dated and still be the same one: banks often book a spend a few days after it was made
+const DefaultEntryMatchWindow = 4 * 24 * time.Hour
+
+// StatementLine is a transaction read from a bank statement file, whatever its format
+type StatementLine struct {
+	ImportID            string      // Bank-assigned ID (e.g. OFX FITID), unique per account
+	Date                time.Time   // Booking date: when the transaction changed the account balance
+	ValueDate           time.Time   // When interest starts or stops accruing, if the file has it
+	Amount              money.Money // Positive for inflows, negative for outflows
+	Payee               string
+	Memo                string
+	CounterpartyAccount string // e.g. the payee's IBAN, if the file has it
+}
+
+// RejectedLine is a line of a statement file that could not be read
+type RejectedLine struct {
+	Line   int // 1-based line number in the file
+	Reason string
+}
+
+// Statement is a bank statement file read for import into an account
+type Statement struct {
+	Lines              []StatementLine
+	Rejected           []RejectedLine
+	Balance            optional.Option[money.Money] // Closing balance stated by the file, if any
+	BalanceDate        time.Time
+	OpeningBalance     optional.Option[money.Money] // Balance before the first line, if the file states it
+	OpeningBalanceDate time.Time
+}
+
+// ImportPreview is what importing a statement would record. Nothing is stored until it is committed;
+// drafts may be edited (e.g. their budget item) or removed before then.
+type ImportPreview struct {
+	LedgerID             ledgerEntity.LedgerID
+	AccountID            AccountID
+	Drafts               []*Transaction  // New transactions to record
+	Duplicates           []StatementLine // Lines skipped because they were already imported
+	Rejected             []RejectedLine  // Lines of the file that could not be read
+	AccountBalance       money.Money     // Account balance before the import
+	StatementBalance     optional.Option[money.Money]
+	StatementBalanceDate time.Time
+	StatementOpening     optional.Option[money.Money]
+	// RecordedOpening is the account's recorded balance before the statement's first line, for comparison
+	// with StatementOpening. It is set by whoever has the account history.
+	RecordedOpening optional.Option[money.Money]
+	// TransferAccounts holds, by import ID, the drafts to record as transfers with another account
+	// rather than as ordinary transactions, e.g. because a rule marked them
+	TransferAccounts map[string]AccountID
+}
+
+// PreviewImport turns the statement lines into draft transactions assigned to the budget item.
+// Lines whose import ID is in imported, or repeated within the statement, are reported as duplicates.
+// Payees are matched to active counterparties by name.
+func (a *Account) PreviewImport(
+	statement Statement,
+	itemID budgetEntity.ItemID,
+	imported map[string]bool,
+	counterparties []*counterpartyEntity.Counterparty,
+) (*ImportPreview, error) {
+	if !a.Status.IsActive() {
+		return nil, fmt.Errorf("cannot import into inactive account %s", a.Name)
+	}
+
+	preview := &ImportPreview{
+		LedgerID:             a.LedgerID,
+		AccountID:            a.ID,
+		AccountBalance:       a.Balance,
+		StatementBalance:     statement.Balance,
+		StatementBalanceDate: statement.BalanceDate,
+		StatementOpening:     statement.OpeningBalance,
+		RecordedOpening:      optional.None[money.Money](),
+		Rejected:             statement.Rejected,
+		TransferAccounts:     make(map[string]AccountID),
+	}
+
+	for _, balance := range []optional.Option[money.Money]{statement.Balance, statement.OpeningBalance} {
+		if balance.IsSome() && balance.Unwrap().Currency != a.Currency {
+			return nil, fmt.Errorf("currency mismatch: account %s uses %s, statement uses %s",
+				a.Name, a.Currency, balance.Unwrap().Currency)
+		}
+	}
+
+	seen := make(map[string]bool, len(statement.Lines))
+	for _, line := range statement.Lines {
+		if line.ImportID == "" {
+			return nil, fmt.Errorf("statement line %q of %s has no import ID", line.Payee, line.Date.Format(time.DateOnly))
+		}
+
+		if line.Amount.Currency != a.Currency {
+			return nil, fmt.Errorf("currency mismatch: account %s uses %s, statement line %s uses %s",
+				a.Name, a.Currency, line.ImportID, line.Amount.Currency)
+		}
+
+		if imported[line.ImportID] || seen[line.ImportID] {
+			preview.Duplicates = append(preview.Duplicates, line)
+			continue
+		}
+		seen[line.ImportID] = true
+
+		if line.Amount.IsZero() {
+			continue
+		}
+
+		description, notes := line.Payee, line.Memo
+		switch {
+		case description == "" && notes != "":
+			description, notes = notes, ""
+		case description == "":
+			description = importedDescription
+		}
+
+		tx, err := NewTransaction(a.LedgerID, a.ID, itemID, line.Amount, description, line.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid statement line %s: %w", line.ImportID, err)
+		}
+		if line.CounterpartyAccount != "" {
+			notes = strings.TrimSpace(notes + "\nAccount: " + line.CounterpartyAccount)
+		}
+		tx.Notes = notes
+		tx.ImportID = line.ImportID
+
+		for _, counterparty := range counterparties {
+			if counterparty.Status.IsActive() && counterparty.MatchesName(line.Payee) {
+				tx.SetCounterparty(counterparty.ID)
+				break
+			}
+		}
+
+		preview.Drafts = append(preview.Drafts, tx)
+	}
+
+	return preview, nil
+}
+
+// ProjectedBalance returns the account balance once the drafts are recorded
+func (p *ImportPreview) ProjectedBalance() (money.Money, error) {
+	balance := p.AccountBalance
+	for _, tx := range p.Drafts {
+		var err error
+		if balance, err = balance.Add(tx.Amount); err != nil {
+			return money.Money{}, fmt.Errorf("failed to project balance: %w", err)
+		}
+	}
+	return balance, nil
+}
+
+// BalanceDifference returns the statement balance minus the projected balance, if the statement has a balance.
+// A non-zero difference means the account and the bank disagree, e.g. because of missing transactions.
+func (p *ImportPreview) BalanceDifference() (optional.Option[money.Money], error) {
+	if p.StatementBalance.IsNone() {
+		return optional.None[money.Money](), nil
+	}
+
+	projected, err := p.ProjectedBalance()
+	if err != nil {
+		return optional.None[money.Money](), err
+	}
+
+	difference, err := p.StatementBalance.Unwrap().Subtract(projected)
+	if err != nil {
+		return optional.None[money.Money](), fmt.Errorf("failed to compare balances: %w", err)
+	}
+	return optional.Some(difference), nil
+}
+
+// OpeningDifference returns the statement's opening balance minus the recorded balance before its first line,
+// if both are known. A non-zero difference means transactions before the statement are missing or wrong.
+func (p *ImportPreview) OpeningDifference() (optional.Option[money.Money], error) {
+	if p.StatementOpening.IsNone() || p.RecordedOpening.IsNone() {
+		return optional.None[money.Money](), nil
+	}
+
+	difference, err := p.StatementOpening.Unwrap().Subtract(p.RecordedOpening.Unwrap())
+	if err != nil {
+		return optional.None[money.Money](), fmt.Errorf("failed to compare opening balances: %w", err)
+	}
+	return optional.Some(difference), nil
+}
+
+// FirstDate returns the earliest date of the statement's lines, if it has any
+func (s Statement) FirstDate() optional.Option[time.Time] {
+	first := optional.None[time.Time]()
+	for _, line := range s.Lines {
+		if first.IsNone() || line.Date.Before(first.Unwrap()) {
+			first = optional.Some(line.Date)
+		}
+	}
+	return first
+}
+
+// MatchEntry finds the transaction entered by hand that a statement draft records: a posted transaction
+// of the draft's account without an import ID, of exactly the draft's amount and dated within window of it.
+// Of several, the one closest in date wins, and the oldest of those equally close.
+func MatchEntry(entries []*Transaction, draft *Transaction, window time.Duration) optional.Option[*Transaction] {
+	var (
+		best     *Transaction
+		bestDiff time.Duration
+	)
+	for _, entry := range entries {
+		if !entry.PostingStatus.IsPosted() || entry.ImportID != "" || !entry.AccountID.Equals(draft.AccountID) ||
+			!entry.Amount.Equals(draft.Amount) {
+			continue
+		}
+
+		diff := entry.TransactionDate.Sub(draft.TransactionDate).Abs()
+		if diff > window {
+			continue
+		}
+
+		if best == nil || diff < bestDiff ||
+			diff == bestDiff && entry.TransactionDate.Before(best.TransactionDate) {
+			best, bestDiff = entry, diff
+		}
+	}
+
+	if best == nil {
+		return optional.None[*Transaction]()
+	}
+	return optional.Some(best)
+}
+
+// ClaimImport records that a transaction entered by hand is the one a statement line with importID
+// records, so importing the line again finds it as a duplicate
+func (t *Transaction) ClaimImport(importID string) error {
+	if importID == "" {
+		return fmt.Errorf("import ID cannot be empty")
+	}
+
+	if t.ImportID != "" {
+		return fmt.Errorf("transaction is already imported as %s", t.ImportID)
+	}
+
+	t.ImportID = importID
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// AssignMissingImportIDs identifies lines without a bank-assigned ID by their content, prefixed with prefix.
+// The Nth identical line of a file gets the same ID on every import, so re-importing the file does not
+// duplicate it.
+func (s *Statement) AssignMissingImportIDs(prefix string) {
+	occurrences := make(map[string]int)
+	for i, line := range s.Lines {
+		if line.ImportID != "" {
+			continue
+		}
+
+		key := strings.Join([]string{
+			line.Date.Format(time.DateOnly), line.Amount.String(), line.Payee, line.Memo,
+		}, "\x1f")
+		occurrences[key]++
+
+		sum := sha256.Sum256(fmt.Appendf(nil, "%s\x1f%d", key, occurrences[key]))
+		s.Lines[i].ImportID = prefix + hex.EncodeToString(sum[:16])
+	}
+}
+
+// Error describes the rejected line
+func (r RejectedLine) Error() string {
+	return fmt.Sprintf("line %d: %s", r.Line, r.Reason)
+}
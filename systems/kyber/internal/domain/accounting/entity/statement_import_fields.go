@@ -0,0 +1,28 @@
+package entity
+
+import "fmt"
+
+// StatementFormat is the file format of a bank statement that describes itself, i.e. needs no CSVProfile
+type StatementFormat string
+
+// Statement format constants
+const (
+	StatementFormatOFX     StatementFormat = "OFX"     // OFX 1.x/2.x, including QFX
+	StatementFormatCAMT053 StatementFormat = "CAMT053" // ISO 20022 camt.053 XML
+	StatementFormatMT940   StatementFormat = "MT940"   // SWIFT MT940
+)
+
+// NewStatementFormat creates a new StatementFormat from string
+func NewStatementFormat(format string) (StatementFormat, error) {
+	switch StatementFormat(format) {
+	case StatementFormatOFX, StatementFormatCAMT053, StatementFormatMT940:
+		return StatementFormat(format), nil
+	default:
+		return "", fmt.Errorf("invalid statement format: %s", format)
+	}
+}
+
+// String returns the string representation of StatementFormat
+func (f StatementFormat) String() string {
+	return string(f)
+}
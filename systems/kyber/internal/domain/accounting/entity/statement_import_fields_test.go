@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStatementFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    StatementFormat
+		wantErr bool
+	}{
+		{input: "OFX", want: StatementFormatOFX},
+		{input: "CAMT053", want: StatementFormatCAMT053},
+		{input: "MT940", want: StatementFormatMT940},
+		{input: "CSV", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NewStatementFormat(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.input, got.String())
+		})
+	}
+}
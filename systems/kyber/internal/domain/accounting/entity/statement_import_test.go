@@ -0,0 +1,156 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestAccount_PreviewImport(t *testing.T) {
+	account := createTestAccount(t)
+	account.Balance = mustMoney(t, "100.00", "USD")
+	itemID := mustItemID(t)
+
+	grocer, err := counterpartyEntity.NewCounterparty(account.LedgerID, "Whole Foods", counterpartyEntity.CounterpartyTypeBusiness, "")
+	require.NoError(t, err)
+
+	date := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	statement := Statement{
+		Lines: []StatementLine{
+			{ImportID: "1", Date: date, Amount: mustMoney(t, "-42.50", "USD"), Payee: "WHOLE  FOODS", Memo: "Groceries"},
+			{ImportID: "2", Date: date, Amount: mustMoney(t, "-10.00", "USD"), Payee: "Already imported"},
+			{ImportID: "3", Date: date, Amount: mustMoney(t, "2500.00", "USD"), Memo: "Salary"},
+			{ImportID: "3", Date: date, Amount: mustMoney(t, "2500.00", "USD"), Memo: "Salary"},
+		},
+		Balance:     optional.Some(mustMoney(t, "2547.50", "USD")),
+		BalanceDate: date,
+	}
+
+	preview, err := account.PreviewImport(statement, itemID, map[string]bool{"2": true},
+		[]*counterpartyEntity.Counterparty{grocer})
+	require.NoError(t, err)
+
+	require.Len(t, preview.Drafts, 2)
+	assert.Equal(t, "WHOLE  FOODS", preview.Drafts[0].Description)
+	assert.Equal(t, "Groceries", preview.Drafts[0].Notes)
+	assert.Equal(t, "1", preview.Drafts[0].ImportID)
+	assert.True(t, preview.Drafts[0].CounterpartyID.Unwrap().Equals(grocer.ID))
+	assert.Equal(t, "Salary", preview.Drafts[1].Description)
+	assert.True(t, preview.Drafts[1].CounterpartyID.IsNone())
+	assert.True(t, preview.Drafts[1].ItemID.Equals(itemID))
+
+	require.Len(t, preview.Duplicates, 2)
+	assert.Equal(t, "2", preview.Duplicates[0].ImportID)
+	assert.Equal(t, "3", preview.Duplicates[1].ImportID)
+
+	projected, err := preview.ProjectedBalance()
+	require.NoError(t, err)
+	assert.Equal(t, "2557.50 USD", projected.String())
+
+	difference, err := preview.BalanceDifference()
+	require.NoError(t, err)
+	assert.Equal(t, "-10.00 USD", difference.Unwrap().String())
+}
+
+func TestAccount_PreviewImport_Invalid(t *testing.T) {
+	date := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		line        StatementLine
+		archived    bool
+		errContains string
+	}{
+		{
+			name:        "missing import ID",
+			line:        StatementLine{Date: date, Amount: mustMoney(t, "-1.00", "USD"), Payee: "Shop"},
+			errContains: "has no import ID",
+		},
+		{
+			name:        "currency mismatch",
+			line:        StatementLine{ImportID: "1", Date: date, Amount: mustMoney(t, "-1.00", "EUR"), Payee: "Shop"},
+			errContains: "currency mismatch",
+		},
+		{
+			name:        "archived account",
+			line:        StatementLine{ImportID: "1", Date: date, Amount: mustMoney(t, "-1.00", "USD"), Payee: "Shop"},
+			archived:    true,
+			errContains: "inactive account",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := createTestAccount(t)
+			if tt.archived {
+				account.Archive()
+			}
+
+			_, err := account.PreviewImport(Statement{Lines: []StatementLine{tt.line}, Balance: optional.None[money.Money]()},
+				mustItemID(t), nil, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestMatchEntry(t *testing.T) {
+	base := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	template := createTestTransaction(t)
+
+	newTransaction := func(t *testing.T, amount string, date time.Time) *Transaction {
+		tx, err := NewTransaction(template.LedgerID, template.AccountID, template.ItemID,
+			mustMoney(t, amount, "USD"), "Coffee", date)
+		require.NoError(t, err)
+		return tx
+	}
+
+	older := newTransaction(t, "-50.00", base.AddDate(0, 0, -1))
+	newer := newTransaction(t, "-50.00", base.AddDate(0, 0, 1))
+	closer := newTransaction(t, "-50.00", base)
+	imported := newTransaction(t, "-50.00", base)
+	require.NoError(t, imported.ClaimImport("FITID-1"))
+	pending := newTransaction(t, "-50.00", base)
+	require.NoError(t, pending.MarkPending())
+	otherAccount := newTransaction(t, "-50.00", base)
+	otherAccount.AccountID = createTestAccount(t).ID
+
+	match := MatchEntry([]*Transaction{newer, older, closer, imported, pending, otherAccount},
+		newTransaction(t, "-50.00", base), DefaultEntryMatchWindow)
+	require.True(t, match.IsSome())
+	assert.Same(t, closer, match.Unwrap(), "closest date wins")
+
+	match = MatchEntry([]*Transaction{newer, older}, newTransaction(t, "-50.00", base), DefaultEntryMatchWindow)
+	require.True(t, match.IsSome())
+	assert.Same(t, older, match.Unwrap(), "oldest of equally close entries wins")
+
+	match = MatchEntry([]*Transaction{older}, newTransaction(t, "-50.01", base), DefaultEntryMatchWindow)
+	assert.True(t, match.IsNone(), "amounts must be equal")
+
+	match = MatchEntry([]*Transaction{older}, newTransaction(t, "-50.00", base.AddDate(0, 0, 10)), DefaultEntryMatchWindow)
+	assert.True(t, match.IsNone(), "outside the window")
+
+	match = MatchEntry([]*Transaction{imported, pending, otherAccount}, newTransaction(t, "-50.00", base), DefaultEntryMatchWindow)
+	assert.True(t, match.IsNone(), "imported, pending and other accounts' transactions are not entries")
+}
+
+func TestTransaction_ClaimImport(t *testing.T) {
+	tx := createTestTransaction(t)
+
+	require.NoError(t, tx.ClaimImport("csv:abc"))
+	assert.Equal(t, "csv:abc", tx.ImportID)
+
+	err := tx.ClaimImport("csv:def")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already imported as csv:abc")
+
+	err = createTestTransaction(t).ClaimImport("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "import ID cannot be empty")
+}
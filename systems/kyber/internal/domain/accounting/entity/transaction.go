@@ -2,6 +2,8 @@ package entity
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/kneadCODE/coruscant/shared/golib/optional"
@@ -13,17 +15,27 @@ import (
 
 // Transaction represents a financial transaction within a ledger
 type Transaction struct {
-	ID              TransactionID
-	LedgerID        ledgerEntity.LedgerID
-	AccountID       AccountID
-	ItemID          budgetEntity.ItemID
-	CounterpartyID  optional.Option[counterpartyEntity.CounterpartyID] // Optional - who the transaction is with
-	Amount          money.Money
-	Description     string
-	Notes           string
-	TransactionDate time.Time // When the transaction actually occurred
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	ID               TransactionID
+	LedgerID         ledgerEntity.LedgerID
+	AccountID        AccountID
+	ItemID           budgetEntity.ItemID                                // First split line's item when the transaction is split
+	CounterpartyID   optional.Option[counterpartyEntity.CounterpartyID] // Optional - who the transaction is with
+	TransferID       optional.Option[TransferID]                        // Set when the transaction is one side of a Transfer
+	Amount           money.Money                                        // Settled amount in the account's currency, FX fee included
+	Foreign          optional.Option[ForeignAmount]                     // Set when the transaction was charged in another currency
+	Splits           []SplitLine                                        // Set when the amount is split across budget items; lines sum to Amount
+	Description      string
+	Notes            string
+	TransactionDate  time.Time // When the transaction actually occurred
+	Status           TransactionStatus
+	PostingStatus    PostingStatus
+	HoldAmount       optional.Option[money.Money]      // Set when the transaction started as a pending hold: the amount authorised
+	ReconciliationID optional.Option[ReconciliationID] // Set once the transaction has been reconciled
+	Locked           bool                              // Reconciled transactions are locked: amount and date cannot change until unlocked
+	ImportID         string                            // Bank-assigned ID (e.g. OFX FITID) of an imported transaction, unique per account
+	Tags             []string                          // Free-form labels, lower case and unique
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 // NewTransaction creates a new Transaction
@@ -51,6 +63,10 @@ func NewTransaction(
 		return nil, fmt.Errorf("transaction amount cannot be zero")
 	}
 
+	if err := amount.ValidatePrecision(); err != nil {
+		return nil, fmt.Errorf("invalid transaction amount: %w", err)
+	}
+
 	if description == "" {
 		return nil, fmt.Errorf("transaction description cannot be empty")
 	}
@@ -67,16 +83,22 @@ func NewTransaction(
 	now := time.Now()
 
 	return &Transaction{
-		ID:              id,
-		LedgerID:        ledgerID,
-		AccountID:       accountID,
-		ItemID:          itemID,
-		CounterpartyID:  optional.None[counterpartyEntity.CounterpartyID](),
-		Amount:          amount,
-		Description:     description,
-		TransactionDate: transactionDate,
-		CreatedAt:       now,
-		UpdatedAt:       now,
+		ID:               id,
+		LedgerID:         ledgerID,
+		AccountID:        accountID,
+		ItemID:           itemID,
+		CounterpartyID:   optional.None[counterpartyEntity.CounterpartyID](),
+		TransferID:       optional.None[TransferID](),
+		Amount:           amount,
+		Foreign:          optional.None[ForeignAmount](),
+		Description:      description,
+		TransactionDate:  transactionDate,
+		Status:           TransactionStatusUncleared,
+		PostingStatus:    PostingStatusPosted,
+		HoldAmount:       optional.None[money.Money](),
+		ReconciliationID: optional.None[ReconciliationID](),
+		CreatedAt:        now,
+		UpdatedAt:        now,
 	}, nil
 }
 
@@ -110,17 +132,23 @@ func ReconstructTransaction(
 	transactionDate, createdAt, updatedAt time.Time,
 ) *Transaction {
 	return &Transaction{
-		ID:              id,
-		LedgerID:        ledgerID,
-		AccountID:       accountID,
-		ItemID:          itemID,
-		CounterpartyID:  counterpartyID,
-		Amount:          amount,
-		Description:     description,
-		Notes:           notes,
-		TransactionDate: transactionDate,
-		CreatedAt:       createdAt,
-		UpdatedAt:       updatedAt,
+		ID:               id,
+		LedgerID:         ledgerID,
+		AccountID:        accountID,
+		ItemID:           itemID,
+		CounterpartyID:   counterpartyID,
+		TransferID:       optional.None[TransferID](),
+		Amount:           amount,
+		Foreign:          optional.None[ForeignAmount](),
+		Description:      description,
+		Notes:            notes,
+		TransactionDate:  transactionDate,
+		Status:           TransactionStatusUncleared,
+		PostingStatus:    PostingStatusPosted,
+		HoldAmount:       optional.None[money.Money](),
+		ReconciliationID: optional.None[ReconciliationID](),
+		CreatedAt:        createdAt,
+		UpdatedAt:        updatedAt,
 	}
 }
 
@@ -136,8 +164,58 @@ func (t *Transaction) UpdateInfo(description, notes string) error {
 	return nil
 }
 
+// AddTag labels the transaction with a tag, ignoring case and surrounding spaces.
+// Adding a tag the transaction already has is a no-op.
+func (t *Transaction) AddTag(tag string) error {
+	tag = normalizeTag(tag)
+	if tag == "" {
+		return fmt.Errorf("tag cannot be empty")
+	}
+
+	if slices.Contains(t.Tags, tag) {
+		return nil
+	}
+
+	t.Tags = append(t.Tags, tag)
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// HasTag checks if the transaction is labelled with the tag, ignoring case
+func (t *Transaction) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, normalizeTag(tag))
+}
+
+// RemoveTag removes the tag from the transaction, ignoring case
+func (t *Transaction) RemoveTag(tag string) {
+	tag = normalizeTag(tag)
+	if i := slices.Index(t.Tags, tag); i >= 0 {
+		t.Tags = slices.Delete(t.Tags, i, i+1)
+		t.UpdatedAt = time.Now()
+	}
+}
+
+// AppendNote adds a line to the transaction's notes unless they already contain it
+func (t *Transaction) AppendNote(note string) {
+	note = strings.TrimSpace(note)
+	if note == "" || strings.Contains(t.Notes, note) {
+		return
+	}
+
+	t.Notes = strings.TrimSpace(t.Notes + "\n" + note)
+	t.UpdatedAt = time.Now()
+}
+
 // UpdateAmount updates the transaction amount
 func (t *Transaction) UpdateAmount(amount money.Money) error {
+	if t.Locked {
+		return fmt.Errorf("transaction is reconciled and locked")
+	}
+
+	if t.IsSplit() {
+		return fmt.Errorf("transaction is split: remove the split before changing the amount")
+	}
+
 	if amount.IsZero() {
 		return fmt.Errorf("transaction amount cannot be zero")
 	}
@@ -146,20 +224,35 @@ func (t *Transaction) UpdateAmount(amount money.Money) error {
 		return fmt.Errorf("currency mismatch: transaction uses %s, new amount uses %s", t.Amount.Currency, amount.Currency)
 	}
 
+	if t.IsForeign() {
+		return fmt.Errorf("transaction was charged in %s: change its amounts with SetForeignAmount",
+			t.Foreign.Unwrap().Original.Currency)
+	}
+
+	if err := amount.ValidatePrecision(); err != nil {
+		return fmt.Errorf("invalid transaction amount: %w", err)
+	}
+
 	t.Amount = amount
 	t.UpdatedAt = time.Now()
 	return nil
 }
 
 // UpdateTransactionDate updates when the transaction occurred
-func (t *Transaction) UpdateTransactionDate(transactionDate time.Time) {
+func (t *Transaction) UpdateTransactionDate(transactionDate time.Time) error {
+	if t.Locked {
+		return fmt.Errorf("transaction is reconciled and locked")
+	}
+
 	t.TransactionDate = transactionDate
 	t.UpdatedAt = time.Now()
+	return nil
 }
 
-// UpdateItem updates the budget item
+// UpdateItem assigns the whole transaction to a single budget item, removing any split
 func (t *Transaction) UpdateItem(itemID budgetEntity.ItemID) {
 	t.ItemID = itemID
+	t.Splits = nil
 	t.UpdatedAt = time.Now()
 }
 
@@ -193,6 +286,66 @@ func (t *Transaction) GetCounterpartyID() optional.Option[counterpartyEntity.Cou
 	return t.CounterpartyID
 }
 
+// IsTransfer checks if the transaction is one side of a Transfer and must be edited through it
+func (t *Transaction) IsTransfer() bool {
+	return t.TransferID.IsSome()
+}
+
+// Clear marks the transaction as seen on a statement
+func (t *Transaction) Clear() error {
+	if t.Status.IsReconciled() {
+		return fmt.Errorf("transaction is already reconciled")
+	}
+
+	if !t.PostingStatus.IsPosted() {
+		return fmt.Errorf("only posted transactions can be cleared")
+	}
+
+	t.Status = TransactionStatusCleared
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// Unclear marks the transaction as not yet seen on a statement
+func (t *Transaction) Unclear() error {
+	if t.Status.IsReconciled() {
+		return fmt.Errorf("transaction is already reconciled")
+	}
+
+	t.Status = TransactionStatusUncleared
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// Unlock allows a reconciled transaction's amount and date to be edited again
+func (t *Transaction) Unlock() {
+	t.Locked = false
+	t.UpdatedAt = time.Now()
+}
+
+// Lock prevents a reconciled transaction's amount and date from being edited
+func (t *Transaction) Lock() error {
+	if !t.Status.IsReconciled() {
+		return fmt.Errorf("only reconciled transactions can be locked")
+	}
+
+	t.Locked = true
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsLocked checks if the transaction is reconciled and locked against edits
+func (t *Transaction) IsLocked() bool {
+	return t.Locked
+}
+
+func (t *Transaction) markReconciled(id ReconciliationID) {
+	t.Status = TransactionStatusReconciled
+	t.ReconciliationID = optional.Some(id)
+	t.Locked = true
+	t.UpdatedAt = time.Now()
+}
+
 // IsDebit checks if the transaction is a debit (negative amount)
 func (t *Transaction) IsDebit() bool {
 	return t.Amount.IsNegative()
@@ -207,3 +360,7 @@ func (t *Transaction) IsCredit() bool {
 func (t *Transaction) GetAbsoluteAmount() money.Money {
 	return t.Amount.Abs()
 }
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
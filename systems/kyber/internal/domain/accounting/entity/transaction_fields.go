@@ -33,3 +33,78 @@ func NewTransactionIDFromString(idStr string) (TransactionID, error) {
 func (t TransactionID) Equals(other TransactionID) bool {
 	return t.EntityID.Equals(other.EntityID)
 }
+
+// TransactionStatus represents how far a transaction has been matched against bank statements
+type TransactionStatus string
+
+// Transaction status constants
+const (
+	TransactionStatusUncleared  TransactionStatus = "UNCLEARED"  // Recorded but not yet seen on a statement
+	TransactionStatusCleared    TransactionStatus = "CLEARED"    // Seen on a statement, not yet reconciled
+	TransactionStatusReconciled TransactionStatus = "RECONCILED" // Part of a finished reconciliation
+)
+
+// NewTransactionStatus creates a new TransactionStatus from string
+func NewTransactionStatus(status string) (TransactionStatus, error) {
+	switch TransactionStatus(status) {
+	case TransactionStatusUncleared, TransactionStatusCleared, TransactionStatusReconciled:
+		return TransactionStatus(status), nil
+	default:
+		return "", fmt.Errorf("invalid transaction status: %s", status)
+	}
+}
+
+// String returns the string representation of TransactionStatus
+func (s TransactionStatus) String() string {
+	return string(s)
+}
+
+// IsCleared checks if the transaction has been seen on a statement (including reconciled ones)
+func (s TransactionStatus) IsCleared() bool {
+	return s == TransactionStatusCleared || s == TransactionStatusReconciled
+}
+
+// IsReconciled checks if the transaction is part of a finished reconciliation
+func (s TransactionStatus) IsReconciled() bool {
+	return s == TransactionStatusReconciled
+}
+
+// PostingStatus represents whether a transaction has settled, separately from how far it has been reconciled
+type PostingStatus string
+
+// Posting status constants
+const (
+	PostingStatusPending PostingStatus = "PENDING" // An authorisation hold: counts against the available balance only
+	PostingStatusPosted  PostingStatus = "POSTED"  // Settled and part of the current balance
+	PostingStatusVoided  PostingStatus = "VOIDED"  // A hold that was released or expired without settling
+)
+
+// NewPostingStatus creates a new PostingStatus from string
+func NewPostingStatus(status string) (PostingStatus, error) {
+	switch PostingStatus(status) {
+	case PostingStatusPending, PostingStatusPosted, PostingStatusVoided:
+		return PostingStatus(status), nil
+	default:
+		return "", fmt.Errorf("invalid posting status: %s", status)
+	}
+}
+
+// String returns the string representation of PostingStatus
+func (s PostingStatus) String() string {
+	return string(s)
+}
+
+// IsPending checks if the transaction is an authorisation hold that has not settled yet
+func (s PostingStatus) IsPending() bool {
+	return s == PostingStatusPending
+}
+
+// IsPosted checks if the transaction has settled and counts towards the current balance
+func (s PostingStatus) IsPosted() bool {
+	return s == PostingStatusPosted
+}
+
+// IsVoided checks if the transaction is a hold that was released without settling
+func (s PostingStatus) IsVoided() bool {
+	return s == PostingStatusVoided
+}
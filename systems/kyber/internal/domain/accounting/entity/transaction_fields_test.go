@@ -112,4 +112,68 @@ func TestTransactionID_TypeSafety(t *testing.T) {
 	// This ensures TransactionID is a distinct type from EntityID
 	// and can't be accidentally used interchangeably
 	assert.IsType(t, TransactionID{}, transactionID)
-}
\ No newline at end of file
+}
+
+func TestTransactionStatus(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantErr    bool
+		cleared    bool
+		reconciled bool
+	}{
+		{input: "UNCLEARED"},
+		{input: "CLEARED", cleared: true},
+		{input: "RECONCILED", cleared: true, reconciled: true},
+		{input: "cleared", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			status, err := NewTransactionStatus(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid transaction status")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.input, status.String())
+			assert.Equal(t, tt.cleared, status.IsCleared())
+			assert.Equal(t, tt.reconciled, status.IsReconciled())
+		})
+	}
+}
+
+func TestPostingStatus(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+		pending bool
+		posted  bool
+		voided  bool
+	}{
+		{input: "PENDING", pending: true},
+		{input: "POSTED", posted: true},
+		{input: "VOIDED", voided: true},
+		{input: "posted", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			status, err := NewPostingStatus(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid posting status")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.input, status.String())
+			assert.Equal(t, tt.pending, status.IsPending())
+			assert.Equal(t, tt.posted, status.IsPosted())
+			assert.Equal(t, tt.voided, status.IsVoided())
+		})
+	}
+}
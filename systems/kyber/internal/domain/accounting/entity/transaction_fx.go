@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// fxRatePlaces is the number of decimal places effective FX rates are kept to, as transfers store theirs
+const fxRatePlaces = 12
+
+// ForeignAmount is what a transaction was charged in a currency other than its account's, e.g. USD on an
+// SGD card, and how that converted. The transaction's Amount is the settled amount in the account's
+// currency: the converted original amount plus the FX fee.
+type ForeignAmount struct {
+	Original money.Money // Amount charged in the foreign currency, with the sign of the settled amount
+	Rate     money.Rate  // Effective rate from the original to the account's currency, before the fee
+	Fee      money.Money // FX fee in the account's currency, with the sign of the settled amount; zero if none
+}
+
+// Converted returns the original amount in the account's currency: the settled amount less the FX fee
+func (f ForeignAmount) Converted(settled money.Money) (money.Money, error) {
+	return settled.Subtract(f.Fee)
+}
+
+// OriginalCurrencySpend sums the transactions charged in one currency, e.g. to see what a trip tagged on its
+// transactions cost in each currency paid in. Amounts are signed, so spend is negative and refunds offset it.
+type OriginalCurrencySpend struct {
+	Currency money.Currency // Foreign currency charged, or the account's for transactions charged in it
+	Original money.Money    // Sum in Currency
+	Settled  money.Bag      // Sum settled per account currency, FX fees included
+	Fees     money.Bag      // FX fees per account currency
+	Count    int
+}
+
+// SetForeignAmount records that the transaction was charged original in a foreign currency and settled
+// in the account's currency, of which fee was an FX fee. The effective rate is derived from the settled
+// amount less the fee.
+func (t *Transaction) SetForeignAmount(original, settled, fee money.Money) error {
+	if t.Locked {
+		return fmt.Errorf("transaction is reconciled and locked")
+	}
+
+	if t.IsTransfer() {
+		return fmt.Errorf("transfers record their exchange rate on the transfer")
+	}
+
+	if settled.Currency != t.Amount.Currency {
+		return fmt.Errorf("currency mismatch: transaction uses %s, settled amount uses %s", t.Amount.Currency, settled.Currency)
+	}
+
+	if t.IsSplit() && !settled.Equals(t.Amount) {
+		return fmt.Errorf("transaction is split: remove the split before changing the amount")
+	}
+
+	foreign, err := newForeignAmount(original, settled, fee, t.TransactionDate)
+	if err != nil {
+		return err
+	}
+
+	t.Amount = settled
+	t.Foreign = optional.Some(foreign)
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// RemoveForeignAmount forgets what the transaction was charged in a foreign currency, keeping its settled amount
+func (t *Transaction) RemoveForeignAmount() error {
+	if t.Locked {
+		return fmt.Errorf("transaction is reconciled and locked")
+	}
+
+	t.Foreign = optional.None[ForeignAmount]()
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsForeign checks if the transaction was charged in a currency other than its account's
+func (t *Transaction) IsForeign() bool {
+	return t.Foreign.IsSome()
+}
+
+// OriginalAmount returns the amount the transaction was charged: in the foreign currency if it was
+// charged in one, otherwise its amount
+func (t *Transaction) OriginalAmount() money.Money {
+	if t.IsForeign() {
+		return t.Foreign.Unwrap().Original
+	}
+	return t.Amount
+}
+
+// resettle keeps the foreign amount and fee of a transaction settling at another amount, deriving the new
+// effective rate. Transactions charged in the account's currency are left alone.
+func (t *Transaction) resettle(settled money.Money, date time.Time) error {
+	if !t.IsForeign() || settled.Equals(t.Amount) && date.Equal(t.TransactionDate) {
+		return nil
+	}
+
+	f := t.Foreign.Unwrap()
+	foreign, err := newForeignAmount(f.Original, settled, f.Fee, date)
+	if err != nil {
+		return err
+	}
+
+	t.Foreign = optional.Some(foreign)
+	return nil
+}
+
+func newForeignAmount(original, settled, fee money.Money, date time.Time) (ForeignAmount, error) {
+	if original.Currency == settled.Currency {
+		return ForeignAmount{}, fmt.Errorf("original amount must be in a currency other than %s", settled.Currency)
+	}
+
+	if original.IsZero() || settled.IsZero() {
+		return ForeignAmount{}, fmt.Errorf("original and settled amounts cannot be zero")
+	}
+
+	if original.IsNegative() != settled.IsNegative() {
+		return ForeignAmount{}, fmt.Errorf("original amount %s must have the same sign as the settled amount %s",
+			original, settled)
+	}
+
+	if err := original.ValidatePrecision(); err != nil {
+		return ForeignAmount{}, fmt.Errorf("invalid original amount: %w", err)
+	}
+
+	if err := settled.ValidatePrecision(); err != nil {
+		return ForeignAmount{}, fmt.Errorf("invalid settled amount: %w", err)
+	}
+
+	if fee.Currency != settled.Currency {
+		return ForeignAmount{}, fmt.Errorf("currency mismatch: settled amount uses %s, FX fee uses %s",
+			settled.Currency, fee.Currency)
+	}
+
+	if !fee.IsZero() && fee.IsNegative() != settled.IsNegative() {
+		return ForeignAmount{}, fmt.Errorf("FX fee %s must have the same sign as the settled amount %s", fee, settled)
+	}
+
+	if err := fee.ValidatePrecision(); err != nil {
+		return ForeignAmount{}, fmt.Errorf("invalid FX fee: %w", err)
+	}
+
+	converted, err := settled.Subtract(fee)
+	if err != nil {
+		return ForeignAmount{}, err
+	}
+
+	if converted.IsZero() || converted.IsNegative() != settled.IsNegative() {
+		return ForeignAmount{}, fmt.Errorf("FX fee %s must be less than the settled amount %s", fee.Abs(), settled.Abs())
+	}
+
+	rate, err := money.NewRate(original.Currency, settled.Currency,
+		converted.Decimal().DivRound(original.Decimal(), fxRatePlaces), date)
+	if err != nil {
+		return ForeignAmount{}, fmt.Errorf("invalid effective FX rate: %w", err)
+	}
+
+	return ForeignAmount{Original: original, Rate: rate, Fee: fee}, nil
+}
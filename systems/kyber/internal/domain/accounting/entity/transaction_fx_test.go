@@ -0,0 +1,133 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestTransaction_SetForeignAmount(t *testing.T) {
+	tx := createTestTransaction(t)
+	assert.False(t, tx.IsForeign())
+	assert.Equal(t, "100.00 USD", tx.OriginalAmount().String())
+
+	// 100.00 EUR converted at 1.078 plus a 2.20 fee
+	require.NoError(t, tx.SetForeignAmount(
+		mustMoney(t, "-100.00", "EUR"), mustMoney(t, "-110.00", "USD"), mustMoney(t, "-2.20", "USD"),
+	))
+	require.True(t, tx.IsForeign())
+	assert.Equal(t, "-110.00 USD", tx.Amount.String())
+	assert.Equal(t, "-100.00 EUR", tx.OriginalAmount().String())
+
+	foreign := tx.Foreign.Unwrap()
+	assert.Equal(t, money.Currency("EUR"), foreign.Rate.From)
+	assert.Equal(t, money.Currency("USD"), foreign.Rate.To)
+	assert.True(t, foreign.Rate.Value.Equal(decimal.RequireFromString("1.078")))
+	assert.Equal(t, tx.TransactionDate, foreign.Rate.Date)
+	converted, err := foreign.Converted(tx.Amount)
+	require.NoError(t, err)
+	assert.Equal(t, "-107.80 USD", converted.String())
+
+	err = tx.UpdateAmount(mustMoney(t, "-120.00", "USD"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "transaction was charged in EUR")
+
+	tx.Status = TransactionStatusReconciled
+	require.NoError(t, tx.Lock())
+	err = tx.RemoveForeignAmount()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reconciled and locked")
+	assert.True(t, tx.IsForeign())
+
+	tx.Unlock()
+	require.NoError(t, tx.RemoveForeignAmount())
+	assert.False(t, tx.IsForeign())
+	assert.Equal(t, "-110.00 USD", tx.OriginalAmount().String())
+}
+
+func TestTransaction_SetForeignAmount_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		original    string
+		currency    string
+		settled     string
+		fee         string
+		prepare     func(t *testing.T, tx *Transaction)
+		errContains string
+	}{
+		{name: "same currency", original: "-100.00", currency: "USD", settled: "-100.00", fee: "0", errContains: "must be in a currency other than USD"},
+		{name: "opposite signs", original: "100.00", currency: "EUR", settled: "-110.00", fee: "0", errContains: "must have the same sign as the settled amount"},
+		{name: "zero original", original: "0", currency: "EUR", settled: "-110.00", fee: "0", errContains: "cannot be zero"},
+		{name: "original precision", original: "-100.001", currency: "EUR", settled: "-110.00", fee: "0", errContains: "invalid original amount"},
+		{name: "fee sign", original: "-100.00", currency: "EUR", settled: "-110.00", fee: "2.20", errContains: "FX fee 2.20 USD must have the same sign"},
+		{name: "fee of the whole amount", original: "-100.00", currency: "EUR", settled: "-110.00", fee: "-110.00", errContains: "must be less than the settled amount"},
+		{
+			name: "locked", original: "-100.00", currency: "EUR", settled: "-110.00", fee: "0",
+			prepare: func(t *testing.T, tx *Transaction) {
+				tx.Status = TransactionStatusReconciled
+				require.NoError(t, tx.Lock())
+			},
+			errContains: "reconciled and locked",
+		},
+		{
+			name: "transfer", original: "-100.00", currency: "EUR", settled: "-110.00", fee: "0",
+			prepare: func(t *testing.T, tx *Transaction) {
+				transferID, err := NewTransferID()
+				require.NoError(t, err)
+				tx.TransferID = optional.Some(transferID)
+			},
+			errContains: "transfers record their exchange rate on the transfer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := createTestTransaction(t)
+			if tt.prepare != nil {
+				tt.prepare(t, tx)
+			}
+
+			err := tx.SetForeignAmount(mustMoney(t, tt.original, tt.currency), mustMoney(t, tt.settled, "USD"),
+				mustMoney(t, tt.fee, "USD"))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+			assert.False(t, tx.IsForeign())
+			assert.Equal(t, "100.00 USD", tx.Amount.String())
+		})
+	}
+}
+
+func TestTransaction_Post_Foreign(t *testing.T) {
+	postedDate := time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	tx := createTestTransaction(t)
+	require.NoError(t, tx.SetForeignAmount(
+		mustMoney(t, "-100.00", "EUR"), mustMoney(t, "-110.00", "USD"), mustMoney(t, "-2.20", "USD"),
+	))
+	require.NoError(t, tx.MarkPending())
+
+	// The card settles at a worse rate than it authorised at: the original amount and fee stay the same
+	require.NoError(t, tx.Post(mustMoney(t, "-111.10", "USD"), postedDate))
+	foreign := tx.Foreign.Unwrap()
+	assert.Equal(t, "-100.00 EUR", foreign.Original.String())
+	assert.Equal(t, "-2.20 USD", foreign.Fee.String())
+	assert.True(t, foreign.Rate.Value.Equal(decimal.RequireFromString("1.089")))
+	assert.Equal(t, postedDate, foreign.Rate.Date)
+
+	hold := createTestTransaction(t)
+	require.NoError(t, hold.SetForeignAmount(
+		mustMoney(t, "-100.00", "EUR"), mustMoney(t, "-110.00", "USD"), mustMoney(t, "-2.20", "USD"),
+	))
+	require.NoError(t, hold.MarkPending())
+
+	err := hold.Post(mustMoney(t, "-2.00", "USD"), postedDate)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be less than the settled amount")
+	assert.True(t, hold.PostingStatus.IsPending())
+}
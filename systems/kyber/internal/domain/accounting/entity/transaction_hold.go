@@ -0,0 +1,153 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// DefaultHoldExpiry is how long a pending hold waits for its settlement before it is voided.
+// Card networks release most authorisations within a week; this leaves room for slow merchants.
+const DefaultHoldExpiry = 10 * 24 * time.Hour
+
+// settlementDateSlack is how far before its hold a settlement may be dated, as banks sometimes
+// post at the authorisation date in another time zone
+const settlementDateSlack = 24 * time.Hour
+
+// HoldTolerance is how far a settlement may differ from its pending hold and still be matched to it:
+// the larger of Percent of the hold amount and MinorUnits of the hold's currency, so the same tolerance
+// suits currencies of any size. Tips and FX make settlements differ from the amount authorised.
+type HoldTolerance struct {
+	Percent    decimal.Decimal
+	MinorUnits int64 // e.g. 100 is 1.00 USD, 100 JPY or 0.100 BHD
+}
+
+// DefaultHoldTolerance matches settlements within 20% or 100 minor units of currency of their hold
+var DefaultHoldTolerance = HoldTolerance{Percent: decimal.NewFromInt(20), MinorUnits: 100}
+
+// Allows checks if the settlement amount is close enough to the hold amount.
+// Amounts in different currencies or of opposite signs are never close enough.
+func (h HoldTolerance) Allows(hold, settlement money.Money) bool {
+	if hold.Currency != settlement.Currency || hold.IsNegative() != settlement.IsNegative() {
+		return false
+	}
+
+	limit := decimal.Max(hold.Decimal().Abs().Mul(h.Percent).Div(decimal.NewFromInt(100)),
+		decimal.New(h.MinorUnits, -hold.Currency.MinorUnits()))
+	return settlement.Decimal().Sub(hold.Decimal()).Abs().LessThanOrEqual(limit)
+}
+
+// MarkPending records a new transaction as an authorisation hold, which counts against the available
+// balance but not the current one until it is posted
+func (t *Transaction) MarkPending() error {
+	if !t.PostingStatus.IsPosted() || t.HoldAmount.IsSome() {
+		return fmt.Errorf("transaction is already %s", t.PostingStatus)
+	}
+
+	if t.Status.IsCleared() {
+		return fmt.Errorf("cleared transactions cannot be pending")
+	}
+
+	if t.IsTransfer() {
+		return fmt.Errorf("transfers cannot be pending")
+	}
+
+	t.PostingStatus = PostingStatusPending
+	t.HoldAmount = optional.Some(t.Amount)
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// Post settles a pending hold at the amount and date it posted with, keeping the authorised amount
+// in HoldAmount. A zero postedDate keeps the hold's date.
+func (t *Transaction) Post(amount money.Money, postedDate time.Time) error {
+	if !t.PostingStatus.IsPending() {
+		return fmt.Errorf("only pending transactions can be posted, transaction is %s", t.PostingStatus)
+	}
+
+	if amount.Currency != t.Amount.Currency {
+		return fmt.Errorf("currency mismatch: transaction uses %s, posted amount uses %s", t.Amount.Currency, amount.Currency)
+	}
+
+	if amount.IsZero() || amount.IsNegative() != t.Amount.IsNegative() {
+		return fmt.Errorf("posted amount %s must have the same sign as the hold %s", amount, t.Amount)
+	}
+
+	if err := amount.ValidatePrecision(); err != nil {
+		return fmt.Errorf("invalid posted amount: %w", err)
+	}
+
+	if t.IsSplit() && !amount.Equals(t.Amount) {
+		return fmt.Errorf("transaction is split: remove the split before posting at another amount")
+	}
+
+	if postedDate.IsZero() {
+		postedDate = t.TransactionDate
+	}
+
+	// A foreign hold settles at the rate of the posting date: the original amount and fee stay the same
+	if err := t.resettle(amount, postedDate); err != nil {
+		return err
+	}
+
+	t.Amount = amount
+	t.TransactionDate = postedDate
+	t.PostingStatus = PostingStatusPosted
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// Void releases a pending hold that will not settle
+func (t *Transaction) Void() error {
+	if !t.PostingStatus.IsPending() {
+		return fmt.Errorf("only pending transactions can be voided, transaction is %s", t.PostingStatus)
+	}
+
+	t.PostingStatus = PostingStatusVoided
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsHoldExpired checks if the transaction is a pending hold dated more than maxAge before now
+func (t *Transaction) IsHoldExpired(now time.Time, maxAge time.Duration) bool {
+	return t.PostingStatus.IsPending() && t.TransactionDate.Before(now.Add(-maxAge))
+}
+
+// MatchHold finds the pending hold of the settlement's account that it most likely settles: the one
+// closest in amount within the tolerance, and the oldest of those equally close. Holds dated after the
+// settlement are not considered, and split holds only match their exact amount.
+func MatchHold(holds []*Transaction, settlement *Transaction, tolerance HoldTolerance) optional.Option[*Transaction] {
+	var (
+		best     *Transaction
+		bestDiff decimal.Decimal
+	)
+	for _, hold := range holds {
+		if !hold.PostingStatus.IsPending() || !hold.AccountID.Equals(settlement.AccountID) {
+			continue
+		}
+
+		if settlement.TransactionDate.Before(hold.TransactionDate.Add(-settlementDateSlack)) {
+			continue
+		}
+
+		if !tolerance.Allows(hold.Amount, settlement.Amount) ||
+			hold.IsSplit() && !hold.Amount.Equals(settlement.Amount) {
+			continue
+		}
+
+		diff := settlement.Amount.Decimal().Sub(hold.Amount.Decimal()).Abs()
+		if best == nil || diff.LessThan(bestDiff) ||
+			diff.Equal(bestDiff) && hold.TransactionDate.Before(best.TransactionDate) {
+			best, bestDiff = hold, diff
+		}
+	}
+
+	if best == nil {
+		return optional.None[*Transaction]()
+	}
+	return optional.Some(best)
+}
@@ -0,0 +1,216 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction_MarkPending(t *testing.T) {
+	tx := createTestTransaction(t)
+	require.NoError(t, tx.MarkPending())
+	assert.True(t, tx.PostingStatus.IsPending())
+	assert.True(t, tx.HoldAmount.IsSome())
+	assert.True(t, tx.HoldAmount.Unwrap().Equals(tx.Amount))
+
+	err := tx.MarkPending()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "transaction is already PENDING")
+
+	cleared := createTestTransaction(t)
+	require.NoError(t, cleared.Clear())
+	err = cleared.MarkPending()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cleared transactions cannot be pending")
+
+	err = tx.Clear()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only posted transactions can be cleared")
+}
+
+func TestTransaction_Post(t *testing.T) {
+	holdDate := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	postedDate := time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	newHold := func(t *testing.T) *Transaction {
+		tx := createTestTransaction(t)
+		require.NoError(t, tx.UpdateAmount(mustMoney(t, "-50.00", "USD")))
+		require.NoError(t, tx.UpdateTransactionDate(holdDate))
+		require.NoError(t, tx.MarkPending())
+		return tx
+	}
+
+	tests := []struct {
+		name        string
+		amount      string
+		currency    string
+		postedDate  time.Time
+		wantDate    time.Time
+		errContains string
+	}{
+		{name: "with tip", amount: "-57.50", currency: "USD", postedDate: postedDate, wantDate: postedDate},
+		{name: "keeps hold date", amount: "-50.00", currency: "USD", wantDate: holdDate},
+		{name: "currency mismatch", amount: "-50.00", currency: "EUR", errContains: "currency mismatch"},
+		{name: "opposite sign", amount: "50.00", currency: "USD", errContains: "must have the same sign"},
+		{name: "zero", amount: "0.00", currency: "USD", errContains: "must have the same sign"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newHold(t)
+			err := tx.Post(mustMoney(t, tt.amount, tt.currency), tt.postedDate)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.True(t, tx.PostingStatus.IsPending())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tx.PostingStatus.IsPosted())
+			assert.Equal(t, tt.amount+" USD", tx.Amount.String())
+			assert.Equal(t, "-50.00 USD", tx.HoldAmount.Unwrap().String())
+			assert.Equal(t, tt.wantDate, tx.TransactionDate)
+		})
+	}
+
+	t.Run("not pending", func(t *testing.T) {
+		tx := newHold(t)
+		require.NoError(t, tx.Post(tx.Amount, time.Time{}))
+		err := tx.Post(tx.Amount, time.Time{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only pending transactions can be posted")
+	})
+
+	t.Run("split", func(t *testing.T) {
+		tx := newHold(t)
+		require.NoError(t, tx.Split([]SplitLine{
+			{ItemID: mustItemID(t), Amount: mustMoney(t, "-20.00", "USD")},
+			{ItemID: mustItemID(t), Amount: mustMoney(t, "-30.00", "USD")},
+		}))
+
+		err := tx.Post(mustMoney(t, "-55.00", "USD"), time.Time{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "transaction is split")
+
+		require.NoError(t, tx.Post(mustMoney(t, "-50.00", "USD"), time.Time{}))
+	})
+}
+
+func TestTransaction_Void(t *testing.T) {
+	tx := createTestTransaction(t)
+	err := tx.Void()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only pending transactions can be voided")
+
+	require.NoError(t, tx.MarkPending())
+	require.NoError(t, tx.Void())
+	assert.True(t, tx.PostingStatus.IsVoided())
+
+	err = tx.Post(tx.Amount, time.Time{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only pending transactions can be posted")
+}
+
+func TestTransaction_IsHoldExpired(t *testing.T) {
+	now := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	tx := createTestTransaction(t)
+	require.NoError(t, tx.UpdateTransactionDate(now.Add(-DefaultHoldExpiry-time.Hour)))
+	assert.False(t, tx.IsHoldExpired(now, DefaultHoldExpiry), "posted transactions never expire")
+
+	require.NoError(t, tx.MarkPending())
+	assert.True(t, tx.IsHoldExpired(now, DefaultHoldExpiry))
+	assert.False(t, tx.IsHoldExpired(now, DefaultHoldExpiry+2*time.Hour))
+}
+
+func TestHoldTolerance_Allows(t *testing.T) {
+	tests := []struct {
+		name         string
+		hold         string
+		settlement   string
+		holdCurrency string // USD unless set
+		currency     string
+		want         bool
+	}{
+		{name: "exact", hold: "-50.00", settlement: "-50.00", currency: "USD", want: true},
+		{name: "within percent", hold: "-50.00", settlement: "-60.00", currency: "USD", want: true},
+		{name: "beyond percent", hold: "-50.00", settlement: "-60.01", currency: "USD"},
+		{name: "within absolute", hold: "-2.00", settlement: "-3.00", currency: "USD", want: true},
+		{name: "beyond absolute", hold: "-2.00", settlement: "-3.01", currency: "USD"},
+		{name: "opposite sign", hold: "-0.50", settlement: "0.50", currency: "USD"},
+		{name: "currency mismatch", hold: "-50.00", settlement: "-50.00", currency: "EUR"},
+		{name: "within absolute without minor units", hold: "-200", settlement: "-300", holdCurrency: "JPY", currency: "JPY", want: true},
+		{name: "beyond absolute without minor units", hold: "-200", settlement: "-301", holdCurrency: "JPY", currency: "JPY"},
+		{name: "within absolute with three minor units", hold: "-0.200", settlement: "-0.300", holdCurrency: "BHD", currency: "BHD", want: true},
+		{name: "beyond absolute with three minor units", hold: "-0.200", settlement: "-0.301", holdCurrency: "BHD", currency: "BHD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holdCurrency := tt.holdCurrency
+			if holdCurrency == "" {
+				holdCurrency = "USD"
+			}
+			got := DefaultHoldTolerance.Allows(mustMoney(t, tt.hold, holdCurrency), mustMoney(t, tt.settlement, tt.currency))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	strict := HoldTolerance{Percent: decimal.Zero}
+	assert.False(t, strict.Allows(mustMoney(t, "-50.00", "USD"), mustMoney(t, "-50.01", "USD")))
+}
+
+func TestMatchHold(t *testing.T) {
+	base := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	template := createTestTransaction(t)
+
+	newTransaction := func(t *testing.T, amount string, date time.Time, pending bool) *Transaction {
+		tx, err := NewTransaction(template.LedgerID, template.AccountID, template.ItemID,
+			mustMoney(t, amount, "USD"), "Coffee", date)
+		require.NoError(t, err)
+		if pending {
+			require.NoError(t, tx.MarkPending())
+		}
+		return tx
+	}
+
+	older := newTransaction(t, "-50.00", base, true)
+	newer := newTransaction(t, "-50.00", base.AddDate(0, 0, 1), true)
+	closer := newTransaction(t, "-54.00", base.AddDate(0, 0, 2), true)
+	future := newTransaction(t, "-55.00", base.AddDate(0, 0, 10), true)
+	voided := newTransaction(t, "-55.00", base, true)
+	require.NoError(t, voided.Void())
+
+	otherAccount := newTransaction(t, "-55.00", base, true)
+	otherAccount.AccountID = createTestAccount(t).ID
+
+	holds := []*Transaction{newer, older, closer, future, voided, otherAccount}
+
+	match := MatchHold(holds, newTransaction(t, "-55.00", base.AddDate(0, 0, 3), false), DefaultHoldTolerance)
+	require.True(t, match.IsSome())
+	assert.Same(t, closer, match.Unwrap(), "closest amount wins")
+
+	match = MatchHold(holds, newTransaction(t, "-50.00", base.AddDate(0, 0, 3), false), DefaultHoldTolerance)
+	require.True(t, match.IsSome())
+	assert.Same(t, older, match.Unwrap(), "oldest of equally close holds wins")
+
+	match = MatchHold(holds, newTransaction(t, "-90.00", base.AddDate(0, 0, 3), false), DefaultHoldTolerance)
+	assert.True(t, match.IsNone(), "outside the tolerance")
+
+	match = MatchHold([]*Transaction{future}, newTransaction(t, "-55.00", base, false), DefaultHoldTolerance)
+	assert.True(t, match.IsNone(), "holds dated after the settlement are not considered")
+
+	split := newTransaction(t, "-50.00", base, true)
+	require.NoError(t, split.Split([]SplitLine{
+		{ItemID: mustItemID(t), Amount: mustMoney(t, "-20.00", "USD")},
+		{ItemID: mustItemID(t), Amount: mustMoney(t, "-30.00", "USD")},
+	}))
+	match = MatchHold([]*Transaction{split}, newTransaction(t, "-52.00", base, false), DefaultHoldTolerance)
+	assert.True(t, match.IsNone(), "split holds only match their exact amount")
+	match = MatchHold([]*Transaction{split}, newTransaction(t, "-50.00", base, false), DefaultHoldTolerance)
+	assert.True(t, match.IsSome())
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// TransactionFilter selects transactions of a ledger. Filters that are not set match any transaction;
+// the ones that are set must all match.
+type TransactionFilter struct {
+	// Text is a full-text query over description and notes in web search syntax:
+	// words, "quoted phrases", OR, and -excluded words
+	Text           string
+	MinAmount      optional.Option[money.Money] // Inclusive; amounts are signed, so outflows are negative
+	MaxAmount      optional.Option[money.Money] // Inclusive; amount filters only match their own currency
+	From           optional.Option[time.Time]   // Inclusive
+	To             optional.Option[time.Time]   // Exclusive
+	AccountID      optional.Option[AccountID]
+	ItemID         optional.Option[budgetEntity.ItemID] // Also matches split lines assigned to the item
+	CounterpartyID optional.Option[counterpartyEntity.CounterpartyID]
+	Tag            string
+}
+
+// TransactionPage is one page of the transactions matching a filter, newest first
+type TransactionPage struct {
+	Transactions []*Transaction
+	Total        int // Matching transactions across all pages
+	// Totals sums the matching transactions per currency across all pages, leaving out voided holds.
+	// When filtering by item, a split transaction counts only its lines assigned to the item.
+	Totals money.Bag
+}
+
+// Normalize returns the filter with its text trimmed and its tag normalised the way tags are stored,
+// checking that its ranges are valid
+func (f TransactionFilter) Normalize() (TransactionFilter, error) {
+	f.Text = strings.TrimSpace(f.Text)
+	f.Tag = normalizeTag(f.Tag)
+
+	if f.MinAmount.IsSome() && f.MaxAmount.IsSome() {
+		greater, err := f.MinAmount.Unwrap().GreaterThan(f.MaxAmount.Unwrap())
+		if err != nil {
+			return TransactionFilter{}, fmt.Errorf("invalid amount range: %w", err)
+		}
+		if greater {
+			return TransactionFilter{}, fmt.Errorf("invalid amount range: minimum %s is above maximum %s",
+				f.MinAmount.Unwrap(), f.MaxAmount.Unwrap())
+		}
+	}
+
+	if f.From.IsSome() && f.To.IsSome() && !f.To.Unwrap().After(f.From.Unwrap()) {
+		return TransactionFilter{}, fmt.Errorf("invalid date range: must end after it starts")
+	}
+
+	return f, nil
+}
+
+// AmountCurrency returns the currency of the amount filters, if either is set
+func (f TransactionFilter) AmountCurrency() optional.Option[money.Currency] {
+	switch {
+	case f.MinAmount.IsSome():
+		return optional.Some(f.MinAmount.Unwrap().Currency)
+	case f.MaxAmount.IsSome():
+		return optional.Some(f.MaxAmount.Unwrap().Currency)
+	default:
+		return optional.None[money.Currency]()
+	}
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestTransactionFilter_Normalize(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      TransactionFilter
+		expected    TransactionFilter
+		errContains string
+	}{
+		{
+			name:     "empty",
+			filter:   TransactionFilter{},
+			expected: TransactionFilter{},
+		},
+		{
+			name:     "text and tag",
+			filter:   TransactionFilter{Text: "  coffee -starbucks ", Tag: " Holiday "},
+			expected: TransactionFilter{Text: "coffee -starbucks", Tag: "holiday"},
+		},
+		{
+			name: "inverted amount range",
+			filter: TransactionFilter{
+				MinAmount: optional.Some(mustMoney(t, "10.00", "USD")),
+				MaxAmount: optional.Some(mustMoney(t, "5.00", "USD")),
+			},
+			errContains: "minimum 10.00 USD is above maximum 5.00 USD",
+		},
+		{
+			name: "amount range in two currencies",
+			filter: TransactionFilter{
+				MinAmount: optional.Some(mustMoney(t, "5.00", "USD")),
+				MaxAmount: optional.Some(mustMoney(t, "10.00", "EUR")),
+			},
+			errContains: "invalid amount range",
+		},
+		{
+			name: "empty date range",
+			filter: TransactionFilter{
+				From: optional.Some(utcDate(2024, 3, 1)),
+				To:   optional.Some(utcDate(2024, 3, 1)),
+			},
+			errContains: "must end after it starts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := tt.filter.Normalize()
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, filter)
+		})
+	}
+}
+
+func TestTransactionFilter_AmountCurrency(t *testing.T) {
+	assert.True(t, TransactionFilter{}.AmountCurrency().IsNone())
+
+	filter := TransactionFilter{MaxAmount: optional.Some(mustMoney(t, "-5.00", "EUR"))}
+	assert.Equal(t, money.Currency("EUR"), filter.AmountCurrency().Unwrap())
+}
@@ -0,0 +1,175 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// SplitLine assigns part of a transaction's amount to a budget item
+type SplitLine struct {
+	ItemID budgetEntity.ItemID
+	Amount money.Money // Same sign convention as Transaction.Amount
+	Note   string
+}
+
+// NewSplitLine creates a new SplitLine
+func NewSplitLine(itemID budgetEntity.ItemID, amount money.Money, note string) (SplitLine, error) {
+	if !itemID.IsValid() {
+		return SplitLine{}, fmt.Errorf("item ID is invalid")
+	}
+
+	if amount.IsZero() {
+		return SplitLine{}, fmt.Errorf("split amount cannot be zero")
+	}
+
+	if err := amount.ValidatePrecision(); err != nil {
+		return SplitLine{}, fmt.Errorf("invalid split amount: %w", err)
+	}
+
+	return SplitLine{ItemID: itemID, Amount: amount, Note: note}, nil
+}
+
+// Split spreads the transaction's amount across several budget items. The lines must sum to the amount.
+func (t *Transaction) Split(lines []SplitLine) error {
+	if t.IsTransfer() {
+		return fmt.Errorf("transfer transactions cannot be split")
+	}
+
+	if len(lines) < 2 {
+		return fmt.Errorf("a split requires at least two lines")
+	}
+
+	total, err := money.Zero(t.Amount.Currency)
+	if err != nil {
+		return fmt.Errorf("failed to initialize split total: %w", err)
+	}
+
+	for i, line := range lines {
+		if _, err := NewSplitLine(line.ItemID, line.Amount, line.Note); err != nil {
+			return fmt.Errorf("invalid split line %d: %w", i, err)
+		}
+
+		if line.Amount.Currency != t.Amount.Currency {
+			return fmt.Errorf("invalid split line %d: currency mismatch: transaction uses %s, line uses %s",
+				i, t.Amount.Currency, line.Amount.Currency)
+		}
+
+		total, err = total.Add(line.Amount)
+		if err != nil {
+			return fmt.Errorf("failed to sum split lines: %w", err)
+		}
+	}
+
+	if !total.Equals(t.Amount) {
+		return fmt.Errorf("split lines sum to %s but the transaction amount is %s", total, t.Amount)
+	}
+
+	t.Splits = append([]SplitLine(nil), lines...)
+	t.ItemID = lines[0].ItemID
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsSplit checks if the transaction's amount is spread across several budget items
+func (t *Transaction) IsSplit() bool {
+	return len(t.Splits) > 0
+}
+
+// Lines returns how the transaction's amount is assigned to budget items: the split lines,
+// or a single line for the whole amount when the transaction is not split
+func (t *Transaction) Lines() []SplitLine {
+	if t.IsSplit() {
+		return append([]SplitLine(nil), t.Splits...)
+	}
+	return []SplitLine{{ItemID: t.ItemID, Amount: t.Amount}}
+}
+
+// ItemActualChange is the change a transaction makes to one budget item's actuals in a month.
+// Amount follows the transaction sign convention: negative for money leaving the account.
+type ItemActualChange struct {
+	ItemID budgetEntity.ItemID
+	Year   int
+	Month  int
+	Amount money.Money
+}
+
+// ItemActualChanges returns how budget item actuals change when a transaction goes from before to after.
+// Pass nil before for a new transaction and nil after for a deleted one. Unchanged items are omitted,
+// so moving a split line from one item to another yields one change for each.
+func ItemActualChanges(before, after *Transaction) ([]ItemActualChange, error) {
+	type key struct {
+		itemID      string
+		year, month int
+	}
+
+	var (
+		changes []ItemActualChange
+		index   = make(map[key]int)
+	)
+
+	apply := func(tx *Transaction, negate bool) error {
+		// Pending holds count towards actuals so spending shows up before it settles
+		if tx == nil || tx.PostingStatus.IsVoided() {
+			return nil
+		}
+
+		year, month := tx.TransactionDate.Year(), int(tx.TransactionDate.Month())
+		for _, line := range tx.Lines() {
+			amount := line.Amount
+			if negate {
+				amount = amount.Negate()
+			}
+
+			k := key{itemID: line.ItemID.String(), year: year, month: month}
+			i, ok := index[k]
+			if !ok {
+				index[k] = len(changes)
+				changes = append(changes, ItemActualChange{ItemID: line.ItemID, Year: year, Month: month, Amount: amount})
+				continue
+			}
+
+			total, err := changes[i].Amount.Add(amount)
+			if err != nil {
+				return fmt.Errorf("failed to combine changes of item %s: %w", line.ItemID, err)
+			}
+			changes[i].Amount = total
+		}
+		return nil
+	}
+
+	if err := apply(before, true); err != nil {
+		return nil, err
+	}
+	if err := apply(after, false); err != nil {
+		return nil, err
+	}
+
+	nonZero := changes[:0]
+	for _, c := range changes {
+		if !c.Amount.IsZero() {
+			nonZero = append(nonZero, c)
+		}
+	}
+	return nonZero, nil
+}
+
+// ActualAmount returns what the change adds to the item's actuals. Expense and transfer items accumulate
+// money leaving the account; income items accumulate money coming in.
+func (c ItemActualChange) ActualAmount(item *budgetEntity.Item) (money.Money, error) {
+	if !item.ID.Equals(c.ItemID) {
+		return money.Money{}, fmt.Errorf("change is for item %s, not %s", c.ItemID, item.ID)
+	}
+
+	if c.Amount.Currency != item.Currency {
+		return money.Money{}, fmt.Errorf("currency mismatch: item %s uses %s, change uses %s",
+			item.Name, item.Currency, c.Amount.Currency)
+	}
+
+	if item.Type.IsIncome() {
+		return c.Amount, nil
+	}
+	return c.Amount.Negate(), nil
+}
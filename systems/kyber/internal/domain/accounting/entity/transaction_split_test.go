@@ -0,0 +1,197 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+)
+
+func TestTransaction_Split(t *testing.T) {
+	food := mustItemID(t)
+	household := mustItemID(t)
+
+	tests := []struct {
+		name        string
+		lines       func(t *testing.T) []SplitLine
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "lines sum to the amount",
+			lines: func(t *testing.T) []SplitLine {
+				return []SplitLine{
+					{ItemID: food, Amount: mustMoney(t, "-70.00", "USD"), Note: "Groceries"},
+					{ItemID: household, Amount: mustMoney(t, "-30.00", "USD")},
+				}
+			},
+		},
+		{
+			name: "lines do not sum to the amount",
+			lines: func(t *testing.T) []SplitLine {
+				return []SplitLine{
+					{ItemID: food, Amount: mustMoney(t, "-70.00", "USD")},
+					{ItemID: household, Amount: mustMoney(t, "-20.00", "USD")},
+				}
+			},
+			wantErr:     true,
+			errContains: "split lines sum to -90.00 USD but the transaction amount is -100.00 USD",
+		},
+		{
+			name: "single line",
+			lines: func(t *testing.T) []SplitLine {
+				return []SplitLine{{ItemID: food, Amount: mustMoney(t, "-100.00", "USD")}}
+			},
+			wantErr:     true,
+			errContains: "at least two lines",
+		},
+		{
+			name: "zero line",
+			lines: func(t *testing.T) []SplitLine {
+				return []SplitLine{
+					{ItemID: food, Amount: mustMoney(t, "-100.00", "USD")},
+					{ItemID: household, Amount: mustMoney(t, "0", "USD")},
+				}
+			},
+			wantErr:     true,
+			errContains: "split amount cannot be zero",
+		},
+		{
+			name: "currency mismatch",
+			lines: func(t *testing.T) []SplitLine {
+				return []SplitLine{
+					{ItemID: food, Amount: mustMoney(t, "-70.00", "USD")},
+					{ItemID: household, Amount: mustMoney(t, "-30.00", "EUR")},
+				}
+			},
+			wantErr:     true,
+			errContains: "currency mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := createTestTransaction(t)
+			require.NoError(t, transaction.UpdateAmount(mustMoney(t, "-100.00", "USD")))
+
+			lines := tt.lines(t)
+			err := transaction.Split(lines)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.False(t, transaction.IsSplit())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, transaction.IsSplit())
+			assert.Equal(t, lines, transaction.Lines())
+			assert.True(t, transaction.ItemID.Equals(food))
+
+			err = transaction.UpdateAmount(mustMoney(t, "-50.00", "USD"))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "transaction is split")
+
+			transaction.UpdateItem(household)
+			assert.False(t, transaction.IsSplit())
+			assert.Len(t, transaction.Lines(), 1)
+		})
+	}
+}
+
+func TestTransaction_Split_Transfer(t *testing.T) {
+	transfer, _, _ := createTestTransfer(t)
+
+	err := transfer.Outgoing.Split([]SplitLine{
+		{ItemID: mustItemID(t), Amount: mustMoney(t, "-50.00", "USD")},
+		{ItemID: mustItemID(t), Amount: mustMoney(t, "-50.00", "USD")},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "transfer transactions cannot be split")
+}
+
+func TestItemActualChanges(t *testing.T) {
+	food := mustItemID(t)
+	household := mustItemID(t)
+
+	before := createTestTransaction(t)
+	require.NoError(t, before.UpdateAmount(mustMoney(t, "-100.00", "USD")))
+	before.UpdateItem(food)
+	require.NoError(t, before.UpdateTransactionDate(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)))
+
+	after := *before
+	require.NoError(t, after.Split([]SplitLine{
+		{ItemID: food, Amount: mustMoney(t, "-70.00", "USD")},
+		{ItemID: household, Amount: mustMoney(t, "-30.00", "USD")},
+	}))
+
+	changes, err := ItemActualChanges(before, &after)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.True(t, changes[0].ItemID.Equals(food))
+	assert.Equal(t, "30.00 USD", changes[0].Amount.String())
+	assert.True(t, changes[1].ItemID.Equals(household))
+	assert.Equal(t, "-30.00 USD", changes[1].Amount.String())
+	assert.Equal(t, 2024, changes[1].Year)
+	assert.Equal(t, 3, changes[1].Month)
+
+	changes, err = ItemActualChanges(nil, &after)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "-70.00 USD", changes[0].Amount.String())
+
+	changes, err = ItemActualChanges(&after, &after)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	require.NoError(t, after.MarkPending())
+	voided := after
+	require.NoError(t, voided.Void())
+	changes, err = ItemActualChanges(&after, &voided)
+	require.NoError(t, err)
+	require.Len(t, changes, 2, "voiding a hold takes it out of the actuals")
+	assert.Equal(t, "70.00 USD", changes[0].Amount.String())
+	assert.Equal(t, "30.00 USD", changes[1].Amount.String())
+}
+
+func TestItemActualChange_ActualAmount(t *testing.T) {
+	ledgerID := createTestTransaction(t).LedgerID
+
+	expense, err := budgetEntity.NewItem(ledgerID, "Food", "", budgetEntity.ItemTypeExpense, "USD")
+	require.NoError(t, err)
+
+	income, err := budgetEntity.NewItem(ledgerID, "Salary", "", budgetEntity.ItemTypeIncome, "USD")
+	require.NoError(t, err)
+
+	spend := ItemActualChange{ItemID: expense.ID, Year: 2024, Month: 3, Amount: mustMoney(t, "-70.00", "USD")}
+	amount, err := spend.ActualAmount(expense)
+	require.NoError(t, err)
+	assert.Equal(t, "70.00 USD", amount.String())
+
+	earn := ItemActualChange{ItemID: income.ID, Year: 2024, Month: 3, Amount: mustMoney(t, "500.00", "USD")}
+	amount, err = earn.ActualAmount(income)
+	require.NoError(t, err)
+	assert.Equal(t, "500.00 USD", amount.String())
+
+	_, err = spend.ActualAmount(income)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "change is for item")
+
+	euros := ItemActualChange{ItemID: expense.ID, Year: 2024, Month: 3, Amount: mustMoney(t, "-70.00", "EUR")}
+	_, err = euros.ActualAmount(expense)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "currency mismatch")
+}
+
+// Helper functions
+
+func mustItemID(t *testing.T) budgetEntity.ItemID {
+	t.Helper()
+
+	id, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+	return id
+}
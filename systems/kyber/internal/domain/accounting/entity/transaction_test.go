@@ -92,6 +92,17 @@ func TestNewTransaction(t *testing.T) {
 			wantErr:         true,
 			errContains:     "transaction amount cannot be zero",
 		},
+		{
+			name:            "amount exceeds currency precision",
+			ledgerID:        ledgerID,
+			accountID:       accountID,
+			itemID:          itemID,
+			amount:          mustMoney(t, "100.505", "USD"),
+			description:     "Test transaction",
+			transactionDate: transactionDate,
+			wantErr:         true,
+			errContains:     "exceeds USD precision",
+		},
 		{
 			name:            "empty description",
 			ledgerID:        ledgerID,
@@ -327,6 +338,12 @@ func TestTransaction_UpdateAmount(t *testing.T) {
 			wantErr:     true,
 			errContains: "currency mismatch",
 		},
+		{
+			name:        "amount exceeds currency precision",
+			amount:      mustMoney(t, "200.001", "USD"),
+			wantErr:     true,
+			errContains: "exceeds USD precision",
+		},
 	}
 
 	for _, tt := range tests {
@@ -354,7 +371,7 @@ func TestTransaction_UpdateTransactionDate(t *testing.T) {
 	time.Sleep(time.Millisecond)
 
 	newDate := time.Now().Add(-2 * time.Hour)
-	transaction.UpdateTransactionDate(newDate)
+	require.NoError(t, transaction.UpdateTransactionDate(newDate))
 
 	assert.Equal(t, newDate, transaction.TransactionDate)
 	assert.True(t, transaction.UpdatedAt.After(originalUpdatedAt))
@@ -486,6 +503,91 @@ func TestTransaction_GetAbsoluteAmount(t *testing.T) {
 	}
 }
 
+func TestTransaction_ClearAndUnclear(t *testing.T) {
+	transaction := createTestTransaction(t)
+	assert.Equal(t, TransactionStatusUncleared, transaction.Status)
+
+	require.NoError(t, transaction.Clear())
+	assert.True(t, transaction.Status.IsCleared())
+
+	require.NoError(t, transaction.Unclear())
+	assert.False(t, transaction.Status.IsCleared())
+
+	reconciliationID, err := NewReconciliationID()
+	require.NoError(t, err)
+	transaction.markReconciled(reconciliationID)
+
+	err = transaction.Clear()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already reconciled")
+
+	err = transaction.Unclear()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already reconciled")
+}
+
+func TestTransaction_LockAndUnlock(t *testing.T) {
+	transaction := createTestTransaction(t)
+
+	err := transaction.Lock()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only reconciled transactions can be locked")
+
+	reconciliationID, err := NewReconciliationID()
+	require.NoError(t, err)
+	transaction.markReconciled(reconciliationID)
+
+	assert.True(t, transaction.IsLocked())
+	assert.True(t, transaction.Status.IsReconciled())
+	assert.True(t, transaction.ReconciliationID.Unwrap().Equals(reconciliationID))
+
+	err = transaction.UpdateAmount(mustMoney(t, "50.00", "USD"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reconciled and locked")
+
+	err = transaction.UpdateTransactionDate(time.Now().Add(-time.Hour))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reconciled and locked")
+
+	require.NoError(t, transaction.UpdateInfo("Still editable", "notes"))
+
+	transaction.Unlock()
+	assert.False(t, transaction.IsLocked())
+	require.NoError(t, transaction.UpdateAmount(mustMoney(t, "50.00", "USD")))
+
+	require.NoError(t, transaction.Lock())
+	assert.True(t, transaction.IsLocked())
+}
+
+func TestTransaction_Tags(t *testing.T) {
+	transaction := createTestTransaction(t)
+
+	require.NoError(t, transaction.AddTag(" Holiday "))
+	require.NoError(t, transaction.AddTag("holiday"))
+	require.NoError(t, transaction.AddTag("Reimbursable"))
+	assert.Equal(t, []string{"holiday", "reimbursable"}, transaction.Tags)
+	assert.True(t, transaction.HasTag("HOLIDAY"))
+	assert.False(t, transaction.HasTag("work"))
+
+	transaction.RemoveTag("HOLIDAY")
+	transaction.RemoveTag("work")
+	assert.Equal(t, []string{"reimbursable"}, transaction.Tags)
+
+	err := transaction.AddTag("  ")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tag cannot be empty")
+}
+
+func TestTransaction_AppendNote(t *testing.T) {
+	transaction := createTestTransaction(t)
+
+	transaction.AppendNote("Paid by card")
+	transaction.AppendNote(" ")
+	transaction.AppendNote("Split with Alex")
+	transaction.AppendNote("Paid by card")
+	assert.Equal(t, "Paid by card\nSplit with Alex", transaction.Notes)
+}
+
 // Helper functions
 
 func createTestTransaction(t *testing.T) *Transaction {
@@ -0,0 +1,412 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// Transfer moves money between two accounts of a ledger as a linked pair of transactions:
+// an outgoing (negative) one on the source account and an incoming (positive) one on the
+// destination account. When the accounts hold different currencies an explicit exchange
+// rate converts the outgoing amount into the incoming one.
+type Transfer struct {
+	ID        TransferID
+	LedgerID  ledgerEntity.LedgerID
+	Outgoing  *Transaction
+	Incoming  *Transaction
+	Rate      optional.Option[money.Rate] // Source to destination currency; only set when they differ
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewTransfer creates a new Transfer of amount (in the source currency) and applies it to both account balances
+func NewTransfer(
+	source, destination *Account,
+	itemID budgetEntity.ItemID,
+	amount money.Money,
+	fxRate optional.Option[decimal.Decimal],
+	description string,
+	transferDate time.Time,
+) (*Transfer, error) {
+	return newTransfer(source, destination, itemID, amount, fxRate, description, transferDate, false)
+}
+
+func newTransfer(
+	source, destination *Account,
+	itemID budgetEntity.ItemID,
+	amount money.Money,
+	fxRate optional.Option[decimal.Decimal],
+	description string,
+	transferDate time.Time,
+	allowOverdraft bool,
+) (*Transfer, error) {
+	if err := validateTransferAccounts(source, destination); err != nil {
+		return nil, err
+	}
+
+	if transferDate.IsZero() {
+		transferDate = time.Now()
+	}
+
+	rate, err := newTransferRate(source.Currency, destination.Currency, fxRate, transferDate)
+	if err != nil {
+		return nil, err
+	}
+
+	incomingAmount, err := transferIncomingAmount(source, amount, rate)
+	if err != nil {
+		return nil, err
+	}
+
+	if !allowOverdraft && !source.CanDebit(amount) {
+		return nil, fmt.Errorf("insufficient balance in source account: %s available, %s required", source.Balance, amount)
+	}
+
+	id, err := NewTransferID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate transfer ID: %w", err)
+	}
+
+	outgoing, err := NewTransaction(source.LedgerID, source.ID, itemID, amount.Negate(), description, transferDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outgoing transaction: %w", err)
+	}
+	outgoing.TransferID = optional.Some(id)
+
+	incoming, err := NewTransaction(destination.LedgerID, destination.ID, itemID, incomingAmount, description, transferDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create incoming transaction: %w", err)
+	}
+	incoming.TransferID = optional.Some(id)
+
+	if err := source.adjustBalance(amount.Negate()); err != nil {
+		return nil, fmt.Errorf("failed to debit source account: %w", err)
+	}
+
+	if err := destination.CreditBalance(incomingAmount); err != nil {
+		return nil, fmt.Errorf("failed to credit destination account: %w", err)
+	}
+
+	now := time.Now()
+
+	return &Transfer{
+		ID:        id,
+		LedgerID:  source.LedgerID,
+		Outgoing:  outgoing,
+		Incoming:  incoming,
+		Rate:      rate,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// NewTransferFromTransaction records a not yet stored transaction of account as a transfer with other:
+// an outflow moves money to other, an inflow from it. The transaction's side of the transfer keeps its
+// notes, tags and import ID. Both accounts must use the same currency.
+func NewTransferFromTransaction(tx *Transaction, account, other *Account) (*Transfer, error) {
+	return transferFromTransaction(tx, account, other, false)
+}
+
+// NewTransferFromStatement is NewTransferFromTransaction for a line of a bank statement. The statement is
+// the bank's own record of money that already moved, so the source account may go overdrawn.
+func NewTransferFromStatement(tx *Transaction, account, other *Account) (*Transfer, error) {
+	return transferFromTransaction(tx, account, other, true)
+}
+
+func transferFromTransaction(tx *Transaction, account, other *Account, allowOverdraft bool) (*Transfer, error) {
+	if account == nil || !account.ID.Equals(tx.AccountID) {
+		return nil, fmt.Errorf("account does not match transaction %s", tx.ID)
+	}
+
+	source, destination := account, other
+	if tx.IsCredit() {
+		source, destination = other, account
+	}
+
+	transfer, err := newTransfer(source, destination, tx.ItemID, tx.GetAbsoluteAmount(),
+		optional.None[decimal.Decimal](), tx.Description, tx.TransactionDate, allowOverdraft)
+	if err != nil {
+		return nil, err
+	}
+
+	side := transfer.Outgoing
+	if tx.IsCredit() {
+		side = transfer.Incoming
+	}
+	side.Notes = tx.Notes
+	side.Tags = tx.Tags
+	side.ImportID = tx.ImportID
+
+	return transfer, nil
+}
+
+// ReconstructTransfer reconstructs a Transfer from stored data
+func ReconstructTransfer(
+	id TransferID,
+	ledgerID ledgerEntity.LedgerID,
+	outgoing, incoming *Transaction,
+	rate optional.Option[money.Rate],
+	createdAt, updatedAt time.Time,
+) *Transfer {
+	outgoing.TransferID = optional.Some(id)
+	incoming.TransferID = optional.Some(id)
+
+	return &Transfer{
+		ID:        id,
+		LedgerID:  ledgerID,
+		Outgoing:  outgoing,
+		Incoming:  incoming,
+		Rate:      rate,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}
+}
+
+// SourceAccountID returns the account money is transferred from
+func (t *Transfer) SourceAccountID() AccountID {
+	return t.Outgoing.AccountID
+}
+
+// DestinationAccountID returns the account money is transferred to
+func (t *Transfer) DestinationAccountID() AccountID {
+	return t.Incoming.AccountID
+}
+
+// Amount returns the amount leaving the source account
+func (t *Transfer) Amount() money.Money {
+	return t.Outgoing.Amount.Abs()
+}
+
+// IncomingAmount returns the amount arriving in the destination account
+func (t *Transfer) IncomingAmount() money.Money {
+	return t.Incoming.Amount
+}
+
+// IsCrossCurrency checks if the source and destination accounts use different currencies
+func (t *Transfer) IsCrossCurrency() bool {
+	return t.Rate.IsSome()
+}
+
+// UpdateInfo updates the description and notes of both transactions
+func (t *Transfer) UpdateInfo(description, notes string) error {
+	if description == "" {
+		return fmt.Errorf("transfer description cannot be empty")
+	}
+
+	for _, tx := range []*Transaction{t.Outgoing, t.Incoming} {
+		if err := tx.UpdateInfo(description, notes); err != nil {
+			return fmt.Errorf("failed to update transaction %s: %w", tx.ID, err)
+		}
+	}
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// UpdateTransferDate updates when the transfer occurred on both transactions
+func (t *Transfer) UpdateTransferDate(transferDate time.Time) error {
+	if t.IsLocked() {
+		return fmt.Errorf("transfer has a reconciled and locked transaction")
+	}
+
+	for _, tx := range []*Transaction{t.Outgoing, t.Incoming} {
+		if err := tx.UpdateTransactionDate(transferDate); err != nil {
+			return fmt.Errorf("failed to update transaction %s: %w", tx.ID, err)
+		}
+	}
+	if t.Rate.IsSome() {
+		rate := t.Rate.Unwrap()
+		rate.Date = transferDate
+		t.Rate = optional.Some(rate)
+	}
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// UpdateItem updates the budget item of both transactions
+func (t *Transfer) UpdateItem(itemID budgetEntity.ItemID) {
+	t.Outgoing.UpdateItem(itemID)
+	t.Incoming.UpdateItem(itemID)
+	t.UpdatedAt = time.Now()
+}
+
+// UpdateAmount changes the transferred amount (and exchange rate for cross-currency transfers),
+// moving the difference between the account balances
+func (t *Transfer) UpdateAmount(
+	source, destination *Account,
+	amount money.Money,
+	fxRate optional.Option[decimal.Decimal],
+) error {
+	if err := t.checkAccounts(source, destination); err != nil {
+		return err
+	}
+
+	if t.IsLocked() {
+		return fmt.Errorf("transfer has a reconciled and locked transaction")
+	}
+
+	rate, err := newTransferRate(source.Currency, destination.Currency, fxRate, t.Outgoing.TransactionDate)
+	if err != nil {
+		return err
+	}
+
+	incomingAmount, err := transferIncomingAmount(source, amount, rate)
+	if err != nil {
+		return err
+	}
+
+	// Check the new amount against the balance as it was before this transfer
+	before := *source
+	before.Balance, err = source.Balance.Subtract(t.Outgoing.Amount)
+	if err != nil {
+		return fmt.Errorf("failed to compute source balance: %w", err)
+	}
+	if !before.CanDebit(amount) {
+		return fmt.Errorf("insufficient balance in source account: %s available, %s required", before.Balance, amount)
+	}
+
+	if err := t.Reverse(source, destination); err != nil {
+		return err
+	}
+
+	if err := source.DebitBalance(amount); err != nil {
+		return fmt.Errorf("failed to debit source account: %w", err)
+	}
+
+	if err := destination.CreditBalance(incomingAmount); err != nil {
+		return fmt.Errorf("failed to credit destination account: %w", err)
+	}
+
+	if err := t.Outgoing.UpdateAmount(amount.Negate()); err != nil {
+		return fmt.Errorf("failed to update outgoing transaction: %w", err)
+	}
+
+	if err := t.Incoming.UpdateAmount(incomingAmount); err != nil {
+		return fmt.Errorf("failed to update incoming transaction: %w", err)
+	}
+
+	t.Rate = rate
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsLocked checks if either transaction is reconciled and locked against edits
+func (t *Transfer) IsLocked() bool {
+	return t.Outgoing.IsLocked() || t.Incoming.IsLocked()
+}
+
+// Reverse undoes the transfer's effect on both account balances, e.g. before it is deleted
+func (t *Transfer) Reverse(source, destination *Account) error {
+	if err := t.checkAccounts(source, destination); err != nil {
+		return err
+	}
+
+	if err := source.CreditBalance(t.Outgoing.Amount.Abs()); err != nil {
+		return fmt.Errorf("failed to restore source account: %w", err)
+	}
+
+	if err := destination.CreditBalance(t.Incoming.Amount.Negate()); err != nil {
+		return fmt.Errorf("failed to restore destination account: %w", err)
+	}
+
+	return nil
+}
+
+func (t *Transfer) checkAccounts(source, destination *Account) error {
+	if source == nil || !source.ID.Equals(t.SourceAccountID()) {
+		return fmt.Errorf("source account does not match transfer %s", t.ID)
+	}
+
+	if destination == nil || !destination.ID.Equals(t.DestinationAccountID()) {
+		return fmt.Errorf("destination account does not match transfer %s", t.ID)
+	}
+
+	return nil
+}
+
+func validateTransferAccounts(source, destination *Account) error {
+	if source == nil {
+		return fmt.Errorf("source account cannot be empty")
+	}
+
+	if destination == nil {
+		return fmt.Errorf("destination account cannot be empty")
+	}
+
+	if source.ID.Equals(destination.ID) {
+		return fmt.Errorf("cannot transfer to the same account")
+	}
+
+	if !source.LedgerID.Equals(destination.LedgerID) {
+		return fmt.Errorf("cannot transfer between accounts of different ledgers")
+	}
+
+	if !source.Status.IsActive() {
+		return fmt.Errorf("source account is not active")
+	}
+
+	if !destination.Status.IsActive() {
+		return fmt.Errorf("destination account is not active")
+	}
+
+	return nil
+}
+
+func newTransferRate(
+	from, to money.Currency,
+	fxRate optional.Option[decimal.Decimal],
+	date time.Time,
+) (optional.Option[money.Rate], error) {
+	if from == to {
+		if fxRate.IsSome() {
+			return optional.None[money.Rate](), fmt.Errorf("exchange rate must not be set when both accounts use %s", from)
+		}
+		return optional.None[money.Rate](), nil
+	}
+
+	if fxRate.IsNone() {
+		return optional.None[money.Rate](), fmt.Errorf("exchange rate is required to transfer from %s to %s", from, to)
+	}
+
+	rate, err := money.NewRate(from, to, fxRate.Unwrap(), date)
+	if err != nil {
+		return optional.None[money.Rate](), fmt.Errorf("invalid exchange rate: %w", err)
+	}
+
+	return optional.Some(rate), nil
+}
+
+func transferIncomingAmount(source *Account, amount money.Money, rate optional.Option[money.Rate]) (money.Money, error) {
+	if amount.Currency != source.Currency {
+		return money.Money{}, fmt.Errorf("currency mismatch: source account uses %s, transfer uses %s", source.Currency, amount.Currency)
+	}
+
+	if !amount.IsPositive() {
+		return money.Money{}, fmt.Errorf("transfer amount must be positive")
+	}
+
+	if err := amount.ValidatePrecision(); err != nil {
+		return money.Money{}, fmt.Errorf("invalid transfer amount: %w", err)
+	}
+
+	if rate.IsNone() {
+		return amount, nil
+	}
+
+	incoming, err := rate.Unwrap().Apply(amount, money.RoundHalfUp)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("failed to convert transfer amount: %w", err)
+	}
+
+	if incoming.IsZero() {
+		return money.Money{}, fmt.Errorf("transfer amount %s converts to zero %s", amount, incoming.Currency)
+	}
+
+	return incoming, nil
+}
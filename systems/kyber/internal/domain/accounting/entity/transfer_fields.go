@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/kneadCODE/coruscant/shared/golib/id"
+)
+
+// TransferID represents a unique identifier for a transfer using UUIDv7
+type TransferID struct {
+	id.EntityID
+}
+
+// NewTransferID creates a new TransferID using UUIDv7
+func NewTransferID() (TransferID, error) {
+	base, err := id.NewEntityID()
+	if err != nil {
+		return TransferID{}, fmt.Errorf("failed to create transfer ID: %w", err)
+	}
+	return TransferID{EntityID: base}, nil
+}
+
+// NewTransferIDFromString creates a TransferID from an existing string
+func NewTransferIDFromString(idStr string) (TransferID, error) {
+	base, err := id.NewEntityIDFromString(idStr)
+	if err != nil {
+		return TransferID{}, fmt.Errorf("failed to create transfer ID: %w", err)
+	}
+	return TransferID{EntityID: base}, nil
+}
+
+// Equals checks if two TransferIDs are equal
+func (t TransferID) Equals(other TransferID) bool {
+	return t.EntityID.Equals(other.EntityID)
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferID_NewTransferID(t *testing.T) {
+	id, err := NewTransferID()
+
+	require.NoError(t, err)
+	assert.True(t, id.IsValid())
+	assert.NotEmpty(t, id.String())
+}
+
+func TestTransferID_NewTransferIDFromString(t *testing.T) {
+	validID, err := NewTransferID()
+	require.NoError(t, err)
+
+	parsed, err := NewTransferIDFromString(validID.String())
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(validID))
+
+	_, err = NewTransferIDFromString("invalid-uuid")
+	assert.Error(t, err)
+}
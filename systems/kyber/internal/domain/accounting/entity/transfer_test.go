@@ -0,0 +1,469 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestNewTransfer(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	otherLedgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	noRate := optional.None[decimal.Decimal]()
+	rate := optional.Some(decimal.RequireFromString("1.3412"))
+
+	tests := []struct {
+		name            string
+		source          func(t *testing.T) *Account
+		destination     func(t *testing.T) *Account
+		amount          money.Money
+		fxRate          optional.Option[decimal.Decimal]
+		wantErr         bool
+		errContains     string
+		wantIncoming    string
+		wantSource      string
+		wantDestination string
+	}{
+		{
+			name:            "same currency",
+			source:          fundedAccount(ledgerID, AccountTypeChecking, "USD", "500.00"),
+			destination:     fundedAccount(ledgerID, AccountTypeSavings, "USD", "0"),
+			amount:          mustMoney(t, "200.00", "USD"),
+			fxRate:          noRate,
+			wantIncoming:    "200.00 USD",
+			wantSource:      "300.00 USD",
+			wantDestination: "200.00 USD",
+		},
+		{
+			name:            "cross currency with explicit rate",
+			source:          fundedAccount(ledgerID, AccountTypeChecking, "USD", "500.00"),
+			destination:     fundedAccount(ledgerID, AccountTypeSavings, "SGD", "10.00"),
+			amount:          mustMoney(t, "100.25", "USD"),
+			fxRate:          rate,
+			wantIncoming:    "134.46 SGD",
+			wantSource:      "399.75 USD",
+			wantDestination: "144.46 SGD",
+		},
+		{
+			name:            "liability source can go negative",
+			source:          fundedAccount(ledgerID, AccountTypeCreditCard, "USD", "0"),
+			destination:     fundedAccount(ledgerID, AccountTypeChecking, "USD", "0"),
+			amount:          mustMoney(t, "50.00", "USD"),
+			fxRate:          noRate,
+			wantIncoming:    "50.00 USD",
+			wantSource:      "-50.00 USD",
+			wantDestination: "50.00 USD",
+		},
+		{
+			name:        "insufficient balance",
+			source:      fundedAccount(ledgerID, AccountTypeChecking, "USD", "10.00"),
+			destination: fundedAccount(ledgerID, AccountTypeSavings, "USD", "0"),
+			amount:      mustMoney(t, "10.01", "USD"),
+			fxRate:      noRate,
+			wantErr:     true,
+			errContains: "insufficient balance in source account",
+		},
+		{
+			name:        "missing rate for cross currency",
+			source:      fundedAccount(ledgerID, AccountTypeChecking, "USD", "100.00"),
+			destination: fundedAccount(ledgerID, AccountTypeSavings, "SGD", "0"),
+			amount:      mustMoney(t, "10.00", "USD"),
+			fxRate:      noRate,
+			wantErr:     true,
+			errContains: "exchange rate is required to transfer from USD to SGD",
+		},
+		{
+			name:        "rate for same currency",
+			source:      fundedAccount(ledgerID, AccountTypeChecking, "USD", "100.00"),
+			destination: fundedAccount(ledgerID, AccountTypeSavings, "USD", "0"),
+			amount:      mustMoney(t, "10.00", "USD"),
+			fxRate:      rate,
+			wantErr:     true,
+			errContains: "exchange rate must not be set",
+		},
+		{
+			name:        "non-positive rate",
+			source:      fundedAccount(ledgerID, AccountTypeChecking, "USD", "100.00"),
+			destination: fundedAccount(ledgerID, AccountTypeSavings, "SGD", "0"),
+			amount:      mustMoney(t, "10.00", "USD"),
+			fxRate:      optional.Some(decimal.Zero),
+			wantErr:     true,
+			errContains: "invalid exchange rate",
+		},
+		{
+			name:        "amount in wrong currency",
+			source:      fundedAccount(ledgerID, AccountTypeChecking, "USD", "100.00"),
+			destination: fundedAccount(ledgerID, AccountTypeSavings, "USD", "0"),
+			amount:      mustMoney(t, "10.00", "EUR"),
+			fxRate:      noRate,
+			wantErr:     true,
+			errContains: "currency mismatch",
+		},
+		{
+			name:        "negative amount",
+			source:      fundedAccount(ledgerID, AccountTypeChecking, "USD", "100.00"),
+			destination: fundedAccount(ledgerID, AccountTypeSavings, "USD", "0"),
+			amount:      mustMoney(t, "-10.00", "USD"),
+			fxRate:      noRate,
+			wantErr:     true,
+			errContains: "transfer amount must be positive",
+		},
+		{
+			name:        "converts to zero",
+			source:      fundedAccount(ledgerID, AccountTypeChecking, "USD", "100.00"),
+			destination: fundedAccount(ledgerID, AccountTypeSavings, "JPY", "0"),
+			amount:      mustMoney(t, "0.01", "USD"),
+			fxRate:      optional.Some(decimal.RequireFromString("0.5")),
+			wantErr:     true,
+			errContains: "converts to zero JPY",
+		},
+		{
+			name:        "different ledgers",
+			source:      fundedAccount(ledgerID, AccountTypeChecking, "USD", "100.00"),
+			destination: fundedAccount(otherLedgerID, AccountTypeSavings, "USD", "0"),
+			amount:      mustMoney(t, "10.00", "USD"),
+			fxRate:      noRate,
+			wantErr:     true,
+			errContains: "different ledgers",
+		},
+		{
+			name:   "archived destination",
+			source: fundedAccount(ledgerID, AccountTypeChecking, "USD", "100.00"),
+			destination: func(t *testing.T) *Account {
+				a := fundedAccount(ledgerID, AccountTypeSavings, "USD", "0")(t)
+				a.Archive()
+				return a
+			},
+			amount:      mustMoney(t, "10.00", "USD"),
+			fxRate:      noRate,
+			wantErr:     true,
+			errContains: "destination account is not active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.source(t)
+			destination := tt.destination(t)
+			sourceBalance := source.Balance
+			destinationBalance := destination.Balance
+
+			transfer, err := NewTransfer(source, destination, itemID, tt.amount, tt.fxRate, "Move savings", time.Now())
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Nil(t, transfer)
+				assert.True(t, source.Balance.Equals(sourceBalance))
+				assert.True(t, destination.Balance.Equals(destinationBalance))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, transfer.ID.IsValid())
+			assert.Equal(t, ledgerID, transfer.LedgerID)
+			assert.True(t, transfer.SourceAccountID().Equals(source.ID))
+			assert.True(t, transfer.DestinationAccountID().Equals(destination.ID))
+			assert.True(t, transfer.Outgoing.Amount.Equals(tt.amount.Negate()))
+			assert.True(t, transfer.Amount().Equals(tt.amount))
+			assert.Equal(t, tt.wantIncoming, transfer.IncomingAmount().String())
+			assert.Equal(t, tt.fxRate.IsSome(), transfer.IsCrossCurrency())
+
+			for _, tx := range []*Transaction{transfer.Outgoing, transfer.Incoming} {
+				assert.True(t, tx.IsTransfer())
+				assert.True(t, tx.TransferID.Unwrap().Equals(transfer.ID))
+				assert.True(t, tx.ItemID.Equals(itemID))
+				assert.Equal(t, "Move savings", tx.Description)
+			}
+			assert.True(t, transfer.Outgoing.IsDebit())
+			assert.True(t, transfer.Incoming.IsCredit())
+
+			assert.Equal(t, tt.wantSource, source.Balance.String())
+			assert.Equal(t, tt.wantDestination, destination.Balance.String())
+		})
+	}
+}
+
+func TestNewTransfer_SameAccount(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	account := fundedAccount(ledgerID, AccountTypeChecking, "USD", "100.00")(t)
+
+	_, err = NewTransfer(account, account, itemID, mustMoney(t, "1.00", "USD"), optional.None[decimal.Decimal](), "Loop", time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot transfer to the same account")
+}
+
+func TestTransfer_UpdateInfo(t *testing.T) {
+	transfer, _, _ := createTestTransfer(t)
+
+	err := transfer.UpdateInfo("Rent top-up", "March")
+	require.NoError(t, err)
+	assert.Equal(t, "Rent top-up", transfer.Outgoing.Description)
+	assert.Equal(t, "Rent top-up", transfer.Incoming.Description)
+	assert.Equal(t, "March", transfer.Outgoing.Notes)
+	assert.Equal(t, "March", transfer.Incoming.Notes)
+
+	err = transfer.UpdateInfo("", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "transfer description cannot be empty")
+	assert.Equal(t, "Rent top-up", transfer.Outgoing.Description)
+	assert.Equal(t, "Rent top-up", transfer.Incoming.Description)
+}
+
+func TestTransfer_UpdateTransferDate(t *testing.T) {
+	transfer, _, _ := createTestTransfer(t)
+	originalUpdatedAt := transfer.UpdatedAt
+	time.Sleep(time.Millisecond)
+
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, transfer.UpdateTransferDate(date))
+
+	assert.Equal(t, date, transfer.Outgoing.TransactionDate)
+	assert.Equal(t, date, transfer.Incoming.TransactionDate)
+	assert.True(t, transfer.UpdatedAt.After(originalUpdatedAt))
+}
+
+func TestTransfer_UpdateItem(t *testing.T) {
+	transfer, _, _ := createTestTransfer(t)
+
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	transfer.UpdateItem(itemID)
+	assert.True(t, transfer.Outgoing.ItemID.Equals(itemID))
+	assert.True(t, transfer.Incoming.ItemID.Equals(itemID))
+}
+
+func TestTransfer_UpdateAmount(t *testing.T) {
+	tests := []struct {
+		name            string
+		amount          string
+		wantErr         bool
+		errContains     string
+		wantSource      string
+		wantDestination string
+	}{
+		{name: "increase", amount: "500.00", wantSource: "0.00 USD", wantDestination: "500.00 USD"},
+		{name: "decrease", amount: "50.00", wantSource: "450.00 USD", wantDestination: "50.00 USD"},
+		{name: "exceeds balance before transfer", amount: "500.01", wantErr: true, errContains: "insufficient balance"},
+		{name: "zero", amount: "0", wantErr: true, errContains: "transfer amount must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer, source, destination := createTestTransfer(t)
+
+			err := transfer.UpdateAmount(source, destination, mustMoney(t, tt.amount, "USD"), optional.None[decimal.Decimal]())
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Equal(t, "400.00 USD", source.Balance.String())
+				assert.Equal(t, "100.00 USD", destination.Balance.String())
+				assert.Equal(t, "-100.00 USD", transfer.Outgoing.Amount.String())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSource, source.Balance.String())
+			assert.Equal(t, tt.wantDestination, destination.Balance.String())
+			assert.True(t, transfer.Amount().Equals(mustMoney(t, tt.amount, "USD")))
+			assert.True(t, transfer.IncomingAmount().Equals(mustMoney(t, tt.amount, "USD")))
+		})
+	}
+}
+
+func TestTransfer_UpdateAmount_CrossCurrency(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	source := fundedAccount(ledgerID, AccountTypeChecking, "USD", "100.00")(t)
+	destination := fundedAccount(ledgerID, AccountTypeSavings, "EUR", "0")(t)
+
+	transfer, err := NewTransfer(source, destination, itemID, mustMoney(t, "100.00", "USD"),
+		optional.Some(decimal.RequireFromString("0.9")), "To EUR", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "90.00 EUR", destination.Balance.String())
+
+	err = transfer.UpdateAmount(source, destination, mustMoney(t, "40.00", "USD"), optional.Some(decimal.RequireFromString("0.95")))
+	require.NoError(t, err)
+	assert.Equal(t, "60.00 USD", source.Balance.String())
+	assert.Equal(t, "38.00 EUR", destination.Balance.String())
+	assert.True(t, transfer.Rate.Unwrap().Value.Equal(decimal.RequireFromString("0.95")))
+
+	err = transfer.UpdateAmount(source, destination, mustMoney(t, "40.00", "USD"), optional.None[decimal.Decimal]())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exchange rate is required")
+}
+
+func TestTransfer_Reverse(t *testing.T) {
+	transfer, source, destination := createTestTransfer(t)
+
+	err := transfer.Reverse(destination, source)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "source account does not match transfer")
+
+	err = transfer.Reverse(source, destination)
+	require.NoError(t, err)
+	assert.Equal(t, "500.00 USD", source.Balance.String())
+	assert.Equal(t, "0.00 USD", destination.Balance.String())
+}
+
+func TestReconstructTransfer(t *testing.T) {
+	original, _, _ := createTestTransfer(t)
+
+	outgoing := *original.Outgoing
+	incoming := *original.Incoming
+	outgoing.TransferID = optional.None[TransferID]()
+	incoming.TransferID = optional.None[TransferID]()
+
+	transfer := ReconstructTransfer(original.ID, original.LedgerID, &outgoing, &incoming,
+		optional.None[money.Rate](), original.CreatedAt, original.UpdatedAt)
+
+	assert.True(t, transfer.ID.Equals(original.ID))
+	assert.True(t, transfer.Outgoing.TransferID.Unwrap().Equals(original.ID))
+	assert.True(t, transfer.Incoming.TransferID.Unwrap().Equals(original.ID))
+	assert.False(t, transfer.IsCrossCurrency())
+}
+
+func TestTransfer_Locked(t *testing.T) {
+	transfer, source, destination := createTestTransfer(t)
+
+	reconciliationID, err := NewReconciliationID()
+	require.NoError(t, err)
+	transfer.Incoming.markReconciled(reconciliationID)
+	assert.True(t, transfer.IsLocked())
+
+	err = transfer.UpdateAmount(source, destination, mustMoney(t, "50.00", "USD"), optional.None[decimal.Decimal]())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reconciled and locked")
+
+	originalDate := transfer.Outgoing.TransactionDate
+	err = transfer.UpdateTransferDate(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reconciled and locked")
+	assert.Equal(t, originalDate, transfer.Outgoing.TransactionDate)
+	assert.Equal(t, originalDate, transfer.Incoming.TransactionDate)
+
+	require.NoError(t, transfer.UpdateInfo("Still editable", ""))
+	assert.Equal(t, "400.00 USD", source.Balance.String())
+}
+
+func TestNewTransferFromTransaction(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	checking := fundedAccount(ledgerID, AccountTypeChecking, "USD", "500.00")(t)
+	savings := fundedAccount(ledgerID, AccountTypeSavings, "USD", "0")(t)
+
+	outflow, err := NewTransaction(ledgerID, checking.ID, itemID, mustMoney(t, "-200.00", "USD"), "To savings",
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	outflow.Notes = "Monthly"
+	require.NoError(t, outflow.AddTag("savings"))
+
+	transfer, err := NewTransferFromTransaction(outflow, checking, savings)
+	require.NoError(t, err)
+	assert.True(t, transfer.SourceAccountID().Equals(checking.ID))
+	assert.True(t, transfer.DestinationAccountID().Equals(savings.ID))
+	assert.Equal(t, "-200.00 USD", transfer.Outgoing.Amount.String())
+	assert.Equal(t, "Monthly", transfer.Outgoing.Notes)
+	assert.Equal(t, []string{"savings"}, transfer.Outgoing.Tags)
+	assert.Equal(t, "300.00 USD", checking.Balance.String())
+	assert.Equal(t, "200.00 USD", savings.Balance.String())
+
+	inflow, err := NewTransaction(ledgerID, savings.ID, itemID, mustMoney(t, "50.00", "USD"), "From checking",
+		time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	transfer, err = NewTransferFromTransaction(inflow, savings, checking)
+	require.NoError(t, err)
+	assert.True(t, transfer.SourceAccountID().Equals(checking.ID))
+	assert.True(t, transfer.DestinationAccountID().Equals(savings.ID))
+
+	_, err = NewTransferFromTransaction(inflow, checking, savings)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "account does not match transaction")
+}
+
+func TestNewTransferFromStatement(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	checking := fundedAccount(ledgerID, AccountTypeChecking, "USD", "100.00")(t)
+	savings := fundedAccount(ledgerID, AccountTypeSavings, "USD", "0")(t)
+
+	outflow, err := NewTransaction(ledgerID, checking.ID, itemID, mustMoney(t, "-200.00", "USD"), "To savings",
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	_, err = NewTransferFromTransaction(outflow, checking, savings)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient balance")
+
+	transfer, err := NewTransferFromStatement(outflow, checking, savings)
+	require.NoError(t, err)
+	assert.Equal(t, "-200.00 USD", transfer.Outgoing.Amount.String())
+	assert.Equal(t, "-100.00 USD", checking.Balance.String())
+	assert.Equal(t, "200.00 USD", savings.Balance.String())
+}
+
+// Helper functions
+
+func fundedAccount(ledgerID ledgerEntity.LedgerID, accountType AccountType, currency, balance string) func(t *testing.T) *Account {
+	return func(t *testing.T) *Account {
+		t.Helper()
+
+		account, err := NewAccount(ledgerID, "Account "+string(accountType)+" "+currency, "", accountType, money.Currency(currency))
+		require.NoError(t, err)
+
+		account.Balance = mustMoney(t, balance, currency)
+		return account
+	}
+}
+
+func createTestTransfer(t *testing.T) (*Transfer, *Account, *Account) {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	source := fundedAccount(ledgerID, AccountTypeChecking, "USD", "500.00")(t)
+	destination := fundedAccount(ledgerID, AccountTypeSavings, "USD", "0")(t)
+
+	transfer, err := NewTransfer(source, destination, itemID, mustMoney(t, "100.00", "USD"),
+		optional.None[decimal.Decimal](), "Savings", time.Now())
+	require.NoError(t, err)
+
+	return transfer, source, destination
+}
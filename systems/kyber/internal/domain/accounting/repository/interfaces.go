@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// ErrNotFound is returned when the requested entity does not exist in the ledger
+var ErrNotFound = errors.New("not found")
+
+// Transactor runs a unit of work atomically. Repository calls made with the ctx
+// passed to fn take part in the same database transaction.
+type Transactor interface {
+	// WithinTx commits if fn succeeds and rolls back if it returns an error
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// CommodityRepository persists the non-ISO commodities registered with the money package
+type CommodityRepository interface {
+	// Save stores the commodity, replacing its kind, symbol and name if the code already exists
+	Save(ctx context.Context, info money.CurrencyInfo) error
+	// List returns all stored commodities
+	List(ctx context.Context) ([]money.CurrencyInfo, error)
+}
+
+// AccountRepository persists accounts
+type AccountRepository interface {
+	// GetByID returns the account
+	GetByID(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.AccountID) (*entity.Account, error)
+	// ListByLedger returns all accounts of the ledger
+	ListByLedger(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Account, error)
+	// GetForUpdate returns the accounts in the given order, locking them until the surrounding transaction ends
+	GetForUpdate(ctx context.Context, ledgerID ledgerEntity.LedgerID, ids ...entity.AccountID) ([]*entity.Account, error)
+	// UpdateBalance stores the account's current balance
+	UpdateBalance(ctx context.Context, account *entity.Account) error
+}
+
+// TransactionRepository queries transaction history
+type TransactionRepository interface {
+	// SumByAccount returns the sum of the account's posted transactions dated before the cutoff,
+	// or of all its posted transactions if there is none
+	SumByAccount(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		accountID entity.AccountID,
+		before optional.Option[time.Time],
+	) (money.Money, error)
+	// ListByAccount returns the account's transactions dated within [from, to), oldest first
+	ListByAccount(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		accountID entity.AccountID,
+		from, to time.Time,
+	) ([]*entity.Transaction, error)
+	// Create stores a new transaction and its split lines
+	Create(ctx context.Context, transaction *entity.Transaction) error
+	// GetByID returns the transaction
+	GetByID(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.TransactionID) (*entity.Transaction, error)
+	// GetForUpdate returns the transaction, locking it until the surrounding transaction ends
+	GetForUpdate(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.TransactionID) (*entity.Transaction, error)
+	// ListUnreconciled returns the account's posted transactions dated before the cutoff that are not yet reconciled,
+	// oldest first
+	ListUnreconciled(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		accountID entity.AccountID,
+		before time.Time,
+	) ([]*entity.Transaction, error)
+	// ListImportIDs returns those of the given import IDs that the account's transactions already have
+	ListImportIDs(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		accountID entity.AccountID,
+		importIDs []string,
+	) ([]string, error)
+	// ListPending returns the ledger's pending holds, or only the account's if one is given, oldest first
+	ListPending(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		accountID optional.Option[entity.AccountID],
+	) ([]*entity.Transaction, error)
+	// UpdatePosting stores the transaction's posting status, amount, effective FX rate, date and import ID
+	UpdatePosting(ctx context.Context, transaction *entity.Transaction) error
+	// UpdateStatus stores the transaction's cleared status, reconciliation and lock
+	UpdateStatus(ctx context.Context, transaction *entity.Transaction) error
+	// UpdateSplits stores the transaction's budget item and split lines
+	UpdateSplits(ctx context.Context, transaction *entity.Transaction) error
+	// UpdateTags stores the transaction's tags
+	UpdateTags(ctx context.Context, transaction *entity.Transaction) error
+	// Search returns one page of the ledger's transactions matching the filter, newest first,
+	// with the count and per-currency sums of all matching transactions that are not voided (of only their
+	// split lines assigned to the item, when filtering by item)
+	Search(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		filter entity.TransactionFilter,
+		limit, offset int,
+	) (*entity.TransactionPage, error)
+	// SumByOriginalCurrency sums the ledger's transactions matching the filter per currency they were charged in,
+	// ordered by currency. Transfers and voided holds are left out.
+	SumByOriginalCurrency(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		filter entity.TransactionFilter,
+	) ([]entity.OriginalCurrencySpend, error)
+}
+
+// ReconciliationRepository persists statement reconciliations
+type ReconciliationRepository interface {
+	// Create stores a new reconciliation
+	Create(ctx context.Context, reconciliation *entity.Reconciliation) error
+	// Update stores the reconciliation's status
+	Update(ctx context.Context, reconciliation *entity.Reconciliation) error
+	// Delete removes the reconciliation
+	Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.ReconciliationID) error
+	// GetByID returns the reconciliation, without its transactions
+	GetByID(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.ReconciliationID) (*entity.Reconciliation, error)
+	// GetInProgress returns the account's unfinished reconciliation, if any
+	GetInProgress(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		accountID entity.AccountID,
+	) (optional.Option[*entity.Reconciliation], error)
+	// GetLatestFinished returns the account's finished reconciliation with the latest statement date, if any
+	GetLatestFinished(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		accountID entity.AccountID,
+	) (optional.Option[*entity.Reconciliation], error)
+	// ListLatestFinished returns the latest finished reconciliation of each account of the ledger
+	ListLatestFinished(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Reconciliation, error)
+}
+
+// ScheduledTransactionRepository persists scheduled transactions together with their changed or generated occurrences
+type ScheduledTransactionRepository interface {
+	// Create stores a new scheduled transaction
+	Create(ctx context.Context, schedule *entity.ScheduledTransaction) error
+	// Update stores the scheduled transaction and its occurrences
+	Update(ctx context.Context, schedule *entity.ScheduledTransaction) error
+	// Delete removes the scheduled transaction; transactions it generated are kept
+	Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.ScheduledTransactionID) error
+	// GetByID returns the scheduled transaction with its occurrences
+	GetByID(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		id entity.ScheduledTransactionID,
+	) (*entity.ScheduledTransaction, error)
+	// GetForUpdate returns the scheduled transaction, locking it until the surrounding transaction ends
+	GetForUpdate(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		id entity.ScheduledTransactionID,
+	) (*entity.ScheduledTransaction, error)
+	// ListActive returns the ledger's scheduled transactions that are not paused, with their occurrences
+	ListActive(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.ScheduledTransaction, error)
+}
+
+// CSVProfileRepository persists the CSV import profiles of accounts
+type CSVProfileRepository interface {
+	// Create stores a new CSV profile
+	Create(ctx context.Context, profile *entity.CSVProfile) error
+	// Update stores the CSV profile's name and format
+	Update(ctx context.Context, profile *entity.CSVProfile) error
+	// Delete removes the CSV profile
+	Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.CSVProfileID) error
+	// GetByID returns the CSV profile
+	GetByID(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.CSVProfileID) (*entity.CSVProfile, error)
+	// ListByAccount returns the account's CSV profiles ordered by name
+	ListByAccount(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		accountID entity.AccountID,
+	) ([]*entity.CSVProfile, error)
+}
+
+// RuleRepository persists the categorisation rules of ledgers
+type RuleRepository interface {
+	// Create stores a new rule
+	Create(ctx context.Context, rule *entity.Rule) error
+	// Update stores the rule's name, priority, conditions, actions and whether it is enabled
+	Update(ctx context.Context, rule *entity.Rule) error
+	// Delete removes the rule
+	Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.RuleID) error
+	// GetByID returns the rule
+	GetByID(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.RuleID) (*entity.Rule, error)
+	// ListByLedger returns all rules of the ledger in priority order
+	ListByLedger(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Rule, error)
+	// ListEnabled returns the ledger's enabled rules in priority order
+	ListEnabled(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Rule, error)
+}
+
+// CreditCardRepository persists the billing terms of credit card accounts
+type CreditCardRepository interface {
+	// Create stores the billing terms of a credit card account
+	Create(ctx context.Context, card *entity.CreditCard) error
+	// Update stores the card's closing day, payment due days, minimum payment and credit limit
+	Update(ctx context.Context, card *entity.CreditCard) error
+	// GetByAccount returns the billing terms of the credit card account
+	GetByAccount(ctx context.Context, ledgerID ledgerEntity.LedgerID, accountID entity.AccountID) (*entity.CreditCard, error)
+	// ListByLedger returns the billing terms of all credit card accounts of the ledger
+	ListByLedger(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.CreditCard, error)
+}
+
+// LoanRepository persists the terms of loan, mortgage and installment accounts with their rate changes
+type LoanRepository interface {
+	// Create stores the terms of a loan account and its initial rate
+	Create(ctx context.Context, loan *entity.Loan) error
+	// Update stores the loan's rates and the date interest accrues from
+	Update(ctx context.Context, loan *entity.Loan) error
+	// GetByAccount returns the terms of the loan account with its rates, oldest first
+	GetByAccount(ctx context.Context, ledgerID ledgerEntity.LedgerID, accountID entity.AccountID) (*entity.Loan, error)
+}
+
+// InvestmentActivityRepository persists the buys, sells, dividends, splits and fees of investment accounts
+type InvestmentActivityRepository interface {
+	// Create stores a new investment activity
+	Create(ctx context.Context, activity *entity.InvestmentActivity) error
+	// ListByAccount returns the activities of the investment account, oldest first
+	ListByAccount(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		accountID entity.AccountID,
+	) ([]*entity.InvestmentActivity, error)
+}
+
+// TransferRepository persists transfers together with their linked pair of transactions
+type TransferRepository interface {
+	// Create stores a new transfer and both of its transactions
+	Create(ctx context.Context, transfer *entity.Transfer) error
+	// Update stores the transfer and both of its transactions
+	Update(ctx context.Context, transfer *entity.Transfer) error
+	// Delete removes the transfer and both of its transactions
+	Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.TransferID) error
+	// GetByID returns the transfer with both of its transactions
+	GetByID(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.TransferID) (*entity.Transfer, error)
+	// GetForUpdate returns the transfer with both of its transactions, locking them until the surrounding transaction ends
+	GetForUpdate(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.TransferID) (*entity.Transfer, error)
+}
+
+// JournalEntryRepository persists journal entries together with their postings
+type JournalEntryRepository interface {
+	// Create stores a new journal entry and all of its postings
+	Create(ctx context.Context, entry *entity.JournalEntry) error
+	// Update replaces the journal entry and its postings
+	Update(ctx context.Context, entry *entity.JournalEntry) error
+	// Delete removes the journal entry and its postings
+	Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.JournalEntryID) error
+	// GetByID returns the journal entry with its postings
+	GetByID(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.JournalEntryID) (*entity.JournalEntry, error)
+	// GetBySourceTransaction returns the journal entry recorded from the transaction with its postings
+	GetBySourceTransaction(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		transactionID entity.TransactionID,
+	) (*entity.JournalEntry, error)
+	// ListByAccount returns entries with a posting against the account dated within [from, to)
+	ListByAccount(ctx context.Context, accountID entity.AccountID, from, to time.Time) ([]*entity.JournalEntry, error)
+	// ListByItem returns entries with a posting against the budget item dated within [from, to)
+	ListByItem(ctx context.Context, itemID budgetEntity.ItemID, from, to time.Time) ([]*entity.JournalEntry, error)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// BalanceService derives account balances from transaction history rather than the stored balance
+type BalanceService struct {
+	accounts     repository.AccountRepository
+	transactions repository.TransactionRepository
+}
+
+// NewBalanceService creates a new BalanceService
+func NewBalanceService(
+	accounts repository.AccountRepository,
+	transactions repository.TransactionRepository,
+) *BalanceService {
+	return &BalanceService{
+		accounts:     accounts,
+		transactions: transactions,
+	}
+}
+
+// Balances returns the account's current balance of posted transactions and its available balance,
+// which also takes pending outflows into account
+func (s *BalanceService) Balances(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (entity.AccountBalances, error) {
+	account, err := s.accounts.GetByID(ctx, ledgerID, accountID)
+	if err != nil {
+		return entity.AccountBalances{}, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	pending, err := s.transactions.ListPending(ctx, ledgerID, optional.Some(accountID))
+	if err != nil {
+		return entity.AccountBalances{}, fmt.Errorf("failed to list pending transactions: %w", err)
+	}
+
+	return entity.NewAccountBalances(account.ID, account.Balance, pending)
+}
+
+// BalanceAt returns the account balance at the given instant, i.e. the sum of all posted transactions dated before it
+func (s *BalanceService) BalanceAt(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	at time.Time,
+) (money.Money, error) {
+	balance, err := s.transactions.SumByAccount(ctx, ledgerID, accountID, optional.Some(at))
+	if err != nil {
+		return money.Money{}, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+	return balance, nil
+}
+
+// BalanceOn returns the account balance at the end of the given calendar day, in the date's location
+func (s *BalanceService) BalanceOn(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	date time.Time,
+) (money.Money, error) {
+	return s.BalanceAt(ctx, ledgerID, accountID, startOfDay(date).AddDate(0, 0, 1))
+}
+
+// RunningBalances returns the account's transactions dated within [from, to), oldest first,
+// each with the balance right after it
+func (s *BalanceService) RunningBalances(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	from, to time.Time,
+) ([]entity.BalanceEntry, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("invalid date range: %s is not before %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	opening, err := s.BalanceAt(ctx, ledgerID, accountID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.transactions.ListByAccount(ctx, ledgerID, accountID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return entity.RunningBalances(opening, transactions)
+}
+
+// CheckDrift compares the account's stored balance with the sum of all its transactions
+func (s *BalanceService) CheckDrift(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (entity.BalanceDrift, error) {
+	account, err := s.accounts.GetByID(ctx, ledgerID, accountID)
+	if err != nil {
+		return entity.BalanceDrift{}, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	return s.checkDrift(ctx, account)
+}
+
+// FindDrift returns the accounts of the ledger whose stored balance differs from their transaction sum
+func (s *BalanceService) FindDrift(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]entity.BalanceDrift, error) {
+	accounts, err := s.accounts.ListByLedger(ctx, ledgerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	var drifts []entity.BalanceDrift
+	for _, account := range accounts {
+		drift, err := s.checkDrift(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		if drift.HasDrift() {
+			drifts = append(drifts, drift)
+		}
+	}
+	return drifts, nil
+}
+
+func (s *BalanceService) checkDrift(ctx context.Context, account *entity.Account) (entity.BalanceDrift, error) {
+	derived, err := s.transactions.SumByAccount(ctx, account.LedgerID, account.ID, optional.None[time.Time]())
+	if err != nil {
+		return entity.BalanceDrift{}, fmt.Errorf("failed to sum transactions of account %s: %w", account.ID, err)
+	}
+
+	return account.CheckDrift(derived)
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
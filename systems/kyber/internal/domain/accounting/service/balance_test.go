@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestBalanceService_BalanceAt(t *testing.T) {
+	f := newBalanceFixture(t)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want string
+	}{
+		{name: "before any transaction", at: date(2024, 2, 1), want: "0.00 USD"},
+		{name: "excludes transactions at the instant", at: date(2024, 3, 5), want: "1000.00 USD"},
+		{name: "after some transactions", at: date(2024, 3, 20), want: "950.00 USD"},
+		{name: "after all transactions", at: date(2025, 1, 1), want: "1150.00 USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balance, err := f.service.BalanceAt(context.Background(), f.ledgerID, f.account.ID, tt.at)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, balance.String())
+		})
+	}
+
+	_, err := f.service.BalanceAt(context.Background(), f.ledgerID, mustAccountID(t), date(2024, 3, 1))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestBalanceService_BalanceOn(t *testing.T) {
+	f := newBalanceFixture(t)
+
+	balance, err := f.service.BalanceOn(context.Background(), f.ledgerID, f.account.ID, date(2024, 3, 5))
+	require.NoError(t, err)
+	assert.Equal(t, "850.00 USD", balance.String())
+
+	balance, err = f.service.BalanceOn(context.Background(), f.ledgerID, f.account.ID, date(2024, 3, 31).Add(15*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "1150.00 USD", balance.String())
+}
+
+func TestBalanceService_RunningBalances(t *testing.T) {
+	f := newBalanceFixture(t)
+
+	entries, err := f.service.RunningBalances(context.Background(), f.ledgerID, f.account.ID, date(2024, 3, 2), date(2024, 4, 1))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	want := []string{"850.00 USD", "950.00 USD", "1150.00 USD"}
+	for i, entry := range entries {
+		assert.Equal(t, want[i], entry.Balance.String())
+	}
+	assert.Equal(t, "-150.00 USD", entries[0].Transaction.Amount.String())
+
+	_, err = f.service.RunningBalances(context.Background(), f.ledgerID, f.account.ID, date(2024, 4, 1), date(2024, 4, 1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid date range")
+}
+
+func TestBalanceService_CheckDrift(t *testing.T) {
+	f := newBalanceFixture(t)
+
+	drift, err := f.service.CheckDrift(context.Background(), f.ledgerID, f.account.ID)
+	require.NoError(t, err)
+	assert.False(t, drift.HasDrift())
+
+	stored := *f.account
+	stored.Balance = mustMoney(t, "1100.00", money.CurrencyUSD)
+	f.accounts[f.account.ID.String()] = &stored
+
+	drift, err = f.service.CheckDrift(context.Background(), f.ledgerID, f.account.ID)
+	require.NoError(t, err)
+	assert.True(t, drift.HasDrift())
+	assert.Equal(t, "1150.00 USD", drift.Derived.String())
+	assert.Equal(t, "-50.00 USD", drift.Difference.String())
+}
+
+func TestBalanceService_FindDrift(t *testing.T) {
+	f := newBalanceFixture(t)
+
+	other, err := entity.NewAccount(f.ledgerID, "Savings", "", entity.AccountTypeSavings, money.CurrencyUSD)
+	require.NoError(t, err)
+	other.Balance = mustMoney(t, "5.00", money.CurrencyUSD)
+	f.accounts[other.ID.String()] = other
+
+	drifts, err := f.service.FindDrift(context.Background(), f.ledgerID)
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	assert.True(t, drifts[0].AccountID.Equals(other.ID))
+	assert.Equal(t, "5.00 USD", drifts[0].Difference.String())
+}
+
+func TestBalanceService_Balances(t *testing.T) {
+	f := newBalanceFixture(t)
+
+	for _, amount := range []string{"-80.00", "25.00"} {
+		hold, err := entity.NewTransaction(f.ledgerID, f.account.ID, f.itemID, mustMoney(t, amount, money.CurrencyUSD), "Hold", date(2024, 4, 2))
+		require.NoError(t, err)
+		require.NoError(t, hold.MarkPending())
+		f.transactions.stored = append(f.transactions.stored, hold)
+	}
+
+	balances, err := f.service.Balances(context.Background(), f.ledgerID, f.account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "1150.00 USD", balances.Current.String())
+	assert.Equal(t, "-55.00 USD", balances.Pending.String())
+	assert.Equal(t, "1070.00 USD", balances.Available.String())
+
+	drift, err := f.service.CheckDrift(context.Background(), f.ledgerID, f.account.ID)
+	require.NoError(t, err)
+	assert.False(t, drift.HasDrift(), "holds are not part of the derived balance")
+
+	_, err = f.service.Balances(context.Background(), f.ledgerID, mustAccountID(t))
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+// Helper functions
+
+type balanceFixture struct {
+	ledgerID     ledgerEntity.LedgerID
+	account      *entity.Account
+	itemID       budgetEntity.ItemID
+	accounts     fakeAccountRepository
+	transactions *fakeTransactionRepository
+	service      *BalanceService
+}
+
+func newBalanceFixture(t *testing.T) *balanceFixture {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	account, err := entity.NewAccount(ledgerID, "Checking", "", entity.AccountTypeChecking, money.CurrencyUSD)
+	require.NoError(t, err)
+	account.Balance = mustMoney(t, "1150.00", money.CurrencyUSD)
+
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	accounts := fakeAccountRepository{account.ID.String(): account}
+	transactions := &fakeTransactionRepository{accounts: accounts}
+	for _, tx := range []struct {
+		amount string
+		date   time.Time
+	}{
+		{amount: "1000.00", date: date(2024, 3, 1)},
+		{amount: "200.00", date: date(2024, 3, 31)},
+		{amount: "-150.00", date: date(2024, 3, 5)},
+		{amount: "100.00", date: date(2024, 3, 15)},
+	} {
+		created, err := entity.NewTransaction(ledgerID, account.ID, itemID, mustMoney(t, tx.amount, money.CurrencyUSD), "Test", tx.date)
+		require.NoError(t, err)
+		transactions.stored = append(transactions.stored, created)
+	}
+
+	return &balanceFixture{
+		ledgerID:     ledgerID,
+		account:      account,
+		itemID:       itemID,
+		accounts:     accounts,
+		transactions: transactions,
+		service:      NewBalanceService(accounts, transactions),
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func mustMoney(t *testing.T, amount string, currency money.Currency) money.Money {
+	t.Helper()
+
+	m, err := money.NewMoney(amount, currency)
+	require.NoError(t, err)
+	return m
+}
+
+func mustAccountID(t *testing.T) entity.AccountID {
+	t.Helper()
+
+	id, err := entity.NewAccountID()
+	require.NoError(t, err)
+	return id
+}
+
+type fakeAccountRepository map[string]*entity.Account
+
+func (f fakeAccountRepository) GetByID(_ context.Context, _ ledgerEntity.LedgerID, id entity.AccountID) (*entity.Account, error) {
+	a, ok := f[id.String()]
+	if !ok {
+		return nil, fmt.Errorf("account %s: %w", id, repository.ErrNotFound)
+	}
+	return a, nil
+}
+
+func (f fakeAccountRepository) ListByLedger(_ context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Account, error) {
+	var accounts []*entity.Account
+	for _, a := range f {
+		if a.LedgerID.Equals(ledgerID) {
+			accounts = append(accounts, a)
+		}
+	}
+	return accounts, nil
+}
+
+func (f fakeAccountRepository) GetForUpdate(ctx context.Context, ledgerID ledgerEntity.LedgerID, ids ...entity.AccountID) ([]*entity.Account, error) {
+	accounts := make([]*entity.Account, 0, len(ids))
+	for _, id := range ids {
+		a, err := f.GetByID(ctx, ledgerID, id)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, nil
+}
+
+func (f fakeAccountRepository) UpdateBalance(_ context.Context, account *entity.Account) error {
+	f[account.ID.String()] = account
+	return nil
+}
+
+type fakeTransactionRepository struct {
+	accounts fakeAccountRepository
+	stored   []*entity.Transaction
+}
+
+func (f *fakeTransactionRepository) SumByAccount(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	before optional.Option[time.Time],
+) (money.Money, error) {
+	account, ok := f.accounts[accountID.String()]
+	if !ok {
+		return money.Money{}, fmt.Errorf("account %s: %w", accountID, repository.ErrNotFound)
+	}
+
+	sum, err := money.Zero(account.Currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	for _, tx := range f.stored {
+		if !tx.AccountID.Equals(accountID) || !tx.PostingStatus.IsPosted() ||
+			(before.IsSome() && !tx.TransactionDate.Before(before.Unwrap())) {
+			continue
+		}
+		if sum, err = sum.Add(tx.Amount); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return sum, nil
+}
+
+func (f *fakeTransactionRepository) ListByAccount(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	from, to time.Time,
+) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	for _, tx := range f.stored {
+		if tx.AccountID.Equals(accountID) && !tx.TransactionDate.Before(from) && tx.TransactionDate.Before(to) {
+			transactions = append(transactions, tx)
+		}
+	}
+
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].TransactionDate.Before(transactions[j].TransactionDate)
+	})
+	return transactions, nil
+}
+
+func (f *fakeTransactionRepository) GetByID(_ context.Context, _ ledgerEntity.LedgerID, id entity.TransactionID) (*entity.Transaction, error) {
+	for _, tx := range f.stored {
+		if tx.ID.Equals(id) {
+			return tx, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction %s: %w", id, repository.ErrNotFound)
+}
+
+func (f *fakeTransactionRepository) GetForUpdate(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+) (*entity.Transaction, error) {
+	return f.GetByID(ctx, ledgerID, id)
+}
+
+func (f *fakeTransactionRepository) ListUnreconciled(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	before time.Time,
+) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	for _, tx := range f.stored {
+		if tx.AccountID.Equals(accountID) && !tx.Status.IsReconciled() && tx.TransactionDate.Before(before) {
+			transactions = append(transactions, tx)
+		}
+	}
+	return transactions, nil
+}
+
+func (f *fakeTransactionRepository) ListPending(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	accountID optional.Option[entity.AccountID],
+) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	for _, tx := range f.stored {
+		if tx.PostingStatus.IsPending() && (accountID.IsNone() || accountID.Unwrap().Equals(tx.AccountID)) {
+			transactions = append(transactions, tx)
+		}
+	}
+	return transactions, nil
+}
+
+func (f *fakeTransactionRepository) UpdatePosting(_ context.Context, _ *entity.Transaction) error {
+	return nil
+}
+
+func (f *fakeTransactionRepository) UpdateStatus(_ context.Context, _ *entity.Transaction) error {
+	return nil
+}
+
+func (f *fakeTransactionRepository) UpdateSplits(_ context.Context, _ *entity.Transaction) error {
+	return nil
+}
+
+func (f *fakeTransactionRepository) UpdateTags(_ context.Context, _ *entity.Transaction) error {
+	return nil
+}
+
+func (f *fakeTransactionRepository) Search(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	_ entity.TransactionFilter,
+	_, _ int,
+) (*entity.TransactionPage, error) {
+	return &entity.TransactionPage{}, nil
+}
+
+func (f *fakeTransactionRepository) SumByOriginalCurrency(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	_ entity.TransactionFilter,
+) ([]entity.OriginalCurrencySpend, error) {
+	return nil, nil
+}
+
+func (f *fakeTransactionRepository) Create(_ context.Context, tx *entity.Transaction) error {
+	f.stored = append(f.stored, tx)
+	return nil
+}
+
+func (f *fakeTransactionRepository) ListImportIDs(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	_ entity.AccountID,
+	_ []string,
+) ([]string, error) {
+	return nil, nil
+}
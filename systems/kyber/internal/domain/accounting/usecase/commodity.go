@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// CommodityUseCase persists the crypto, points and security commodities accounts can be held in and
+// registers them with the money package. LoadCommodities must run on startup before any stored amount
+// in a custom commodity is decoded.
+type CommodityUseCase struct {
+	commodities repository.CommodityRepository
+}
+
+// NewCommodityUseCase creates a new CommodityUseCase
+func NewCommodityUseCase(commodities repository.CommodityRepository) *CommodityUseCase {
+	return &CommodityUseCase{commodities: commodities}
+}
+
+// RegisterCommodity stores the commodity and makes its code usable as a currency. The precision of an
+// already registered commodity cannot change, since its amounts are stored in minor units.
+func (u *CommodityUseCase) RegisterCommodity(ctx context.Context, info money.CurrencyInfo) (money.CurrencyInfo, error) {
+	info, err := money.ValidateCommodity(info)
+	if err != nil {
+		return money.CurrencyInfo{}, err
+	}
+
+	if existing, ok := money.LookupCurrency(info.Code); ok && existing.MinorUnits != info.MinorUnits {
+		return money.CurrencyInfo{}, fmt.Errorf("cannot change the precision of commodity %s from %d to %d",
+			info.Code, existing.MinorUnits, info.MinorUnits)
+	}
+
+	if err := u.commodities.Save(ctx, info); err != nil {
+		return money.CurrencyInfo{}, err
+	}
+
+	if err := money.RegisterCommodity(info); err != nil {
+		return money.CurrencyInfo{}, err
+	}
+	return info, nil
+}
+
+// LoadCommodities registers all stored commodities with the money package
+func (u *CommodityUseCase) LoadCommodities(ctx context.Context) error {
+	commodities, err := u.commodities.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range commodities {
+		if err := money.RegisterCommodity(info); err != nil {
+			return fmt.Errorf("failed to register commodity %s: %w", info.Code, err)
+		}
+	}
+	return nil
+}
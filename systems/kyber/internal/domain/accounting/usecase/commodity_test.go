@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestCommodityUseCase_RegisterCommodity(t *testing.T) {
+	stored := fakeCommodityRepository{}
+	useCase := NewCommodityUseCase(stored)
+	ctx := context.Background()
+
+	info, err := useCase.RegisterCommodity(ctx, money.CurrencyInfo{
+		Code: "uc_miles", Kind: money.CommodityKindPoints, MinorUnits: 0, Name: "Use Case Miles",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, money.Currency("UC_MILES"), info.Code)
+	assert.Contains(t, stored, "UC_MILES")
+
+	c, err := money.NewCurrency("UC_MILES")
+	require.NoError(t, err)
+	assert.Equal(t, money.CommodityKindPoints, c.Kind())
+
+	// Renaming keeps the stored amounts valid
+	_, err = useCase.RegisterCommodity(ctx, money.CurrencyInfo{
+		Code: "UC_MILES", Kind: money.CommodityKindPoints, MinorUnits: 0, Name: "Renamed Miles",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed Miles", stored["UC_MILES"].Name)
+
+	_, err = useCase.RegisterCommodity(ctx, money.CurrencyInfo{
+		Code: "UC_MILES", Kind: money.CommodityKindPoints, MinorUnits: 2, Name: "Use Case Miles",
+	})
+	assert.ErrorContains(t, err, "cannot change the precision of commodity UC_MILES")
+
+	_, err = useCase.RegisterCommodity(ctx, money.CurrencyInfo{
+		Code: "UC_WEI", Kind: money.CommodityKindCrypto, MinorUnits: 18, Name: "Wei",
+	})
+	require.NoError(t, err, "18-decimal tokens are supported")
+	assert.Contains(t, stored, "UC_WEI")
+
+	_, err = useCase.RegisterCommodity(ctx, money.CurrencyInfo{
+		Code: "UC_TINY", Kind: money.CommodityKindCrypto, MinorUnits: 19, Name: "Tiny",
+	})
+	assert.ErrorContains(t, err, "minor units must be between 0 and 18")
+	assert.NotContains(t, stored, "UC_TINY")
+}
+
+func TestCommodityUseCase_LoadCommodities(t *testing.T) {
+	stored := fakeCommodityRepository{
+		"UC_FUND": {Code: "UC_FUND", Kind: money.CommodityKindSecurity, MinorUnits: 4, Name: "Use Case Fund"},
+	}
+	_, err := money.NewCurrency("UC_FUND")
+	require.Error(t, err, "not registered before loading")
+
+	require.NoError(t, NewCommodityUseCase(stored).LoadCommodities(context.Background()))
+
+	fund, err := money.NewMoney("12.3456", "UC_FUND")
+	require.NoError(t, err)
+	units, err := fund.ToMinorUnits()
+	require.NoError(t, err)
+	assert.Equal(t, int64(123456), units)
+}
+
+type fakeCommodityRepository map[string]money.CurrencyInfo
+
+func (f fakeCommodityRepository) Save(_ context.Context, info money.CurrencyInfo) error {
+	f[info.Code.String()] = info
+	return nil
+}
+
+func (f fakeCommodityRepository) List(context.Context) ([]money.CurrencyInfo, error) {
+	commodities := make([]money.CurrencyInfo, 0, len(f))
+	for _, info := range f {
+		commodities = append(commodities, info)
+	}
+	return commodities, nil
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// defaultCycleCount is how many closed cycles ListCycles returns when no count is given
+const defaultCycleCount = 12
+
+// CreditCardInput describes the billing terms of a credit card account
+type CreditCardInput struct {
+	LedgerID       ledgerEntity.LedgerID
+	AccountID      entity.AccountID
+	ClosingDay     int // Day of the month statements close on
+	PaymentDueDays int // Days from the closing date to the payment due date
+	MinimumPayment entity.MinimumPayment
+	CreditLimit    money.Money
+}
+
+// ListCyclesInput describes which statement cycles of a credit card to list
+type ListCyclesInput struct {
+	LedgerID  ledgerEntity.LedgerID
+	AccountID entity.AccountID
+	Count     int // Closed cycles before the open one; 12 if zero
+	Now       time.Time
+}
+
+// CreditCardUseCase manages the billing terms of credit card accounts and reports their statement cycles:
+// statement balance, minimum payment, due date, the payments that settle each statement and overdue ones.
+type CreditCardUseCase struct {
+	accounts     repository.AccountRepository
+	transactions repository.TransactionRepository
+	cards        repository.CreditCardRepository
+}
+
+// NewCreditCardUseCase creates a new CreditCardUseCase
+func NewCreditCardUseCase(
+	accounts repository.AccountRepository,
+	transactions repository.TransactionRepository,
+	cards repository.CreditCardRepository,
+) *CreditCardUseCase {
+	return &CreditCardUseCase{
+		accounts:     accounts,
+		transactions: transactions,
+		cards:        cards,
+	}
+}
+
+// CreateCreditCard sets up the billing terms of a credit card account
+func (u *CreditCardUseCase) CreateCreditCard(ctx context.Context, in CreditCardInput) (*entity.CreditCard, error) {
+	account, err := u.accounts.GetByID(ctx, in.LedgerID, in.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	card, err := entity.NewCreditCard(account, in.ClosingDay, in.PaymentDueDays, in.MinimumPayment, in.CreditLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.cards.Create(ctx, card); err != nil {
+		return nil, fmt.Errorf("failed to create credit card: %w", err)
+	}
+
+	return card, nil
+}
+
+// UpdateCreditCard changes the billing terms of a credit card account
+func (u *CreditCardUseCase) UpdateCreditCard(ctx context.Context, in CreditCardInput) (*entity.CreditCard, error) {
+	card, err := u.cards.GetByAccount(ctx, in.LedgerID, in.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit card: %w", err)
+	}
+
+	if err := card.UpdateTerms(in.ClosingDay, in.PaymentDueDays, in.MinimumPayment, in.CreditLimit); err != nil {
+		return nil, err
+	}
+
+	if err := u.cards.Update(ctx, card); err != nil {
+		return nil, fmt.Errorf("failed to update credit card: %w", err)
+	}
+
+	return card, nil
+}
+
+// GetCreditCard returns the billing terms of a credit card account
+func (u *CreditCardUseCase) GetCreditCard(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (*entity.CreditCard, error) {
+	card, err := u.cards.GetByAccount(ctx, ledgerID, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit card: %w", err)
+	}
+	return card, nil
+}
+
+// AvailableCredit returns how much of the card's credit limit is left after its balance and pending holds
+func (u *CreditCardUseCase) AvailableCredit(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (money.Money, error) {
+	card, err := u.GetCreditCard(ctx, ledgerID, accountID)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	account, err := u.accounts.GetByID(ctx, ledgerID, accountID)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	pending, err := u.transactions.ListPending(ctx, ledgerID, optional.Some(accountID))
+	if err != nil {
+		return money.Money{}, fmt.Errorf("failed to list pending transactions: %w", err)
+	}
+
+	balances, err := entity.NewAccountBalances(account.ID, account.Balance, pending)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	return card.AvailableCredit(balances.Available)
+}
+
+// ListCycles returns the card's latest closed statement cycles followed by the open one, oldest first
+func (u *CreditCardUseCase) ListCycles(ctx context.Context, in ListCyclesInput) ([]*entity.CreditCardCycle, error) {
+	if in.Count < 0 {
+		return nil, fmt.Errorf("cycle count cannot be negative: %d", in.Count)
+	}
+
+	count := in.Count
+	if count == 0 {
+		count = defaultCycleCount
+	}
+
+	card, err := u.GetCreditCard(ctx, in.LedgerID, in.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.cycles(ctx, card, count, in.Now)
+}
+
+// ListOverdueCycles returns, for each credit card of the ledger, every closed cycle among the last defaultCycleCount
+// that passed its due date without the minimum payment and has not been paid off by later payments, oldest first
+func (u *CreditCardUseCase) ListOverdueCycles(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	now time.Time,
+) ([]*entity.CreditCardCycle, error) {
+	cards, err := u.cards.ListByLedger(ctx, ledgerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit cards: %w", err)
+	}
+
+	var overdue []*entity.CreditCardCycle
+	for _, card := range cards {
+		cycles, err := u.cycles(ctx, card, defaultCycleCount, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive cycles of account %s: %w", card.AccountID, err)
+		}
+
+		overdue = append(overdue, entity.OverdueCycles(cycles)...)
+	}
+	return overdue, nil
+}
+
+// cycles derives count closed cycles of the card before the open one at now
+func (u *CreditCardUseCase) cycles(
+	ctx context.Context,
+	card *entity.CreditCard,
+	count int,
+	now time.Time,
+) ([]*entity.CreditCardCycle, error) {
+	start, closing := card.CycleAt(now)
+	for range count {
+		start, _ = card.CycleAt(start.AddDate(0, 0, -1))
+	}
+
+	opening, err := u.transactions.SumByAccount(ctx, card.LedgerID, card.AccountID, optional.Some(start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+
+	transactions, err := u.transactions.ListByAccount(ctx, card.LedgerID, card.AccountID, start, closing.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return card.Cycles(opening, start, transactions, now)
+}
@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestCreditCardUseCase_CreateAndUpdate(t *testing.T) {
+	f := newCreditCardFixture(t)
+	ctx := context.Background()
+
+	in := f.input(t)
+	in.AccountID = f.checking.ID
+	_, err := f.useCase.CreateCreditCard(ctx, in)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a credit card")
+
+	_, err = f.useCase.UpdateCreditCard(ctx, f.input(t))
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	card, err := f.useCase.CreateCreditCard(ctx, f.input(t))
+	require.NoError(t, err)
+	assert.Equal(t, 15, card.ClosingDay)
+
+	in = f.input(t)
+	in.ClosingDay = 20
+	in.CreditLimit = mustMoney(t, "7500.00", money.CurrencyUSD)
+	_, err = f.useCase.UpdateCreditCard(ctx, in)
+	require.NoError(t, err)
+
+	card, err = f.useCase.GetCreditCard(ctx, f.ledgerID, f.card.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 20, card.ClosingDay)
+	assert.Equal(t, "7500.00 USD", card.CreditLimit.String())
+}
+
+func TestCreditCardUseCase_AvailableCredit(t *testing.T) {
+	f := newCreditCardFixture(t)
+	ctx := context.Background()
+
+	_, err := f.useCase.CreateCreditCard(ctx, f.input(t))
+	require.NoError(t, err)
+
+	account := f.accounts.stored[f.card.ID.String()]
+	account.Balance = mustMoney(t, "-1200.00", money.CurrencyUSD)
+	f.accounts.stored[f.card.ID.String()] = account
+
+	hold := f.record(t, "-300.00", time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC), false)
+	require.NoError(t, hold.MarkPending())
+
+	available, err := f.useCase.AvailableCredit(ctx, f.ledgerID, f.card.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "3500.00 USD", available.String(), "pending holds use up credit too")
+}
+
+func TestCreditCardUseCase_ListCycles(t *testing.T) {
+	f := newCreditCardFixture(t)
+	ctx := context.Background()
+
+	_, err := f.useCase.ListCycles(ctx, ListCyclesInput{LedgerID: f.ledgerID, AccountID: f.card.ID, Now: time.Now()})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, err = f.useCase.CreateCreditCard(ctx, f.input(t))
+	require.NoError(t, err)
+
+	f.record(t, "-400.00", time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), false)
+	f.record(t, "400.00", time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC), true)
+	f.record(t, "-100.00", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), false)
+	f.record(t, "-1000.00", time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC), false)
+	f.record(t, "100.00", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), true)
+	f.record(t, "500.00", time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), true)
+	now := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	cycles, err := f.useCase.ListCycles(ctx, ListCyclesInput{LedgerID: f.ledgerID, AccountID: f.card.ID, Count: 3, Now: now})
+	require.NoError(t, err)
+	require.Len(t, cycles, 4)
+	assert.Equal(t, "2024-01-15", cycles[0].ClosingDate.Format(time.DateOnly))
+	assert.Equal(t, "100.00 USD", cycles[0].StatementBalance.String(), "the opening balance carries the earlier cycles")
+	assert.Equal(t, entity.CreditCardCycleStatusPaid, cycles[0].Status)
+	assert.Equal(t, entity.CreditCardCycleStatusOverdue, cycles[1].Status)
+	assert.Equal(t, entity.CreditCardCycleStatusDue, cycles[2].Status)
+	assert.Equal(t, "500.00 USD", cycles[2].AmountDue.String())
+	assert.True(t, cycles[3].Status.IsOpen())
+
+	cycles, err = f.useCase.ListCycles(ctx, ListCyclesInput{LedgerID: f.ledgerID, AccountID: f.card.ID, Now: now})
+	require.NoError(t, err)
+	assert.Len(t, cycles, 13)
+
+	_, err = f.useCase.ListCycles(ctx, ListCyclesInput{LedgerID: f.ledgerID, AccountID: f.card.ID, Count: -1, Now: now})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle count cannot be negative")
+
+	overdue, err := f.useCase.ListOverdueCycles(ctx, f.ledgerID, time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, overdue, 1)
+	assert.Equal(t, "2024-02-15", overdue[0].ClosingDate.Format(time.DateOnly))
+	assert.Equal(t, "1000.00 USD", overdue[0].AmountDue.String())
+
+	overdue, err = f.useCase.ListOverdueCycles(ctx, f.ledgerID, now)
+	require.NoError(t, err)
+	require.Len(t, overdue, 1, "the latest statement is not due yet but February's is still unpaid")
+	assert.Equal(t, "2024-02-15", overdue[0].ClosingDate.Format(time.DateOnly))
+
+	f.record(t, "600.00", time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC), true)
+	overdue, err = f.useCase.ListOverdueCycles(ctx, f.ledgerID, now)
+	require.NoError(t, err)
+	assert.Empty(t, overdue, "later payments cover February's amount due")
+}
+
+type creditCardFixture struct {
+	ledgerID     ledgerEntity.LedgerID
+	card         *entity.Account
+	checking     *entity.Account
+	itemID       budgetEntity.ItemID
+	accounts     *fakeAccountRepository
+	transactions *fakeTransactionRepository
+	useCase      *CreditCardUseCase
+}
+
+func newCreditCardFixture(t *testing.T) *creditCardFixture {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	card, err := entity.NewAccount(ledgerID, "Visa", "", entity.AccountTypeCreditCard, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	checking, err := entity.NewAccount(ledgerID, "Checking", "", entity.AccountTypeChecking, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	f := &creditCardFixture{
+		ledgerID: ledgerID,
+		card:     card,
+		checking: checking,
+		itemID:   itemID,
+		accounts: &fakeAccountRepository{stored: map[string]entity.Account{
+			card.ID.String():     *card,
+			checking.ID.String(): *checking,
+		}},
+		transactions: &fakeTransactionRepository{stored: map[string]*entity.Transaction{}},
+	}
+	f.useCase = NewCreditCardUseCase(f.accounts, f.transactions, fakeCreditCardRepository{})
+	return f
+}
+
+func (f *creditCardFixture) input(t *testing.T) CreditCardInput {
+	t.Helper()
+
+	percent, err := money.NewPercent("2")
+	require.NoError(t, err)
+
+	return CreditCardInput{
+		LedgerID:       f.ledgerID,
+		AccountID:      f.card.ID,
+		ClosingDay:     15,
+		PaymentDueDays: 25,
+		MinimumPayment: entity.MinimumPayment{Percent: percent, Floor: mustMoney(t, "25.00", money.CurrencyUSD)},
+		CreditLimit:    mustMoney(t, "5000.00", money.CurrencyUSD),
+	}
+}
+
+// record stores a transaction of the card; payments are stored as the incoming side of a transfer
+func (f *creditCardFixture) record(t *testing.T, amount string, date time.Time, payment bool) *entity.Transaction {
+	t.Helper()
+
+	tx, err := entity.NewTransaction(f.ledgerID, f.card.ID, f.itemID, mustMoney(t, amount, money.CurrencyUSD), "Test", date)
+	require.NoError(t, err)
+
+	if payment {
+		transferID, err := entity.NewTransferID()
+		require.NoError(t, err)
+		tx.TransferID = optional.Some(transferID)
+	}
+
+	f.transactions.stored[tx.ID.String()] = tx
+	return tx
+}
+
+type fakeCreditCardRepository map[string]*entity.CreditCard
+
+func (f fakeCreditCardRepository) Create(_ context.Context, card *entity.CreditCard) error {
+	c := *card
+	f[card.AccountID.String()] = &c
+	return nil
+}
+
+func (f fakeCreditCardRepository) Update(_ context.Context, card *entity.CreditCard) error {
+	if _, ok := f[card.AccountID.String()]; !ok {
+		return fmt.Errorf("credit card %s: %w", card.AccountID, repository.ErrNotFound)
+	}
+	c := *card
+	f[card.AccountID.String()] = &c
+	return nil
+}
+
+func (f fakeCreditCardRepository) GetByAccount(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (*entity.CreditCard, error) {
+	card, ok := f[accountID.String()]
+	if !ok || !card.LedgerID.Equals(ledgerID) {
+		return nil, fmt.Errorf("credit card %s: %w", accountID, repository.ErrNotFound)
+	}
+	c := *card
+	return &c, nil
+}
+
+func (f fakeCreditCardRepository) ListByLedger(_ context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.CreditCard, error) {
+	var cards []*entity.CreditCard
+	for _, card := range f {
+		if card.LedgerID.Equals(ledgerID) {
+			c := *card
+			cards = append(cards, &c)
+		}
+	}
+	return cards, nil
+}
@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	budgetRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// RecordInvestmentActivityInput describes a buy, sell, dividend, split or fee of an investment account
+type RecordInvestmentActivityInput struct {
+	LedgerID   ledgerEntity.LedgerID
+	AccountID  entity.AccountID
+	ItemID     budgetEntity.ItemID // Item of the cash side; unused for splits
+	Type       entity.InvestmentActivityType
+	Security   money.Currency  // Security of dividends and splits; trades take it from Quantity
+	Quantity   money.Money     // Units bought or sold
+	Amount     money.Money     // Cost or proceeds of a trade before its fee, or the dividend or fee
+	Fee        money.Money     // Brokerage of a trade
+	SplitRatio decimal.Decimal // Units held after a split per unit held before it
+	Date       time.Time
+}
+
+// InvestmentUseCase records the activities of investment accounts and derives their holdings, realised
+// gains and market value. The account balance stays the cash balance: buys, sells, dividends and fees
+// also record a transaction for their cash side.
+type InvestmentUseCase struct {
+	transactor   repository.Transactor
+	accounts     repository.AccountRepository
+	transactions repository.TransactionRepository
+	journal      repository.JournalEntryRepository
+	activities   repository.InvestmentActivityRepository
+	items        budgetRepository.ItemRepository
+	prices       *money.Converter // Prices of securities, as rates from the security to the account currency
+}
+
+// NewInvestmentUseCase creates a new InvestmentUseCase
+func NewInvestmentUseCase(
+	transactor repository.Transactor,
+	accounts repository.AccountRepository,
+	transactions repository.TransactionRepository,
+	journal repository.JournalEntryRepository,
+	activities repository.InvestmentActivityRepository,
+	items budgetRepository.ItemRepository,
+	prices *money.Converter,
+) *InvestmentUseCase {
+	return &InvestmentUseCase{
+		transactor:   transactor,
+		accounts:     accounts,
+		transactions: transactions,
+		journal:      journal,
+		activities:   activities,
+		items:        items,
+		prices:       prices,
+	}
+}
+
+// RecordActivity records an activity of an investment account and the transaction of its cash side.
+// Sales of more units than the account holds on their date are rejected.
+func (u *InvestmentUseCase) RecordActivity(
+	ctx context.Context,
+	in RecordInvestmentActivityInput,
+) (*entity.InvestmentActivity, error) {
+	var activity *entity.InvestmentActivity
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		accounts, err := u.accounts.GetForUpdate(ctx, in.LedgerID, in.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		account := accounts[0]
+
+		if activity, err = newInvestmentActivity(account, in); err != nil {
+			return err
+		}
+
+		activities, err := u.activities.ListByAccount(ctx, in.LedgerID, in.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to list investment activities: %w", err)
+		}
+
+		if _, err := entity.BuildPortfolio(account, entity.CostBasisMethodFIFO, append(activities, activity)); err != nil {
+			return err
+		}
+
+		cash, err := activity.CashAmount()
+		if err != nil {
+			return err
+		}
+
+		if !cash.IsZero() {
+			tx, err := u.recordCash(ctx, account, activity, in.ItemID, cash)
+			if err != nil {
+				return err
+			}
+			activity.TransactionID = optional.Some(tx.ID)
+		}
+
+		if err := u.activities.Create(ctx, activity); err != nil {
+			return fmt.Errorf("failed to create investment activity: %w", err)
+		}
+
+		if err := u.accounts.UpdateBalance(ctx, account); err != nil {
+			return fmt.Errorf("failed to update balance of account %s: %w", account.ID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return activity, nil
+}
+
+// GetPortfolio returns the holdings and realised gains of an investment account under the cost basis method
+func (u *InvestmentUseCase) GetPortfolio(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	method entity.CostBasisMethod,
+) (*entity.Portfolio, error) {
+	_, portfolio, err := u.portfolio(ctx, ledgerID, accountID, method)
+	return portfolio, err
+}
+
+// ValuePortfolio values an investment account at the prices of its securities as of the date: its cash
+// balance plus each holding's units at their price, with the unrealised gain over the cost basis method
+func (u *InvestmentUseCase) ValuePortfolio(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	method entity.CostBasisMethod,
+	asOf time.Time,
+) (*entity.PortfolioValuation, error) {
+	account, portfolio, err := u.portfolio(ctx, ledgerID, accountID, method)
+	if err != nil {
+		return nil, err
+	}
+
+	marketValues := make(map[money.Currency]money.Money, len(portfolio.Holdings))
+	for _, h := range portfolio.Holdings {
+		if h.Quantity.IsZero() {
+			continue
+		}
+
+		value, err := u.prices.Convert(ctx, h.Quantity, account.Currency, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to price %s: %w", h.Security, err)
+		}
+		marketValues[h.Security] = value
+	}
+
+	return portfolio.Value(account.Balance, asOf, marketValues)
+}
+
+func (u *InvestmentUseCase) portfolio(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	method entity.CostBasisMethod,
+) (*entity.Account, *entity.Portfolio, error) {
+	account, err := u.accounts.GetByID(ctx, ledgerID, accountID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	activities, err := u.activities.ListByAccount(ctx, ledgerID, accountID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list investment activities: %w", err)
+	}
+
+	portfolio, err := entity.BuildPortfolio(account, method, activities)
+	if err != nil {
+		return nil, nil, err
+	}
+	return account, portfolio, nil
+}
+
+// recordCash records the cash side of an activity as a transaction of the investment account
+func (u *InvestmentUseCase) recordCash(
+	ctx context.Context,
+	account *entity.Account,
+	activity *entity.InvestmentActivity,
+	itemID budgetEntity.ItemID,
+	cash money.Money,
+) (*entity.Transaction, error) {
+	tx, err := entity.NewTransaction(activity.LedgerID, account.ID, itemID, cash, activity.Description(), activity.Date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	if err := account.ApplyTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	changes, err := entity.ItemActualChanges(nil, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyItemActuals(ctx, u.items, activity.LedgerID, changes); err != nil {
+		return nil, err
+	}
+
+	if err := u.transactions.Create(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	if err := postJournalEntries(ctx, u.journal, tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func newInvestmentActivity(
+	account *entity.Account,
+	in RecordInvestmentActivityInput,
+) (*entity.InvestmentActivity, error) {
+	switch in.Type {
+	case entity.InvestmentActivityTypeBuy:
+		return entity.NewInvestmentBuy(account, in.Quantity, in.Amount, in.Fee, in.Date)
+	case entity.InvestmentActivityTypeSell:
+		return entity.NewInvestmentSell(account, in.Quantity, in.Amount, in.Fee, in.Date)
+	case entity.InvestmentActivityTypeDividend:
+		return entity.NewInvestmentDividend(account, in.Security, in.Amount, in.Date)
+	case entity.InvestmentActivityTypeSplit:
+		return entity.NewInvestmentSplit(account, in.Security, in.SplitRatio, in.Date)
+	case entity.InvestmentActivityTypeFee:
+		return entity.NewInvestmentFee(account, in.Amount, in.Date)
+	default:
+		return nil, fmt.Errorf("invalid investment activity type: %s", in.Type)
+	}
+}
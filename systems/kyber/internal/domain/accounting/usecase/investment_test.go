@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestInvestmentUseCase_RecordActivity(t *testing.T) {
+	f := newInvestmentFixture(t)
+	ctx := context.Background()
+
+	buy, err := f.useCase.RecordActivity(ctx, RecordInvestmentActivityInput{
+		LedgerID:  f.ledgerID,
+		AccountID: f.account.ID,
+		ItemID:    f.investing.ID,
+		Type:      entity.InvestmentActivityTypeBuy,
+		Quantity:  mustMoney(t, "10", "VWRA"),
+		Amount:    mustMoney(t, "1000.00", money.CurrencyUSD),
+		Fee:       mustMoney(t, "10.00", money.CurrencyUSD),
+		Date:      time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.True(t, buy.TransactionID.IsSome())
+
+	tx := f.transactions.stored[buy.TransactionID.Unwrap().String()]
+	require.NotNil(t, tx)
+	assert.Equal(t, "-1010.00 USD", tx.Amount.String())
+	assert.Equal(t, "BUY 10 VWRA", tx.Description)
+	assert.Equal(t, "3990.00 USD", f.balance())
+
+	t.Run("selling more than held", func(t *testing.T) {
+		_, err := f.useCase.RecordActivity(ctx, RecordInvestmentActivityInput{
+			LedgerID:  f.ledgerID,
+			AccountID: f.account.ID,
+			ItemID:    f.investing.ID,
+			Type:      entity.InvestmentActivityTypeSell,
+			Quantity:  mustMoney(t, "20", "VWRA"),
+			Amount:    mustMoney(t, "2000.00", money.CurrencyUSD),
+			Fee:       mustMoney(t, "0", money.CurrencyUSD),
+			Date:      time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC),
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot sell")
+		assert.Len(t, f.activities[f.account.ID.String()], 1)
+		assert.Equal(t, "3990.00 USD", f.balance())
+	})
+
+	split, err := f.useCase.RecordActivity(ctx, RecordInvestmentActivityInput{
+		LedgerID:   f.ledgerID,
+		AccountID:  f.account.ID,
+		Type:       entity.InvestmentActivityTypeSplit,
+		Security:   "VWRA",
+		SplitRatio: decimal.NewFromInt(2),
+		Date:       time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.True(t, split.TransactionID.IsNone())
+
+	_, err = f.useCase.RecordActivity(ctx, RecordInvestmentActivityInput{
+		LedgerID:  f.ledgerID,
+		AccountID: f.account.ID,
+		ItemID:    f.dividends.ID,
+		Type:      entity.InvestmentActivityTypeDividend,
+		Security:  "VWRA",
+		Amount:    mustMoney(t, "12.34", money.CurrencyUSD),
+		Date:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "4002.34 USD", f.balance())
+	assert.Len(t, f.transactions.stored, 2)
+
+	portfolio, err := f.useCase.GetPortfolio(ctx, f.ledgerID, f.account.ID, entity.CostBasisMethodFIFO)
+	require.NoError(t, err)
+	require.Len(t, portfolio.Holdings, 1)
+	assert.Equal(t, "20.0000 VWRA", portfolio.Holdings[0].Quantity.String())
+	assert.Equal(t, "1010.00 USD", portfolio.Holdings[0].Cost.String())
+	assert.Equal(t, "12.34 USD", portfolio.Holdings[0].Dividends.String())
+}
+
+func TestInvestmentUseCase_ValuePortfolio(t *testing.T) {
+	f := newInvestmentFixture(t)
+	ctx := context.Background()
+
+	_, err := f.useCase.RecordActivity(ctx, RecordInvestmentActivityInput{
+		LedgerID:  f.ledgerID,
+		AccountID: f.account.ID,
+		ItemID:    f.investing.ID,
+		Type:      entity.InvestmentActivityTypeBuy,
+		Quantity:  mustMoney(t, "10", "VWRA"),
+		Amount:    mustMoney(t, "1000.00", money.CurrencyUSD),
+		Fee:       mustMoney(t, "10.00", money.CurrencyUSD),
+		Date:      time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	valuation, err := f.useCase.ValuePortfolio(ctx, f.ledgerID, f.account.ID, entity.CostBasisMethodFIFO,
+		time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "3990.00 USD", valuation.Cash.String())
+	require.Len(t, valuation.Holdings, 1)
+	assert.Equal(t, "1150.00 USD", valuation.Holdings[0].MarketValue.String()) // 10 at 115.00
+	assert.Equal(t, "5140.00 USD", valuation.MarketValue.String())
+	assert.Equal(t, "140.00 USD", valuation.UnrealisedGain.String())
+
+	_, err = f.useCase.ValuePortfolio(ctx, f.ledgerID, f.account.ID, entity.CostBasisMethodFIFO,
+		time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to price VWRA")
+}
+
+type investmentFixture struct {
+	ledgerID     ledgerEntity.LedgerID
+	account      *entity.Account
+	investing    *budgetEntity.Item
+	dividends    *budgetEntity.Item
+	accounts     *fakeAccountRepository
+	transactions *fakeTransactionRepository
+	activities   fakeInvestmentActivityRepository
+	useCase      *InvestmentUseCase
+}
+
+func newInvestmentFixture(t *testing.T) *investmentFixture {
+	t.Helper()
+
+	require.NoError(t, money.RegisterCommodity(money.CurrencyInfo{
+		Code: "VWRA", Kind: money.CommodityKindSecurity, MinorUnits: 4, Name: "Vanguard FTSE All-World",
+	}))
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	account, err := entity.NewAccount(ledgerID, "Brokerage", "", entity.AccountTypeInvestment, money.CurrencyUSD)
+	require.NoError(t, err)
+	account.Balance = mustMoney(t, "5000.00", money.CurrencyUSD)
+
+	investing, err := budgetEntity.NewItem(ledgerID, "Investing", "", budgetEntity.ItemTypeExpense, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	dividends, err := budgetEntity.NewItem(ledgerID, "Dividends", "", budgetEntity.ItemTypeIncome, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	rates := make([]money.Rate, 0, 2)
+	for _, r := range []struct {
+		value string
+		date  time.Time
+	}{
+		{value: "100", date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "115", date: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		rate, err := money.NewRate("VWRA", money.CurrencyUSD, decimal.RequireFromString(r.value), r.date)
+		require.NoError(t, err)
+		rates = append(rates, rate)
+	}
+
+	provider, err := money.NewMemoryRateProvider(rates...)
+	require.NoError(t, err)
+
+	prices, err := money.NewConverter(provider)
+	require.NoError(t, err)
+
+	f := &investmentFixture{
+		ledgerID:  ledgerID,
+		account:   account,
+		investing: investing,
+		dividends: dividends,
+		accounts: &fakeAccountRepository{stored: map[string]entity.Account{
+			account.ID.String(): *account,
+		}},
+		transactions: &fakeTransactionRepository{stored: map[string]*entity.Transaction{}},
+		activities:   fakeInvestmentActivityRepository{},
+	}
+	items := fakeItemRepository{investing.ID.String(): investing, dividends.ID.String(): dividends}
+	f.useCase = NewInvestmentUseCase(&fakeTransactor{}, f.accounts, f.transactions, newFakeJournalEntryRepository(), f.activities, items, prices)
+	return f
+}
+
+func (f *investmentFixture) balance() string {
+	account := f.accounts.stored[f.account.ID.String()]
+	return account.Balance.String()
+}
+
+type fakeInvestmentActivityRepository map[string][]*entity.InvestmentActivity
+
+func (f fakeInvestmentActivityRepository) Create(_ context.Context, activity *entity.InvestmentActivity) error {
+	stored := *activity
+	f[activity.AccountID.String()] = append(f[activity.AccountID.String()], &stored)
+	return nil
+}
+
+func (f fakeInvestmentActivityRepository) ListByAccount(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) ([]*entity.InvestmentActivity, error) {
+	var activities []*entity.InvestmentActivity
+	for _, a := range f[accountID.String()] {
+		if a.LedgerID.Equals(ledgerID) {
+			stored := *a
+			activities = append(activities, &stored)
+		}
+	}
+	return activities, nil
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	budgetRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+// applyItemActuals adds the changes to the items' monthly actuals, writing only the months that change.
+// Transfer items are rejected as their transactions are managed through transfers.
+func applyItemActuals(
+	ctx context.Context,
+	repo budgetRepository.ItemRepository,
+	ledgerID ledgerEntity.LedgerID,
+	changes []entity.ItemActualChange,
+) error {
+	visited := make(map[string]*budgetEntity.Item)
+
+	for _, change := range changes {
+		item, ok := visited[change.ItemID.String()]
+		if !ok {
+			var err error
+			item, err = repo.GetByID(ctx, ledgerID, change.ItemID)
+			if err != nil {
+				return fmt.Errorf("failed to get budget item: %w", err)
+			}
+
+			if item.Type.IsTransfer() {
+				return fmt.Errorf("budget item %s is a transfer item and can only be used by transfers", item.Name)
+			}
+
+			visited[change.ItemID.String()] = item
+		}
+
+		amount, err := change.ActualAmount(item)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.AddActualAmount(ctx, ledgerID, item.ID, change.Year, change.Month, amount); err != nil {
+			return fmt.Errorf("failed to update actuals of budget item %s for %04d-%02d: %w",
+				item.Name, change.Year, change.Month, err)
+		}
+	}
+
+	return nil
+}
+
+// checkTransferItem checks that the budget item is a transfer item, which transfers must use
+func checkTransferItem(
+	ctx context.Context,
+	repo budgetRepository.ItemRepository,
+	ledgerID ledgerEntity.LedgerID,
+	itemID budgetEntity.ItemID,
+) error {
+	item, err := repo.GetByID(ctx, ledgerID, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to get budget item: %w", err)
+	}
+
+	if !item.Type.IsTransfer() {
+		return fmt.Errorf("budget item %s is not a transfer item: %s", item.Name, item.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+)
+
+// postJournalEntries records the balanced journal entry of each new posted transaction.
+// Pending and voided transactions are not part of the account balance and get none.
+func postJournalEntries(
+	ctx context.Context,
+	repo repository.JournalEntryRepository,
+	transactions ...*entity.Transaction,
+) error {
+	for _, tx := range transactions {
+		if !tx.PostingStatus.IsPosted() {
+			continue
+		}
+
+		entry, err := entity.NewJournalEntryFromTransaction(tx)
+		if err != nil {
+			return fmt.Errorf("failed to map transaction %s to a journal entry: %w", tx.ID, err)
+		}
+
+		if err := repo.Create(ctx, entry); err != nil {
+			return fmt.Errorf("failed to create journal entry of transaction %s: %w", tx.ID, err)
+		}
+	}
+	return nil
+}
+
+// syncJournalEntries keeps the journal entries of changed transactions in step: the entry is created once
+// a transaction is posted, mapped again when it changes and removed when it is voided
+func syncJournalEntries(
+	ctx context.Context,
+	repo repository.JournalEntryRepository,
+	transactions ...*entity.Transaction,
+) error {
+	for _, tx := range transactions {
+		entry, err := repo.GetBySourceTransaction(ctx, tx.LedgerID, tx.ID)
+		if errors.Is(err, repository.ErrNotFound) {
+			if err := postJournalEntries(ctx, repo, tx); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get journal entry of transaction %s: %w", tx.ID, err)
+		}
+
+		if !tx.PostingStatus.IsPosted() {
+			if err := repo.Delete(ctx, tx.LedgerID, entry.ID); err != nil {
+				return fmt.Errorf("failed to delete journal entry of transaction %s: %w", tx.ID, err)
+			}
+			continue
+		}
+
+		if err := entry.UpdateFromTransaction(tx); err != nil {
+			return fmt.Errorf("failed to map transaction %s to its journal entry: %w", tx.ID, err)
+		}
+
+		if err := repo.Update(ctx, entry); err != nil {
+			return fmt.Errorf("failed to update journal entry of transaction %s: %w", tx.ID, err)
+		}
+	}
+	return nil
+}
+
+// removeJournalEntries removes the journal entries of transactions being deleted
+func removeJournalEntries(
+	ctx context.Context,
+	repo repository.JournalEntryRepository,
+	transactions ...*entity.Transaction,
+) error {
+	for _, tx := range transactions {
+		entry, err := repo.GetBySourceTransaction(ctx, tx.LedgerID, tx.ID)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get journal entry of transaction %s: %w", tx.ID, err)
+		}
+
+		if err := repo.Delete(ctx, tx.LedgerID, entry.ID); err != nil {
+			return fmt.Errorf("failed to delete journal entry of transaction %s: %w", tx.ID, err)
+		}
+	}
+	return nil
+}
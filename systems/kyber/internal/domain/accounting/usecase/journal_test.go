@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestJournalEntries_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	journal := newFakeJournalEntryRepository()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+	accountID, err := entity.NewAccountID()
+	require.NoError(t, err)
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	hold, err := entity.NewTransaction(ledgerID, accountID, itemID, mustMoney(t, "-40.00", money.CurrencyUSD),
+		"Petrol", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NoError(t, hold.MarkPending())
+
+	require.NoError(t, postJournalEntries(ctx, journal, hold))
+	assert.Empty(t, journal.stored, "pending holds are not part of the balance")
+
+	require.NoError(t, hold.Post(mustMoney(t, "-38.20", money.CurrencyUSD), time.Time{}))
+	require.NoError(t, syncJournalEntries(ctx, journal, hold))
+	require.Len(t, journal.stored, 1)
+	assert.Equal(t, "-38.20 USD", journal.balance(t, accountID, money.CurrencyUSD))
+
+	require.NoError(t, syncJournalEntries(ctx, journal, hold), "syncing an unchanged transaction keeps its entry")
+	require.Len(t, journal.stored, 1)
+
+	require.NoError(t, removeJournalEntries(ctx, journal, hold))
+	assert.Empty(t, journal.stored)
+	require.NoError(t, removeJournalEntries(ctx, journal, hold), "removing a missing entry is a no-op")
+}
+
+// fakeJournalEntryRepository stores copies of journal entries keyed by ID
+type fakeJournalEntryRepository struct {
+	stored map[string]*entity.JournalEntry
+}
+
+func newFakeJournalEntryRepository() *fakeJournalEntryRepository {
+	return &fakeJournalEntryRepository{stored: map[string]*entity.JournalEntry{}}
+}
+
+func (f *fakeJournalEntryRepository) Create(_ context.Context, entry *entity.JournalEntry) error {
+	if entry.SourceTransactionID.IsSome() {
+		for _, e := range f.stored {
+			if e.SourceTransactionID.IsSome() && e.SourceTransactionID.Unwrap().Equals(entry.SourceTransactionID.Unwrap()) {
+				return fmt.Errorf("transaction %s already has a journal entry", entry.SourceTransactionID.Unwrap())
+			}
+		}
+	}
+	f.stored[entry.ID.String()] = copyJournalEntry(entry)
+	return nil
+}
+
+func (f *fakeJournalEntryRepository) Update(_ context.Context, entry *entity.JournalEntry) error {
+	if _, ok := f.stored[entry.ID.String()]; !ok {
+		return fmt.Errorf("journal entry %s: %w", entry.ID, repository.ErrNotFound)
+	}
+	f.stored[entry.ID.String()] = copyJournalEntry(entry)
+	return nil
+}
+
+func (f *fakeJournalEntryRepository) Delete(_ context.Context, _ ledgerEntity.LedgerID, id entity.JournalEntryID) error {
+	if _, ok := f.stored[id.String()]; !ok {
+		return fmt.Errorf("journal entry %s: %w", id, repository.ErrNotFound)
+	}
+	delete(f.stored, id.String())
+	return nil
+}
+
+func (f *fakeJournalEntryRepository) GetByID(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.JournalEntryID,
+) (*entity.JournalEntry, error) {
+	entry, ok := f.stored[id.String()]
+	if !ok || !entry.LedgerID.Equals(ledgerID) {
+		return nil, fmt.Errorf("journal entry %s: %w", id, repository.ErrNotFound)
+	}
+	return copyJournalEntry(entry), nil
+}
+
+func (f *fakeJournalEntryRepository) GetBySourceTransaction(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	transactionID entity.TransactionID,
+) (*entity.JournalEntry, error) {
+	for _, entry := range f.stored {
+		if entry.LedgerID.Equals(ledgerID) && entry.SourceTransactionID.IsSome() &&
+			entry.SourceTransactionID.Unwrap().Equals(transactionID) {
+			return copyJournalEntry(entry), nil
+		}
+	}
+	return nil, fmt.Errorf("journal entry of transaction %s: %w", transactionID, repository.ErrNotFound)
+}
+
+func (f *fakeJournalEntryRepository) ListByAccount(
+	_ context.Context,
+	accountID entity.AccountID,
+	from, to time.Time,
+) ([]*entity.JournalEntry, error) {
+	return f.list(func(p entity.Posting) bool { return p.IsForAccount(accountID) }, from, to), nil
+}
+
+func (f *fakeJournalEntryRepository) ListByItem(
+	_ context.Context,
+	itemID budgetEntity.ItemID,
+	from, to time.Time,
+) ([]*entity.JournalEntry, error) {
+	return f.list(func(p entity.Posting) bool { return p.IsForItem(itemID) }, from, to), nil
+}
+
+func (f *fakeJournalEntryRepository) list(match func(entity.Posting) bool, from, to time.Time) []*entity.JournalEntry {
+	var entries []*entity.JournalEntry
+	for _, entry := range f.stored {
+		if entry.EntryDate.Before(from) || !entry.EntryDate.Before(to) {
+			continue
+		}
+		for _, p := range entry.Postings {
+			if match(p) {
+				entries = append(entries, copyJournalEntry(entry))
+				break
+			}
+		}
+	}
+	return entries
+}
+
+// balance derives the account's balance from all stored entries
+func (f *fakeJournalEntryRepository) balance(t *testing.T, accountID entity.AccountID, currency money.Currency) string {
+	t.Helper()
+
+	entries := make([]*entity.JournalEntry, 0, len(f.stored))
+	for _, entry := range f.stored {
+		entries = append(entries, entry)
+	}
+
+	balance, err := entity.DeriveAccountBalance(accountID, currency, entries)
+	require.NoError(t, err)
+	return balance.String()
+}
+
+func copyJournalEntry(e *entity.JournalEntry) *entity.JournalEntry {
+	c := *e
+	c.Postings = append([]entity.Posting(nil), e.Postings...)
+	return &c
+}
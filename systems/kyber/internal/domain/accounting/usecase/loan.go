@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	budgetRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// CreateLoanInput describes the terms of a loan, mortgage or installment account
+type CreateLoanInput struct {
+	LedgerID   ledgerEntity.LedgerID
+	AccountID  entity.AccountID
+	Principal  money.Money
+	RateType   entity.LoanRateType
+	AnnualRate money.Percent // Rate from the start date; variable rates change with AddRateChange
+	TermMonths int
+	Frequency  entity.PaymentFrequency
+	StartDate  time.Time
+	Rounding   money.RoundingMode
+}
+
+// RecordRepaymentInput describes a repayment of a loan from another account of the ledger
+type RecordRepaymentInput struct {
+	LedgerID       ledgerEntity.LedgerID
+	LoanAccountID  entity.AccountID
+	FromAccountID  entity.AccountID    // Account the repayment is paid from, in the loan's currency
+	TransferItemID budgetEntity.ItemID // Transfer item of the principal repaid
+	InterestItemID budgetEntity.ItemID // Expense item of the interest paid
+	Amount         money.Money
+	Description    string
+	Date           time.Time
+}
+
+// LoanRepayment is how a repayment was split and recorded
+type LoanRepayment struct {
+	Principal           money.Money
+	Interest            money.Money
+	Transfer            *entity.Transfer    // Principal moved to the loan account; nil if the repayment was all interest
+	InterestTransaction *entity.Transaction // Interest paid from the paying account; nil if no interest accrued
+}
+
+// LoanUseCase manages the terms of loan, mortgage and installment accounts, derives their amortisation
+// schedules and prepayment what-ifs, and records repayments split into principal and interest.
+type LoanUseCase struct {
+	transactor   repository.Transactor
+	accounts     repository.AccountRepository
+	transactions repository.TransactionRepository
+	journal      repository.JournalEntryRepository
+	transfers    repository.TransferRepository
+	loans        repository.LoanRepository
+	items        budgetRepository.ItemRepository
+}
+
+// NewLoanUseCase creates a new LoanUseCase
+func NewLoanUseCase(
+	transactor repository.Transactor,
+	accounts repository.AccountRepository,
+	transactions repository.TransactionRepository,
+	journal repository.JournalEntryRepository,
+	transfers repository.TransferRepository,
+	loans repository.LoanRepository,
+	items budgetRepository.ItemRepository,
+) *LoanUseCase {
+	return &LoanUseCase{
+		transactor:   transactor,
+		accounts:     accounts,
+		transactions: transactions,
+		journal:      journal,
+		transfers:    transfers,
+		loans:        loans,
+		items:        items,
+	}
+}
+
+// CreateLoan sets up the terms of a loan, mortgage or installment account
+func (u *LoanUseCase) CreateLoan(ctx context.Context, in CreateLoanInput) (*entity.Loan, error) {
+	account, err := u.accounts.GetByID(ctx, in.LedgerID, in.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	loan, err := entity.NewLoan(account, in.Principal, in.RateType, in.AnnualRate, in.TermMonths, in.Frequency,
+		in.StartDate, in.Rounding)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.loans.Create(ctx, loan); err != nil {
+		return nil, fmt.Errorf("failed to create loan: %w", err)
+	}
+
+	return loan, nil
+}
+
+// AddRateChange sets the annual rate of a variable rate loan from the effective date on
+func (u *LoanUseCase) AddRateChange(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	effectiveDate time.Time,
+	annualRate money.Percent,
+) (*entity.Loan, error) {
+	loan, err := u.GetLoan(ctx, ledgerID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loan.AddRateChange(effectiveDate, annualRate); err != nil {
+		return nil, err
+	}
+
+	if err := u.loans.Update(ctx, loan); err != nil {
+		return nil, fmt.Errorf("failed to update loan: %w", err)
+	}
+
+	return loan, nil
+}
+
+// GetLoan returns the terms of a loan account
+func (u *LoanUseCase) GetLoan(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (*entity.Loan, error) {
+	loan, err := u.loans.GetByAccount(ctx, ledgerID, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loan: %w", err)
+	}
+	return loan, nil
+}
+
+// GetSchedule returns the amortisation schedule of a loan account
+func (u *LoanUseCase) GetSchedule(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) ([]entity.LoanInstallment, error) {
+	loan, err := u.GetLoan(ctx, ledgerID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return loan.Schedule()
+}
+
+// WhatIfPrepayment works out the new end date and the interest saved by prepaying a loan
+func (u *LoanUseCase) WhatIfPrepayment(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	prepayment entity.Prepayment,
+) (*entity.PrepaymentImpact, error) {
+	loan, err := u.GetLoan(ctx, ledgerID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return loan.WhatIfPrepayment(prepayment)
+}
+
+// RecordRepayment splits a repayment into the interest accrued since the last one and the principal it
+// repays. The principal is transferred from the paying account to the loan account and the interest is
+// recorded as an expense of the paying account, in a single database transaction.
+// Only repayments recorded here are split: a transfer into the loan account made with TransferUseCase or
+// brought in by a statement import is all principal and leaves interest accruing from the last repayment.
+func (u *LoanUseCase) RecordRepayment(ctx context.Context, in RecordRepaymentInput) (*LoanRepayment, error) {
+	if in.Date.IsZero() {
+		in.Date = time.Now()
+	}
+
+	var repayment *LoanRepayment
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if in.FromAccountID.Equals(in.LoanAccountID) {
+			return fmt.Errorf("cannot repay a loan from its own account")
+		}
+
+		accounts, err := u.accounts.GetForUpdate(ctx, in.LedgerID, in.FromAccountID, in.LoanAccountID)
+		if err != nil {
+			return fmt.Errorf("failed to lock accounts: %w", err)
+		}
+		from, loanAccount := accounts[0], accounts[1]
+
+		// Loaded under the loan account's lock so concurrent repayments accrue interest from the latest one
+		loan, err := u.GetLoan(ctx, in.LedgerID, in.LoanAccountID)
+		if err != nil {
+			return err
+		}
+
+		owed := loanAccount.Balance.Negate()
+		if !owed.IsPositive() {
+			return fmt.Errorf("loan account %s has nothing owed", loanAccount.Name)
+		}
+
+		principal, interest, err := loan.SplitRepayment(owed, in.Amount, in.Date)
+		if err != nil {
+			return err
+		}
+		repayment = &LoanRepayment{Principal: principal, Interest: interest}
+
+		if principal.IsPositive() {
+			if err := checkTransferItem(ctx, u.items, in.LedgerID, in.TransferItemID); err != nil {
+				return err
+			}
+
+			repayment.Transfer, err = entity.NewTransfer(from, loanAccount, in.TransferItemID, principal,
+				optional.None[decimal.Decimal](), in.Description, in.Date)
+			if err != nil {
+				return err
+			}
+
+			if err := u.transfers.Create(ctx, repayment.Transfer); err != nil {
+				return fmt.Errorf("failed to create transfer: %w", err)
+			}
+
+			if err := postJournalEntries(ctx, u.journal, repayment.Transfer.Outgoing, repayment.Transfer.Incoming); err != nil {
+				return err
+			}
+		}
+
+		if interest.IsPositive() {
+			if repayment.InterestTransaction, err = u.recordInterest(ctx, from, in, interest); err != nil {
+				return err
+			}
+		}
+
+		if err := u.loans.Update(ctx, loan); err != nil {
+			return fmt.Errorf("failed to update loan: %w", err)
+		}
+
+		for _, a := range []*entity.Account{from, loanAccount} {
+			if err := u.accounts.UpdateBalance(ctx, a); err != nil {
+				return fmt.Errorf("failed to update balance of account %s: %w", a.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repayment, nil
+}
+
+// recordInterest records the interest part of a repayment as an outflow of the paying account
+func (u *LoanUseCase) recordInterest(
+	ctx context.Context,
+	from *entity.Account,
+	in RecordRepaymentInput,
+	interest money.Money,
+) (*entity.Transaction, error) {
+	tx, err := entity.NewTransaction(in.LedgerID, from.ID, in.InterestItemID, interest.Negate(), in.Description, in.Date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create interest transaction: %w", err)
+	}
+
+	if err := from.ApplyTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	changes, err := entity.ItemActualChanges(nil, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyItemActuals(ctx, u.items, in.LedgerID, changes); err != nil {
+		return nil, err
+	}
+
+	if err := u.transactions.Create(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	if err := postJournalEntries(ctx, u.journal, tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestLoanUseCase_CreateLoan(t *testing.T) {
+	f := newLoanFixture(t)
+	ctx := context.Background()
+
+	in := f.input(t)
+	in.AccountID = f.checking.ID
+	_, err := f.useCase.CreateLoan(ctx, in)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a loan")
+
+	_, err = f.useCase.GetLoan(ctx, f.ledgerID, f.loan.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, err = f.useCase.CreateLoan(ctx, f.input(t))
+	require.NoError(t, err)
+
+	rate, err := money.NewPercent("20")
+	require.NoError(t, err)
+	_, err = f.useCase.AddRateChange(ctx, f.ledgerID, f.loan.ID, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), rate)
+	require.NoError(t, err)
+
+	loan, err := f.useCase.GetLoan(ctx, f.ledgerID, f.loan.ID)
+	require.NoError(t, err)
+	require.Len(t, loan.Rates, 2)
+	assert.True(t, loan.Rates[1].AnnualRate.Equals(rate))
+
+	schedule, err := f.useCase.GetSchedule(ctx, f.ledgerID, f.loan.ID)
+	require.NoError(t, err)
+	require.Len(t, schedule, 12)
+	assert.True(t, schedule[11].Rate.Equals(rate))
+
+	impact, err := f.useCase.WhatIfPrepayment(ctx, f.ledgerID, f.loan.ID, entity.Prepayment{
+		PerPayment: mustMoney(t, "1000.00", money.CurrencyUSD),
+		LumpSum:    mustMoney(t, "0", money.CurrencyUSD),
+	})
+	require.NoError(t, err)
+	assert.Positive(t, impact.PaymentsSaved)
+	assert.True(t, impact.InterestSaved.IsPositive())
+}
+
+func TestLoanUseCase_RecordRepayment(t *testing.T) {
+	f := newLoanFixture(t)
+	ctx := context.Background()
+
+	_, err := f.useCase.CreateLoan(ctx, f.input(t))
+	require.NoError(t, err)
+
+	// 31 days at 10% on 36500.00 accrues 310.00
+	repayment, err := f.useCase.RecordRepayment(ctx, f.repayment(t, "1000.00", time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	assert.Equal(t, "690.00 USD", repayment.Principal.String())
+	assert.Equal(t, "310.00 USD", repayment.Interest.String())
+
+	require.NotNil(t, repayment.Transfer)
+	assert.True(t, f.transfers.stored[repayment.Transfer.ID.String()].Incoming.AccountID.Equals(f.loan.ID))
+	assert.Equal(t, "-690.00 USD", repayment.Transfer.Outgoing.Amount.String())
+
+	require.NotNil(t, repayment.InterestTransaction)
+	stored := f.transactions.stored[repayment.InterestTransaction.ID.String()]
+	require.NotNil(t, stored)
+	assert.True(t, stored.AccountID.Equals(f.checking.ID))
+	assert.Equal(t, "-310.00 USD", stored.Amount.String())
+	assert.Equal(t, "310.00 USD", f.items[f.interest.ID.String()].GetMonthlyBudget(2026, 2).ActualAmount.String())
+
+	assert.Equal(t, "9000.00 USD", f.balance(f.checking.ID))
+	assert.Equal(t, "-35810.00 USD", f.balance(f.loan.ID))
+
+	loan, err := f.useCase.GetLoan(ctx, f.ledgerID, f.loan.ID)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), loan.AccruedFrom)
+
+	t.Run("before the last repayment", func(t *testing.T) {
+		_, err := f.useCase.RecordRepayment(ctx, f.repayment(t, "1000.00", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "interest already accrued")
+		assert.Equal(t, "9000.00 USD", f.balance(f.checking.ID))
+	})
+
+	t.Run("interest item must not be a transfer item", func(t *testing.T) {
+		in := f.repayment(t, "1000.00", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC))
+		in.InterestItemID = f.transfer.ID
+		_, err := f.useCase.RecordRepayment(ctx, in)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "can only be used by transfers")
+	})
+
+	t.Run("from the loan account", func(t *testing.T) {
+		in := f.repayment(t, "1000.00", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC))
+		in.FromAccountID = f.loan.ID
+		_, err := f.useCase.RecordRepayment(ctx, in)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot repay a loan from its own account")
+	})
+}
+
+type loanFixture struct {
+	ledgerID     ledgerEntity.LedgerID
+	loan         *entity.Account
+	checking     *entity.Account
+	transfer     *budgetEntity.Item
+	interest     *budgetEntity.Item
+	accounts     *fakeAccountRepository
+	transactions *fakeTransactionRepository
+	transfers    *fakeTransferRepository
+	items        fakeItemRepository
+	useCase      *LoanUseCase
+}
+
+func newLoanFixture(t *testing.T) *loanFixture {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	loan, err := entity.NewAccount(ledgerID, "Car Loan", "", entity.AccountTypeLoan, money.CurrencyUSD)
+	require.NoError(t, err)
+	loan.Balance = mustMoney(t, "-36500.00", money.CurrencyUSD)
+
+	checking, err := entity.NewAccount(ledgerID, "Checking", "", entity.AccountTypeChecking, money.CurrencyUSD)
+	require.NoError(t, err)
+	checking.Balance = mustMoney(t, "10000.00", money.CurrencyUSD)
+
+	transfer, err := budgetEntity.NewItem(ledgerID, "Loan Repayments", "", budgetEntity.ItemTypeTransfer, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	interest, err := budgetEntity.NewItem(ledgerID, "Loan Interest", "", budgetEntity.ItemTypeExpense, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	f := &loanFixture{
+		ledgerID: ledgerID,
+		loan:     loan,
+		checking: checking,
+		transfer: transfer,
+		interest: interest,
+		accounts: &fakeAccountRepository{stored: map[string]entity.Account{
+			loan.ID.String():     *loan,
+			checking.ID.String(): *checking,
+		}},
+		transactions: &fakeTransactionRepository{stored: map[string]*entity.Transaction{}},
+		transfers:    &fakeTransferRepository{stored: map[string]*entity.Transfer{}},
+		items:        fakeItemRepository{transfer.ID.String(): transfer, interest.ID.String(): interest},
+	}
+	f.useCase = NewLoanUseCase(&fakeTransactor{}, f.accounts, f.transactions, newFakeJournalEntryRepository(), f.transfers, fakeLoanRepository{}, f.items)
+	return f
+}
+
+func (f *loanFixture) input(t *testing.T) CreateLoanInput {
+	t.Helper()
+
+	rate, err := money.NewPercent("10")
+	require.NoError(t, err)
+
+	return CreateLoanInput{
+		LedgerID:   f.ledgerID,
+		AccountID:  f.loan.ID,
+		Principal:  mustMoney(t, "36500.00", money.CurrencyUSD),
+		RateType:   entity.LoanRateTypeVariable,
+		AnnualRate: rate,
+		TermMonths: 12,
+		Frequency:  entity.PaymentFrequencyMonthly,
+		StartDate:  time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		Rounding:   money.RoundHalfEven,
+	}
+}
+
+func (f *loanFixture) repayment(t *testing.T, amount string, date time.Time) RecordRepaymentInput {
+	t.Helper()
+
+	return RecordRepaymentInput{
+		LedgerID:       f.ledgerID,
+		LoanAccountID:  f.loan.ID,
+		FromAccountID:  f.checking.ID,
+		TransferItemID: f.transfer.ID,
+		InterestItemID: f.interest.ID,
+		Amount:         mustMoney(t, amount, money.CurrencyUSD),
+		Description:    "Car loan repayment",
+		Date:           date,
+	}
+}
+
+func (f *loanFixture) balance(id entity.AccountID) string {
+	account := f.accounts.stored[id.String()]
+	return account.Balance.String()
+}
+
+type fakeLoanRepository map[string]*entity.Loan
+
+func (f fakeLoanRepository) Create(_ context.Context, loan *entity.Loan) error {
+	f[loan.AccountID.String()] = copyLoan(loan)
+	return nil
+}
+
+func (f fakeLoanRepository) Update(_ context.Context, loan *entity.Loan) error {
+	if _, ok := f[loan.AccountID.String()]; !ok {
+		return fmt.Errorf("loan %s: %w", loan.AccountID, repository.ErrNotFound)
+	}
+	f[loan.AccountID.String()] = copyLoan(loan)
+	return nil
+}
+
+func (f fakeLoanRepository) GetByAccount(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (*entity.Loan, error) {
+	loan, ok := f[accountID.String()]
+	if !ok || !loan.LedgerID.Equals(ledgerID) {
+		return nil, fmt.Errorf("loan %s: %w", accountID, repository.ErrNotFound)
+	}
+	return copyLoan(loan), nil
+}
+
+func copyLoan(l *entity.Loan) *entity.Loan {
+	c := *l
+	c.Rates = append([]entity.LoanRate(nil), l.Rates...)
+	return &c
+}
@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// StartReconciliationInput describes the bank statement an account is reconciled against
+type StartReconciliationInput struct {
+	LedgerID       ledgerEntity.LedgerID
+	AccountID      entity.AccountID
+	StatementDate  time.Time
+	ClosingBalance money.Money
+}
+
+// ReconciliationUseCase runs statement reconciliations: ticking off transactions until the
+// cleared balance matches the statement, then reconciling and locking them.
+type ReconciliationUseCase struct {
+	transactor      repository.Transactor
+	accounts        repository.AccountRepository
+	transactions    repository.TransactionRepository
+	reconciliations repository.ReconciliationRepository
+}
+
+// NewReconciliationUseCase creates a new ReconciliationUseCase
+func NewReconciliationUseCase(
+	transactor repository.Transactor,
+	accounts repository.AccountRepository,
+	transactions repository.TransactionRepository,
+	reconciliations repository.ReconciliationRepository,
+) *ReconciliationUseCase {
+	return &ReconciliationUseCase{
+		transactor:      transactor,
+		accounts:        accounts,
+		transactions:    transactions,
+		reconciliations: reconciliations,
+	}
+}
+
+// StartReconciliation opens a reconciliation for the account, continuing from its last finished one
+func (u *ReconciliationUseCase) StartReconciliation(
+	ctx context.Context,
+	in StartReconciliationInput,
+) (*entity.Reconciliation, error) {
+	var reconciliation *entity.Reconciliation
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		account, err := u.accounts.GetByID(ctx, in.LedgerID, in.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to get account: %w", err)
+		}
+
+		inProgress, err := u.reconciliations.GetInProgress(ctx, in.LedgerID, in.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to get reconciliation in progress: %w", err)
+		}
+		if inProgress.IsSome() {
+			return fmt.Errorf("account %s already has a reconciliation in progress", account.Name)
+		}
+
+		latest, err := u.reconciliations.GetLatestFinished(ctx, in.LedgerID, in.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to get last reconciliation: %w", err)
+		}
+
+		reconciliation, err = account.StartReconciliation(in.StatementDate, in.ClosingBalance, latest.UnwrapOr(nil))
+		if err != nil {
+			return err
+		}
+
+		if err := u.reconciliations.Create(ctx, reconciliation); err != nil {
+			return fmt.Errorf("failed to create reconciliation: %w", err)
+		}
+
+		return u.loadTransactions(ctx, reconciliation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+// GetReconciliation returns the reconciliation; while in progress it includes the transactions to tick off
+func (u *ReconciliationUseCase) GetReconciliation(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ReconciliationID,
+) (*entity.Reconciliation, error) {
+	return u.getReconciliation(ctx, ledgerID, id)
+}
+
+// SetCleared ticks a transaction off (or un-ticks it); the returned reconciliation reflects the new difference
+func (u *ReconciliationUseCase) SetCleared(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ReconciliationID,
+	transactionID entity.TransactionID,
+	cleared bool,
+) (*entity.Reconciliation, error) {
+	var reconciliation *entity.Reconciliation
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		reconciliation, err = u.getReconciliation(ctx, ledgerID, id)
+		if err != nil {
+			return err
+		}
+
+		tx, err := reconciliation.SetCleared(transactionID, cleared)
+		if err != nil {
+			return err
+		}
+
+		if err := u.transactions.UpdateStatus(ctx, tx); err != nil {
+			return fmt.Errorf("failed to update transaction status: %w", err)
+		}
+
+		if err := u.reconciliations.Update(ctx, reconciliation); err != nil {
+			return fmt.Errorf("failed to update reconciliation: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+// FinishReconciliation reconciles and locks the cleared transactions once they match the statement
+func (u *ReconciliationUseCase) FinishReconciliation(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ReconciliationID,
+) (*entity.Reconciliation, error) {
+	var reconciliation *entity.Reconciliation
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		reconciliation, err = u.getReconciliation(ctx, ledgerID, id)
+		if err != nil {
+			return err
+		}
+
+		reconciled, err := reconciliation.Finish()
+		if err != nil {
+			return err
+		}
+
+		for _, tx := range reconciled {
+			if err := u.transactions.UpdateStatus(ctx, tx); err != nil {
+				return fmt.Errorf("failed to update status of transaction %s: %w", tx.ID, err)
+			}
+		}
+
+		if err := u.reconciliations.Update(ctx, reconciliation); err != nil {
+			return fmt.Errorf("failed to update reconciliation: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+// CancelReconciliation discards a reconciliation in progress. Cleared transactions stay cleared.
+func (u *ReconciliationUseCase) CancelReconciliation(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ReconciliationID,
+) error {
+	return u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		reconciliation, err := u.reconciliations.GetByID(ctx, ledgerID, id)
+		if err != nil {
+			return fmt.Errorf("failed to get reconciliation: %w", err)
+		}
+
+		if !reconciliation.IsInProgress() {
+			return fmt.Errorf("cannot cancel a finished reconciliation")
+		}
+
+		if err := u.reconciliations.Delete(ctx, ledgerID, id); err != nil {
+			return fmt.Errorf("failed to delete reconciliation: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UnlockTransaction allows a reconciled transaction's amount and date to be edited
+func (u *ReconciliationUseCase) UnlockTransaction(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+) (*entity.Transaction, error) {
+	return u.updateTransactionLock(ctx, ledgerID, id, func(tx *entity.Transaction) error {
+		tx.Unlock()
+		return nil
+	})
+}
+
+// LockTransaction locks a reconciled transaction against edits again
+func (u *ReconciliationUseCase) LockTransaction(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+) (*entity.Transaction, error) {
+	return u.updateTransactionLock(ctx, ledgerID, id, func(tx *entity.Transaction) error {
+		return tx.Lock()
+	})
+}
+
+// LastReconciliation returns the account's latest finished reconciliation, if any
+func (u *ReconciliationUseCase) LastReconciliation(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (optional.Option[*entity.Reconciliation], error) {
+	latest, err := u.reconciliations.GetLatestFinished(ctx, ledgerID, accountID)
+	if err != nil {
+		return optional.None[*entity.Reconciliation](), fmt.Errorf("failed to get last reconciliation: %w", err)
+	}
+	return latest, nil
+}
+
+// LastReconciliations returns the latest finished reconciliation of each account of the ledger,
+// i.e. the last reconciled statement date and balance per account
+func (u *ReconciliationUseCase) LastReconciliations(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+) ([]*entity.Reconciliation, error) {
+	reconciliations, err := u.reconciliations.ListLatestFinished(ctx, ledgerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reconciliations: %w", err)
+	}
+	return reconciliations, nil
+}
+
+func (u *ReconciliationUseCase) getReconciliation(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ReconciliationID,
+) (*entity.Reconciliation, error) {
+	reconciliation, err := u.reconciliations.GetByID(ctx, ledgerID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliation: %w", err)
+	}
+
+	if !reconciliation.IsInProgress() {
+		return reconciliation, nil
+	}
+
+	if err := u.loadTransactions(ctx, reconciliation); err != nil {
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+func (u *ReconciliationUseCase) loadTransactions(ctx context.Context, reconciliation *entity.Reconciliation) error {
+	transactions, err := u.transactions.ListUnreconciled(
+		ctx, reconciliation.LedgerID, reconciliation.AccountID, reconciliation.StatementCutoff())
+	if err != nil {
+		return fmt.Errorf("failed to list unreconciled transactions: %w", err)
+	}
+
+	return reconciliation.LoadTransactions(transactions)
+}
+
+func (u *ReconciliationUseCase) updateTransactionLock(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+	update func(tx *entity.Transaction) error,
+) (*entity.Transaction, error) {
+	var tx *entity.Transaction
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		tx, err = u.transactions.GetByID(ctx, ledgerID, id)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+
+		if err := update(tx); err != nil {
+			return err
+		}
+
+		if err := u.transactions.UpdateStatus(ctx, tx); err != nil {
+			return fmt.Errorf("failed to update transaction status: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
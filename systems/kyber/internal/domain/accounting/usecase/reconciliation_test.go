@@ -0,0 +1,533 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestReconciliationUseCase_Workflow(t *testing.T) {
+	f := newReconciliationFixture(t)
+	ctx := context.Background()
+
+	reconciliation, err := f.useCase.StartReconciliation(ctx, StartReconciliationInput{
+		LedgerID:       f.ledgerID,
+		AccountID:      f.account.ID,
+		StatementDate:  time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		ClosingBalance: mustMoney(t, "150.00", money.CurrencyUSD),
+	})
+	require.NoError(t, err)
+	require.Len(t, reconciliation.Transactions, 2, "transactions after the statement date are excluded")
+
+	_, err = f.useCase.StartReconciliation(ctx, StartReconciliationInput{
+		LedgerID:       f.ledgerID,
+		AccountID:      f.account.ID,
+		StatementDate:  time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+		ClosingBalance: mustMoney(t, "150.00", money.CurrencyUSD),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already has a reconciliation in progress")
+
+	reconciliation, err = f.useCase.SetCleared(ctx, f.ledgerID, reconciliation.ID, f.transactions[0].ID, true)
+	require.NoError(t, err)
+	difference, err := reconciliation.Difference()
+	require.NoError(t, err)
+	assert.Equal(t, "-50.00 USD", difference.String())
+	assert.Equal(t, entity.TransactionStatusCleared, f.transactionRepo.stored[f.transactions[0].ID.String()].Status)
+
+	_, err = f.useCase.FinishReconciliation(ctx, f.ledgerID, reconciliation.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reconciliation is off by -50.00 USD")
+
+	_, err = f.useCase.SetCleared(ctx, f.ledgerID, reconciliation.ID, f.transactions[1].ID, true)
+	require.NoError(t, err)
+
+	reconciliation, err = f.useCase.FinishReconciliation(ctx, f.ledgerID, reconciliation.ID)
+	require.NoError(t, err)
+	assert.True(t, reconciliation.IsFinished())
+
+	for _, tx := range f.transactions[:2] {
+		stored := f.transactionRepo.stored[tx.ID.String()]
+		assert.True(t, stored.Status.IsReconciled())
+		assert.True(t, stored.IsLocked())
+	}
+	assert.False(t, f.transactionRepo.stored[f.transactions[2].ID.String()].Status.IsCleared())
+
+	last, err := f.useCase.LastReconciliation(ctx, f.ledgerID, f.account.ID)
+	require.NoError(t, err)
+	require.True(t, last.IsSome())
+	assert.Equal(t, "150.00 USD", last.Unwrap().ClosingBalance.String())
+
+	all, err := f.useCase.LastReconciliations(ctx, f.ledgerID)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	next, err := f.useCase.StartReconciliation(ctx, StartReconciliationInput{
+		LedgerID:       f.ledgerID,
+		AccountID:      f.account.ID,
+		StatementDate:  time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+		ClosingBalance: mustMoney(t, "130.00", money.CurrencyUSD),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "150.00 USD", next.OpeningBalance.String())
+	require.Len(t, next.Transactions, 1, "reconciled transactions are not offered again")
+
+	require.NoError(t, f.useCase.CancelReconciliation(ctx, f.ledgerID, next.ID))
+	err = f.useCase.CancelReconciliation(ctx, f.ledgerID, reconciliation.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot cancel a finished reconciliation")
+}
+
+func TestReconciliationUseCase_LockAndUnlockTransaction(t *testing.T) {
+	f := newReconciliationFixture(t)
+	ctx := context.Background()
+
+	_, err := f.useCase.LockTransaction(ctx, f.ledgerID, f.transactions[0].ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only reconciled transactions can be locked")
+
+	reconciliation, err := f.useCase.StartReconciliation(ctx, StartReconciliationInput{
+		LedgerID:       f.ledgerID,
+		AccountID:      f.account.ID,
+		StatementDate:  time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		ClosingBalance: mustMoney(t, "200.00", money.CurrencyUSD),
+	})
+	require.NoError(t, err)
+	_, err = f.useCase.SetCleared(ctx, f.ledgerID, reconciliation.ID, f.transactions[0].ID, true)
+	require.NoError(t, err)
+	_, err = f.useCase.FinishReconciliation(ctx, f.ledgerID, reconciliation.ID)
+	require.NoError(t, err)
+
+	tx, err := f.useCase.UnlockTransaction(ctx, f.ledgerID, f.transactions[0].ID)
+	require.NoError(t, err)
+	assert.False(t, tx.IsLocked())
+	assert.False(t, f.transactionRepo.stored[tx.ID.String()].IsLocked())
+
+	tx, err = f.useCase.LockTransaction(ctx, f.ledgerID, f.transactions[0].ID)
+	require.NoError(t, err)
+	assert.True(t, tx.IsLocked())
+	assert.True(t, f.transactionRepo.stored[tx.ID.String()].IsLocked())
+}
+
+// Helper functions
+
+type reconciliationFixture struct {
+	ledgerID        ledgerEntity.LedgerID
+	account         *entity.Account
+	transactions    []*entity.Transaction
+	transactionRepo *fakeTransactionRepository
+	useCase         *ReconciliationUseCase
+}
+
+func newReconciliationFixture(t *testing.T) *reconciliationFixture {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	account, err := entity.NewAccount(ledgerID, "Checking", "", entity.AccountTypeChecking, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	itemID, err := budgetEntity.NewItemID()
+	require.NoError(t, err)
+
+	accounts := &fakeAccountRepository{stored: map[string]entity.Account{account.ID.String(): *account}}
+	transactions := &fakeTransactionRepository{stored: map[string]*entity.Transaction{}}
+
+	f := &reconciliationFixture{ledgerID: ledgerID, account: account, transactionRepo: transactions}
+	for _, tx := range []struct {
+		amount string
+		date   time.Time
+	}{
+		{amount: "200.00", date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{amount: "-50.00", date: time.Date(2024, 3, 31, 18, 0, 0, 0, time.UTC)},
+		{amount: "-20.00", date: time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC)},
+	} {
+		created, err := entity.NewTransaction(ledgerID, account.ID, itemID, mustMoney(t, tx.amount, money.CurrencyUSD), "Test", tx.date)
+		require.NoError(t, err)
+		transactions.stored[created.ID.String()] = created
+		f.transactions = append(f.transactions, created)
+	}
+
+	reconciliations := &fakeReconciliationRepository{stored: map[string]*entity.Reconciliation{}}
+	f.useCase = NewReconciliationUseCase(&fakeTransactor{}, accounts, transactions, reconciliations)
+	return f
+}
+
+// fakeTransactionRepository stores copies so use cases must save changes explicitly
+type fakeTransactionRepository struct {
+	stored map[string]*entity.Transaction
+}
+
+func (f *fakeTransactionRepository) SumByAccount(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	before optional.Option[time.Time],
+) (money.Money, error) {
+	// Test accounts use USD
+	sum, err := money.Zero(money.CurrencyUSD)
+	if err != nil {
+		return money.Money{}, err
+	}
+	for _, tx := range f.stored {
+		if !tx.LedgerID.Equals(ledgerID) || !tx.AccountID.Equals(accountID) || !tx.PostingStatus.IsPosted() {
+			continue
+		}
+		if before.IsSome() && !tx.TransactionDate.Before(before.Unwrap()) {
+			continue
+		}
+		if sum, err = sum.Add(tx.Amount); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return sum, nil
+}
+
+func (f *fakeTransactionRepository) ListByAccount(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	from, to time.Time,
+) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	for _, tx := range f.stored {
+		if tx.LedgerID.Equals(ledgerID) && tx.AccountID.Equals(accountID) &&
+			!tx.TransactionDate.Before(from) && tx.TransactionDate.Before(to) {
+			c := *tx
+			transactions = append(transactions, &c)
+		}
+	}
+	slices.SortFunc(transactions, func(a, b *entity.Transaction) int { return a.TransactionDate.Compare(b.TransactionDate) })
+	return transactions, nil
+}
+
+func (f *fakeTransactionRepository) GetByID(_ context.Context, ledgerID ledgerEntity.LedgerID, id entity.TransactionID) (*entity.Transaction, error) {
+	tx, ok := f.stored[id.String()]
+	if !ok || !tx.LedgerID.Equals(ledgerID) {
+		return nil, fmt.Errorf("transaction %s: %w", id, repository.ErrNotFound)
+	}
+	c := *tx
+	return &c, nil
+}
+
+func (f *fakeTransactionRepository) GetForUpdate(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+) (*entity.Transaction, error) {
+	return f.GetByID(ctx, ledgerID, id)
+}
+
+func (f *fakeTransactionRepository) ListUnreconciled(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	before time.Time,
+) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	for _, tx := range f.stored {
+		if tx.AccountID.Equals(accountID) && tx.PostingStatus.IsPosted() && !tx.Status.IsReconciled() &&
+			tx.TransactionDate.Before(before) {
+			c := *tx
+			transactions = append(transactions, &c)
+		}
+	}
+	return transactions, nil
+}
+
+func (f *fakeTransactionRepository) ListPending(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID optional.Option[entity.AccountID],
+) ([]*entity.Transaction, error) {
+	var transactions []*entity.Transaction
+	for _, tx := range f.stored {
+		if tx.LedgerID.Equals(ledgerID) && tx.PostingStatus.IsPending() &&
+			(accountID.IsNone() || accountID.Unwrap().Equals(tx.AccountID)) {
+			c := *tx
+			transactions = append(transactions, &c)
+		}
+	}
+	slices.SortFunc(transactions, func(a, b *entity.Transaction) int { return a.TransactionDate.Compare(b.TransactionDate) })
+	return transactions, nil
+}
+
+func (f *fakeTransactionRepository) UpdatePosting(_ context.Context, tx *entity.Transaction) error {
+	c := *tx
+	f.stored[tx.ID.String()] = &c
+	return nil
+}
+
+func (f *fakeTransactionRepository) UpdateStatus(_ context.Context, tx *entity.Transaction) error {
+	c := *tx
+	f.stored[tx.ID.String()] = &c
+	return nil
+}
+
+type fakeReconciliationRepository struct {
+	stored map[string]*entity.Reconciliation
+}
+
+func (f *fakeReconciliationRepository) Create(_ context.Context, reconciliation *entity.Reconciliation) error {
+	f.stored[reconciliation.ID.String()] = copyReconciliation(reconciliation)
+	return nil
+}
+
+func (f *fakeReconciliationRepository) Update(_ context.Context, reconciliation *entity.Reconciliation) error {
+	f.stored[reconciliation.ID.String()] = copyReconciliation(reconciliation)
+	return nil
+}
+
+func (f *fakeReconciliationRepository) Delete(_ context.Context, _ ledgerEntity.LedgerID, id entity.ReconciliationID) error {
+	delete(f.stored, id.String())
+	return nil
+}
+
+func (f *fakeReconciliationRepository) GetByID(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ReconciliationID,
+) (*entity.Reconciliation, error) {
+	reconciliation, ok := f.stored[id.String()]
+	if !ok || !reconciliation.LedgerID.Equals(ledgerID) {
+		return nil, fmt.Errorf("reconciliation %s: %w", id, repository.ErrNotFound)
+	}
+	return copyReconciliation(reconciliation), nil
+}
+
+func (f *fakeReconciliationRepository) GetInProgress(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (optional.Option[*entity.Reconciliation], error) {
+	for _, r := range f.stored {
+		if r.AccountID.Equals(accountID) && r.IsInProgress() {
+			return optional.Some(copyReconciliation(r)), nil
+		}
+	}
+	return optional.None[*entity.Reconciliation](), nil
+}
+
+func (f *fakeReconciliationRepository) GetLatestFinished(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (optional.Option[*entity.Reconciliation], error) {
+	var latest *entity.Reconciliation
+	for _, r := range f.stored {
+		if r.AccountID.Equals(accountID) && r.IsFinished() && (latest == nil || r.StatementDate.After(latest.StatementDate)) {
+			latest = r
+		}
+	}
+	if latest == nil {
+		return optional.None[*entity.Reconciliation](), nil
+	}
+	return optional.Some(copyReconciliation(latest)), nil
+}
+
+func (f *fakeReconciliationRepository) ListLatestFinished(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+) ([]*entity.Reconciliation, error) {
+	seen := map[string]bool{}
+	var reconciliations []*entity.Reconciliation
+	for _, r := range f.stored {
+		if !r.LedgerID.Equals(ledgerID) || seen[r.AccountID.String()] {
+			continue
+		}
+		seen[r.AccountID.String()] = true
+
+		latest, err := f.GetLatestFinished(ctx, ledgerID, r.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		if latest.IsSome() {
+			reconciliations = append(reconciliations, latest.Unwrap())
+		}
+	}
+	return reconciliations, nil
+}
+
+func copyReconciliation(r *entity.Reconciliation) *entity.Reconciliation {
+	c := *r
+	c.Transactions = nil
+	return &c
+}
+
+func (f *fakeTransactionRepository) UpdateSplits(_ context.Context, tx *entity.Transaction) error {
+	c := *tx
+	f.stored[tx.ID.String()] = &c
+	return nil
+}
+
+func (f *fakeTransactionRepository) UpdateTags(_ context.Context, tx *entity.Transaction) error {
+	c := *tx
+	c.Tags = slices.Clone(tx.Tags)
+	f.stored[tx.ID.String()] = &c
+	return nil
+}
+
+// Search approximates the full-text query: every word must appear in the description or notes,
+// and words prefixed with - must not
+func (f *fakeTransactionRepository) Search(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	filter entity.TransactionFilter,
+	limit, offset int,
+) (*entity.TransactionPage, error) {
+	var matched []*entity.Transaction
+	for _, tx := range f.stored {
+		if tx.LedgerID.Equals(ledgerID) && fakeFilterMatches(filter, tx) {
+			c := *tx
+			matched = append(matched, &c)
+		}
+	}
+	slices.SortFunc(matched, func(a, b *entity.Transaction) int { return b.TransactionDate.Compare(a.TransactionDate) })
+
+	page := &entity.TransactionPage{Total: len(matched)}
+	for _, tx := range matched {
+		if tx.PostingStatus.IsVoided() {
+			continue
+		}
+		for _, line := range tx.Lines() {
+			if filter.ItemID.IsSome() && tx.IsSplit() && !line.ItemID.Equals(filter.ItemID.Unwrap()) {
+				continue
+			}
+			var err error
+			if page.Totals, err = page.Totals.Add(line.Amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+	page.Transactions = matched[min(offset, len(matched)):min(offset+limit, len(matched))]
+	return page, nil
+}
+
+func (f *fakeTransactionRepository) SumByOriginalCurrency(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	filter entity.TransactionFilter,
+) ([]entity.OriginalCurrencySpend, error) {
+	byCurrency := map[money.Currency]*entity.OriginalCurrencySpend{}
+	for _, tx := range f.stored {
+		if !tx.LedgerID.Equals(ledgerID) || tx.IsTransfer() || tx.PostingStatus.IsVoided() ||
+			!fakeFilterMatches(filter, tx) {
+			continue
+		}
+
+		original := tx.OriginalAmount()
+		s, ok := byCurrency[original.Currency]
+		if !ok {
+			zero, err := money.Zero(original.Currency)
+			if err != nil {
+				return nil, err
+			}
+			s = &entity.OriginalCurrencySpend{Currency: original.Currency, Original: zero}
+			byCurrency[original.Currency] = s
+		}
+
+		var err error
+		if s.Original, err = s.Original.Add(original); err != nil {
+			return nil, err
+		}
+		if s.Settled, err = s.Settled.Add(tx.Amount); err != nil {
+			return nil, err
+		}
+		if tx.IsForeign() && !tx.Foreign.Unwrap().Fee.IsZero() {
+			if s.Fees, err = s.Fees.Add(tx.Foreign.Unwrap().Fee); err != nil {
+				return nil, err
+			}
+		}
+		s.Count++
+	}
+
+	spend := make([]entity.OriginalCurrencySpend, 0, len(byCurrency))
+	for _, s := range byCurrency {
+		spend = append(spend, *s)
+	}
+	slices.SortFunc(spend, func(a, b entity.OriginalCurrencySpend) int {
+		return strings.Compare(a.Currency.String(), b.Currency.String())
+	})
+	return spend, nil
+}
+
+func fakeFilterMatches(filter entity.TransactionFilter, tx *entity.Transaction) bool {
+	text := strings.ToLower(tx.Description + " " + tx.Notes)
+	for _, word := range strings.Fields(strings.ToLower(filter.Text)) {
+		if excluded, ok := strings.CutPrefix(word, "-"); ok {
+			if strings.Contains(text, excluded) {
+				return false
+			}
+		} else if !strings.Contains(text, word) {
+			return false
+		}
+	}
+
+	if filter.MinAmount.IsSome() {
+		if below, err := tx.Amount.LessThan(filter.MinAmount.Unwrap()); err != nil || below {
+			return false
+		}
+	}
+	if filter.MaxAmount.IsSome() {
+		if above, err := tx.Amount.GreaterThan(filter.MaxAmount.Unwrap()); err != nil || above {
+			return false
+		}
+	}
+	if filter.From.IsSome() && tx.TransactionDate.Before(filter.From.Unwrap()) {
+		return false
+	}
+	if filter.To.IsSome() && !tx.TransactionDate.Before(filter.To.Unwrap()) {
+		return false
+	}
+	if filter.AccountID.IsSome() && !filter.AccountID.Unwrap().Equals(tx.AccountID) {
+		return false
+	}
+	if filter.ItemID.IsSome() && !tx.ItemID.Equals(filter.ItemID.Unwrap()) &&
+		!slices.ContainsFunc(tx.Splits, func(l entity.SplitLine) bool { return l.ItemID.Equals(filter.ItemID.Unwrap()) }) {
+		return false
+	}
+	if filter.CounterpartyID.IsSome() &&
+		!tx.CounterpartyID.Equal(filter.CounterpartyID, counterpartyEntity.CounterpartyID.Equals) {
+		return false
+	}
+	return filter.Tag == "" || tx.HasTag(filter.Tag)
+}
+
+func (f *fakeTransactionRepository) Create(_ context.Context, tx *entity.Transaction) error {
+	if _, ok := f.stored[tx.ID.String()]; ok {
+		return fmt.Errorf("transaction %s already exists", tx.ID)
+	}
+	c := *tx
+	f.stored[tx.ID.String()] = &c
+	return nil
+}
+
+func (f *fakeTransactionRepository) ListImportIDs(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	importIDs []string,
+) ([]string, error) {
+	var found []string
+	for _, tx := range f.stored {
+		if tx.AccountID.Equals(accountID) && tx.ImportID != "" && slices.Contains(importIDs, tx.ImportID) {
+			found = append(found, tx.ImportID)
+		}
+	}
+	return found, nil
+}
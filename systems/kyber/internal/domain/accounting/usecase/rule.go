@@ -0,0 +1,306 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/repository"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	counterpartyRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+// CreateRuleInput describes a new categorisation rule
+type CreateRuleInput struct {
+	LedgerID   ledgerEntity.LedgerID
+	Name       string
+	Priority   int // Lowest runs first
+	Conditions entity.RuleConditions
+	Actions    entity.RuleActions
+}
+
+// UpdateRuleInput describes the new state of an existing rule
+type UpdateRuleInput struct {
+	LedgerID   ledgerEntity.LedgerID
+	RuleID     entity.RuleID
+	Name       string
+	Priority   int
+	Enabled    bool
+	Conditions entity.RuleConditions
+	Actions    entity.RuleActions
+}
+
+// CreateRuleFromCorrectionInput describes a rule to create from a transaction the user corrected by hand
+type CreateRuleFromCorrectionInput struct {
+	LedgerID      ledgerEntity.LedgerID
+	TransactionID entity.TransactionID
+	Name          string
+	Priority      int
+}
+
+// DryRunRuleInput describes the historical transactions to check a rule against
+type DryRunRuleInput struct {
+	LedgerID ledgerEntity.LedgerID
+	RuleID   entity.RuleID
+	From     time.Time // Inclusive
+	To       time.Time // Exclusive
+}
+
+// RuleChange is what a rule would change on a stored transaction
+type RuleChange struct {
+	Before *entity.Transaction
+	After  *entity.Transaction
+	// TransferAccountID is set when the rule would mark the transaction as a transfer with that account
+	TransferAccountID optional.Option[entity.AccountID]
+}
+
+// RuleUseCase manages the categorisation rules of ledgers. Rules are applied by TransactionUseCase
+// and ImportUseCase as transactions are created and imported.
+type RuleUseCase struct {
+	rules          repository.RuleRepository
+	accounts       repository.AccountRepository
+	transactions   repository.TransactionRepository
+	transfers      repository.TransferRepository
+	items          budgetRepository.ItemRepository
+	counterparties counterpartyRepository.CounterpartyRepository
+}
+
+// NewRuleUseCase creates a new RuleUseCase
+func NewRuleUseCase(
+	rules repository.RuleRepository,
+	accounts repository.AccountRepository,
+	transactions repository.TransactionRepository,
+	transfers repository.TransferRepository,
+	items budgetRepository.ItemRepository,
+	counterparties counterpartyRepository.CounterpartyRepository,
+) *RuleUseCase {
+	return &RuleUseCase{
+		rules:          rules,
+		accounts:       accounts,
+		transactions:   transactions,
+		transfers:      transfers,
+		items:          items,
+		counterparties: counterparties,
+	}
+}
+
+// CreateRule saves a new, enabled rule
+func (u *RuleUseCase) CreateRule(ctx context.Context, in CreateRuleInput) (*entity.Rule, error) {
+	rule, err := entity.NewRule(in.LedgerID, in.Name, in.Priority, in.Conditions, in.Actions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.checkReferences(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	if err := u.rules.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// UpdateRule changes a rule
+func (u *RuleUseCase) UpdateRule(ctx context.Context, in UpdateRuleInput) (*entity.Rule, error) {
+	rule, err := u.rules.GetByID(ctx, in.LedgerID, in.RuleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rule: %w", err)
+	}
+
+	if err := rule.Update(in.Name, in.Priority, in.Conditions, in.Actions); err != nil {
+		return nil, err
+	}
+
+	if in.Enabled {
+		rule.Enable()
+	} else {
+		rule.Disable()
+	}
+
+	if err := u.checkReferences(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	if err := u.rules.Update(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// DeleteRule removes a rule; transactions it categorised keep their categories
+func (u *RuleUseCase) DeleteRule(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.RuleID) error {
+	if err := u.rules.Delete(ctx, ledgerID, id); err != nil {
+		return fmt.Errorf("failed to delete rule: %w", err)
+	}
+	return nil
+}
+
+// ListRules returns the rules of a ledger in priority order
+func (u *RuleUseCase) ListRules(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Rule, error) {
+	rules, err := u.rules.ListByLedger(ctx, ledgerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateRuleFromCorrection saves a rule that repeats how the user categorised a transaction by hand,
+// so transactions like it are categorised the same way from now on
+func (u *RuleUseCase) CreateRuleFromCorrection(
+	ctx context.Context,
+	in CreateRuleFromCorrectionInput,
+) (*entity.Rule, error) {
+	tx, err := u.transactions.GetByID(ctx, in.LedgerID, in.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	transferAccountID := optional.None[entity.AccountID]()
+	if tx.IsTransfer() {
+		transfer, err := u.transfers.GetByID(ctx, in.LedgerID, tx.TransferID.Unwrap())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transfer: %w", err)
+		}
+
+		other := transfer.SourceAccountID()
+		if other.Equals(tx.AccountID) {
+			other = transfer.DestinationAccountID()
+		}
+		transferAccountID = optional.Some(other)
+	}
+
+	rule, err := entity.NewRuleFromTransaction(tx, transferAccountID, in.Name, in.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.rules.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// DryRun reports which of the ledger's transactions dated within [From, To) the rule would change, and how,
+// without changing anything. Disabled rules can be dry run before they are enabled. Transfers are left out
+// as rules do not apply to them.
+func (u *RuleUseCase) DryRun(ctx context.Context, in DryRunRuleInput) ([]RuleChange, error) {
+	if !in.To.After(in.From) {
+		return nil, fmt.Errorf("dry run period must end after it starts")
+	}
+
+	rule, err := u.rules.GetByID(ctx, in.LedgerID, in.RuleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rule: %w", err)
+	}
+
+	var accountIDs []entity.AccountID
+	if rule.Conditions.AccountID.IsSome() {
+		accountIDs = append(accountIDs, rule.Conditions.AccountID.Unwrap())
+	} else {
+		accounts, err := u.accounts.ListByLedger(ctx, in.LedgerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+		for _, account := range accounts {
+			accountIDs = append(accountIDs, account.ID)
+		}
+	}
+
+	counterparties, err := u.counterparties.ListByLedger(ctx, in.LedgerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list counterparties: %w", err)
+	}
+
+	var changes []RuleChange
+	for _, accountID := range accountIDs {
+		transactions, err := u.transactions.ListByAccount(ctx, in.LedgerID, accountID, in.From, in.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list transactions: %w", err)
+		}
+
+		for _, tx := range transactions {
+			if tx.IsTransfer() {
+				continue
+			}
+
+			after := *tx
+			after.Tags = slices.Clone(tx.Tags)
+			outcome, err := entity.ApplyRules(&after, []*entity.Rule{rule}, counterparties)
+			if err != nil {
+				return nil, err
+			}
+			if len(outcome.Matched) == 0 {
+				continue
+			}
+
+			if outcome.TransferAccountID.IsSome() || categoryChanged(tx, &after) {
+				changes = append(changes, RuleChange{
+					Before:            tx,
+					After:             &after,
+					TransferAccountID: outcome.TransferAccountID,
+				})
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// checkReferences checks that the accounts, budget item and counterparty a rule refers to exist in its ledger,
+// and that it uses a transfer item exactly when it marks transfers
+func (u *RuleUseCase) checkReferences(ctx context.Context, rule *entity.Rule) error {
+	for _, accountID := range []optional.Option[entity.AccountID]{
+		rule.Conditions.AccountID, rule.Actions.TransferAccountID,
+	} {
+		if accountID.IsSome() {
+			if _, err := u.accounts.GetByID(ctx, rule.LedgerID, accountID.Unwrap()); err != nil {
+				return fmt.Errorf("failed to get account: %w", err)
+			}
+		}
+	}
+
+	if rule.Actions.ItemID.IsSome() {
+		item, err := u.items.GetByID(ctx, rule.LedgerID, rule.Actions.ItemID.Unwrap())
+		if err != nil {
+			return fmt.Errorf("failed to get budget item: %w", err)
+		}
+
+		if item.Type.IsTransfer() != rule.IsTransferRule() {
+			if rule.IsTransferRule() {
+				return fmt.Errorf("budget item %s is not a transfer item: %s", item.Name, item.Type)
+			}
+			return fmt.Errorf("budget item %s is a transfer item and can only be used by transfers", item.Name)
+		}
+	}
+
+	if rule.Actions.CounterpartyID.IsSome() {
+		counterparties, err := u.counterparties.ListByLedger(ctx, rule.LedgerID)
+		if err != nil {
+			return fmt.Errorf("failed to list counterparties: %w", err)
+		}
+
+		id := rule.Actions.CounterpartyID.Unwrap()
+		if !slices.ContainsFunc(counterparties, func(c *counterpartyEntity.Counterparty) bool { return c.ID.Equals(id) }) {
+			return fmt.Errorf("counterparty %s: %w", id, repository.ErrNotFound)
+		}
+	}
+
+	return nil
+}
+
+// categoryChanged checks if the item, counterparty, tags or notes of the transaction differ
+func categoryChanged(before, after *entity.Transaction) bool {
+	return !before.ItemID.Equals(after.ItemID) ||
+		!before.CounterpartyID.Equal(after.CounterpartyID, counterpartyEntity.CounterpartyID.Equals) ||
+		!slices.Equal(before.Tags, after.Tags) ||
+		before.Notes != after.Notes
+}
@@ -0,0 +1,344 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestRuleUseCase_CreateRule(t *testing.T) {
+	f := newRuleFixture(t)
+
+	otherLedgerItem, err := budgetEntity.NewItem(f.ledgerID, "Elsewhere", "", budgetEntity.ItemTypeExpense, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		actions     entity.RuleActions
+		errContains string
+	}{
+		{
+			name:    "item and counterparty",
+			actions: entity.RuleActions{ItemID: optional.Some(f.food.ID), CounterpartyID: optional.Some(f.grocer.ID)},
+		},
+		{
+			name: "transfer",
+			actions: entity.RuleActions{
+				ItemID:            optional.Some(f.transferItem.ID),
+				TransferAccountID: optional.Some(f.savings.ID),
+			},
+		},
+		{
+			name:        "unknown item",
+			actions:     entity.RuleActions{ItemID: optional.Some(otherLedgerItem.ID)},
+			errContains: "failed to get budget item",
+		},
+		{
+			name:        "transfer item without transfer account",
+			actions:     entity.RuleActions{ItemID: optional.Some(f.transferItem.ID)},
+			errContains: "can only be used by transfers",
+		},
+		{
+			name: "transfer with ordinary item",
+			actions: entity.RuleActions{
+				ItemID:            optional.Some(f.food.ID),
+				TransferAccountID: optional.Some(f.savings.ID),
+			},
+			errContains: "is not a transfer item",
+		},
+		{
+			name:        "unknown counterparty",
+			actions:     entity.RuleActions{CounterpartyID: optional.Some(counterpartyEntity.CounterpartyID{})},
+			errContains: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := f.rules.CreateRule(context.Background(), CreateRuleInput{
+				LedgerID:   f.ledgerID,
+				Name:       "Rule",
+				Conditions: entity.RuleConditions{DescriptionContains: "whole foods"},
+				Actions:    tt.actions,
+			})
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, f.ruleRepo, rule.ID.String())
+		})
+	}
+}
+
+func TestRuleUseCase_UpdateRule(t *testing.T) {
+	f := newRuleFixture(t)
+	ctx := context.Background()
+	rule := f.createGroceryRule(t)
+
+	updated, err := f.rules.UpdateRule(ctx, UpdateRuleInput{
+		LedgerID:   f.ledgerID,
+		RuleID:     rule.ID,
+		Name:       "Groceries",
+		Priority:   5,
+		Enabled:    false,
+		Conditions: rule.Conditions,
+		Actions:    entity.RuleActions{Tag: "groceries"},
+	})
+	require.NoError(t, err)
+	assert.False(t, updated.Enabled)
+	assert.Equal(t, 5, f.ruleRepo[rule.ID.String()].Priority)
+
+	rules, err := f.rules.ListRules(ctx, f.ledgerID)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	require.NoError(t, f.rules.DeleteRule(ctx, f.ledgerID, rule.ID))
+	_, err = f.rules.UpdateRule(ctx, UpdateRuleInput{LedgerID: f.ledgerID, RuleID: rule.ID, Name: "Groceries"})
+	require.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestRuleUseCase_DryRun(t *testing.T) {
+	f := newRuleFixture(t)
+	ctx := context.Background()
+
+	matching := f.storeTransaction(t, f.checking, "WHOLE FOODS #123", "-42.50", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC))
+	f.storeTransaction(t, f.checking, "Cinema", "-12.00", time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC))
+	f.storeTransaction(t, f.checking, "Whole Foods", "-10.00", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+
+	// Already categorised the way the rule would: nothing to change
+	done := f.storeTransaction(t, f.savings, "Whole Foods", "-8.00", time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC))
+	done.UpdateItem(f.food.ID)
+	done.SetCounterparty(f.grocer.ID)
+
+	rule := f.createGroceryRule(t)
+	rule.Disable()
+
+	changes, err := f.rules.DryRun(ctx, DryRunRuleInput{
+		LedgerID: f.ledgerID,
+		RuleID:   rule.ID,
+		From:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+
+	change := changes[0]
+	assert.True(t, change.Before.ID.Equals(matching.ID))
+	assert.True(t, change.Before.ItemID.Equals(f.uncategorized.ID))
+	assert.True(t, change.After.ItemID.Equals(f.food.ID))
+	assert.True(t, change.After.CounterpartyID.Unwrap().Equals(f.grocer.ID))
+	assert.True(t, f.transactions.stored[matching.ID.String()].ItemID.Equals(f.uncategorized.ID), "nothing is saved")
+
+	_, err = f.rules.DryRun(ctx, DryRunRuleInput{LedgerID: f.ledgerID, RuleID: rule.ID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must end after it starts")
+}
+
+func TestRuleUseCase_CreateRuleFromCorrection(t *testing.T) {
+	f := newRuleFixture(t)
+	ctx := context.Background()
+
+	corrected := f.storeTransaction(t, f.checking, "Netflix", "-15.99", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC))
+	corrected.UpdateItem(f.food.ID)
+	corrected.SetCounterparty(f.grocer.ID)
+
+	rule, err := f.rules.CreateRuleFromCorrection(ctx, CreateRuleFromCorrectionInput{
+		LedgerID:      f.ledgerID,
+		TransactionID: corrected.ID,
+		Name:          "Netflix",
+		Priority:      10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Netflix", rule.Conditions.DescriptionContains)
+	assert.True(t, rule.Conditions.AccountID.Unwrap().Equals(f.checking.ID))
+	assert.True(t, rule.Actions.ItemID.Unwrap().Equals(f.food.ID))
+	assert.True(t, rule.Actions.CounterpartyID.Unwrap().Equals(f.grocer.ID))
+	assert.True(t, rule.Actions.TransferAccountID.IsNone())
+	assert.Contains(t, f.ruleRepo, rule.ID.String())
+
+	transfer, err := entity.NewTransfer(f.checking, f.savings, f.transferItem.ID, mustMoney(t, "100.00", money.CurrencyUSD),
+		optional.None[decimal.Decimal](), "Monthly savings", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	f.transfers.stored[transfer.ID.String()] = transfer
+	f.transactions.stored[transfer.Incoming.ID.String()] = transfer.Incoming
+
+	rule, err = f.rules.CreateRuleFromCorrection(ctx, CreateRuleFromCorrectionInput{
+		LedgerID:      f.ledgerID,
+		TransactionID: transfer.Incoming.ID,
+		Name:          "Savings",
+	})
+	require.NoError(t, err)
+	assert.True(t, rule.Conditions.AccountID.Unwrap().Equals(f.savings.ID))
+	assert.True(t, rule.Actions.TransferAccountID.Unwrap().Equals(f.checking.ID), "the other side of the transfer")
+	assert.True(t, rule.Actions.ItemID.Unwrap().Equals(f.transferItem.ID))
+}
+
+// Helper functions
+
+type ruleFixture struct {
+	ledgerID      ledgerEntity.LedgerID
+	checking      *entity.Account
+	savings       *entity.Account
+	uncategorized *budgetEntity.Item
+	food          *budgetEntity.Item
+	transferItem  *budgetEntity.Item
+	grocer        *counterpartyEntity.Counterparty
+	accounts      *fakeAccountRepository
+	transactions  *fakeTransactionRepository
+	transfers     *fakeTransferRepository
+	journal       *fakeJournalEntryRepository
+	items         fakeItemRepository
+	ruleRepo      fakeRuleRepository
+	rules         *RuleUseCase
+	useCase       *TransactionUseCase
+}
+
+func newRuleFixture(t *testing.T) *ruleFixture {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	checking, err := entity.NewAccount(ledgerID, "Checking", "", entity.AccountTypeChecking, money.CurrencyUSD)
+	require.NoError(t, err)
+	checking.Balance = mustMoney(t, "1000.00", money.CurrencyUSD)
+
+	savings, err := entity.NewAccount(ledgerID, "Savings", "", entity.AccountTypeSavings, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	uncategorized, err := budgetEntity.NewItem(ledgerID, "Uncategorized", "", budgetEntity.ItemTypeExpense, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	food, err := budgetEntity.NewItem(ledgerID, "Food", "", budgetEntity.ItemTypeExpense, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	transferItem, err := budgetEntity.NewItem(ledgerID, "Savings", "", budgetEntity.ItemTypeTransfer, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	grocer, err := counterpartyEntity.NewCounterparty(ledgerID, "Whole Foods", counterpartyEntity.CounterpartyTypeRetailer, "")
+	require.NoError(t, err)
+
+	f := &ruleFixture{
+		ledgerID:      ledgerID,
+		checking:      checking,
+		savings:       savings,
+		uncategorized: uncategorized,
+		food:          food,
+		transferItem:  transferItem,
+		grocer:        grocer,
+		accounts: &fakeAccountRepository{stored: map[string]entity.Account{
+			checking.ID.String(): *checking,
+			savings.ID.String():  *savings,
+		}},
+		transactions: &fakeTransactionRepository{stored: map[string]*entity.Transaction{}},
+		transfers:    &fakeTransferRepository{stored: map[string]*entity.Transfer{}},
+		journal:      newFakeJournalEntryRepository(),
+		items: fakeItemRepository{
+			uncategorized.ID.String(): uncategorized,
+			food.ID.String():          food,
+			transferItem.ID.String():  transferItem,
+		},
+		ruleRepo: fakeRuleRepository{},
+	}
+	counterparties := fakeCounterpartyRepository{grocer}
+	f.rules = NewRuleUseCase(f.ruleRepo, f.accounts, f.transactions, f.transfers, f.items, counterparties)
+	f.useCase = NewTransactionUseCase(&fakeTransactor{}, f.accounts, f.transactions, f.journal, f.transfers,
+		f.items, counterparties, f.ruleRepo)
+	return f
+}
+
+// createGroceryRule assigns "whole foods" transactions to the food item and grocer
+func (f *ruleFixture) createGroceryRule(t *testing.T) *entity.Rule {
+	t.Helper()
+
+	rule, err := f.rules.CreateRule(context.Background(), CreateRuleInput{
+		LedgerID:   f.ledgerID,
+		Name:       "Whole Foods",
+		Conditions: entity.RuleConditions{DescriptionContains: "whole foods"},
+		Actions:    entity.RuleActions{ItemID: optional.Some(f.food.ID), CounterpartyID: optional.Some(f.grocer.ID)},
+	})
+	require.NoError(t, err)
+	return rule
+}
+
+// storeTransaction stores an uncategorised transaction as if it had been recorded earlier
+func (f *ruleFixture) storeTransaction(
+	t *testing.T,
+	account *entity.Account,
+	description, amount string,
+	date time.Time,
+) *entity.Transaction {
+	t.Helper()
+
+	tx, err := entity.NewTransaction(f.ledgerID, account.ID, f.uncategorized.ID, mustMoney(t, amount, money.CurrencyUSD),
+		description, date)
+	require.NoError(t, err)
+	f.transactions.stored[tx.ID.String()] = tx
+	return tx
+}
+
+type fakeRuleRepository map[string]*entity.Rule
+
+func (f fakeRuleRepository) Create(_ context.Context, rule *entity.Rule) error {
+	f[rule.ID.String()] = rule
+	return nil
+}
+
+func (f fakeRuleRepository) Update(_ context.Context, rule *entity.Rule) error {
+	if _, ok := f[rule.ID.String()]; !ok {
+		return repository.ErrNotFound
+	}
+	f[rule.ID.String()] = rule
+	return nil
+}
+
+func (f fakeRuleRepository) Delete(_ context.Context, _ ledgerEntity.LedgerID, id entity.RuleID) error {
+	if _, ok := f[id.String()]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(f, id.String())
+	return nil
+}
+
+func (f fakeRuleRepository) GetByID(_ context.Context, ledgerID ledgerEntity.LedgerID, id entity.RuleID) (*entity.Rule, error) {
+	rule, ok := f[id.String()]
+	if !ok || !rule.LedgerID.Equals(ledgerID) {
+		return nil, repository.ErrNotFound
+	}
+	return rule, nil
+}
+
+func (f fakeRuleRepository) ListByLedger(_ context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Rule, error) {
+	var rules []*entity.Rule
+	for _, rule := range f {
+		if rule.LedgerID.Equals(ledgerID) {
+			rules = append(rules, rule)
+		}
+	}
+	slices.SortFunc(rules, func(a, b *entity.Rule) int { return a.Priority - b.Priority })
+	return rules, nil
+}
+
+func (f fakeRuleRepository) ListEnabled(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Rule, error) {
+	rules, err := f.ListByLedger(ctx, ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(rules, func(r *entity.Rule) bool { return !r.Enabled }), nil
+}
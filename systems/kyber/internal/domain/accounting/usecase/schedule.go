@@ -0,0 +1,294 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	budgetRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/repository"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/rrule"
+)
+
+// CreateScheduleInput describes a new scheduled transaction
+type CreateScheduleInput struct {
+	LedgerID       ledgerEntity.LedgerID
+	AccountID      entity.AccountID
+	ItemID         budgetEntity.ItemID
+	CounterpartyID optional.Option[counterpartyEntity.CounterpartyID]
+	Amount         money.Money // Positive for inflows, negative for outflows
+	Description    string
+	Notes          string
+	RRule          string // RFC 5545 RRULE, e.g. "FREQ=MONTHLY;BYMONTHDAY=-1"; COUNT or UNTIL ends it
+	StartDate      time.Time
+}
+
+// ModifyOccurrenceInput describes the change to a single occurrence of a scheduled transaction
+type ModifyOccurrenceInput struct {
+	LedgerID    ledgerEntity.LedgerID
+	ScheduleID  entity.ScheduledTransactionID
+	Date        time.Time
+	Amount      money.Money
+	Description string // Keeps the schedule's description when empty
+}
+
+// ScheduleUseCase manages scheduled transactions and generates their transactions when they come due
+type ScheduleUseCase struct {
+	transactor   repository.Transactor
+	accounts     repository.AccountRepository
+	transactions repository.TransactionRepository
+	journal      repository.JournalEntryRepository
+	schedules    repository.ScheduledTransactionRepository
+	items        budgetRepository.ItemRepository
+}
+
+// NewScheduleUseCase creates a new ScheduleUseCase
+func NewScheduleUseCase(
+	transactor repository.Transactor,
+	accounts repository.AccountRepository,
+	transactions repository.TransactionRepository,
+	journal repository.JournalEntryRepository,
+	schedules repository.ScheduledTransactionRepository,
+	items budgetRepository.ItemRepository,
+) *ScheduleUseCase {
+	return &ScheduleUseCase{
+		transactor:   transactor,
+		accounts:     accounts,
+		transactions: transactions,
+		journal:      journal,
+		schedules:    schedules,
+		items:        items,
+	}
+}
+
+// CreateSchedule creates a scheduled transaction
+func (u *ScheduleUseCase) CreateSchedule(ctx context.Context, in CreateScheduleInput) (*entity.ScheduledTransaction, error) {
+	rule, err := rrule.Parse(in.RRule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	account, err := u.accounts.GetByID(ctx, in.LedgerID, in.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	if in.Amount.Currency != account.Currency {
+		return nil, fmt.Errorf("currency mismatch: account %s uses %s, scheduled amount uses %s",
+			account.Name, account.Currency, in.Amount.Currency)
+	}
+
+	item, err := u.items.GetByID(ctx, in.LedgerID, in.ItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget item: %w", err)
+	}
+
+	if item.Type.IsTransfer() {
+		return nil, fmt.Errorf("budget item %s is a transfer item and can only be used by transfers", item.Name)
+	}
+
+	schedule, err := entity.NewScheduledTransaction(
+		in.LedgerID, in.AccountID, in.ItemID, in.Amount, in.Description, rule, in.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	schedule.CounterpartyID = in.CounterpartyID
+	schedule.Notes = in.Notes
+
+	if err := u.schedules.Create(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to create scheduled transaction: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// SkipOccurrence stops a single occurrence from generating a transaction
+func (u *ScheduleUseCase) SkipOccurrence(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ScheduledTransactionID,
+	date time.Time,
+) (*entity.ScheduledTransaction, error) {
+	return u.updateSchedule(ctx, ledgerID, id, func(schedule *entity.ScheduledTransaction) error {
+		return schedule.SkipOccurrence(date)
+	})
+}
+
+// ModifyOccurrence changes the amount and description of a single occurrence before it is generated
+func (u *ScheduleUseCase) ModifyOccurrence(ctx context.Context, in ModifyOccurrenceInput) (*entity.ScheduledTransaction, error) {
+	return u.updateSchedule(ctx, in.LedgerID, in.ScheduleID, func(schedule *entity.ScheduledTransaction) error {
+		return schedule.ModifyOccurrence(in.Date, in.Amount, in.Description)
+	})
+}
+
+// Upcoming returns the occurrences of the ledger's active schedules within [from, to) that
+// will still generate a transaction, earliest first, e.g. to show the bills due this week
+func (u *ScheduleUseCase) Upcoming(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	from, to time.Time,
+) ([]entity.Occurrence, error) {
+	schedules, err := u.schedules.ListActive(ctx, ledgerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled transactions: %w", err)
+	}
+
+	var occurrences []entity.Occurrence
+	for _, schedule := range schedules {
+		occurrences = append(occurrences, schedule.Upcoming(from, to)...)
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Date.Before(occurrences[j].Date)
+	})
+	return occurrences, nil
+}
+
+// GenerateDue creates the transactions of every occurrence due up to asOf. Each schedule is
+// generated in its own database transaction with the schedule locked, so running it again (or
+// concurrently) never generates an occurrence twice. A failing schedule does not stop the others.
+func (u *ScheduleUseCase) GenerateDue(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	asOf time.Time,
+) ([]*entity.Transaction, error) {
+	schedules, err := u.schedules.ListActive(ctx, ledgerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled transactions: %w", err)
+	}
+
+	var (
+		generated []*entity.Transaction
+		errs      []error
+	)
+
+	for _, schedule := range schedules {
+		if len(schedule.Due(asOf)) == 0 {
+			continue
+		}
+
+		transactions, err := u.generate(ctx, ledgerID, schedule.ID, asOf)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scheduled transaction %s: %w", schedule.Description, err))
+			continue
+		}
+		generated = append(generated, transactions...)
+	}
+
+	return generated, errors.Join(errs...)
+}
+
+func (u *ScheduleUseCase) generate(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ScheduledTransactionID,
+	asOf time.Time,
+) ([]*entity.Transaction, error) {
+	var generated []*entity.Transaction
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		generated = nil
+
+		schedule, err := u.schedules.GetForUpdate(ctx, ledgerID, id)
+		if err != nil {
+			return fmt.Errorf("failed to get scheduled transaction: %w", err)
+		}
+
+		due := schedule.Due(asOf)
+		if len(due) == 0 {
+			return nil
+		}
+
+		accounts, err := u.accounts.GetForUpdate(ctx, ledgerID, schedule.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		account := accounts[0]
+
+		var changes []entity.ItemActualChange
+		for _, occurrence := range due {
+			tx, err := schedule.Generate(occurrence)
+			if err != nil {
+				return err
+			}
+
+			if err := account.ApplyTransaction(tx); err != nil {
+				return fmt.Errorf("failed to apply transaction of %s: %w", occurrence.Date.Format(time.DateOnly), err)
+			}
+
+			if err := u.transactions.Create(ctx, tx); err != nil {
+				return fmt.Errorf("failed to create transaction: %w", err)
+			}
+
+			if err := postJournalEntries(ctx, u.journal, tx); err != nil {
+				return err
+			}
+
+			txChanges, err := entity.ItemActualChanges(nil, tx)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, txChanges...)
+			generated = append(generated, tx)
+		}
+
+		if err := applyItemActuals(ctx, u.items, ledgerID, changes); err != nil {
+			return err
+		}
+
+		if err := u.accounts.UpdateBalance(ctx, account); err != nil {
+			return fmt.Errorf("failed to update account balance: %w", err)
+		}
+
+		if err := u.schedules.Update(ctx, schedule); err != nil {
+			return fmt.Errorf("failed to update scheduled transaction: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return generated, nil
+}
+
+func (u *ScheduleUseCase) updateSchedule(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ScheduledTransactionID,
+	update func(schedule *entity.ScheduledTransaction) error,
+) (*entity.ScheduledTransaction, error) {
+	var schedule *entity.ScheduledTransaction
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		schedule, err = u.schedules.GetForUpdate(ctx, ledgerID, id)
+		if err != nil {
+			return fmt.Errorf("failed to get scheduled transaction: %w", err)
+		}
+
+		if err := update(schedule); err != nil {
+			return err
+		}
+
+		if err := u.schedules.Update(ctx, schedule); err != nil {
+			return fmt.Errorf("failed to update scheduled transaction: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestScheduleUseCase_GenerateDue(t *testing.T) {
+	f := newScheduleFixture(t)
+	ctx := context.Background()
+
+	schedule := f.createSchedule(t, "FREQ=MONTHLY;BYMONTHDAY=1;COUNT=6")
+
+	_, err := f.useCase.SkipOccurrence(ctx, f.ledgerID, schedule.ID, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	_, err = f.useCase.ModifyOccurrence(ctx, ModifyOccurrenceInput{
+		LedgerID:   f.ledgerID,
+		ScheduleID: schedule.ID,
+		Date:       time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Amount:     mustMoney(t, "-1600.00", money.CurrencyUSD),
+	})
+	require.NoError(t, err)
+
+	generated, err := f.useCase.GenerateDue(ctx, f.ledgerID, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, generated, 2)
+	assert.Equal(t, "-1500.00 USD", generated[0].Amount.String())
+	assert.Equal(t, "-1600.00 USD", generated[1].Amount.String())
+	assert.Len(t, f.transactions.stored, 2)
+	assert.Equal(t, "1900.00 USD", f.accounts.stored[f.account.ID.String()].Balance.String())
+	assert.Equal(t, "1600.00 USD", f.items[f.item.ID.String()].GetMonthlyBudget(2024, 3).ActualAmount.String())
+
+	// Running again generates nothing new
+	generated, err = f.useCase.GenerateDue(ctx, f.ledgerID, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, generated)
+	assert.Len(t, f.transactions.stored, 2)
+
+	_, err = f.useCase.SkipOccurrence(ctx, f.ledgerID, schedule.ID, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already been generated")
+}
+
+func TestScheduleUseCase_Upcoming(t *testing.T) {
+	f := newScheduleFixture(t)
+	ctx := context.Background()
+
+	f.createSchedule(t, "FREQ=MONTHLY;BYMONTHDAY=1")
+	f.createSchedule(t, "FREQ=WEEKLY;BYDAY=FR")
+
+	upcoming, err := f.useCase.Upcoming(ctx, f.ledgerID,
+		time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	var dates []string
+	for _, occurrence := range upcoming {
+		dates = append(dates, occurrence.Date.Format(time.DateOnly))
+	}
+	assert.Equal(t, []string{"2024-02-01", "2024-02-02"}, dates)
+}
+
+func TestScheduleUseCase_CreateSchedule_Invalid(t *testing.T) {
+	f := newScheduleFixture(t)
+
+	transferItem, err := budgetEntity.NewItem(f.ledgerID, "Savings", "", budgetEntity.ItemTypeTransfer, money.CurrencyUSD)
+	require.NoError(t, err)
+	f.items[transferItem.ID.String()] = transferItem
+
+	tests := []struct {
+		name        string
+		in          CreateScheduleInput
+		errContains string
+	}{
+		{
+			name:        "invalid rule",
+			in:          f.scheduleInput(t, "FREQ=HOURLY", f.item.ID, money.CurrencyUSD),
+			errContains: "invalid recurrence rule",
+		},
+		{
+			name:        "currency mismatch",
+			in:          f.scheduleInput(t, "FREQ=MONTHLY", f.item.ID, money.CurrencyEUR),
+			errContains: "currency mismatch",
+		},
+		{
+			name:        "transfer item",
+			in:          f.scheduleInput(t, "FREQ=MONTHLY", transferItem.ID, money.CurrencyUSD),
+			errContains: "is a transfer item",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.useCase.CreateSchedule(context.Background(), tt.in)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+			assert.Empty(t, f.schedules.stored)
+		})
+	}
+}
+
+// Helper functions
+
+type scheduleFixture struct {
+	ledgerID     ledgerEntity.LedgerID
+	account      *entity.Account
+	item         *budgetEntity.Item
+	accounts     *fakeAccountRepository
+	transactions *fakeTransactionRepository
+	schedules    *fakeScheduleRepository
+	items        fakeItemRepository
+	useCase      *ScheduleUseCase
+}
+
+func newScheduleFixture(t *testing.T) *scheduleFixture {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	account, err := entity.NewAccount(ledgerID, "Checking", "", entity.AccountTypeChecking, money.CurrencyUSD)
+	require.NoError(t, err)
+	account.Balance = mustMoney(t, "5000.00", money.CurrencyUSD)
+
+	item, err := budgetEntity.NewItem(ledgerID, "Rent", "", budgetEntity.ItemTypeExpense, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	f := &scheduleFixture{
+		ledgerID:     ledgerID,
+		account:      account,
+		item:         item,
+		accounts:     &fakeAccountRepository{stored: map[string]entity.Account{account.ID.String(): *account}},
+		transactions: &fakeTransactionRepository{stored: map[string]*entity.Transaction{}},
+		schedules:    &fakeScheduleRepository{stored: map[string]*entity.ScheduledTransaction{}},
+		items:        fakeItemRepository{item.ID.String(): item},
+	}
+	f.useCase = NewScheduleUseCase(&fakeTransactor{}, f.accounts, f.transactions, newFakeJournalEntryRepository(), f.schedules, f.items)
+	return f
+}
+
+func (f *scheduleFixture) scheduleInput(
+	t *testing.T,
+	rule string,
+	itemID budgetEntity.ItemID,
+	currency money.Currency,
+) CreateScheduleInput {
+	t.Helper()
+
+	return CreateScheduleInput{
+		LedgerID:       f.ledgerID,
+		AccountID:      f.account.ID,
+		ItemID:         itemID,
+		CounterpartyID: optional.None[counterpartyEntity.CounterpartyID](),
+		Amount:         mustMoney(t, "-1500.00", currency),
+		Description:    "Rent",
+		RRule:          rule,
+		StartDate:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (f *scheduleFixture) createSchedule(t *testing.T, rule string) *entity.ScheduledTransaction {
+	t.Helper()
+
+	schedule, err := f.useCase.CreateSchedule(context.Background(), f.scheduleInput(t, rule, f.item.ID, money.CurrencyUSD))
+	require.NoError(t, err)
+	return schedule
+}
+
+type fakeScheduleRepository struct {
+	stored map[string]*entity.ScheduledTransaction
+}
+
+func (f *fakeScheduleRepository) Create(_ context.Context, schedule *entity.ScheduledTransaction) error {
+	f.stored[schedule.ID.String()] = copySchedule(schedule)
+	return nil
+}
+
+func (f *fakeScheduleRepository) Update(_ context.Context, schedule *entity.ScheduledTransaction) error {
+	f.stored[schedule.ID.String()] = copySchedule(schedule)
+	return nil
+}
+
+func (f *fakeScheduleRepository) Delete(_ context.Context, _ ledgerEntity.LedgerID, id entity.ScheduledTransactionID) error {
+	delete(f.stored, id.String())
+	return nil
+}
+
+func (f *fakeScheduleRepository) GetByID(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ScheduledTransactionID,
+) (*entity.ScheduledTransaction, error) {
+	schedule, ok := f.stored[id.String()]
+	if !ok || !schedule.LedgerID.Equals(ledgerID) {
+		return nil, fmt.Errorf("scheduled transaction %s: %w", id, repository.ErrNotFound)
+	}
+	return copySchedule(schedule), nil
+}
+
+func (f *fakeScheduleRepository) GetForUpdate(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ScheduledTransactionID,
+) (*entity.ScheduledTransaction, error) {
+	return f.GetByID(ctx, ledgerID, id)
+}
+
+func (f *fakeScheduleRepository) ListActive(_ context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.ScheduledTransaction, error) {
+	var schedules []*entity.ScheduledTransaction
+	for _, schedule := range f.stored {
+		if schedule.LedgerID.Equals(ledgerID) && schedule.IsActive {
+			schedules = append(schedules, copySchedule(schedule))
+		}
+	}
+	return schedules, nil
+}
+
+func copySchedule(s *entity.ScheduledTransaction) *entity.ScheduledTransaction {
+	c := *s
+	c.Occurrences = make(map[string]*entity.Occurrence, len(s.Occurrences))
+	for key, o := range s.Occurrences {
+		occurrence := *o
+		c.Occurrences[key] = &occurrence
+	}
+	return &c
+}
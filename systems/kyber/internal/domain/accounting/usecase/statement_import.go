@@ -0,0 +1,717 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	budgetRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/repository"
+	counterpartyRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/camt"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/mt940"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/ofx"
+)
+
+// ImportFileInput describes an OFX/QFX, camt.053 or MT940 file to import into an account
+type ImportFileInput struct {
+	LedgerID           ledgerEntity.LedgerID
+	AccountID          entity.AccountID
+	ItemID             budgetEntity.ItemID // Budget item of imported transactions that no rule assigns another to
+	Format             entity.StatementFormat
+	File               io.Reader
+	StatementAccountID string // Bank account number or IBAN of the statement to import when the file has several
+}
+
+// ImportCSVInput describes a CSV file to import using one of the account's saved profiles
+type ImportCSVInput struct {
+	LedgerID  ledgerEntity.LedgerID
+	ProfileID entity.CSVProfileID
+	ItemID    budgetEntity.ItemID // Budget item of imported transactions that no rule assigns another to
+	File      io.Reader
+}
+
+// CreateCSVProfileInput describes a CSV profile to save for an account
+type CreateCSVProfileInput struct {
+	LedgerID  ledgerEntity.LedgerID
+	AccountID entity.AccountID
+	Name      string
+	Format    entity.CSVFormat
+}
+
+// UpdateCSVProfileInput describes changes to a saved CSV profile
+type UpdateCSVProfileInput struct {
+	LedgerID  ledgerEntity.LedgerID
+	ProfileID entity.CSVProfileID
+	Name      string
+	Format    entity.CSVFormat
+}
+
+// ImportResult reports what committing an import recorded
+type ImportResult struct {
+	Transactions []*entity.Transaction
+	Skipped      []string // Import IDs of drafts that were imported by someone else since the preview
+	Settled      []string // Import IDs of drafts that posted one of the account's pending holds instead of being recorded
+	Matched      []string // Import IDs of drafts that matched a transaction entered by hand instead of being recorded
+}
+
+// ImportUseCase imports bank statement files. Importing is two steps: a preview with draft
+// transactions and skipped duplicates, then a commit that records the (possibly edited) drafts.
+type ImportUseCase struct {
+	transactor     repository.Transactor
+	accounts       repository.AccountRepository
+	transactions   repository.TransactionRepository
+	journal        repository.JournalEntryRepository
+	items          budgetRepository.ItemRepository
+	counterparties counterpartyRepository.CounterpartyRepository
+	csvProfiles    repository.CSVProfileRepository
+	transfers      repository.TransferRepository
+	rules          repository.RuleRepository
+}
+
+// NewImportUseCase creates a new ImportUseCase
+func NewImportUseCase(
+	transactor repository.Transactor,
+	accounts repository.AccountRepository,
+	transactions repository.TransactionRepository,
+	journal repository.JournalEntryRepository,
+	items budgetRepository.ItemRepository,
+	counterparties counterpartyRepository.CounterpartyRepository,
+	csvProfiles repository.CSVProfileRepository,
+	transfers repository.TransferRepository,
+	rules repository.RuleRepository,
+) *ImportUseCase {
+	return &ImportUseCase{
+		transactor:     transactor,
+		accounts:       accounts,
+		transactions:   transactions,
+		journal:        journal,
+		items:          items,
+		counterparties: counterparties,
+		csvProfiles:    csvProfiles,
+		transfers:      transfers,
+		rules:          rules,
+	}
+}
+
+// PreviewFile parses a statement in the given format and previews importing it.
+// Bank references (OFX FITIDs, camt.053 account servicer references, MT940 bank references) are used
+// to skip duplicates; lines without one are identified by their content.
+func (u *ImportUseCase) PreviewFile(ctx context.Context, in ImportFileInput) (*entity.ImportPreview, error) {
+	account, err := u.accounts.GetByID(ctx, in.LedgerID, in.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	var statement entity.Statement
+	switch in.Format {
+	case entity.StatementFormatOFX:
+		statements, err := ofx.Parse(in.File)
+		if err != nil {
+			return nil, err
+		}
+		selected, err := selectStatement(statements, in.StatementAccountID, func(s ofx.Statement) string { return s.AccountID })
+		if err != nil {
+			return nil, err
+		}
+		if statement, err = statementFromOFX(selected, account.Currency); err != nil {
+			return nil, err
+		}
+	case entity.StatementFormatCAMT053:
+		statements, err := camt.Parse(in.File)
+		if err != nil {
+			return nil, err
+		}
+		selected, err := selectStatement(statements, in.StatementAccountID, func(s camt.Statement) string { return s.Account })
+		if err != nil {
+			return nil, err
+		}
+		if statement, err = statementFromCAMT(selected, account.Currency); err != nil {
+			return nil, err
+		}
+	case entity.StatementFormatMT940:
+		statements, err := mt940.Parse(in.File)
+		if err != nil {
+			return nil, err
+		}
+		selected, err := selectStatement(statements, in.StatementAccountID, func(s mt940.Statement) string { return s.Account })
+		if err != nil {
+			return nil, err
+		}
+		if statement, err = statementFromMT940(selected, account.Currency); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid statement format: %s", in.Format)
+	}
+
+	return u.previewStatement(ctx, account, in.ItemID, statement)
+}
+
+// PreviewCSV parses a CSV file with a saved profile and previews importing it into the profile's account.
+// Rows that cannot be read are reported in the preview's Rejected lines; the rest can still be committed.
+func (u *ImportUseCase) PreviewCSV(ctx context.Context, in ImportCSVInput) (*entity.ImportPreview, error) {
+	profile, err := u.csvProfiles.GetByID(ctx, in.LedgerID, in.ProfileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CSV profile: %w", err)
+	}
+
+	account, err := u.accounts.GetByID(ctx, in.LedgerID, profile.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	statement, err := profile.ParseStatement(in.File, account.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.previewStatement(ctx, account, in.ItemID, statement)
+}
+
+// CreateCSVProfile saves a CSV layout for an account
+func (u *ImportUseCase) CreateCSVProfile(ctx context.Context, in CreateCSVProfileInput) (*entity.CSVProfile, error) {
+	if _, err := u.accounts.GetByID(ctx, in.LedgerID, in.AccountID); err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	profile, err := entity.NewCSVProfile(in.LedgerID, in.AccountID, in.Name, in.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.csvProfiles.Create(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to create CSV profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// UpdateCSVProfile changes a saved CSV profile
+func (u *ImportUseCase) UpdateCSVProfile(ctx context.Context, in UpdateCSVProfileInput) (*entity.CSVProfile, error) {
+	profile, err := u.csvProfiles.GetByID(ctx, in.LedgerID, in.ProfileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CSV profile: %w", err)
+	}
+
+	if err := profile.Update(in.Name, in.Format); err != nil {
+		return nil, err
+	}
+
+	if err := u.csvProfiles.Update(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to update CSV profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// DeleteCSVProfile removes a saved CSV profile
+func (u *ImportUseCase) DeleteCSVProfile(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.CSVProfileID) error {
+	if err := u.csvProfiles.Delete(ctx, ledgerID, id); err != nil {
+		return fmt.Errorf("failed to delete CSV profile: %w", err)
+	}
+	return nil
+}
+
+// ListCSVProfiles returns the CSV profiles saved for an account
+func (u *ImportUseCase) ListCSVProfiles(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) ([]*entity.CSVProfile, error) {
+	profiles, err := u.csvProfiles.ListByAccount(ctx, ledgerID, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CSV profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// CommitImport records the preview's drafts, updating the account balance and budget actuals.
+// Drafts that rules marked as transfers are recorded as transfers with the other account.
+// A draft that settles one of the account's pending holds, within entity.DefaultHoldTolerance, posts
+// the hold instead of being recorded, so card spends are not counted twice. Likewise a draft of the same
+// amount as a transaction entered by hand, within entity.DefaultEntryMatchWindow, gives it its import ID.
+// Drafts imported since the preview was made are skipped, so committing twice is harmless.
+// The statement is the bank's record, so drafts may overdraw an asset account in the order they arrive.
+func (u *ImportUseCase) CommitImport(ctx context.Context, preview *entity.ImportPreview) (*ImportResult, error) {
+	var result *ImportResult
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		result = &ImportResult{}
+
+		accountIDs := []entity.AccountID{preview.AccountID}
+		for _, id := range preview.TransferAccounts {
+			if !slices.ContainsFunc(accountIDs, id.Equals) {
+				accountIDs = append(accountIDs, id)
+			}
+		}
+
+		accounts, err := u.accounts.GetForUpdate(ctx, preview.LedgerID, accountIDs...)
+		if err != nil {
+			return fmt.Errorf("failed to lock accounts: %w", err)
+		}
+		account := accounts[0]
+		others := make(map[string]*entity.Account, len(accounts)-1)
+		for _, other := range accounts[1:] {
+			others[other.ID.String()] = other
+		}
+
+		importIDs := make([]string, 0, len(preview.Drafts))
+		for _, tx := range preview.Drafts {
+			if !tx.LedgerID.Equals(preview.LedgerID) || !tx.AccountID.Equals(preview.AccountID) {
+				return fmt.Errorf("draft %s does not belong to account %s", tx.ImportID, account.Name)
+			}
+			importIDs = append(importIDs, tx.ImportID)
+		}
+
+		existing, err := u.transactions.ListImportIDs(ctx, preview.LedgerID, preview.AccountID, importIDs)
+		if err != nil {
+			return fmt.Errorf("failed to check for duplicates: %w", err)
+		}
+		imported := make(map[string]bool, len(existing))
+		for _, id := range existing {
+			imported[id] = true
+		}
+
+		holds, err := u.transactions.ListPending(ctx, preview.LedgerID, optional.Some(preview.AccountID))
+		if err != nil {
+			return fmt.Errorf("failed to list pending transactions: %w", err)
+		}
+
+		entries, err := u.listEntries(ctx, preview)
+		if err != nil {
+			return err
+		}
+
+		var changes []entity.ItemActualChange
+		for _, tx := range preview.Drafts {
+			if imported[tx.ImportID] {
+				result.Skipped = append(result.Skipped, tx.ImportID)
+				continue
+			}
+			imported[tx.ImportID] = true
+
+			if match := entity.MatchEntry(entries, tx, entity.DefaultEntryMatchWindow); match.IsSome() {
+				entry := match.Unwrap()
+				entries = slices.DeleteFunc(entries, func(e *entity.Transaction) bool { return e == entry })
+
+				if err := entry.ClaimImport(tx.ImportID); err != nil {
+					return fmt.Errorf("failed to match %s with transaction %s: %w", tx.ImportID, entry.ID, err)
+				}
+				if err := u.transactions.UpdatePosting(ctx, entry); err != nil {
+					return fmt.Errorf("failed to update transaction posting: %w", err)
+				}
+				result.Transactions = append(result.Transactions, entry)
+				result.Matched = append(result.Matched, tx.ImportID)
+				continue
+			}
+
+			if otherID, ok := preview.TransferAccounts[tx.ImportID]; ok {
+				side, err := u.createTransfer(ctx, tx, account, others[otherID.String()])
+				if err != nil {
+					return fmt.Errorf("failed to record %s as a transfer: %w", tx.ImportID, err)
+				}
+				result.Transactions = append(result.Transactions, side)
+				continue
+			}
+
+			if match := entity.MatchHold(holds, tx, entity.DefaultHoldTolerance); match.IsSome() {
+				hold := match.Unwrap()
+				holds = slices.DeleteFunc(holds, func(h *entity.Transaction) bool { return h == hold })
+
+				holdChanges, err := u.settleHold(ctx, account, hold, tx)
+				if err != nil {
+					return fmt.Errorf("failed to settle hold %s with %s: %w", hold.ID, tx.ImportID, err)
+				}
+				changes = append(changes, holdChanges...)
+				result.Transactions = append(result.Transactions, hold)
+				result.Settled = append(result.Settled, tx.ImportID)
+				continue
+			}
+
+			if err := account.ApplyStatementTransaction(tx); err != nil {
+				return fmt.Errorf("failed to apply transaction %s: %w", tx.ImportID, err)
+			}
+
+			if err := u.transactions.Create(ctx, tx); err != nil {
+				return fmt.Errorf("failed to create transaction: %w", err)
+			}
+
+			if err := postJournalEntries(ctx, u.journal, tx); err != nil {
+				return err
+			}
+
+			txChanges, err := entity.ItemActualChanges(nil, tx)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, txChanges...)
+			result.Transactions = append(result.Transactions, tx)
+		}
+
+		if err := applyItemActuals(ctx, u.items, preview.LedgerID, changes); err != nil {
+			return err
+		}
+
+		for _, a := range accounts {
+			if err := u.accounts.UpdateBalance(ctx, a); err != nil {
+				return fmt.Errorf("failed to update balance of account %s: %w", a.Name, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// listEntries returns the account's transactions dated around the preview's drafts, which the drafts
+// may record if they were entered by hand
+func (u *ImportUseCase) listEntries(ctx context.Context, preview *entity.ImportPreview) ([]*entity.Transaction, error) {
+	if len(preview.Drafts) == 0 {
+		return nil, nil
+	}
+
+	from, to := preview.Drafts[0].TransactionDate, preview.Drafts[0].TransactionDate
+	for _, tx := range preview.Drafts[1:] {
+		if tx.TransactionDate.Before(from) {
+			from = tx.TransactionDate
+		}
+		if tx.TransactionDate.After(to) {
+			to = tx.TransactionDate
+		}
+	}
+
+	entries, err := u.transactions.ListByAccount(ctx, preview.LedgerID, preview.AccountID,
+		from.Add(-entity.DefaultEntryMatchWindow), to.Add(entity.DefaultEntryMatchWindow).AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	return entries, nil
+}
+
+// settleHold posts the pending hold at the draft's amount and date, keeping the hold's description and
+// budget items, and returns how budget actuals change
+func (u *ImportUseCase) settleHold(
+	ctx context.Context,
+	account *entity.Account,
+	hold, draft *entity.Transaction,
+) ([]entity.ItemActualChange, error) {
+	before := *hold
+	if err := hold.Post(draft.Amount, draft.TransactionDate); err != nil {
+		return nil, err
+	}
+	hold.ImportID = draft.ImportID
+
+	if err := account.ApplyStatementTransaction(hold); err != nil {
+		return nil, err
+	}
+
+	if err := u.transactions.UpdatePosting(ctx, hold); err != nil {
+		return nil, fmt.Errorf("failed to update transaction posting: %w", err)
+	}
+
+	if err := syncJournalEntries(ctx, u.journal, hold); err != nil {
+		return nil, err
+	}
+
+	return entity.ItemActualChanges(&before, hold)
+}
+
+// createTransfer records a draft as a transfer with the other account, returning the draft's side of it
+func (u *ImportUseCase) createTransfer(
+	ctx context.Context,
+	tx *entity.Transaction,
+	account, other *entity.Account,
+) (*entity.Transaction, error) {
+	if err := checkTransferItem(ctx, u.items, tx.LedgerID, tx.ItemID); err != nil {
+		return nil, err
+	}
+
+	transfer, err := entity.NewTransferFromStatement(tx, account, other)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.transfers.Create(ctx, transfer); err != nil {
+		return nil, fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	if err := postJournalEntries(ctx, u.journal, transfer.Outgoing, transfer.Incoming); err != nil {
+		return nil, err
+	}
+
+	if tx.IsCredit() {
+		return transfer.Incoming, nil
+	}
+	return transfer.Outgoing, nil
+}
+
+// previewStatement previews importing a parsed statement of any format
+func (u *ImportUseCase) previewStatement(
+	ctx context.Context,
+	account *entity.Account,
+	itemID budgetEntity.ItemID,
+	statement entity.Statement,
+) (*entity.ImportPreview, error) {
+	item, err := u.items.GetByID(ctx, account.LedgerID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget item: %w", err)
+	}
+
+	if item.Type.IsTransfer() {
+		return nil, fmt.Errorf("budget item %s is a transfer item and can only be used by transfers", item.Name)
+	}
+
+	importIDs := make([]string, len(statement.Lines))
+	for i, line := range statement.Lines {
+		importIDs[i] = line.ImportID
+	}
+
+	existing, err := u.transactions.ListImportIDs(ctx, account.LedgerID, account.ID, importIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for duplicates: %w", err)
+	}
+	imported := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		imported[id] = true
+	}
+
+	counterparties, err := u.counterparties.ListByLedger(ctx, account.LedgerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list counterparties: %w", err)
+	}
+
+	preview, err := account.PreviewImport(statement, itemID, imported, counterparties)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := u.rules.ListEnabled(ctx, account.LedgerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+	for _, tx := range preview.Drafts {
+		outcome, err := entity.ApplyRules(tx, rules, counterparties)
+		if err != nil {
+			return nil, err
+		}
+		if outcome.TransferAccountID.IsSome() {
+			preview.TransferAccounts[tx.ImportID] = outcome.TransferAccountID.Unwrap()
+		}
+	}
+
+	if first := statement.FirstDate(); statement.OpeningBalance.IsSome() && first.IsSome() {
+		recorded, err := u.transactions.SumByAccount(ctx, account.LedgerID, account.ID, first)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get balance before the statement: %w", err)
+		}
+		preview.RecordedOpening = optional.Some(recorded)
+	}
+
+	return preview, nil
+}
+
+// selectStatement picks the statement to import from a file that may hold several accounts.
+// Account numbers are compared ignoring case and spaces, as IBANs are often written in groups.
+func selectStatement[S any](statements []S, accountID string, accountOf func(S) string) (S, error) {
+	var none S
+
+	if accountID == "" {
+		if len(statements) > 1 {
+			ids := make([]string, len(statements))
+			for i, s := range statements {
+				ids[i] = accountOf(s)
+			}
+			return none, fmt.Errorf("file has statements for several accounts (%s): choose one",
+				strings.Join(ids, ", "))
+		}
+		return statements[0], nil
+	}
+
+	normalize := func(id string) string {
+		return strings.ToUpper(strings.ReplaceAll(id, " ", ""))
+	}
+	for _, s := range statements {
+		if normalize(accountOf(s)) == normalize(accountID) {
+			return s, nil
+		}
+	}
+	return none, fmt.Errorf("file has no statement for account %s", accountID)
+}
+
+// statementFromOFX converts an OFX statement into the account's currency-checked statement
+func statementFromOFX(s ofx.Statement, currency money.Currency) (entity.Statement, error) {
+	if s.Currency != "" && money.Currency(s.Currency) != currency {
+		return entity.Statement{}, fmt.Errorf("currency mismatch: account uses %s, statement uses %s", currency, s.Currency)
+	}
+
+	statement := entity.Statement{Balance: optional.None[money.Money](), OpeningBalance: optional.None[money.Money]()}
+	for _, tx := range s.Transactions {
+		amount, err := money.NewMoneyFromDecimal(tx.Amount, currency)
+		if err != nil {
+			return entity.Statement{}, fmt.Errorf("invalid amount of transaction %s: %w", tx.FITID, err)
+		}
+
+		statement.Lines = append(statement.Lines, entity.StatementLine{
+			ImportID: tx.FITID,
+			Date:     tx.Posted,
+			Amount:   amount,
+			Payee:    tx.Name,
+			Memo:     tx.Memo,
+		})
+	}
+
+	if s.LedgerBalance.IsSome() {
+		balance, err := money.NewMoneyFromDecimal(s.LedgerBalance.Unwrap().Amount, currency)
+		if err != nil {
+			return entity.Statement{}, fmt.Errorf("invalid ledger balance: %w", err)
+		}
+		statement.Balance = optional.Some(balance)
+		statement.BalanceDate = s.LedgerBalance.Unwrap().AsOf
+	}
+
+	return statement, nil
+}
+
+// statementFromCAMT converts the booked entries of a camt.053 statement; pending and information-only
+// entries are left out
+func statementFromCAMT(s camt.Statement, currency money.Currency) (entity.Statement, error) {
+	if s.Currency != "" && money.Currency(s.Currency) != currency {
+		return entity.Statement{}, fmt.Errorf("currency mismatch: account uses %s, statement uses %s", currency, s.Currency)
+	}
+
+	statement := entity.Statement{Balance: optional.None[money.Money](), OpeningBalance: optional.None[money.Money]()}
+	for _, e := range s.Entries {
+		if !e.IsBooked() {
+			continue
+		}
+
+		if e.Currency != "" && money.Currency(e.Currency) != currency {
+			return entity.Statement{}, fmt.Errorf("currency mismatch: account uses %s, entry %s uses %s",
+				currency, e.Reference, e.Currency)
+		}
+
+		amount, err := money.NewMoneyFromDecimal(e.Amount, currency)
+		if err != nil {
+			return entity.Statement{}, fmt.Errorf("invalid amount of entry %s: %w", e.Reference, err)
+		}
+
+		memo := e.RemittanceInfo
+		if memo == "" {
+			memo = e.AdditionalInfo
+		}
+
+		statement.Lines = append(statement.Lines, entity.StatementLine{
+			ImportID:            e.Reference,
+			Date:                bookingDate(e.BookingDate, e.ValueDate),
+			ValueDate:           e.ValueDate,
+			Amount:              amount,
+			Payee:               e.CounterpartyName,
+			Memo:                memo,
+			CounterpartyAccount: e.CounterpartyIBAN,
+		})
+	}
+
+	if err := setStatementBalances(&statement, currency, s.Opening, s.Closing,
+		func(b camt.Balance) (decimal.Decimal, time.Time) { return b.Amount, b.Date }); err != nil {
+		return entity.Statement{}, err
+	}
+
+	statement.AssignMissingImportIDs("camt:")
+	return statement, nil
+}
+
+// statementFromMT940 converts an MT940 statement
+func statementFromMT940(s mt940.Statement, currency money.Currency) (entity.Statement, error) {
+	if s.Currency != "" && money.Currency(s.Currency) != currency {
+		return entity.Statement{}, fmt.Errorf("currency mismatch: account uses %s, statement uses %s", currency, s.Currency)
+	}
+
+	statement := entity.Statement{Balance: optional.None[money.Money](), OpeningBalance: optional.None[money.Money]()}
+	for _, e := range s.Entries {
+		importID := e.BankReference
+		if strings.EqualFold(importID, "NONREF") {
+			importID = ""
+		}
+
+		amount, err := money.NewMoneyFromDecimal(e.Amount, currency)
+		if err != nil {
+			return entity.Statement{}, fmt.Errorf("invalid amount of statement line %s: %w", e.BankReference, err)
+		}
+
+		memo := e.RemittanceInfo
+		if memo == "" {
+			memo = e.Details
+		}
+
+		statement.Lines = append(statement.Lines, entity.StatementLine{
+			ImportID:            importID,
+			Date:                bookingDate(e.BookingDate, e.ValueDate),
+			ValueDate:           e.ValueDate,
+			Amount:              amount,
+			Payee:               e.CounterpartyName,
+			Memo:                memo,
+			CounterpartyAccount: e.CounterpartyIBAN,
+		})
+	}
+
+	if err := setStatementBalances(&statement, currency, s.Opening, s.Closing,
+		func(b mt940.Balance) (decimal.Decimal, time.Time) { return b.Amount, b.Date }); err != nil {
+		return entity.Statement{}, err
+	}
+
+	statement.AssignMissingImportIDs("mt940:")
+	return statement, nil
+}
+
+// setStatementBalances sets the opening and closing balances of a converted statement
+func setStatementBalances[B any](
+	statement *entity.Statement,
+	currency money.Currency,
+	opening, closing optional.Option[B],
+	amountOf func(B) (decimal.Decimal, time.Time),
+) error {
+	if opening.IsSome() {
+		amount, date := amountOf(opening.Unwrap())
+		balance, err := money.NewMoneyFromDecimal(amount, currency)
+		if err != nil {
+			return fmt.Errorf("invalid opening balance: %w", err)
+		}
+		statement.OpeningBalance = optional.Some(balance)
+		statement.OpeningBalanceDate = date
+	}
+
+	if closing.IsSome() {
+		amount, date := amountOf(closing.Unwrap())
+		balance, err := money.NewMoneyFromDecimal(amount, currency)
+		if err != nil {
+			return fmt.Errorf("invalid closing balance: %w", err)
+		}
+		statement.Balance = optional.Some(balance)
+		statement.BalanceDate = date
+	}
+
+	return nil
+}
+
+// bookingDate returns the booking date, or the value date for banks that only give that
+func bookingDate(booking, value time.Time) time.Time {
+	if booking.IsZero() {
+		return value
+	}
+	return booking
+}
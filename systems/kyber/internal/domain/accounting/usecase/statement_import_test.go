@@ -0,0 +1,548 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+const testOFX = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STATUS><CODE>0<SEVERITY>INFO</STATUS>
+<STMTRS>
+<CURDEF>USD
+<BANKACCTFROM><BANKID>121000248<ACCTID>1234<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><TRNTYPE>POS<DTPOSTED>20240105<TRNAMT>-42.50<FITID>A1<NAME>WHOLE FOODS</STMTTRN>
+<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20240115<TRNAMT>2500.00<FITID>A2<NAME>ACME PAYROLL</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>3457.50<DTASOF>20240131</LEDGERBAL>
+</STMTRS>
+</STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+const testCAMT053 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+<BkToCstmrStmt><Stmt>
+<Id>STMT-1</Id>
+<Acct><Id><IBAN>US00 KYBR 0000 1234</IBAN></Id><Ccy>USD</Ccy></Acct>
+<Bal><Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp><Amt Ccy="USD">1000.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Dt><Dt>2024-01-01</Dt></Dt></Bal>
+<Bal><Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp><Amt Ccy="USD">3457.50</Amt><CdtDbtInd>CRDT</CdtDbtInd><Dt><Dt>2024-01-31</Dt></Dt></Bal>
+<Ntry><Amt Ccy="USD">42.50</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>BOOK</Sts>
+<BookgDt><Dt>2024-01-05</Dt></BookgDt><ValDt><Dt>2024-01-04</Dt></ValDt><AcctSvcrRef>R1</AcctSvcrRef>
+<NtryDtls><TxDtls><RltdPties><Cdtr><Nm>Whole Foods</Nm></Cdtr><CdtrAcct><Id><IBAN>US00WHFD</IBAN></Id></CdtrAcct></RltdPties>
+<RmtInf><Ustrd>Groceries</Ustrd></RmtInf></TxDtls></NtryDtls></Ntry>
+<Ntry><Amt Ccy="USD">12.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>PDNG</Sts><ValDt><Dt>2024-02-01</Dt></ValDt></Ntry>
+<Ntry><Amt Ccy="USD">2500.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts>
+<BookgDt><Dt>2024-01-15</Dt></BookgDt><ValDt><Dt>2024-01-15</Dt></ValDt>
+<NtryDtls><TxDtls><RltdPties><Dbtr><Nm>ACME Payroll</Nm></Dbtr></RltdPties></TxDtls></NtryDtls></Ntry>
+</Stmt></BkToCstmrStmt>
+</Document>
+`
+
+const testMT940 = `:20:STMT
+:25:1234
+:28C:1/1
+:60F:C231231USD900,00
+:61:2401050105D42,50NMSCNONREF//B1
+:86:/NAME/Whole Foods/REMI/Groceries/
+:62F:C240131USD857,50
+-
+`
+
+func TestImportUseCase_OFX(t *testing.T) {
+	f := newImportFixture(t)
+	ctx := context.Background()
+
+	preview, err := f.useCase.PreviewFile(ctx, f.fileInput(entity.StatementFormatOFX, testOFX))
+	require.NoError(t, err)
+	require.Len(t, preview.Drafts, 2)
+	assert.Empty(t, preview.Duplicates)
+	assert.True(t, preview.Drafts[0].CounterpartyID.Unwrap().Equals(f.grocer.ID))
+
+	difference, err := preview.BalanceDifference()
+	require.NoError(t, err)
+	assert.True(t, difference.Unwrap().IsZero())
+
+	result, err := f.useCase.CommitImport(ctx, preview)
+	require.NoError(t, err)
+	assert.Len(t, result.Transactions, 2)
+	assert.Empty(t, result.Skipped)
+	assert.Equal(t, "3457.50 USD", f.accounts.stored[f.account.ID.String()].Balance.String())
+	// Both lines land on the chosen item until they are categorised: the inflow offsets the spend
+	assert.Equal(t, "-2457.50 USD", f.items[f.item.ID.String()].GetMonthlyBudget(2024, 1).ActualAmount.String())
+
+	// Committing the same preview again records nothing
+	result, err = f.useCase.CommitImport(ctx, preview)
+	require.NoError(t, err)
+	assert.Empty(t, result.Transactions)
+	assert.Equal(t, []string{"A1", "A2"}, result.Skipped)
+	assert.Len(t, f.transactions.stored, 2)
+
+	// Re-importing the file reports every line as a duplicate
+	preview, err = f.useCase.PreviewFile(ctx, f.fileInput(entity.StatementFormatOFX, testOFX))
+	require.NoError(t, err)
+	assert.Empty(t, preview.Drafts)
+	assert.Len(t, preview.Duplicates, 2)
+}
+
+func TestImportUseCase_OverdraftInFileOrder(t *testing.T) {
+	f := newImportFixture(t)
+	ctx := context.Background()
+
+	// The spend is booked before the payroll that covers it: the bank's own record is not refused
+	account := f.accounts.stored[f.account.ID.String()]
+	account.Balance = mustMoney(t, "0.00", money.CurrencyUSD)
+	f.accounts.stored[f.account.ID.String()] = account
+
+	preview, err := f.useCase.PreviewFile(ctx, f.fileInput(entity.StatementFormatOFX, testOFX))
+	require.NoError(t, err)
+
+	result, err := f.useCase.CommitImport(ctx, preview)
+	require.NoError(t, err)
+	assert.Len(t, result.Transactions, 2)
+	assert.Equal(t, "2457.50 USD", f.accounts.stored[f.account.ID.String()].Balance.String())
+}
+
+func TestImportUseCase_SettlesHold(t *testing.T) {
+	f := newImportFixture(t)
+	ctx := context.Background()
+
+	hold, err := entity.NewTransaction(f.ledgerID, f.account.ID, f.item.ID, mustMoney(t, "-40.00", money.CurrencyUSD),
+		"Whole Foods pre-auth", time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NoError(t, hold.MarkPending())
+	f.transactions.stored[hold.ID.String()] = hold
+
+	preview, err := f.useCase.PreviewFile(ctx, f.fileInput(entity.StatementFormatOFX, testOFX))
+	require.NoError(t, err)
+
+	result, err := f.useCase.CommitImport(ctx, preview)
+	require.NoError(t, err)
+	require.Len(t, result.Transactions, 2)
+	assert.Equal(t, []string{"A1"}, result.Settled)
+	assert.Len(t, f.transactions.stored, 2, "the settlement posts the hold instead of adding a transaction")
+
+	settled := f.transactions.stored[hold.ID.String()]
+	assert.True(t, settled.PostingStatus.IsPosted())
+	assert.Equal(t, "-42.50 USD", settled.Amount.String())
+	assert.Equal(t, "-40.00 USD", settled.HoldAmount.Unwrap().String())
+	assert.Equal(t, "Whole Foods pre-auth", settled.Description)
+	assert.Equal(t, "A1", settled.ImportID)
+	assert.Equal(t, "3457.50 USD", f.accounts.stored[f.account.ID.String()].Balance.String())
+
+	// Re-importing the file finds the settled hold by its import ID
+	preview, err = f.useCase.PreviewFile(ctx, f.fileInput(entity.StatementFormatOFX, testOFX))
+	require.NoError(t, err)
+	assert.Empty(t, preview.Drafts)
+	assert.Len(t, preview.Duplicates, 2)
+}
+
+func TestImportUseCase_CAMT053(t *testing.T) {
+	f := newImportFixture(t)
+	f.recordOpeningDeposit(t)
+	ctx := context.Background()
+
+	in := f.fileInput(entity.StatementFormatCAMT053, testCAMT053)
+	in.StatementAccountID = "US00KYBR00001234"
+	preview, err := f.useCase.PreviewFile(ctx, in)
+	require.NoError(t, err)
+
+	// The pending entry is left out
+	require.Len(t, preview.Drafts, 2)
+	grocery := preview.Drafts[0]
+	assert.Equal(t, "R1", grocery.ImportID)
+	assert.Equal(t, "-42.50 USD", grocery.Amount.String())
+	assert.Equal(t, "2024-01-05", grocery.TransactionDate.Format(time.DateOnly), "booking date, not value date")
+	assert.Equal(t, "Groceries\nAccount: US00WHFD", grocery.Notes)
+	assert.True(t, grocery.CounterpartyID.Unwrap().Equals(f.grocer.ID))
+	assert.True(t, strings.HasPrefix(preview.Drafts[1].ImportID, "camt:"))
+
+	opening, err := preview.OpeningDifference()
+	require.NoError(t, err)
+	assert.True(t, opening.Unwrap().IsZero())
+	closing, err := preview.BalanceDifference()
+	require.NoError(t, err)
+	assert.True(t, closing.Unwrap().IsZero())
+
+	_, err = f.useCase.CommitImport(ctx, preview)
+	require.NoError(t, err)
+
+	preview, err = f.useCase.PreviewFile(ctx, f.fileInput(entity.StatementFormatCAMT053, testCAMT053))
+	require.NoError(t, err)
+	assert.Empty(t, preview.Drafts)
+	assert.Len(t, preview.Duplicates, 2)
+}
+
+func TestImportUseCase_MT940(t *testing.T) {
+	f := newImportFixture(t)
+	f.recordOpeningDeposit(t)
+
+	preview, err := f.useCase.PreviewFile(context.Background(), f.fileInput(entity.StatementFormatMT940, testMT940))
+	require.NoError(t, err)
+	require.Len(t, preview.Drafts, 1)
+	assert.Equal(t, "B1", preview.Drafts[0].ImportID)
+	assert.Equal(t, "Whole Foods", preview.Drafts[0].Description)
+	assert.Equal(t, "Groceries", preview.Drafts[0].Notes)
+
+	// The bank opened the statement 100.00 lower than the account was recorded, and closed it the same
+	opening, err := preview.OpeningDifference()
+	require.NoError(t, err)
+	assert.Equal(t, "-100.00 USD", opening.Unwrap().String())
+	closing, err := preview.BalanceDifference()
+	require.NoError(t, err)
+	assert.Equal(t, "-100.00 USD", closing.Unwrap().String())
+}
+
+func TestImportUseCase_Rules(t *testing.T) {
+	f := newImportFixture(t)
+	ctx := context.Background()
+
+	food, err := budgetEntity.NewItem(f.ledgerID, "Food", "", budgetEntity.ItemTypeExpense, money.CurrencyUSD)
+	require.NoError(t, err)
+	transferItem, err := budgetEntity.NewItem(f.ledgerID, "Transfers", "", budgetEntity.ItemTypeTransfer, money.CurrencyUSD)
+	require.NoError(t, err)
+	f.items[food.ID.String()] = food
+	f.items[transferItem.ID.String()] = transferItem
+
+	savings, err := entity.NewAccount(f.ledgerID, "Savings", "", entity.AccountTypeSavings, money.CurrencyUSD)
+	require.NoError(t, err)
+	savings.Balance = mustMoney(t, "5000.00", money.CurrencyUSD)
+	f.accounts.stored[savings.ID.String()] = *savings
+
+	grocery, err := entity.NewRule(f.ledgerID, "Groceries", 0,
+		entity.RuleConditions{DescriptionPattern: `(?i)whole\s+foods`},
+		entity.RuleActions{ItemID: optional.Some(food.ID), Tag: "groceries"})
+	require.NoError(t, err)
+	withdrawal, err := entity.NewRule(f.ledgerID, "Savings withdrawal", 1,
+		entity.RuleConditions{MinAmount: optional.Some(mustMoney(t, "1000.00", money.CurrencyUSD))},
+		entity.RuleActions{ItemID: optional.Some(transferItem.ID), TransferAccountID: optional.Some(savings.ID)})
+	require.NoError(t, err)
+	// Sets an ordinary item before the transfer rule: the transfer still gets the transfer item
+	payroll, err := entity.NewRule(f.ledgerID, "Payroll", -1,
+		entity.RuleConditions{DescriptionContains: "payroll"},
+		entity.RuleActions{ItemID: optional.Some(food.ID)})
+	require.NoError(t, err)
+	f.rules[grocery.ID.String()] = grocery
+	f.rules[withdrawal.ID.String()] = withdrawal
+	f.rules[payroll.ID.String()] = payroll
+
+	preview, err := f.useCase.PreviewFile(ctx, f.fileInput(entity.StatementFormatOFX, testOFX))
+	require.NoError(t, err)
+	require.Len(t, preview.Drafts, 2)
+	assert.True(t, preview.Drafts[0].ItemID.Equals(food.ID))
+	assert.Equal(t, []string{"groceries"}, preview.Drafts[0].Tags)
+	assert.True(t, preview.Drafts[0].CounterpartyID.Unwrap().Equals(f.grocer.ID), "payees are still matched by name")
+	assert.Equal(t, map[string]entity.AccountID{"A2": savings.ID}, preview.TransferAccounts)
+	assert.True(t, preview.Drafts[1].ItemID.Equals(transferItem.ID))
+
+	result, err := f.useCase.CommitImport(ctx, preview)
+	require.NoError(t, err)
+	require.Len(t, result.Transactions, 2)
+	assert.False(t, result.Transactions[0].IsTransfer())
+	assert.True(t, result.Transactions[1].IsTransfer())
+	assert.Equal(t, "A2", result.Transactions[1].ImportID)
+
+	assert.Len(t, f.transactions.stored, 1)
+	assert.Len(t, f.transfers.stored, 1)
+	assert.Equal(t, "3457.50 USD", f.accounts.stored[f.account.ID.String()].Balance.String())
+	assert.Equal(t, "2500.00 USD", f.accounts.stored[savings.ID.String()].Balance.String())
+	assert.Equal(t, "42.50 USD", f.items[food.ID.String()].GetMonthlyBudget(2024, 1).ActualAmount.String())
+}
+
+func TestImportUseCase_PreviewFile_Invalid(t *testing.T) {
+	f := newImportFixture(t)
+
+	tests := []struct {
+		name        string
+		in          ImportFileInput
+		errContains string
+	}{
+		{
+			name:        "currency mismatch",
+			in:          f.fileInput(entity.StatementFormatOFX, strings.Replace(testOFX, "<CURDEF>USD", "<CURDEF>EUR", 1)),
+			errContains: "currency mismatch",
+		},
+		{
+			name: "unknown statement account",
+			in: func() ImportFileInput {
+				in := f.fileInput(entity.StatementFormatOFX, testOFX)
+				in.StatementAccountID = "9999"
+				return in
+			}(),
+			errContains: "no statement for account 9999",
+		},
+		{
+			name:        "not OFX",
+			in:          f.fileInput(entity.StatementFormatOFX, "Date,Amount"),
+			errContains: "not an OFX file",
+		},
+		{
+			name:        "not camt.053",
+			in:          f.fileInput(entity.StatementFormatCAMT053, testMT940),
+			errContains: "not a camt.053 file",
+		},
+		{
+			name:        "not MT940",
+			in:          f.fileInput(entity.StatementFormatMT940, testCAMT053),
+			errContains: "not an MT940 file",
+		},
+		{
+			name:        "MT940 currency mismatch",
+			in:          f.fileInput(entity.StatementFormatMT940, strings.ReplaceAll(testMT940, "USD", "EUR")),
+			errContains: "currency mismatch",
+		},
+		{
+			name:        "unknown format",
+			in:          f.fileInput("QIF", testOFX),
+			errContains: "invalid statement format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.useCase.PreviewFile(context.Background(), tt.in)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestImportUseCase_CSV(t *testing.T) {
+	f := newImportFixture(t)
+	ctx := context.Background()
+
+	profile, err := f.useCase.CreateCSVProfile(ctx, CreateCSVProfileInput{
+		LedgerID:  f.ledgerID,
+		AccountID: f.account.ID,
+		Name:      "Card export",
+		Format: entity.CSVFormat{
+			Delimiter:          ';',
+			SkipRows:           1,
+			DateFormat:         "DD.MM.YYYY",
+			DecimalSeparator:   ',',
+			ThousandsSeparator: '.',
+			SignConvention:     entity.CSVSignOutflowPositive,
+			Columns: entity.CSVColumns{
+				Date:   0,
+				Payee:  optional.Some(1),
+				Amount: optional.Some(2),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	profiles, err := f.useCase.ListCSVProfiles(ctx, f.ledgerID, f.account.ID)
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+
+	file := "Datum;Empfänger;Betrag\n" +
+		"05.01.2024;Whole Foods;42,50\n" +
+		"06.01.2024;Refund;-1.000,00\n" +
+		"07.01.2024;Broken;zwölf\n"
+
+	preview, err := f.useCase.PreviewCSV(ctx, f.csvInput(profile.ID, file))
+	require.NoError(t, err)
+	require.Len(t, preview.Drafts, 2)
+	assert.Equal(t, "-42.50 USD", preview.Drafts[0].Amount.String())
+	assert.True(t, preview.Drafts[0].CounterpartyID.Unwrap().Equals(f.grocer.ID))
+	assert.Equal(t, "1000.00 USD", preview.Drafts[1].Amount.String())
+	assert.Equal(t, []entity.RejectedLine{{Line: 4, Reason: `invalid amount "zwölf"`}}, preview.Rejected)
+
+	_, err = f.useCase.CommitImport(ctx, preview)
+	require.NoError(t, err)
+	assert.Equal(t, "1957.50 USD", f.accounts.stored[f.account.ID.String()].Balance.String())
+
+	// Rows without a bank reference are still recognised when the file is imported again
+	preview, err = f.useCase.PreviewCSV(ctx, f.csvInput(profile.ID, file))
+	require.NoError(t, err)
+	assert.Empty(t, preview.Drafts)
+	assert.Len(t, preview.Duplicates, 2)
+
+	// A row entered by hand before the export is matched to it rather than recorded twice
+	entry, err := entity.NewTransaction(f.ledgerID, f.account.ID, f.item.ID, mustMoney(t, "-12.00", money.CurrencyUSD),
+		"Coffee beans", time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	f.transactions.stored[entry.ID.String()] = entry
+	pending, err := entity.NewTransaction(f.ledgerID, f.account.ID, f.item.ID, mustMoney(t, "-8.00", money.CurrencyUSD),
+		"Parking", time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NoError(t, pending.MarkPending())
+	f.transactions.stored[pending.ID.String()] = pending
+
+	file = "Datum;Empfänger;Betrag\n" +
+		"10.01.2024;Roastery;12,00\n" +
+		"10.01.2024;City Parking;8,40\n"
+	preview, err = f.useCase.PreviewCSV(ctx, f.csvInput(profile.ID, file))
+	require.NoError(t, err)
+	require.Len(t, preview.Drafts, 2)
+
+	result, err := f.useCase.CommitImport(ctx, preview)
+	require.NoError(t, err)
+	require.Len(t, result.Matched, 1)
+	require.Len(t, result.Settled, 1)
+	assert.Equal(t, result.Matched[0], f.transactions.stored[entry.ID.String()].ImportID)
+	assert.Equal(t, "-8.40 USD", f.transactions.stored[pending.ID.String()].Amount.String())
+	assert.Len(t, f.transactions.stored, 4, "neither row is recorded as a new transaction")
+	assert.Equal(t, "1949.10 USD", f.accounts.stored[f.account.ID.String()].Balance.String())
+
+	preview, err = f.useCase.PreviewCSV(ctx, f.csvInput(profile.ID, file))
+	require.NoError(t, err)
+	assert.Empty(t, preview.Drafts)
+
+	require.NoError(t, f.useCase.DeleteCSVProfile(ctx, f.ledgerID, profile.ID))
+	_, err = f.useCase.PreviewCSV(ctx, f.csvInput(profile.ID, file))
+	require.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+// Helper functions
+
+type importFixture struct {
+	ledgerID     ledgerEntity.LedgerID
+	account      *entity.Account
+	item         *budgetEntity.Item
+	grocer       *counterpartyEntity.Counterparty
+	accounts     *fakeAccountRepository
+	transactions *fakeTransactionRepository
+	transfers    *fakeTransferRepository
+	items        fakeItemRepository
+	rules        fakeRuleRepository
+	useCase      *ImportUseCase
+}
+
+func newImportFixture(t *testing.T) *importFixture {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	account, err := entity.NewAccount(ledgerID, "Checking", "", entity.AccountTypeChecking, money.CurrencyUSD)
+	require.NoError(t, err)
+	account.Balance = mustMoney(t, "1000.00", money.CurrencyUSD)
+
+	item, err := budgetEntity.NewItem(ledgerID, "Uncategorized", "", budgetEntity.ItemTypeExpense, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	grocer, err := counterpartyEntity.NewCounterparty(ledgerID, "Whole Foods", counterpartyEntity.CounterpartyTypeBusiness, "")
+	require.NoError(t, err)
+
+	f := &importFixture{
+		ledgerID:     ledgerID,
+		account:      account,
+		item:         item,
+		grocer:       grocer,
+		accounts:     &fakeAccountRepository{stored: map[string]entity.Account{account.ID.String(): *account}},
+		transactions: &fakeTransactionRepository{stored: map[string]*entity.Transaction{}},
+		transfers:    &fakeTransferRepository{stored: map[string]*entity.Transfer{}},
+		items:        fakeItemRepository{item.ID.String(): item},
+		rules:        fakeRuleRepository{},
+	}
+	counterparties := fakeCounterpartyRepository{grocer}
+	f.useCase = NewImportUseCase(&fakeTransactor{}, f.accounts, f.transactions, newFakeJournalEntryRepository(), f.items, counterparties,
+		fakeCSVProfileRepository{}, f.transfers, f.rules)
+	return f
+}
+
+func (f *importFixture) fileInput(format entity.StatementFormat, file string) ImportFileInput {
+	return ImportFileInput{
+		LedgerID:  f.ledgerID,
+		AccountID: f.account.ID,
+		ItemID:    f.item.ID,
+		Format:    format,
+		File:      strings.NewReader(file),
+	}
+}
+
+// recordOpeningDeposit records the account's 1000.00 balance as a transaction before the test statements
+func (f *importFixture) recordOpeningDeposit(t *testing.T) {
+	t.Helper()
+
+	tx, err := entity.NewTransaction(f.ledgerID, f.account.ID, f.item.ID, mustMoney(t, "1000.00", money.CurrencyUSD),
+		"Opening deposit", time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	f.transactions.stored[tx.ID.String()] = tx
+}
+
+type fakeCounterpartyRepository []*counterpartyEntity.Counterparty
+
+func (f fakeCounterpartyRepository) ListByLedger(_ context.Context, ledgerID ledgerEntity.LedgerID) ([]*counterpartyEntity.Counterparty, error) {
+	var counterparties []*counterpartyEntity.Counterparty
+	for _, c := range f {
+		if c.LedgerID.Equals(ledgerID) {
+			counterparties = append(counterparties, c)
+		}
+	}
+	return counterparties, nil
+}
+
+func (f *importFixture) csvInput(profileID entity.CSVProfileID, file string) ImportCSVInput {
+	return ImportCSVInput{
+		LedgerID:  f.ledgerID,
+		ProfileID: profileID,
+		ItemID:    f.item.ID,
+		File:      strings.NewReader(file),
+	}
+}
+
+type fakeCSVProfileRepository map[string]*entity.CSVProfile
+
+func (f fakeCSVProfileRepository) Create(_ context.Context, profile *entity.CSVProfile) error {
+	f[profile.ID.String()] = profile
+	return nil
+}
+
+func (f fakeCSVProfileRepository) Update(_ context.Context, profile *entity.CSVProfile) error {
+	if _, ok := f[profile.ID.String()]; !ok {
+		return repository.ErrNotFound
+	}
+	f[profile.ID.String()] = profile
+	return nil
+}
+
+func (f fakeCSVProfileRepository) Delete(_ context.Context, _ ledgerEntity.LedgerID, id entity.CSVProfileID) error {
+	if _, ok := f[id.String()]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(f, id.String())
+	return nil
+}
+
+func (f fakeCSVProfileRepository) GetByID(_ context.Context, ledgerID ledgerEntity.LedgerID, id entity.CSVProfileID) (*entity.CSVProfile, error) {
+	profile, ok := f[id.String()]
+	if !ok || !profile.LedgerID.Equals(ledgerID) {
+		return nil, repository.ErrNotFound
+	}
+	return profile, nil
+}
+
+func (f fakeCSVProfileRepository) ListByAccount(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) ([]*entity.CSVProfile, error) {
+	var profiles []*entity.CSVProfile
+	for _, profile := range f {
+		if profile.LedgerID.Equals(ledgerID) && profile.AccountID.Equals(accountID) {
+			profiles = append(profiles, profile)
+		}
+	}
+	slices.SortFunc(profiles, func(a, b *entity.CSVProfile) int { return strings.Compare(a.Name, b.Name) })
+	return profiles, nil
+}
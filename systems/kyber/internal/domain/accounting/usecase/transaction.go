@@ -0,0 +1,496 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	budgetRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/repository"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	counterpartyRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// CreateTransactionInput describes a transaction entered by hand
+type CreateTransactionInput struct {
+	LedgerID        ledgerEntity.LedgerID
+	AccountID       entity.AccountID
+	ItemID          budgetEntity.ItemID // Used unless one of the ledger's rules assigns another item
+	CounterpartyID  optional.Option[counterpartyEntity.CounterpartyID]
+	Amount          money.Money                  // Positive for inflows, negative for outflows; settled amount if foreign
+	OriginalAmount  optional.Option[money.Money] // Amount charged in a foreign currency, with the sign of Amount
+	FXFee           optional.Option[money.Money] // Part of Amount charged as an FX fee on OriginalAmount
+	Description     string
+	Notes           string
+	TransactionDate time.Time
+	Tags            []string
+	Pending         bool // Recorded as an authorisation hold that counts towards the current balance once posted
+}
+
+// SplitTransactionInput describes how a transaction's amount is spread across budget items
+type SplitTransactionInput struct {
+	LedgerID      ledgerEntity.LedgerID
+	TransactionID entity.TransactionID
+	Lines         []entity.SplitLine // Must sum to the transaction amount
+}
+
+// PostTransactionInput describes how a pending hold settled
+type PostTransactionInput struct {
+	LedgerID      ledgerEntity.LedgerID
+	TransactionID entity.TransactionID
+	Amount        optional.Option[money.Money] // Amount it settled at; the amount held if none
+	PostedDate    time.Time                    // Date it settled on; the hold's date if zero
+}
+
+// SearchTransactionsInput describes the page of a ledger's matching transactions to return
+type SearchTransactionsInput struct {
+	LedgerID ledgerEntity.LedgerID
+	Filter   entity.TransactionFilter
+	Limit    int // Defaults to 50, at most 500
+	Offset   int
+}
+
+// UpdateTagsInput describes tags to add to and remove from a transaction
+type UpdateTagsInput struct {
+	LedgerID      ledgerEntity.LedgerID
+	TransactionID entity.TransactionID
+	Add           []string
+	Remove        []string
+}
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+// TransactionUseCase creates transactions and edits how they are assigned to budget items, keeping
+// account balances, each item's monthly actuals and the journal in step in the same database transaction.
+type TransactionUseCase struct {
+	transactor     repository.Transactor
+	accounts       repository.AccountRepository
+	transactions   repository.TransactionRepository
+	journal        repository.JournalEntryRepository
+	transfers      repository.TransferRepository
+	items          budgetRepository.ItemRepository
+	counterparties counterpartyRepository.CounterpartyRepository
+	rules          repository.RuleRepository
+}
+
+// NewTransactionUseCase creates a new TransactionUseCase
+func NewTransactionUseCase(
+	transactor repository.Transactor,
+	accounts repository.AccountRepository,
+	transactions repository.TransactionRepository,
+	journal repository.JournalEntryRepository,
+	transfers repository.TransferRepository,
+	items budgetRepository.ItemRepository,
+	counterparties counterpartyRepository.CounterpartyRepository,
+	rules repository.RuleRepository,
+) *TransactionUseCase {
+	return &TransactionUseCase{
+		transactor:     transactor,
+		accounts:       accounts,
+		transactions:   transactions,
+		journal:        journal,
+		transfers:      transfers,
+		items:          items,
+		counterparties: counterparties,
+		rules:          rules,
+	}
+}
+
+// CreateTransaction records a transaction entered by hand after applying the ledger's enabled rules to it.
+// A transaction a rule marks as a transfer is recorded as one, and its side of the transfer is returned.
+func (u *TransactionUseCase) CreateTransaction(ctx context.Context, in CreateTransactionInput) (*entity.Transaction, error) {
+	tx, err := entity.NewTransaction(in.LedgerID, in.AccountID, in.ItemID, in.Amount, in.Description, in.TransactionDate)
+	if err != nil {
+		return nil, err
+	}
+	tx.Notes = in.Notes
+	if in.CounterpartyID.IsSome() {
+		tx.SetCounterparty(in.CounterpartyID.Unwrap())
+	}
+	for _, tag := range in.Tags {
+		if err := tx.AddTag(tag); err != nil {
+			return nil, err
+		}
+	}
+	if in.OriginalAmount.IsSome() {
+		noFee, err := money.Zero(in.Amount.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.SetForeignAmount(in.OriginalAmount.Unwrap(), in.Amount, in.FXFee.UnwrapOr(noFee)); err != nil {
+			return nil, err
+		}
+	} else if in.FXFee.IsSome() {
+		return nil, fmt.Errorf("an FX fee needs the original amount it was charged on")
+	}
+	if in.Pending {
+		if err := tx.MarkPending(); err != nil {
+			return nil, err
+		}
+	}
+
+	err = u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		rules, err := u.rules.ListEnabled(ctx, in.LedgerID)
+		if err != nil {
+			return fmt.Errorf("failed to list rules: %w", err)
+		}
+
+		counterparties, err := u.counterparties.ListByLedger(ctx, in.LedgerID)
+		if err != nil {
+			return fmt.Errorf("failed to list counterparties: %w", err)
+		}
+
+		outcome, err := entity.ApplyRules(tx, rules, counterparties)
+		if err != nil {
+			return err
+		}
+		if outcome.TransferAccountID.IsSome() {
+			tx, err = u.createTransfer(ctx, tx, outcome.TransferAccountID.Unwrap())
+			return err
+		}
+
+		accounts, err := u.accounts.GetForUpdate(ctx, in.LedgerID, in.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		account := accounts[0]
+
+		if err := account.ApplyTransaction(tx); err != nil {
+			return err
+		}
+
+		changes, err := entity.ItemActualChanges(nil, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := applyItemActuals(ctx, u.items, in.LedgerID, changes); err != nil {
+			return err
+		}
+
+		if err := u.transactions.Create(ctx, tx); err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		if err := postJournalEntries(ctx, u.journal, tx); err != nil {
+			return err
+		}
+
+		if err := u.accounts.UpdateBalance(ctx, account); err != nil {
+			return fmt.Errorf("failed to update account balance: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// SplitTransaction replaces the transaction's budget item (or existing split) with the given lines,
+// moving actuals between the affected items
+func (u *TransactionUseCase) SplitTransaction(ctx context.Context, in SplitTransactionInput) (*entity.Transaction, error) {
+	return u.updateLines(ctx, in.LedgerID, in.TransactionID, func(tx *entity.Transaction) error {
+		return tx.Split(in.Lines)
+	})
+}
+
+// UnsplitTransaction assigns the whole transaction back to a single budget item
+func (u *TransactionUseCase) UnsplitTransaction(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+	itemID budgetEntity.ItemID,
+) (*entity.Transaction, error) {
+	return u.updateLines(ctx, ledgerID, id, func(tx *entity.Transaction) error {
+		tx.UpdateItem(itemID)
+		return nil
+	})
+}
+
+// PostTransaction settles a pending hold, adding it to the account's current balance and moving
+// budget actuals by the difference from the amount held
+func (u *TransactionUseCase) PostTransaction(ctx context.Context, in PostTransactionInput) (*entity.Transaction, error) {
+	return u.updatePosting(ctx, in.LedgerID, in.TransactionID, func(tx *entity.Transaction) error {
+		return tx.Post(in.Amount.UnwrapOr(tx.Amount), in.PostedDate)
+	})
+}
+
+// VoidTransaction releases a pending hold that will not settle, removing it from budget actuals
+func (u *TransactionUseCase) VoidTransaction(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+) (*entity.Transaction, error) {
+	return u.updatePosting(ctx, ledgerID, id, func(tx *entity.Transaction) error {
+		return tx.Void()
+	})
+}
+
+// ExpireHolds voids the ledger's pending holds dated more than maxAge before now, or
+// entity.DefaultHoldExpiry if maxAge is zero. It is meant to run periodically and returns the voided holds.
+func (u *TransactionUseCase) ExpireHolds(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	now time.Time,
+	maxAge time.Duration,
+) ([]*entity.Transaction, error) {
+	if maxAge == 0 {
+		maxAge = entity.DefaultHoldExpiry
+	}
+	if maxAge < 0 {
+		return nil, fmt.Errorf("hold expiry cannot be negative: %s", maxAge)
+	}
+
+	pending, err := u.transactions.ListPending(ctx, ledgerID, optional.None[entity.AccountID]())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending transactions: %w", err)
+	}
+
+	var voided []*entity.Transaction
+	for _, hold := range pending {
+		if !hold.IsHoldExpired(now, maxAge) {
+			continue
+		}
+
+		tx, err := u.VoidTransaction(ctx, ledgerID, hold.ID)
+		if err != nil {
+			return voided, fmt.Errorf("failed to void hold %s: %w", hold.ID, err)
+		}
+		voided = append(voided, tx)
+	}
+	return voided, nil
+}
+
+// SearchTransactions returns one page of the ledger's transactions matching the filter, newest first,
+// with the count and per-currency totals of all matching transactions
+func (u *TransactionUseCase) SearchTransactions(
+	ctx context.Context,
+	in SearchTransactionsInput,
+) (*entity.TransactionPage, error) {
+	filter, err := in.Filter.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	limit := in.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit < 0 || limit > maxSearchLimit {
+		return nil, fmt.Errorf("page size must be between 1 and %d: %d", maxSearchLimit, limit)
+	}
+	if in.Offset < 0 {
+		return nil, fmt.Errorf("page offset cannot be negative: %d", in.Offset)
+	}
+
+	page, err := u.transactions.Search(ctx, in.LedgerID, filter, limit, in.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transactions: %w", err)
+	}
+	return page, nil
+}
+
+// SpendByOriginalCurrency sums the ledger's transactions matching the filter per currency they were charged in,
+// e.g. with the tag of a trip to see what it cost in each currency paid in. Transfers and voided holds are left out.
+func (u *TransactionUseCase) SpendByOriginalCurrency(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	filter entity.TransactionFilter,
+) ([]entity.OriginalCurrencySpend, error) {
+	filter, err := filter.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	spend, err := u.transactions.SumByOriginalCurrency(ctx, ledgerID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum transactions by original currency: %w", err)
+	}
+	return spend, nil
+}
+
+// UpdateTags adds and removes tags of a transaction. Tags removed are removed after those added.
+func (u *TransactionUseCase) UpdateTags(ctx context.Context, in UpdateTagsInput) (*entity.Transaction, error) {
+	tx, err := u.transactions.GetByID(ctx, in.LedgerID, in.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	for _, tag := range in.Add {
+		if err := tx.AddTag(tag); err != nil {
+			return nil, err
+		}
+	}
+	for _, tag := range in.Remove {
+		tx.RemoveTag(tag)
+	}
+
+	if err := u.transactions.UpdateTags(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to update transaction tags: %w", err)
+	}
+	return tx, nil
+}
+
+// createTransfer records tx as a transfer with the other account, returning tx's side of it
+func (u *TransactionUseCase) createTransfer(
+	ctx context.Context,
+	tx *entity.Transaction,
+	otherID entity.AccountID,
+) (*entity.Transaction, error) {
+	if err := checkTransferItem(ctx, u.items, tx.LedgerID, tx.ItemID); err != nil {
+		return nil, err
+	}
+
+	if otherID.Equals(tx.AccountID) {
+		return nil, fmt.Errorf("cannot transfer to the same account")
+	}
+
+	accounts, err := u.accounts.GetForUpdate(ctx, tx.LedgerID, tx.AccountID, otherID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock accounts: %w", err)
+	}
+
+	transfer, err := entity.NewTransferFromTransaction(tx, accounts[0], accounts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.transfers.Create(ctx, transfer); err != nil {
+		return nil, fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	if err := postJournalEntries(ctx, u.journal, transfer.Outgoing, transfer.Incoming); err != nil {
+		return nil, err
+	}
+
+	for _, account := range accounts {
+		if err := u.accounts.UpdateBalance(ctx, account); err != nil {
+			return nil, fmt.Errorf("failed to update balance of account %s: %w", account.ID, err)
+		}
+	}
+
+	if tx.IsCredit() {
+		return transfer.Incoming, nil
+	}
+	return transfer.Outgoing, nil
+}
+
+func (u *TransactionUseCase) updateLines(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+	update func(tx *entity.Transaction) error,
+) (*entity.Transaction, error) {
+	var tx *entity.Transaction
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		tx, err = u.transactions.GetByID(ctx, ledgerID, id)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+
+		if tx.IsTransfer() {
+			return fmt.Errorf("transaction %s is part of a transfer and must be edited through it", id)
+		}
+
+		before := *tx
+		if err := update(tx); err != nil {
+			return err
+		}
+
+		changes, err := entity.ItemActualChanges(&before, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := applyItemActuals(ctx, u.items, ledgerID, changes); err != nil {
+			return err
+		}
+
+		if err := u.transactions.UpdateSplits(ctx, tx); err != nil {
+			return fmt.Errorf("failed to update transaction splits: %w", err)
+		}
+
+		return syncJournalEntries(ctx, u.journal, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// updatePosting changes the posting status of a transaction with it and its account locked, keeping the balance
+// and budget actuals in step
+func (u *TransactionUseCase) updatePosting(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+	update func(tx *entity.Transaction) error,
+) (*entity.Transaction, error) {
+	var tx *entity.Transaction
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		// Locked so a concurrent post, void or import settling the same hold sees the status this one commits
+		tx, err = u.transactions.GetForUpdate(ctx, ledgerID, id)
+		if err != nil {
+			return fmt.Errorf("failed to lock transaction: %w", err)
+		}
+
+		accounts, err := u.accounts.GetForUpdate(ctx, ledgerID, tx.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		account := accounts[0]
+
+		before := *tx
+		if err := update(tx); err != nil {
+			return err
+		}
+
+		// Only posting changes the balance; ApplyTransaction leaves it alone for pending and voided ones
+		if err := account.ApplyTransaction(tx); err != nil {
+			return err
+		}
+
+		changes, err := entity.ItemActualChanges(&before, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := applyItemActuals(ctx, u.items, ledgerID, changes); err != nil {
+			return err
+		}
+
+		if err := u.transactions.UpdatePosting(ctx, tx); err != nil {
+			return fmt.Errorf("failed to update transaction posting: %w", err)
+		}
+
+		if err := syncJournalEntries(ctx, u.journal, tx); err != nil {
+			return err
+		}
+
+		if err := u.accounts.UpdateBalance(ctx, account); err != nil {
+			return fmt.Errorf("failed to update account balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
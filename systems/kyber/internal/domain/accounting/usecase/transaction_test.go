@@ -0,0 +1,471 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestTransactionUseCase_CreateTransaction(t *testing.T) {
+	f := newRuleFixture(t)
+	ctx := context.Background()
+	f.createGroceryRule(t)
+
+	_, err := f.rules.CreateRule(ctx, CreateRuleInput{
+		LedgerID:   f.ledgerID,
+		Name:       "Retail tag",
+		Priority:   10,
+		Conditions: entity.RuleConditions{CounterpartyType: optional.Some(counterpartyEntity.CounterpartyTypeRetailer)},
+		Actions:    entity.RuleActions{ItemID: optional.Some(f.uncategorized.ID), Tag: "Shopping", Note: "Check receipt"},
+	})
+	require.NoError(t, err)
+
+	tx, err := f.useCase.CreateTransaction(ctx, CreateTransactionInput{
+		LedgerID:        f.ledgerID,
+		AccountID:       f.checking.ID,
+		ItemID:          f.uncategorized.ID,
+		Amount:          mustMoney(t, "-42.50", money.CurrencyUSD),
+		Description:     "Whole Foods Market",
+		TransactionDate: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		Tags:            []string{"weekly"},
+	})
+	require.NoError(t, err)
+	assert.True(t, tx.ItemID.Equals(f.food.ID), "the first matching rule sets the item")
+	assert.True(t, tx.CounterpartyID.Unwrap().Equals(f.grocer.ID))
+	assert.Equal(t, []string{"weekly", "shopping"}, tx.Tags, "a later rule matches the counterparty set by an earlier one")
+	assert.Equal(t, "Check receipt", tx.Notes)
+
+	assert.Contains(t, f.transactions.stored, tx.ID.String())
+	assert.Equal(t, "957.50 USD", f.accounts.stored[f.checking.ID.String()].Balance.String())
+	assert.Equal(t, "-42.50 USD", f.journal.balance(t, f.checking.ID, money.CurrencyUSD), "the journal records the change")
+	tracking := f.items[f.food.ID.String()].GetMonthlyBudget(2024, 3)
+	require.NotNil(t, tracking)
+	assert.Equal(t, "42.50 USD", tracking.ActualAmount.String())
+
+	// No rule matches: the given item is kept
+	tx, err = f.useCase.CreateTransaction(ctx, CreateTransactionInput{
+		LedgerID:    f.ledgerID,
+		AccountID:   f.checking.ID,
+		ItemID:      f.uncategorized.ID,
+		Amount:      mustMoney(t, "-12.00", money.CurrencyUSD),
+		Description: "Cinema",
+	})
+	require.NoError(t, err)
+	assert.True(t, tx.ItemID.Equals(f.uncategorized.ID))
+	assert.False(t, tx.HasCounterparty())
+}
+
+func TestTransactionUseCase_CreateTransaction_Transfer(t *testing.T) {
+	f := newRuleFixture(t)
+	ctx := context.Background()
+
+	_, err := f.rules.CreateRule(ctx, CreateRuleInput{
+		LedgerID:   f.ledgerID,
+		Name:       "Savings",
+		Conditions: entity.RuleConditions{DescriptionPattern: `(?i)^transfer to savings`},
+		Actions: entity.RuleActions{
+			ItemID:            optional.Some(f.transferItem.ID),
+			TransferAccountID: optional.Some(f.savings.ID),
+		},
+	})
+	require.NoError(t, err)
+
+	tx, err := f.useCase.CreateTransaction(ctx, CreateTransactionInput{
+		LedgerID:    f.ledgerID,
+		AccountID:   f.checking.ID,
+		ItemID:      f.uncategorized.ID,
+		Amount:      mustMoney(t, "-200.00", money.CurrencyUSD),
+		Description: "Transfer to savings",
+	})
+	require.NoError(t, err)
+	assert.True(t, tx.IsTransfer())
+	assert.True(t, tx.AccountID.Equals(f.checking.ID))
+	assert.Contains(t, f.transfers.stored, tx.TransferID.Unwrap().String())
+	assert.Empty(t, f.transactions.stored, "recorded through the transfer")
+	assert.Equal(t, "800.00 USD", f.accounts.stored[f.checking.ID.String()].Balance.String())
+	assert.Equal(t, "200.00 USD", f.accounts.stored[f.savings.ID.String()].Balance.String())
+	assert.Len(t, f.journal.stored, 2, "each side of the transfer has its entry")
+	assert.Equal(t, "200.00 USD", f.journal.balance(t, f.savings.ID, money.CurrencyUSD))
+}
+
+func TestTransactionUseCase_Holds(t *testing.T) {
+	f := newRuleFixture(t)
+	ctx := context.Background()
+	holdDate := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	newHold := func(t *testing.T, amount string) *entity.Transaction {
+		tx, err := f.useCase.CreateTransaction(ctx, CreateTransactionInput{
+			LedgerID:        f.ledgerID,
+			AccountID:       f.checking.ID,
+			ItemID:          f.food.ID,
+			Amount:          mustMoney(t, amount, money.CurrencyUSD),
+			Description:     "Restaurant",
+			TransactionDate: holdDate,
+			Pending:         true,
+		})
+		require.NoError(t, err)
+		return tx
+	}
+	balance := func() string { return f.accounts.stored[f.checking.ID.String()].Balance.String() }
+	actual := func() string { return f.items[f.food.ID.String()].GetMonthlyBudget(2024, 3).ActualAmount.String() }
+
+	dinner := newHold(t, "-50.00")
+	assert.True(t, dinner.PostingStatus.IsPending())
+	assert.Equal(t, "1000.00 USD", balance(), "holds do not change the current balance")
+	assert.Equal(t, "50.00 USD", actual(), "holds count against the budget")
+
+	posted, err := f.useCase.PostTransaction(ctx, PostTransactionInput{
+		LedgerID:      f.ledgerID,
+		TransactionID: dinner.ID,
+		Amount:        optional.Some(mustMoney(t, "-58.00", money.CurrencyUSD)),
+		PostedDate:    holdDate.AddDate(0, 0, 2),
+	})
+	require.NoError(t, err)
+	assert.True(t, posted.PostingStatus.IsPosted())
+	assert.Equal(t, "-50.00 USD", posted.HoldAmount.Unwrap().String())
+	assert.Equal(t, "942.00 USD", balance())
+	assert.Equal(t, "58.00 USD", actual())
+
+	_, err = f.useCase.VoidTransaction(ctx, f.ledgerID, dinner.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only pending transactions can be voided")
+
+	_, err = f.useCase.PostTransaction(ctx, PostTransactionInput{LedgerID: f.ledgerID, TransactionID: dinner.ID})
+	require.Error(t, err, "a hold is posted once")
+	assert.Equal(t, "942.00 USD", balance())
+	assert.Equal(t, "58.00 USD", actual())
+
+	fuel := newHold(t, "-100.00")
+	voided, err := f.useCase.VoidTransaction(ctx, f.ledgerID, fuel.ID)
+	require.NoError(t, err)
+	assert.True(t, voided.PostingStatus.IsVoided())
+	assert.Equal(t, "942.00 USD", balance())
+	assert.Equal(t, "58.00 USD", actual(), "voided holds leave the budget")
+
+	_, err = f.useCase.VoidTransaction(ctx, f.ledgerID, fuel.ID)
+	require.Error(t, err, "a hold is voided once")
+	assert.Equal(t, "58.00 USD", actual())
+
+	stale := newHold(t, "-20.00")
+	_, err = f.useCase.ExpireHolds(ctx, f.ledgerID, holdDate, -time.Hour)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hold expiry cannot be negative")
+
+	expired, err := f.useCase.ExpireHolds(ctx, f.ledgerID, holdDate.Add(entity.DefaultHoldExpiry), 0)
+	require.NoError(t, err)
+	assert.Empty(t, expired, "holds are kept until they are older than the expiry")
+
+	expired, err = f.useCase.ExpireHolds(ctx, f.ledgerID, holdDate.Add(entity.DefaultHoldExpiry+time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.True(t, expired[0].ID.Equals(stale.ID))
+	assert.True(t, f.transactions.stored[stale.ID.String()].PostingStatus.IsVoided())
+	assert.Equal(t, "58.00 USD", actual())
+}
+
+func TestTransactionUseCase_SearchTransactions(t *testing.T) {
+	f := newRuleFixture(t)
+	ctx := context.Background()
+
+	march := func(day int) time.Time { return time.Date(2024, 3, day, 0, 0, 0, 0, time.UTC) }
+	coffee := f.storeTransaction(t, f.checking, "Blue Bottle Coffee", "-4.50", march(2))
+	f.storeTransaction(t, f.checking, "Starbucks coffee", "-5.25", march(3))
+	lunch := f.storeTransaction(t, f.savings, "Lunch", "-18.00", march(4))
+	lunch.Notes = "coffee after"
+	require.NoError(t, lunch.AddTag("work"))
+	require.NoError(t, lunch.Split([]entity.SplitLine{
+		{ItemID: f.food.ID, Amount: mustMoney(t, "-12.00", money.CurrencyUSD)},
+		{ItemID: f.uncategorized.ID, Amount: mustMoney(t, "-6.00", money.CurrencyUSD)},
+	}))
+	f.storeTransaction(t, f.checking, "Salary", "2500.00", march(5))
+
+	tests := []struct {
+		name     string
+		input    SearchTransactionsInput
+		expected []*entity.Transaction
+		total    int
+		totals   string
+	}{
+		{
+			name:     "text over description and notes",
+			input:    SearchTransactionsInput{Filter: entity.TransactionFilter{Text: "coffee -starbucks"}},
+			expected: []*entity.Transaction{lunch, coffee},
+			total:    2,
+			totals:   "-22.50 USD",
+		},
+		{
+			name: "amount range and account",
+			input: SearchTransactionsInput{Filter: entity.TransactionFilter{
+				MinAmount: optional.Some(mustMoney(t, "-10.00", money.CurrencyUSD)),
+				MaxAmount: optional.Some(mustMoney(t, "0", money.CurrencyUSD)),
+				AccountID: optional.Some(f.checking.ID),
+			}},
+			total:  2,
+			totals: "-9.75 USD",
+		},
+		{
+			name:     "tag ignoring case",
+			input:    SearchTransactionsInput{Filter: entity.TransactionFilter{Tag: "WORK"}},
+			expected: []*entity.Transaction{lunch},
+			total:    1,
+			totals:   "-18.00 USD",
+		},
+		{
+			name:     "item sums only its split lines",
+			input:    SearchTransactionsInput{Filter: entity.TransactionFilter{ItemID: optional.Some(f.food.ID)}},
+			expected: []*entity.Transaction{lunch},
+			total:    1,
+			totals:   "-12.00 USD",
+		},
+		{
+			name: "second page",
+			input: SearchTransactionsInput{
+				Filter: entity.TransactionFilter{From: optional.Some(march(3)), To: optional.Some(march(6))},
+				Limit:  2,
+				Offset: 2,
+			},
+			total:  3,
+			totals: "2476.75 USD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.LedgerID = f.ledgerID
+			page, err := f.useCase.SearchTransactions(ctx, tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.total, page.Total)
+			assert.Equal(t, tt.totals, page.Totals.String())
+			if tt.expected != nil {
+				require.Len(t, page.Transactions, len(tt.expected))
+				for i, tx := range tt.expected {
+					assert.True(t, page.Transactions[i].ID.Equals(tx.ID))
+				}
+			}
+		})
+	}
+
+	page, err := f.useCase.SearchTransactions(ctx, SearchTransactionsInput{LedgerID: f.ledgerID, Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.Len(t, page.Transactions, 2)
+
+	_, err = f.useCase.SearchTransactions(ctx, SearchTransactionsInput{LedgerID: f.ledgerID, Limit: 1000})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "page size must be between 1 and 500")
+
+	_, err = f.useCase.SearchTransactions(ctx, SearchTransactionsInput{
+		LedgerID: f.ledgerID,
+		Filter:   entity.TransactionFilter{From: optional.Some(march(5)), To: optional.Some(march(1))},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid date range")
+}
+
+func TestTransactionUseCase_ForeignCurrency(t *testing.T) {
+	f := newRuleFixture(t)
+	ctx := context.Background()
+
+	create := func(t *testing.T, amount string, original, fee optional.Option[money.Money], tags ...string) {
+		t.Helper()
+
+		_, err := f.useCase.CreateTransaction(ctx, CreateTransactionInput{
+			LedgerID:       f.ledgerID,
+			AccountID:      f.checking.ID,
+			ItemID:         f.uncategorized.ID,
+			Amount:         mustMoney(t, amount, money.CurrencyUSD),
+			OriginalAmount: original,
+			FXFee:          fee,
+			Description:    "Holiday",
+			Tags:           tags,
+		})
+		require.NoError(t, err)
+	}
+
+	none := optional.None[money.Money]()
+	create(t, "-110.00", optional.Some(mustMoney(t, "-100.00", money.CurrencyEUR)),
+		optional.Some(mustMoney(t, "-2.20", money.CurrencyUSD)), "paris-2024")
+	create(t, "-55.00", optional.Some(mustMoney(t, "-50.00", money.CurrencyEUR)), none, "paris-2024")
+	create(t, "-20.00", none, none, "paris-2024")
+	create(t, "-12.00", none, none)
+	assert.Equal(t, "803.00 USD", f.accounts.stored[f.checking.ID.String()].Balance.String())
+
+	spend, err := f.useCase.SpendByOriginalCurrency(ctx, f.ledgerID, entity.TransactionFilter{Tag: " Paris-2024 "})
+	require.NoError(t, err)
+	require.Len(t, spend, 2)
+
+	assert.Equal(t, money.CurrencyEUR, spend[0].Currency)
+	assert.Equal(t, "-150.00 EUR", spend[0].Original.String())
+	assert.Equal(t, "-165.00 USD", spend[0].Settled.Get(money.CurrencyUSD).String())
+	assert.Equal(t, "-2.20 USD", spend[0].Fees.Get(money.CurrencyUSD).String())
+	assert.Equal(t, 2, spend[0].Count)
+
+	assert.Equal(t, money.CurrencyUSD, spend[1].Currency)
+	assert.Equal(t, "-20.00 USD", spend[1].Original.String())
+	assert.True(t, spend[1].Fees.IsZero())
+	assert.Equal(t, 1, spend[1].Count)
+
+	_, err = f.useCase.CreateTransaction(ctx, CreateTransactionInput{
+		LedgerID:    f.ledgerID,
+		AccountID:   f.checking.ID,
+		ItemID:      f.uncategorized.ID,
+		Amount:      mustMoney(t, "-10.00", money.CurrencyUSD),
+		FXFee:       optional.Some(mustMoney(t, "-0.20", money.CurrencyUSD)),
+		Description: "Holiday",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "an FX fee needs the original amount")
+}
+
+func TestTransactionUseCase_UpdateTags(t *testing.T) {
+	f := newRuleFixture(t)
+	ctx := context.Background()
+
+	tx := f.storeTransaction(t, f.checking, "Hotel", "-120.00", time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, tx.AddTag("travel"))
+
+	updated, err := f.useCase.UpdateTags(ctx, UpdateTagsInput{
+		LedgerID:      f.ledgerID,
+		TransactionID: tx.ID,
+		Add:           []string{"Reimbursable", "work"},
+		Remove:        []string{"TRAVEL"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reimbursable", "work"}, updated.Tags)
+	assert.Equal(t, []string{"reimbursable", "work"}, f.transactions.stored[tx.ID.String()].Tags)
+
+	_, err = f.useCase.UpdateTags(ctx, UpdateTagsInput{LedgerID: f.ledgerID, TransactionID: tx.ID, Add: []string{" "}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tag cannot be empty")
+}
+
+func TestTransactionUseCase_SplitTransaction(t *testing.T) {
+	f := newSplitFixture(t)
+	ctx := context.Background()
+
+	tx, err := f.useCase.SplitTransaction(ctx, SplitTransactionInput{
+		LedgerID:      f.ledgerID,
+		TransactionID: f.tx.ID,
+		Lines: []entity.SplitLine{
+			{ItemID: f.food.ID, Amount: mustMoney(t, "-70.00", money.CurrencyUSD), Note: "Groceries"},
+			{ItemID: f.household.ID, Amount: mustMoney(t, "-30.00", money.CurrencyUSD)},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, tx.IsSplit())
+	assert.True(t, f.transactions.stored[tx.ID.String()].IsSplit())
+	assert.Equal(t, "70.00 USD", f.actual(t, f.food))
+	assert.Equal(t, "30.00 USD", f.actual(t, f.household))
+
+	// Re-splitting moves actuals between the items
+	_, err = f.useCase.SplitTransaction(ctx, SplitTransactionInput{
+		LedgerID:      f.ledgerID,
+		TransactionID: f.tx.ID,
+		Lines: []entity.SplitLine{
+			{ItemID: f.food.ID, Amount: mustMoney(t, "-55.00", money.CurrencyUSD)},
+			{ItemID: f.household.ID, Amount: mustMoney(t, "-45.00", money.CurrencyUSD)},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "55.00 USD", f.actual(t, f.food))
+	assert.Equal(t, "45.00 USD", f.actual(t, f.household))
+
+	_, err = f.useCase.SplitTransaction(ctx, SplitTransactionInput{
+		LedgerID:      f.ledgerID,
+		TransactionID: f.tx.ID,
+		Lines: []entity.SplitLine{
+			{ItemID: f.food.ID, Amount: mustMoney(t, "-55.00", money.CurrencyUSD)},
+			{ItemID: f.household.ID, Amount: mustMoney(t, "-40.00", money.CurrencyUSD)},
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "split lines sum to")
+	assert.Equal(t, "55.00 USD", f.actual(t, f.food))
+
+	tx, err = f.useCase.UnsplitTransaction(ctx, f.ledgerID, f.tx.ID, f.household.ID)
+	require.NoError(t, err)
+	assert.False(t, tx.IsSplit())
+	assert.Equal(t, "0.00 USD", f.actual(t, f.food))
+	assert.Equal(t, "100.00 USD", f.actual(t, f.household))
+}
+
+func TestTransactionUseCase_SplitTransaction_TransferItem(t *testing.T) {
+	f := newSplitFixture(t)
+
+	transferItem, err := budgetEntity.NewItem(f.ledgerID, "Savings", "", budgetEntity.ItemTypeTransfer, money.CurrencyUSD)
+	require.NoError(t, err)
+	f.items[transferItem.ID.String()] = transferItem
+
+	_, err = f.useCase.SplitTransaction(context.Background(), SplitTransactionInput{
+		LedgerID:      f.ledgerID,
+		TransactionID: f.tx.ID,
+		Lines: []entity.SplitLine{
+			{ItemID: f.food.ID, Amount: mustMoney(t, "-70.00", money.CurrencyUSD)},
+			{ItemID: transferItem.ID, Amount: mustMoney(t, "-30.00", money.CurrencyUSD)},
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is a transfer item")
+}
+
+// Helper functions
+
+type splitFixture struct {
+	ledgerID     ledgerEntity.LedgerID
+	food         *budgetEntity.Item
+	household    *budgetEntity.Item
+	tx           *entity.Transaction
+	items        fakeItemRepository
+	transactions *fakeTransactionRepository
+	useCase      *TransactionUseCase
+}
+
+func newSplitFixture(t *testing.T) *splitFixture {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	accountID, err := entity.NewAccountID()
+	require.NoError(t, err)
+
+	food, err := budgetEntity.NewItem(ledgerID, "Food", "", budgetEntity.ItemTypeExpense, money.CurrencyUSD)
+	require.NoError(t, err)
+	require.NoError(t, food.AddActualAmount(2024, 3, mustMoney(t, "100.00", money.CurrencyUSD)))
+
+	household, err := budgetEntity.NewItem(ledgerID, "Household", "", budgetEntity.ItemTypeExpense, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	tx, err := entity.NewTransaction(ledgerID, accountID, food.ID, mustMoney(t, "-100.00", money.CurrencyUSD),
+		"Supermarket", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	f := &splitFixture{
+		ledgerID:     ledgerID,
+		food:         food,
+		household:    household,
+		tx:           tx,
+		items:        fakeItemRepository{food.ID.String(): food, household.ID.String(): household},
+		transactions: &fakeTransactionRepository{stored: map[string]*entity.Transaction{tx.ID.String(): tx}},
+	}
+	f.useCase = NewTransactionUseCase(&fakeTransactor{}, &fakeAccountRepository{}, f.transactions, newFakeJournalEntryRepository(),
+		&fakeTransferRepository{}, f.items, fakeCounterpartyRepository{}, fakeRuleRepository{})
+	return f
+}
+
+func (f *splitFixture) actual(t *testing.T, item *budgetEntity.Item) string {
+	t.Helper()
+
+	tracking := f.items[item.ID.String()].GetMonthlyBudget(2024, 3)
+	require.NotNil(t, tracking)
+	return tracking.ActualAmount.String()
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	budgetRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// CreateTransferInput describes a new transfer between two accounts of a ledger
+type CreateTransferInput struct {
+	LedgerID             ledgerEntity.LedgerID
+	SourceAccountID      entity.AccountID
+	DestinationAccountID entity.AccountID
+	ItemID               budgetEntity.ItemID              // Must be a transfer item
+	Amount               money.Money                      // In the source account's currency
+	FXRate               optional.Option[decimal.Decimal] // Destination units per source unit; required when currencies differ
+	Description          string
+	Notes                string
+	TransferDate         time.Time
+}
+
+// UpdateTransferInput describes the new state of an existing transfer
+type UpdateTransferInput struct {
+	LedgerID     ledgerEntity.LedgerID
+	TransferID   entity.TransferID
+	ItemID       budgetEntity.ItemID
+	Amount       money.Money
+	FXRate       optional.Option[decimal.Decimal]
+	Description  string
+	Notes        string
+	TransferDate time.Time
+}
+
+// TransferUseCase creates, edits and deletes transfers. Each operation writes the linked
+// transactions, their journal entries and both account balances in a single database transaction.
+type TransferUseCase struct {
+	transactor repository.Transactor
+	accounts   repository.AccountRepository
+	transfers  repository.TransferRepository
+	journal    repository.JournalEntryRepository
+	items      budgetRepository.ItemRepository
+}
+
+// NewTransferUseCase creates a new TransferUseCase
+func NewTransferUseCase(
+	transactor repository.Transactor,
+	accounts repository.AccountRepository,
+	transfers repository.TransferRepository,
+	journal repository.JournalEntryRepository,
+	items budgetRepository.ItemRepository,
+) *TransferUseCase {
+	return &TransferUseCase{
+		transactor: transactor,
+		accounts:   accounts,
+		transfers:  transfers,
+		journal:    journal,
+		items:      items,
+	}
+}
+
+// CreateTransfer moves money from the source to the destination account
+func (u *TransferUseCase) CreateTransfer(ctx context.Context, in CreateTransferInput) (*entity.Transfer, error) {
+	var transfer *entity.Transfer
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := checkTransferItem(ctx, u.items, in.LedgerID, in.ItemID); err != nil {
+			return err
+		}
+
+		source, destination, err := u.lockAccounts(ctx, in.LedgerID, in.SourceAccountID, in.DestinationAccountID)
+		if err != nil {
+			return err
+		}
+
+		transfer, err = entity.NewTransfer(source, destination, in.ItemID, in.Amount, in.FXRate, in.Description, in.TransferDate)
+		if err != nil {
+			return err
+		}
+
+		if in.Notes != "" {
+			if err := transfer.UpdateInfo(in.Description, in.Notes); err != nil {
+				return err
+			}
+		}
+
+		if err := u.transfers.Create(ctx, transfer); err != nil {
+			return fmt.Errorf("failed to create transfer: %w", err)
+		}
+
+		if err := postJournalEntries(ctx, u.journal, transfer.Outgoing, transfer.Incoming); err != nil {
+			return err
+		}
+
+		return u.saveBalances(ctx, source, destination)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// UpdateTransfer edits both transactions of a transfer together, moving any change in amount between the accounts
+func (u *TransferUseCase) UpdateTransfer(ctx context.Context, in UpdateTransferInput) (*entity.Transfer, error) {
+	var transfer *entity.Transfer
+
+	err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// Locked so a concurrent edit or delete cannot reverse an amount this one replaces
+		var err error
+		transfer, err = u.transfers.GetForUpdate(ctx, in.LedgerID, in.TransferID)
+		if err != nil {
+			return fmt.Errorf("failed to lock transfer: %w", err)
+		}
+
+		if !in.ItemID.Equals(transfer.Outgoing.ItemID) {
+			if err := checkTransferItem(ctx, u.items, in.LedgerID, in.ItemID); err != nil {
+				return err
+			}
+			transfer.UpdateItem(in.ItemID)
+		}
+
+		if err := transfer.UpdateInfo(in.Description, in.Notes); err != nil {
+			return err
+		}
+
+		if !in.TransferDate.IsZero() && !in.TransferDate.Equal(transfer.Outgoing.TransactionDate) {
+			if err := transfer.UpdateTransferDate(in.TransferDate); err != nil {
+				return err
+			}
+		}
+
+		if !transferAmountChanged(transfer, in.Amount, in.FXRate) {
+			if err := u.transfers.Update(ctx, transfer); err != nil {
+				return fmt.Errorf("failed to update transfer: %w", err)
+			}
+			return syncJournalEntries(ctx, u.journal, transfer.Outgoing, transfer.Incoming)
+		}
+
+		source, destination, err := u.lockAccounts(ctx, in.LedgerID, transfer.SourceAccountID(), transfer.DestinationAccountID())
+		if err != nil {
+			return err
+		}
+
+		if err := transfer.UpdateAmount(source, destination, in.Amount, in.FXRate); err != nil {
+			return err
+		}
+
+		if err := u.transfers.Update(ctx, transfer); err != nil {
+			return fmt.Errorf("failed to update transfer: %w", err)
+		}
+
+		if err := syncJournalEntries(ctx, u.journal, transfer.Outgoing, transfer.Incoming); err != nil {
+			return err
+		}
+
+		return u.saveBalances(ctx, source, destination)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// DeleteTransfer removes both transactions of a transfer and restores the account balances
+func (u *TransferUseCase) DeleteTransfer(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.TransferID) error {
+	return u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		transfer, err := u.transfers.GetForUpdate(ctx, ledgerID, id)
+		if err != nil {
+			return fmt.Errorf("failed to lock transfer: %w", err)
+		}
+
+		if transfer.IsLocked() {
+			return fmt.Errorf("cannot delete transfer %s: it has a reconciled and locked transaction", id)
+		}
+
+		source, destination, err := u.lockAccounts(ctx, ledgerID, transfer.SourceAccountID(), transfer.DestinationAccountID())
+		if err != nil {
+			return err
+		}
+
+		if err := transfer.Reverse(source, destination); err != nil {
+			return err
+		}
+
+		if err := removeJournalEntries(ctx, u.journal, transfer.Outgoing, transfer.Incoming); err != nil {
+			return err
+		}
+
+		if err := u.transfers.Delete(ctx, ledgerID, id); err != nil {
+			return fmt.Errorf("failed to delete transfer: %w", err)
+		}
+
+		return u.saveBalances(ctx, source, destination)
+	})
+}
+
+// transferAmountChanged checks if the amount or exchange rate differ from the transfer's current ones
+func transferAmountChanged(t *entity.Transfer, amount money.Money, fxRate optional.Option[decimal.Decimal]) bool {
+	if !amount.Equals(t.Amount()) || fxRate.IsSome() != t.Rate.IsSome() {
+		return true
+	}
+	return fxRate.IsSome() && !fxRate.Unwrap().Equal(t.Rate.Unwrap().Value)
+}
+
+func (u *TransferUseCase) lockAccounts(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	sourceID, destinationID entity.AccountID,
+) (*entity.Account, *entity.Account, error) {
+	if sourceID.Equals(destinationID) {
+		return nil, nil, fmt.Errorf("cannot transfer to the same account")
+	}
+
+	accounts, err := u.accounts.GetForUpdate(ctx, ledgerID, sourceID, destinationID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lock accounts: %w", err)
+	}
+
+	return accounts[0], accounts[1], nil
+}
+
+func (u *TransferUseCase) saveBalances(ctx context.Context, accounts ...*entity.Account) error {
+	for _, a := range accounts {
+		if err := u.accounts.UpdateBalance(ctx, a); err != nil {
+			return fmt.Errorf("failed to update balance of account %s: %w", a.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	budgetRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+func TestTransferUseCase_CreateTransfer(t *testing.T) {
+	tests := []struct {
+		name            string
+		destCurrency    money.Currency
+		itemType        budgetEntity.ItemType
+		amount          string
+		fxRate          optional.Option[decimal.Decimal]
+		wantErr         bool
+		errContains     string
+		wantSource      string
+		wantDestination string
+	}{
+		{
+			name:            "same currency",
+			destCurrency:    money.CurrencyUSD,
+			itemType:        budgetEntity.ItemTypeTransfer,
+			amount:          "150.00",
+			fxRate:          optional.None[decimal.Decimal](),
+			wantSource:      "850.00 USD",
+			wantDestination: "150.00 USD",
+		},
+		{
+			name:            "cross currency",
+			destCurrency:    money.CurrencyEUR,
+			itemType:        budgetEntity.ItemTypeTransfer,
+			amount:          "100.00",
+			fxRate:          optional.Some(decimal.RequireFromString("0.9234")),
+			wantSource:      "900.00 USD",
+			wantDestination: "92.34 EUR",
+		},
+		{
+			name:         "not a transfer item",
+			destCurrency: money.CurrencyUSD,
+			itemType:     budgetEntity.ItemTypeExpense,
+			amount:       "10.00",
+			fxRate:       optional.None[decimal.Decimal](),
+			wantErr:      true,
+			errContains:  "is not a transfer item",
+		},
+		{
+			name:         "insufficient balance",
+			destCurrency: money.CurrencyUSD,
+			itemType:     budgetEntity.ItemTypeTransfer,
+			amount:       "1000.01",
+			fxRate:       optional.None[decimal.Decimal](),
+			wantErr:      true,
+			errContains:  "insufficient balance",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTransferFixture(t, tt.destCurrency, tt.itemType)
+
+			transfer, err := f.useCase.CreateTransfer(context.Background(), CreateTransferInput{
+				LedgerID:             f.ledgerID,
+				SourceAccountID:      f.source.ID,
+				DestinationAccountID: f.destination.ID,
+				ItemID:               f.item.ID,
+				Amount:               mustMoney(t, tt.amount, money.CurrencyUSD),
+				FXRate:               tt.fxRate,
+				Description:          "Monthly savings",
+				Notes:                "Auto",
+				TransferDate:         time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			})
+
+			assert.Equal(t, 1, f.transactor.calls)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Empty(t, f.transfers.stored)
+				assert.Equal(t, "1000.00 USD", f.accounts.stored[f.source.ID.String()].Balance.String())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, f.transfers.stored, transfer.ID.String())
+			assert.Equal(t, "Auto", transfer.Incoming.Notes)
+			assert.Equal(t, tt.wantSource, f.accounts.stored[f.source.ID.String()].Balance.String())
+			assert.Equal(t, tt.wantDestination, f.accounts.stored[f.destination.ID.String()].Balance.String())
+			assert.Equal(t, tt.wantDestination, f.journal.balance(t, f.destination.ID, tt.destCurrency),
+				"the journal derives the same balance")
+		})
+	}
+}
+
+func TestTransferUseCase_UpdateTransfer(t *testing.T) {
+	f := newTransferFixture(t, money.CurrencyUSD, budgetEntity.ItemTypeTransfer)
+	transfer := f.createTransfer(t, "200.00")
+
+	date := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	updated, err := f.useCase.UpdateTransfer(context.Background(), UpdateTransferInput{
+		LedgerID:     f.ledgerID,
+		TransferID:   transfer.ID,
+		ItemID:       f.item.ID,
+		Amount:       mustMoney(t, "250.00", money.CurrencyUSD),
+		FXRate:       optional.None[decimal.Decimal](),
+		Description:  "Bigger savings",
+		TransferDate: date,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, f.transactor.calls)
+	assert.Equal(t, "Bigger savings", updated.Outgoing.Description)
+	assert.Equal(t, "Bigger savings", updated.Incoming.Description)
+	assert.Equal(t, date, updated.Outgoing.TransactionDate)
+	assert.Equal(t, date, updated.Incoming.TransactionDate)
+	assert.Equal(t, "-250.00 USD", f.transfers.stored[transfer.ID.String()].Outgoing.Amount.String())
+	assert.Equal(t, "750.00 USD", f.accounts.stored[f.source.ID.String()].Balance.String())
+	assert.Equal(t, "250.00 USD", f.accounts.stored[f.destination.ID.String()].Balance.String())
+	assert.Len(t, f.journal.stored, 2)
+	assert.Equal(t, "250.00 USD", f.journal.balance(t, f.destination.ID, money.CurrencyUSD))
+	assert.Equal(t, "-250.00 USD", f.journal.balance(t, f.source.ID, money.CurrencyUSD))
+
+	_, err = f.useCase.UpdateTransfer(context.Background(), UpdateTransferInput{
+		LedgerID:    f.ledgerID,
+		TransferID:  transfer.ID,
+		ItemID:      f.item.ID,
+		Amount:      mustMoney(t, "1000.01", money.CurrencyUSD),
+		FXRate:      optional.None[decimal.Decimal](),
+		Description: "Too much",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient balance")
+	assert.Equal(t, "750.00 USD", f.accounts.stored[f.source.ID.String()].Balance.String())
+}
+
+func TestTransferUseCase_DeleteTransfer(t *testing.T) {
+	f := newTransferFixture(t, money.CurrencyUSD, budgetEntity.ItemTypeTransfer)
+	transfer := f.createTransfer(t, "200.00")
+
+	err := f.useCase.DeleteTransfer(context.Background(), f.ledgerID, transfer.ID)
+	require.NoError(t, err)
+
+	assert.Empty(t, f.transfers.stored)
+	assert.Empty(t, f.journal.stored)
+	assert.Equal(t, "1000.00 USD", f.accounts.stored[f.source.ID.String()].Balance.String())
+	assert.Equal(t, "0.00 USD", f.accounts.stored[f.destination.ID.String()].Balance.String())
+
+	err = f.useCase.DeleteTransfer(context.Background(), f.ledgerID, transfer.ID)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+// Helper functions
+
+type transferFixture struct {
+	ledgerID    ledgerEntity.LedgerID
+	source      *entity.Account
+	destination *entity.Account
+	item        *budgetEntity.Item
+	transactor  *fakeTransactor
+	accounts    *fakeAccountRepository
+	transfers   *fakeTransferRepository
+	journal     *fakeJournalEntryRepository
+	useCase     *TransferUseCase
+}
+
+func newTransferFixture(t *testing.T, destCurrency money.Currency, itemType budgetEntity.ItemType) *transferFixture {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	source, err := entity.NewAccount(ledgerID, "Checking", "", entity.AccountTypeChecking, money.CurrencyUSD)
+	require.NoError(t, err)
+	source.Balance = mustMoney(t, "1000.00", money.CurrencyUSD)
+
+	destination, err := entity.NewAccount(ledgerID, "Savings", "", entity.AccountTypeSavings, destCurrency)
+	require.NoError(t, err)
+
+	item, err := budgetEntity.NewItem(ledgerID, "Savings", "", itemType, money.CurrencyUSD)
+	require.NoError(t, err)
+
+	f := &transferFixture{
+		ledgerID:    ledgerID,
+		source:      source,
+		destination: destination,
+		item:        item,
+		transactor:  &fakeTransactor{},
+		accounts:    &fakeAccountRepository{stored: map[string]entity.Account{}},
+		transfers:   &fakeTransferRepository{stored: map[string]*entity.Transfer{}},
+		journal:     newFakeJournalEntryRepository(),
+	}
+	f.accounts.stored[source.ID.String()] = *source
+	f.accounts.stored[destination.ID.String()] = *destination
+
+	items := fakeItemRepository{item.ID.String(): item}
+	f.useCase = NewTransferUseCase(f.transactor, f.accounts, f.transfers, f.journal, items)
+	return f
+}
+
+func (f *transferFixture) createTransfer(t *testing.T, amount string) *entity.Transfer {
+	t.Helper()
+
+	transfer, err := f.useCase.CreateTransfer(context.Background(), CreateTransferInput{
+		LedgerID:             f.ledgerID,
+		SourceAccountID:      f.source.ID,
+		DestinationAccountID: f.destination.ID,
+		ItemID:               f.item.ID,
+		Amount:               mustMoney(t, amount, money.CurrencyUSD),
+		FXRate:               optional.None[decimal.Decimal](),
+		Description:          "Savings",
+		TransferDate:         time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	return transfer
+}
+
+func mustMoney(t *testing.T, amount string, currency money.Currency) money.Money {
+	t.Helper()
+
+	m, err := money.NewMoney(amount, currency)
+	require.NoError(t, err)
+	return m
+}
+
+// fakeTransactor runs fn inline and counts the units of work
+type fakeTransactor struct {
+	calls int
+}
+
+func (f *fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.calls++
+	return fn(ctx)
+}
+
+type fakeAccountRepository struct {
+	stored map[string]entity.Account
+}
+
+func (f *fakeAccountRepository) GetByID(_ context.Context, ledgerID ledgerEntity.LedgerID, id entity.AccountID) (*entity.Account, error) {
+	a, ok := f.stored[id.String()]
+	if !ok || !a.LedgerID.Equals(ledgerID) {
+		return nil, fmt.Errorf("account %s: %w", id, repository.ErrNotFound)
+	}
+	return &a, nil
+}
+
+func (f *fakeAccountRepository) ListByLedger(_ context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Account, error) {
+	var accounts []*entity.Account
+	for _, a := range f.stored {
+		if a.LedgerID.Equals(ledgerID) {
+			accounts = append(accounts, &a)
+		}
+	}
+	return accounts, nil
+}
+
+func (f *fakeAccountRepository) GetForUpdate(ctx context.Context, ledgerID ledgerEntity.LedgerID, ids ...entity.AccountID) ([]*entity.Account, error) {
+	accounts := make([]*entity.Account, 0, len(ids))
+	for _, id := range ids {
+		a, err := f.GetByID(ctx, ledgerID, id)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, nil
+}
+
+func (f *fakeAccountRepository) UpdateBalance(_ context.Context, account *entity.Account) error {
+	f.stored[account.ID.String()] = *account
+	return nil
+}
+
+type fakeTransferRepository struct {
+	stored map[string]*entity.Transfer
+}
+
+func (f *fakeTransferRepository) Create(_ context.Context, transfer *entity.Transfer) error {
+	f.stored[transfer.ID.String()] = copyTransfer(transfer)
+	return nil
+}
+
+func (f *fakeTransferRepository) Update(_ context.Context, transfer *entity.Transfer) error {
+	f.stored[transfer.ID.String()] = copyTransfer(transfer)
+	return nil
+}
+
+func (f *fakeTransferRepository) Delete(_ context.Context, _ ledgerEntity.LedgerID, id entity.TransferID) error {
+	delete(f.stored, id.String())
+	return nil
+}
+
+func (f *fakeTransferRepository) GetByID(_ context.Context, ledgerID ledgerEntity.LedgerID, id entity.TransferID) (*entity.Transfer, error) {
+	transfer, ok := f.stored[id.String()]
+	if !ok || !transfer.LedgerID.Equals(ledgerID) {
+		return nil, fmt.Errorf("transfer %s: %w", id, repository.ErrNotFound)
+	}
+	return copyTransfer(transfer), nil
+}
+
+func (f *fakeTransferRepository) GetForUpdate(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransferID,
+) (*entity.Transfer, error) {
+	return f.GetByID(ctx, ledgerID, id)
+}
+
+func copyTransfer(t *entity.Transfer) *entity.Transfer {
+	outgoing := *t.Outgoing
+	incoming := *t.Incoming
+	c := *t
+	c.Outgoing = &outgoing
+	c.Incoming = &incoming
+	return &c
+}
+
+type fakeItemRepository map[string]*budgetEntity.Item
+
+func (f fakeItemRepository) GetByID(_ context.Context, _ ledgerEntity.LedgerID, id budgetEntity.ItemID) (*budgetEntity.Item, error) {
+	item, ok := f[id.String()]
+	if !ok {
+		return nil, fmt.Errorf("item %s: %w", id, budgetRepository.ErrNotFound)
+	}
+	return item, nil
+}
+
+func (f fakeItemRepository) AddActualAmount(
+	_ context.Context,
+	_ ledgerEntity.LedgerID,
+	id budgetEntity.ItemID,
+	year, month int,
+	amount money.Money,
+) error {
+	item, ok := f[id.String()]
+	if !ok {
+		return fmt.Errorf("item %s: %w", id, budgetRepository.ErrNotFound)
+	}
+	return item.AddActualAmount(year, month, amount)
+}
@@ -0,0 +1,210 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+const (
+	// MaxContentSize is the largest file that can be attached
+	MaxContentSize = 20 << 20
+
+	// RetentionPeriod is how long the attachments of a deleted transaction, counterparty or ledger are kept
+	// before they are purged, so a deletion can still be investigated
+	RetentionPeriod = 30 * 24 * time.Hour
+
+	maxFileNameLength = 255
+)
+
+// allowedContentTypes are the sniffed types that can be attached: scans, photos and documents of receipts,
+// invoices and warranties
+var allowedContentTypes = []string{
+	"application/pdf",
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"text/plain",
+}
+
+// Content is a file stored once per ledger however many attachments share it, addressed by its SHA-256 digest
+type Content struct {
+	LedgerID    ledgerEntity.LedgerID
+	SHA256      string // Hex digest of the file
+	Size        int64  // Bytes
+	ContentType string // Sniffed from the file, not taken from the uploader
+	CreatedAt   time.Time
+}
+
+// Attachment links a stored file to a transaction or counterparty under the name it was uploaded with
+type Attachment struct {
+	ID        AttachmentID
+	LedgerID  ledgerEntity.LedgerID
+	Owner     Owner
+	FileName  string
+	Content   Content
+	CreatedAt time.Time
+	// OwnerDeletedAt is set when the owner was deleted; the attachment is purged once RetentionPeriod has passed
+	OwnerDeletedAt optional.Option[time.Time]
+}
+
+// NewContent checks the size and sniffed type of a file and computes its digest
+func NewContent(ledgerID ledgerEntity.LedgerID, data []byte) (*Content, error) {
+	if !ledgerID.IsValid() {
+		return nil, fmt.Errorf("ledger ID is invalid")
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("attachment is empty")
+	}
+
+	if len(data) > MaxContentSize {
+		return nil, fmt.Errorf("attachment is %d bytes, above the limit of %d", len(data), MaxContentSize)
+	}
+
+	contentType, err := SniffContentType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(data)
+
+	return &Content{
+		LedgerID:    ledgerID,
+		SHA256:      hex.EncodeToString(digest[:]),
+		Size:        int64(len(data)),
+		ContentType: contentType,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// ReconstructContent reconstructs a Content from stored data
+func ReconstructContent(
+	ledgerID ledgerEntity.LedgerID,
+	sha256 string,
+	size int64,
+	contentType string,
+	createdAt time.Time,
+) Content {
+	return Content{
+		LedgerID:    ledgerID,
+		SHA256:      sha256,
+		Size:        size,
+		ContentType: contentType,
+		CreatedAt:   createdAt,
+	}
+}
+
+// BlobKey returns the key the file is stored under in blob storage
+func (c Content) BlobKey() string {
+	return path.Join("ledgers", c.LedgerID.String(), c.SHA256)
+}
+
+// SniffContentType detects the media type of a file from its first bytes, rejecting types that cannot be attached
+func SniffContentType(data []byte) (string, error) {
+	sniffed := http.DetectContentType(data)
+
+	mediaType, _, err := mime.ParseMediaType(sniffed)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse content type %q: %w", sniffed, err)
+	}
+
+	if !slices.Contains(allowedContentTypes, mediaType) {
+		return "", fmt.Errorf("unsupported attachment type: %s", mediaType)
+	}
+	return mediaType, nil
+}
+
+// NewAttachment creates a new Attachment of the content to the owner
+func NewAttachment(owner Owner, fileName string, content Content) (*Attachment, error) {
+	if !owner.ID.IsValid() {
+		return nil, fmt.Errorf("attachment owner ID is invalid")
+	}
+
+	if _, err := NewOwnerType(owner.Type.String()); err != nil {
+		return nil, err
+	}
+
+	fileName, err := cleanFileName(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentID, err := NewAttachmentID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate attachment ID: %w", err)
+	}
+
+	return &Attachment{
+		ID:             attachmentID,
+		LedgerID:       content.LedgerID,
+		Owner:          owner,
+		FileName:       fileName,
+		Content:        content,
+		CreatedAt:      time.Now(),
+		OwnerDeletedAt: optional.None[time.Time](),
+	}, nil
+}
+
+// ReconstructAttachment reconstructs an Attachment from stored data
+func ReconstructAttachment(
+	id AttachmentID,
+	owner Owner,
+	fileName string,
+	content Content,
+	createdAt time.Time,
+	ownerDeletedAt optional.Option[time.Time],
+) *Attachment {
+	return &Attachment{
+		ID:             id,
+		LedgerID:       content.LedgerID,
+		Owner:          owner,
+		FileName:       fileName,
+		Content:        content,
+		CreatedAt:      createdAt,
+		OwnerDeletedAt: ownerDeletedAt,
+	}
+}
+
+// IsOwnerDeleted checks if the attachment's owner was deleted and the attachment awaits purging
+func (a *Attachment) IsOwnerDeleted() bool {
+	return a.OwnerDeletedAt.IsSome()
+}
+
+// IsExpired checks if the attachment's owner was deleted more than RetentionPeriod before now
+func (a *Attachment) IsExpired(now time.Time) bool {
+	return a.IsOwnerDeleted() && a.OwnerDeletedAt.Unwrap().Before(now.Add(-RetentionPeriod))
+}
+
+// cleanFileName keeps the base name of an uploaded file, dropping any directories a client sent
+func cleanFileName(fileName string) (string, error) {
+	fileName = strings.TrimSpace(fileName)
+	if i := strings.LastIndexAny(fileName, `/\`); i >= 0 {
+		fileName = fileName[i+1:]
+	}
+
+	if fileName == "" || fileName == "." || fileName == ".." {
+		return "", fmt.Errorf("attachment file name cannot be empty")
+	}
+
+	if utf8.RuneCountInString(fileName) > maxFileNameLength {
+		return "", fmt.Errorf("attachment file name cannot exceed %d characters", maxFileNameLength)
+	}
+
+	if strings.ContainsFunc(fileName, func(r rune) bool { return r < ' ' || r == 0x7f }) {
+		return "", fmt.Errorf("attachment file name cannot contain control characters")
+	}
+
+	return fileName, nil
+}
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/kneadCODE/coruscant/shared/golib/id"
+	accountingEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+)
+
+// AttachmentID represents a unique identifier for an attachment using UUIDv7
+type AttachmentID struct {
+	id.EntityID
+}
+
+// NewAttachmentID creates a new AttachmentID using UUIDv7
+func NewAttachmentID() (AttachmentID, error) {
+	base, err := id.NewEntityID()
+	if err != nil {
+		return AttachmentID{}, fmt.Errorf("failed to create attachment ID: %w", err)
+	}
+	return AttachmentID{EntityID: base}, nil
+}
+
+// NewAttachmentIDFromString creates an AttachmentID from an existing string
+func NewAttachmentIDFromString(idStr string) (AttachmentID, error) {
+	base, err := id.NewEntityIDFromString(idStr)
+	if err != nil {
+		return AttachmentID{}, fmt.Errorf("failed to create attachment ID: %w", err)
+	}
+	return AttachmentID{EntityID: base}, nil
+}
+
+// Equals checks if two AttachmentIDs are equal
+func (a AttachmentID) Equals(other AttachmentID) bool {
+	return a.EntityID.Equals(other.EntityID)
+}
+
+// OwnerType is the kind of entity an attachment belongs to
+type OwnerType string
+
+// Owner type constants
+const (
+	OwnerTypeTransaction  OwnerType = "TRANSACTION"
+	OwnerTypeCounterparty OwnerType = "COUNTERPARTY"
+)
+
+// NewOwnerType creates a new OwnerType from string
+func NewOwnerType(ownerType string) (OwnerType, error) {
+	switch OwnerType(ownerType) {
+	case OwnerTypeTransaction, OwnerTypeCounterparty:
+		return OwnerType(ownerType), nil
+	default:
+		return "", fmt.Errorf("invalid attachment owner type: %s", ownerType)
+	}
+}
+
+// String returns the string representation of OwnerType
+func (o OwnerType) String() string {
+	return string(o)
+}
+
+// Owner identifies the transaction or counterparty an attachment belongs to
+type Owner struct {
+	Type OwnerType
+	ID   id.EntityID
+}
+
+// TransactionOwner returns the Owner for a transaction
+func TransactionOwner(transactionID accountingEntity.TransactionID) Owner {
+	return Owner{Type: OwnerTypeTransaction, ID: transactionID.EntityID}
+}
+
+// CounterpartyOwner returns the Owner for a counterparty
+func CounterpartyOwner(counterpartyID counterpartyEntity.CounterpartyID) Owner {
+	return Owner{Type: OwnerTypeCounterparty, ID: counterpartyID.EntityID}
+}
+
+// NewOwner creates an Owner from stored values
+func NewOwner(ownerType, ownerID string) (Owner, error) {
+	t, err := NewOwnerType(ownerType)
+	if err != nil {
+		return Owner{}, err
+	}
+
+	base, err := id.NewEntityIDFromString(ownerID)
+	if err != nil {
+		return Owner{}, fmt.Errorf("invalid attachment owner ID: %w", err)
+	}
+	return Owner{Type: t, ID: base}, nil
+}
+
+// Equals checks if two Owners are the same entity
+func (o Owner) Equals(other Owner) bool {
+	return o.Type == other.Type && o.ID.Equals(other.ID)
+}
+
+// String returns the owner as TYPE/ID
+func (o Owner) String() string {
+	return fmt.Sprintf("%s/%s", o.Type, o.ID)
+}
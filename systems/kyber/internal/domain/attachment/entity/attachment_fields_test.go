@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+)
+
+func TestAttachmentID_NewAttachmentIDFromString(t *testing.T) {
+	validID, err := NewAttachmentID()
+	require.NoError(t, err)
+
+	parsed, err := NewAttachmentIDFromString(validID.String())
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(validID))
+
+	_, err = NewAttachmentIDFromString("invalid-uuid")
+	assert.Error(t, err)
+}
+
+func TestNewOwner(t *testing.T) {
+	counterpartyID, err := counterpartyEntity.NewCounterpartyID()
+	require.NoError(t, err)
+	owner := CounterpartyOwner(counterpartyID)
+
+	parsed, err := NewOwner("COUNTERPARTY", counterpartyID.String())
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(owner))
+	assert.Equal(t, "COUNTERPARTY/"+counterpartyID.String(), parsed.String())
+
+	transactionOwner, err := NewOwner("TRANSACTION", counterpartyID.String())
+	require.NoError(t, err)
+	assert.False(t, transactionOwner.Equals(owner), "the same ID of another owner type is another owner")
+
+	_, err = NewOwner("LEDGER", counterpartyID.String())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid attachment owner type")
+
+	_, err = NewOwner("TRANSACTION", "invalid-uuid")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid attachment owner ID")
+}
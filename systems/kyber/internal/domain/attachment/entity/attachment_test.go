@@ -0,0 +1,151 @@
+package entity
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	accountingEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+var (
+	pdfData = []byte("%PDF-1.7\n1 0 obj\n<< /Type /Catalog >>\nendobj\n")
+	pngData = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+)
+
+func TestNewContent(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		data         []byte
+		expectedType string
+		errContains  string
+	}{
+		{name: "pdf", data: pdfData, expectedType: "application/pdf"},
+		{name: "png", data: pngData, expectedType: "image/png"},
+		{name: "plain text", data: []byte("Warranty: 2 years"), expectedType: "text/plain"},
+		{name: "empty", data: nil, errContains: "attachment is empty"},
+		{name: "zip", data: []byte("PK\x03\x04\x14\x00"), errContains: "unsupported attachment type: application/zip"},
+		{name: "html", data: []byte("<html><script>alert(1)</script>"), errContains: "unsupported attachment type: text/html"},
+		{
+			name:        "too large",
+			data:        bytes.Repeat([]byte("a"), MaxContentSize+1),
+			errContains: "above the limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := NewContent(ledgerID, tt.data)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedType, content.ContentType)
+			assert.Equal(t, int64(len(tt.data)), content.Size)
+			assert.Len(t, content.SHA256, 64)
+		})
+	}
+}
+
+func TestContent_BlobKey(t *testing.T) {
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	first, err := NewContent(ledgerID, pdfData)
+	require.NoError(t, err)
+	second, err := NewContent(ledgerID, bytes.Clone(pdfData))
+	require.NoError(t, err)
+
+	assert.Equal(t, first.BlobKey(), second.BlobKey(), "the same file is stored once")
+	assert.Equal(t, "ledgers/"+ledgerID.String()+"/"+first.SHA256, first.BlobKey())
+}
+
+func TestNewAttachment(t *testing.T) {
+	content := createTestContent(t)
+	owner := createTestOwner(t)
+
+	tests := []struct {
+		name         string
+		owner        Owner
+		fileName     string
+		expectedName string
+		errContains  string
+	}{
+		{name: "valid", owner: owner, fileName: "receipt.pdf", expectedName: "receipt.pdf"},
+		{name: "directories dropped", owner: owner, fileName: `C:\Users\me\..\receipt.pdf`, expectedName: "receipt.pdf"},
+		{name: "path traversal", owner: owner, fileName: "../../etc/passwd", expectedName: "passwd"},
+		{name: "empty", owner: owner, fileName: "  ", errContains: "file name cannot be empty"},
+		{name: "only a directory", owner: owner, fileName: "scans/", errContains: "file name cannot be empty"},
+		{name: "control characters", owner: owner, fileName: "a\nb.pdf", errContains: "control characters"},
+		{name: "too long", owner: owner, fileName: strings.Repeat("a", 256), errContains: "cannot exceed 255"},
+		{name: "invalid owner", owner: Owner{Type: OwnerTypeTransaction}, fileName: "receipt.pdf", errContains: "owner ID is invalid"},
+		{
+			name:        "invalid owner type",
+			owner:       Owner{Type: "LEDGER", ID: owner.ID},
+			fileName:    "receipt.pdf",
+			errContains: "invalid attachment owner type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachment, err := NewAttachment(tt.owner, tt.fileName, content)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedName, attachment.FileName)
+			assert.True(t, attachment.LedgerID.Equals(content.LedgerID))
+			assert.False(t, attachment.IsOwnerDeleted())
+		})
+	}
+}
+
+func TestAttachment_IsExpired(t *testing.T) {
+	attachment, err := NewAttachment(createTestOwner(t), "receipt.pdf", createTestContent(t))
+	require.NoError(t, err)
+
+	deletedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	assert.False(t, attachment.IsExpired(deletedAt.Add(RetentionPeriod)))
+
+	attachment.OwnerDeletedAt = optional.Some(deletedAt)
+	assert.True(t, attachment.IsOwnerDeleted())
+	assert.False(t, attachment.IsExpired(deletedAt.Add(RetentionPeriod)))
+	assert.True(t, attachment.IsExpired(deletedAt.Add(RetentionPeriod+time.Second)))
+}
+
+// Helper functions
+
+func createTestContent(t *testing.T) Content {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	content, err := NewContent(ledgerID, pdfData)
+	require.NoError(t, err)
+	return *content
+}
+
+func createTestOwner(t *testing.T) Owner {
+	t.Helper()
+
+	transactionID, err := accountingEntity.NewTransactionID()
+	require.NoError(t, err)
+	return TransactionOwner(transactionID)
+}
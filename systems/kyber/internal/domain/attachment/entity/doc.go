@@ -0,0 +1,2 @@
+// Package entity contains domain entities and value objects for documents attached to transactions and counterparties.
+package entity
@@ -0,0 +1,2 @@
+// Package repository provides data and blob persistence interfaces for attachment domain entities.
+package repository
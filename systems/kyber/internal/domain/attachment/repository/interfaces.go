@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/attachment/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+// ErrNotFound is returned when the requested attachment or blob does not exist
+var ErrNotFound = errors.New("not found")
+
+// AttachmentRepository persists attachment metadata. File contents are kept in a BlobStore.
+type AttachmentRepository interface {
+	// LockContent stores the content's metadata unless the ledger already has the same file, and locks it
+	// until the surrounding transaction ends. Returns true if the content was new or marked deleted,
+	// in which case the file has to be stored again.
+	LockContent(ctx context.Context, content *entity.Content) (bool, error)
+	// Create stores a new attachment of content locked by LockContent
+	Create(ctx context.Context, attachment *entity.Attachment) error
+	// Delete removes the attachment
+	Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.AttachmentID) error
+	// MarkContentDeletedIfUnused marks the content deleted unless attachments still share it,
+	// returning true if it was marked. Its file is removed later, by DeleteContent.
+	MarkContentDeletedIfUnused(ctx context.Context, content entity.Content) (bool, error)
+	// ListDeletedContents returns up to limit contents of any ledger that are marked deleted
+	ListDeletedContents(ctx context.Context, limit int) ([]entity.Content, error)
+	// DeleteContent removes the metadata of content that is still marked deleted, locking it until the
+	// surrounding transaction ends. Returns false if it was attached again or already removed.
+	DeleteContent(ctx context.Context, content entity.Content) (bool, error)
+	// GetByID returns the attachment
+	GetByID(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.AttachmentID) (*entity.Attachment, error)
+	// ListByOwner returns the attachments of an owner that has not been deleted, oldest first
+	ListByOwner(ctx context.Context, ledgerID ledgerEntity.LedgerID, owner entity.Owner) ([]*entity.Attachment, error)
+	// LockOwner checks if the ledger has the transaction or counterparty and keeps it from being deleted
+	// until the surrounding transaction ends
+	LockOwner(ctx context.Context, ledgerID ledgerEntity.LedgerID, owner entity.Owner) (bool, error)
+	// ListOwnerDeletedBefore returns up to limit attachments of any ledger whose owner was deleted before the cutoff
+	ListOwnerDeletedBefore(ctx context.Context, cutoff time.Time, limit int) ([]*entity.Attachment, error)
+}
+
+// BlobStore keeps file contents under opaque keys
+type BlobStore interface {
+	// Put stores the content under the key, replacing anything stored there
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	// Get opens the content stored under the key; callers close it. Returns ErrNotFound if there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the content stored under the key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	accountingRepository "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/attachment/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/attachment/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+// purgeBatchSize is how many expired attachments PurgeExpired loads at a time
+const purgeBatchSize = 100
+
+// AttachInput describes a file to attach to a transaction or counterparty
+type AttachInput struct {
+	LedgerID ledgerEntity.LedgerID
+	Owner    entity.Owner
+	FileName string
+	Content  io.Reader // Read up to entity.MaxContentSize; larger files are rejected
+}
+
+// AttachmentUseCase attaches receipts, invoices and other documents to transactions and counterparties.
+// Files are stored once per ledger in the blob store, keyed by their SHA-256 digest. When the last attachment
+// sharing a file is removed its content is marked deleted, and PurgeExpired removes the file. Attachment
+// metadata and blob writes are serialised per file through the lock taken by AttachmentRepository.LockContent.
+type AttachmentUseCase struct {
+	transactor  accountingRepository.Transactor
+	attachments repository.AttachmentRepository
+	blobs       repository.BlobStore
+}
+
+// NewAttachmentUseCase creates a new AttachmentUseCase
+func NewAttachmentUseCase(
+	transactor accountingRepository.Transactor,
+	attachments repository.AttachmentRepository,
+	blobs repository.BlobStore,
+) *AttachmentUseCase {
+	return &AttachmentUseCase{
+		transactor:  transactor,
+		attachments: attachments,
+		blobs:       blobs,
+	}
+}
+
+// Attach stores the file and attaches it to the owner. Attaching a file the owner already has returns
+// the existing attachment.
+func (u *AttachmentUseCase) Attach(ctx context.Context, in AttachInput) (*entity.Attachment, error) {
+	data, err := io.ReadAll(io.LimitReader(in.Content, entity.MaxContentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	content, err := entity.NewContent(in.LedgerID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := entity.NewAttachment(in.Owner, in.FileName, *content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// Held until commit so the owner cannot be deleted, and its attachments marked, before this one exists
+		exists, err := u.attachments.LockOwner(ctx, in.LedgerID, in.Owner)
+		if err != nil {
+			return fmt.Errorf("failed to lock attachment owner: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("attachment owner %s: %w", in.Owner, repository.ErrNotFound)
+		}
+
+		created, err := u.attachments.LockContent(ctx, content)
+		if err != nil {
+			return fmt.Errorf("failed to lock attachment content: %w", err)
+		}
+
+		if created {
+			// A failure after this leaves an unreferenced blob; storing the same file again overwrites it
+			if err := u.blobs.Put(ctx, content.BlobKey(), bytes.NewReader(data), content.Size,
+				content.ContentType); err != nil {
+				return fmt.Errorf("failed to store attachment content: %w", err)
+			}
+		}
+
+		existing, err := u.attachments.ListByOwner(ctx, in.LedgerID, in.Owner)
+		if err != nil {
+			return fmt.Errorf("failed to list attachments: %w", err)
+		}
+		for _, a := range existing {
+			if a.Content.SHA256 == content.SHA256 {
+				attachment = a
+				return nil
+			}
+		}
+
+		if err := u.attachments.Create(ctx, attachment); err != nil {
+			return fmt.Errorf("failed to create attachment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// ListAttachments returns the attachments of an owner, oldest first
+func (u *AttachmentUseCase) ListAttachments(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	owner entity.Owner,
+) ([]*entity.Attachment, error) {
+	attachments, err := u.attachments.ListByOwner(ctx, ledgerID, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// OpenAttachment returns the attachment and its file; callers close the file
+func (u *AttachmentUseCase) OpenAttachment(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.AttachmentID,
+) (*entity.Attachment, io.ReadCloser, error) {
+	attachment, err := u.attachments.GetByID(ctx, ledgerID, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	file, err := u.blobs.Get(ctx, attachment.Content.BlobKey())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment content: %w", err)
+	}
+	return attachment, file, nil
+}
+
+// DeleteAttachment removes the attachment. Unless other attachments share it, its file is removed by
+// the next PurgeExpired.
+func (u *AttachmentUseCase) DeleteAttachment(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.AttachmentID,
+) error {
+	attachment, err := u.attachments.GetByID(ctx, ledgerID, id)
+	if err != nil {
+		return fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return u.delete(ctx, attachment)
+}
+
+// PurgeExpired removes the attachments whose owner was deleted more than entity.RetentionPeriod before now,
+// then the files no attachment uses any more. It is meant to run periodically and returns how many attachments
+// were removed. A file that cannot be removed stays marked deleted and is retried on the next run.
+func (u *AttachmentUseCase) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	cutoff := now.Add(-entity.RetentionPeriod)
+
+	var purged int
+	for {
+		expired, err := u.attachments.ListOwnerDeletedBefore(ctx, cutoff, purgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("failed to list expired attachments: %w", err)
+		}
+
+		for _, attachment := range expired {
+			if err := u.delete(ctx, attachment); err != nil {
+				return purged, fmt.Errorf("failed to purge attachment %s: %w", attachment.ID, err)
+			}
+			purged++
+		}
+
+		if len(expired) < purgeBatchSize {
+			break
+		}
+	}
+
+	return purged, u.purgeContents(ctx)
+}
+
+// purgeContents removes the files of contents marked deleted. Each file is removed while its content row
+// is deleted and locked, so a concurrent Attach of the same file waits and stores it again; if the removal
+// or the commit fails, the row stays marked and the file is removed on a later run.
+func (u *AttachmentUseCase) purgeContents(ctx context.Context) error {
+	for {
+		deleted, err := u.attachments.ListDeletedContents(ctx, purgeBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list deleted attachment contents: %w", err)
+		}
+
+		for _, content := range deleted {
+			err := u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+				removed, err := u.attachments.DeleteContent(ctx, content)
+				if err != nil || !removed {
+					return err
+				}
+
+				if err := u.blobs.Delete(ctx, content.BlobKey()); err != nil {
+					return fmt.Errorf("failed to delete attachment content: %w", err)
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to purge attachment content %s: %w", content.BlobKey(), err)
+			}
+		}
+
+		if len(deleted) < purgeBatchSize {
+			return nil
+		}
+	}
+}
+
+func (u *AttachmentUseCase) delete(ctx context.Context, attachment *entity.Attachment) error {
+	return u.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		content := attachment.Content
+		if _, err := u.attachments.LockContent(ctx, &content); err != nil {
+			return fmt.Errorf("failed to lock attachment content: %w", err)
+		}
+
+		if err := u.attachments.Delete(ctx, attachment.LedgerID, attachment.ID); err != nil {
+			return fmt.Errorf("failed to delete attachment: %w", err)
+		}
+
+		// The file is left in place: it is only removed by purgeContents once this has committed
+		if _, err := u.attachments.MarkContentDeletedIfUnused(ctx, content); err != nil {
+			return fmt.Errorf("failed to mark attachment content deleted: %w", err)
+		}
+		return nil
+	})
+}
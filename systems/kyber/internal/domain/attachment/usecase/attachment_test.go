@@ -0,0 +1,426 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	accountingEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/attachment/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/attachment/repository"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+var (
+	receiptPDF  = []byte("%PDF-1.7\n1 0 obj\n<< /Type /Catalog >>\nendobj\n")
+	warrantyTXT = []byte("Warranty: 2 years from purchase")
+)
+
+func TestAttachmentUseCase_Attach(t *testing.T) {
+	f := newAttachmentFixture(t)
+	ctx := context.Background()
+
+	receipt, err := f.useCase.Attach(ctx, AttachInput{
+		LedgerID: f.ledgerID,
+		Owner:    f.transaction,
+		FileName: "receipt.pdf",
+		Content:  bytes.NewReader(receiptPDF),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", receipt.Content.ContentType)
+	assert.Equal(t, receiptPDF, f.blobs.stored[receipt.Content.BlobKey()])
+
+	// The same file again: the existing attachment is returned
+	again, err := f.useCase.Attach(ctx, AttachInput{
+		LedgerID: f.ledgerID,
+		Owner:    f.transaction,
+		FileName: "receipt (1).pdf",
+		Content:  bytes.NewReader(receiptPDF),
+	})
+	require.NoError(t, err)
+	assert.True(t, again.ID.Equals(receipt.ID))
+
+	// The same file on another owner: stored once, attached twice
+	invoice, err := f.useCase.Attach(ctx, AttachInput{
+		LedgerID: f.ledgerID,
+		Owner:    f.counterparty,
+		FileName: "invoice.pdf",
+		Content:  bytes.NewReader(receiptPDF),
+	})
+	require.NoError(t, err)
+	assert.False(t, invoice.ID.Equals(receipt.ID))
+	assert.Len(t, f.blobs.stored, 1)
+	assert.Equal(t, 1, f.blobs.puts)
+
+	attachments, err := f.useCase.ListAttachments(ctx, f.ledgerID, f.transaction)
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+
+	_, file, err := f.useCase.OpenAttachment(ctx, f.ledgerID, invoice.ID)
+	require.NoError(t, err)
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, receiptPDF, data)
+}
+
+func TestAttachmentUseCase_Attach_Rejected(t *testing.T) {
+	f := newAttachmentFixture(t)
+
+	missingID, err := accountingEntity.NewTransactionID()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		owner       entity.Owner
+		content     io.Reader
+		errContains string
+	}{
+		{
+			name:        "unknown owner",
+			owner:       entity.TransactionOwner(missingID),
+			content:     bytes.NewReader(receiptPDF),
+			errContains: "not found",
+		},
+		{
+			name:        "too large",
+			owner:       f.transaction,
+			content:     io.MultiReader(bytes.NewReader(receiptPDF), strings.NewReader(strings.Repeat("a", entity.MaxContentSize))),
+			errContains: "above the limit",
+		},
+		{
+			name:        "executable",
+			owner:       f.transaction,
+			content:     bytes.NewReader([]byte("MZ\x90\x00\x03\x00\x00\x00")),
+			errContains: "unsupported attachment type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.useCase.Attach(context.Background(), AttachInput{
+				LedgerID: f.ledgerID,
+				Owner:    tt.owner,
+				FileName: "file",
+				Content:  tt.content,
+			})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+			assert.Empty(t, f.blobs.stored)
+		})
+	}
+}
+
+func TestAttachmentUseCase_DeleteAttachment(t *testing.T) {
+	f := newAttachmentFixture(t)
+	ctx := context.Background()
+
+	receipt := f.attach(t, f.transaction, receiptPDF)
+	shared := f.attach(t, f.counterparty, receiptPDF)
+
+	require.NoError(t, f.useCase.DeleteAttachment(ctx, f.ledgerID, receipt.ID))
+	assert.Empty(t, f.attachments.deleted, "the file is still attached to the counterparty")
+
+	require.NoError(t, f.useCase.DeleteAttachment(ctx, f.ledgerID, shared.ID))
+	assert.Len(t, f.blobs.stored, 1, "the file is only removed by the purge, after the deletion committed")
+	assert.True(t, f.attachments.deleted[shared.Content.BlobKey()])
+
+	err := f.useCase.DeleteAttachment(ctx, f.ledgerID, shared.ID)
+	require.ErrorIs(t, err, repository.ErrNotFound)
+
+	// A failed removal keeps the content marked so the next purge retries it
+	f.blobs.failDeletes = true
+	_, err = f.useCase.PurgeExpired(ctx, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to purge attachment content")
+	assert.Len(t, f.blobs.stored, 1)
+	assert.True(t, f.attachments.deleted[shared.Content.BlobKey()])
+
+	f.blobs.failDeletes = false
+	_, err = f.useCase.PurgeExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, f.blobs.stored)
+	assert.Empty(t, f.attachments.contents)
+}
+
+func TestAttachmentUseCase_Attach_DeletedContent(t *testing.T) {
+	f := newAttachmentFixture(t)
+	ctx := context.Background()
+
+	receipt := f.attach(t, f.transaction, receiptPDF)
+	require.NoError(t, f.useCase.DeleteAttachment(ctx, f.ledgerID, receipt.ID))
+	delete(f.blobs.stored, receipt.Content.BlobKey()) // As if a purge removed the file but failed to commit
+
+	// Attaching the file again before the purge revives its content and stores the file again
+	again := f.attach(t, f.transaction, receiptPDF)
+	assert.Equal(t, receiptPDF, f.blobs.stored[again.Content.BlobKey()])
+	assert.Empty(t, f.attachments.deleted)
+
+	_, err := f.useCase.PurgeExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Contains(t, f.blobs.stored, again.Content.BlobKey())
+}
+
+func TestAttachmentUseCase_PurgeExpired(t *testing.T) {
+	f := newAttachmentFixture(t)
+	ctx := context.Background()
+
+	receipt := f.attach(t, f.transaction, receiptPDF)
+	warranty := f.attach(t, f.transaction, warrantyTXT)
+	invoice := f.attach(t, f.counterparty, receiptPDF)
+
+	// The transaction is deleted: its attachments are kept for the retention period
+	deletedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	f.attachments.ownerDeleted(f.transaction, deletedAt)
+
+	attachments, err := f.useCase.ListAttachments(ctx, f.ledgerID, f.transaction)
+	require.NoError(t, err)
+	assert.Empty(t, attachments)
+
+	purged, err := f.useCase.PurgeExpired(ctx, deletedAt.Add(entity.RetentionPeriod))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	assert.Len(t, f.attachments.stored, 3)
+
+	purged, err = f.useCase.PurgeExpired(ctx, deletedAt.Add(entity.RetentionPeriod+time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	assert.NotContains(t, f.attachments.stored, receipt.ID.String())
+	assert.NotContains(t, f.attachments.stored, warranty.ID.String())
+	assert.Contains(t, f.attachments.stored, invoice.ID.String())
+	assert.Contains(t, f.blobs.stored, invoice.Content.BlobKey(), "still attached to the counterparty")
+	assert.NotContains(t, f.blobs.stored, warranty.Content.BlobKey())
+}
+
+// Helper functions
+
+type attachmentFixture struct {
+	ledgerID     ledgerEntity.LedgerID
+	transaction  entity.Owner
+	counterparty entity.Owner
+	attachments  *fakeAttachmentRepository
+	blobs        *fakeBlobStore
+	useCase      *AttachmentUseCase
+}
+
+func newAttachmentFixture(t *testing.T) *attachmentFixture {
+	t.Helper()
+
+	ledgerID, err := ledgerEntity.NewLedgerID()
+	require.NoError(t, err)
+
+	transactionID, err := accountingEntity.NewTransactionID()
+	require.NoError(t, err)
+
+	counterpartyID, err := counterpartyEntity.NewCounterpartyID()
+	require.NoError(t, err)
+
+	f := &attachmentFixture{
+		ledgerID:     ledgerID,
+		transaction:  entity.TransactionOwner(transactionID),
+		counterparty: entity.CounterpartyOwner(counterpartyID),
+		blobs:        &fakeBlobStore{stored: map[string][]byte{}},
+	}
+	f.attachments = &fakeAttachmentRepository{
+		stored:   map[string]*entity.Attachment{},
+		contents: map[string]entity.Content{},
+		deleted:  map[string]bool{},
+		owners:   []entity.Owner{f.transaction, f.counterparty},
+	}
+	f.useCase = NewAttachmentUseCase(fakeTransactor{f.attachments}, f.attachments, f.blobs)
+	return f
+}
+
+func (f *attachmentFixture) attach(t *testing.T, owner entity.Owner, data []byte) *entity.Attachment {
+	t.Helper()
+
+	attachment, err := f.useCase.Attach(context.Background(), AttachInput{
+		LedgerID: f.ledgerID,
+		Owner:    owner,
+		FileName: "file",
+		Content:  bytes.NewReader(data),
+	})
+	require.NoError(t, err)
+	return attachment
+}
+
+// fakeTransactor rolls back the attachment repository when fn fails, as a database transaction would
+type fakeTransactor struct {
+	attachments *fakeAttachmentRepository
+}
+
+func (t fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	stored, contents, deleted := maps.Clone(t.attachments.stored), maps.Clone(t.attachments.contents),
+		maps.Clone(t.attachments.deleted)
+	t.attachments.inTx = true
+	defer func() { t.attachments.inTx = false }()
+	if err := fn(ctx); err != nil {
+		t.attachments.stored, t.attachments.contents, t.attachments.deleted = stored, contents, deleted
+		return err
+	}
+	return nil
+}
+
+type fakeAttachmentRepository struct {
+	stored   map[string]*entity.Attachment
+	contents map[string]entity.Content // By blob key
+	deleted  map[string]bool           // Blob keys of contents marked deleted
+	owners   []entity.Owner
+	inTx     bool // Owners are only locked for as long as a transaction lasts
+}
+
+func (f *fakeAttachmentRepository) LockContent(_ context.Context, content *entity.Content) (bool, error) {
+	if _, ok := f.contents[content.BlobKey()]; ok && !f.deleted[content.BlobKey()] {
+		return false, nil
+	}
+	f.contents[content.BlobKey()] = *content
+	delete(f.deleted, content.BlobKey())
+	return true, nil
+}
+
+func (f *fakeAttachmentRepository) Create(_ context.Context, attachment *entity.Attachment) error {
+	c := *attachment
+	f.stored[attachment.ID.String()] = &c
+	return nil
+}
+
+func (f *fakeAttachmentRepository) Delete(_ context.Context, _ ledgerEntity.LedgerID, id entity.AttachmentID) error {
+	if _, ok := f.stored[id.String()]; !ok {
+		return fmt.Errorf("attachment %s: %w", id, repository.ErrNotFound)
+	}
+	delete(f.stored, id.String())
+	return nil
+}
+
+func (f *fakeAttachmentRepository) MarkContentDeletedIfUnused(_ context.Context, content entity.Content) (bool, error) {
+	for _, a := range f.stored {
+		if a.Content.BlobKey() == content.BlobKey() {
+			return false, nil
+		}
+	}
+	f.deleted[content.BlobKey()] = true
+	return true, nil
+}
+
+func (f *fakeAttachmentRepository) ListDeletedContents(_ context.Context, limit int) ([]entity.Content, error) {
+	var contents []entity.Content
+	for key := range f.deleted {
+		if len(contents) < limit {
+			contents = append(contents, f.contents[key])
+		}
+	}
+	return contents, nil
+}
+
+func (f *fakeAttachmentRepository) DeleteContent(_ context.Context, content entity.Content) (bool, error) {
+	if !f.deleted[content.BlobKey()] {
+		return false, nil
+	}
+	delete(f.contents, content.BlobKey())
+	delete(f.deleted, content.BlobKey())
+	return true, nil
+}
+
+func (f *fakeAttachmentRepository) GetByID(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.AttachmentID,
+) (*entity.Attachment, error) {
+	a, ok := f.stored[id.String()]
+	if !ok || !a.LedgerID.Equals(ledgerID) {
+		return nil, fmt.Errorf("attachment %s: %w", id, repository.ErrNotFound)
+	}
+	c := *a
+	return &c, nil
+}
+
+func (f *fakeAttachmentRepository) ListByOwner(
+	_ context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	owner entity.Owner,
+) ([]*entity.Attachment, error) {
+	var attachments []*entity.Attachment
+	for _, a := range f.stored {
+		if a.LedgerID.Equals(ledgerID) && a.Owner.Equals(owner) && !a.IsOwnerDeleted() {
+			c := *a
+			attachments = append(attachments, &c)
+		}
+	}
+	return attachments, nil
+}
+
+func (f *fakeAttachmentRepository) LockOwner(_ context.Context, _ ledgerEntity.LedgerID, owner entity.Owner) (bool, error) {
+	if !f.inTx {
+		return false, fmt.Errorf("attachment owner locked outside a transaction")
+	}
+	return slices.ContainsFunc(f.owners, owner.Equals), nil
+}
+
+func (f *fakeAttachmentRepository) ListOwnerDeletedBefore(
+	_ context.Context,
+	cutoff time.Time,
+	limit int,
+) ([]*entity.Attachment, error) {
+	var attachments []*entity.Attachment
+	for _, a := range f.stored {
+		if a.IsExpired(cutoff.Add(entity.RetentionPeriod)) && len(attachments) < limit {
+			c := *a
+			attachments = append(attachments, &c)
+		}
+	}
+	return attachments, nil
+}
+
+// ownerDeleted does what deleting the owner row does in Postgres
+func (f *fakeAttachmentRepository) ownerDeleted(owner entity.Owner, at time.Time) {
+	for _, a := range f.stored {
+		if a.Owner.Equals(owner) {
+			a.OwnerDeletedAt = optional.Some(at)
+		}
+	}
+	f.owners = slices.DeleteFunc(f.owners, owner.Equals)
+}
+
+// fakeBlobStore counts writes so tests can check files are stored once, and can be made to fail deletes
+type fakeBlobStore struct {
+	stored      map[string][]byte
+	puts        int
+	failDeletes bool
+}
+
+func (f *fakeBlobStore) Put(_ context.Context, key string, content io.Reader, _ int64, _ string) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	f.stored[key] = data
+	f.puts++
+	return nil
+}
+
+func (f *fakeBlobStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := f.stored[key]
+	if !ok {
+		return nil, fmt.Errorf("blob %s: %w", key, repository.ErrNotFound)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (f *fakeBlobStore) Delete(_ context.Context, key string) error {
+	if f.failDeletes {
+		return fmt.Errorf("blob store unavailable")
+	}
+	delete(f.stored, key)
+	return nil
+}
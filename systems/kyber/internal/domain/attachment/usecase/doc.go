@@ -0,0 +1,2 @@
+// Package usecase provides application use cases orchestrating attachment domain operations.
+package usecase
@@ -27,6 +27,10 @@ func NewBudgetTracking(year, month int, targetAmount money.Money) (*BudgetTracki
 		return nil, fmt.Errorf("invalid month: %d", month)
 	}
 
+	if err := targetAmount.ValidatePrecision(); err != nil {
+		return nil, fmt.Errorf("invalid target amount: %w", err)
+	}
+
 	// Initialize budgeted amount to target amount
 	// Actual amount starts at zero
 	actualAmount, err := money.Zero(targetAmount.Currency)
@@ -66,6 +70,10 @@ func (bt *BudgetTracking) UpdateBudgetedAmount(amount money.Money) error {
 		return fmt.Errorf("currency mismatch: expected %s, got %s", bt.TargetAmount.Currency, amount.Currency)
 	}
 
+	if err := amount.ValidatePrecision(); err != nil {
+		return fmt.Errorf("invalid budgeted amount: %w", err)
+	}
+
 	bt.BudgetedAmount = amount
 	bt.UpdatedAt = time.Now()
 	return nil
@@ -87,6 +95,17 @@ func (bt *BudgetTracking) AddActualAmount(amount money.Money) error {
 	return nil
 }
 
+// SetActualAmount replaces the actual amount (when derived from journal postings)
+func (bt *BudgetTracking) SetActualAmount(amount money.Money) error {
+	if amount.Currency != bt.ActualAmount.Currency {
+		return fmt.Errorf("currency mismatch: expected %s, got %s", bt.ActualAmount.Currency, amount.Currency)
+	}
+
+	bt.ActualAmount = amount
+	bt.UpdatedAt = time.Now()
+	return nil
+}
+
 // SubtractActualAmount subtracts from the actual amount (transaction reversal)
 func (bt *BudgetTracking) SubtractActualAmount(amount money.Money) error {
 	if amount.Currency != bt.ActualAmount.Currency {
@@ -131,20 +150,20 @@ func (bt *BudgetTracking) IsOverTarget() bool {
 	return variance.IsNegative()
 }
 
-// GetBudgetUtilization returns the percentage of budget used (0.0 to 1.0+)
-func (bt *BudgetTracking) GetBudgetUtilization() float64 {
+// GetBudgetUtilization returns the percentage of budget used (0% to 100%+)
+func (bt *BudgetTracking) GetBudgetUtilization() (money.Percent, error) {
 	if bt.BudgetedAmount.IsZero() {
-		return 0.0
+		return money.Percent{}, nil
 	}
-	return bt.ActualAmount.Float64() / bt.BudgetedAmount.Float64()
+	return money.RatioOf(bt.ActualAmount, bt.BudgetedAmount)
 }
 
-// GetTargetUtilization returns the percentage of target used (0.0 to 1.0+)
-func (bt *BudgetTracking) GetTargetUtilization() float64 {
+// GetTargetUtilization returns the percentage of target used (0% to 100%+)
+func (bt *BudgetTracking) GetTargetUtilization() (money.Percent, error) {
 	if bt.TargetAmount.IsZero() {
-		return 0.0
+		return money.Percent{}, nil
 	}
-	return bt.ActualAmount.Float64() / bt.TargetAmount.Float64()
+	return money.RatioOf(bt.ActualAmount, bt.TargetAmount)
 }
 
 // GetRemainingBudget returns the remaining budget amount
@@ -60,6 +60,14 @@ func TestNewBudgetTracking(t *testing.T) {
 			wantErr:      true,
 			errContains:  "invalid month",
 		},
+		{
+			name:         "target exceeds currency precision",
+			year:         2024,
+			month:        6,
+			targetAmount: mustMoney(t, "500.001", "USD"),
+			wantErr:      true,
+			errContains:  "exceeds USD precision",
+		},
 		{
 			name:         "edge case - minimum valid year",
 			year:         1900,
@@ -225,6 +233,24 @@ func TestBudgetTracking_AddActualAmount(t *testing.T) {
 	assert.Equal(t, expectedFinalActual, bt.ActualAmount)
 }
 
+func TestBudgetTracking_SetActualAmount(t *testing.T) {
+	bt := createTestBudgetTracking(t)
+	require.NoError(t, bt.AddActualAmount(mustMoney(t, "200.00", "USD")))
+
+	originalUpdatedAt := bt.UpdatedAt
+	time.Sleep(time.Millisecond)
+
+	err := bt.SetActualAmount(mustMoney(t, "75.25", "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, mustMoney(t, "75.25", "USD"), bt.ActualAmount)
+	assert.True(t, bt.UpdatedAt.After(originalUpdatedAt))
+
+	err = bt.SetActualAmount(mustMoney(t, "10.00", "EUR"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "currency mismatch")
+	assert.Equal(t, mustMoney(t, "75.25", "USD"), bt.ActualAmount)
+}
+
 func TestBudgetTracking_SubtractActualAmount(t *testing.T) {
 	bt := createTestBudgetTracking(t)
 	// Add some initial actual amount
@@ -413,37 +439,50 @@ func TestBudgetTracking_GetBudgetUtilization(t *testing.T) {
 		name           string
 		budgetedAmount money.Money
 		actualAmount   money.Money
-		expected       float64
+		expected       string
+		wantErr        bool
 	}{
 		{
 			name:           "50% utilization",
 			budgetedAmount: mustMoney(t, "100.00", "USD"),
 			actualAmount:   mustMoney(t, "50.00", "USD"),
-			expected:       0.5,
+			expected:       "50.00%",
 		},
 		{
 			name:           "100% utilization",
 			budgetedAmount: mustMoney(t, "200.00", "USD"),
 			actualAmount:   mustMoney(t, "200.00", "USD"),
-			expected:       1.0,
+			expected:       "100.00%",
 		},
 		{
 			name:           "150% utilization (over budget)",
 			budgetedAmount: mustMoney(t, "100.00", "USD"),
 			actualAmount:   mustMoney(t, "150.00", "USD"),
-			expected:       1.5,
+			expected:       "150.00%",
 		},
 		{
 			name:           "zero budget",
 			budgetedAmount: mustMoney(t, "0.00", "USD"),
 			actualAmount:   mustMoney(t, "50.00", "USD"),
-			expected:       0.0,
+			expected:       "0.00%",
+		},
+		{
+			name:           "one third",
+			budgetedAmount: mustMoney(t, "300.00", "USD"),
+			actualAmount:   mustMoney(t, "100.00", "USD"),
+			expected:       "33.33%",
+		},
+		{
+			name:           "currency mismatch",
+			budgetedAmount: mustMoney(t, "100.00", "USD"),
+			actualAmount:   mustMoney(t, "50.00", "SGD"),
+			wantErr:        true,
 		},
 		{
 			name:           "zero actual",
 			budgetedAmount: mustMoney(t, "100.00", "USD"),
 			actualAmount:   mustMoney(t, "0.00", "USD"),
-			expected:       0.0,
+			expected:       "0.00%",
 		},
 	}
 
@@ -452,8 +491,13 @@ func TestBudgetTracking_GetBudgetUtilization(t *testing.T) {
 			bt.BudgetedAmount = tt.budgetedAmount
 			bt.ActualAmount = tt.actualAmount
 
-			result := bt.GetBudgetUtilization()
-			assert.InDelta(t, tt.expected, result, 0.001)
+			result, err := bt.GetBudgetUtilization()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.String())
 		})
 	}
 }
@@ -465,31 +509,38 @@ func TestBudgetTracking_GetTargetUtilization(t *testing.T) {
 		name         string
 		targetAmount money.Money
 		actualAmount money.Money
-		expected     float64
+		expected     string
+		wantErr      bool
 	}{
 		{
 			name:         "75% of target",
 			targetAmount: mustMoney(t, "400.00", "USD"),
 			actualAmount: mustMoney(t, "300.00", "USD"),
-			expected:     0.75,
+			expected:     "75.00%",
 		},
 		{
 			name:         "exactly on target",
 			targetAmount: mustMoney(t, "500.00", "USD"),
 			actualAmount: mustMoney(t, "500.00", "USD"),
-			expected:     1.0,
+			expected:     "100.00%",
 		},
 		{
 			name:         "120% of target (over target)",
 			targetAmount: mustMoney(t, "100.00", "USD"),
 			actualAmount: mustMoney(t, "120.00", "USD"),
-			expected:     1.2,
+			expected:     "120.00%",
 		},
 		{
 			name:         "zero target",
 			targetAmount: mustMoney(t, "0.00", "USD"),
 			actualAmount: mustMoney(t, "50.00", "USD"),
-			expected:     0.0,
+			expected:     "0.00%",
+		},
+		{
+			name:         "currency mismatch",
+			targetAmount: mustMoney(t, "100.00", "USD"),
+			actualAmount: mustMoney(t, "50.00", "SGD"),
+			wantErr:      true,
 		},
 	}
 
@@ -498,8 +549,13 @@ func TestBudgetTracking_GetTargetUtilization(t *testing.T) {
 			bt.TargetAmount = tt.targetAmount
 			bt.ActualAmount = tt.actualAmount
 
-			result := bt.GetTargetUtilization()
-			assert.InDelta(t, tt.expected, result, 0.001)
+			result, err := bt.GetTargetUtilization()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.String())
 		})
 	}
 }
@@ -43,6 +43,10 @@ func NewItem(
 		return nil, fmt.Errorf("item currency cannot be empty")
 	}
 
+	if err := currency.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid item currency: %w", err)
+	}
+
 	// Validate category matches type
 	// if category.GetItemType() != itemType {
 	// 	return nil, fmt.Errorf("category %s does not match item type %s", category, itemType)
@@ -130,6 +134,10 @@ func (i *Item) SetMonthlyTarget(year, month int, targetAmount money.Money) error
 		return fmt.Errorf("currency mismatch: item uses %s, target uses %s", i.Currency, targetAmount.Currency)
 	}
 
+	if err := targetAmount.ValidatePrecision(); err != nil {
+		return fmt.Errorf("invalid target amount: %w", err)
+	}
+
 	monthKey := fmt.Sprintf("%04d-%02d", year, month)
 
 	if existing, exists := i.MonthlyBudgets[monthKey]; exists {
@@ -164,24 +172,28 @@ func (i *Item) UpdateMonthlyBudget(year, month int, budgetedAmount money.Money)
 
 // AddActualAmount adds actual spending/income to a specific month
 func (i *Item) AddActualAmount(year, month int, amount money.Money) error {
-	monthKey := fmt.Sprintf("%04d-%02d", year, month)
+	budgetTracking, err := i.getOrCreateMonthlyBudget(year, month)
+	if err != nil {
+		return err
+	}
 
-	budgetTracking, exists := i.MonthlyBudgets[monthKey]
-	if !exists {
-		// Create budget tracking with zero target if it doesn't exist
-		zeroTarget, err := money.Zero(i.Currency)
-		if err != nil {
-			return fmt.Errorf("failed to create zero target: %w", err)
-		}
+	err = budgetTracking.AddActualAmount(amount)
+	if err != nil {
+		return err
+	}
 
-		budgetTracking, err = NewBudgetTracking(year, month, zeroTarget)
-		if err != nil {
-			return fmt.Errorf("failed to create budget tracking: %w", err)
-		}
-		i.MonthlyBudgets[monthKey] = budgetTracking
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetActualAmount replaces the actual spending/income of a specific month
+func (i *Item) SetActualAmount(year, month int, amount money.Money) error {
+	budgetTracking, err := i.getOrCreateMonthlyBudget(year, month)
+	if err != nil {
+		return err
 	}
 
-	err := budgetTracking.AddActualAmount(amount)
+	err = budgetTracking.SetActualAmount(amount)
 	if err != nil {
 		return err
 	}
@@ -190,6 +202,28 @@ func (i *Item) AddActualAmount(year, month int, amount money.Money) error {
 	return nil
 }
 
+// getOrCreateMonthlyBudget returns the month's budget tracking, creating it with a zero target if needed
+func (i *Item) getOrCreateMonthlyBudget(year, month int) (*BudgetTracking, error) {
+	monthKey := fmt.Sprintf("%04d-%02d", year, month)
+
+	budgetTracking, exists := i.MonthlyBudgets[monthKey]
+	if exists {
+		return budgetTracking, nil
+	}
+
+	zeroTarget, err := money.Zero(i.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zero target: %w", err)
+	}
+
+	budgetTracking, err = NewBudgetTracking(year, month, zeroTarget)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create budget tracking: %w", err)
+	}
+	i.MonthlyBudgets[monthKey] = budgetTracking
+	return budgetTracking, nil
+}
+
 // GetMonthlyBudget returns the budget tracking for a specific month
 func (i *Item) GetMonthlyBudget(year, month int) *BudgetTracking {
 	monthKey := fmt.Sprintf("%04d-%02d", year, month)
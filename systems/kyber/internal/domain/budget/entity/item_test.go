@@ -64,6 +64,16 @@ func TestNewItem(t *testing.T) {
 			wantErr:     true,
 			errContains: "item currency cannot be empty",
 		},
+		{
+			name:        "unsupported currency",
+			ledgerID:    ledgerID,
+			itemName:    "Test Item",
+			description: "Test description",
+			itemType:    ItemTypeIncome,
+			currency:    "XYZ",
+			wantErr:     true,
+			errContains: "unsupported currency",
+		},
 		{
 			name:        "valid income item",
 			ledgerID:    ledgerID,
@@ -263,6 +273,14 @@ func TestItem_SetMonthlyTarget(t *testing.T) {
 			wantErr:      true,
 			errContains:  "currency mismatch",
 		},
+		{
+			name:         "target exceeds currency precision",
+			year:         2024,
+			month:        4,
+			targetAmount: mustMoney(t, "300.005", "USD"),
+			wantErr:      true,
+			errContains:  "exceeds USD precision",
+		},
 		{
 			name:         "update existing monthly target",
 			year:         2024,
@@ -509,6 +527,23 @@ func TestItem_GetTotalBudgetedForYear(t *testing.T) {
 	assert.True(t, totalEmpty.IsZero())
 }
 
+func TestItem_SetActualAmount(t *testing.T) {
+	item := createTestItem(t)
+
+	require.NoError(t, item.AddActualAmount(2024, 3, mustMoney(t, "40.00", "USD")))
+	require.NoError(t, item.SetActualAmount(2024, 3, mustMoney(t, "15.00", "USD")))
+	assert.Equal(t, mustMoney(t, "15.00", "USD"), item.GetMonthlyBudget(2024, 3).ActualAmount)
+
+	// Creates tracking for a month that has none yet
+	require.NoError(t, item.SetActualAmount(2024, 4, mustMoney(t, "8.00", "USD")))
+	tracking := item.GetMonthlyBudget(2024, 4)
+	require.NotNil(t, tracking)
+	assert.Equal(t, mustMoney(t, "8.00", "USD"), tracking.ActualAmount)
+	assert.True(t, tracking.TargetAmount.IsZero())
+
+	assert.Error(t, item.SetActualAmount(2024, 13, mustMoney(t, "8.00", "USD")))
+}
+
 func TestItem_GetTotalActualForYear(t *testing.T) {
 	item := createTestItem(t)
 
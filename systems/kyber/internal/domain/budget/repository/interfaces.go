@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// ErrNotFound is returned when the requested entity does not exist in the ledger
+var ErrNotFound = errors.New("not found")
+
+// ItemRepository persists budget items
+type ItemRepository interface {
+	// GetByID returns the item with its monthly budget tracking
+	GetByID(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.ItemID) (*entity.Item, error)
+	// AddActualAmount adds the amount to the item's actuals of the month in place, so concurrent
+	// additions to the same item are not lost
+	AddActualAmount(
+		ctx context.Context,
+		ledgerID ledgerEntity.LedgerID,
+		id entity.ItemID,
+		year, month int,
+		amount money.Money,
+	) error
+}
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
@@ -104,3 +105,12 @@ func (c *Counterparty) Archive() {
 	c.Status = CounterpartyStatusArchived
 	c.UpdatedAt = time.Now()
 }
+
+// MatchesName checks if the name refers to this counterparty, ignoring case and extra whitespace
+func (c *Counterparty) MatchesName(name string) bool {
+	return normalizeName(c.Name) == normalizeName(name)
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -335,6 +335,26 @@ func TestCounterparty_TypeSpecificBehavior(t *testing.T) {
 	}
 }
 
+func TestCounterparty_MatchesName(t *testing.T) {
+	counterparty := createTestCounterparty(t)
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "Test Counterparty", want: true},
+		{name: "  TEST   counterparty ", want: true},
+		{name: "Test Counterparty Ltd", want: false},
+		{name: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, counterparty.MatchesName(tt.name))
+		})
+	}
+}
+
 // Helper functions
 
 func createTestCounterparty(t *testing.T) *Counterparty {
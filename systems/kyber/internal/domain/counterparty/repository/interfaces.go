@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+// CounterpartyRepository persists counterparties
+type CounterpartyRepository interface {
+	// ListByLedger returns all counterparties of the ledger
+	ListByLedger(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Counterparty, error)
+}
@@ -30,6 +30,14 @@ func NewLedger(name, description string, baseCurrency money.Currency, adminUserI
 		return nil, fmt.Errorf("base currency cannot be empty")
 	}
 
+	if err := baseCurrency.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid base currency: %w", err)
+	}
+
+	if !baseCurrency.Kind().IsFiat() {
+		return nil, fmt.Errorf("base currency must be a fiat currency: %s", baseCurrency)
+	}
+
 	if !adminUserID.IsValid() {
 		return nil, fmt.Errorf("admin user ID is invalid")
 	}
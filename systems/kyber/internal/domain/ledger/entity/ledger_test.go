@@ -50,6 +50,24 @@ func TestNewLedger(t *testing.T) {
 			wantErr:      true,
 			errContains:  "base currency cannot be empty",
 		},
+		{
+			name:         "unsupported base currency",
+			ledgerName:   "Test Ledger",
+			description:  "Test description",
+			baseCurrency: "XYZ",
+			adminUserID:  adminUserID,
+			wantErr:      true,
+			errContains:  "unsupported currency",
+		},
+		{
+			name:         "non-fiat base currency",
+			ledgerName:   "Test Ledger",
+			description:  "Test description",
+			baseCurrency: "BTC",
+			adminUserID:  adminUserID,
+			wantErr:      true,
+			errContains:  "base currency must be a fiat currency",
+		},
 		{
 			name:         "invalid admin user ID",
 			ledgerName:   "Test Ledger",
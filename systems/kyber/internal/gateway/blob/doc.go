@@ -0,0 +1,2 @@
+// Package blob implements attachment blob storage on the local filesystem and S3-compatible object stores.
+package blob
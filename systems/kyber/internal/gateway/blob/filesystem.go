@@ -0,0 +1,131 @@
+package blob
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/attachment/repository"
+)
+
+// Compile-time check that FileSystemStore satisfies the domain interface
+var _ repository.BlobStore = (*FileSystemStore)(nil)
+
+// FileSystemStore keeps blobs as files below a root directory, one file per key.
+// Writes go to a temporary file renamed into place, so readers never see a partial blob.
+type FileSystemStore struct {
+	root *os.Root
+}
+
+// NewFileSystemStore creates a new FileSystemStore, creating the root directory if needed
+func NewFileSystemStore(dir string) (*FileSystemStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("blob root directory cannot be empty")
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob root directory: %w", err)
+	}
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob root directory: %w", err)
+	}
+	return &FileSystemStore{root: root}, nil
+}
+
+// Close releases the root directory
+func (s *FileSystemStore) Close() error {
+	return s.root.Close()
+}
+
+// Put stores the content under the key, replacing anything stored there
+func (s *FileSystemStore) Put(ctx context.Context, key string, content io.Reader, size int64, _ string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	if err := s.root.MkdirAll(path.Dir(key), 0o750); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	tmpName := key + ".tmp-" + rand.Text()
+	tmp, err := s.root.OpenFile(tmpName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to create blob %s: %w", key, err)
+	}
+	defer func() { _ = s.root.Remove(tmpName) }() // Fails once renamed into place
+
+	written, err := io.Copy(tmp, io.LimitReader(contextReader{ctx, content}, size+1))
+	if err == nil && written != size {
+		err = fmt.Errorf("got %d bytes, expected %d", written, size)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write blob %s: %w", key, err)
+	}
+
+	if err := s.root.Rename(tmpName, key); err != nil {
+		return fmt.Errorf("failed to store blob %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get opens the content stored under the key
+func (s *FileSystemStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+
+	file, err := s.root.Open(key)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("blob %s: %w", key, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob %s: %w", key, err)
+	}
+	return file, nil
+}
+
+// Delete removes the content stored under the key
+func (s *FileSystemStore) Delete(_ context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	if err := s.root.Remove(key); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob %s: %w", key, err)
+	}
+	return nil
+}
+
+// checkKey rejects keys that are not clean slash-separated relative paths
+func checkKey(key string) error {
+	if !fs.ValidPath(key) || key == "." {
+		return fmt.Errorf("invalid blob key: %q", key)
+	}
+	return nil
+}
+
+// contextReader stops reading once the context is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
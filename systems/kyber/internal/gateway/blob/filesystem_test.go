@@ -0,0 +1,104 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/attachment/repository"
+)
+
+func TestFileSystemStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := NewFileSystemStore(dir)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	testBlobStore(t, store)
+
+	entries, err := os.ReadDir(filepath.Join(dir, "ledgers", "l1"))
+	require.NoError(t, err)
+	assert.Empty(t, entries, "no temporary files are left behind")
+
+	require.NoError(t, store.Put(ctx, "ledgers/l1/a", strings.NewReader("abc"), 3, "text/plain"))
+	data, err := os.ReadFile(filepath.Join(dir, "ledgers", "l1", "a"))
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(data))
+}
+
+func TestFileSystemStore_Put_SizeMismatch(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	err = store.Put(ctx, "short", strings.NewReader("abc"), 4, "text/plain")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "got 3 bytes, expected 4")
+
+	_, err = store.Get(ctx, "short")
+	assert.ErrorIs(t, err, repository.ErrNotFound, "a failed write stores nothing")
+}
+
+func TestFileSystemStore_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewFileSystemStore(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	for _, key := range []string{"", ".", "../escape", "/abs", "a//b", "a/./b"} {
+		t.Run(key, func(t *testing.T) {
+			err := store.Put(ctx, key, strings.NewReader("abc"), 3, "text/plain")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid blob key")
+
+			_, err = store.Get(ctx, key)
+			assert.Error(t, err)
+			assert.Error(t, store.Delete(ctx, key))
+		})
+	}
+}
+
+// testBlobStore exercises the repository.BlobStore contract on store
+func testBlobStore(t *testing.T, store repository.BlobStore) {
+	t.Helper()
+	ctx := context.Background()
+	key := "ledgers/l1/0a1b2c"
+
+	_, err := store.Get(ctx, key)
+	require.ErrorIs(t, err, repository.ErrNotFound)
+
+	require.NoError(t, store.Put(ctx, key, strings.NewReader("first"), 5, "text/plain"))
+	assert.Equal(t, "first", readBlob(t, store, key))
+
+	require.NoError(t, store.Put(ctx, key, strings.NewReader("second"), 6, "text/plain"))
+	assert.Equal(t, "second", readBlob(t, store, key), "put replaces the content")
+
+	require.NoError(t, store.Delete(ctx, key))
+	_, err = store.Get(ctx, key)
+	require.ErrorIs(t, err, repository.ErrNotFound)
+
+	assert.NoError(t, store.Delete(ctx, key), "deleting a missing key is not an error")
+}
+
+func readBlob(t *testing.T, store repository.BlobStore, key string) string {
+	t.Helper()
+
+	file, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	return string(data)
+}
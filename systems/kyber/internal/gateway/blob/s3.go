@@ -0,0 +1,209 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/attachment/repository"
+)
+
+// Compile-time check that S3Store satisfies the domain interface
+var _ repository.BlobStore = (*S3Store)(nil)
+
+// S3Store keeps blobs as objects in a bucket of an S3-compatible object store, such as AWS S3, MinIO
+// or LocalStack. Requests use path-style addressing and are signed with AWS Signature Version 4.
+type S3Store struct {
+	endpoint *url.URL
+	bucket   string
+	signer   signer
+	options  *s3Options
+}
+
+// s3Options holds configuration for the S3Store (unexported)
+type s3Options struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	httpClient      *http.Client
+}
+
+// S3Option is a function that configures S3 store options
+type S3Option func(*s3Options)
+
+// WithS3Credentials sets the access key used to sign requests
+func WithS3Credentials(accessKeyID, secretAccessKey string) S3Option {
+	return func(o *s3Options) {
+		o.accessKeyID = accessKeyID
+		o.secretAccessKey = secretAccessKey
+	}
+}
+
+// WithS3SessionToken sets the session token of temporary credentials
+func WithS3SessionToken(sessionToken string) S3Option {
+	return func(o *s3Options) {
+		o.sessionToken = sessionToken
+	}
+}
+
+// WithS3HTTPClient sets the HTTP client used for requests (http.DefaultClient by default)
+func WithS3HTTPClient(client *http.Client) S3Option {
+	return func(o *s3Options) {
+		o.httpClient = client
+	}
+}
+
+// NewS3Store creates a new S3Store for the bucket, e.g. with endpoint https://s3.eu-west-1.amazonaws.com
+// or http://localhost:9000 for a local emulator
+func NewS3Store(endpoint, region, bucket string, opts ...S3Option) (*S3Store, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint: %s", endpoint)
+	}
+
+	if region == "" {
+		return nil, fmt.Errorf("S3 region cannot be empty")
+	}
+
+	if bucket == "" || strings.Contains(bucket, "/") {
+		return nil, fmt.Errorf("invalid S3 bucket: %q", bucket)
+	}
+
+	options := &s3Options{httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.accessKeyID == "" || options.secretAccessKey == "" {
+		return nil, fmt.Errorf("S3 credentials cannot be empty")
+	}
+
+	return &S3Store{
+		endpoint: u,
+		bucket:   bucket,
+		signer: signer{
+			accessKeyID:     options.accessKeyID,
+			secretAccessKey: options.secretAccessKey,
+			region:          region,
+			service:         "s3",
+		},
+		options: options,
+	}, nil
+}
+
+// Put stores the content under the key, replacing anything stored there
+func (s *S3Store) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	// The payload is hashed for the signature, so it is read up front; attachments are small
+	data, err := io.ReadAll(io.LimitReader(content, size+1))
+	if err != nil {
+		return fmt.Errorf("failed to read blob %s: %w", key, err)
+	}
+	if int64(len(data)) != size {
+		return fmt.Errorf("failed to read blob %s: got %d bytes, expected %d", key, len(data), size)
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, key, data, contentType)
+	if err != nil {
+		return fmt.Errorf("failed to store blob %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to store blob %s: %w", key, responseError(resp))
+	}
+	return nil
+}
+
+// Get opens the content stored under the key
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob %s: %w", key, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("blob %s: %w", key, repository.ErrNotFound)
+	default:
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("failed to open blob %s: %w", key, responseError(resp))
+	}
+}
+
+// Delete removes the content stored under the key
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to delete blob %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	// S3 answers 204 whether or not the object existed; some emulators answer 404 for a missing one
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK &&
+		resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete blob %s: %w", key, responseError(resp))
+	}
+	return nil
+}
+
+func (s *S3Store) do(ctx context.Context, method, key string, body []byte, contentType string) (*http.Response, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+	u.RawPath = uriEncode(u.Path, false)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	if body == nil {
+		req.Body = http.NoBody
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if s.options.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.options.sessionToken)
+	}
+
+	digest := sha256.Sum256(body)
+	s.signer.sign(req, hex.EncodeToString(digest[:]), time.Now())
+
+	return s.options.httpClient.Do(req)
+}
+
+// s3Error is the XML body of an S3 error response
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// responseError describes an unexpected response, using the S3 error code when there is one
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	var s3Err s3Error
+	if xml.Unmarshal(body, &s3Err) == nil && s3Err.Code != "" {
+		return fmt.Errorf("S3 returned %s: %s: %s", resp.Status, s3Err.Code, s3Err.Message)
+	}
+	return fmt.Errorf("S3 returned %s", resp.Status)
+}
@@ -0,0 +1,204 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAccessKeyID     = "test-access-key"
+	testSecretAccessKey = "test-secret-key"
+	testRegion          = "eu-west-1"
+)
+
+func TestS3Store(t *testing.T) {
+	server := newFakeS3(t, "attachments")
+
+	store, err := NewS3Store(server.URL, testRegion, "attachments",
+		WithS3Credentials(testAccessKeyID, testSecretAccessKey),
+		WithS3HTTPClient(server.Client()),
+	)
+	require.NoError(t, err)
+
+	testBlobStore(t, store)
+}
+
+func TestS3Store_Errors(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeS3(t, "attachments")
+
+	wrongSecret, err := NewS3Store(server.URL, testRegion, "attachments",
+		WithS3Credentials(testAccessKeyID, "wrong"),
+		WithS3HTTPClient(server.Client()),
+	)
+	require.NoError(t, err)
+
+	err = wrongSecret.Put(ctx, "a", strings.NewReader("abc"), 3, "text/plain")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SignatureDoesNotMatch")
+
+	store, err := NewS3Store(server.URL, testRegion, "attachments",
+		WithS3Credentials(testAccessKeyID, testSecretAccessKey),
+		WithS3HTTPClient(server.Client()),
+	)
+	require.NoError(t, err)
+
+	err = store.Put(ctx, "a", strings.NewReader("abc"), 4, "text/plain")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "got 3 bytes, expected 4")
+
+	err = store.Put(ctx, "../a", strings.NewReader("abc"), 3, "text/plain")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid blob key")
+}
+
+func TestNewS3Store(t *testing.T) {
+	credentials := WithS3Credentials(testAccessKeyID, testSecretAccessKey)
+
+	tests := []struct {
+		name        string
+		endpoint    string
+		region      string
+		bucket      string
+		opts        []S3Option
+		errContains string
+	}{
+		{name: "valid", endpoint: "http://localhost:9000", region: testRegion, bucket: "b", opts: []S3Option{credentials}},
+		{name: "no scheme", endpoint: "localhost:9000", region: testRegion, bucket: "b", opts: []S3Option{credentials}, errContains: "invalid S3 endpoint"},
+		{name: "no region", endpoint: "http://localhost:9000", bucket: "b", opts: []S3Option{credentials}, errContains: "region cannot be empty"},
+		{name: "no bucket", endpoint: "http://localhost:9000", region: testRegion, opts: []S3Option{credentials}, errContains: "invalid S3 bucket"},
+		{name: "no credentials", endpoint: "http://localhost:9000", region: testRegion, bucket: "b", errContains: "credentials cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewS3Store(tt.endpoint, tt.region, tt.bucket, tt.opts...)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestS3Store_Emulator runs against an S3-compatible emulator such as MinIO or LocalStack, e.g.
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//	KYBER_TEST_S3_ENDPOINT=http://localhost:9000 KYBER_TEST_S3_BUCKET=kyber-test \
+//	KYBER_TEST_S3_ACCESS_KEY_ID=minioadmin KYBER_TEST_S3_SECRET_ACCESS_KEY=minioadmin go test ./internal/gateway/blob/
+//
+// The bucket must already exist.
+func TestS3Store_Emulator(t *testing.T) {
+	endpoint := os.Getenv("KYBER_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("KYBER_TEST_S3_ENDPOINT is not set")
+	}
+
+	region := os.Getenv("KYBER_TEST_S3_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	store, err := NewS3Store(endpoint, region, os.Getenv("KYBER_TEST_S3_BUCKET"),
+		WithS3Credentials(os.Getenv("KYBER_TEST_S3_ACCESS_KEY_ID"), os.Getenv("KYBER_TEST_S3_SECRET_ACCESS_KEY")),
+	)
+	require.NoError(t, err)
+
+	testBlobStore(t, store)
+}
+
+// newFakeS3 starts a server that keeps the objects of one bucket in memory and checks request signatures
+func newFakeS3(t *testing.T, bucket string) *httptest.Server {
+	t.Helper()
+
+	var (
+		mu      sync.Mutex
+		objects = map[string][]byte{}
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if !validSignature(r, body) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>SignatureDoesNotMatch</Code><Message>The request signature does not match</Message></Error>`)
+			return
+		}
+
+		key, ok := strings.CutPrefix(r.URL.Path, "/"+bucket+"/")
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			objects[key] = body
+		case http.MethodGet:
+			object, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(object)
+		case http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// validSignature signs the headers the client signed again with the test credentials and compares the result
+func validSignature(r *http.Request, body []byte) bool {
+	auth := r.Header.Get("Authorization")
+	_, signedHeaders, ok := strings.Cut(auth, "SignedHeaders=")
+	if !ok {
+		return false
+	}
+	signedHeaders, _, _ = strings.Cut(signedHeaders, ",")
+
+	date, err := time.Parse(amzDateFormat, r.Header.Get("X-Amz-Date"))
+	if err != nil || r.Header.Get("X-Amz-Content-Sha256") != sha256Hex(string(body)) {
+		return false
+	}
+
+	resigned, err := http.NewRequest(r.Method, "http://"+r.Host+r.URL.EscapedPath(), nil)
+	if err != nil {
+		return false
+	}
+	for _, name := range strings.Split(signedHeaders, ";") {
+		if name != "host" {
+			resigned.Header.Set(name, r.Header.Get(name))
+		}
+	}
+
+	s := signer{accessKeyID: testAccessKeyID, secretAccessKey: testSecretAccessKey, region: testRegion, service: "s3"}
+	s.sign(resigned, sha256Hex(string(body)), date)
+
+	return resigned.Header.Get("Authorization") == auth
+}
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+const accountColumns = `id::TEXT, ledger_id::TEXT, name, COALESCE(description, ''), type, currency,
+	balance_amount, status, created_at, updated_at`
+
+// Compile-time check that AccountRepository satisfies the domain interface
+var _ repository.AccountRepository = (*AccountRepository)(nil)
+
+// AccountRepository implements repository.AccountRepository
+type AccountRepository struct {
+	client *pg.Client
+}
+
+// NewAccountRepository creates a new AccountRepository
+func NewAccountRepository(client *pg.Client) *AccountRepository {
+	return &AccountRepository{client: client}
+}
+
+// GetByID returns the account
+func (r *AccountRepository) GetByID(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.AccountID,
+) (*entity.Account, error) {
+	row := conn(ctx, r.client).QueryRow(ctx,
+		`SELECT `+accountColumns+` FROM accounts WHERE ledger_id = $1 AND id = $2`,
+		ledgerID.String(), id.String(),
+	)
+
+	account, err := scanAccount(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("account %s: %w", id, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	return account, nil
+}
+
+// ListByLedger returns all accounts of the ledger
+func (r *AccountRepository) ListByLedger(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Account, error) {
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT `+accountColumns+` FROM accounts WHERE ledger_id = $1 ORDER BY name`,
+		ledgerID.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*entity.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// GetForUpdate returns the accounts in the given order, locking their rows until the
+// surrounding transaction ends. Rows are locked in ID order so concurrent callers cannot deadlock.
+func (r *AccountRepository) GetForUpdate(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	ids ...entity.AccountID,
+) ([]*entity.Account, error) {
+	idStrs := make([]string, len(ids))
+	for i, id := range ids {
+		idStrs[i] = id.String()
+	}
+
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT `+accountColumns+` FROM accounts
+		WHERE ledger_id = $1 AND id = ANY($2::UUID[])
+		ORDER BY id
+		FOR UPDATE`,
+		ledgerID.String(), idStrs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock accounts: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[string]*entity.Account, len(ids))
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		found[account.ID.String()] = account
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock accounts: %w", err)
+	}
+
+	accounts := make([]*entity.Account, len(ids))
+	for i, id := range ids {
+		account, ok := found[id.String()]
+		if !ok {
+			return nil, fmt.Errorf("account %s: %w", id, repository.ErrNotFound)
+		}
+		accounts[i] = account
+	}
+	return accounts, nil
+}
+
+// UpdateBalance stores the account's current balance
+func (r *AccountRepository) UpdateBalance(ctx context.Context, account *entity.Account) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`UPDATE accounts SET balance_amount = $1, updated_at = $2 WHERE ledger_id = $3 AND id = $4`,
+		account.Balance, account.UpdatedAt, account.LedgerID.String(), account.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update account balance: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("account %s: %w", account.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+func scanAccount(row pgx.Row) (*entity.Account, error) {
+	var (
+		idStr, ledgerIDStr, name, description string
+		accountType, currency, status         string
+		balanceUnits                          decimal.Decimal
+		createdAt, updatedAt                  time.Time
+	)
+
+	if err := row.Scan(
+		&idStr, &ledgerIDStr, &name, &description, &accountType, &currency,
+		&balanceUnits, &status, &createdAt, &updatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	id, err := entity.NewAccountIDFromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	ledgerID, err := ledgerEntity.NewLedgerIDFromString(ledgerIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := money.FromMinorUnitsDecimal(balanceUnits, money.Currency(currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid balance of account %s: %w", idStr, err)
+	}
+
+	return entity.ReconstructAccount(
+		id, ledgerID, name, description,
+		entity.AccountType(accountType), money.Currency(currency), balance,
+		entity.AccountStatus(status), createdAt, updatedAt,
+	), nil
+}
@@ -0,0 +1,296 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/attachment/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/attachment/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+// attachmentColumns selects an attachment joined with its content
+const attachmentColumns = `a.id::TEXT, a.ledger_id::TEXT, a.owner_type, a.owner_id::TEXT, a.file_name,
+	a.created_at, a.owner_deleted_at, c.sha256, c.size, c.content_type, c.created_at`
+
+// attachmentFrom joins attachments with their content
+const attachmentFrom = `attachments a
+	JOIN attachment_contents c ON c.ledger_id = a.ledger_id AND c.sha256 = a.sha256`
+
+// Compile-time check that AttachmentRepository satisfies the domain interface
+var _ repository.AttachmentRepository = (*AttachmentRepository)(nil)
+
+// AttachmentRepository implements repository.AttachmentRepository
+type AttachmentRepository struct {
+	client *pg.Client
+}
+
+// NewAttachmentRepository creates a new AttachmentRepository
+func NewAttachmentRepository(client *pg.Client) *AttachmentRepository {
+	return &AttachmentRepository{client: client}
+}
+
+// LockContent stores the content's metadata unless the ledger already has it, reviving it if it was
+// marked deleted, then locks its row until the surrounding transaction ends
+func (r *AttachmentRepository) LockContent(ctx context.Context, content *entity.Content) (bool, error) {
+	q := conn(ctx, r.client)
+
+	tag, err := q.Exec(ctx,
+		`INSERT INTO attachment_contents (ledger_id, sha256, size, content_type, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (ledger_id, sha256) DO UPDATE SET deleted_at = NULL
+		WHERE attachment_contents.deleted_at IS NOT NULL`,
+		content.LedgerID.String(), content.SHA256, content.Size, content.ContentType, content.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create attachment content: %w", err)
+	}
+
+	if err := q.QueryRow(ctx,
+		`SELECT size, content_type, created_at FROM attachment_contents
+		WHERE ledger_id = $1 AND sha256 = $2
+		FOR UPDATE`,
+		content.LedgerID.String(), content.SHA256,
+	).Scan(&content.Size, &content.ContentType, &content.CreatedAt); err != nil {
+		return false, fmt.Errorf("failed to lock attachment content: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Create stores a new attachment
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *entity.Attachment) error {
+	_, err := conn(ctx, r.client).Exec(ctx,
+		`INSERT INTO attachments (id, ledger_id, owner_type, owner_id, file_name, sha256, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		attachment.ID.String(), attachment.LedgerID.String(), attachment.Owner.Type.String(),
+		attachment.Owner.ID.String(), attachment.FileName, attachment.Content.SHA256, attachment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the attachment
+func (r *AttachmentRepository) Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.AttachmentID) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`DELETE FROM attachments WHERE ledger_id = $1 AND id = $2`,
+		ledgerID.String(), id.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("attachment %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// MarkContentDeletedIfUnused marks the content deleted unless attachments still share it
+func (r *AttachmentRepository) MarkContentDeletedIfUnused(ctx context.Context, content entity.Content) (bool, error) {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`UPDATE attachment_contents c SET deleted_at = NOW()
+		WHERE c.ledger_id = $1 AND c.sha256 = $2 AND c.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM attachments a WHERE a.ledger_id = c.ledger_id AND a.sha256 = c.sha256)`,
+		content.LedgerID.String(), content.SHA256,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark attachment content deleted: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListDeletedContents returns up to limit contents of any ledger that are marked deleted, oldest first.
+// Served by idx_attachment_contents_deleted_at.
+func (r *AttachmentRepository) ListDeletedContents(ctx context.Context, limit int) ([]entity.Content, error) {
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT ledger_id::TEXT, sha256, size, content_type, created_at FROM attachment_contents
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at, ledger_id, sha256
+		LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted attachment contents: %w", err)
+	}
+	defer rows.Close()
+
+	var contents []entity.Content
+	for rows.Next() {
+		var (
+			ledgerIDStr, sha256, contentType string
+			size                             int64
+			createdAt                        time.Time
+		)
+		if err := rows.Scan(&ledgerIDStr, &sha256, &size, &contentType, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment content: %w", err)
+		}
+
+		ledgerID, err := ledgerEntity.NewLedgerIDFromString(ledgerIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment content: %w", err)
+		}
+		contents = append(contents, entity.ReconstructContent(ledgerID, sha256, size, contentType, createdAt))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list deleted attachment contents: %w", err)
+	}
+	return contents, nil
+}
+
+// DeleteContent removes the metadata of content that is still marked deleted; the deleted row stays
+// locked until the surrounding transaction ends
+func (r *AttachmentRepository) DeleteContent(ctx context.Context, content entity.Content) (bool, error) {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`DELETE FROM attachment_contents WHERE ledger_id = $1 AND sha256 = $2 AND deleted_at IS NOT NULL`,
+		content.LedgerID.String(), content.SHA256,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete attachment content: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetByID returns the attachment
+func (r *AttachmentRepository) GetByID(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.AttachmentID,
+) (*entity.Attachment, error) {
+	attachment, err := scanAttachment(conn(ctx, r.client).QueryRow(ctx,
+		`SELECT `+attachmentColumns+` FROM `+attachmentFrom+` WHERE a.ledger_id = $1 AND a.id = $2`,
+		ledgerID.String(), id.String(),
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("attachment %s: %w", id, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// ListByOwner returns the attachments of an owner that has not been deleted, oldest first.
+// Served by idx_attachments_owner.
+func (r *AttachmentRepository) ListByOwner(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	owner entity.Owner,
+) ([]*entity.Attachment, error) {
+	return r.list(ctx,
+		`SELECT `+attachmentColumns+` FROM `+attachmentFrom+`
+		WHERE a.ledger_id = $1 AND a.owner_type = $2 AND a.owner_id = $3 AND a.owner_deleted_at IS NULL
+		ORDER BY a.created_at, a.id`,
+		ledgerID.String(), owner.Type.String(), owner.ID.String(),
+	)
+}
+
+// LockOwner checks if the ledger has the transaction or counterparty, holding its row with FOR KEY SHARE
+// so it cannot be deleted, and its attachments marked, before the attachment is created
+func (r *AttachmentRepository) LockOwner(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	owner entity.Owner,
+) (bool, error) {
+	var query string
+	switch owner.Type {
+	case entity.OwnerTypeTransaction:
+		query = `SELECT 1 FROM transactions WHERE ledger_id = $1 AND id = $2 FOR KEY SHARE`
+	case entity.OwnerTypeCounterparty:
+		query = `SELECT 1 FROM counterparties WHERE ledger_id = $1 AND id = $2 FOR KEY SHARE`
+	default:
+		return false, fmt.Errorf("invalid attachment owner type: %s", owner.Type)
+	}
+
+	var found int
+	err := conn(ctx, r.client).QueryRow(ctx, query, ledgerID.String(), owner.ID.String()).Scan(&found)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to lock attachment owner: %w", err)
+	}
+	return true, nil
+}
+
+// ListOwnerDeletedBefore returns up to limit attachments of any ledger whose owner was deleted before the cutoff.
+// Served by idx_attachments_owner_deleted_at.
+func (r *AttachmentRepository) ListOwnerDeletedBefore(
+	ctx context.Context,
+	cutoff time.Time,
+	limit int,
+) ([]*entity.Attachment, error) {
+	return r.list(ctx,
+		`SELECT `+attachmentColumns+` FROM `+attachmentFrom+`
+		WHERE a.owner_deleted_at < $1
+		ORDER BY a.owner_deleted_at, a.id
+		LIMIT $2`,
+		cutoff, limit,
+	)
+}
+
+func (r *AttachmentRepository) list(ctx context.Context, query string, args ...any) ([]*entity.Attachment, error) {
+	rows, err := conn(ctx, r.client).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []*entity.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// scanAttachment scans a row selected with attachmentColumns
+func scanAttachment(row pgx.Row) (*entity.Attachment, error) {
+	var (
+		idStr, ledgerIDStr, ownerType, ownerIDStr, fileName string
+		sha256, contentType                                 string
+		size                                                int64
+		createdAt, contentCreatedAt                         time.Time
+		ownerDeletedAt                                      *time.Time
+	)
+	if err := row.Scan(
+		&idStr, &ledgerIDStr, &ownerType, &ownerIDStr, &fileName,
+		&createdAt, &ownerDeletedAt, &sha256, &size, &contentType, &contentCreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	id, err := entity.NewAttachmentIDFromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	ledgerID, err := ledgerEntity.NewLedgerIDFromString(ledgerIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := entity.NewOwner(ownerType, ownerIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	content := entity.ReconstructContent(ledgerID, sha256, size, contentType, contentCreatedAt)
+
+	return entity.ReconstructAttachment(
+		id, owner, fileName, content, createdAt, optional.FromPtr(ownerDeletedAt),
+	), nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// Compile-time check that CommodityRepository satisfies the domain interface
+var _ repository.CommodityRepository = (*CommodityRepository)(nil)
+
+// CommodityRepository implements repository.CommodityRepository
+type CommodityRepository struct {
+	client *pg.Client
+}
+
+// NewCommodityRepository creates a new CommodityRepository
+func NewCommodityRepository(client *pg.Client) *CommodityRepository {
+	return &CommodityRepository{client: client}
+}
+
+// Save stores the commodity, replacing its kind, symbol and name if the code already exists.
+// The precision of a stored commodity is never changed, since amounts are kept in its minor units.
+func (r *CommodityRepository) Save(ctx context.Context, info money.CurrencyInfo) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`INSERT INTO commodities (code, kind, minor_units, symbol, name)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		ON CONFLICT (code) DO UPDATE SET kind = EXCLUDED.kind, symbol = EXCLUDED.symbol, name = EXCLUDED.name
+		WHERE commodities.minor_units = EXCLUDED.minor_units`,
+		info.Code.String(), info.Kind.String(), info.MinorUnits, info.Symbol, info.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save commodity: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("commodity %s is stored with a different precision", info.Code)
+	}
+	return nil
+}
+
+// List returns all stored commodities
+func (r *CommodityRepository) List(ctx context.Context) ([]money.CurrencyInfo, error) {
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT code, kind, minor_units, COALESCE(symbol, ''), name FROM commodities ORDER BY code`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commodities: %w", err)
+	}
+	defer rows.Close()
+
+	var commodities []money.CurrencyInfo
+	for rows.Next() {
+		var (
+			code, kind, symbol, name string
+			minorUnits               int32
+		)
+		if err := rows.Scan(&code, &kind, &minorUnits, &symbol, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan commodity: %w", err)
+		}
+
+		commodityKind, err := money.NewCommodityKind(kind)
+		if err != nil {
+			return nil, err
+		}
+
+		commodities = append(commodities, money.CurrencyInfo{
+			Code:       money.Currency(code),
+			Kind:       commodityKind,
+			MinorUnits: minorUnits,
+			Symbol:     symbol,
+			Name:       name,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list commodities: %w", err)
+	}
+	return commodities, nil
+}
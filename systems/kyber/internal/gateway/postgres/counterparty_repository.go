@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+// Compile-time check that CounterpartyRepository satisfies the domain interface
+var _ repository.CounterpartyRepository = (*CounterpartyRepository)(nil)
+
+// CounterpartyRepository implements the counterparty repository.CounterpartyRepository
+type CounterpartyRepository struct {
+	client *pg.Client
+}
+
+// NewCounterpartyRepository creates a new CounterpartyRepository
+func NewCounterpartyRepository(client *pg.Client) *CounterpartyRepository {
+	return &CounterpartyRepository{client: client}
+}
+
+// ListByLedger returns all counterparties of the ledger
+func (r *CounterpartyRepository) ListByLedger(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Counterparty, error) {
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT id::TEXT, name, type, COALESCE(description, ''), status, created_at, updated_at
+		FROM counterparties WHERE ledger_id = $1
+		ORDER BY name`,
+		ledgerID.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list counterparties: %w", err)
+	}
+	defer rows.Close()
+
+	var counterparties []*entity.Counterparty
+	for rows.Next() {
+		var (
+			idStr, name, typeStr, description, status string
+			createdAt, updatedAt                      time.Time
+		)
+		if err := rows.Scan(&idStr, &name, &typeStr, &description, &status, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan counterparty: %w", err)
+		}
+
+		id, err := entity.NewCounterpartyIDFromString(idStr)
+		if err != nil {
+			return nil, err
+		}
+
+		counterpartyType, err := entity.NewCounterpartyType(typeStr)
+		if err != nil {
+			return nil, err
+		}
+
+		counterparties = append(counterparties, entity.ReconstructCounterparty(
+			id, ledgerID, name, counterpartyType, description, "",
+			entity.CounterpartyStatus(status), createdAt, updatedAt,
+		))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list counterparties: %w", err)
+	}
+	return counterparties, nil
+}
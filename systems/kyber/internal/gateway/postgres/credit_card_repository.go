@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// creditCardColumns selects a credit card aliased c joined to its account aliased a (for the currency)
+const creditCardColumns = `c.account_id::TEXT, c.closing_day, c.payment_due_days, c.minimum_payment_percent::TEXT,
+	c.minimum_payment_floor, c.credit_limit, a.currency, c.created_at, c.updated_at`
+
+// Compile-time check that CreditCardRepository satisfies the domain interface
+var _ repository.CreditCardRepository = (*CreditCardRepository)(nil)
+
+// CreditCardRepository implements repository.CreditCardRepository
+type CreditCardRepository struct {
+	client *pg.Client
+}
+
+// NewCreditCardRepository creates a new CreditCardRepository
+func NewCreditCardRepository(client *pg.Client) *CreditCardRepository {
+	return &CreditCardRepository{client: client}
+}
+
+// Create stores the billing terms of a credit card account
+func (r *CreditCardRepository) Create(ctx context.Context, card *entity.CreditCard) error {
+	if _, err := conn(ctx, r.client).Exec(ctx,
+		`INSERT INTO credit_cards (
+			account_id, ledger_id, closing_day, payment_due_days, minimum_payment_percent,
+			minimum_payment_floor, credit_limit, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		card.AccountID.String(), card.LedgerID.String(), card.ClosingDay, card.PaymentDueDays,
+		card.MinimumPayment.Percent.Points().String(), card.MinimumPayment.Floor, card.CreditLimit,
+		card.CreatedAt, card.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert credit card: %w", err)
+	}
+	return nil
+}
+
+// Update stores the card's closing day, payment due days, minimum payment and credit limit
+func (r *CreditCardRepository) Update(ctx context.Context, card *entity.CreditCard) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`UPDATE credit_cards SET
+			closing_day = $1, payment_due_days = $2, minimum_payment_percent = $3,
+			minimum_payment_floor = $4, credit_limit = $5, updated_at = $6
+		WHERE ledger_id = $7 AND account_id = $8`,
+		card.ClosingDay, card.PaymentDueDays, card.MinimumPayment.Percent.Points().String(),
+		card.MinimumPayment.Floor, card.CreditLimit, card.UpdatedAt,
+		card.LedgerID.String(), card.AccountID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update credit card: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("credit card %s: %w", card.AccountID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// GetByAccount returns the billing terms of the credit card account
+func (r *CreditCardRepository) GetByAccount(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (*entity.CreditCard, error) {
+	card, err := scanCreditCard(conn(ctx, r.client).QueryRow(ctx,
+		`SELECT `+creditCardColumns+`
+		FROM credit_cards c
+		JOIN accounts a ON a.id = c.account_id
+		WHERE c.ledger_id = $1 AND c.account_id = $2`,
+		ledgerID.String(), accountID.String(),
+	), ledgerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("credit card %s: %w", accountID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit card: %w", err)
+	}
+	return card, nil
+}
+
+// ListByLedger returns the billing terms of all credit card accounts of the ledger
+func (r *CreditCardRepository) ListByLedger(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.CreditCard, error) {
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT `+creditCardColumns+`
+		FROM credit_cards c
+		JOIN accounts a ON a.id = c.account_id
+		WHERE c.ledger_id = $1
+		ORDER BY a.name`,
+		ledgerID.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit cards: %w", err)
+	}
+	defer rows.Close()
+
+	var cards []*entity.CreditCard
+	for rows.Next() {
+		card, err := scanCreditCard(rows, ledgerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan credit card: %w", err)
+		}
+		cards = append(cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list credit cards: %w", err)
+	}
+	return cards, nil
+}
+
+// scanCreditCard scans a row selected with creditCardColumns
+func scanCreditCard(row pgx.Row, ledgerID ledgerEntity.LedgerID) (*entity.CreditCard, error) {
+	var (
+		accountIDStr, percentStr, currency string
+		closingDay, paymentDueDays         int
+		floorUnits, limitUnits             decimal.Decimal
+		createdAt, updatedAt               time.Time
+	)
+	if err := row.Scan(
+		&accountIDStr, &closingDay, &paymentDueDays, &percentStr,
+		&floorUnits, &limitUnits, &currency, &createdAt, &updatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	accountID, err := entity.NewAccountIDFromString(accountIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	percent, err := money.NewPercent(percentStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid minimum payment percent of credit card %s: %w", accountID, err)
+	}
+
+	floor, err := money.FromMinorUnitsDecimal(floorUnits, money.Currency(currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid minimum payment floor of credit card %s: %w", accountID, err)
+	}
+
+	creditLimit, err := money.FromMinorUnitsDecimal(limitUnits, money.Currency(currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid credit limit of credit card %s: %w", accountID, err)
+	}
+
+	minimum := entity.MinimumPayment{Percent: percent, Floor: floor}
+	return entity.ReconstructCreditCard(accountID, ledgerID, closingDay, paymentDueDays, minimum, creditLimit,
+		createdAt, updatedAt), nil
+}
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+)
+
+// csvProfileColumns selects a CSV import profile
+const csvProfileColumns = `id::TEXT, account_id::TEXT, name, delimiter, skip_rows, date_format, decimal_separator,
+	COALESCE(thousands_separator, ''), sign_convention, date_column, amount_column, debit_column, credit_column,
+	payee_column, memo_column, reference_column, created_at, updated_at`
+
+// Compile-time check that CSVProfileRepository satisfies the domain interface
+var _ repository.CSVProfileRepository = (*CSVProfileRepository)(nil)
+
+// CSVProfileRepository implements repository.CSVProfileRepository
+type CSVProfileRepository struct {
+	client *pg.Client
+}
+
+// NewCSVProfileRepository creates a new CSVProfileRepository
+func NewCSVProfileRepository(client *pg.Client) *CSVProfileRepository {
+	return &CSVProfileRepository{client: client}
+}
+
+// Create stores a new CSV profile
+func (r *CSVProfileRepository) Create(ctx context.Context, profile *entity.CSVProfile) error {
+	f, c := profile.Format, profile.Format.Columns
+
+	if _, err := conn(ctx, r.client).Exec(ctx,
+		`INSERT INTO csv_import_profiles (
+			id, ledger_id, account_id, name, delimiter, skip_rows, date_format, decimal_separator,
+			thousands_separator, sign_convention, date_column, amount_column, debit_column, credit_column,
+			payee_column, memo_column, reference_column, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)`,
+		profile.ID.String(), profile.LedgerID.String(), profile.AccountID.String(), profile.Name,
+		string(f.Delimiter), f.SkipRows, f.DateFormat, string(f.DecimalSeparator), runeValue(f.ThousandsSeparator),
+		f.SignConvention.String(), c.Date, c.Amount.Ptr(), c.Debit.Ptr(), c.Credit.Ptr(),
+		c.Payee.Ptr(), c.Memo.Ptr(), c.Reference.Ptr(), profile.CreatedAt, profile.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert CSV profile: %w", err)
+	}
+	return nil
+}
+
+// Update stores the CSV profile's name and format
+func (r *CSVProfileRepository) Update(ctx context.Context, profile *entity.CSVProfile) error {
+	f, c := profile.Format, profile.Format.Columns
+
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`UPDATE csv_import_profiles SET
+			name = $1, delimiter = $2, skip_rows = $3, date_format = $4, decimal_separator = $5,
+			thousands_separator = NULLIF($6, ''), sign_convention = $7, date_column = $8, amount_column = $9,
+			debit_column = $10, credit_column = $11, payee_column = $12, memo_column = $13,
+			reference_column = $14, updated_at = $15
+		WHERE ledger_id = $16 AND id = $17`,
+		profile.Name, string(f.Delimiter), f.SkipRows, f.DateFormat, string(f.DecimalSeparator),
+		runeValue(f.ThousandsSeparator), f.SignConvention.String(), c.Date, c.Amount.Ptr(),
+		c.Debit.Ptr(), c.Credit.Ptr(), c.Payee.Ptr(), c.Memo.Ptr(),
+		c.Reference.Ptr(), profile.UpdatedAt, profile.LedgerID.String(), profile.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update CSV profile: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("CSV profile %s: %w", profile.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// Delete removes the CSV profile
+func (r *CSVProfileRepository) Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.CSVProfileID) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`DELETE FROM csv_import_profiles WHERE ledger_id = $1 AND id = $2`,
+		ledgerID.String(), id.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete CSV profile: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("CSV profile %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// GetByID returns the CSV profile
+func (r *CSVProfileRepository) GetByID(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.CSVProfileID,
+) (*entity.CSVProfile, error) {
+	profile, err := scanCSVProfile(conn(ctx, r.client).QueryRow(ctx,
+		`SELECT `+csvProfileColumns+` FROM csv_import_profiles WHERE ledger_id = $1 AND id = $2`,
+		ledgerID.String(), id.String(),
+	), ledgerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("CSV profile %s: %w", id, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CSV profile: %w", err)
+	}
+	return profile, nil
+}
+
+// ListByAccount returns the account's CSV profiles ordered by name
+func (r *CSVProfileRepository) ListByAccount(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) ([]*entity.CSVProfile, error) {
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT `+csvProfileColumns+`
+		FROM csv_import_profiles
+		WHERE ledger_id = $1 AND account_id = $2
+		ORDER BY name`,
+		ledgerID.String(), accountID.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CSV profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []*entity.CSVProfile
+	for rows.Next() {
+		profile, err := scanCSVProfile(rows, ledgerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan CSV profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list CSV profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// scanCSVProfile scans a row selected with csvProfileColumns
+func scanCSVProfile(row pgx.Row, ledgerID ledgerEntity.LedgerID) (*entity.CSVProfile, error) {
+	var (
+		idStr, accountIDStr, name, delimiter, dateFormat string
+		decimalSeparator, thousandsSeparator, signStr    string
+		skipRows, dateColumn                             int
+		amount, debit, credit, payee, memo, reference    *int
+		createdAt, updatedAt                             time.Time
+	)
+	if err := row.Scan(
+		&idStr, &accountIDStr, &name, &delimiter, &skipRows, &dateFormat, &decimalSeparator,
+		&thousandsSeparator, &signStr, &dateColumn, &amount, &debit, &credit,
+		&payee, &memo, &reference, &createdAt, &updatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	id, err := entity.NewCSVProfileIDFromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	accountID, err := entity.NewAccountIDFromString(accountIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	signConvention, err := entity.NewCSVSignConvention(signStr)
+	if err != nil {
+		return nil, err
+	}
+
+	format := entity.CSVFormat{
+		Delimiter:          firstRune(delimiter),
+		SkipRows:           skipRows,
+		DateFormat:         dateFormat,
+		DecimalSeparator:   firstRune(decimalSeparator),
+		ThousandsSeparator: firstRune(thousandsSeparator),
+		SignConvention:     signConvention,
+		Columns: entity.CSVColumns{
+			Date:      dateColumn,
+			Amount:    optional.FromPtr(amount),
+			Debit:     optional.FromPtr(debit),
+			Credit:    optional.FromPtr(credit),
+			Payee:     optional.FromPtr(payee),
+			Memo:      optional.FromPtr(memo),
+			Reference: optional.FromPtr(reference),
+		},
+	}
+
+	return entity.ReconstructCSVProfile(id, ledgerID, accountID, name, format, createdAt, updatedAt), nil
+}
+
+// runeValue returns the rune as a string, or an empty string for 0
+func runeValue(r rune) string {
+	if r == 0 {
+		return ""
+	}
+	return string(r)
+}
+
+// firstRune returns the first rune of s, or 0 if s is empty
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return 0
+	}
+	return r
+}
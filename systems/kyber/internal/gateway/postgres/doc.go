@@ -0,0 +1,2 @@
+// Package postgres implements domain repositories on PostgreSQL.
+package postgres
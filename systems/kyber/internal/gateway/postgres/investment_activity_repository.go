@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// Compile-time check that InvestmentActivityRepository satisfies the domain interface
+var _ repository.InvestmentActivityRepository = (*InvestmentActivityRepository)(nil)
+
+// InvestmentActivityRepository implements repository.InvestmentActivityRepository.
+// Quantities are stored in minor units of the security, so securities must be registered
+// with the money package before their activities are loaded.
+type InvestmentActivityRepository struct {
+	client *pg.Client
+}
+
+// NewInvestmentActivityRepository creates a new InvestmentActivityRepository
+func NewInvestmentActivityRepository(client *pg.Client) *InvestmentActivityRepository {
+	return &InvestmentActivityRepository{client: client}
+}
+
+// Create stores a new investment activity
+func (r *InvestmentActivityRepository) Create(ctx context.Context, activity *entity.InvestmentActivity) error {
+	var quantity decimal.Decimal
+	if activity.Security != "" {
+		units, err := activity.Quantity.ToMinorUnitsDecimal()
+		if err != nil {
+			return fmt.Errorf("invalid quantity of investment activity %s: %w", activity.ID, err)
+		}
+		quantity = units
+	}
+
+	var splitRatio, transactionID any
+	if activity.Type == entity.InvestmentActivityTypeSplit {
+		splitRatio = activity.SplitRatio.String()
+	}
+	if activity.TransactionID.IsSome() {
+		transactionID = activity.TransactionID.Unwrap().String()
+	}
+
+	if _, err := conn(ctx, r.client).Exec(ctx,
+		`INSERT INTO investment_activities (
+			id, ledger_id, account_id, activity_type, security, quantity, amount, fee,
+			split_ratio, transaction_id, activity_date, created_at
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12)`,
+		activity.ID.String(), activity.LedgerID.String(), activity.AccountID.String(), activity.Type.String(),
+		activity.Security.String(), quantity, activity.Amount, activity.Fee,
+		splitRatio, transactionID, activity.Date, activity.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert investment activity: %w", err)
+	}
+	return nil
+}
+
+// ListByAccount returns the activities of the investment account, oldest first
+func (r *InvestmentActivityRepository) ListByAccount(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) ([]*entity.InvestmentActivity, error) {
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT i.id::TEXT, i.activity_type, COALESCE(i.security, ''), i.quantity, i.amount, i.fee,
+			i.split_ratio::TEXT, i.transaction_id::TEXT, a.currency, i.activity_date, i.created_at
+		FROM investment_activities i
+		JOIN accounts a ON a.id = i.account_id
+		WHERE i.ledger_id = $1 AND i.account_id = $2
+		ORDER BY i.activity_date, i.created_at`,
+		ledgerID.String(), accountID.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list investment activities: %w", err)
+	}
+	defer rows.Close()
+
+	var activities []*entity.InvestmentActivity
+	for rows.Next() {
+		activity, err := scanInvestmentActivity(rows, ledgerID, accountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan investment activity: %w", err)
+		}
+		activities = append(activities, activity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list investment activities: %w", err)
+	}
+	return activities, nil
+}
+
+func scanInvestmentActivity(
+	row pgx.Row,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (*entity.InvestmentActivity, error) {
+	var (
+		idStr, typeStr, security, currency   string
+		splitRatioStr, transactionIDStr      *string
+		quantityUnits, amountUnits, feeUnits decimal.Decimal
+		date, createdAt                      time.Time
+	)
+
+	if err := row.Scan(
+		&idStr, &typeStr, &security, &quantityUnits, &amountUnits, &feeUnits, &splitRatioStr,
+		&transactionIDStr, &currency, &date, &createdAt,
+	); err != nil {
+		return nil, err
+	}
+
+	id, err := entity.NewInvestmentActivityIDFromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	activityType, err := entity.NewInvestmentActivityType(typeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var quantity money.Money
+	if security != "" {
+		if quantity, err = money.FromMinorUnitsDecimal(quantityUnits, money.Currency(security)); err != nil {
+			return nil, fmt.Errorf("invalid quantity of investment activity %s: %w", idStr, err)
+		}
+	}
+
+	amount, err := money.FromMinorUnitsDecimal(amountUnits, money.Currency(currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount of investment activity %s: %w", idStr, err)
+	}
+
+	fee, err := money.FromMinorUnitsDecimal(feeUnits, money.Currency(currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee of investment activity %s: %w", idStr, err)
+	}
+
+	splitRatio := decimal.Zero
+	if splitRatioStr != nil {
+		if splitRatio, err = decimal.NewFromString(*splitRatioStr); err != nil {
+			return nil, fmt.Errorf("invalid split ratio of investment activity %s: %w", idStr, err)
+		}
+	}
+
+	transactionID := optional.None[entity.TransactionID]()
+	if transactionIDStr != nil {
+		txID, err := entity.NewTransactionIDFromString(*transactionIDStr)
+		if err != nil {
+			return nil, err
+		}
+		transactionID = optional.Some(txID)
+	}
+
+	return entity.ReconstructInvestmentActivity(
+		id, ledgerID, accountID, activityType, money.Currency(security),
+		quantity, amount, fee, splitRatio, transactionID, date, createdAt,
+	), nil
+}
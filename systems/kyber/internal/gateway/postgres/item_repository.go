@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// Compile-time check that ItemRepository satisfies the domain interface
+var _ repository.ItemRepository = (*ItemRepository)(nil)
+
+// ItemRepository implements the budget repository.ItemRepository
+type ItemRepository struct {
+	client *pg.Client
+}
+
+// NewItemRepository creates a new ItemRepository
+func NewItemRepository(client *pg.Client) *ItemRepository {
+	return &ItemRepository{client: client}
+}
+
+// GetByID returns the item with its monthly budget tracking
+func (r *ItemRepository) GetByID(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.ItemID) (*entity.Item, error) {
+	q := conn(ctx, r.client)
+
+	var (
+		name, description, itemType, currency string
+		isActive                              bool
+		createdAt, updatedAt                  time.Time
+	)
+	err := q.QueryRow(ctx,
+		`SELECT name, COALESCE(description, ''), type, currency, is_active, created_at, updated_at
+		FROM budget_items WHERE ledger_id = $1 AND id = $2`,
+		ledgerID.String(), id.String(),
+	).Scan(&name, &description, &itemType, &currency, &isActive, &createdAt, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("budget item %s: %w", id, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget item: %w", err)
+	}
+
+	rows, err := q.Query(ctx,
+		`SELECT year, month, target_amount, budgeted_amount, actual_amount, updated_at
+		FROM budget_tracking WHERE item_id = $1`,
+		id.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget tracking: %w", err)
+	}
+	defer rows.Close()
+
+	monthlyBudgets := make(map[string]*entity.BudgetTracking)
+	for rows.Next() {
+		var (
+			year, month              int
+			target, budgeted, actual decimal.Decimal
+			trackingUpdatedAt        time.Time
+		)
+		if err := rows.Scan(&year, &month, &target, &budgeted, &actual, &trackingUpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan budget tracking: %w", err)
+		}
+
+		amounts := make([]money.Money, 3)
+		for i, units := range []decimal.Decimal{target, budgeted, actual} {
+			amounts[i], err = money.FromMinorUnitsDecimal(units, money.Currency(currency))
+			if err != nil {
+				return nil, fmt.Errorf("invalid budget tracking amount for %04d-%02d: %w", year, month, err)
+			}
+		}
+
+		monthlyBudgets[fmt.Sprintf("%04d-%02d", year, month)] = entity.ReconstructBudgetTracking(
+			year, month, amounts[0], amounts[1], amounts[2], trackingUpdatedAt,
+		)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get budget tracking: %w", err)
+	}
+
+	return entity.ReconstructItem(
+		id, ledgerID, name, description, entity.ItemType(itemType), money.Currency(currency),
+		monthlyBudgets, isActive, createdAt, updatedAt,
+	), nil
+}
+
+// AddActualAmount adds the amount to the item's actuals of the month in place, creating the month's
+// budget tracking if it has none yet
+func (r *ItemRepository) AddActualAmount(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ItemID,
+	year, month int,
+	amount money.Money,
+) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`INSERT INTO budget_tracking (item_id, year, month, target_amount, budgeted_amount, actual_amount, updated_at)
+		SELECT id, $3, $4, 0, 0, $5, NOW() FROM budget_items WHERE ledger_id = $1 AND id = $2 AND currency = $6
+		ON CONFLICT (item_id, year, month)
+		DO UPDATE SET actual_amount = budget_tracking.actual_amount + EXCLUDED.actual_amount, updated_at = EXCLUDED.updated_at`,
+		ledgerID.String(), id.String(), year, month, amount, string(amount.Currency),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update budget tracking for %04d-%02d: %w", year, month, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("budget item %s in %s: %w", id, amount.Currency, repository.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// journalEntryColumns selects a journal entry aliased e
+const journalEntryColumns = `e.id::TEXT, e.ledger_id::TEXT, e.source_transaction_id::TEXT, e.description,
+	e.entry_date, e.created_at, e.updated_at`
+
+// Compile-time check that JournalEntryRepository satisfies the domain interface
+var _ repository.JournalEntryRepository = (*JournalEntryRepository)(nil)
+
+// JournalEntryRepository implements repository.JournalEntryRepository.
+// Postings are stored in journal_postings numbered in entry order.
+type JournalEntryRepository struct {
+	client *pg.Client
+}
+
+// NewJournalEntryRepository creates a new JournalEntryRepository
+func NewJournalEntryRepository(client *pg.Client) *JournalEntryRepository {
+	return &JournalEntryRepository{client: client}
+}
+
+// Create stores a new journal entry and all of its postings
+func (r *JournalEntryRepository) Create(ctx context.Context, entry *entity.JournalEntry) error {
+	q := conn(ctx, r.client)
+
+	if _, err := q.Exec(ctx,
+		`INSERT INTO journal_entries (id, ledger_id, source_transaction_id, description, entry_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		entry.ID.String(), entry.LedgerID.String(), sourceTransactionValue(entry), entry.Description,
+		entry.EntryDate, entry.CreatedAt, entry.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert journal entry: %w", err)
+	}
+
+	return insertPostings(ctx, q, entry)
+}
+
+// Update replaces the journal entry and its postings
+func (r *JournalEntryRepository) Update(ctx context.Context, entry *entity.JournalEntry) error {
+	q := conn(ctx, r.client)
+
+	tag, err := q.Exec(ctx,
+		`UPDATE journal_entries SET description = $1, entry_date = $2, updated_at = $3
+		WHERE ledger_id = $4 AND id = $5`,
+		entry.Description, entry.EntryDate, entry.UpdatedAt, entry.LedgerID.String(), entry.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update journal entry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("journal entry %s: %w", entry.ID, repository.ErrNotFound)
+	}
+
+	if _, err := q.Exec(ctx, `DELETE FROM journal_postings WHERE journal_entry_id = $1`, entry.ID.String()); err != nil {
+		return fmt.Errorf("failed to delete journal postings: %w", err)
+	}
+
+	return insertPostings(ctx, q, entry)
+}
+
+// Delete removes the journal entry; its postings are removed by the journal_entry_id cascade
+func (r *JournalEntryRepository) Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.JournalEntryID) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`DELETE FROM journal_entries WHERE ledger_id = $1 AND id = $2`,
+		ledgerID.String(), id.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete journal entry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("journal entry %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// GetByID returns the journal entry with its postings
+func (r *JournalEntryRepository) GetByID(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.JournalEntryID,
+) (*entity.JournalEntry, error) {
+	return r.getOne(ctx,
+		`SELECT `+journalEntryColumns+` FROM journal_entries e WHERE e.ledger_id = $1 AND e.id = $2`,
+		fmt.Sprintf("journal entry %s", id), ledgerID.String(), id.String(),
+	)
+}
+
+// GetBySourceTransaction returns the journal entry recorded from the transaction with its postings.
+// Served by idx_journal_entries_source_transaction.
+func (r *JournalEntryRepository) GetBySourceTransaction(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	transactionID entity.TransactionID,
+) (*entity.JournalEntry, error) {
+	return r.getOne(ctx,
+		`SELECT `+journalEntryColumns+` FROM journal_entries e WHERE e.ledger_id = $1 AND e.source_transaction_id = $2`,
+		fmt.Sprintf("journal entry of transaction %s", transactionID), ledgerID.String(), transactionID.String(),
+	)
+}
+
+// ListByAccount returns entries with a posting against the account dated within [from, to)
+func (r *JournalEntryRepository) ListByAccount(
+	ctx context.Context,
+	accountID entity.AccountID,
+	from, to time.Time,
+) ([]*entity.JournalEntry, error) {
+	return r.list(ctx,
+		`SELECT `+journalEntryColumns+`
+		FROM journal_entries e
+		WHERE EXISTS (SELECT 1 FROM journal_postings p WHERE p.journal_entry_id = e.id AND p.account_id = $1)
+			AND e.entry_date >= $2 AND e.entry_date < $3
+		ORDER BY e.entry_date, e.id`,
+		accountID.String(), from, to,
+	)
+}
+
+// ListByItem returns entries with a posting against the budget item dated within [from, to)
+func (r *JournalEntryRepository) ListByItem(
+	ctx context.Context,
+	itemID budgetEntity.ItemID,
+	from, to time.Time,
+) ([]*entity.JournalEntry, error) {
+	return r.list(ctx,
+		`SELECT `+journalEntryColumns+`
+		FROM journal_entries e
+		WHERE EXISTS (SELECT 1 FROM journal_postings p WHERE p.journal_entry_id = e.id AND p.item_id = $1)
+			AND e.entry_date >= $2 AND e.entry_date < $3
+		ORDER BY e.entry_date, e.id`,
+		itemID.String(), from, to,
+	)
+}
+
+func (r *JournalEntryRepository) getOne(ctx context.Context, sql, what string, args ...any) (*entity.JournalEntry, error) {
+	q := conn(ctx, r.client)
+
+	entry, err := scanJournalEntry(q.QueryRow(ctx, sql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", what, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal entry: %w", err)
+	}
+
+	if err := loadPostings(ctx, q, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r *JournalEntryRepository) list(ctx context.Context, sql string, args ...any) ([]*entity.JournalEntry, error) {
+	q := conn(ctx, r.client)
+
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*entity.JournalEntry
+	for rows.Next() {
+		entry, err := scanJournalEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list journal entries: %w", err)
+	}
+
+	if err := loadPostings(ctx, q, entries...); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func insertPostings(ctx context.Context, q querier, entry *entity.JournalEntry) error {
+	for i, p := range entry.Postings {
+		var accountID, itemID any
+		if p.AccountID.IsSome() {
+			accountID = p.AccountID.Unwrap().String()
+		}
+		if p.ItemID.IsSome() {
+			itemID = p.ItemID.Unwrap().String()
+		}
+
+		if _, err := q.Exec(ctx,
+			`INSERT INTO journal_postings (journal_entry_id, line_number, account_id, item_id, side, amount, currency, memo)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))`,
+			entry.ID.String(), i, accountID, itemID, p.Side.String(), p.Amount, p.Amount.Currency.String(), p.Memo,
+		); err != nil {
+			return fmt.Errorf("failed to insert journal posting %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// scanJournalEntry scans a row selected with journalEntryColumns; postings are attached by loadPostings
+func scanJournalEntry(row pgx.Row) (*entity.JournalEntry, error) {
+	var (
+		idStr, ledgerIDStr, description string
+		sourceTransactionIDStr          *string
+		entryDate, createdAt, updatedAt time.Time
+	)
+	if err := row.Scan(&idStr, &ledgerIDStr, &sourceTransactionIDStr, &description, &entryDate, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	id, err := entity.NewJournalEntryIDFromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	ledgerID, err := ledgerEntity.NewLedgerIDFromString(ledgerIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceTransactionID := optional.None[entity.TransactionID]()
+	if sourceTransactionIDStr != nil {
+		txID, err := entity.NewTransactionIDFromString(*sourceTransactionIDStr)
+		if err != nil {
+			return nil, err
+		}
+		sourceTransactionID = optional.Some(txID)
+	}
+
+	return entity.ReconstructJournalEntry(
+		id, ledgerID, sourceTransactionID, nil, description, entryDate, createdAt, updatedAt,
+	), nil
+}
+
+// loadPostings attaches the postings of the given journal entries
+func loadPostings(ctx context.Context, q querier, entries ...*entity.JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*entity.JournalEntry, len(entries))
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		byID[e.ID.String()] = e
+		ids = append(ids, e.ID.String())
+	}
+
+	rows, err := q.Query(ctx,
+		`SELECT journal_entry_id::TEXT, account_id::TEXT, item_id::TEXT, side, amount, currency, COALESCE(memo, '')
+		FROM journal_postings
+		WHERE journal_entry_id = ANY($1::UUID[])
+		ORDER BY journal_entry_id, line_number`,
+		ids,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get journal postings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entryIDStr, sideStr, currency, memo string
+			accountIDStr, itemIDStr             *string
+			amountUnits                         decimal.Decimal
+		)
+		if err := rows.Scan(&entryIDStr, &accountIDStr, &itemIDStr, &sideStr, &amountUnits, &currency, &memo); err != nil {
+			return fmt.Errorf("failed to scan journal posting: %w", err)
+		}
+
+		posting, err := reconstructPosting(accountIDStr, itemIDStr, sideStr, amountUnits, currency)
+		if err != nil {
+			return fmt.Errorf("invalid posting of journal entry %s: %w", entryIDStr, err)
+		}
+
+		entry := byID[entryIDStr]
+		entry.Postings = append(entry.Postings, posting.WithMemo(memo))
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get journal postings: %w", err)
+	}
+	return nil
+}
+
+func reconstructPosting(accountIDStr, itemIDStr *string, sideStr string, amountUnits decimal.Decimal, currency string) (entity.Posting, error) {
+	side, err := entity.NewPostingSide(sideStr)
+	if err != nil {
+		return entity.Posting{}, err
+	}
+
+	amount, err := money.FromMinorUnitsDecimal(amountUnits, money.Currency(currency))
+	if err != nil {
+		return entity.Posting{}, err
+	}
+
+	if accountIDStr != nil {
+		accountID, err := entity.NewAccountIDFromString(*accountIDStr)
+		if err != nil {
+			return entity.Posting{}, err
+		}
+		return entity.NewAccountPosting(accountID, side, amount)
+	}
+
+	if itemIDStr == nil {
+		return entity.Posting{}, fmt.Errorf("posting has neither an account nor a budget item")
+	}
+	itemID, err := budgetEntity.NewItemIDFromString(*itemIDStr)
+	if err != nil {
+		return entity.Posting{}, err
+	}
+	return entity.NewItemPosting(itemID, side, amount)
+}
+
+func sourceTransactionValue(entry *entity.JournalEntry) any {
+	if entry.SourceTransactionID.IsSome() {
+		return entry.SourceTransactionID.Unwrap().String()
+	}
+	return nil
+}
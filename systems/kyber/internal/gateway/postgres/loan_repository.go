@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// Compile-time check that LoanRepository satisfies the domain interface
+var _ repository.LoanRepository = (*LoanRepository)(nil)
+
+// LoanRepository implements repository.LoanRepository
+type LoanRepository struct {
+	client *pg.Client
+}
+
+// NewLoanRepository creates a new LoanRepository
+func NewLoanRepository(client *pg.Client) *LoanRepository {
+	return &LoanRepository{client: client}
+}
+
+// Create stores the terms of a loan account and its initial rate
+func (r *LoanRepository) Create(ctx context.Context, loan *entity.Loan) error {
+	q := conn(ctx, r.client)
+
+	if _, err := q.Exec(ctx,
+		`INSERT INTO loans (
+			account_id, ledger_id, principal, rate_type, term_months, payment_frequency,
+			start_date, rounding_mode, accrued_from, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		loan.AccountID.String(), loan.LedgerID.String(), loan.Principal, loan.RateType.String(), loan.TermMonths,
+		loan.Frequency.String(), loan.StartDate, loan.Rounding.String(), loan.AccruedFrom,
+		loan.CreatedAt, loan.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert loan: %w", err)
+	}
+
+	return insertLoanRates(ctx, q, loan)
+}
+
+// Update stores the loan's rates and the date interest accrues from
+func (r *LoanRepository) Update(ctx context.Context, loan *entity.Loan) error {
+	q := conn(ctx, r.client)
+
+	tag, err := q.Exec(ctx,
+		`UPDATE loans SET accrued_from = $1, updated_at = $2 WHERE ledger_id = $3 AND account_id = $4`,
+		loan.AccruedFrom, loan.UpdatedAt, loan.LedgerID.String(), loan.AccountID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update loan: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("loan %s: %w", loan.AccountID, repository.ErrNotFound)
+	}
+
+	if _, err := q.Exec(ctx, `DELETE FROM loan_rates WHERE account_id = $1`, loan.AccountID.String()); err != nil {
+		return fmt.Errorf("failed to delete loan rates: %w", err)
+	}
+
+	return insertLoanRates(ctx, q, loan)
+}
+
+// GetByAccount returns the terms of the loan account with its rates, oldest first
+func (r *LoanRepository) GetByAccount(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (*entity.Loan, error) {
+	q := conn(ctx, r.client)
+
+	var (
+		rateTypeStr, frequencyStr, roundingStr, currency string
+		principalUnits                                   decimal.Decimal
+		termMonths                                       int
+		startDate, accruedFrom, createdAt, updatedAt     time.Time
+	)
+	err := q.QueryRow(ctx,
+		`SELECT l.principal, l.rate_type, l.term_months, l.payment_frequency, l.start_date, l.rounding_mode,
+			l.accrued_from, a.currency, l.created_at, l.updated_at
+		FROM loans l
+		JOIN accounts a ON a.id = l.account_id
+		WHERE l.ledger_id = $1 AND l.account_id = $2`,
+		ledgerID.String(), accountID.String(),
+	).Scan(
+		&principalUnits, &rateTypeStr, &termMonths, &frequencyStr, &startDate, &roundingStr,
+		&accruedFrom, &currency, &createdAt, &updatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("loan %s: %w", accountID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loan: %w", err)
+	}
+
+	principal, err := money.FromMinorUnitsDecimal(principalUnits, money.Currency(currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid principal of loan %s: %w", accountID, err)
+	}
+
+	rateType, err := entity.NewLoanRateType(rateTypeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	frequency, err := entity.NewPaymentFrequency(frequencyStr)
+	if err != nil {
+		return nil, err
+	}
+
+	rounding, err := money.NewRoundingMode(roundingStr)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := loadLoanRates(ctx, q, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.ReconstructLoan(accountID, ledgerID, principal, rateType, rates, termMonths, frequency,
+		startDate, rounding, accruedFrom, createdAt, updatedAt), nil
+}
+
+func insertLoanRates(ctx context.Context, q querier, loan *entity.Loan) error {
+	for _, rate := range loan.Rates {
+		if _, err := q.Exec(ctx,
+			`INSERT INTO loan_rates (account_id, effective_date, annual_rate) VALUES ($1, $2, $3)`,
+			loan.AccountID.String(), rate.EffectiveDate, rate.AnnualRate.Points().String(),
+		); err != nil {
+			return fmt.Errorf("failed to insert loan rate: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// loadLoanRates returns the rates of the loan, oldest first
+func loadLoanRates(ctx context.Context, q querier, accountID entity.AccountID) ([]entity.LoanRate, error) {
+	rows, err := q.Query(ctx,
+		`SELECT effective_date, annual_rate::TEXT
+		FROM loan_rates
+		WHERE account_id = $1
+		ORDER BY effective_date`,
+		accountID.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loan rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []entity.LoanRate
+	for rows.Next() {
+		var (
+			effectiveDate time.Time
+			rateStr       string
+		)
+		if err := rows.Scan(&effectiveDate, &rateStr); err != nil {
+			return nil, fmt.Errorf("failed to scan loan rate: %w", err)
+		}
+
+		rate, err := money.NewPercent(rateStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate of loan %s: %w", accountID, err)
+		}
+
+		rates = append(rates, entity.LoanRate{EffectiveDate: effectiveDate, AnnualRate: rate})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get loan rates: %w", err)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("loan %s has no rates", accountID)
+	}
+	return rates, nil
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// reconciliationColumns selects a reconciliation aliased r joined to its account aliased a (for the currency)
+const reconciliationColumns = `r.id::TEXT, r.account_id::TEXT, r.statement_date, r.opening_balance,
+	r.closing_balance, a.currency, r.status, r.finished_at, r.created_at, r.updated_at`
+
+// Compile-time check that ReconciliationRepository satisfies the domain interface
+var _ repository.ReconciliationRepository = (*ReconciliationRepository)(nil)
+
+// ReconciliationRepository implements repository.ReconciliationRepository
+type ReconciliationRepository struct {
+	client *pg.Client
+}
+
+// NewReconciliationRepository creates a new ReconciliationRepository
+func NewReconciliationRepository(client *pg.Client) *ReconciliationRepository {
+	return &ReconciliationRepository{client: client}
+}
+
+// Create stores a new reconciliation
+func (r *ReconciliationRepository) Create(ctx context.Context, reconciliation *entity.Reconciliation) error {
+	if _, err := conn(ctx, r.client).Exec(ctx,
+		`INSERT INTO reconciliations (
+			id, ledger_id, account_id, statement_date, opening_balance, closing_balance,
+			status, finished_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		reconciliation.ID.String(), reconciliation.LedgerID.String(), reconciliation.AccountID.String(),
+		reconciliation.StatementDate, reconciliation.OpeningBalance, reconciliation.ClosingBalance,
+		reconciliation.Status.String(), reconciliation.FinishedAt.Ptr(),
+		reconciliation.CreatedAt, reconciliation.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert reconciliation: %w", err)
+	}
+	return nil
+}
+
+// Update stores the reconciliation's status
+func (r *ReconciliationRepository) Update(ctx context.Context, reconciliation *entity.Reconciliation) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`UPDATE reconciliations SET status = $1, finished_at = $2, updated_at = $3
+		WHERE ledger_id = $4 AND id = $5`,
+		reconciliation.Status.String(), reconciliation.FinishedAt.Ptr(), reconciliation.UpdatedAt,
+		reconciliation.LedgerID.String(), reconciliation.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update reconciliation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("reconciliation %s: %w", reconciliation.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// Delete removes the reconciliation; reconciled transactions keep their status
+func (r *ReconciliationRepository) Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.ReconciliationID) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`DELETE FROM reconciliations WHERE ledger_id = $1 AND id = $2`,
+		ledgerID.String(), id.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete reconciliation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("reconciliation %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// GetByID returns the reconciliation, without its transactions
+func (r *ReconciliationRepository) GetByID(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ReconciliationID,
+) (*entity.Reconciliation, error) {
+	row := conn(ctx, r.client).QueryRow(ctx,
+		`SELECT `+reconciliationColumns+`
+		FROM reconciliations r
+		JOIN accounts a ON a.id = r.account_id
+		WHERE r.ledger_id = $1 AND r.id = $2`,
+		ledgerID.String(), id.String(),
+	)
+
+	reconciliation, err := scanReconciliation(row, ledgerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("reconciliation %s: %w", id, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliation: %w", err)
+	}
+	return reconciliation, nil
+}
+
+// GetInProgress returns the account's unfinished reconciliation, if any.
+// Served by idx_reconciliations_in_progress, which also allows at most one per account.
+func (r *ReconciliationRepository) GetInProgress(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (optional.Option[*entity.Reconciliation], error) {
+	return r.getOne(ctx, ledgerID,
+		`SELECT `+reconciliationColumns+`
+		FROM reconciliations r
+		JOIN accounts a ON a.id = r.account_id
+		WHERE r.ledger_id = $1 AND r.account_id = $2 AND r.status = $3`,
+		ledgerID.String(), accountID.String(), entity.ReconciliationStatusInProgress.String(),
+	)
+}
+
+// GetLatestFinished returns the account's finished reconciliation with the latest statement date, if any
+func (r *ReconciliationRepository) GetLatestFinished(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+) (optional.Option[*entity.Reconciliation], error) {
+	return r.getOne(ctx, ledgerID,
+		`SELECT `+reconciliationColumns+`
+		FROM reconciliations r
+		JOIN accounts a ON a.id = r.account_id
+		WHERE r.ledger_id = $1 AND r.account_id = $2 AND r.status = $3
+		ORDER BY r.statement_date DESC
+		LIMIT 1`,
+		ledgerID.String(), accountID.String(), entity.ReconciliationStatusFinished.String(),
+	)
+}
+
+// ListLatestFinished returns the latest finished reconciliation of each account of the ledger
+func (r *ReconciliationRepository) ListLatestFinished(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+) ([]*entity.Reconciliation, error) {
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT DISTINCT ON (r.account_id) `+reconciliationColumns+`
+		FROM reconciliations r
+		JOIN accounts a ON a.id = r.account_id
+		WHERE r.ledger_id = $1 AND r.status = $2
+		ORDER BY r.account_id, r.statement_date DESC`,
+		ledgerID.String(), entity.ReconciliationStatusFinished.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reconciliations: %w", err)
+	}
+	defer rows.Close()
+
+	var reconciliations []*entity.Reconciliation
+	for rows.Next() {
+		reconciliation, err := scanReconciliation(rows, ledgerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reconciliation: %w", err)
+		}
+		reconciliations = append(reconciliations, reconciliation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list reconciliations: %w", err)
+	}
+	return reconciliations, nil
+}
+
+func (r *ReconciliationRepository) getOne(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	query string,
+	args ...any,
+) (optional.Option[*entity.Reconciliation], error) {
+	reconciliation, err := scanReconciliation(conn(ctx, r.client).QueryRow(ctx, query, args...), ledgerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return optional.None[*entity.Reconciliation](), nil
+	}
+	if err != nil {
+		return optional.None[*entity.Reconciliation](), fmt.Errorf("failed to get reconciliation: %w", err)
+	}
+	return optional.Some(reconciliation), nil
+}
+
+// scanReconciliation scans a row selected with reconciliationColumns
+func scanReconciliation(row pgx.Row, ledgerID ledgerEntity.LedgerID) (*entity.Reconciliation, error) {
+	var (
+		idStr, accountIDStr, currency, statusStr string
+		statementDate, createdAt, updatedAt      time.Time
+		openingUnits, closingUnits               decimal.Decimal
+		finishedAt                               *time.Time
+	)
+
+	if err := row.Scan(
+		&idStr, &accountIDStr, &statementDate, &openingUnits, &closingUnits, &currency,
+		&statusStr, &finishedAt, &createdAt, &updatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	id, err := entity.NewReconciliationIDFromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	accountID, err := entity.NewAccountIDFromString(accountIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := entity.NewReconciliationStatus(statusStr)
+	if err != nil {
+		return nil, err
+	}
+
+	opening, err := money.FromMinorUnitsDecimal(openingUnits, money.Currency(currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid opening balance of reconciliation %s: %w", idStr, err)
+	}
+
+	closing, err := money.FromMinorUnitsDecimal(closingUnits, money.Currency(currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid closing balance of reconciliation %s: %w", idStr, err)
+	}
+
+	return entity.ReconstructReconciliation(
+		id, ledgerID, accountID, statementDate, opening, closing,
+		status, optional.FromPtr(finishedAt), createdAt, updatedAt,
+	), nil
+}
@@ -0,0 +1,335 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// ruleColumns selects a transaction rule
+const ruleColumns = `id::TEXT, name, priority, enabled, COALESCE(description_contains, ''),
+	COALESCE(description_pattern, ''), min_amount, max_amount, COALESCE(amount_currency, ''), account_id::TEXT,
+	counterparty_type, day_from, day_to, item_id::TEXT, counterparty_id::TEXT, COALESCE(tag, ''),
+	COALESCE(note, ''), transfer_account_id::TEXT, created_at, updated_at`
+
+// Compile-time check that RuleRepository satisfies the domain interface
+var _ repository.RuleRepository = (*RuleRepository)(nil)
+
+// RuleRepository implements repository.RuleRepository
+type RuleRepository struct {
+	client *pg.Client
+}
+
+// NewRuleRepository creates a new RuleRepository
+func NewRuleRepository(client *pg.Client) *RuleRepository {
+	return &RuleRepository{client: client}
+}
+
+// Create stores a new rule
+func (r *RuleRepository) Create(ctx context.Context, rule *entity.Rule) error {
+	v, err := newRuleValues(rule)
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn(ctx, r.client).Exec(ctx,
+		`INSERT INTO transaction_rules (
+			id, ledger_id, name, priority, enabled, description_contains, description_pattern,
+			min_amount, max_amount, amount_currency, account_id, counterparty_type, day_from, day_to,
+			item_id, counterparty_id, tag, note, transfer_account_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10, $11, $12, $13, $14,
+			$15, $16, NULLIF($17, ''), NULLIF($18, ''), $19, $20, $21)`,
+		rule.ID.String(), rule.LedgerID.String(), rule.Name, rule.Priority, rule.Enabled,
+		rule.Conditions.DescriptionContains, rule.Conditions.DescriptionPattern,
+		v.minAmount, v.maxAmount, v.currency, v.accountID, v.counterpartyType, v.dayFrom, v.dayTo,
+		v.itemID, v.counterpartyID, rule.Actions.Tag, rule.Actions.Note, v.transferAccountID,
+		rule.CreatedAt, rule.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert rule: %w", err)
+	}
+	return nil
+}
+
+// Update stores the rule's name, priority, conditions, actions and whether it is enabled
+func (r *RuleRepository) Update(ctx context.Context, rule *entity.Rule) error {
+	v, err := newRuleValues(rule)
+	if err != nil {
+		return err
+	}
+
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`UPDATE transaction_rules SET
+			name = $1, priority = $2, enabled = $3, description_contains = NULLIF($4, ''),
+			description_pattern = NULLIF($5, ''), min_amount = $6, max_amount = $7, amount_currency = $8,
+			account_id = $9, counterparty_type = $10, day_from = $11, day_to = $12, item_id = $13,
+			counterparty_id = $14, tag = NULLIF($15, ''), note = NULLIF($16, ''), transfer_account_id = $17,
+			updated_at = $18
+		WHERE ledger_id = $19 AND id = $20`,
+		rule.Name, rule.Priority, rule.Enabled, rule.Conditions.DescriptionContains,
+		rule.Conditions.DescriptionPattern, v.minAmount, v.maxAmount, v.currency,
+		v.accountID, v.counterpartyType, v.dayFrom, v.dayTo, v.itemID,
+		v.counterpartyID, rule.Actions.Tag, rule.Actions.Note, v.transferAccountID,
+		rule.UpdatedAt, rule.LedgerID.String(), rule.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("rule %s: %w", rule.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// Delete removes the rule
+func (r *RuleRepository) Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.RuleID) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`DELETE FROM transaction_rules WHERE ledger_id = $1 AND id = $2`,
+		ledgerID.String(), id.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("rule %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// GetByID returns the rule
+func (r *RuleRepository) GetByID(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.RuleID,
+) (*entity.Rule, error) {
+	rule, err := scanRule(conn(ctx, r.client).QueryRow(ctx,
+		`SELECT `+ruleColumns+` FROM transaction_rules WHERE ledger_id = $1 AND id = $2`,
+		ledgerID.String(), id.String(),
+	), ledgerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("rule %s: %w", id, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rule: %w", err)
+	}
+	return rule, nil
+}
+
+// ListByLedger returns all rules of the ledger in priority order
+func (r *RuleRepository) ListByLedger(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Rule, error) {
+	return r.list(ctx, ledgerID, false)
+}
+
+// ListEnabled returns the ledger's enabled rules in priority order.
+// Served by idx_transaction_rules_ledger_priority.
+func (r *RuleRepository) ListEnabled(ctx context.Context, ledgerID ledgerEntity.LedgerID) ([]*entity.Rule, error) {
+	return r.list(ctx, ledgerID, true)
+}
+
+func (r *RuleRepository) list(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	enabledOnly bool,
+) ([]*entity.Rule, error) {
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT `+ruleColumns+`
+		FROM transaction_rules
+		WHERE ledger_id = $1 AND (enabled OR NOT $2)
+		ORDER BY priority, created_at, id`,
+		ledgerID.String(), enabledOnly,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*entity.Rule
+	for rows.Next() {
+		rule, err := scanRule(rows, ledgerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+	return rules, nil
+}
+
+// ruleValues holds the nullable column values of a rule
+type ruleValues struct {
+	minAmount, maxAmount   *decimal.Decimal
+	currency               *string
+	accountID              *string
+	counterpartyType       *string
+	dayFrom, dayTo         *int
+	itemID, counterpartyID *string
+	transferAccountID      *string
+}
+
+func newRuleValues(rule *entity.Rule) (ruleValues, error) {
+	c, a := rule.Conditions, rule.Actions
+	var v ruleValues
+
+	for _, bound := range []struct {
+		amount optional.Option[money.Money]
+		dest   **decimal.Decimal
+	}{
+		{c.MinAmount, &v.minAmount},
+		{c.MaxAmount, &v.maxAmount},
+	} {
+		if bound.amount.IsNone() {
+			continue
+		}
+		units, err := bound.amount.Unwrap().ToMinorUnitsDecimal()
+		if err != nil {
+			return ruleValues{}, fmt.Errorf("invalid amount condition of rule %s: %w", rule.ID, err)
+		}
+		currency := bound.amount.Unwrap().Currency.String()
+		*bound.dest = &units
+		v.currency = &currency
+	}
+
+	if c.AccountID.IsSome() {
+		v.accountID = optional.Some(c.AccountID.Unwrap().String()).Ptr()
+	}
+	if c.CounterpartyType.IsSome() {
+		v.counterpartyType = optional.Some(c.CounterpartyType.Unwrap().String()).Ptr()
+	}
+	if c.DayOfMonth.IsSome() {
+		days := c.DayOfMonth.Unwrap()
+		v.dayFrom, v.dayTo = &days.From, &days.To
+	}
+	if a.ItemID.IsSome() {
+		v.itemID = optional.Some(a.ItemID.Unwrap().String()).Ptr()
+	}
+	if a.CounterpartyID.IsSome() {
+		v.counterpartyID = optional.Some(a.CounterpartyID.Unwrap().String()).Ptr()
+	}
+	if a.TransferAccountID.IsSome() {
+		v.transferAccountID = optional.Some(a.TransferAccountID.Unwrap().String()).Ptr()
+	}
+
+	return v, nil
+}
+
+// scanRule scans a row selected with ruleColumns
+func scanRule(row pgx.Row, ledgerID ledgerEntity.LedgerID) (*entity.Rule, error) {
+	var (
+		idStr, name, contains, pattern, currency, tag, note string
+		priority                                            int
+		enabled                                             bool
+		minUnits, maxUnits                                  *decimal.Decimal
+		accountIDStr, counterpartyTypeStr                   *string
+		dayFrom, dayTo                                      *int
+		itemIDStr, counterpartyIDStr, transferAccountIDStr  *string
+		createdAt, updatedAt                                time.Time
+	)
+	if err := row.Scan(
+		&idStr, &name, &priority, &enabled, &contains,
+		&pattern, &minUnits, &maxUnits, &currency, &accountIDStr,
+		&counterpartyTypeStr, &dayFrom, &dayTo, &itemIDStr, &counterpartyIDStr, &tag,
+		&note, &transferAccountIDStr, &createdAt, &updatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	id, err := entity.NewRuleIDFromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := entity.RuleConditions{
+		DescriptionContains: contains,
+		DescriptionPattern:  pattern,
+		MinAmount:           optional.None[money.Money](),
+		MaxAmount:           optional.None[money.Money](),
+		AccountID:           optional.None[entity.AccountID](),
+		CounterpartyType:    optional.None[counterpartyEntity.CounterpartyType](),
+		DayOfMonth:          optional.None[entity.DayRange](),
+	}
+
+	for _, bound := range []struct {
+		units *decimal.Decimal
+		dest  *optional.Option[money.Money]
+	}{
+		{minUnits, &conditions.MinAmount},
+		{maxUnits, &conditions.MaxAmount},
+	} {
+		if bound.units == nil {
+			continue
+		}
+		amount, err := money.FromMinorUnitsDecimal(*bound.units, money.Currency(currency))
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount condition of rule %s: %w", idStr, err)
+		}
+		*bound.dest = optional.Some(amount)
+	}
+
+	if accountIDStr != nil {
+		accountID, err := entity.NewAccountIDFromString(*accountIDStr)
+		if err != nil {
+			return nil, err
+		}
+		conditions.AccountID = optional.Some(accountID)
+	}
+
+	if counterpartyTypeStr != nil {
+		counterpartyType, err := counterpartyEntity.NewCounterpartyType(*counterpartyTypeStr)
+		if err != nil {
+			return nil, err
+		}
+		conditions.CounterpartyType = optional.Some(counterpartyType)
+	}
+
+	if dayFrom != nil && dayTo != nil {
+		conditions.DayOfMonth = optional.Some(entity.DayRange{From: *dayFrom, To: *dayTo})
+	}
+
+	actions := entity.RuleActions{
+		ItemID:            optional.None[budgetEntity.ItemID](),
+		CounterpartyID:    optional.None[counterpartyEntity.CounterpartyID](),
+		Tag:               tag,
+		Note:              note,
+		TransferAccountID: optional.None[entity.AccountID](),
+	}
+
+	if itemIDStr != nil {
+		itemID, err := budgetEntity.NewItemIDFromString(*itemIDStr)
+		if err != nil {
+			return nil, err
+		}
+		actions.ItemID = optional.Some(itemID)
+	}
+
+	if counterpartyIDStr != nil {
+		counterpartyID, err := counterpartyEntity.NewCounterpartyIDFromString(*counterpartyIDStr)
+		if err != nil {
+			return nil, err
+		}
+		actions.CounterpartyID = optional.Some(counterpartyID)
+	}
+
+	if transferAccountIDStr != nil {
+		transferAccountID, err := entity.NewAccountIDFromString(*transferAccountIDStr)
+		if err != nil {
+			return nil, err
+		}
+		actions.TransferAccountID = optional.Some(transferAccountID)
+	}
+
+	return entity.ReconstructRule(id, ledgerID, name, priority, enabled, conditions, actions, createdAt, updatedAt), nil
+}
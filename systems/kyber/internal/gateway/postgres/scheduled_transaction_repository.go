@@ -0,0 +1,358 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/rrule"
+)
+
+// scheduleColumns selects a scheduled transaction aliased s joined to its account aliased a (for the currency)
+const scheduleColumns = `s.id::TEXT, s.account_id::TEXT, s.item_id::TEXT, s.counterparty_id::TEXT, s.amount,
+	a.currency, s.description, COALESCE(s.notes, ''), s.rrule, s.start_date, s.last_generated, s.is_active,
+	s.created_at, s.updated_at`
+
+// Compile-time check that ScheduledTransactionRepository satisfies the domain interface
+var _ repository.ScheduledTransactionRepository = (*ScheduledTransactionRepository)(nil)
+
+// ScheduledTransactionRepository implements repository.ScheduledTransactionRepository
+type ScheduledTransactionRepository struct {
+	client *pg.Client
+}
+
+// NewScheduledTransactionRepository creates a new ScheduledTransactionRepository
+func NewScheduledTransactionRepository(client *pg.Client) *ScheduledTransactionRepository {
+	return &ScheduledTransactionRepository{client: client}
+}
+
+// Create stores a new scheduled transaction
+func (r *ScheduledTransactionRepository) Create(ctx context.Context, schedule *entity.ScheduledTransaction) error {
+	q := conn(ctx, r.client)
+
+	if _, err := q.Exec(ctx,
+		`INSERT INTO scheduled_transactions (
+			id, ledger_id, account_id, item_id, counterparty_id, amount, description, notes,
+			rrule, start_date, last_generated, is_active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11, $12, $13, $14)`,
+		schedule.ID.String(), schedule.LedgerID.String(), schedule.AccountID.String(), schedule.ItemID.String(),
+		scheduleCounterpartyValue(schedule), schedule.Amount, schedule.Description, schedule.Notes,
+		schedule.Rule.String(), schedule.StartDate, schedule.LastGenerated.Ptr(), schedule.IsActive,
+		schedule.CreatedAt, schedule.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert scheduled transaction: %w", err)
+	}
+
+	return upsertOccurrences(ctx, q, schedule)
+}
+
+// Update stores the scheduled transaction and its occurrences
+func (r *ScheduledTransactionRepository) Update(ctx context.Context, schedule *entity.ScheduledTransaction) error {
+	q := conn(ctx, r.client)
+
+	tag, err := q.Exec(ctx,
+		`UPDATE scheduled_transactions SET
+			counterparty_id = $1, amount = $2, description = $3, notes = NULLIF($4, ''), rrule = $5,
+			start_date = $6, last_generated = $7, is_active = $8, updated_at = $9
+		WHERE ledger_id = $10 AND id = $11`,
+		scheduleCounterpartyValue(schedule), schedule.Amount, schedule.Description, schedule.Notes,
+		schedule.Rule.String(), schedule.StartDate, schedule.LastGenerated.Ptr(), schedule.IsActive,
+		schedule.UpdatedAt, schedule.LedgerID.String(), schedule.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update scheduled transaction: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("scheduled transaction %s: %w", schedule.ID, repository.ErrNotFound)
+	}
+
+	return upsertOccurrences(ctx, q, schedule)
+}
+
+// Delete removes the scheduled transaction; transactions it generated are kept
+func (r *ScheduledTransactionRepository) Delete(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ScheduledTransactionID,
+) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`DELETE FROM scheduled_transactions WHERE ledger_id = $1 AND id = $2`,
+		ledgerID.String(), id.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete scheduled transaction: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("scheduled transaction %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// GetByID returns the scheduled transaction with its occurrences
+func (r *ScheduledTransactionRepository) GetByID(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ScheduledTransactionID,
+) (*entity.ScheduledTransaction, error) {
+	return r.getOne(ctx, ledgerID, id,
+		`SELECT `+scheduleColumns+`
+		FROM scheduled_transactions s
+		JOIN accounts a ON a.id = s.account_id
+		WHERE s.ledger_id = $1 AND s.id = $2`,
+	)
+}
+
+// GetForUpdate returns the scheduled transaction, locking it until the surrounding transaction ends
+func (r *ScheduledTransactionRepository) GetForUpdate(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ScheduledTransactionID,
+) (*entity.ScheduledTransaction, error) {
+	return r.getOne(ctx, ledgerID, id,
+		`SELECT `+scheduleColumns+`
+		FROM scheduled_transactions s
+		JOIN accounts a ON a.id = s.account_id
+		WHERE s.ledger_id = $1 AND s.id = $2
+		FOR UPDATE OF s`,
+	)
+}
+
+// ListActive returns the ledger's scheduled transactions that are not paused, with their occurrences
+func (r *ScheduledTransactionRepository) ListActive(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+) ([]*entity.ScheduledTransaction, error) {
+	q := conn(ctx, r.client)
+
+	rows, err := q.Query(ctx,
+		`SELECT `+scheduleColumns+`
+		FROM scheduled_transactions s
+		JOIN accounts a ON a.id = s.account_id
+		WHERE s.ledger_id = $1 AND s.is_active
+		ORDER BY s.start_date`,
+		ledgerID.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []*entity.ScheduledTransaction
+	for rows.Next() {
+		schedule, err := scanSchedule(rows, ledgerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled transaction: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list scheduled transactions: %w", err)
+	}
+
+	if err := loadOccurrences(ctx, q, schedules...); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *ScheduledTransactionRepository) getOne(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.ScheduledTransactionID,
+	query string,
+) (*entity.ScheduledTransaction, error) {
+	q := conn(ctx, r.client)
+
+	schedule, err := scanSchedule(q.QueryRow(ctx, query, ledgerID.String(), id.String()), ledgerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("scheduled transaction %s: %w", id, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled transaction: %w", err)
+	}
+
+	if err := loadOccurrences(ctx, q, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// upsertOccurrences stores the schedule's skipped, modified and generated occurrences
+func upsertOccurrences(ctx context.Context, q querier, schedule *entity.ScheduledTransaction) error {
+	for _, o := range schedule.Occurrences {
+		var transactionID any
+		if o.TransactionID.IsSome() {
+			transactionID = o.TransactionID.Unwrap().String()
+		}
+
+		if _, err := q.Exec(ctx,
+			`INSERT INTO scheduled_occurrences (
+				schedule_id, occurrence_date, amount, description, skipped, modified, transaction_id
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (schedule_id, occurrence_date) DO UPDATE SET
+				amount = EXCLUDED.amount, description = EXCLUDED.description, skipped = EXCLUDED.skipped,
+				modified = EXCLUDED.modified, transaction_id = EXCLUDED.transaction_id`,
+			schedule.ID.String(), o.Date, o.Amount, o.Description, o.Skipped, o.Modified, transactionID,
+		); err != nil {
+			return fmt.Errorf("failed to store occurrence %s: %w", o.Date.Format(time.DateOnly), err)
+		}
+	}
+	return nil
+}
+
+// loadOccurrences attaches the stored occurrences of the given schedules
+func loadOccurrences(ctx context.Context, q querier, schedules ...*entity.ScheduledTransaction) error {
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*entity.ScheduledTransaction, len(schedules))
+	ids := make([]string, len(schedules))
+	for i, schedule := range schedules {
+		byID[schedule.ID.String()] = schedule
+		ids[i] = schedule.ID.String()
+	}
+
+	rows, err := q.Query(ctx,
+		`SELECT schedule_id::TEXT, occurrence_date, amount, description, skipped, modified, transaction_id::TEXT
+		FROM scheduled_occurrences
+		WHERE schedule_id = ANY($1::UUID[])`,
+		ids,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get occurrences: %w", err)
+	}
+	defer rows.Close()
+
+	occurrences := make(map[string][]entity.Occurrence, len(schedules))
+	for rows.Next() {
+		var (
+			scheduleIDStr, description string
+			date                       time.Time
+			amountUnits                decimal.Decimal
+			skipped, modified          bool
+			transactionIDStr           *string
+		)
+		if err := rows.Scan(
+			&scheduleIDStr, &date, &amountUnits, &description, &skipped, &modified, &transactionIDStr,
+		); err != nil {
+			return fmt.Errorf("failed to scan occurrence: %w", err)
+		}
+
+		schedule := byID[scheduleIDStr]
+		amount, err := money.FromMinorUnitsDecimal(amountUnits, schedule.Amount.Currency)
+		if err != nil {
+			return fmt.Errorf("invalid amount of occurrence %s: %w", date.Format(time.DateOnly), err)
+		}
+
+		transactionID := optional.None[entity.TransactionID]()
+		if transactionIDStr != nil {
+			txID, err := entity.NewTransactionIDFromString(*transactionIDStr)
+			if err != nil {
+				return err
+			}
+			transactionID = optional.Some(txID)
+		}
+
+		occurrences[scheduleIDStr] = append(occurrences[scheduleIDStr], entity.Occurrence{
+			ScheduleID:    schedule.ID,
+			Date:          date,
+			Amount:        amount,
+			Description:   description,
+			Skipped:       skipped,
+			Modified:      modified,
+			TransactionID: transactionID,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get occurrences: %w", err)
+	}
+
+	for _, schedule := range schedules {
+		*schedule = *entity.ReconstructScheduledTransaction(
+			schedule.ID, schedule.LedgerID, schedule.AccountID, schedule.ItemID, schedule.CounterpartyID,
+			schedule.Amount, schedule.Description, schedule.Notes, schedule.Rule, schedule.StartDate,
+			occurrences[schedule.ID.String()], schedule.LastGenerated, schedule.IsActive,
+			schedule.CreatedAt, schedule.UpdatedAt,
+		)
+	}
+	return nil
+}
+
+// scanSchedule scans a row selected with scheduleColumns, without its occurrences
+func scanSchedule(row pgx.Row, ledgerID ledgerEntity.LedgerID) (*entity.ScheduledTransaction, error) {
+	var (
+		idStr, accountIDStr, itemIDStr         string
+		counterpartyIDStr                      *string
+		amountUnits                            decimal.Decimal
+		currency, description, notes, rruleStr string
+		startDate, createdAt, updatedAt        time.Time
+		lastGenerated                          *time.Time
+		isActive                               bool
+	)
+
+	if err := row.Scan(
+		&idStr, &accountIDStr, &itemIDStr, &counterpartyIDStr, &amountUnits, &currency, &description, &notes,
+		&rruleStr, &startDate, &lastGenerated, &isActive, &createdAt, &updatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	id, err := entity.NewScheduledTransactionIDFromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	accountID, err := entity.NewAccountIDFromString(accountIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	itemID, err := budgetEntity.NewItemIDFromString(itemIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	counterpartyID := optional.None[counterpartyEntity.CounterpartyID]()
+	if counterpartyIDStr != nil {
+		cpID, err := counterpartyEntity.NewCounterpartyIDFromString(*counterpartyIDStr)
+		if err != nil {
+			return nil, err
+		}
+		counterpartyID = optional.Some(cpID)
+	}
+
+	amount, err := money.FromMinorUnitsDecimal(amountUnits, money.Currency(currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount of scheduled transaction %s: %w", idStr, err)
+	}
+
+	rule, err := rrule.Parse(rruleStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule of scheduled transaction %s: %w", idStr, err)
+	}
+
+	return entity.ReconstructScheduledTransaction(
+		id, ledgerID, accountID, itemID, counterpartyID, amount, description, notes, rule, startDate,
+		nil, optional.FromPtr(lastGenerated), isActive, createdAt, updatedAt,
+	), nil
+}
+
+func scheduleCounterpartyValue(schedule *entity.ScheduledTransaction) any {
+	if schedule.CounterpartyID.IsSome() {
+		return schedule.CounterpartyID.Unwrap().String()
+	}
+	return nil
+}
@@ -0,0 +1,880 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	budgetEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/budget/entity"
+	counterpartyEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/counterparty/entity"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// transactionColumns selects a transaction aliased t joined to its account aliased a (for the currency)
+const transactionColumns = `t.id::TEXT, t.account_id::TEXT, t.item_id::TEXT, t.counterparty_id::TEXT,
+	t.transfer_id::TEXT, t.amount, a.currency, t.description, COALESCE(t.notes, ''),
+	t.transaction_date, t.status, t.reconciliation_id::TEXT, t.locked, COALESCE(t.import_id, ''),
+	t.tags, t.posting_status, t.hold_amount, t.original_amount, t.original_currency, t.fx_rate::TEXT,
+	t.fx_fee, t.created_at, t.updated_at`
+
+// Compile-time check that TransactionRepository satisfies the domain interface
+var _ repository.TransactionRepository = (*TransactionRepository)(nil)
+
+// TransactionRepository implements repository.TransactionRepository
+type TransactionRepository struct {
+	client *pg.Client
+}
+
+// NewTransactionRepository creates a new TransactionRepository
+func NewTransactionRepository(client *pg.Client) *TransactionRepository {
+	return &TransactionRepository{client: client}
+}
+
+// SumByAccount returns the sum of the account's posted transactions dated before the cutoff,
+// or of all its posted transactions if there is none. Served by idx_transactions_account_balance_calc.
+func (r *TransactionRepository) SumByAccount(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	before optional.Option[time.Time],
+) (money.Money, error) {
+	var (
+		currency string
+		sumUnits decimal.Decimal
+	)
+	err := conn(ctx, r.client).QueryRow(ctx,
+		`SELECT a.currency, COALESCE(SUM(t.amount), 0)
+		FROM accounts a
+		LEFT JOIN transactions t ON t.account_id = a.id
+			AND ($3::TIMESTAMPTZ IS NULL OR t.transaction_date < $3) AND t.posting_status = $4
+		WHERE a.ledger_id = $1 AND a.id = $2
+		GROUP BY a.currency`,
+		ledgerID.String(), accountID.String(), before.Ptr(), entity.PostingStatusPosted.String(),
+	).Scan(&currency, &sumUnits)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return money.Money{}, fmt.Errorf("account %s: %w", accountID, repository.ErrNotFound)
+	}
+	if err != nil {
+		return money.Money{}, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+
+	sum, err := money.FromMinorUnitsDecimal(sumUnits, money.Currency(currency))
+	if err != nil {
+		return money.Money{}, fmt.Errorf("invalid transaction sum of account %s: %w", accountID, err)
+	}
+	return sum, nil
+}
+
+// ListByAccount returns the account's transactions dated within [from, to), oldest first
+func (r *TransactionRepository) ListByAccount(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	from, to time.Time,
+) ([]*entity.Transaction, error) {
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT `+transactionColumns+`
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE t.ledger_id = $1 AND t.account_id = $2
+			AND t.transaction_date >= $3 AND t.transaction_date < $4
+		ORDER BY t.transaction_date, t.created_at, t.id`,
+		ledgerID.String(), accountID.String(), from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*entity.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows, ledgerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	if err := loadSplits(ctx, conn(ctx, r.client), transactions...); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// GetByID returns the transaction
+func (r *TransactionRepository) GetByID(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+) (*entity.Transaction, error) {
+	return r.getOne(ctx, ledgerID, id,
+		`SELECT `+transactionColumns+`
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE t.ledger_id = $1 AND t.id = $2`,
+	)
+}
+
+// GetForUpdate returns the transaction, locking it until the surrounding transaction ends
+func (r *TransactionRepository) GetForUpdate(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+) (*entity.Transaction, error) {
+	return r.getOne(ctx, ledgerID, id,
+		`SELECT `+transactionColumns+`
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE t.ledger_id = $1 AND t.id = $2
+		FOR UPDATE OF t`,
+	)
+}
+
+func (r *TransactionRepository) getOne(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransactionID,
+	query string,
+) (*entity.Transaction, error) {
+	row := conn(ctx, r.client).QueryRow(ctx, query, ledgerID.String(), id.String())
+
+	tx, err := scanTransaction(row, ledgerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("transaction %s: %w", id, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if err := loadSplits(ctx, conn(ctx, r.client), tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ListUnreconciled returns the account's posted transactions dated before the cutoff that are not yet reconciled,
+// oldest first. Served by idx_transactions_unreconciled.
+func (r *TransactionRepository) ListUnreconciled(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	before time.Time,
+) ([]*entity.Transaction, error) {
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT `+transactionColumns+`
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE t.ledger_id = $1 AND t.account_id = $2
+			AND t.status <> $3 AND t.transaction_date < $4 AND t.posting_status = $5
+		ORDER BY t.transaction_date, t.created_at, t.id`,
+		ledgerID.String(), accountID.String(), entity.TransactionStatusReconciled.String(), before,
+		entity.PostingStatusPosted.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unreconciled transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*entity.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows, ledgerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list unreconciled transactions: %w", err)
+	}
+
+	if err := loadSplits(ctx, conn(ctx, r.client), transactions...); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// Create stores a new transaction and its split lines
+func (r *TransactionRepository) Create(ctx context.Context, tx *entity.Transaction) error {
+	q := conn(ctx, r.client)
+
+	if err := insertTransaction(ctx, q, tx); err != nil {
+		return err
+	}
+	return insertSplits(ctx, q, tx)
+}
+
+// ListImportIDs returns those of the given import IDs that the account's transactions already have.
+// Served by idx_transactions_import_id.
+func (r *TransactionRepository) ListImportIDs(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID entity.AccountID,
+	importIDs []string,
+) ([]string, error) {
+	if len(importIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT import_id FROM transactions
+		WHERE ledger_id = $1 AND account_id = $2 AND import_id = ANY($3::TEXT[])`,
+		ledgerID.String(), accountID.String(), importIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import IDs: %w", err)
+	}
+	defer rows.Close()
+
+	var found []string
+	for rows.Next() {
+		var importID string
+		if err := rows.Scan(&importID); err != nil {
+			return nil, fmt.Errorf("failed to scan import ID: %w", err)
+		}
+		found = append(found, importID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list import IDs: %w", err)
+	}
+	return found, nil
+}
+
+// ListPending returns the ledger's pending holds, or only the account's if one is given, oldest first.
+// Served by idx_transactions_pending.
+func (r *TransactionRepository) ListPending(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	accountID optional.Option[entity.AccountID],
+) ([]*entity.Transaction, error) {
+	var accountIDStr *string
+	if accountID.IsSome() {
+		accountIDStr = optional.Some(accountID.Unwrap().String()).Ptr()
+	}
+
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT `+transactionColumns+`
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE t.ledger_id = $1 AND ($2::UUID IS NULL OR t.account_id = $2) AND t.posting_status = $3
+		ORDER BY t.transaction_date, t.created_at, t.id`,
+		ledgerID.String(), accountIDStr, entity.PostingStatusPending.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*entity.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows, ledgerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list pending transactions: %w", err)
+	}
+
+	if err := loadSplits(ctx, conn(ctx, r.client), transactions...); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// UpdatePosting stores the transaction's posting status, amount, effective FX rate, date and import ID
+func (r *TransactionRepository) UpdatePosting(ctx context.Context, tx *entity.Transaction) error {
+	foreign := foreignValues(tx)
+
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`UPDATE transactions
+		SET posting_status = $1, amount = $2, fx_rate = $3, transaction_date = $4, import_id = NULLIF($5, ''),
+			updated_at = $6
+		WHERE ledger_id = $7 AND id = $8`,
+		tx.PostingStatus.String(), tx.Amount, foreign.rate, tx.TransactionDate, tx.ImportID, tx.UpdatedAt,
+		tx.LedgerID.String(), tx.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction posting: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("transaction %s: %w", tx.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// UpdateStatus stores the transaction's cleared status, reconciliation and lock
+func (r *TransactionRepository) UpdateStatus(ctx context.Context, tx *entity.Transaction) error {
+	var reconciliationID any
+	if tx.ReconciliationID.IsSome() {
+		reconciliationID = tx.ReconciliationID.Unwrap().String()
+	}
+
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`UPDATE transactions SET status = $1, reconciliation_id = $2, locked = $3, updated_at = $4
+		WHERE ledger_id = $5 AND id = $6`,
+		tx.Status.String(), reconciliationID, tx.Locked, tx.UpdatedAt, tx.LedgerID.String(), tx.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("transaction %s: %w", tx.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// UpdateSplits stores the transaction's budget item and replaces its split lines
+func (r *TransactionRepository) UpdateSplits(ctx context.Context, tx *entity.Transaction) error {
+	q := conn(ctx, r.client)
+
+	tag, err := q.Exec(ctx,
+		`UPDATE transactions SET item_id = $1, updated_at = $2 WHERE ledger_id = $3 AND id = $4`,
+		tx.ItemID.String(), tx.UpdatedAt, tx.LedgerID.String(), tx.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction item: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("transaction %s: %w", tx.ID, repository.ErrNotFound)
+	}
+
+	if _, err := q.Exec(ctx, `DELETE FROM transaction_splits WHERE transaction_id = $1`, tx.ID.String()); err != nil {
+		return fmt.Errorf("failed to delete split lines: %w", err)
+	}
+
+	return insertSplits(ctx, q, tx)
+}
+
+// UpdateTags stores the transaction's tags
+func (r *TransactionRepository) UpdateTags(ctx context.Context, tx *entity.Transaction) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`UPDATE transactions SET tags = $1, updated_at = $2 WHERE ledger_id = $3 AND id = $4`,
+		tagsValue(tx.Tags), tx.UpdatedAt, tx.LedgerID.String(), tx.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction tags: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("transaction %s: %w", tx.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// Search returns one page of the ledger's transactions matching the filter, newest first, with the count
+// and per-currency sums of all matching transactions. Text is matched by idx_transactions_search_vector.
+func (r *TransactionRepository) Search(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	filter entity.TransactionFilter,
+	limit, offset int,
+) (*entity.TransactionPage, error) {
+	q := conn(ctx, r.client)
+	where, args := transactionFilterClause(ledgerID, filter)
+
+	page, err := sumTransactions(ctx, q, where, args, filter.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.Total == 0 || offset >= page.Total {
+		return page, nil
+	}
+
+	args = append(args, limit, offset)
+	rows, err := q.Query(ctx,
+		`SELECT `+transactionColumns+`
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE `+where+`
+		ORDER BY t.transaction_date DESC, t.created_at DESC, t.id DESC
+		LIMIT $`+fmt.Sprint(len(args)-1)+` OFFSET $`+fmt.Sprint(len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transactions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tx, err := scanTransaction(rows, ledgerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		page.Transactions = append(page.Transactions, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search transactions: %w", err)
+	}
+
+	if err := loadSplits(ctx, q, page.Transactions...); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// SumByOriginalCurrency sums the ledger's transactions matching the filter per currency they were charged in,
+// ordered by currency. Transfers and voided holds are left out.
+func (r *TransactionRepository) SumByOriginalCurrency(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	filter entity.TransactionFilter,
+) ([]entity.OriginalCurrencySpend, error) {
+	where, args := transactionFilterClause(ledgerID, filter)
+
+	rows, err := conn(ctx, r.client).Query(ctx,
+		`SELECT COALESCE(t.original_currency, a.currency), a.currency, COUNT(*),
+			SUM(COALESCE(t.original_amount, t.amount)), SUM(t.amount),
+			COALESCE(SUM(t.fx_fee), 0)
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE `+where+` AND t.transfer_id IS NULL
+			AND t.posting_status <> '`+entity.PostingStatusVoided.String()+`'
+		GROUP BY 1, 2
+		ORDER BY 1, 2`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum transactions by original currency: %w", err)
+	}
+	defer rows.Close()
+
+	var spend []entity.OriginalCurrencySpend
+	for rows.Next() {
+		var (
+			originalCurrency, currency            string
+			count                                 int64
+			originalUnits, settledUnits, feeUnits decimal.Decimal
+		)
+		if err := rows.Scan(&originalCurrency, &currency, &count, &originalUnits, &settledUnits, &feeUnits); err != nil {
+			return nil, fmt.Errorf("failed to scan original currency totals: %w", err)
+		}
+
+		original, err := money.FromMinorUnitsDecimal(originalUnits, money.Currency(originalCurrency))
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction total in %s: %w", originalCurrency, err)
+		}
+
+		settled, err := money.FromMinorUnitsDecimal(settledUnits, money.Currency(currency))
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction total in %s: %w", currency, err)
+		}
+
+		fee, err := money.FromMinorUnitsDecimal(feeUnits, money.Currency(currency))
+		if err != nil {
+			return nil, fmt.Errorf("invalid FX fee total in %s: %w", currency, err)
+		}
+
+		// Rows come ordered by original currency, one per account currency it settled in
+		if len(spend) == 0 || spend[len(spend)-1].Currency != original.Currency {
+			zero, err := money.Zero(original.Currency)
+			if err != nil {
+				return nil, err
+			}
+			spend = append(spend, entity.OriginalCurrencySpend{Currency: original.Currency, Original: zero})
+		}
+
+		s := &spend[len(spend)-1]
+		if s.Original, err = s.Original.Add(original); err != nil {
+			return nil, err
+		}
+		if s.Settled, err = s.Settled.Add(settled); err != nil {
+			return nil, err
+		}
+		if !fee.IsZero() {
+			if s.Fees, err = s.Fees.Add(fee); err != nil {
+				return nil, err
+			}
+		}
+		s.Count += int(count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to sum transactions by original currency: %w", err)
+	}
+	return spend, nil
+}
+
+// sumTransactions counts per currency the transactions matching a transactionFilterClause, and sums
+// those that are not voided. When filtering by item, a split transaction adds only its lines assigned to it.
+func sumTransactions(
+	ctx context.Context,
+	q querier,
+	where string,
+	args []any,
+	itemID optional.Option[budgetEntity.ItemID],
+) (*entity.TransactionPage, error) {
+	amount := "t.amount"
+	if itemID.IsSome() {
+		args = append(slices.Clone(args), itemID.Unwrap().String())
+		amount = fmt.Sprintf(`CASE WHEN EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id)
+			THEN (SELECT COALESCE(SUM(s.amount), 0) FROM transaction_splits s
+				WHERE s.transaction_id = t.id AND s.item_id = $%d)
+			ELSE t.amount END`, len(args))
+	}
+
+	rows, err := q.Query(ctx,
+		`SELECT a.currency, COUNT(*),
+			COALESCE(SUM(`+amount+`) FILTER (WHERE t.posting_status <> '`+entity.PostingStatusVoided.String()+`'), 0)
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE `+where+`
+		GROUP BY a.currency`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+	defer rows.Close()
+
+	page := &entity.TransactionPage{}
+	for rows.Next() {
+		var (
+			currency string
+			count    int64
+			sumUnits decimal.Decimal
+		)
+		if err := rows.Scan(&currency, &count, &sumUnits); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction totals: %w", err)
+		}
+
+		sum, err := money.FromMinorUnitsDecimal(sumUnits, money.Currency(currency))
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction total in %s: %w", currency, err)
+		}
+		if page.Totals, err = page.Totals.Add(sum); err != nil {
+			return nil, err
+		}
+		page.Total += int(count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+	return page, nil
+}
+
+// transactionFilterClause builds the WHERE clause and arguments of a search over transactions aliased t
+// joined to their accounts aliased a. Only the filters that are set are added, so the planner can pick
+// the index that suits them.
+func transactionFilterClause(ledgerID ledgerEntity.LedgerID, f entity.TransactionFilter) (string, []any) {
+	conditions := []string{"t.ledger_id = $1"}
+	args := []any{ledgerID.String()}
+
+	// add appends a condition whose %[1]d verbs are replaced by the number of its argument
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.Text != "" {
+		add(`t.search_vector @@ websearch_to_tsquery('simple', $%[1]d)`, f.Text)
+	}
+	if f.AmountCurrency().IsSome() {
+		add(`a.currency = $%[1]d`, f.AmountCurrency().Unwrap().String())
+	}
+	if f.MinAmount.IsSome() {
+		add(`t.amount >= $%[1]d`, f.MinAmount.Unwrap())
+	}
+	if f.MaxAmount.IsSome() {
+		add(`t.amount <= $%[1]d`, f.MaxAmount.Unwrap())
+	}
+	if f.From.IsSome() {
+		add(`t.transaction_date >= $%[1]d`, f.From.Unwrap())
+	}
+	if f.To.IsSome() {
+		add(`t.transaction_date < $%[1]d`, f.To.Unwrap())
+	}
+	if f.AccountID.IsSome() {
+		add(`t.account_id = $%[1]d`, f.AccountID.Unwrap().String())
+	}
+	if f.ItemID.IsSome() {
+		add(`(t.item_id = $%[1]d OR EXISTS (
+			SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id AND s.item_id = $%[1]d
+		))`, f.ItemID.Unwrap().String())
+	}
+	if f.CounterpartyID.IsSome() {
+		add(`t.counterparty_id = $%[1]d`, f.CounterpartyID.Unwrap().String())
+	}
+	if f.Tag != "" {
+		add(`t.tags @> ARRAY[$%[1]d::TEXT]`, f.Tag)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+func insertSplits(ctx context.Context, q querier, tx *entity.Transaction) error {
+	for i, line := range tx.Splits {
+		if _, err := q.Exec(ctx,
+			`INSERT INTO transaction_splits (transaction_id, line_no, item_id, amount, note)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''))`,
+			tx.ID.String(), i, line.ItemID.String(), line.Amount, line.Note,
+		); err != nil {
+			return fmt.Errorf("failed to insert split line %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func insertTransaction(ctx context.Context, q querier, tx *entity.Transaction) error {
+	var transferID any
+	if tx.TransferID.IsSome() {
+		transferID = tx.TransferID.Unwrap().String()
+	}
+
+	foreign := foreignValues(tx)
+
+	if _, err := q.Exec(ctx,
+		`INSERT INTO transactions (
+			id, ledger_id, account_id, item_id, counterparty_id, transfer_id, amount,
+			description, notes, transaction_date, status, import_id, tags, posting_status, hold_amount,
+			original_amount, original_currency, fx_rate, fx_fee, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), $13, $14, $15, $16, $17, $18, $19, $20, $21
+		)`,
+		tx.ID.String(), tx.LedgerID.String(), tx.AccountID.String(), tx.ItemID.String(),
+		counterpartyValue(tx), transferID, tx.Amount,
+		tx.Description, tx.Notes, tx.TransactionDate, tx.Status.String(), tx.ImportID, tagsValue(tx.Tags),
+		tx.PostingStatus.String(), tx.HoldAmount.Ptr(), foreign.original, foreign.currency, foreign.rate, foreign.fee,
+		tx.CreatedAt, tx.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert transaction %s: %w", tx.ID, err)
+	}
+	return nil
+}
+
+// scanTransaction scans a row selected with transactionColumns
+func scanTransaction(row pgx.Row, ledgerID ledgerEntity.LedgerID) (*entity.Transaction, error) {
+	var (
+		idStr, accountIDStr, itemIDStr                        string
+		counterpartyIDStr, transferIDStr, reconciliationIDStr *string
+		amountUnits                                           decimal.Decimal
+		currency, description, notes, statusStr, importID     string
+		postingStatusStr                                      string
+		holdUnits, originalUnits, fxFeeUnits                  *decimal.Decimal
+		originalCurrency, fxRateStr                           *string
+		tags                                                  []string
+		locked                                                bool
+		transactionDate, createdAt, updatedAt                 time.Time
+	)
+
+	if err := row.Scan(
+		&idStr, &accountIDStr, &itemIDStr, &counterpartyIDStr, &transferIDStr, &amountUnits, &currency,
+		&description, &notes, &transactionDate, &statusStr, &reconciliationIDStr, &locked, &importID,
+		&tags, &postingStatusStr, &holdUnits, &originalUnits, &originalCurrency, &fxRateStr, &fxFeeUnits,
+		&createdAt, &updatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	id, err := entity.NewTransactionIDFromString(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	accountID, err := entity.NewAccountIDFromString(accountIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	itemID, err := budgetEntity.NewItemIDFromString(itemIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	counterpartyID := optional.None[counterpartyEntity.CounterpartyID]()
+	if counterpartyIDStr != nil {
+		cpID, err := counterpartyEntity.NewCounterpartyIDFromString(*counterpartyIDStr)
+		if err != nil {
+			return nil, err
+		}
+		counterpartyID = optional.Some(cpID)
+	}
+
+	amount, err := money.FromMinorUnitsDecimal(amountUnits, money.Currency(currency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount of transaction %s: %w", idStr, err)
+	}
+
+	tx := entity.ReconstructTransaction(
+		id, ledgerID, accountID, itemID, counterpartyID, amount,
+		description, notes, transactionDate, createdAt, updatedAt,
+	)
+
+	if transferIDStr != nil {
+		transferID, err := entity.NewTransferIDFromString(*transferIDStr)
+		if err != nil {
+			return nil, err
+		}
+		tx.TransferID = optional.Some(transferID)
+	}
+
+	tx.Status, err = entity.NewTransactionStatus(statusStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if reconciliationIDStr != nil {
+		reconciliationID, err := entity.NewReconciliationIDFromString(*reconciliationIDStr)
+		if err != nil {
+			return nil, err
+		}
+		tx.ReconciliationID = optional.Some(reconciliationID)
+	}
+	tx.PostingStatus, err = entity.NewPostingStatus(postingStatusStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if holdUnits != nil {
+		holdAmount, err := money.FromMinorUnitsDecimal(*holdUnits, money.Currency(currency))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hold amount of transaction %s: %w", idStr, err)
+		}
+		tx.HoldAmount = optional.Some(holdAmount)
+	}
+
+	if originalUnits != nil {
+		if tx.Foreign, err = scanForeignAmount(tx, *originalUnits, *originalCurrency, *fxRateStr, *fxFeeUnits); err != nil {
+			return nil, fmt.Errorf("invalid foreign amount of transaction %s: %w", idStr, err)
+		}
+	}
+
+	tx.Locked = locked
+	tx.ImportID = importID
+	if len(tags) > 0 {
+		tx.Tags = tags
+	}
+
+	return tx, nil
+}
+
+// loadSplits attaches the split lines of the given transactions
+func loadSplits(ctx context.Context, q querier, transactions ...*entity.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*entity.Transaction, len(transactions))
+	ids := make([]string, 0, len(transactions))
+	for _, tx := range transactions {
+		byID[tx.ID.String()] = tx
+		ids = append(ids, tx.ID.String())
+	}
+
+	rows, err := q.Query(ctx,
+		`SELECT transaction_id::TEXT, item_id::TEXT, amount, COALESCE(note, '')
+		FROM transaction_splits
+		WHERE transaction_id = ANY($1::UUID[])
+		ORDER BY transaction_id, line_no`,
+		ids,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get split lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			txIDStr, itemIDStr, note string
+			amountUnits              decimal.Decimal
+		)
+		if err := rows.Scan(&txIDStr, &itemIDStr, &amountUnits, &note); err != nil {
+			return fmt.Errorf("failed to scan split line: %w", err)
+		}
+
+		tx := byID[txIDStr]
+
+		itemID, err := budgetEntity.NewItemIDFromString(itemIDStr)
+		if err != nil {
+			return err
+		}
+
+		amount, err := money.FromMinorUnitsDecimal(amountUnits, tx.Amount.Currency)
+		if err != nil {
+			return fmt.Errorf("invalid split amount of transaction %s: %w", txIDStr, err)
+		}
+
+		tx.Splits = append(tx.Splits, entity.SplitLine{ItemID: itemID, Amount: amount, Note: note})
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get split lines: %w", err)
+	}
+	return nil
+}
+
+// tagsValue returns the tags as a TEXT[], empty rather than NULL
+func tagsValue(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+func counterpartyValue(tx *entity.Transaction) any {
+	if tx.CounterpartyID.IsSome() {
+		return tx.CounterpartyID.Unwrap().String()
+	}
+	return nil
+}
+
+// foreignColumns holds the values of a transaction's foreign amount columns, all NULL unless it is foreign
+type foreignColumns struct {
+	original, currency, rate, fee any
+}
+
+func foreignValues(tx *entity.Transaction) foreignColumns {
+	if !tx.IsForeign() {
+		return foreignColumns{}
+	}
+
+	f := tx.Foreign.Unwrap()
+	return foreignColumns{
+		original: f.Original,
+		currency: f.Original.Currency.String(),
+		rate:     f.Rate.Value.String(),
+		fee:      f.Fee,
+	}
+}
+
+func scanForeignAmount(
+	tx *entity.Transaction,
+	originalUnits decimal.Decimal,
+	originalCurrency, fxRate string,
+	feeUnits decimal.Decimal,
+) (optional.Option[entity.ForeignAmount], error) {
+	original, err := money.FromMinorUnitsDecimal(originalUnits, money.Currency(originalCurrency))
+	if err != nil {
+		return optional.None[entity.ForeignAmount](), err
+	}
+
+	value, err := decimal.NewFromString(fxRate)
+	if err != nil {
+		return optional.None[entity.ForeignAmount](), err
+	}
+
+	rate, err := money.NewRate(original.Currency, tx.Amount.Currency, value, tx.TransactionDate)
+	if err != nil {
+		return optional.None[entity.ForeignAmount](), err
+	}
+
+	fee, err := money.FromMinorUnitsDecimal(feeUnits, tx.Amount.Currency)
+	if err != nil {
+		return optional.None[entity.ForeignAmount](), err
+	}
+
+	return optional.Some(entity.ForeignAmount{Original: original, Rate: rate, Fee: fee}), nil
+}
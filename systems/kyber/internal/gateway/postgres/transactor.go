@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+)
+
+// querier is the subset of pg.Client and pgx.Tx used by the repositories
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type txKey struct{}
+
+// Compile-time check that Transactor satisfies the domain interface
+var _ repository.Transactor = (*Transactor)(nil)
+
+// Transactor runs units of work in a single pg.Client transaction
+type Transactor struct {
+	client *pg.Client
+}
+
+// NewTransactor creates a new Transactor
+func NewTransactor(client *pg.Client) *Transactor {
+	return &Transactor{client: client}
+}
+
+// WithinTx runs fn in a transaction; repository calls made with the ctx passed to fn join it.
+// Nested calls reuse the outer transaction.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	return t.client.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return fn(withTx(ctx, tx))
+	})
+}
+
+func withTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// conn returns the transaction carried by ctx, or the client when there is none
+func conn(ctx context.Context, client *pg.Client) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return client
+}
@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+	"github.com/kneadCODE/coruscant/shared/golib/pg"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/domain/accounting/repository"
+	ledgerEntity "github.com/kneadCODE/coruscant/systems/kyber/internal/domain/ledger/entity"
+	"github.com/kneadCODE/coruscant/systems/kyber/internal/pkg/money"
+)
+
+// Compile-time check that TransferRepository satisfies the domain interface
+var _ repository.TransferRepository = (*TransferRepository)(nil)
+
+// TransferRepository implements repository.TransferRepository.
+// Both transactions are stored in the transactions table linked by transfer_id.
+type TransferRepository struct {
+	client *pg.Client
+}
+
+// NewTransferRepository creates a new TransferRepository
+func NewTransferRepository(client *pg.Client) *TransferRepository {
+	return &TransferRepository{client: client}
+}
+
+// Create stores a new transfer and both of its transactions
+func (r *TransferRepository) Create(ctx context.Context, transfer *entity.Transfer) error {
+	q := conn(ctx, r.client)
+
+	if _, err := q.Exec(ctx,
+		`INSERT INTO transfers (id, ledger_id, fx_rate, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`,
+		transfer.ID.String(), transfer.LedgerID.String(), fxRateValue(transfer),
+		transfer.CreatedAt, transfer.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert transfer: %w", err)
+	}
+
+	for _, tx := range []*entity.Transaction{transfer.Outgoing, transfer.Incoming} {
+		if err := insertTransaction(ctx, q, tx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Update stores the transfer and both of its transactions
+func (r *TransferRepository) Update(ctx context.Context, transfer *entity.Transfer) error {
+	q := conn(ctx, r.client)
+
+	tag, err := q.Exec(ctx,
+		`UPDATE transfers SET fx_rate = $1, updated_at = $2 WHERE ledger_id = $3 AND id = $4`,
+		fxRateValue(transfer), transfer.UpdatedAt, transfer.LedgerID.String(), transfer.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("transfer %s: %w", transfer.ID, repository.ErrNotFound)
+	}
+
+	for _, tx := range []*entity.Transaction{transfer.Outgoing, transfer.Incoming} {
+		if _, err := q.Exec(ctx,
+			`UPDATE transactions
+			SET item_id = $1, counterparty_id = $2, amount = $3, description = $4, notes = $5,
+				transaction_date = $6, updated_at = $7
+			WHERE id = $8 AND transfer_id = $9`,
+			tx.ItemID.String(), counterpartyValue(tx), tx.Amount, tx.Description, tx.Notes,
+			tx.TransactionDate, tx.UpdatedAt, tx.ID.String(), transfer.ID.String(),
+		); err != nil {
+			return fmt.Errorf("failed to update transaction %s: %w", tx.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the transfer; its transactions are removed by the transfer_id cascade
+func (r *TransferRepository) Delete(ctx context.Context, ledgerID ledgerEntity.LedgerID, id entity.TransferID) error {
+	tag, err := conn(ctx, r.client).Exec(ctx,
+		`DELETE FROM transfers WHERE ledger_id = $1 AND id = $2`,
+		ledgerID.String(), id.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete transfer: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("transfer %s: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// GetByID returns the transfer with both of its transactions
+func (r *TransferRepository) GetByID(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransferID,
+) (*entity.Transfer, error) {
+	return r.getOne(ctx, ledgerID, id, false)
+}
+
+// GetForUpdate returns the transfer with both of its transactions, locking them until the surrounding
+// transaction ends
+func (r *TransferRepository) GetForUpdate(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransferID,
+) (*entity.Transfer, error) {
+	return r.getOne(ctx, ledgerID, id, true)
+}
+
+// getOne reads the transfer and its transactions, locking their rows when forUpdate is set
+func (r *TransferRepository) getOne(
+	ctx context.Context,
+	ledgerID ledgerEntity.LedgerID,
+	id entity.TransferID,
+	forUpdate bool,
+) (*entity.Transfer, error) {
+	q := conn(ctx, r.client)
+
+	var transferLock, transactionsLock string
+	if forUpdate {
+		transferLock, transactionsLock = " FOR UPDATE", " FOR UPDATE OF t"
+	}
+
+	var (
+		fxRate               *string
+		createdAt, updatedAt time.Time
+	)
+	err := q.QueryRow(ctx,
+		`SELECT fx_rate::TEXT, created_at, updated_at FROM transfers WHERE ledger_id = $1 AND id = $2`+transferLock,
+		ledgerID.String(), id.String(),
+	).Scan(&fxRate, &createdAt, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("transfer %s: %w", id, repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	rows, err := q.Query(ctx,
+		`SELECT `+transactionColumns+`
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE t.transfer_id = $1`+transactionsLock,
+		id.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var outgoing, incoming *entity.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows, ledgerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transfer transaction: %w", err)
+		}
+		if tx.IsDebit() {
+			outgoing = tx
+		} else {
+			incoming = tx
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get transfer transactions: %w", err)
+	}
+
+	if outgoing == nil || incoming == nil {
+		return nil, fmt.Errorf("transfer %s is missing its outgoing or incoming transaction", id)
+	}
+
+	rate := optional.None[money.Rate]()
+	if fxRate != nil {
+		value, err := decimal.NewFromString(*fxRate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rate of transfer %s: %w", id, err)
+		}
+
+		r, err := money.NewRate(outgoing.Amount.Currency, incoming.Amount.Currency, value, outgoing.TransactionDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rate of transfer %s: %w", id, err)
+		}
+		rate = optional.Some(r)
+	}
+
+	return entity.ReconstructTransfer(id, ledgerID, outgoing, incoming, rate, createdAt, updatedAt), nil
+}
+
+func fxRateValue(transfer *entity.Transfer) any {
+	if transfer.Rate.IsSome() {
+		return transfer.Rate.Unwrap().Value.String()
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+package camt
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+)
+
+// Statement is an account statement (Stmt) of one account
+type Statement struct {
+	ID       string // Statement identification given by the bank
+	Account  string // IBAN, or the other account ID when there is none
+	Currency string // ISO 4217 code of the account
+	Opening  optional.Option[Balance]
+	Closing  optional.Option[Balance]
+	Entries  []Entry
+}
+
+// Balance is a booked balance of the statement. Amounts are signed: negative when overdrawn.
+type Balance struct {
+	Amount decimal.Decimal
+	Date   time.Time
+}
+
+// Entry is a statement entry (Ntry). Amounts are signed: negative for debits.
+type Entry struct {
+	Reference        string // Account servicer reference, unique per account; empty if the bank gives none
+	Status           string // BOOK, PDNG or INFO
+	BookingDate      time.Time
+	ValueDate        time.Time
+	Amount           decimal.Decimal
+	Currency         string
+	Reversal         bool
+	CounterpartyName string // Debtor of credits, creditor of debits
+	CounterpartyIBAN string
+	RemittanceInfo   string
+	AdditionalInfo   string
+}
+
+// IsBooked checks if the entry has been booked, as opposed to pending or for information only
+func (e Entry) IsBooked() bool {
+	return e.Status == "BOOK"
+}
+
+// Parse reads all statements from a camt.053 file
+func Parse(r io.Reader) ([]Statement, error) {
+	var doc document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("not a camt.053 file: %w", err)
+	}
+
+	if doc.XMLName.Local != "Document" || len(doc.Statement.Statements) == 0 {
+		return nil, errors.New("not a camt.053 file: missing BkToCstmrStmt/Stmt")
+	}
+
+	statements := make([]Statement, 0, len(doc.Statement.Statements))
+	for _, stmt := range doc.Statement.Statements {
+		statement, err := parseStatement(stmt)
+		if err != nil {
+			return nil, fmt.Errorf("statement %s: %w", stmt.ID, err)
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+func parseStatement(stmt xmlStatement) (Statement, error) {
+	s := Statement{
+		ID:       strings.TrimSpace(stmt.ID),
+		Account:  stmt.Account.ID.value(),
+		Currency: strings.ToUpper(strings.TrimSpace(stmt.Account.Currency)),
+		Opening:  optional.None[Balance](),
+		Closing:  optional.None[Balance](),
+	}
+	if s.Account == "" {
+		return Statement{}, errors.New("statement has no account ID")
+	}
+
+	for _, bal := range stmt.Balances {
+		balance, err := parseBalance(bal)
+		if err != nil {
+			return Statement{}, err
+		}
+
+		switch bal.code() {
+		case "OPBD", "PRCD": // Opening booked, or previously closed booked when there is no opening balance
+			if s.Opening.IsNone() || bal.code() == "OPBD" {
+				s.Opening = optional.Some(balance)
+			}
+		case "CLBD":
+			s.Closing = optional.Some(balance)
+		}
+		if s.Currency == "" {
+			s.Currency = strings.ToUpper(bal.Amount.Currency)
+		}
+	}
+
+	for i, ntry := range stmt.Entries {
+		entry, err := parseEntry(ntry)
+		if err != nil {
+			return Statement{}, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		s.Entries = append(s.Entries, entry)
+	}
+
+	return s, nil
+}
+
+func parseBalance(bal xmlBalance) (Balance, error) {
+	amount, err := bal.Amount.signed(bal.Indicator)
+	if err != nil {
+		return Balance{}, fmt.Errorf("invalid %s balance: %w", bal.code(), err)
+	}
+
+	date, err := bal.Date.parse()
+	if err != nil {
+		return Balance{}, fmt.Errorf("invalid %s balance date: %w", bal.code(), err)
+	}
+
+	return Balance{Amount: amount, Date: date}, nil
+}
+
+func parseEntry(ntry xmlEntry) (Entry, error) {
+	amount, err := ntry.Amount.signed(ntry.Indicator)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	e := Entry{
+		Reference:      strings.TrimSpace(ntry.Reference),
+		Status:         ntry.Status.value(),
+		Amount:         amount,
+		Currency:       strings.ToUpper(ntry.Amount.Currency),
+		Reversal:       strings.TrimSpace(ntry.Reversal) == "true",
+		AdditionalInfo: strings.TrimSpace(ntry.AdditionalInfo),
+	}
+
+	if e.BookingDate, err = ntry.BookingDate.parse(); err != nil {
+		return Entry{}, fmt.Errorf("invalid booking date: %w", err)
+	}
+	if e.ValueDate, err = ntry.ValueDate.parse(); err != nil {
+		return Entry{}, fmt.Errorf("invalid value date: %w", err)
+	}
+
+	var details []xmlTxDetails
+	for _, d := range ntry.Details {
+		details = append(details, d.Transactions...)
+	}
+
+	// Batch bookings have one detail per transaction; only a single one describes the entry as a whole
+	if len(details) == 1 {
+		d := details[0]
+		if e.Reference == "" {
+			e.Reference = strings.TrimSpace(d.Refs.AccountServicerRef)
+		}
+
+		party, account := d.Parties.Creditor, d.Parties.CreditorAccount
+		if e.Amount.IsPositive() {
+			party, account = d.Parties.Debtor, d.Parties.DebtorAccount
+		}
+		if d.Parties.UltimateDebtor.name() != "" && e.Amount.IsPositive() {
+			party = d.Parties.UltimateDebtor
+		}
+		e.CounterpartyName = party.name()
+		e.CounterpartyIBAN = account.ID.value()
+
+		var lines []string
+		for _, line := range d.Remittance.Unstructured {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		for _, s := range d.Remittance.Structured {
+			if ref := strings.TrimSpace(s.CreditorRef); ref != "" {
+				lines = append(lines, ref)
+			}
+		}
+		e.RemittanceInfo = strings.Join(lines, " ")
+	}
+
+	if e.Status == "" {
+		e.Status = "BOOK"
+	}
+	return e, nil
+}
+
+// document is the camt.053 XML root. Element names are matched in any namespace, so every
+// version of the message (urn:iso:std:iso:20022:tech:xsd:camt.053.001.xx) is accepted.
+type document struct {
+	XMLName   xml.Name
+	Statement struct {
+		Statements []xmlStatement `xml:"Stmt"`
+	} `xml:"BkToCstmrStmt"`
+}
+
+type xmlStatement struct {
+	ID      string `xml:"Id"`
+	Account struct {
+		ID       xmlAccountID `xml:"Id"`
+		Currency string       `xml:"Ccy"`
+	} `xml:"Acct"`
+	Balances []xmlBalance `xml:"Bal"`
+	Entries  []xmlEntry   `xml:"Ntry"`
+}
+
+type xmlAccountID struct {
+	IBAN  string `xml:"IBAN"`
+	Other string `xml:"Othr>Id"`
+}
+
+func (a xmlAccountID) value() string {
+	if iban := strings.TrimSpace(a.IBAN); iban != "" {
+		return iban
+	}
+	return strings.TrimSpace(a.Other)
+}
+
+type xmlAccount struct {
+	ID xmlAccountID `xml:"Id"`
+}
+
+type xmlBalance struct {
+	Code      string    `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    xmlAmount `xml:"Amt"`
+	Indicator string    `xml:"CdtDbtInd"`
+	Date      xmlDate   `xml:"Dt"`
+}
+
+func (b xmlBalance) code() string {
+	return strings.ToUpper(strings.TrimSpace(b.Code))
+}
+
+type xmlEntry struct {
+	Reference      string       `xml:"AcctSvcrRef"`
+	Amount         xmlAmount    `xml:"Amt"`
+	Indicator      string       `xml:"CdtDbtInd"`
+	Reversal       string       `xml:"RvslInd"`
+	Status         xmlStatus    `xml:"Sts"`
+	BookingDate    xmlDate      `xml:"BookgDt"`
+	ValueDate      xmlDate      `xml:"ValDt"`
+	Details        []xmlDetails `xml:"NtryDtls"`
+	AdditionalInfo string       `xml:"AddtlNtryInf"`
+}
+
+type xmlDetails struct {
+	Transactions []xmlTxDetails `xml:"TxDtls"`
+}
+
+type xmlTxDetails struct {
+	Refs struct {
+		AccountServicerRef string `xml:"AcctSvcrRef"`
+	} `xml:"Refs"`
+	Parties struct {
+		Debtor          xmlParty   `xml:"Dbtr"`
+		DebtorAccount   xmlAccount `xml:"DbtrAcct"`
+		UltimateDebtor  xmlParty   `xml:"UltmtDbtr"`
+		Creditor        xmlParty   `xml:"Cdtr"`
+		CreditorAccount xmlAccount `xml:"CdtrAcct"`
+	} `xml:"RltdPties"`
+	Remittance struct {
+		Unstructured []string `xml:"Ustrd"`
+		Structured   []struct {
+			CreditorRef string `xml:"CdtrRefInf>Ref"`
+		} `xml:"Strd"`
+	} `xml:"RmtInf"`
+}
+
+// xmlParty is a related party; from version 001.08 its name is nested in Pty
+type xmlParty struct {
+	Name      string `xml:"Nm"`
+	PartyName string `xml:"Pty>Nm"`
+}
+
+func (p xmlParty) name() string {
+	if name := strings.TrimSpace(p.Name); name != "" {
+		return name
+	}
+	return strings.TrimSpace(p.PartyName)
+}
+
+// xmlStatus is the entry status: a code until version 001.07, a Cd element from 001.08
+type xmlStatus struct {
+	Text string `xml:",chardata"`
+	Code string `xml:"Cd"`
+}
+
+func (s xmlStatus) value() string {
+	if code := strings.TrimSpace(s.Code); code != "" {
+		return strings.ToUpper(code)
+	}
+	return strings.ToUpper(strings.TrimSpace(s.Text))
+}
+
+type xmlAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+// signed returns the amount, negated for the DBIT indicator
+func (a xmlAmount) signed(indicator string) (decimal.Decimal, error) {
+	amount, err := decimal.NewFromString(strings.TrimSpace(a.Value))
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("invalid amount %q", a.Value)
+	}
+
+	switch strings.TrimSpace(indicator) {
+	case "CRDT":
+		return amount, nil
+	case "DBIT":
+		return amount.Neg(), nil
+	default:
+		return decimal.Decimal{}, fmt.Errorf("invalid credit/debit indicator %q", indicator)
+	}
+}
+
+// xmlDate holds either a date (Dt) or a date and time (DtTm)
+type xmlDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+// parse returns the calendar date in UTC, or the zero time if there is none
+func (d xmlDate) parse() (time.Time, error) {
+	if s := strings.TrimSpace(d.Date); s != "" {
+		return time.Parse(time.DateOnly, s)
+	}
+
+	s := strings.TrimSpace(d.DateTime)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if len(s) < len(time.DateOnly) {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	// The calendar date is the one the bank booked on, whatever the time zone
+	return time.Parse(time.DateOnly, s[:len(time.DateOnly)])
+}
@@ -0,0 +1,112 @@
+package camt
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	file, err := os.Open("testdata/camt053.xml")
+	require.NoError(t, err)
+	defer file.Close()
+
+	statements, err := Parse(file)
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+
+	s := statements[0]
+	assert.Equal(t, "2024-01-DE89370400440532013000", s.ID)
+	assert.Equal(t, "DE89370400440532013000", s.Account)
+	assert.Equal(t, "EUR", s.Currency)
+	assert.Equal(t, "1000", s.Opening.Unwrap().Amount.String(), "OPBD wins over PRCD")
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), s.Opening.Unwrap().Date)
+	assert.Equal(t, "3457.5", s.Closing.Unwrap().Amount.String())
+	require.Len(t, s.Entries, 3)
+
+	debit := s.Entries[0]
+	assert.Equal(t, "2024010500012", debit.Reference)
+	assert.True(t, debit.IsBooked())
+	assert.Equal(t, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), debit.BookingDate)
+	assert.Equal(t, time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC), debit.ValueDate)
+	assert.Equal(t, "-42.5", debit.Amount.String())
+	assert.Equal(t, "Buero Bedarf AG", debit.CounterpartyName, "the creditor of a debit")
+	assert.Equal(t, "DE02120300000000202051", debit.CounterpartyIBAN)
+	assert.Equal(t, "Invoice 2024-17 Printer paper", debit.RemittanceInfo)
+
+	credit := s.Entries[1]
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), credit.BookingDate)
+	assert.Equal(t, "2500", credit.Amount.String())
+	assert.Equal(t, "ACME Corp", credit.CounterpartyName, "the debtor of a credit")
+	assert.Equal(t, "NL91ABNA0417164300", credit.CounterpartyIBAN)
+	assert.Equal(t, "RF18539007547034", credit.RemittanceInfo)
+
+	pending := s.Entries[2]
+	assert.False(t, pending.IsBooked())
+	assert.True(t, pending.BookingDate.IsZero())
+	assert.Equal(t, "Card payment pending", pending.AdditionalInfo)
+}
+
+func TestParse_Version08(t *testing.T) {
+	file, err := os.Open("testdata/camt053_v08.xml")
+	require.NoError(t, err)
+	defer file.Close()
+
+	statements, err := Parse(file)
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+
+	s := statements[0]
+	assert.Equal(t, "0532013000", s.Account)
+	assert.Equal(t, "CHF", s.Currency)
+	assert.True(t, s.Opening.IsNone())
+	assert.Equal(t, "-150.25", s.Closing.Unwrap().Amount.String())
+
+	require.Len(t, s.Entries, 1)
+	assert.True(t, s.Entries[0].IsBooked())
+	assert.Equal(t, "CH-REF-9", s.Entries[0].Reference, "falls back to the transaction reference")
+	assert.Equal(t, "Swisscom", s.Entries[0].CounterpartyName)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		errContains string
+	}{
+		{
+			name:        "not XML",
+			file:        "OFXHEADER:100",
+			errContains: "not a camt.053 file",
+		},
+		{
+			name:        "other message",
+			file:        `<Document><BkToCstmrDbtCdtNtfctn><Ntfctn/></BkToCstmrDbtCdtNtfctn></Document>`,
+			errContains: "missing BkToCstmrStmt/Stmt",
+		},
+		{
+			name: "bad indicator",
+			file: `<Document><BkToCstmrStmt><Stmt><Id>1</Id><Acct><Id><IBAN>X</IBAN></Id></Acct>
+				<Ntry><Amt Ccy="EUR">1.00</Amt><CdtDbtInd>UP</CdtDbtInd><Sts>BOOK</Sts></Ntry>
+				</Stmt></BkToCstmrStmt></Document>`,
+			errContains: `entry 1: invalid credit/debit indicator "UP"`,
+		},
+		{
+			name:        "no account",
+			file:        `<Document><BkToCstmrStmt><Stmt><Id>1</Id></Stmt></BkToCstmrStmt></Document>`,
+			errContains: "statement has no account ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.file))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
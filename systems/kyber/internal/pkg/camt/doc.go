@@ -0,0 +1,2 @@
+// Package camt parses ISO 20022 camt.053 bank-to-customer account statements (versions 001.02 to 001.08)
+package camt
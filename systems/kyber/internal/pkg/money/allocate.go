@@ -0,0 +1,89 @@
+package money
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Allocate distributes the amount across the given ratios at the currency's minor unit
+// using the largest-remainder method, so the parts always sum exactly to the original.
+// Leftover minor units go to the parts with the largest fractional remainder, ties
+// resolved in ratio order. Ratios must be non-negative and sum to more than zero.
+func (m Money) Allocate(ratios ...decimal.Decimal) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, fmt.Errorf("at least one ratio is required")
+	}
+
+	sum := decimal.Zero
+	for i, r := range ratios {
+		if r.IsNegative() {
+			return nil, fmt.Errorf("ratio %d cannot be negative: %s", i, r)
+		}
+		sum = sum.Add(r)
+	}
+	if !sum.IsPositive() {
+		return nil, fmt.Errorf("ratios must sum to more than zero")
+	}
+
+	units, err := m.ToMinorUnitsDecimal()
+	if err != nil {
+		return nil, fmt.Errorf("cannot allocate: %w", err)
+	}
+
+	// Allocate the absolute value and reapply the sign so negative amounts
+	// round the same way as positive ones
+	total := units.Abs()
+
+	shares := make([]decimal.Decimal, len(ratios))
+	remainders := make([]decimal.Decimal, len(ratios))
+	allocated := decimal.Zero
+	for i, r := range ratios {
+		shares[i], remainders[i] = total.Mul(r).QuoRem(sum, 0)
+		allocated = allocated.Add(shares[i])
+	}
+
+	order := make([]int, len(ratios))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].GreaterThan(remainders[order[b]])
+	})
+
+	// Each share is short of its exact value by less than one minor unit, so fewer are left over than there are ratios
+	leftover := total.Sub(allocated).IntPart()
+	for i := int64(0); i < leftover; i++ {
+		shares[order[i]] = shares[order[i]].Add(decimal.NewFromInt(1))
+	}
+
+	parts := make([]Money, len(ratios))
+	for i, share := range shares {
+		if units.IsNegative() {
+			share = share.Neg()
+		}
+		part, err := FromMinorUnitsDecimal(share, m.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("cannot allocate: %w", err)
+		}
+		parts[i] = part
+	}
+
+	return parts, nil
+}
+
+// Split divides the amount into n parts that differ by at most one minor unit
+// and sum exactly to the original
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("split count must be positive, got %d", n)
+	}
+
+	ratios := make([]decimal.Decimal, n)
+	for i := range ratios {
+		ratios[i] = decimal.NewFromInt(1)
+	}
+
+	return m.Allocate(ratios...)
+}
@@ -0,0 +1,125 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_Split(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency Currency
+		n        int
+		expected []string
+	}{
+		{"three way bill", "100.00", CurrencySGD, 3, []string{"33.34", "33.33", "33.33"}},
+		{"even split", "90.00", CurrencyUSD, 3, []string{"30.00", "30.00", "30.00"}},
+		{"negative amount", "-100.00", CurrencySGD, 3, []string{"-33.34", "-33.33", "-33.33"}},
+		{"fewer cents than parts", "0.02", CurrencyUSD, 3, []string{"0.01", "0.01", "0.00"}},
+		{"zero-decimal currency", "1000", CurrencyJPY, 3, []string{"334", "333", "333"}},
+		{"three-decimal currency", "10.000", CurrencyBHD, 3, []string{"3.334", "3.333", "3.333"}},
+		{"single part", "12.34", CurrencyUSD, 1, []string{"12.34"}},
+		{"wei beyond int64", "100", CurrencyETH, 3, []string{"33.333333333333333334", "33.333333333333333333", "33.333333333333333333"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMoney(tt.amount, tt.currency)
+			require.NoError(t, err)
+
+			parts, err := m.Split(tt.n)
+			require.NoError(t, err)
+			assertParts(t, m, tt.expected, parts)
+		})
+	}
+}
+
+func TestMoney_Split_Invalid(t *testing.T) {
+	m, err := NewMoney("10.00", CurrencyUSD)
+	require.NoError(t, err)
+
+	_, err = m.Split(0)
+	assert.Error(t, err)
+
+	_, err = m.Split(-2)
+	assert.Error(t, err)
+
+	tooPrecise, err := NewMoney("10.005", CurrencyUSD)
+	require.NoError(t, err)
+	_, err = tooPrecise.Split(2)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds USD precision")
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency Currency
+		ratios   []string
+		expected []string
+	}{
+		{"70/20/10", "100.00", CurrencyUSD, []string{"70", "20", "10"}, []string{"70.00", "20.00", "10.00"}},
+		{"largest remainder wins", "0.05", CurrencyUSD, []string{"1", "3"}, []string{"0.01", "0.04"}},
+		{"fractional ratios", "10.00", CurrencyUSD, []string{"0.5", "0.25", "0.25"}, []string{"5.00", "2.50", "2.50"}},
+		{"zero ratio gets nothing", "10.00", CurrencyUSD, []string{"1", "0", "1"}, []string{"5.00", "0.00", "5.00"}},
+		{"installments", "1000.00", CurrencySGD, []string{"1", "1", "1", "1", "1", "1", "1"}, []string{"142.86", "142.86", "142.86", "142.86", "142.86", "142.85", "142.85"}},
+		{"negative", "-0.05", CurrencyUSD, []string{"1", "3"}, []string{"-0.01", "-0.04"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMoney(tt.amount, tt.currency)
+			require.NoError(t, err)
+
+			ratios := make([]decimal.Decimal, len(tt.ratios))
+			for i, r := range tt.ratios {
+				ratios[i] = decimal.RequireFromString(r)
+			}
+
+			parts, err := m.Allocate(ratios...)
+			require.NoError(t, err)
+			assertParts(t, m, tt.expected, parts)
+		})
+	}
+}
+
+func TestMoney_Allocate_Invalid(t *testing.T) {
+	m, err := NewMoney("10.00", CurrencyUSD)
+	require.NoError(t, err)
+
+	_, err = m.Allocate()
+	assert.Error(t, err)
+
+	_, err = m.Allocate(decimal.NewFromInt(1), decimal.NewFromInt(-1))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be negative")
+
+	_, err = m.Allocate(decimal.Zero, decimal.Zero)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "sum to more than zero")
+}
+
+func assertParts(t *testing.T, original Money, expected []string, parts []Money) {
+	t.Helper()
+
+	require.Len(t, parts, len(expected))
+
+	total, err := Zero(original.Currency)
+	require.NoError(t, err)
+
+	for i, part := range parts {
+		want, err := NewMoney(expected[i], original.Currency)
+		require.NoError(t, err)
+		assert.True(t, part.Equals(want), "part %d: got %s, want %s", i, part, want)
+
+		total, err = total.Add(part)
+		require.NoError(t, err)
+	}
+
+	assert.True(t, total.Equals(original), "parts sum to %s, want %s", total, original)
+}
@@ -0,0 +1,173 @@
+package money
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Bag holds per-currency subtotals so amounts in different currencies can be
+// aggregated without conversion. Bag is a value type: every operation returns
+// a new Bag and leaves the receiver untouched. Zero subtotals are dropped.
+type Bag struct {
+	amounts map[Currency]decimal.Decimal
+}
+
+// NewBag creates a Bag containing the sum of the given amounts
+func NewBag(amounts ...Money) (Bag, error) {
+	b := Bag{}
+	for _, m := range amounts {
+		var err error
+		b, err = b.Add(m)
+		if err != nil {
+			return Bag{}, err
+		}
+	}
+	return b, nil
+}
+
+// Add returns a new Bag with the amount added to its currency's subtotal
+func (b Bag) Add(m Money) (Bag, error) {
+	if err := m.Currency.Validate(); err != nil {
+		return Bag{}, fmt.Errorf("cannot add to bag: %w", err)
+	}
+
+	result := b.clone()
+	result.set(m.Currency, result.amounts[m.Currency].Add(m.Amount))
+	return result, nil
+}
+
+// Subtract returns a new Bag with the amount subtracted from its currency's subtotal
+func (b Bag) Subtract(m Money) (Bag, error) {
+	return b.Add(m.Negate())
+}
+
+// AddBag returns a new Bag with all subtotals of other added
+func (b Bag) AddBag(other Bag) Bag {
+	result := b.clone()
+	for c, amount := range other.amounts {
+		result.set(c, result.amounts[c].Add(amount))
+	}
+	return result
+}
+
+// SubtractBag returns a new Bag with all subtotals of other subtracted
+func (b Bag) SubtractBag(other Bag) Bag {
+	return b.AddBag(other.Negate())
+}
+
+// Negate returns a new Bag with every subtotal negated
+func (b Bag) Negate() Bag {
+	result := Bag{amounts: make(map[Currency]decimal.Decimal, len(b.amounts))}
+	for c, amount := range b.amounts {
+		result.amounts[c] = amount.Neg()
+	}
+	return result
+}
+
+// Get returns the subtotal for the currency, or zero if the bag holds none
+func (b Bag) Get(currency Currency) Money {
+	return Money{
+		Amount:   b.amounts[currency],
+		Currency: currency,
+	}
+}
+
+// Currencies returns the currencies held in the bag sorted by code
+func (b Bag) Currencies() []Currency {
+	currencies := make([]Currency, 0, len(b.amounts))
+	for c := range b.amounts {
+		currencies = append(currencies, c)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i] < currencies[j] })
+	return currencies
+}
+
+// Amounts returns the subtotals as Money values sorted by currency code
+func (b Bag) Amounts() []Money {
+	currencies := b.Currencies()
+	amounts := make([]Money, len(currencies))
+	for i, c := range currencies {
+		amounts[i] = b.Get(c)
+	}
+	return amounts
+}
+
+// Len returns the number of currencies with a non-zero subtotal
+func (b Bag) Len() int {
+	return len(b.amounts)
+}
+
+// IsZero checks if every subtotal in the bag is zero
+func (b Bag) IsZero() bool {
+	return len(b.amounts) == 0
+}
+
+// Equals checks if two bags hold the same subtotals
+func (b Bag) Equals(other Bag) bool {
+	if len(b.amounts) != len(other.amounts) {
+		return false
+	}
+	for c, amount := range b.amounts {
+		if !amount.Equal(other.amounts[c]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Collapse converts every subtotal into a single currency using the rates effective at asOf
+// and returns their sum. Each subtotal is rounded by the converter before summing.
+func (b Bag) Collapse(ctx context.Context, converter *Converter, to Currency, asOf time.Time) (Money, error) {
+	total, err := Zero(to)
+	if err != nil {
+		return Money{}, err
+	}
+
+	for _, m := range b.Amounts() {
+		converted, err := converter.Convert(ctx, m, to, asOf)
+		if err != nil {
+			return Money{}, fmt.Errorf("failed to collapse %s subtotal: %w", m.Currency, err)
+		}
+
+		total, err = total.Add(converted)
+		if err != nil {
+			return Money{}, fmt.Errorf("failed to collapse %s subtotal: %w", m.Currency, err)
+		}
+	}
+
+	return total, nil
+}
+
+// String returns the subtotals joined with " + ", e.g. "100.00 SGD + 50.00 USD"
+func (b Bag) String() string {
+	if b.IsZero() {
+		return "0"
+	}
+
+	parts := make([]string, 0, len(b.amounts))
+	for _, m := range b.Amounts() {
+		parts = append(parts, m.String())
+	}
+	return strings.Join(parts, " + ")
+}
+
+func (b Bag) clone() Bag {
+	result := Bag{amounts: make(map[Currency]decimal.Decimal, len(b.amounts)+1)}
+	for c, amount := range b.amounts {
+		result.amounts[c] = amount
+	}
+	return result
+}
+
+func (b Bag) set(currency Currency, amount decimal.Decimal) {
+	if amount.IsZero() {
+		delete(b.amounts, currency)
+		return
+	}
+	b.amounts[currency] = amount
+}
@@ -0,0 +1,131 @@
+package money
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBag(t *testing.T) {
+	bag, err := NewBag(
+		mustNewMoney(t, "100.00", CurrencySGD),
+		mustNewMoney(t, "50.25", CurrencyUSD),
+		mustNewMoney(t, "20.00", CurrencySGD),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, bag.Len())
+	assert.Equal(t, []Currency{CurrencySGD, CurrencyUSD}, bag.Currencies())
+	assert.True(t, bag.Get(CurrencySGD).Equals(mustNewMoney(t, "120.00", CurrencySGD)))
+	assert.True(t, bag.Get(CurrencyUSD).Equals(mustNewMoney(t, "50.25", CurrencyUSD)))
+	assert.True(t, bag.Get(CurrencyJPY).IsZero())
+	assert.Equal(t, "120.00 SGD + 50.25 USD", bag.String())
+
+	_, err = NewBag(Money{})
+	assert.Error(t, err)
+}
+
+func TestBag_ValueSemantics(t *testing.T) {
+	original, err := NewBag(mustNewMoney(t, "10.00", CurrencyUSD))
+	require.NoError(t, err)
+
+	added, err := original.Add(mustNewMoney(t, "5.00", CurrencyUSD))
+	require.NoError(t, err)
+
+	assert.True(t, original.Get(CurrencyUSD).Equals(mustNewMoney(t, "10.00", CurrencyUSD)))
+	assert.True(t, added.Get(CurrencyUSD).Equals(mustNewMoney(t, "15.00", CurrencyUSD)))
+
+	var empty Bag
+	withAmount, err := empty.Add(mustNewMoney(t, "1.00", CurrencyEUR))
+	require.NoError(t, err)
+	assert.True(t, empty.IsZero())
+	assert.False(t, withAmount.IsZero())
+}
+
+func TestBag_SubtractAndNegate(t *testing.T) {
+	bag, err := NewBag(
+		mustNewMoney(t, "100.00", CurrencySGD),
+		mustNewMoney(t, "50.00", CurrencyUSD),
+	)
+	require.NoError(t, err)
+
+	bag, err = bag.Subtract(mustNewMoney(t, "50.00", CurrencyUSD))
+	require.NoError(t, err)
+	assert.Equal(t, []Currency{CurrencySGD}, bag.Currencies(), "zero subtotals should be dropped")
+
+	bag, err = bag.Subtract(mustNewMoney(t, "1000", CurrencyJPY))
+	require.NoError(t, err)
+	assert.True(t, bag.Get(CurrencyJPY).Equals(mustNewMoney(t, "-1000", CurrencyJPY)))
+
+	negated := bag.Negate()
+	assert.True(t, negated.Get(CurrencySGD).Equals(mustNewMoney(t, "-100.00", CurrencySGD)))
+	assert.True(t, negated.Get(CurrencyJPY).Equals(mustNewMoney(t, "1000", CurrencyJPY)))
+	assert.True(t, negated.AddBag(bag).IsZero())
+	assert.Equal(t, "0", negated.AddBag(bag).String())
+}
+
+func TestBag_AddBagAndSubtractBag(t *testing.T) {
+	a, err := NewBag(mustNewMoney(t, "10.00", CurrencySGD), mustNewMoney(t, "5.00", CurrencyUSD))
+	require.NoError(t, err)
+	b, err := NewBag(mustNewMoney(t, "2.50", CurrencyUSD), mustNewMoney(t, "7.000", CurrencyBHD))
+	require.NoError(t, err)
+
+	sum := a.AddBag(b)
+	expectedSum, err := NewBag(
+		mustNewMoney(t, "10.00", CurrencySGD),
+		mustNewMoney(t, "7.50", CurrencyUSD),
+		mustNewMoney(t, "7.000", CurrencyBHD),
+	)
+	require.NoError(t, err)
+	assert.True(t, sum.Equals(expectedSum))
+
+	assert.True(t, sum.SubtractBag(b).Equals(a))
+	assert.False(t, a.Equals(b))
+}
+
+func TestBag_Collapse(t *testing.T) {
+	ctx := context.Background()
+	asOf := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	provider, err := NewMemoryRateProvider(
+		mustRate(t, CurrencyUSD, CurrencySGD, "1.35", asOf),
+		mustRate(t, CurrencyUSD, CurrencyJPY, "150", asOf),
+	)
+	require.NoError(t, err)
+
+	converter, err := NewConverter(provider, WithPivotCurrency(CurrencyUSD))
+	require.NoError(t, err)
+
+	bag, err := NewBag(
+		mustNewMoney(t, "100.00", CurrencySGD),
+		mustNewMoney(t, "20.00", CurrencyUSD),
+		mustNewMoney(t, "1500", CurrencyJPY),
+	)
+	require.NoError(t, err)
+
+	total, err := bag.Collapse(ctx, converter, CurrencySGD, asOf)
+	require.NoError(t, err)
+	// 100 SGD + 20 USD * 1.35 + 1500 JPY / 150 * 1.35
+	assert.True(t, total.Equals(mustNewMoney(t, "140.50", CurrencySGD)), "got %s", total)
+
+	empty, err := Bag{}.Collapse(ctx, converter, CurrencyUSD, asOf)
+	require.NoError(t, err)
+	assert.True(t, empty.IsZero())
+	assert.Equal(t, CurrencyUSD, empty.Currency)
+
+	withEUR, err := bag.Add(mustNewMoney(t, "1.00", CurrencyEUR))
+	require.NoError(t, err)
+	_, err = withEUR.Collapse(ctx, converter, CurrencySGD, asOf)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func mustNewMoney(t *testing.T, amount string, currency Currency) Money {
+	t.Helper()
+
+	m, err := NewMoney(amount, currency)
+	require.NoError(t, err)
+	return m
+}
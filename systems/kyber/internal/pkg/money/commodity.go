@@ -0,0 +1,150 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// MaxMinorUnits is the largest precision a commodity can be registered with, enough for tokens
+// counted in 18-decimal base units such as wei. Amounts are persisted as NUMERIC(38,0) minor
+// units, which keeps 10^20 whole units representable at this precision.
+const MaxMinorUnits int32 = 18
+
+// ErrCommodityKindMismatch is returned when arithmetic mixes commodities of different kinds
+var ErrCommodityKindMismatch = errors.New("commodity kind mismatch")
+
+// CommodityKind represents the kind of unit an amount is held in
+type CommodityKind string
+
+// Commodity kind constants
+const (
+	CommodityKindFiat     CommodityKind = "FIAT"     // ISO 4217 currencies
+	CommodityKindCrypto   CommodityKind = "CRYPTO"   // Cryptocurrencies and tokens
+	CommodityKindPoints   CommodityKind = "POINTS"   // Loyalty points and airline miles
+	CommodityKindSecurity CommodityKind = "SECURITY" // Shares, ETF and fund units
+)
+
+// NewCommodityKind creates a new CommodityKind from string
+func NewCommodityKind(kind string) (CommodityKind, error) {
+	switch CommodityKind(kind) {
+	case CommodityKindFiat,
+		CommodityKindCrypto,
+		CommodityKindPoints,
+		CommodityKindSecurity:
+		return CommodityKind(kind), nil
+	default:
+		return "", fmt.Errorf("invalid commodity kind: %s", kind)
+	}
+}
+
+// String returns the string representation of CommodityKind
+func (k CommodityKind) String() string {
+	return string(k)
+}
+
+// IsFiat checks if the kind is an ISO 4217 currency
+func (k CommodityKind) IsFiat() bool {
+	return k == CommodityKindFiat
+}
+
+// commodityCodePattern restricts commodity codes to upper-case tickers such as "BTC" or "KF_MILES"
+var commodityCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._-]{1,15}$`)
+
+// commodityRegistry holds non-ISO commodities; it is guarded by commodityMu
+// since commodities can be registered at runtime
+var (
+	commodityMu       sync.RWMutex
+	commodityRegistry = map[Currency]CurrencyInfo{
+		CurrencyBTC: {Code: CurrencyBTC, Kind: CommodityKindCrypto, MinorUnits: 8, Symbol: "₿", Name: "Bitcoin"},
+		CurrencyETH: {Code: CurrencyETH, Kind: CommodityKindCrypto, MinorUnits: 18, Symbol: "Ξ", Name: "Ether"}, // Wei
+	}
+)
+
+// RegisterCommodity registers a non-fiat commodity such as a token, a loyalty programme's
+// points or a security, making its code usable as a Currency. Registering an existing
+// commodity code replaces it; ISO 4217 codes cannot be registered.
+//
+// The registry is held in memory only: callers persist commodities and register them
+// again on startup, before amounts in them are decoded.
+func RegisterCommodity(info CurrencyInfo) error {
+	info, err := ValidateCommodity(info)
+	if err != nil {
+		return err
+	}
+
+	commodityMu.Lock()
+	defer commodityMu.Unlock()
+
+	commodityRegistry[info.Code] = info
+	return nil
+}
+
+// ValidateCommodity checks that the commodity can be registered and returns it with its code normalised
+func ValidateCommodity(info CurrencyInfo) (CurrencyInfo, error) {
+	info.Code = Currency(strings.ToUpper(strings.TrimSpace(string(info.Code))))
+	if !commodityCodePattern.MatchString(string(info.Code)) {
+		return CurrencyInfo{}, fmt.Errorf("invalid commodity code: %q", info.Code)
+	}
+
+	if _, ok := currencyRegistry[info.Code]; ok {
+		return CurrencyInfo{}, fmt.Errorf("cannot register ISO 4217 currency %s as a commodity", info.Code)
+	}
+
+	if _, err := NewCommodityKind(string(info.Kind)); err != nil {
+		return CurrencyInfo{}, err
+	}
+	if info.Kind.IsFiat() {
+		return CurrencyInfo{}, fmt.Errorf("fiat currencies are limited to ISO 4217 codes: %s", info.Code)
+	}
+
+	if info.MinorUnits < 0 || info.MinorUnits > MaxMinorUnits {
+		return CurrencyInfo{}, fmt.Errorf("commodity minor units must be between 0 and %d: %d", MaxMinorUnits, info.MinorUnits)
+	}
+
+	if strings.TrimSpace(info.Name) == "" {
+		return CurrencyInfo{}, fmt.Errorf("commodity name cannot be empty")
+	}
+
+	return info, nil
+}
+
+// Kind returns the commodity kind of the currency, or an empty kind if unregistered
+func (c Currency) Kind() CommodityKind {
+	info, _ := LookupCurrency(c)
+	return info.Kind
+}
+
+func lookupCommodity(c Currency) (CurrencyInfo, bool) {
+	commodityMu.RLock()
+	defer commodityMu.RUnlock()
+
+	info, ok := commodityRegistry[c]
+	return info, ok
+}
+
+func allCommodities() []CurrencyInfo {
+	commodityMu.RLock()
+	defer commodityMu.RUnlock()
+
+	all := make([]CurrencyInfo, 0, len(commodityRegistry))
+	for _, info := range commodityRegistry {
+		all = append(all, info)
+	}
+	return all
+}
+
+// checkSameUnit verifies that two amounts can be combined by the named operation
+func checkSameUnit(op string, a, b Currency) error {
+	if a == b {
+		return nil
+	}
+
+	if ka, kb := a.Kind(), b.Kind(); ka != "" && kb != "" && ka != kb {
+		return fmt.Errorf("%w: cannot %s %s (%s) and %s (%s)", ErrCommodityKindMismatch, op, a, ka, b, kb)
+	}
+
+	return fmt.Errorf("cannot %s different currencies: %s and %s", op, a, b)
+}
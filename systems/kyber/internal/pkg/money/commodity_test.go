@@ -0,0 +1,161 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCommodityKind(t *testing.T) {
+	for _, kind := range []CommodityKind{CommodityKindFiat, CommodityKindCrypto, CommodityKindPoints, CommodityKindSecurity} {
+		parsed, err := NewCommodityKind(string(kind))
+		require.NoError(t, err)
+		assert.Equal(t, kind, parsed)
+		assert.Equal(t, string(kind), parsed.String())
+	}
+
+	_, err := NewCommodityKind("STOCK")
+	assert.Error(t, err)
+
+	assert.True(t, CommodityKindFiat.IsFiat())
+	assert.False(t, CommodityKindCrypto.IsFiat())
+}
+
+func TestCurrency_Kind(t *testing.T) {
+	assert.Equal(t, CommodityKindFiat, CurrencyUSD.Kind())
+	assert.Equal(t, CommodityKindCrypto, CurrencyBTC.Kind())
+	assert.Equal(t, CommodityKind(""), Currency("XYZ").Kind())
+
+	assert.Equal(t, int32(8), CurrencyBTC.MinorUnits())
+	assert.Equal(t, int32(18), CurrencyETH.MinorUnits())
+	assert.Equal(t, "₿", CurrencyBTC.Symbol())
+	assert.Empty(t, CurrencyBTC.NumericCode())
+}
+
+func TestRegisterCommodity(t *testing.T) {
+	tests := []struct {
+		name        string
+		info        CurrencyInfo
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "airline miles",
+			info: CurrencyInfo{Code: "kf_miles", Kind: CommodityKindPoints, MinorUnits: 0, Name: "KrisFlyer Miles"},
+		},
+		{
+			name: "ETF units",
+			info: CurrencyInfo{Code: "VWRA", Kind: CommodityKindSecurity, MinorUnits: 4, Name: "Vanguard FTSE All-World"},
+		},
+		{
+			name:        "ISO code",
+			info:        CurrencyInfo{Code: "USD", Kind: CommodityKindCrypto, MinorUnits: 2, Name: "Fake Dollar"},
+			wantErr:     true,
+			errContains: "cannot register ISO 4217 currency",
+		},
+		{
+			name:        "fiat kind",
+			info:        CurrencyInfo{Code: "XXD", Kind: CommodityKindFiat, MinorUnits: 2, Name: "Made-up Dollar"},
+			wantErr:     true,
+			errContains: "fiat currencies are limited to ISO 4217 codes",
+		},
+		{
+			name:        "invalid kind",
+			info:        CurrencyInfo{Code: "GOLD", Kind: "METAL", MinorUnits: 3, Name: "Gold"},
+			wantErr:     true,
+			errContains: "invalid commodity kind",
+		},
+		{
+			name:        "precision too high",
+			info:        CurrencyInfo{Code: "TOKEN", Kind: CommodityKindCrypto, MinorUnits: 19, Name: "Token"},
+			wantErr:     true,
+			errContains: "minor units must be between 0 and 18",
+		},
+		{
+			name:        "invalid code",
+			info:        CurrencyInfo{Code: "A B", Kind: CommodityKindCrypto, MinorUnits: 2, Name: "Token"},
+			wantErr:     true,
+			errContains: "invalid commodity code",
+		},
+		{
+			name:        "empty name",
+			info:        CurrencyInfo{Code: "TOKEN", Kind: CommodityKindCrypto, MinorUnits: 2},
+			wantErr:     true,
+			errContains: "commodity name cannot be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterCommodity(tt.info)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			c, err := NewCurrency(string(tt.info.Code))
+			require.NoError(t, err)
+			assert.Equal(t, tt.info.Kind, c.Kind())
+			assert.Equal(t, tt.info.MinorUnits, c.MinorUnits())
+		})
+	}
+}
+
+func TestMoney_CommodityPrecision(t *testing.T) {
+	btc, err := NewMoney("0.00012345", CurrencyBTC)
+	require.NoError(t, err)
+	require.NoError(t, btc.ValidatePrecision())
+	assert.Equal(t, "0.00012345 BTC", btc.String())
+
+	units, err := btc.ToMinorUnits()
+	require.NoError(t, err)
+	assert.Equal(t, int64(12345), units)
+
+	eth, err := NewMoney("1000000000.000000000000000001", CurrencyETH)
+	require.NoError(t, err)
+	require.NoError(t, eth.ValidatePrecision())
+
+	wei, err := eth.ToMinorUnitsDecimal()
+	require.NoError(t, err, "billions of ether are whole wei beyond int64")
+	assert.Equal(t, "1000000000000000000000000001", wei.String())
+
+	_, err = eth.ToMinorUnits()
+	assert.ErrorContains(t, err, "overflows int64")
+
+	tooPrecise, err := NewMoney("0.000000001", CurrencyBTC)
+	require.NoError(t, err)
+	assert.Error(t, tooPrecise.ValidatePrecision())
+}
+
+func TestMoney_MixedCommodityKinds(t *testing.T) {
+	usd := mustNewMoney(t, "10.00", CurrencyUSD)
+	sgd := mustNewMoney(t, "10.00", CurrencySGD)
+	btc := mustNewMoney(t, "0.5", CurrencyBTC)
+	eth := mustNewMoney(t, "0.5", CurrencyETH)
+
+	_, err := usd.Add(btc)
+	assert.ErrorIs(t, err, ErrCommodityKindMismatch)
+	assert.Contains(t, err.Error(), "cannot add USD (FIAT) and BTC (CRYPTO)")
+
+	_, err = btc.Subtract(usd)
+	assert.ErrorIs(t, err, ErrCommodityKindMismatch)
+
+	_, err = usd.Compare(btc)
+	assert.ErrorIs(t, err, ErrCommodityKindMismatch)
+
+	_, err = RatioOf(btc, usd)
+	assert.ErrorIs(t, err, ErrCommodityKindMismatch)
+
+	// Same kind, different unit is still rejected, but not as a kind mismatch
+	_, err = btc.Add(eth)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCommodityKindMismatch)
+	assert.Contains(t, err.Error(), "cannot add different currencies")
+
+	_, err = usd.Add(sgd)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCommodityKindMismatch)
+}
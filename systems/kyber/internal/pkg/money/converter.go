@@ -0,0 +1,200 @@
+package money
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Converter converts Money between currencies using a RateProvider.
+// Lookups fall back from the direct rate to the inverse rate, and finally to a
+// triangulated rate via the pivot currency when one is configured.
+type Converter struct {
+	provider RateProvider
+	options  *converterOptions
+}
+
+// roundingPolicy determines how converted amounts are rounded
+type roundingPolicy int
+
+const (
+	roundToCurrency roundingPolicy = iota
+	roundToPlaces
+	roundNone
+)
+
+// converterOptions holds configuration for the Converter (unexported)
+type converterOptions struct {
+	pivot    Currency
+	rounding roundingPolicy
+	places   int32
+	mode     RoundingMode
+}
+
+// ConverterOption is a function that configures converter options
+type ConverterOption func(*converterOptions)
+
+// WithPivotCurrency sets the currency used to triangulate cross rates
+// when neither a direct nor an inverse rate is available
+func WithPivotCurrency(pivot Currency) ConverterOption {
+	return func(o *converterOptions) {
+		o.pivot = pivot
+	}
+}
+
+// WithCurrencyRounding rounds converted amounts to the target currency's minor units (default)
+func WithCurrencyRounding() ConverterOption {
+	return func(o *converterOptions) {
+		o.rounding = roundToCurrency
+	}
+}
+
+// WithRoundingPlaces rounds converted amounts to a fixed number of decimal places
+func WithRoundingPlaces(places int32) ConverterOption {
+	return func(o *converterOptions) {
+		o.rounding = roundToPlaces
+		o.places = places
+	}
+}
+
+// WithRoundingMode sets how converted amounts are rounded (half up by default)
+func WithRoundingMode(mode RoundingMode) ConverterOption {
+	return func(o *converterOptions) {
+		o.mode = mode
+	}
+}
+
+// WithoutRounding keeps the full precision of converted amounts
+func WithoutRounding() ConverterOption {
+	return func(o *converterOptions) {
+		o.rounding = roundNone
+	}
+}
+
+func defaultConverterOptions() *converterOptions {
+	return &converterOptions{
+		rounding: roundToCurrency,
+		mode:     RoundHalfUp,
+	}
+}
+
+// NewConverter creates a new Converter backed by the given RateProvider
+func NewConverter(provider RateProvider, opts ...ConverterOption) (*Converter, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("rate provider cannot be nil")
+	}
+
+	options := defaultConverterOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.pivot != "" {
+		if err := options.pivot.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid pivot currency: %w", err)
+		}
+	}
+
+	if options.rounding == roundToPlaces && options.places < 0 {
+		return nil, fmt.Errorf("rounding places cannot be negative: %d", options.places)
+	}
+
+	if !options.mode.IsValid() {
+		return nil, fmt.Errorf("invalid rounding mode: %s", options.mode)
+	}
+
+	return &Converter{
+		provider: provider,
+		options:  options,
+	}, nil
+}
+
+// Convert converts the amount into the target currency using the rate effective at asOf
+func (c *Converter) Convert(ctx context.Context, m Money, to Currency, asOf time.Time) (Money, error) {
+	if err := to.Validate(); err != nil {
+		return Money{}, fmt.Errorf("invalid target currency: %w", err)
+	}
+
+	if m.Currency == to {
+		return m, nil
+	}
+
+	rate, err := c.Rate(ctx, m.Currency, to, asOf)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return c.round(Money{
+		Amount:   m.Amount.Mul(rate.Value),
+		Currency: to,
+	}), nil
+}
+
+// Rate resolves the effective rate for from→to at asOf, trying the direct rate,
+// then the inverse rate, then triangulation via the pivot currency
+func (c *Converter) Rate(ctx context.Context, from, to Currency, asOf time.Time) (Rate, error) {
+	rate, err := c.directOrInverse(ctx, from, to, asOf)
+	if err == nil || !errors.Is(err, ErrRateNotFound) {
+		return rate, err
+	}
+
+	pivot := c.options.pivot
+	if pivot == "" || pivot == from || pivot == to {
+		return Rate{}, err
+	}
+
+	first, err := c.directOrInverse(ctx, from, pivot, asOf)
+	if err != nil {
+		return Rate{}, fmt.Errorf("failed to triangulate %s/%s via %s: %w", from, to, pivot, err)
+	}
+
+	second, err := c.directOrInverse(ctx, pivot, to, asOf)
+	if err != nil {
+		return Rate{}, fmt.Errorf("failed to triangulate %s/%s via %s: %w", from, to, pivot, err)
+	}
+
+	// The cross rate is only as fresh as the older of its two legs
+	date := first.Date
+	if second.Date.Before(date) {
+		date = second.Date
+	}
+
+	return Rate{
+		From:  from,
+		To:    to,
+		Value: first.Value.Mul(second.Value),
+		Date:  date,
+	}, nil
+}
+
+func (c *Converter) directOrInverse(ctx context.Context, from, to Currency, asOf time.Time) (Rate, error) {
+	rate, err := c.provider.Rate(ctx, from, to, asOf)
+	if err == nil {
+		return rate, nil
+	}
+	if !errors.Is(err, ErrRateNotFound) {
+		return Rate{}, fmt.Errorf("failed to get %s/%s rate: %w", from, to, err)
+	}
+
+	inverse, err := c.provider.Rate(ctx, to, from, asOf)
+	if err == nil {
+		return inverse.Inverse(), nil
+	}
+	if !errors.Is(err, ErrRateNotFound) {
+		return Rate{}, fmt.Errorf("failed to get %s/%s rate: %w", to, from, err)
+	}
+
+	return Rate{}, fmt.Errorf("%w: %s/%s as of %s", ErrRateNotFound, from, to, asOf.Format(time.DateOnly))
+}
+
+func (c *Converter) round(m Money) Money {
+	switch c.options.rounding {
+	case roundToPlaces:
+		return m.RoundWithMode(c.options.places, c.options.mode)
+	case roundNone:
+		return m
+	default:
+		return m.RoundCurrencyWithMode(c.options.mode)
+	}
+}
@@ -0,0 +1,184 @@
+package money
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConverter(t *testing.T) {
+	provider, err := NewMemoryRateProvider()
+	require.NoError(t, err)
+
+	_, err = NewConverter(nil)
+	assert.Error(t, err)
+
+	_, err = NewConverter(provider, WithPivotCurrency("XYZ"))
+	assert.Error(t, err)
+
+	_, err = NewConverter(provider, WithRoundingPlaces(-1))
+	assert.Error(t, err)
+
+	_, err = NewConverter(provider, WithRoundingMode(RoundingMode(99)))
+	assert.Error(t, err)
+
+	c, err := NewConverter(provider, WithPivotCurrency(CurrencyUSD))
+	require.NoError(t, err)
+	assert.NotNil(t, c)
+}
+
+func TestConverter_Convert(t *testing.T) {
+	ctx := context.Background()
+	jan := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+
+	provider, err := NewMemoryRateProvider(
+		mustRate(t, CurrencyUSD, CurrencySGD, "1.3412", jan),
+		mustRate(t, CurrencyUSD, CurrencySGD, "1.3455", feb),
+		mustRate(t, CurrencyUSD, CurrencyJPY, "147.25", jan),
+		mustRate(t, CurrencyEUR, CurrencyUSD, "1.08", jan),
+	)
+	require.NoError(t, err)
+
+	converter, err := NewConverter(provider, WithPivotCurrency(CurrencyUSD))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		amount   string
+		from     Currency
+		to       Currency
+		asOf     time.Time
+		expected string
+	}{
+		{"same currency", "10.00", CurrencySGD, CurrencySGD, jan, "10.00"},
+		{"direct rate", "100.00", CurrencyUSD, CurrencySGD, jan, "134.12"},
+		{"historical rate by date", "100.00", CurrencyUSD, CurrencySGD, feb.AddDate(0, 0, 1), "134.55"},
+		{"inverse rate", "134.12", CurrencySGD, CurrencyUSD, jan, "100.00"},
+		{"rounds to target currency precision", "10.00", CurrencyUSD, CurrencyJPY, jan, "1473"},
+		{"triangulated via pivot", "100.00", CurrencyEUR, CurrencySGD, jan, "144.85"},
+		{"triangulated with inverse leg", "144.85", CurrencySGD, CurrencyEUR, jan, "100.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMoney(tt.amount, tt.from)
+			require.NoError(t, err)
+
+			converted, err := converter.Convert(ctx, m, tt.to, tt.asOf)
+			require.NoError(t, err)
+
+			expected, err := NewMoney(tt.expected, tt.to)
+			require.NoError(t, err)
+			assert.True(t, converted.Equals(expected), "got %s, want %s", converted, expected)
+		})
+	}
+}
+
+func TestConverter_Rate_Triangulated(t *testing.T) {
+	ctx := context.Background()
+	jan := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+
+	provider, err := NewMemoryRateProvider(
+		mustRate(t, CurrencyUSD, CurrencySGD, "1.35", feb),
+		mustRate(t, CurrencyEUR, CurrencyUSD, "1.08", jan),
+	)
+	require.NoError(t, err)
+
+	converter, err := NewConverter(provider, WithPivotCurrency(CurrencyUSD))
+	require.NoError(t, err)
+
+	rate, err := converter.Rate(ctx, CurrencyEUR, CurrencySGD, feb)
+	require.NoError(t, err)
+	assert.True(t, rate.Value.Equal(decimal.RequireFromString("1.458")))
+	assert.Equal(t, jan, rate.Date, "cross rate should carry the older leg's date")
+}
+
+func TestConverter_Errors(t *testing.T) {
+	ctx := context.Background()
+	jan := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	provider, err := NewMemoryRateProvider(mustRate(t, CurrencyUSD, CurrencySGD, "1.34", jan))
+	require.NoError(t, err)
+
+	m, err := NewMoney("10.00", CurrencyEUR)
+	require.NoError(t, err)
+
+	// No pivot configured, so EUR→SGD cannot be resolved
+	noPivot, err := NewConverter(provider)
+	require.NoError(t, err)
+	_, err = noPivot.Convert(ctx, m, CurrencySGD, jan)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+
+	// Pivot configured but EUR→USD leg missing
+	withPivot, err := NewConverter(provider, WithPivotCurrency(CurrencyUSD))
+	require.NoError(t, err)
+	_, err = withPivot.Convert(ctx, m, CurrencySGD, jan)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+	assert.Contains(t, err.Error(), "triangulate")
+
+	// Rate requested before any rate exists
+	usd, err := NewMoney("10.00", CurrencyUSD)
+	require.NoError(t, err)
+	_, err = withPivot.Convert(ctx, usd, CurrencySGD, jan.AddDate(0, 0, -1))
+	assert.ErrorIs(t, err, ErrRateNotFound)
+
+	// Invalid target currency
+	_, err = withPivot.Convert(ctx, usd, "XYZ", jan)
+	assert.Error(t, err)
+
+	// Provider failures other than not-found are surfaced as-is
+	failing, err := NewConverter(failingRateProvider{})
+	require.NoError(t, err)
+	_, err = failing.Convert(ctx, usd, CurrencySGD, jan)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrRateNotFound)
+	assert.Contains(t, err.Error(), "provider unavailable")
+}
+
+func TestConverter_Rounding(t *testing.T) {
+	ctx := context.Background()
+	jan := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	provider, err := NewMemoryRateProvider(mustRate(t, CurrencyUSD, CurrencySGD, "1.341278", jan))
+	require.NoError(t, err)
+
+	m, err := NewMoney("10.00", CurrencyUSD)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		opts     []ConverterOption
+		expected string
+	}{
+		{"currency rounding by default", nil, "13.41"},
+		{"fixed places", []ConverterOption{WithRoundingPlaces(4)}, "13.4128"},
+		{"no rounding", []ConverterOption{WithoutRounding()}, "13.41278"},
+		{"explicit currency rounding", []ConverterOption{WithoutRounding(), WithCurrencyRounding()}, "13.41"},
+		{"ceiling mode", []ConverterOption{WithRoundingMode(RoundCeiling)}, "13.42"},
+		{"floor mode with fixed places", []ConverterOption{WithRoundingPlaces(4), WithRoundingMode(RoundFloor)}, "13.4127"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := NewConverter(provider, tt.opts...)
+			require.NoError(t, err)
+
+			converted, err := converter.Convert(ctx, m, CurrencySGD, jan)
+			require.NoError(t, err)
+			assert.True(t, converted.Amount.Equal(decimal.RequireFromString(tt.expected)), "got %s", converted.Amount)
+		})
+	}
+}
+
+type failingRateProvider struct{}
+
+func (failingRateProvider) Rate(context.Context, Currency, Currency, time.Time) (Rate, error) {
+	return Rate{}, errors.New("provider unavailable")
+}
@@ -0,0 +1,288 @@
+package money
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Currency represents an ISO 4217 alphabetic currency code or a registered
+// non-ISO commodity code such as BTC, airline miles or ETF units
+type Currency string
+
+// Commonly used currency constants
+const (
+	// CurrencyUSD represents US Dollar
+	CurrencyUSD Currency = "USD"
+	// CurrencySGD represents Singapore Dollar
+	CurrencySGD Currency = "SGD"
+	// CurrencyEUR represents Euro
+	CurrencyEUR Currency = "EUR"
+	// CurrencyGBP represents Pound Sterling
+	CurrencyGBP Currency = "GBP"
+	// CurrencyJPY represents Japanese Yen
+	CurrencyJPY Currency = "JPY"
+	// CurrencyKRW represents South Korean Won
+	CurrencyKRW Currency = "KRW"
+	// CurrencyBHD represents Bahraini Dinar
+	CurrencyBHD Currency = "BHD"
+	// CurrencyKWD represents Kuwaiti Dinar
+	CurrencyKWD Currency = "KWD"
+
+	// CurrencyBTC represents Bitcoin
+	CurrencyBTC Currency = "BTC"
+	// CurrencyETH represents Ether
+	CurrencyETH Currency = "ETH"
+)
+
+// defaultMinorUnits is used for codes that are not present in the registry
+const defaultMinorUnits int32 = 2
+
+// CurrencyInfo describes an ISO 4217 currency or a registered commodity
+type CurrencyInfo struct {
+	Code        Currency
+	Kind        CommodityKind // Fiat for ISO 4217 currencies
+	NumericCode string        // ISO 4217 three-digit numeric code, empty for non-fiat commodities
+	MinorUnits  int32         // Number of decimal places (exponent) of the minor unit
+	Symbol      string        // Display symbol, empty if none is commonly used
+	Name        string
+}
+
+// currencyRegistry holds the active ISO 4217 currencies keyed by alphabetic code
+var currencyRegistry = map[Currency]CurrencyInfo{
+	"AED": {Code: "AED", NumericCode: "784", MinorUnits: 2, Symbol: "د.إ", Name: "UAE Dirham"},
+	"AFN": {Code: "AFN", NumericCode: "971", MinorUnits: 2, Symbol: "؋", Name: "Afghani"},
+	"ALL": {Code: "ALL", NumericCode: "008", MinorUnits: 2, Name: "Lek"},
+	"AMD": {Code: "AMD", NumericCode: "051", MinorUnits: 2, Symbol: "֏", Name: "Armenian Dram"},
+	"ANG": {Code: "ANG", NumericCode: "532", MinorUnits: 2, Name: "Netherlands Antillean Guilder"},
+	"AOA": {Code: "AOA", NumericCode: "973", MinorUnits: 2, Name: "Kwanza"},
+	"ARS": {Code: "ARS", NumericCode: "032", MinorUnits: 2, Name: "Argentine Peso"},
+	"AUD": {Code: "AUD", NumericCode: "036", MinorUnits: 2, Symbol: "A$", Name: "Australian Dollar"},
+	"AWG": {Code: "AWG", NumericCode: "533", MinorUnits: 2, Name: "Aruban Florin"},
+	"AZN": {Code: "AZN", NumericCode: "944", MinorUnits: 2, Symbol: "₼", Name: "Azerbaijan Manat"},
+	"BAM": {Code: "BAM", NumericCode: "977", MinorUnits: 2, Name: "Convertible Mark"},
+	"BBD": {Code: "BBD", NumericCode: "052", MinorUnits: 2, Name: "Barbados Dollar"},
+	"BDT": {Code: "BDT", NumericCode: "050", MinorUnits: 2, Symbol: "৳", Name: "Taka"},
+	"BGN": {Code: "BGN", NumericCode: "975", MinorUnits: 2, Name: "Bulgarian Lev"},
+	"BHD": {Code: "BHD", NumericCode: "048", MinorUnits: 3, Name: "Bahraini Dinar"},
+	"BIF": {Code: "BIF", NumericCode: "108", MinorUnits: 0, Name: "Burundi Franc"},
+	"BMD": {Code: "BMD", NumericCode: "060", MinorUnits: 2, Name: "Bermudian Dollar"},
+	"BND": {Code: "BND", NumericCode: "096", MinorUnits: 2, Symbol: "B$", Name: "Brunei Dollar"},
+	"BOB": {Code: "BOB", NumericCode: "068", MinorUnits: 2, Name: "Boliviano"},
+	"BRL": {Code: "BRL", NumericCode: "986", MinorUnits: 2, Symbol: "R$", Name: "Brazilian Real"},
+	"BSD": {Code: "BSD", NumericCode: "044", MinorUnits: 2, Name: "Bahamian Dollar"},
+	"BTN": {Code: "BTN", NumericCode: "064", MinorUnits: 2, Name: "Ngultrum"},
+	"BWP": {Code: "BWP", NumericCode: "072", MinorUnits: 2, Name: "Pula"},
+	"BYN": {Code: "BYN", NumericCode: "933", MinorUnits: 2, Name: "Belarusian Ruble"},
+	"BZD": {Code: "BZD", NumericCode: "084", MinorUnits: 2, Name: "Belize Dollar"},
+	"CAD": {Code: "CAD", NumericCode: "124", MinorUnits: 2, Symbol: "C$", Name: "Canadian Dollar"},
+	"CDF": {Code: "CDF", NumericCode: "976", MinorUnits: 2, Name: "Congolese Franc"},
+	"CHF": {Code: "CHF", NumericCode: "756", MinorUnits: 2, Name: "Swiss Franc"},
+	"CLP": {Code: "CLP", NumericCode: "152", MinorUnits: 0, Name: "Chilean Peso"},
+	"CNY": {Code: "CNY", NumericCode: "156", MinorUnits: 2, Symbol: "CN¥", Name: "Yuan Renminbi"},
+	"COP": {Code: "COP", NumericCode: "170", MinorUnits: 2, Name: "Colombian Peso"},
+	"CRC": {Code: "CRC", NumericCode: "188", MinorUnits: 2, Symbol: "₡", Name: "Costa Rican Colon"},
+	"CUP": {Code: "CUP", NumericCode: "192", MinorUnits: 2, Name: "Cuban Peso"},
+	"CVE": {Code: "CVE", NumericCode: "132", MinorUnits: 2, Name: "Cabo Verde Escudo"},
+	"CZK": {Code: "CZK", NumericCode: "203", MinorUnits: 2, Symbol: "Kč", Name: "Czech Koruna"},
+	"DJF": {Code: "DJF", NumericCode: "262", MinorUnits: 0, Name: "Djibouti Franc"},
+	"DKK": {Code: "DKK", NumericCode: "208", MinorUnits: 2, Name: "Danish Krone"},
+	"DOP": {Code: "DOP", NumericCode: "214", MinorUnits: 2, Name: "Dominican Peso"},
+	"DZD": {Code: "DZD", NumericCode: "012", MinorUnits: 2, Name: "Algerian Dinar"},
+	"EGP": {Code: "EGP", NumericCode: "818", MinorUnits: 2, Name: "Egyptian Pound"},
+	"ERN": {Code: "ERN", NumericCode: "232", MinorUnits: 2, Name: "Nakfa"},
+	"ETB": {Code: "ETB", NumericCode: "230", MinorUnits: 2, Name: "Ethiopian Birr"},
+	"EUR": {Code: "EUR", NumericCode: "978", MinorUnits: 2, Symbol: "€", Name: "Euro"},
+	"FJD": {Code: "FJD", NumericCode: "242", MinorUnits: 2, Name: "Fiji Dollar"},
+	"FKP": {Code: "FKP", NumericCode: "238", MinorUnits: 2, Name: "Falkland Islands Pound"},
+	"GBP": {Code: "GBP", NumericCode: "826", MinorUnits: 2, Symbol: "£", Name: "Pound Sterling"},
+	"GEL": {Code: "GEL", NumericCode: "981", MinorUnits: 2, Symbol: "₾", Name: "Lari"},
+	"GHS": {Code: "GHS", NumericCode: "936", MinorUnits: 2, Symbol: "₵", Name: "Ghana Cedi"},
+	"GIP": {Code: "GIP", NumericCode: "292", MinorUnits: 2, Name: "Gibraltar Pound"},
+	"GMD": {Code: "GMD", NumericCode: "270", MinorUnits: 2, Name: "Dalasi"},
+	"GNF": {Code: "GNF", NumericCode: "324", MinorUnits: 0, Name: "Guinean Franc"},
+	"GTQ": {Code: "GTQ", NumericCode: "320", MinorUnits: 2, Name: "Quetzal"},
+	"GYD": {Code: "GYD", NumericCode: "328", MinorUnits: 2, Name: "Guyana Dollar"},
+	"HKD": {Code: "HKD", NumericCode: "344", MinorUnits: 2, Symbol: "HK$", Name: "Hong Kong Dollar"},
+	"HNL": {Code: "HNL", NumericCode: "340", MinorUnits: 2, Name: "Lempira"},
+	"HTG": {Code: "HTG", NumericCode: "332", MinorUnits: 2, Name: "Gourde"},
+	"HUF": {Code: "HUF", NumericCode: "348", MinorUnits: 2, Symbol: "Ft", Name: "Forint"},
+	"IDR": {Code: "IDR", NumericCode: "360", MinorUnits: 2, Symbol: "Rp", Name: "Rupiah"},
+	"ILS": {Code: "ILS", NumericCode: "376", MinorUnits: 2, Symbol: "₪", Name: "New Israeli Sheqel"},
+	"INR": {Code: "INR", NumericCode: "356", MinorUnits: 2, Symbol: "₹", Name: "Indian Rupee"},
+	"IQD": {Code: "IQD", NumericCode: "368", MinorUnits: 3, Name: "Iraqi Dinar"},
+	"IRR": {Code: "IRR", NumericCode: "364", MinorUnits: 2, Name: "Iranian Rial"},
+	"ISK": {Code: "ISK", NumericCode: "352", MinorUnits: 0, Name: "Iceland Krona"},
+	"JMD": {Code: "JMD", NumericCode: "388", MinorUnits: 2, Name: "Jamaican Dollar"},
+	"JOD": {Code: "JOD", NumericCode: "400", MinorUnits: 3, Name: "Jordanian Dinar"},
+	"JPY": {Code: "JPY", NumericCode: "392", MinorUnits: 0, Symbol: "¥", Name: "Yen"},
+	"KES": {Code: "KES", NumericCode: "404", MinorUnits: 2, Name: "Kenyan Shilling"},
+	"KGS": {Code: "KGS", NumericCode: "417", MinorUnits: 2, Name: "Som"},
+	"KHR": {Code: "KHR", NumericCode: "116", MinorUnits: 2, Symbol: "៛", Name: "Riel"},
+	"KMF": {Code: "KMF", NumericCode: "174", MinorUnits: 0, Name: "Comorian Franc"},
+	"KPW": {Code: "KPW", NumericCode: "408", MinorUnits: 2, Name: "North Korean Won"},
+	"KRW": {Code: "KRW", NumericCode: "410", MinorUnits: 0, Symbol: "₩", Name: "Won"},
+	"KWD": {Code: "KWD", NumericCode: "414", MinorUnits: 3, Name: "Kuwaiti Dinar"},
+	"KYD": {Code: "KYD", NumericCode: "136", MinorUnits: 2, Name: "Cayman Islands Dollar"},
+	"KZT": {Code: "KZT", NumericCode: "398", MinorUnits: 2, Symbol: "₸", Name: "Tenge"},
+	"LAK": {Code: "LAK", NumericCode: "418", MinorUnits: 2, Symbol: "₭", Name: "Lao Kip"},
+	"LBP": {Code: "LBP", NumericCode: "422", MinorUnits: 2, Name: "Lebanese Pound"},
+	"LKR": {Code: "LKR", NumericCode: "144", MinorUnits: 2, Name: "Sri Lanka Rupee"},
+	"LRD": {Code: "LRD", NumericCode: "430", MinorUnits: 2, Name: "Liberian Dollar"},
+	"LSL": {Code: "LSL", NumericCode: "426", MinorUnits: 2, Name: "Loti"},
+	"LYD": {Code: "LYD", NumericCode: "434", MinorUnits: 3, Name: "Libyan Dinar"},
+	"MAD": {Code: "MAD", NumericCode: "504", MinorUnits: 2, Name: "Moroccan Dirham"},
+	"MDL": {Code: "MDL", NumericCode: "498", MinorUnits: 2, Name: "Moldovan Leu"},
+	"MGA": {Code: "MGA", NumericCode: "969", MinorUnits: 2, Name: "Malagasy Ariary"},
+	"MKD": {Code: "MKD", NumericCode: "807", MinorUnits: 2, Name: "Denar"},
+	"MMK": {Code: "MMK", NumericCode: "104", MinorUnits: 2, Name: "Kyat"},
+	"MNT": {Code: "MNT", NumericCode: "496", MinorUnits: 2, Symbol: "₮", Name: "Tugrik"},
+	"MOP": {Code: "MOP", NumericCode: "446", MinorUnits: 2, Name: "Pataca"},
+	"MRU": {Code: "MRU", NumericCode: "929", MinorUnits: 2, Name: "Ouguiya"},
+	"MUR": {Code: "MUR", NumericCode: "480", MinorUnits: 2, Name: "Mauritius Rupee"},
+	"MVR": {Code: "MVR", NumericCode: "462", MinorUnits: 2, Name: "Rufiyaa"},
+	"MWK": {Code: "MWK", NumericCode: "454", MinorUnits: 2, Name: "Malawi Kwacha"},
+	"MXN": {Code: "MXN", NumericCode: "484", MinorUnits: 2, Symbol: "MX$", Name: "Mexican Peso"},
+	"MYR": {Code: "MYR", NumericCode: "458", MinorUnits: 2, Symbol: "RM", Name: "Malaysian Ringgit"},
+	"MZN": {Code: "MZN", NumericCode: "943", MinorUnits: 2, Name: "Mozambique Metical"},
+	"NAD": {Code: "NAD", NumericCode: "516", MinorUnits: 2, Name: "Namibia Dollar"},
+	"NGN": {Code: "NGN", NumericCode: "566", MinorUnits: 2, Symbol: "₦", Name: "Naira"},
+	"NIO": {Code: "NIO", NumericCode: "558", MinorUnits: 2, Name: "Cordoba Oro"},
+	"NOK": {Code: "NOK", NumericCode: "578", MinorUnits: 2, Name: "Norwegian Krone"},
+	"NPR": {Code: "NPR", NumericCode: "524", MinorUnits: 2, Name: "Nepalese Rupee"},
+	"NZD": {Code: "NZD", NumericCode: "554", MinorUnits: 2, Symbol: "NZ$", Name: "New Zealand Dollar"},
+	"OMR": {Code: "OMR", NumericCode: "512", MinorUnits: 3, Name: "Rial Omani"},
+	"PAB": {Code: "PAB", NumericCode: "590", MinorUnits: 2, Name: "Balboa"},
+	"PEN": {Code: "PEN", NumericCode: "604", MinorUnits: 2, Name: "Sol"},
+	"PGK": {Code: "PGK", NumericCode: "598", MinorUnits: 2, Name: "Kina"},
+	"PHP": {Code: "PHP", NumericCode: "608", MinorUnits: 2, Symbol: "₱", Name: "Philippine Peso"},
+	"PKR": {Code: "PKR", NumericCode: "586", MinorUnits: 2, Name: "Pakistan Rupee"},
+	"PLN": {Code: "PLN", NumericCode: "985", MinorUnits: 2, Symbol: "zł", Name: "Zloty"},
+	"PYG": {Code: "PYG", NumericCode: "600", MinorUnits: 0, Symbol: "₲", Name: "Guarani"},
+	"QAR": {Code: "QAR", NumericCode: "634", MinorUnits: 2, Name: "Qatari Rial"},
+	"RON": {Code: "RON", NumericCode: "946", MinorUnits: 2, Name: "Romanian Leu"},
+	"RSD": {Code: "RSD", NumericCode: "941", MinorUnits: 2, Name: "Serbian Dinar"},
+	"RUB": {Code: "RUB", NumericCode: "643", MinorUnits: 2, Symbol: "₽", Name: "Russian Ruble"},
+	"RWF": {Code: "RWF", NumericCode: "646", MinorUnits: 0, Name: "Rwanda Franc"},
+	"SAR": {Code: "SAR", NumericCode: "682", MinorUnits: 2, Name: "Saudi Riyal"},
+	"SBD": {Code: "SBD", NumericCode: "090", MinorUnits: 2, Name: "Solomon Islands Dollar"},
+	"SCR": {Code: "SCR", NumericCode: "690", MinorUnits: 2, Name: "Seychelles Rupee"},
+	"SDG": {Code: "SDG", NumericCode: "938", MinorUnits: 2, Name: "Sudanese Pound"},
+	"SEK": {Code: "SEK", NumericCode: "752", MinorUnits: 2, Name: "Swedish Krona"},
+	"SGD": {Code: "SGD", NumericCode: "702", MinorUnits: 2, Symbol: "S$", Name: "Singapore Dollar"},
+	"SHP": {Code: "SHP", NumericCode: "654", MinorUnits: 2, Name: "Saint Helena Pound"},
+	"SLE": {Code: "SLE", NumericCode: "925", MinorUnits: 2, Name: "Leone"},
+	"SOS": {Code: "SOS", NumericCode: "706", MinorUnits: 2, Name: "Somali Shilling"},
+	"SRD": {Code: "SRD", NumericCode: "968", MinorUnits: 2, Name: "Surinam Dollar"},
+	"SSP": {Code: "SSP", NumericCode: "728", MinorUnits: 2, Name: "South Sudanese Pound"},
+	"STN": {Code: "STN", NumericCode: "930", MinorUnits: 2, Name: "Dobra"},
+	"SVC": {Code: "SVC", NumericCode: "222", MinorUnits: 2, Name: "El Salvador Colon"},
+	"SYP": {Code: "SYP", NumericCode: "760", MinorUnits: 2, Name: "Syrian Pound"},
+	"SZL": {Code: "SZL", NumericCode: "748", MinorUnits: 2, Name: "Lilangeni"},
+	"THB": {Code: "THB", NumericCode: "764", MinorUnits: 2, Symbol: "฿", Name: "Baht"},
+	"TJS": {Code: "TJS", NumericCode: "972", MinorUnits: 2, Name: "Somoni"},
+	"TMT": {Code: "TMT", NumericCode: "934", MinorUnits: 2, Name: "Turkmenistan New Manat"},
+	"TND": {Code: "TND", NumericCode: "788", MinorUnits: 3, Name: "Tunisian Dinar"},
+	"TOP": {Code: "TOP", NumericCode: "776", MinorUnits: 2, Name: "Pa'anga"},
+	"TRY": {Code: "TRY", NumericCode: "949", MinorUnits: 2, Symbol: "₺", Name: "Turkish Lira"},
+	"TTD": {Code: "TTD", NumericCode: "780", MinorUnits: 2, Name: "Trinidad and Tobago Dollar"},
+	"TWD": {Code: "TWD", NumericCode: "901", MinorUnits: 2, Symbol: "NT$", Name: "New Taiwan Dollar"},
+	"TZS": {Code: "TZS", NumericCode: "834", MinorUnits: 2, Name: "Tanzanian Shilling"},
+	"UAH": {Code: "UAH", NumericCode: "980", MinorUnits: 2, Symbol: "₴", Name: "Hryvnia"},
+	"UGX": {Code: "UGX", NumericCode: "800", MinorUnits: 0, Name: "Uganda Shilling"},
+	"USD": {Code: "USD", NumericCode: "840", MinorUnits: 2, Symbol: "$", Name: "US Dollar"},
+	"UYU": {Code: "UYU", NumericCode: "858", MinorUnits: 2, Name: "Peso Uruguayo"},
+	"UZS": {Code: "UZS", NumericCode: "860", MinorUnits: 2, Name: "Uzbekistan Sum"},
+	"VES": {Code: "VES", NumericCode: "928", MinorUnits: 2, Name: "Bolívar Soberano"},
+	"VND": {Code: "VND", NumericCode: "704", MinorUnits: 0, Symbol: "₫", Name: "Dong"},
+	"VUV": {Code: "VUV", NumericCode: "548", MinorUnits: 0, Name: "Vatu"},
+	"WST": {Code: "WST", NumericCode: "882", MinorUnits: 2, Name: "Tala"},
+	"XAF": {Code: "XAF", NumericCode: "950", MinorUnits: 0, Symbol: "FCFA", Name: "CFA Franc BEAC"},
+	"XCD": {Code: "XCD", NumericCode: "951", MinorUnits: 2, Symbol: "EC$", Name: "East Caribbean Dollar"},
+	"XOF": {Code: "XOF", NumericCode: "952", MinorUnits: 0, Symbol: "CFA", Name: "CFA Franc BCEAO"},
+	"XPF": {Code: "XPF", NumericCode: "953", MinorUnits: 0, Symbol: "CFPF", Name: "CFP Franc"},
+	"YER": {Code: "YER", NumericCode: "886", MinorUnits: 2, Name: "Yemeni Rial"},
+	"ZAR": {Code: "ZAR", NumericCode: "710", MinorUnits: 2, Symbol: "R", Name: "Rand"},
+	"ZMW": {Code: "ZMW", NumericCode: "967", MinorUnits: 2, Name: "Zambian Kwacha"},
+	"ZWG": {Code: "ZWG", NumericCode: "924", MinorUnits: 2, Name: "Zimbabwe Gold"},
+}
+
+// NewCurrency creates a Currency from an ISO 4217 or registered commodity code, normalising case and whitespace
+func NewCurrency(code string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	return c, nil
+}
+
+// LookupCurrency returns the registry entry for the given currency or commodity
+func LookupCurrency(c Currency) (CurrencyInfo, bool) {
+	if info, ok := currencyRegistry[c]; ok {
+		info.Kind = CommodityKindFiat
+		return info, true
+	}
+	return lookupCommodity(c)
+}
+
+// AllCurrencies returns all registered currencies and commodities sorted by code
+func AllCurrencies() []CurrencyInfo {
+	all := make([]CurrencyInfo, 0, len(currencyRegistry))
+	for _, info := range currencyRegistry {
+		info.Kind = CommodityKindFiat
+		all = append(all, info)
+	}
+	all = append(all, allCommodities()...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Code < all[j].Code })
+	return all
+}
+
+// Validate checks that the currency is non-empty and present in the registry
+func (c Currency) Validate() error {
+	if c == "" {
+		return fmt.Errorf("currency cannot be empty")
+	}
+	if _, ok := LookupCurrency(c); !ok {
+		return fmt.Errorf("unsupported currency: %s", c)
+	}
+	return nil
+}
+
+// IsValid checks if the currency is a registered ISO 4217 or commodity code
+func (c Currency) IsValid() bool {
+	return c.Validate() == nil
+}
+
+// String returns the string representation of Currency
+func (c Currency) String() string {
+	return string(c)
+}
+
+// MinorUnits returns the number of decimal places used by the currency
+// (2 for USD, 0 for JPY, 3 for BHD). Unregistered codes default to 2.
+func (c Currency) MinorUnits() int32 {
+	if info, ok := LookupCurrency(c); ok {
+		return info.MinorUnits
+	}
+	return defaultMinorUnits
+}
+
+// NumericCode returns the ISO 4217 numeric code, or an empty string if unregistered
+func (c Currency) NumericCode() string {
+	info, _ := LookupCurrency(c)
+	return info.NumericCode
+}
+
+// Symbol returns the display symbol for the currency, falling back to the code
+func (c Currency) Symbol() string {
+	if info, ok := LookupCurrency(c); ok && info.Symbol != "" {
+		return info.Symbol
+	}
+	return string(c)
+}
+
+// Name returns the ISO 4217 currency name, or an empty string if unregistered
+func (c Currency) Name() string {
+	info, _ := LookupCurrency(c)
+	return info.Name
+}
@@ -0,0 +1,107 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected Currency
+		wantErr  bool
+	}{
+		{"valid upper case", "SGD", CurrencySGD, false},
+		{"normalises case and whitespace", " usd ", CurrencyUSD, false},
+		{"empty code", "", "", true},
+		{"unknown code", "ABC", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCurrency(tt.code)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, c)
+			}
+		})
+	}
+}
+
+func TestCurrency_Info(t *testing.T) {
+	tests := []struct {
+		currency    Currency
+		numericCode string
+		minorUnits  int32
+		symbol      string
+	}{
+		{CurrencyUSD, "840", 2, "$"},
+		{CurrencySGD, "702", 2, "S$"},
+		{CurrencyJPY, "392", 0, "¥"},
+		{CurrencyKRW, "410", 0, "₩"},
+		{CurrencyBHD, "048", 3, "BHD"},
+		{CurrencyKWD, "414", 3, "KWD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency.String(), func(t *testing.T) {
+			assert.True(t, tt.currency.IsValid())
+			assert.Equal(t, tt.numericCode, tt.currency.NumericCode())
+			assert.Equal(t, tt.minorUnits, tt.currency.MinorUnits())
+			assert.Equal(t, tt.symbol, tt.currency.Symbol())
+			assert.NotEmpty(t, tt.currency.Name())
+		})
+	}
+}
+
+func TestCurrency_Unregistered(t *testing.T) {
+	c := Currency("XYZ")
+
+	assert.False(t, c.IsValid())
+	assert.Equal(t, int32(2), c.MinorUnits())
+	assert.Equal(t, "XYZ", c.Symbol())
+	assert.Empty(t, c.NumericCode())
+
+	err := c.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported currency")
+
+	err = Currency("").Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "currency cannot be empty")
+}
+
+func TestAllCurrencies(t *testing.T) {
+	all := AllCurrencies()
+	require.NotEmpty(t, all)
+
+	seenNumeric := make(map[string]Currency)
+	for i, info := range all {
+		if i > 0 {
+			assert.Less(t, all[i-1].Code, info.Code, "currencies should be sorted by code")
+		}
+		registered, ok := LookupCurrency(info.Code)
+		assert.True(t, ok)
+		assert.Equal(t, info, registered)
+
+		if !info.Kind.IsFiat() {
+			continue
+		}
+
+		assert.Len(t, string(info.Code), 3)
+		assert.Len(t, info.NumericCode, 3)
+		assert.GreaterOrEqual(t, info.MinorUnits, int32(0))
+		assert.LessOrEqual(t, info.MinorUnits, int32(4))
+
+		if other, dup := seenNumeric[info.NumericCode]; dup {
+			t.Errorf("numeric code %s used by both %s and %s", info.NumericCode, other, info.Code)
+		}
+		seenNumeric[info.NumericCode] = info.Code
+	}
+}
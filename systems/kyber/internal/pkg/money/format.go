@@ -0,0 +1,288 @@
+package money
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+// nbsp separates the currency symbol or code from the number
+const nbsp = "\u00a0"
+
+// plainNumberPattern matches a number once signs, symbols and group separators are removed
+var plainNumberPattern = regexp.MustCompile(`^(\d+(\.\d+)?|\.\d+)$`)
+
+// symbolDisplay determines how the currency is shown by Format
+type symbolDisplay int
+
+const (
+	displaySymbol symbolDisplay = iota
+	displayCode
+	displayNone
+)
+
+// formatOptions holds configuration for Money.Format (unexported)
+type formatOptions struct {
+	accounting bool
+	display    symbolDisplay
+}
+
+// FormatOption is a function that configures formatting options
+type FormatOption func(*formatOptions)
+
+// WithAccountingNegatives wraps negative amounts in parentheses instead of using a minus sign
+func WithAccountingNegatives() FormatOption {
+	return func(o *formatOptions) {
+		o.accounting = true
+	}
+}
+
+// WithCurrencyCode shows the ISO 4217 code instead of the currency symbol
+func WithCurrencyCode() FormatOption {
+	return func(o *formatOptions) {
+		o.display = displayCode
+	}
+}
+
+// WithoutSymbol omits the currency symbol entirely
+func WithoutSymbol() FormatOption {
+	return func(o *formatOptions) {
+		o.display = displayNone
+	}
+}
+
+// Format returns the amount as written in the locale, rounded to the currency's minor units,
+// e.g. "S$1,234.56" (en-US), "1.234,56 €" (de-DE) or "(¥1,235)" with accounting negatives
+func (m Money) Format(locale Locale, opts ...FormatOption) string {
+	options := &formatOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	places := m.Currency.MinorUnits()
+	rounded := m.Amount.Round(places)
+	number := locale.formatNumber(rounded.Abs().StringFixed(places))
+
+	var symbol, spacing string
+	switch options.display {
+	case displayCode:
+		symbol, spacing = string(m.Currency), nbsp
+	case displayNone:
+	default:
+		symbol = locale.Symbol(m.Currency)
+		if locale.SymbolSpacing || symbol == string(m.Currency) {
+			spacing = nbsp
+		}
+	}
+
+	body := number
+	if symbol != "" {
+		if locale.SymbolAfter {
+			body = number + spacing + symbol
+		} else {
+			body = symbol + spacing + number
+		}
+	}
+
+	if !rounded.IsNegative() {
+		return body
+	}
+	if options.accounting {
+		return "(" + body + ")"
+	}
+	return "-" + body
+}
+
+// Parse parses a human-entered amount written in the locale, accepting the currency's
+// symbol or code, group separators, and "-", trailing "-" or accounting "(…)" negatives.
+// The amount cannot have more decimal places than the currency allows.
+func Parse(input string, locale Locale, currency Currency) (Money, error) {
+	if err := currency.Validate(); err != nil {
+		return Money{}, err
+	}
+
+	s := strings.TrimFunc(input, unicode.IsSpace)
+	if s == "" {
+		return Money{}, fmt.Errorf("amount cannot be empty")
+	}
+
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = strings.TrimFunc(s[1:len(s)-1], unicode.IsSpace)
+	}
+
+	s, signed := trimSign(s)
+	s, _ = trimSymbol(s, locale.symbolCandidates(currency))
+	s, signedAfterSymbol := trimSign(s)
+
+	signs := 0
+	for _, v := range []bool{negative, signed, signedAfterSymbol} {
+		if v {
+			signs++
+		}
+	}
+	if signs > 1 {
+		return Money{}, fmt.Errorf("invalid amount %q: multiple negative signs", input)
+	}
+
+	amount, err := locale.parseNumber(s)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q: %w", input, err)
+	}
+	if signs == 1 {
+		amount = amount.Neg()
+	}
+
+	m := Money{Amount: amount, Currency: currency}
+	if err := m.ValidatePrecision(); err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q: %w", input, err)
+	}
+
+	return m, nil
+}
+
+// formatNumber inserts the locale's separators into a plain "1234.56" number
+func (l Locale) formatNumber(plain string) string {
+	intPart, fracPart, hasFrac := strings.Cut(plain, ".")
+
+	groups := l.splitGroups(intPart)
+	result := strings.Join(groups, l.GroupSeparator)
+	if hasFrac {
+		result += l.DecimalSeparator + fracPart
+	}
+	return result
+}
+
+// splitGroups splits the integer digits into groups according to GroupSizes
+func (l Locale) splitGroups(digits string) []string {
+	if len(l.GroupSizes) == 0 || l.GroupSeparator == "" {
+		return []string{digits}
+	}
+
+	var groups []string
+	for i := 0; len(digits) > 0; i++ {
+		size := l.GroupSizes[min(i, len(l.GroupSizes)-1)]
+		if size <= 0 || size >= len(digits) {
+			groups = append(groups, digits)
+			break
+		}
+		groups = append(groups, digits[len(digits)-size:])
+		digits = digits[:len(digits)-size]
+	}
+
+	// Groups were collected right to left
+	for i, j := 0, len(groups)-1; i < j; i, j = i+1, j-1 {
+		groups[i], groups[j] = groups[j], groups[i]
+	}
+	return groups
+}
+
+// parseNumber parses an unsigned number written with the locale's separators
+func (l Locale) parseNumber(s string) (decimal.Decimal, error) {
+	if strings.Count(s, l.DecimalSeparator) > 1 {
+		return decimal.Decimal{}, fmt.Errorf("multiple decimal separators")
+	}
+	intPart, fracPart, hasFrac := strings.Cut(s, l.DecimalSeparator)
+
+	if l.GroupSeparator != "" {
+		groupSep := l.GroupSeparator
+		if r, _ := utf8.DecodeRuneInString(groupSep); unicode.IsSpace(r) {
+			// Any kind of space is accepted where the locale groups with one
+			intPart = strings.Map(func(r rune) rune {
+				if unicode.IsSpace(r) {
+					return ' '
+				}
+				return r
+			}, intPart)
+			groupSep = " "
+		}
+
+		if hasFrac && strings.Contains(fracPart, groupSep) {
+			return decimal.Decimal{}, fmt.Errorf("group separator after decimal separator")
+		}
+
+		if strings.Contains(intPart, groupSep) {
+			groups := strings.Split(intPart, groupSep)
+			if !l.validGrouping(groups) {
+				return decimal.Decimal{}, fmt.Errorf("misplaced group separator")
+			}
+			intPart = strings.Join(groups, "")
+		}
+	}
+
+	plain := intPart
+	if hasFrac {
+		plain += "." + fracPart
+	}
+	if !plainNumberPattern.MatchString(plain) {
+		return decimal.Decimal{}, fmt.Errorf("not a number")
+	}
+
+	return decimal.NewFromString(plain)
+}
+
+// validGrouping checks that digit groups match the sizes the locale would produce
+func (l Locale) validGrouping(groups []string) bool {
+	if len(l.GroupSizes) == 0 {
+		return false
+	}
+
+	expected := l.splitGroups(strings.Join(groups, ""))
+	if len(expected) != len(groups) {
+		return false
+	}
+	for i := range groups {
+		if groups[i] != expected[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// symbolCandidates returns the strings accepted as the currency's symbol when parsing, longest first
+func (l Locale) symbolCandidates(c Currency) []string {
+	candidates := []string{l.Symbol(c), c.Symbol(), string(c)}
+
+	// A bare "$" or "¥" is accepted for "S$" or "CN¥" since the currency is already known
+	if r, size := utf8.DecodeLastRuneInString(c.Symbol()); size > 0 && !unicode.IsLetter(r) {
+		candidates = append(candidates, string(r))
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return len(candidates[i]) > len(candidates[j]) })
+	return candidates
+}
+
+// trimSymbol removes one leading or trailing symbol from s
+func trimSymbol(s string, candidates []string) (string, bool) {
+	for _, sym := range candidates {
+		if sym == "" {
+			continue
+		}
+		if len(s) >= len(sym) && strings.EqualFold(s[:len(sym)], sym) {
+			return strings.TrimFunc(s[len(sym):], unicode.IsSpace), true
+		}
+		if len(s) >= len(sym) && strings.EqualFold(s[len(s)-len(sym):], sym) {
+			return strings.TrimFunc(s[:len(s)-len(sym)], unicode.IsSpace), true
+		}
+	}
+	return s, false
+}
+
+// trimSign removes one leading or trailing minus sign from s
+func trimSign(s string) (string, bool) {
+	for _, minus := range []string{"-", "\u2212"} {
+		if strings.HasPrefix(s, minus) {
+			return strings.TrimFunc(strings.TrimPrefix(s, minus), unicode.IsSpace), true
+		}
+		if strings.HasSuffix(s, minus) {
+			return strings.TrimFunc(strings.TrimSuffix(s, minus), unicode.IsSpace), true
+		}
+	}
+	return s, false
+}
@@ -0,0 +1,139 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_Format(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency Currency
+		locale   Locale
+		opts     []FormatOption
+		expected string
+	}{
+		{"en-US USD", "1234.5", CurrencyUSD, LocaleEnUS, nil, "$1,234.50"},
+		{"en-US SGD uses S$", "1234.56", CurrencySGD, LocaleEnUS, nil, "S$1,234.56"},
+		{"en-SG SGD uses $", "1234.56", CurrencySGD, LocaleEnSG, nil, "$1,234.56"},
+		{"en-SG USD uses US$", "1234.56", CurrencyUSD, LocaleEnSG, nil, "US$1,234.56"},
+		{"en-US negative", "-1234.56", CurrencyUSD, LocaleEnUS, nil, "-$1,234.56"},
+		{"accounting negative", "-1234.56", CurrencyUSD, LocaleEnUS, []FormatOption{WithAccountingNegatives()}, "($1,234.56)"},
+		{"accounting positive is unchanged", "1234.56", CurrencyUSD, LocaleEnUS, []FormatOption{WithAccountingNegatives()}, "$1,234.56"},
+		{"JPY has no decimals", "1234.5", CurrencyJPY, LocaleJaJP, nil, "¥1,235"},
+		{"BHD has three decimals", "1234.5", CurrencyBHD, LocaleEnUS, nil, "BHD\u00a01,234.500"},
+		{"de-DE suffix symbol", "1234567.891", CurrencyEUR, LocaleDeDE, nil, "1.234.567,89\u00a0€"},
+		{"de-DE negative", "-0.5", CurrencyEUR, LocaleDeDE, nil, "-0,50\u00a0€"},
+		{"fr-FR narrow space grouping", "1234.56", CurrencyEUR, LocaleFrFR, nil, "1\u202f234,56\u00a0€"},
+		{"en-IN lakh grouping", "1234567.89", "INR", LocaleEnIN, nil, "₹12,34,567.89"},
+		{"currency code", "1234.56", CurrencySGD, LocaleEnUS, []FormatOption{WithCurrencyCode()}, "SGD\u00a01,234.56"},
+		{"without symbol", "-1234.56", CurrencySGD, LocaleEnUS, []FormatOption{WithoutSymbol(), WithAccountingNegatives()}, "(1,234.56)"},
+		{"small amount", "0.05", CurrencyUSD, LocaleEnUS, nil, "$0.05"},
+		{"rounds to zero without sign", "-0.001", CurrencyUSD, LocaleEnUS, nil, "$0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mustNewMoney(t, tt.amount, tt.currency)
+			assert.Equal(t, tt.expected, m.Format(tt.locale, tt.opts...))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		locale      Locale
+		currency    Currency
+		expected    string
+		wantErr     bool
+		errContains string
+	}{
+		{name: "plain", input: "1234.56", locale: LocaleEnUS, currency: CurrencyUSD, expected: "1234.56"},
+		{name: "grouped with symbol", input: "$1,234.56", locale: LocaleEnUS, currency: CurrencyUSD, expected: "1234.56"},
+		{name: "S$ prefix", input: "S$1,234.56", locale: LocaleEnUS, currency: CurrencySGD, expected: "1234.56"},
+		{name: "bare $ for SGD", input: "$ 12", locale: LocaleEnUS, currency: CurrencySGD, expected: "12"},
+		{name: "en-SG US$", input: "US$10.00", locale: LocaleEnSG, currency: CurrencyUSD, expected: "10"},
+		{name: "yen", input: "¥1,235", locale: LocaleJaJP, currency: CurrencyJPY, expected: "1235"},
+		{name: "code prefix", input: "sgd 99.90", locale: LocaleEnSG, currency: CurrencySGD, expected: "99.9"},
+		{name: "code suffix", input: "99.90 SGD", locale: LocaleEnSG, currency: CurrencySGD, expected: "99.9"},
+		{name: "minus before symbol", input: "-$1,234.56", locale: LocaleEnUS, currency: CurrencyUSD, expected: "-1234.56"},
+		{name: "minus after symbol", input: "$-1,234.56", locale: LocaleEnUS, currency: CurrencyUSD, expected: "-1234.56"},
+		{name: "trailing minus", input: "1,234.56-", locale: LocaleEnUS, currency: CurrencyUSD, expected: "-1234.56"},
+		{name: "unicode minus", input: "\u22121.00", locale: LocaleEnUS, currency: CurrencyUSD, expected: "-1"},
+		{name: "accounting negative", input: "(1,234.56)", locale: LocaleEnUS, currency: CurrencyUSD, expected: "-1234.56"},
+		{name: "accounting negative with symbol", input: "($1,234.56)", locale: LocaleEnUS, currency: CurrencyUSD, expected: "-1234.56"},
+		{name: "de-DE", input: "1.234,56 €", locale: LocaleDeDE, currency: CurrencyEUR, expected: "1234.56"},
+		{name: "de-DE negative", input: "-1.234.567,8\u00a0€", locale: LocaleDeDE, currency: CurrencyEUR, expected: "-1234567.8"},
+		{name: "fr-FR regular space grouping", input: "1 234,56 €", locale: LocaleFrFR, currency: CurrencyEUR, expected: "1234.56"},
+		{name: "fr-FR narrow space grouping", input: "1\u202f234,56", locale: LocaleFrFR, currency: CurrencyEUR, expected: "1234.56"},
+		{name: "en-IN lakh grouping", input: "₹12,34,567.89", locale: LocaleEnIN, currency: "INR", expected: "1234567.89"},
+		{name: "leading decimal", input: ".5", locale: LocaleEnUS, currency: CurrencyUSD, expected: "0.5"},
+		{name: "BHD three decimals", input: "1.250", locale: LocaleEnUS, currency: CurrencyBHD, expected: "1.25"},
+		{name: "formatted output round-trips", input: "(S$1,234.56)", locale: LocaleEnUS, currency: CurrencySGD, expected: "-1234.56"},
+		{name: "empty", input: "  ", locale: LocaleEnUS, currency: CurrencyUSD, wantErr: true, errContains: "amount cannot be empty"},
+		{name: "invalid currency", input: "1", locale: LocaleEnUS, currency: "XYZ", wantErr: true, errContains: "unsupported currency"},
+		{name: "too many decimals for JPY", input: "¥1,234.5", locale: LocaleJaJP, currency: CurrencyJPY, wantErr: true, errContains: "exceeds JPY precision"},
+		{name: "too many decimals for USD", input: "1.234", locale: LocaleEnUS, currency: CurrencyUSD, wantErr: true, errContains: "exceeds USD precision"},
+		{name: "wrong currency symbol", input: "€10", locale: LocaleEnUS, currency: CurrencyUSD, wantErr: true, errContains: "not a number"},
+		{name: "misplaced group separator", input: "1,23,4.00", locale: LocaleEnUS, currency: CurrencyUSD, wantErr: true, errContains: "misplaced group separator"},
+		{name: "de-DE with en-US separators", input: "1,234.56", locale: LocaleDeDE, currency: CurrencyEUR, wantErr: true},
+		{name: "multiple decimal separators", input: "1.2.3", locale: LocaleEnUS, currency: CurrencyUSD, wantErr: true, errContains: "multiple decimal separators"},
+		{name: "double negative", input: "(-1.00)", locale: LocaleEnUS, currency: CurrencyUSD, wantErr: true, errContains: "multiple negative signs"},
+		{name: "exponent notation", input: "1e5", locale: LocaleEnUS, currency: CurrencyUSD, wantErr: true, errContains: "not a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(tt.input, tt.locale, tt.currency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errContains != "" {
+					assert.Contains(t, err.Error(), tt.errContains)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.currency, m.Currency)
+			assert.True(t, m.Equals(mustNewMoney(t, tt.expected, tt.currency)), "got %s", m.Amount)
+		})
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	locales := []Locale{LocaleEnUS, LocaleEnSG, LocaleEnGB, LocaleEnIN, LocaleDeDE, LocaleFrFR, LocaleJaJP}
+	amounts := []Money{
+		mustNewMoney(t, "1234567.89", CurrencySGD),
+		mustNewMoney(t, "-0.01", CurrencyUSD),
+		mustNewMoney(t, "-98765", CurrencyJPY),
+		mustNewMoney(t, "12.345", CurrencyKWD),
+		mustNewMoney(t, "1000", CurrencyEUR),
+	}
+
+	for _, locale := range locales {
+		for _, m := range amounts {
+			for _, opts := range [][]FormatOption{nil, {WithAccountingNegatives()}, {WithCurrencyCode()}} {
+				formatted := m.Format(locale, opts...)
+				parsed, err := Parse(formatted, locale, m.Currency)
+				require.NoError(t, err, "%s in %s", formatted, locale)
+				assert.True(t, parsed.Equals(m), "%s in %s parsed as %s", formatted, locale, parsed)
+			}
+		}
+	}
+}
+
+func TestLookupLocale(t *testing.T) {
+	l, ok := LookupLocale("en_sg")
+	require.True(t, ok)
+	assert.Equal(t, "en-SG", l.String())
+	assert.Equal(t, "$", l.Symbol(CurrencySGD))
+	assert.Equal(t, "€", l.Symbol(CurrencyEUR))
+
+	_, ok = LookupLocale("xx-YY")
+	assert.False(t, ok)
+}
@@ -0,0 +1,109 @@
+package money
+
+import (
+	"strings"
+)
+
+// Locale describes how amounts are written for a region: separators, digit grouping
+// and where the currency symbol goes
+type Locale struct {
+	Tag              string // BCP 47 language tag, e.g. "en-SG"
+	DecimalSeparator string
+	GroupSeparator   string
+	GroupSizes       []int               // Digit group sizes from the right; the last size repeats
+	SymbolAfter      bool                // Symbol follows the number ("1.234,56 €")
+	SymbolSpacing    bool                // Symbol is separated from the number by a non-breaking space
+	Symbols          map[Currency]string // Locale-specific symbols overriding CurrencyInfo.Symbol
+}
+
+// Predefined locales
+var (
+	// LocaleEnUS represents English (United States): $1,234.56
+	LocaleEnUS = Locale{
+		Tag:              "en-US",
+		DecimalSeparator: ".",
+		GroupSeparator:   ",",
+		GroupSizes:       []int{3},
+	}
+	// LocaleEnSG represents English (Singapore): $1,234.56 for SGD, US$1,234.56 for USD
+	LocaleEnSG = Locale{
+		Tag:              "en-SG",
+		DecimalSeparator: ".",
+		GroupSeparator:   ",",
+		GroupSizes:       []int{3},
+		Symbols:          map[Currency]string{CurrencySGD: "$", CurrencyUSD: "US$"},
+	}
+	// LocaleEnGB represents English (United Kingdom): £1,234.56
+	LocaleEnGB = Locale{
+		Tag:              "en-GB",
+		DecimalSeparator: ".",
+		GroupSeparator:   ",",
+		GroupSizes:       []int{3},
+		Symbols:          map[Currency]string{CurrencyUSD: "US$"},
+	}
+	// LocaleEnIN represents English (India): ₹12,34,567.89
+	LocaleEnIN = Locale{
+		Tag:              "en-IN",
+		DecimalSeparator: ".",
+		GroupSeparator:   ",",
+		GroupSizes:       []int{3, 2},
+		Symbols:          map[Currency]string{CurrencyUSD: "US$"},
+	}
+	// LocaleDeDE represents German (Germany): 1.234,56 €
+	LocaleDeDE = Locale{
+		Tag:              "de-DE",
+		DecimalSeparator: ",",
+		GroupSeparator:   ".",
+		GroupSizes:       []int{3},
+		SymbolAfter:      true,
+		SymbolSpacing:    true,
+	}
+	// LocaleFrFR represents French (France): 1 234,56 €
+	LocaleFrFR = Locale{
+		Tag:              "fr-FR",
+		DecimalSeparator: ",",
+		GroupSeparator:   "\u202f", // Narrow no-break space
+		GroupSizes:       []int{3},
+		SymbolAfter:      true,
+		SymbolSpacing:    true,
+	}
+	// LocaleJaJP represents Japanese (Japan): ¥1,235
+	LocaleJaJP = Locale{
+		Tag:              "ja-JP",
+		DecimalSeparator: ".",
+		GroupSeparator:   ",",
+		GroupSizes:       []int{3},
+	}
+)
+
+// localeRegistry holds the predefined locales keyed by lower-cased tag
+var localeRegistry = map[string]Locale{
+	"en-us": LocaleEnUS,
+	"en-sg": LocaleEnSG,
+	"en-gb": LocaleEnGB,
+	"en-in": LocaleEnIN,
+	"de-de": LocaleDeDE,
+	"fr-fr": LocaleFrFR,
+	"ja-jp": LocaleJaJP,
+}
+
+// LookupLocale returns the predefined locale for a language tag such as "en-SG" or "de_DE"
+func LookupLocale(tag string) (Locale, bool) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	l, ok := localeRegistry[normalized]
+	return l, ok
+}
+
+// String returns the locale's language tag
+func (l Locale) String() string {
+	return l.Tag
+}
+
+// Symbol returns the symbol used for the currency in this locale,
+// falling back to the currency's default symbol
+func (l Locale) Symbol(c Currency) string {
+	if s, ok := l.Symbols[c]; ok {
+		return s
+	}
+	return c.Symbol()
+}
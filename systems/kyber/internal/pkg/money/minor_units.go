@@ -0,0 +1,57 @@
+package money
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// FromMinorUnits creates Money from an integer amount of the currency's minor unit
+// (e.g. cents for USD, yen for JPY, fils for BHD)
+func FromMinorUnits(units int64, currency Currency) (Money, error) {
+	return FromMinorUnitsDecimal(decimal.NewFromInt(units), currency)
+}
+
+// FromMinorUnitsDecimal creates Money from an integer amount of the currency's minor unit held as a
+// decimal, for amounts beyond int64 such as wei of Ether. Returns an error if units is not whole.
+func FromMinorUnitsDecimal(units decimal.Decimal, currency Currency) (Money, error) {
+	if err := currency.Validate(); err != nil {
+		return Money{}, err
+	}
+
+	if !units.IsInteger() {
+		return Money{}, fmt.Errorf("minor units must be whole: %s", units)
+	}
+
+	return Money{
+		Amount:   units.Shift(-currency.MinorUnits()),
+		Currency: currency,
+	}, nil
+}
+
+// ToMinorUnits converts the amount to an integer number of the currency's minor unit.
+// Returns an error instead of truncating if the amount has more precision than the
+// currency allows, or if it does not fit in an int64.
+func (m Money) ToMinorUnits() (int64, error) {
+	units, err := m.ToMinorUnitsDecimal()
+	if err != nil {
+		return 0, err
+	}
+
+	if !units.BigInt().IsInt64() {
+		return 0, fmt.Errorf("cannot convert to minor units: amount %s %s overflows int64", m.Amount, m.Currency)
+	}
+
+	return units.IntPart(), nil
+}
+
+// ToMinorUnitsDecimal converts the amount to a whole number of the currency's minor unit without the
+// int64 limit of ToMinorUnits. Returns an error instead of truncating if the amount has more precision
+// than the currency allows.
+func (m Money) ToMinorUnitsDecimal() (decimal.Decimal, error) {
+	if err := m.ValidatePrecision(); err != nil {
+		return decimal.Decimal{}, fmt.Errorf("cannot convert to minor units: %w", err)
+	}
+
+	return m.Amount.Shift(m.Currency.MinorUnits()).Truncate(0), nil
+}
@@ -0,0 +1,110 @@
+package money
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromMinorUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		units    int64
+		currency Currency
+		expected string
+		wantErr  bool
+	}{
+		{"USD cents", 12345, CurrencyUSD, "123.45", false},
+		{"negative SGD", -5025, CurrencySGD, "-50.25", false},
+		{"JPY has no minor unit", 1500, CurrencyJPY, "1500", false},
+		{"BHD fils", 12345, CurrencyBHD, "12.345", false},
+		{"zero", 0, CurrencyUSD, "0", false},
+		{"empty currency", 100, "", "", true},
+		{"unsupported currency", 100, "XYZ", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := FromMinorUnits(tt.units, tt.currency)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			expected, err := NewMoney(tt.expected, tt.currency)
+			require.NoError(t, err)
+			assert.True(t, m.Equals(expected), "got %s, want %s", m, expected)
+		})
+	}
+}
+
+func TestMoney_ToMinorUnits(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      string
+		currency    Currency
+		expected    int64
+		errContains string
+	}{
+		{"USD cents", "123.45", CurrencyUSD, 12345, ""},
+		{"USD whole", "100", CurrencyUSD, 10000, ""},
+		{"USD trailing zeros", "1.2000", CurrencyUSD, 120, ""},
+		{"negative", "-0.01", CurrencyUSD, -1, ""},
+		{"JPY", "1500", CurrencyJPY, 1500, ""},
+		{"KWD", "-7.125", CurrencyKWD, -7125, ""},
+		{"USD fractional cent", "0.001", CurrencyUSD, 0, "exceeds USD precision"},
+		{"JPY fractional yen", "10.5", CurrencyJPY, 0, "exceeds JPY precision"},
+		{"overflow", "999999999999999999999", CurrencyUSD, 0, "overflows int64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMoney(tt.amount, tt.currency)
+			require.NoError(t, err)
+
+			units, err := m.ToMinorUnits()
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, units)
+		})
+	}
+}
+
+func TestFromMinorUnitsDecimal(t *testing.T) {
+	m, err := FromMinorUnitsDecimal(decimal.RequireFromString("-1234567890123456789012"), CurrencyETH)
+	require.NoError(t, err)
+	assert.Equal(t, "-1234.567890123456789012 ETH", m.String())
+
+	units, err := m.ToMinorUnitsDecimal()
+	require.NoError(t, err)
+	assert.Equal(t, "-1234567890123456789012", units.String())
+
+	_, err = FromMinorUnitsDecimal(decimal.RequireFromString("12.5"), CurrencyUSD)
+	assert.ErrorContains(t, err, "minor units must be whole")
+
+	_, err = FromMinorUnitsDecimal(decimal.NewFromInt(100), "XYZ")
+	assert.Error(t, err)
+}
+
+func TestMinorUnits_RoundTrip(t *testing.T) {
+	for _, units := range []int64{0, 1, -1, 99, 100, 123456789, math.MaxInt64, math.MinInt64} {
+		for _, currency := range []Currency{CurrencyUSD, CurrencyJPY, CurrencyBHD} {
+			m, err := FromMinorUnits(units, currency)
+			require.NoError(t, err)
+
+			back, err := m.ToMinorUnits()
+			require.NoError(t, err)
+			assert.Equal(t, units, back, "%d %s", units, currency)
+		}
+	}
+}
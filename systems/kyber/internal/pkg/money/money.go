@@ -6,16 +6,6 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-// Currency represents different currency types
-type Currency string
-
-const (
-	// CurrencyUSD represents US Dollar
-	CurrencyUSD Currency = "USD"
-	// CurrencySGD represents Singapore Dollar
-	CurrencySGD Currency = "SGD"
-)
-
 // Money represents a monetary amount with currency using decimal precision
 type Money struct {
 	Amount   decimal.Decimal
@@ -24,8 +14,8 @@ type Money struct {
 
 // NewMoney creates a new Money value object from string amount (recommended)
 func NewMoney(amount string, currency Currency) (Money, error) {
-	if currency == "" {
-		return Money{}, fmt.Errorf("currency cannot be empty")
+	if err := currency.Validate(); err != nil {
+		return Money{}, err
 	}
 
 	d, err := decimal.NewFromString(amount)
@@ -41,8 +31,8 @@ func NewMoney(amount string, currency Currency) (Money, error) {
 
 // NewMoneyFromFloat creates Money from float64 (use with caution for financial data)
 func NewMoneyFromFloat(amount float64, currency Currency) (Money, error) {
-	if currency == "" {
-		return Money{}, fmt.Errorf("currency cannot be empty")
+	if err := currency.Validate(); err != nil {
+		return Money{}, err
 	}
 
 	d := decimal.NewFromFloat(amount)
@@ -55,8 +45,8 @@ func NewMoneyFromFloat(amount float64, currency Currency) (Money, error) {
 
 // NewMoneyFromDecimal creates Money from existing decimal.Decimal
 func NewMoneyFromDecimal(amount decimal.Decimal, currency Currency) (Money, error) {
-	if currency == "" {
-		return Money{}, fmt.Errorf("currency cannot be empty")
+	if err := currency.Validate(); err != nil {
+		return Money{}, err
 	}
 
 	return Money{
@@ -67,8 +57,8 @@ func NewMoneyFromDecimal(amount decimal.Decimal, currency Currency) (Money, erro
 
 // Zero creates a zero Money value for the given currency
 func Zero(currency Currency) (Money, error) {
-	if currency == "" {
-		return Money{}, fmt.Errorf("currency cannot be empty")
+	if err := currency.Validate(); err != nil {
+		return Money{}, err
 	}
 
 	return Money{
@@ -83,9 +73,9 @@ func (m Money) Float64() float64 {
 	return amount
 }
 
-// String returns formatted money string with 2 decimal places
+// String returns formatted money string using the currency's minor units
 func (m Money) String() string {
-	return fmt.Sprintf("%s %s", m.Amount.StringFixed(2), m.Currency)
+	return fmt.Sprintf("%s %s", m.Amount.StringFixed(m.Currency.MinorUnits()), m.Currency)
 }
 
 // StringFixed returns formatted money string with specified decimal places
@@ -120,8 +110,8 @@ func (m Money) IsNegative() bool {
 
 // Add adds two money amounts (must be same currency)
 func (m Money) Add(other Money) (Money, error) {
-	if m.Currency != other.Currency {
-		return Money{}, fmt.Errorf("cannot add different currencies: %s and %s", m.Currency, other.Currency)
+	if err := checkSameUnit("add", m.Currency, other.Currency); err != nil {
+		return Money{}, err
 	}
 
 	return Money{
@@ -132,8 +122,8 @@ func (m Money) Add(other Money) (Money, error) {
 
 // Subtract subtracts two money amounts (must be same currency)
 func (m Money) Subtract(other Money) (Money, error) {
-	if m.Currency != other.Currency {
-		return Money{}, fmt.Errorf("cannot subtract different currencies: %s and %s", m.Currency, other.Currency)
+	if err := checkSameUnit("subtract", m.Currency, other.Currency); err != nil {
+		return Money{}, err
 	}
 
 	return Money{
@@ -170,6 +160,22 @@ func (m Money) Divide(divisor decimal.Decimal) (Money, error) {
 	}, nil
 }
 
+// MultiplyAndRound multiplies the money amount by a decimal multiplier and rounds the
+// result to the currency's minor units using the given mode
+func (m Money) MultiplyAndRound(multiplier decimal.Decimal, mode RoundingMode) Money {
+	return m.Multiply(multiplier).RoundCurrencyWithMode(mode)
+}
+
+// DivideAndRound divides the money amount by a decimal divisor and rounds the
+// result to the currency's minor units using the given mode
+func (m Money) DivideAndRound(divisor decimal.Decimal, mode RoundingMode) (Money, error) {
+	result, err := m.Divide(divisor)
+	if err != nil {
+		return Money{}, err
+	}
+	return result.RoundCurrencyWithMode(mode), nil
+}
+
 // Negate returns the negative of the money amount
 func (m Money) Negate() Money {
 	return Money{
@@ -186,27 +192,44 @@ func (m Money) Abs() Money {
 	}
 }
 
-// Round rounds the money amount to the specified decimal places
+// Round rounds the money amount to the specified decimal places (half up)
 func (m Money) Round(places int32) Money {
+	return m.RoundWithMode(places, RoundHalfUp)
+}
+
+// RoundWithMode rounds the money amount to the specified decimal places using the given mode
+func (m Money) RoundWithMode(places int32, mode RoundingMode) Money {
 	return Money{
-		Amount:   m.Amount.Round(places),
+		Amount:   mode.apply(m.Amount, places),
 		Currency: m.Currency,
 	}
 }
 
 // RoundCurrency rounds the money amount to standard currency precision
-// (2 decimal places for most currencies, 0 for JPY, etc.)
+// (2 decimal places for most currencies, 0 for JPY, 3 for BHD, etc.)
 func (m Money) RoundCurrency() Money {
-	places := int32(2) // Default to 2 decimal places
+	return m.Round(m.Currency.MinorUnits())
+}
 
-	return m.Round(places)
+// RoundCurrencyWithMode rounds the money amount to standard currency precision using the given mode
+func (m Money) RoundCurrencyWithMode(mode RoundingMode) Money {
+	return m.RoundWithMode(m.Currency.MinorUnits(), mode)
+}
+
+// ValidatePrecision checks that the amount has no more decimal places than the currency allows
+func (m Money) ValidatePrecision() error {
+	places := m.Currency.MinorUnits()
+	if !m.Amount.Equal(m.Amount.Truncate(places)) {
+		return fmt.Errorf("amount %s exceeds %s precision of %d decimal places", m.Amount, m.Currency, places)
+	}
+	return nil
 }
 
 // Compare compares two money amounts (must be same currency)
 // Returns -1 if m < other, 0 if m == other, 1 if m > other
 func (m Money) Compare(other Money) (int, error) {
-	if m.Currency != other.Currency {
-		return 0, fmt.Errorf("cannot compare different currencies: %s and %s", m.Currency, other.Currency)
+	if err := checkSameUnit("compare", m.Currency, other.Currency); err != nil {
+		return 0, err
 	}
 
 	return m.Amount.Cmp(other.Amount), nil
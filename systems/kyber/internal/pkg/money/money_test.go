@@ -3,6 +3,7 @@ package money
 import (
 	"testing"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -19,6 +20,8 @@ func TestNewMoney(t *testing.T) {
 		{"zero amount", "0.00", CurrencyUSD, false, 0.0},
 		{"negative amount", "-50.25", CurrencySGD, false, -50.25},
 		{"empty currency", "100.00", "", true, 0},
+		{"unsupported currency", "100.00", "XYZ", true, 0},
+		{"zero-decimal currency", "1500", CurrencyJPY, false, 1500},
 		{"invalid amount", "invalid", CurrencyUSD, true, 0},
 		{"high precision", "123.456789", CurrencyUSD, false, 123.456789},
 	}
@@ -61,6 +64,11 @@ func TestZero(t *testing.T) {
 	// Test empty currency
 	_, err = Zero("")
 	assert.Error(t, err)
+
+	// Test unsupported currency
+	_, err = Zero("XYZ")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported currency")
 }
 
 func TestMoney_String(t *testing.T) {
@@ -76,6 +84,13 @@ func TestMoney_String(t *testing.T) {
 
 	// Test StringFixed
 	assert.Equal(t, "123.4568 USD", money.StringFixed(4))
+
+	// Test currency-specific precision
+	money, _ = NewMoney("1500", CurrencyJPY)
+	assert.Equal(t, "1500 JPY", money.String())
+
+	money, _ = NewMoney("12.5", CurrencyBHD)
+	assert.Equal(t, "12.500 BHD", money.String())
 }
 
 func TestMoney_Checks(t *testing.T) {
@@ -144,6 +159,37 @@ func TestMoney_Multiply(t *testing.T) {
 	assert.InDelta(t, 30.999999, result2.Float64(), 0.000001)
 }
 
+func TestMoney_MultiplyAndRound(t *testing.T) {
+	m, _ := NewMoney("10.05", CurrencyUSD)
+
+	half := decimal.RequireFromString("0.5")
+	assert.Equal(t, "5.03", m.MultiplyAndRound(half, RoundHalfUp).Amount.String())
+	assert.Equal(t, "5.02", m.MultiplyAndRound(half, RoundHalfEven).Amount.String())
+	assert.Equal(t, "5.02", m.MultiplyAndRound(half, RoundHalfDown).Amount.String())
+	assert.Equal(t, "5.02", m.MultiplyAndRound(half, RoundTruncate).Amount.String())
+}
+
+func TestMoney_DivideAndRound(t *testing.T) {
+	m, _ := NewMoney("100.00", CurrencyUSD)
+	three := decimal.NewFromInt(3)
+
+	result, err := m.DivideAndRound(three, RoundCeiling)
+	require.NoError(t, err)
+	assert.Equal(t, "33.34", result.Amount.String())
+
+	result, err = m.DivideAndRound(three, RoundFloor)
+	require.NoError(t, err)
+	assert.Equal(t, "33.33", result.Amount.String())
+
+	jpy, _ := NewMoney("1000", CurrencyJPY)
+	result, err = jpy.DivideAndRound(decimal.NewFromInt(8), RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, "125", result.Amount.String())
+
+	_, err = m.DivideAndRound(decimal.Zero, RoundHalfUp)
+	assert.Error(t, err)
+}
+
 func TestMoney_Negate(t *testing.T) {
 	positive, _ := NewMoney("100.00", CurrencyUSD)
 	negative, _ := NewMoney("-100.00", CurrencyUSD)
@@ -172,12 +218,69 @@ func TestMoney_Round(t *testing.T) {
 	assert.True(t, rounded0.Equals(expected0))
 }
 
+func TestMoney_RoundWithMode(t *testing.T) {
+	m, _ := NewMoney("-2.345", CurrencyUSD)
+
+	assert.Equal(t, "-2.35", m.RoundWithMode(2, RoundHalfUp).Amount.String())
+	assert.Equal(t, "-2.34", m.RoundWithMode(2, RoundHalfEven).Amount.String())
+	assert.Equal(t, "-2.34", m.RoundWithMode(2, RoundHalfDown).Amount.String())
+	assert.Equal(t, "-2.34", m.RoundWithMode(2, RoundCeiling).Amount.String())
+	assert.Equal(t, "-2.35", m.RoundWithMode(2, RoundFloor).Amount.String())
+	assert.Equal(t, "-2.34", m.RoundWithMode(2, RoundTruncate).Amount.String())
+
+	kwd, _ := NewMoney("1.2345", CurrencyKWD)
+	assert.Equal(t, "1.234", kwd.RoundCurrencyWithMode(RoundHalfDown).Amount.String())
+	assert.Equal(t, "1.235", kwd.RoundCurrencyWithMode(RoundCeiling).Amount.String())
+}
+
 func TestMoney_RoundCurrency(t *testing.T) {
 	// Test USD (2 decimal places)
 	usd, _ := NewMoney("123.456", CurrencyUSD)
 	roundedUSD := usd.RoundCurrency()
 	expectedUSD, _ := NewMoney("123.46", CurrencyUSD)
 	assert.True(t, roundedUSD.Equals(expectedUSD))
+
+	// Test JPY (0 decimal places)
+	jpy, _ := NewMoney("1234.5", CurrencyJPY)
+	expectedJPY, _ := NewMoney("1235", CurrencyJPY)
+	assert.True(t, jpy.RoundCurrency().Equals(expectedJPY))
+
+	// Test KWD (3 decimal places)
+	kwd, _ := NewMoney("10.12345", CurrencyKWD)
+	expectedKWD, _ := NewMoney("10.123", CurrencyKWD)
+	assert.True(t, kwd.RoundCurrency().Equals(expectedKWD))
+}
+
+func TestMoney_ValidatePrecision(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency Currency
+		wantErr  bool
+	}{
+		{"USD cents", "10.25", CurrencyUSD, false},
+		{"USD trailing zeros", "10.2500", CurrencyUSD, false},
+		{"USD fractional cent", "10.255", CurrencyUSD, true},
+		{"JPY whole", "1000", CurrencyJPY, false},
+		{"JPY fractional", "1000.5", CurrencyJPY, true},
+		{"BHD fils", "1.125", CurrencyBHD, false},
+		{"BHD sub-fils", "1.1255", CurrencyBHD, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMoney(tt.amount, tt.currency)
+			require.NoError(t, err)
+
+			err = m.ValidatePrecision()
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "exceeds")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
 }
 
 func TestMoney_Compare(t *testing.T) {
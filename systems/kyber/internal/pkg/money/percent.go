@@ -0,0 +1,100 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultPercentPlaces is the number of decimal places Percent.String displays
+const DefaultPercentPlaces int32 = 2
+
+var hundred = decimal.NewFromInt(100)
+
+// Percent represents a ratio with full decimal precision (0.25 is 25%).
+// Rounding only happens when formatting, so the same value always displays the same way.
+type Percent struct {
+	ratio decimal.Decimal
+}
+
+// NewPercent creates a Percent from percentage points, e.g. "12.5" or "12.5%" for 12.5%
+func NewPercent(points string) (Percent, error) {
+	trimmed := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(points), "%"))
+
+	d, err := decimal.NewFromString(trimmed)
+	if err != nil {
+		return Percent{}, fmt.Errorf("invalid percent format: %w", err)
+	}
+
+	return Percent{ratio: d.Div(hundred)}, nil
+}
+
+// NewPercentFromRatio creates a Percent from a ratio, e.g. 0.125 for 12.5%
+func NewPercentFromRatio(ratio decimal.Decimal) Percent {
+	return Percent{ratio: ratio}
+}
+
+// RatioOf returns part as a Percent of whole (must be same currency)
+func RatioOf(part, whole Money) (Percent, error) {
+	if err := checkSameUnit("compute ratio of", part.Currency, whole.Currency); err != nil {
+		return Percent{}, err
+	}
+
+	if whole.IsZero() {
+		return Percent{}, fmt.Errorf("cannot compute ratio of zero amount")
+	}
+
+	return Percent{ratio: part.Amount.Div(whole.Amount)}, nil
+}
+
+// Ratio returns the underlying ratio (0.25 for 25%)
+func (p Percent) Ratio() decimal.Decimal {
+	return p.ratio
+}
+
+// Points returns the value in percentage points (25 for 25%)
+func (p Percent) Points() decimal.Decimal {
+	return p.ratio.Mul(hundred)
+}
+
+// Of applies the percentage to an amount without rounding
+func (p Percent) Of(m Money) Money {
+	return m.Multiply(p.ratio)
+}
+
+// Round rounds the percentage to the given number of percentage-point decimal places
+func (p Percent) Round(places int32, mode RoundingMode) Percent {
+	return Percent{ratio: mode.apply(p.Points(), places).Div(hundred)}
+}
+
+// Format returns the percentage with a fixed number of decimal places, e.g. "33.33%"
+func (p Percent) Format(places int32, mode RoundingMode) string {
+	return mode.apply(p.Points(), places).StringFixed(places) + "%"
+}
+
+// String returns the percentage rounded half up to DefaultPercentPlaces, e.g. "33.33%"
+func (p Percent) String() string {
+	return p.Format(DefaultPercentPlaces, RoundHalfUp)
+}
+
+// Equals checks if two percentages are equal
+func (p Percent) Equals(other Percent) bool {
+	return p.ratio.Equal(other.ratio)
+}
+
+// Compare compares two percentages
+// Returns -1 if p < other, 0 if p == other, 1 if p > other
+func (p Percent) Compare(other Percent) int {
+	return p.ratio.Cmp(other.ratio)
+}
+
+// IsZero checks if the percentage is zero
+func (p Percent) IsZero() bool {
+	return p.ratio.IsZero()
+}
+
+// IsNegative checks if the percentage is negative
+func (p Percent) IsNegative() bool {
+	return p.ratio.IsNegative()
+}
@@ -0,0 +1,89 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPercent(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedRatio string
+		wantErr       bool
+	}{
+		{"plain points", "12.5", "0.125", false},
+		{"with percent sign", "12.5%", "0.125", false},
+		{"with spaces", " 33.333 % ", "0.33333", false},
+		{"negative", "-5", "-0.05", false},
+		{"invalid", "abc%", "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPercent(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, p.Ratio().Equal(decimal.RequireFromString(tt.expectedRatio)), "got %s", p.Ratio())
+		})
+	}
+}
+
+func TestRatioOf(t *testing.T) {
+	third, err := RatioOf(mustNewMoney(t, "100.00", CurrencyUSD), mustNewMoney(t, "300.00", CurrencyUSD))
+	require.NoError(t, err)
+	assert.Equal(t, "33.33%", third.String())
+	assert.Equal(t, "33.333%", third.Format(3, RoundHalfUp))
+	assert.Equal(t, "33.34%", third.Format(2, RoundCeiling))
+
+	_, err = RatioOf(mustNewMoney(t, "100.00", CurrencyUSD), mustNewMoney(t, "300.00", CurrencySGD))
+	assert.Error(t, err)
+
+	_, err = RatioOf(mustNewMoney(t, "100.00", CurrencyUSD), mustNewMoney(t, "0", CurrencyUSD))
+	assert.Error(t, err)
+}
+
+func TestPercent_FormatIsStable(t *testing.T) {
+	// The same value must always display the same way regardless of where it is formatted
+	p, err := NewPercent("33.335")
+	require.NoError(t, err)
+
+	assert.Equal(t, "33.34%", p.String())
+	assert.Equal(t, p.String(), p.Round(3, RoundHalfUp).String())
+	assert.Equal(t, "33.34%", p.Format(2, RoundHalfUp))
+	assert.Equal(t, "33.34%", p.Format(2, RoundHalfEven))
+	assert.Equal(t, "33.33%", p.Format(2, RoundHalfDown))
+	assert.Equal(t, "33%", p.Format(0, RoundHalfUp))
+	assert.Equal(t, "0.00%", Percent{}.String())
+}
+
+func TestPercent_Of(t *testing.T) {
+	p, err := NewPercent("7.5")
+	require.NoError(t, err)
+
+	fee := p.Of(mustNewMoney(t, "19.99", CurrencyUSD))
+	assert.True(t, fee.Amount.Equal(decimal.RequireFromString("1.49925")))
+	assert.Equal(t, "1.50", fee.RoundCurrencyWithMode(RoundHalfEven).Amount.StringFixed(2))
+}
+
+func TestPercent_Comparisons(t *testing.T) {
+	low := NewPercentFromRatio(decimal.RequireFromString("0.25"))
+	high, err := NewPercent("50")
+	require.NoError(t, err)
+
+	assert.Equal(t, -1, low.Compare(high))
+	assert.Equal(t, 1, high.Compare(low))
+	assert.True(t, low.Equals(NewPercentFromRatio(decimal.RequireFromString("0.250"))))
+	assert.True(t, low.Points().Equal(decimal.NewFromInt(25)))
+	assert.False(t, low.IsZero())
+	assert.False(t, low.IsNegative())
+	assert.True(t, Percent{}.IsZero())
+}
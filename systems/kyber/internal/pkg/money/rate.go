@@ -0,0 +1,87 @@
+package money
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrRateNotFound is returned when no exchange rate is available for a currency pair
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// Rate represents an exchange rate where 1 unit of From equals Value units of To,
+// effective from Date onwards until superseded by a later rate
+type Rate struct {
+	From  Currency
+	To    Currency
+	Value decimal.Decimal
+	Date  time.Time
+}
+
+// NewRate creates a new Rate
+func NewRate(from, to Currency, value decimal.Decimal, date time.Time) (Rate, error) {
+	if err := from.Validate(); err != nil {
+		return Rate{}, fmt.Errorf("invalid source currency: %w", err)
+	}
+
+	if err := to.Validate(); err != nil {
+		return Rate{}, fmt.Errorf("invalid target currency: %w", err)
+	}
+
+	if from == to {
+		return Rate{}, fmt.Errorf("source and target currency cannot be the same: %s", from)
+	}
+
+	if !value.IsPositive() {
+		return Rate{}, fmt.Errorf("exchange rate must be positive, got %s", value)
+	}
+
+	if date.IsZero() {
+		return Rate{}, fmt.Errorf("exchange rate date cannot be empty")
+	}
+
+	return Rate{
+		From:  from,
+		To:    to,
+		Value: value,
+		Date:  date,
+	}, nil
+}
+
+// Inverse returns the rate for the opposite direction
+func (r Rate) Inverse() Rate {
+	return Rate{
+		From:  r.To,
+		To:    r.From,
+		Value: decimal.NewFromInt(1).Div(r.Value),
+		Date:  r.Date,
+	}
+}
+
+// Apply converts an amount in the From currency into the To currency, rounded to the To currency's minor units
+func (r Rate) Apply(m Money, mode RoundingMode) (Money, error) {
+	if m.Currency != r.From {
+		return Money{}, fmt.Errorf("cannot apply %s/%s rate to %s amount", r.From, r.To, m.Currency)
+	}
+
+	return Money{
+		Amount:   m.Amount.Mul(r.Value),
+		Currency: r.To,
+	}.RoundCurrencyWithMode(mode), nil
+}
+
+// String returns the rate formatted as "1 FROM = VALUE TO (DATE)"
+func (r Rate) String() string {
+	return fmt.Sprintf("1 %s = %s %s (%s)", r.From, r.Value, r.To, r.Date.Format(time.DateOnly))
+}
+
+// RateProvider supplies dated exchange rates
+type RateProvider interface {
+	// Rate returns the latest rate for from→to effective on or before asOf.
+	// Implementations return ErrRateNotFound if no such rate exists; they are not
+	// expected to derive inverse or cross rates, which the Converter handles.
+	Rate(ctx context.Context, from, to Currency, asOf time.Time) (Rate, error)
+}
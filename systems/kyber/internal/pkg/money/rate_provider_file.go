@@ -0,0 +1,142 @@
+package money
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// NewFileRateProvider loads rates from a local CSV or JSON file into a MemoryRateProvider,
+// so conversions can run offline. The format is chosen by file extension (.csv or .json).
+//
+// CSV files have a header row followed by one rate per line:
+//
+//	date,from,to,rate
+//	2024-01-31,USD,SGD,1.3412
+//
+// JSON files hold an array of rate objects:
+//
+//	[{"date": "2024-01-31", "from": "USD", "to": "SGD", "rate": "1.3412"}]
+func NewFileRateProvider(path string) (*MemoryRateProvider, error) {
+	f, err := os.Open(path) // #nosec G304 - Path is operator-supplied configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rates file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var rates []Rate
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		rates, err = ParseRatesCSV(f)
+	case ".json":
+		rates, err = ParseRatesJSON(f)
+	default:
+		return nil, fmt.Errorf("unsupported rates file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rates from %s: %w", path, err)
+	}
+
+	return NewMemoryRateProvider(rates...)
+}
+
+// ParseRatesCSV parses rates from CSV with a "date,from,to,rate" header
+func ParseRatesCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("rates CSV is empty")
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	expected := []string{"date", "from", "to", "rate"}
+	for i, col := range header {
+		if strings.ToLower(strings.TrimSpace(col)) != expected[i] {
+			return nil, fmt.Errorf("unexpected CSV header %v, want %v", header, expected)
+		}
+	}
+
+	var rates []Rate
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV record: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rate, err := parseRateFields(record[0], record[1], record[2], record[3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+// rateJSON is the serialised form of a Rate in rate files
+type rateJSON struct {
+	Date string `json:"date"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	Rate string `json:"rate"`
+}
+
+// ParseRatesJSON parses rates from a JSON array of {"date", "from", "to", "rate"} objects
+func ParseRatesJSON(r io.Reader) ([]Rate, error) {
+	var records []rateJSON
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode rates JSON: %w", err)
+	}
+
+	rates := make([]Rate, 0, len(records))
+	for i, record := range records {
+		rate, err := parseRateFields(record.Date, record.From, record.To, record.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+func parseRateFields(date, from, to, value string) (Rate, error) {
+	d, err := time.Parse(time.DateOnly, strings.TrimSpace(date))
+	if err != nil {
+		return Rate{}, fmt.Errorf("invalid date %q: %w", date, err)
+	}
+
+	fromCurrency, err := NewCurrency(from)
+	if err != nil {
+		return Rate{}, fmt.Errorf("invalid source currency: %w", err)
+	}
+
+	toCurrency, err := NewCurrency(to)
+	if err != nil {
+		return Rate{}, fmt.Errorf("invalid target currency: %w", err)
+	}
+
+	v, err := decimal.NewFromString(strings.TrimSpace(value))
+	if err != nil {
+		return Rate{}, fmt.Errorf("invalid rate %q: %w", value, err)
+	}
+
+	return NewRate(fromCurrency, toCurrency, v, d)
+}
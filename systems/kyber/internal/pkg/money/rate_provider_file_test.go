@@ -0,0 +1,102 @@
+package money
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRatesCSV = `date,from,to,rate
+2024-01-31,USD,SGD,1.3412
+2024-02-29, usd ,sgd,1.3455
+2024-01-31,EUR,USD,1.0845
+`
+
+const testRatesJSON = `[
+	{"date": "2024-01-31", "from": "USD", "to": "SGD", "rate": "1.3412"},
+	{"date": "2024-02-29", "from": "USD", "to": "SGD", "rate": "1.3455"},
+	{"date": "2024-01-31", "from": "EUR", "to": "USD", "rate": "1.0845"}
+]`
+
+func TestParseRatesCSV(t *testing.T) {
+	rates, err := ParseRatesCSV(strings.NewReader(testRatesCSV))
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+
+	assert.Equal(t, CurrencyUSD, rates[1].From)
+	assert.Equal(t, CurrencySGD, rates[1].To)
+	assert.True(t, rates[1].Value.Equal(decimal.RequireFromString("1.3455")))
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), rates[1].Date)
+}
+
+func TestParseRatesCSV_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		errContains string
+	}{
+		{"empty", "", "empty"},
+		{"wrong header", "day,base,quote,value\n", "unexpected CSV header"},
+		{"bad date", "date,from,to,rate\n31/01/2024,USD,SGD,1.34\n", "line 2: invalid date"},
+		{"bad currency", "date,from,to,rate\n2024-01-31,USD,XYZ,1.34\n", "invalid target currency"},
+		{"bad rate", "date,from,to,rate\n2024-01-31,USD,SGD,abc\n", "invalid rate"},
+		{"wrong field count", "date,from,to,rate\n2024-01-31,USD,SGD\n", "failed to read CSV record"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRatesCSV(strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestParseRatesJSON(t *testing.T) {
+	rates, err := ParseRatesJSON(strings.NewReader(testRatesJSON))
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+	assert.Equal(t, CurrencyEUR, rates[2].From)
+
+	_, err = ParseRatesJSON(strings.NewReader(`{"not": "an array"}`))
+	assert.Error(t, err)
+
+	_, err = ParseRatesJSON(strings.NewReader(`[{"date": "2024-01-31", "from": "USD", "to": "SGD", "rate": "-1"}]`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "entry 0")
+}
+
+func TestNewFileRateProvider(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{"rates.csv": testRatesCSV, "rates.JSON": testRatesJSON} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+			provider, err := NewFileRateProvider(path)
+			require.NoError(t, err)
+
+			rate, err := provider.Rate(ctx, CurrencyUSD, CurrencySGD, time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC))
+			require.NoError(t, err)
+			assert.True(t, rate.Value.Equal(decimal.RequireFromString("1.3412")))
+		})
+	}
+
+	_, err := NewFileRateProvider(filepath.Join(dir, "missing.csv"))
+	assert.Error(t, err)
+
+	unsupported := filepath.Join(dir, "rates.xml")
+	require.NoError(t, os.WriteFile(unsupported, []byte("<rates/>"), 0o600))
+	_, err = NewFileRateProvider(unsupported)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported rates file format")
+}
@@ -0,0 +1,84 @@
+package money
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// currencyPair identifies a directed exchange rate series
+type currencyPair struct {
+	from Currency
+	to   Currency
+}
+
+// MemoryRateProvider is an in-memory RateProvider holding dated rate series per currency pair.
+// It is safe for concurrent use.
+type MemoryRateProvider struct {
+	mu    sync.RWMutex
+	rates map[currencyPair][]Rate // Sorted by Date ascending
+}
+
+// NewMemoryRateProvider creates a MemoryRateProvider seeded with the given rates
+func NewMemoryRateProvider(rates ...Rate) (*MemoryRateProvider, error) {
+	p := &MemoryRateProvider{
+		rates: make(map[currencyPair][]Rate),
+	}
+
+	for _, r := range rates {
+		if err := p.Add(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// Add stores a rate, replacing any existing rate for the same pair and date
+func (p *MemoryRateProvider) Add(r Rate) error {
+	validated, err := NewRate(r.From, r.To, r.Value, r.Date)
+	if err != nil {
+		return fmt.Errorf("invalid rate: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pair := currencyPair{from: validated.From, to: validated.To}
+	series := p.rates[pair]
+
+	idx := sort.Search(len(series), func(i int) bool {
+		return !series[i].Date.Before(validated.Date)
+	})
+
+	if idx < len(series) && series[idx].Date.Equal(validated.Date) {
+		series[idx] = validated
+	} else {
+		series = append(series, Rate{})
+		copy(series[idx+1:], series[idx:])
+		series[idx] = validated
+	}
+
+	p.rates[pair] = series
+	return nil
+}
+
+// Rate returns the latest rate for from→to effective on or before asOf
+func (p *MemoryRateProvider) Rate(_ context.Context, from, to Currency, asOf time.Time) (Rate, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	series := p.rates[currencyPair{from: from, to: to}]
+
+	// Index of the first rate dated after asOf; the one before it is effective
+	idx := sort.Search(len(series), func(i int) bool {
+		return series[i].Date.After(asOf)
+	})
+	if idx == 0 {
+		return Rate{}, fmt.Errorf("%w: %s/%s as of %s", ErrRateNotFound, from, to, asOf.Format(time.DateOnly))
+	}
+
+	return series[idx-1], nil
+}
@@ -0,0 +1,85 @@
+package money
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRateProvider_Rate(t *testing.T) {
+	ctx := context.Background()
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Added out of order to verify series are kept sorted
+	provider, err := NewMemoryRateProvider(
+		mustRate(t, CurrencyUSD, CurrencySGD, "1.36", mar),
+		mustRate(t, CurrencyUSD, CurrencySGD, "1.33", jan),
+		mustRate(t, CurrencyUSD, CurrencySGD, "1.34", feb),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		asOf     time.Time
+		expected string
+		wantErr  bool
+	}{
+		{"before first rate", jan.Add(-time.Hour), "", true},
+		{"exactly on date", jan, "1.33", false},
+		{"between dates uses earlier rate", feb.AddDate(0, 0, 14), "1.34", false},
+		{"after last rate", mar.AddDate(1, 0, 0), "1.36", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := provider.Rate(ctx, CurrencyUSD, CurrencySGD, tt.asOf)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrRateNotFound)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, rate.Value.Equal(decimal.RequireFromString(tt.expected)), "got %s", rate.Value)
+		})
+	}
+
+	// Providers do not derive inverse rates themselves
+	_, err = provider.Rate(ctx, CurrencySGD, CurrencyUSD, mar)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestMemoryRateProvider_Add(t *testing.T) {
+	ctx := context.Background()
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	provider, err := NewMemoryRateProvider()
+	require.NoError(t, err)
+
+	require.NoError(t, provider.Add(mustRate(t, CurrencyEUR, CurrencyUSD, "1.08", date)))
+	require.NoError(t, provider.Add(mustRate(t, CurrencyEUR, CurrencyUSD, "1.09", date)))
+
+	rate, err := provider.Rate(ctx, CurrencyEUR, CurrencyUSD, date)
+	require.NoError(t, err)
+	assert.True(t, rate.Value.Equal(decimal.RequireFromString("1.09")), "same-date rate should be replaced")
+
+	err = provider.Add(Rate{From: CurrencyEUR, To: CurrencyUSD, Value: decimal.Zero, Date: date})
+	assert.Error(t, err)
+
+	_, err = NewMemoryRateProvider(Rate{From: "XYZ", To: CurrencyUSD, Value: decimal.NewFromInt(1), Date: date})
+	assert.Error(t, err)
+}
+
+func mustRate(t *testing.T, from, to Currency, value string, date time.Time) Rate {
+	t.Helper()
+
+	r, err := NewRate(from, to, decimal.RequireFromString(value), date)
+	require.NoError(t, err)
+	return r
+}
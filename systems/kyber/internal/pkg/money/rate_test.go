@@ -0,0 +1,77 @@
+package money
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRate(t *testing.T) {
+	date := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		from        Currency
+		to          Currency
+		value       string
+		date        time.Time
+		errContains string
+	}{
+		{"valid", CurrencyUSD, CurrencySGD, "1.3412", date, ""},
+		{"invalid source", "XYZ", CurrencySGD, "1.3412", date, "invalid source currency"},
+		{"invalid target", CurrencyUSD, "", "1.3412", date, "invalid target currency"},
+		{"same currency", CurrencyUSD, CurrencyUSD, "1", date, "cannot be the same"},
+		{"zero rate", CurrencyUSD, CurrencySGD, "0", date, "must be positive"},
+		{"negative rate", CurrencyUSD, CurrencySGD, "-1.2", date, "must be positive"},
+		{"missing date", CurrencyUSD, CurrencySGD, "1.3412", time.Time{}, "date cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := NewRate(tt.from, tt.to, decimal.RequireFromString(tt.value), tt.date)
+
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.from, rate.From)
+			assert.Equal(t, tt.to, rate.To)
+			assert.Equal(t, "1 USD = 1.3412 SGD (2024-01-31)", rate.String())
+		})
+	}
+}
+
+func TestRate_Inverse(t *testing.T) {
+	rate, err := NewRate(CurrencyUSD, CurrencySGD, decimal.RequireFromString("1.25"), time.Now())
+	require.NoError(t, err)
+
+	inverse := rate.Inverse()
+	assert.Equal(t, CurrencySGD, inverse.From)
+	assert.Equal(t, CurrencyUSD, inverse.To)
+	assert.True(t, inverse.Value.Equal(decimal.RequireFromString("0.8")))
+	assert.Equal(t, rate.Date, inverse.Date)
+}
+
+func TestRate_Apply(t *testing.T) {
+	rate, err := NewRate(CurrencyUSD, CurrencySGD, decimal.RequireFromString("1.3412"), time.Now())
+	require.NoError(t, err)
+
+	converted, err := rate.Apply(mustNewMoney(t, "100.25", CurrencyUSD), RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, CurrencySGD, converted.Currency)
+	assert.Equal(t, "134.46 SGD", converted.String())
+
+	converted, err = rate.Apply(mustNewMoney(t, "0.01", CurrencyUSD), RoundFloor)
+	require.NoError(t, err)
+	assert.Equal(t, "0.01 SGD", converted.String())
+
+	_, err = rate.Apply(mustNewMoney(t, "1", CurrencyEUR), RoundHalfUp)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot apply USD/SGD rate to EUR amount")
+}
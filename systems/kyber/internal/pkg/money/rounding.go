@@ -0,0 +1,83 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode determines how an amount is rounded to a given number of decimal places
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to nearest, with ties away from zero (2.345 → 2.35, -2.345 → -2.35)
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to nearest, with ties to the even neighbour (banker's rounding)
+	RoundHalfEven
+	// RoundHalfDown rounds to nearest, with ties towards zero (2.345 → 2.34)
+	RoundHalfDown
+	// RoundCeiling rounds towards positive infinity
+	RoundCeiling
+	// RoundFloor rounds towards negative infinity
+	RoundFloor
+	// RoundTruncate drops extra digits, rounding towards zero
+	RoundTruncate
+)
+
+var roundingModeNames = map[RoundingMode]string{
+	RoundHalfUp:   "HALF_UP",
+	RoundHalfEven: "HALF_EVEN",
+	RoundHalfDown: "HALF_DOWN",
+	RoundCeiling:  "CEILING",
+	RoundFloor:    "FLOOR",
+	RoundTruncate: "TRUNCATE",
+}
+
+// NewRoundingMode creates a RoundingMode from its name, e.g. "HALF_EVEN"
+func NewRoundingMode(name string) (RoundingMode, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(name))
+	for mode, modeName := range roundingModeNames {
+		if modeName == normalized {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid rounding mode: %s", name)
+}
+
+// String returns the string representation of the rounding mode
+func (r RoundingMode) String() string {
+	if name, ok := roundingModeNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("RoundingMode(%d)", int(r))
+}
+
+// IsValid checks if the rounding mode is one of the supported modes
+func (r RoundingMode) IsValid() bool {
+	_, ok := roundingModeNames[r]
+	return ok
+}
+
+// apply rounds d to the given number of decimal places using the mode
+func (r RoundingMode) apply(d decimal.Decimal, places int32) decimal.Decimal {
+	switch r {
+	case RoundHalfEven:
+		return d.RoundBank(places)
+	case RoundHalfDown:
+		truncated := d.Truncate(places)
+		half := decimal.New(5, -(places + 1))
+		if d.Sub(truncated).Abs().Equal(half) {
+			return truncated
+		}
+		return d.Round(places)
+	case RoundCeiling:
+		return d.RoundCeil(places)
+	case RoundFloor:
+		return d.RoundFloor(places)
+	case RoundTruncate:
+		return d.Truncate(places)
+	default:
+		return d.Round(places)
+	}
+}
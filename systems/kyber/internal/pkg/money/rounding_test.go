@@ -0,0 +1,65 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundingMode_Apply(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected map[RoundingMode]string
+	}{
+		{"2.345", map[RoundingMode]string{
+			RoundHalfUp: "2.35", RoundHalfEven: "2.34", RoundHalfDown: "2.34",
+			RoundCeiling: "2.35", RoundFloor: "2.34", RoundTruncate: "2.34",
+		}},
+		{"2.355", map[RoundingMode]string{
+			RoundHalfUp: "2.36", RoundHalfEven: "2.36", RoundHalfDown: "2.35",
+			RoundCeiling: "2.36", RoundFloor: "2.35", RoundTruncate: "2.35",
+		}},
+		{"2.3451", map[RoundingMode]string{
+			RoundHalfUp: "2.35", RoundHalfEven: "2.35", RoundHalfDown: "2.35",
+			RoundCeiling: "2.35", RoundFloor: "2.34", RoundTruncate: "2.34",
+		}},
+		{"-2.345", map[RoundingMode]string{
+			RoundHalfUp: "-2.35", RoundHalfEven: "-2.34", RoundHalfDown: "-2.34",
+			RoundCeiling: "-2.34", RoundFloor: "-2.35", RoundTruncate: "-2.34",
+		}},
+		{"2.30", map[RoundingMode]string{
+			RoundHalfUp: "2.3", RoundHalfEven: "2.3", RoundHalfDown: "2.3",
+			RoundCeiling: "2.3", RoundFloor: "2.3", RoundTruncate: "2.3",
+		}},
+	}
+
+	for _, tt := range tests {
+		for mode, expected := range tt.expected {
+			t.Run(tt.value+"/"+mode.String(), func(t *testing.T) {
+				result := mode.apply(decimal.RequireFromString(tt.value), 2)
+				assert.True(t, result.Equal(decimal.RequireFromString(expected)), "got %s", result)
+			})
+		}
+	}
+}
+
+func TestNewRoundingMode(t *testing.T) {
+	for mode, name := range roundingModeNames {
+		parsed, err := NewRoundingMode(name)
+		require.NoError(t, err)
+		assert.Equal(t, mode, parsed)
+		assert.True(t, parsed.IsValid())
+	}
+
+	parsed, err := NewRoundingMode(" half_even ")
+	require.NoError(t, err)
+	assert.Equal(t, RoundHalfEven, parsed)
+
+	_, err = NewRoundingMode("BANKERS")
+	assert.Error(t, err)
+
+	assert.False(t, RoundingMode(99).IsValid())
+	assert.Equal(t, "RoundingMode(99)", RoundingMode(99).String())
+}
@@ -0,0 +1,94 @@
+package money
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// Compile-time checks that Money can be stored in NUMERIC minor-unit columns
+// through both database/sql and the native pgx interface
+var (
+	_ sql.Scanner           = (*Money)(nil)
+	_ driver.Valuer         = Money{}
+	_ pgtype.NumericScanner = (*Money)(nil)
+	_ pgtype.NumericValuer  = Money{}
+)
+
+// Value implements driver.Valuer, encoding the amount as NUMERIC minor units
+func (m Money) Value() (driver.Value, error) {
+	units, err := m.ToMinorUnitsDecimal()
+	if err != nil {
+		return nil, err
+	}
+	return units.String(), nil
+}
+
+// Scan implements sql.Scanner, decoding NUMERIC or BIGINT minor units.
+// The Currency must be set before scanning since the column carries no currency.
+func (m *Money) Scan(src any) error {
+	var units decimal.Decimal
+
+	switch v := src.(type) {
+	case nil:
+		return fmt.Errorf("cannot scan NULL into Money")
+	case int64:
+		units = decimal.NewFromInt(v)
+	case int32:
+		units = decimal.NewFromInt32(v)
+	case []byte:
+		parsed, err := decimal.NewFromString(string(v))
+		if err != nil {
+			return fmt.Errorf("cannot scan %q into Money: %w", v, err)
+		}
+		units = parsed
+	case string:
+		parsed, err := decimal.NewFromString(v)
+		if err != nil {
+			return fmt.Errorf("cannot scan %q into Money: %w", v, err)
+		}
+		units = parsed
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	return m.setMinorUnits(units)
+}
+
+// NumericValue implements pgtype.NumericValuer, encoding the amount as NUMERIC minor units
+func (m Money) NumericValue() (pgtype.Numeric, error) {
+	units, err := m.ToMinorUnitsDecimal()
+	if err != nil {
+		return pgtype.Numeric{}, err
+	}
+	return pgtype.Numeric{Int: units.BigInt(), Valid: true}, nil
+}
+
+// ScanNumeric implements pgtype.NumericScanner, decoding NUMERIC minor units.
+// The Currency must be set before scanning since the column carries no currency.
+func (m *Money) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid {
+		return fmt.Errorf("cannot scan NULL into Money")
+	}
+	if v.NaN || v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("cannot scan a non-finite number into Money")
+	}
+	return m.setMinorUnits(decimal.NewFromBigInt(v.Int, v.Exp))
+}
+
+func (m *Money) setMinorUnits(units decimal.Decimal) error {
+	if m.Currency == "" {
+		return fmt.Errorf("cannot scan into Money without a currency set")
+	}
+
+	decoded, err := FromMinorUnitsDecimal(units, m.Currency)
+	if err != nil {
+		return fmt.Errorf("cannot scan into Money: %w", err)
+	}
+
+	*m = decoded
+	return nil
+}
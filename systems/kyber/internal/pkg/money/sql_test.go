@@ -0,0 +1,100 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_Value(t *testing.T) {
+	m, err := NewMoney("123.45", CurrencyUSD)
+	require.NoError(t, err)
+
+	v, err := m.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "12345", v)
+
+	m, err = NewMoney("12345.678901234567890123", CurrencyETH)
+	require.NoError(t, err)
+
+	v, err = m.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "12345678901234567890123", v, "wei amounts go beyond int64")
+
+	m, err = NewMoney("1.005", CurrencyUSD)
+	require.NoError(t, err)
+
+	_, err = m.Value()
+	assert.Error(t, err)
+}
+
+func TestMoney_Scan(t *testing.T) {
+	tests := []struct {
+		name        string
+		currency    Currency
+		src         any
+		expected    string
+		errContains string
+	}{
+		{"int64", CurrencyUSD, int64(12345), "123.45", ""},
+		{"int32", CurrencyJPY, int32(1500), "1500", ""},
+		{"bytes", CurrencyBHD, []byte("-1250"), "-1.25", ""},
+		{"string", CurrencySGD, "999", "9.99", ""},
+		{"wei", CurrencyETH, "-1500000000000000000000", "-1500", ""},
+		{"fractional units", CurrencyUSD, "1.5", "", "must be whole"},
+		{"null", CurrencyUSD, nil, "", "NULL"},
+		{"unsupported type", CurrencyUSD, 1.5, "", "cannot scan float64"},
+		{"invalid text", CurrencyUSD, "abc", "", "cannot scan"},
+		{"currency not set", "", int64(100), "", "without a currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Money{Currency: tt.currency}
+			err := m.Scan(tt.src)
+
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			expected, err := NewMoney(tt.expected, tt.currency)
+			require.NoError(t, err)
+			assert.True(t, m.Equals(expected), "got %s, want %s", m, expected)
+		})
+	}
+}
+
+func TestMoney_PgxCodec(t *testing.T) {
+	typeMap := pgtype.NewMap()
+
+	for _, original := range []Money{
+		mustNewMoney(t, "-42.99", CurrencySGD),
+		mustNewMoney(t, "123456789.123456789012345678", CurrencyETH),
+	} {
+		for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+			buf, err := typeMap.Encode(pgtype.NumericOID, format, original, nil)
+			require.NoError(t, err)
+
+			decoded := Money{Currency: original.Currency}
+			err = typeMap.Scan(pgtype.NumericOID, format, buf, &decoded)
+			require.NoError(t, err)
+			assert.True(t, original.Equals(decoded), "format %d: got %s", format, decoded)
+		}
+	}
+
+	// Excess precision must fail to encode instead of truncating
+	tooPrecise, err := NewMoney("1.001", CurrencySGD)
+	require.NoError(t, err)
+	_, err = typeMap.Encode(pgtype.NumericOID, pgtype.BinaryFormatCode, tooPrecise, nil)
+	assert.Error(t, err)
+
+	// NULL must not silently decode to zero
+	decoded := Money{Currency: CurrencySGD}
+	err = typeMap.Scan(pgtype.NumericOID, pgtype.BinaryFormatCode, nil, &decoded)
+	assert.Error(t, err)
+}
@@ -0,0 +1,3 @@
+// Package mt940 parses SWIFT MT940 customer statement messages, including the structured :86:
+// fields used by German (GVC ?-subfields) and Dutch (/KEY/ subfields) banks
+package mt940
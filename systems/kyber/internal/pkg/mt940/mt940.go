@@ -0,0 +1,401 @@
+package mt940
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+)
+
+// Statement is an MT940 message: the statement of one account
+type Statement struct {
+	Reference string // Transaction reference (:20:)
+	Account   string // Account identification (:25:), e.g. an IBAN or BLZ/account number
+	Number    string // Statement/sequence number (:28C:)
+	Currency  string // ISO 4217 code of the opening balance
+	Opening   optional.Option[Balance]
+	Closing   optional.Option[Balance]
+	Entries   []Entry
+}
+
+// Balance is an opening (:60F:) or closing (:62F:) balance. Amounts are signed: negative when overdrawn.
+type Balance struct {
+	Amount decimal.Decimal
+	Date   time.Time
+}
+
+// Entry is a statement line (:61:) with its information to account owner (:86:).
+// Amounts are signed: negative for debits.
+type Entry struct {
+	ValueDate         time.Time
+	BookingDate       time.Time // Entry date; the value date when the bank omits it
+	Amount            decimal.Decimal
+	Reversal          bool   // RC or RD: the reversal of an earlier entry
+	TypeCode          string // e.g. NTRF or NMSC
+	CustomerReference string // NONREF when there is none
+	BankReference     string // Reference of the account servicing institution, if any
+	Details           string // Supplementary details: the second line of :61:, if any
+	CounterpartyName  string
+	CounterpartyIBAN  string
+	RemittanceInfo    string
+	Information       string // The :86: field as given, without line breaks
+}
+
+var (
+	fieldTag      = regexp.MustCompile(`^:(\d{2}[A-Z]?):`)
+	statementLine = regexp.MustCompile(`^(\d{6})(\d{4})?(R?[CD])([A-Z])?(\d+,\d*)([NFS][A-Z0-9]{3})(.*)$`)
+	gvcInfo       = regexp.MustCompile(`^\d{3}\?`)
+	gvcSubfield   = regexp.MustCompile(`\?(\d{2})`)
+)
+
+// slashKeys are the subfield keys of :86: fields structured as /KEY/value/, e.g. by Dutch banks
+var slashKeys = map[string]bool{
+	"TRTP": true, "IBAN": true, "BIC": true, "NAME": true, "REMI": true, "EREF": true, "MARF": true,
+	"CSID": true, "CNTP": true, "ORDP": true, "BENM": true, "ULTC": true, "ULTD": true, "PURP": true,
+	"RTRN": true, "ADDR": true, "ID": true, "SVCL": true, "ISDT": true, "CDTRREF": true, "CDTRREFTP": true,
+}
+
+// field is a tag and its value, with continuation lines joined by newlines
+type field struct {
+	tag   string
+	value string
+}
+
+// Parse reads all statements from an MT940 file, with or without SWIFT block headers
+func Parse(r io.Reader) ([]Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MT940 file: %w", err)
+	}
+
+	body := string(data)
+	if !utf8.ValidString(body) {
+		// MT940 files from banks are usually ISO 8859-1
+		body = decodeLatin1(data)
+	}
+
+	var (
+		statements []Statement
+		current    *Statement
+	)
+	flush := func() {
+		if current != nil {
+			statements = append(statements, *current)
+			current = nil
+		}
+	}
+
+	for _, f := range splitFields(body) {
+		if f.tag == "20" {
+			flush()
+			current = &Statement{
+				Reference: f.value,
+				Opening:   optional.None[Balance](),
+				Closing:   optional.None[Balance](),
+			}
+			continue
+		}
+		if f.tag == "-" {
+			flush()
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("not an MT940 file: field :%s: before :20:", f.tag)
+		}
+
+		if err := current.apply(f); err != nil {
+			return nil, fmt.Errorf("statement %s: %w", current.Reference, err)
+		}
+	}
+	flush()
+
+	if len(statements) == 0 {
+		return nil, errors.New("not an MT940 file: missing :20: field")
+	}
+	for _, s := range statements {
+		if s.Account == "" {
+			return nil, fmt.Errorf("statement %s has no account (:25:)", s.Reference)
+		}
+	}
+	return statements, nil
+}
+
+// apply adds a field to the statement
+func (s *Statement) apply(f field) error {
+	switch f.tag {
+	case "25":
+		s.Account = strings.TrimSpace(f.value)
+	case "28", "28C":
+		s.Number = strings.TrimSpace(f.value)
+	case "60F", "60M":
+		// An intermediate opening balance (60M) only continues a statement split across messages
+		if s.Opening.IsSome() {
+			return nil
+		}
+		balance, currency, err := parseBalance(f.value)
+		if err != nil {
+			return fmt.Errorf("invalid opening balance: %w", err)
+		}
+		s.Opening = optional.Some(balance)
+		s.Currency = currency
+	case "62F", "62M":
+		balance, currency, err := parseBalance(f.value)
+		if err != nil {
+			return fmt.Errorf("invalid closing balance: %w", err)
+		}
+		s.Closing = optional.Some(balance)
+		if s.Currency == "" {
+			s.Currency = currency
+		}
+	case "61":
+		entry, err := parseEntry(f.value)
+		if err != nil {
+			return fmt.Errorf("invalid statement line %q: %w", firstLine(f.value), err)
+		}
+		s.Entries = append(s.Entries, entry)
+	case "86":
+		// Information for the statement as a whole, before any :61:, is ignored
+		if len(s.Entries) > 0 {
+			s.Entries[len(s.Entries)-1].describe(f.value)
+		}
+	}
+	return nil
+}
+
+// splitFields splits the message text into fields. Lines of SWIFT block headers ({1:...}{2:...}{4:)
+// are skipped, and the end of a message ("-" or "-}") is returned as a field tagged "-".
+func splitFields(body string) []field {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = strings.ReplaceAll(body, "\r", "\n")
+
+	var fields []field
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "-" || trimmed == "-}" || strings.HasPrefix(trimmed, "-}{"):
+			fields = append(fields, field{tag: "-"})
+		case strings.HasPrefix(trimmed, "{"):
+			continue
+		case fieldTag.MatchString(line):
+			tag := fieldTag.FindStringSubmatch(line)[1]
+			fields = append(fields, field{tag: tag, value: line[len(tag)+2:]})
+		case len(fields) > 0 && fields[len(fields)-1].tag != "-" && trimmed != "":
+			fields[len(fields)-1].value += "\n" + strings.TrimRight(line, " ")
+		}
+	}
+	return fields
+}
+
+// parseBalance parses a balance such as C240131EUR3457,50
+func parseBalance(s string) (Balance, string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 11 {
+		return Balance{}, "", fmt.Errorf("%q is too short", s)
+	}
+
+	date, err := time.Parse("060102", s[1:7])
+	if err != nil {
+		return Balance{}, "", fmt.Errorf("invalid date %q", s[1:7])
+	}
+
+	amount, err := parseAmount(s[10:])
+	if err != nil {
+		return Balance{}, "", err
+	}
+
+	switch s[0] {
+	case 'C':
+	case 'D':
+		amount = amount.Neg()
+	default:
+		return Balance{}, "", fmt.Errorf("invalid debit/credit mark %q", s[0])
+	}
+
+	return Balance{Amount: amount, Date: date}, s[7:10], nil
+}
+
+// parseEntry parses a :61: field:
+// value date (YYMMDD), optional entry date (MMDD), mark (C, D, RC or RD), optional funds code, amount,
+// transaction type, customer reference, optional //bank reference, and an optional line of details.
+func parseEntry(s string) (Entry, error) {
+	line, details, _ := strings.Cut(s, "\n")
+
+	m := statementLine.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return Entry{}, errors.New("unrecognised format")
+	}
+
+	valueDate, err := time.Parse("060102", m[1])
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid value date %q", m[1])
+	}
+
+	amount, err := parseAmount(m[5])
+	if err != nil {
+		return Entry{}, err
+	}
+
+	mark := m[3]
+	// RC reverses a credit, so it takes money out of the account; RD reverses a debit
+	if mark == "D" || mark == "RC" {
+		amount = amount.Neg()
+	}
+
+	customerRef, bankRef, _ := strings.Cut(m[7], "//")
+
+	e := Entry{
+		ValueDate:         valueDate,
+		BookingDate:       valueDate,
+		Amount:            amount,
+		Reversal:          strings.HasPrefix(mark, "R"),
+		TypeCode:          m[6],
+		CustomerReference: strings.TrimSpace(customerRef),
+		BankReference:     strings.TrimSpace(bankRef),
+		Details:           strings.TrimSpace(details),
+	}
+	if m[2] != "" {
+		if e.BookingDate, err = bookingDate(valueDate, m[2]); err != nil {
+			return Entry{}, err
+		}
+	}
+	return e, nil
+}
+
+// bookingDate resolves an MMDD entry date to the year closest to the value date,
+// e.g. a value date of 31 December with an entry date of 0102 is booked in January of the next year
+func bookingDate(valueDate time.Time, mmdd string) (time.Time, error) {
+	date, err := time.Parse("0102", mmdd)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid entry date %q", mmdd)
+	}
+
+	year := valueDate.Year()
+	switch diff := int(date.Month()) - int(valueDate.Month()); {
+	case diff > 6:
+		year--
+	case diff < -6:
+		year++
+	}
+	return time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// describe fills the counterparty and remittance information from a :86: field
+func (e *Entry) describe(info string) {
+	joined := strings.ReplaceAll(info, "\n", "")
+	e.Information = strings.TrimSpace(joined)
+
+	switch {
+	case gvcInfo.MatchString(joined):
+		e.describeGVC(joined)
+	case strings.HasPrefix(strings.TrimSpace(joined), "/"):
+		e.describeSlashKeys(strings.TrimSpace(joined))
+	default:
+		// Free text is usually split into meaningful lines
+		e.RemittanceInfo = strings.Join(strings.Fields(strings.ReplaceAll(info, "\n", " ")), " ")
+	}
+}
+
+// describeGVC reads the ?NN subfields used by German banks after the 3-digit business transaction code:
+// ?20-?29 and ?60-?63 remittance, ?31 counterparty account, ?32-?33 counterparty name
+func (e *Entry) describeGVC(info string) {
+	var (
+		remittance, name []string
+		matches          = gvcSubfield.FindAllStringSubmatchIndex(info, -1)
+	)
+	for i, m := range matches {
+		end := len(info)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		// Subfields are chunks of at most 27 characters, so spaces at their edges are significant
+		code, value := info[m[2]:m[3]], info[m[1]:end]
+
+		switch {
+		case code >= "20" && code <= "29", code >= "60" && code <= "63":
+			remittance = append(remittance, value)
+		case code == "31":
+			e.CounterpartyIBAN = strings.TrimSpace(value)
+		case code == "32", code == "33":
+			name = append(name, value)
+		}
+	}
+
+	e.CounterpartyName = strings.TrimSpace(strings.Join(name, ""))
+	e.RemittanceInfo = strings.TrimSpace(strings.Join(remittance, ""))
+}
+
+// describeSlashKeys reads /KEY/value/ subfields, e.g. /IBAN/NL91ABNA0417164300/NAME/ACME/REMI/Invoice 17/.
+// ING's /CNTP/ holds the counterparty's account/BIC/name/city.
+func (e *Entry) describeSlashKeys(info string) {
+	values := make(map[string][]string)
+	var key string
+	for _, token := range strings.Split(strings.Trim(info, "/"), "/") {
+		if slashKeys[token] {
+			key = token
+			if _, ok := values[key]; !ok {
+				values[key] = []string{}
+			}
+			continue
+		}
+		if key != "" {
+			values[key] = append(values[key], token)
+		}
+	}
+
+	first := func(key string) string {
+		for _, v := range values[key] {
+			if v = strings.TrimSpace(v); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	e.CounterpartyName = first("NAME")
+	e.CounterpartyIBAN = first("IBAN")
+	if cntp := values["CNTP"]; len(cntp) > 0 {
+		e.CounterpartyIBAN = strings.TrimSpace(cntp[0])
+		if len(cntp) > 2 {
+			e.CounterpartyName = strings.TrimSpace(cntp[2])
+		}
+	}
+
+	var remittance []string
+	for _, v := range values["REMI"] {
+		// ING prefixes unstructured and structured remittance with USTD or STRD
+		if v = strings.TrimSpace(v); v != "" && v != "USTD" && v != "STRD" {
+			remittance = append(remittance, v)
+		}
+	}
+	e.RemittanceInfo = strings.Join(remittance, "/")
+}
+
+// parseAmount parses an MT940 amount, which always has a decimal comma
+func parseAmount(s string) (decimal.Decimal, error) {
+	amount, err := decimal.NewFromString(strings.Replace(strings.TrimSpace(s), ",", ".", 1))
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// decodeLatin1 decodes ISO 8859-1 bytes, each of which is the Unicode code point of the same value
+func decodeLatin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
@@ -0,0 +1,142 @@
+package mt940
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_GVC(t *testing.T) {
+	file, err := os.Open("testdata/gvc.sta")
+	require.NoError(t, err)
+	defer file.Close()
+
+	statements, err := Parse(file)
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+
+	s := statements[0]
+	assert.Equal(t, "STARTUMS", s.Reference)
+	assert.Equal(t, "37040044/0532013000", s.Account)
+	assert.Equal(t, "00001/001", s.Number)
+	assert.Equal(t, "EUR", s.Currency)
+	assert.Equal(t, "1000", s.Opening.Unwrap().Amount.String())
+	assert.Equal(t, time.Date(2023, 12, 29, 0, 0, 0, 0, time.UTC), s.Opening.Unwrap().Date)
+	assert.Equal(t, "3442.5", s.Closing.Unwrap().Amount.String())
+	require.Len(t, s.Entries, 3)
+
+	debit := s.Entries[0]
+	assert.Equal(t, "-42.5", debit.Amount.String())
+	assert.Equal(t, "NMSC", debit.TypeCode)
+	assert.Equal(t, "NONREF", debit.CustomerReference)
+	assert.Equal(t, "2024010500012", debit.BankReference)
+	assert.Equal(t, "Buero Bedarf AG", debit.CounterpartyName)
+	assert.Equal(t, "DE02120300000000202051", debit.CounterpartyIBAN)
+	assert.Equal(t, "EREF+INV-2024-17SVWZ+Invoice 2024-17 Printer paper", debit.RemittanceInfo)
+
+	credit := s.Entries[1]
+	assert.Equal(t, "2500", credit.Amount.String())
+	assert.Empty(t, credit.BankReference)
+	assert.Equal(t, "ACME Corp", credit.CounterpartyName)
+	assert.Equal(t, "NL91ABNA0417164300", credit.CounterpartyIBAN)
+	assert.Equal(t, "Salary January", credit.RemittanceInfo)
+
+	fee := s.Entries[2]
+	assert.Equal(t, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), fee.ValueDate)
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), fee.BookingDate, "booked in the next year")
+	assert.Equal(t, "Card fee", fee.Details)
+	assert.Equal(t, "Monthly card fee December", fee.RemittanceInfo)
+}
+
+func TestParse_SlashKeys(t *testing.T) {
+	file, err := os.Open("testdata/slash_keys.sta")
+	require.NoError(t, err)
+	defer file.Close()
+
+	statements, err := Parse(file)
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+
+	s := statements[0]
+	assert.Equal(t, "NL91ABNA0417164300", s.Account)
+	assert.Equal(t, "-200", s.Opening.Unwrap().Amount.String())
+	assert.Equal(t, "-152.5", s.Closing.Unwrap().Amount.String())
+	require.Len(t, s.Entries, 3)
+
+	transfer := s.Entries[0]
+	assert.Equal(t, time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), transfer.BookingDate, "value date when there is no entry date")
+	assert.Equal(t, "Buero Bedarf AG", transfer.CounterpartyName)
+	assert.Equal(t, "DE02120300000000202051", transfer.CounterpartyIBAN)
+	assert.Equal(t, "Invoice 2024-17", transfer.RemittanceInfo)
+	assert.Equal(t, "/TRCD/00100/", transfer.Details)
+
+	ing := s.Entries[1]
+	assert.Equal(t, "J. Jansen", ing.CounterpartyName)
+	assert.Equal(t, "NL20INGB0001234567", ing.CounterpartyIBAN)
+	assert.Equal(t, "Dinner share", ing.RemittanceInfo)
+
+	reversal := s.Entries[2]
+	assert.True(t, reversal.Reversal)
+	assert.Equal(t, "-10", reversal.Amount.String(), "reversing a credit takes money out")
+	assert.Equal(t, "Reversed deposit", reversal.RemittanceInfo)
+}
+
+func TestParse_SeveralStatements(t *testing.T) {
+	file := ":20:A\n:25:111\n:60F:C240101USD0,\n:62F:C240101USD0,\n-\n" +
+		":20:B\n:25:222\n:60F:C240101USD5,00\n:61:240102C1,NTRFNONREF\n:62F:C240102USD6,00\n-\n"
+
+	statements, err := Parse(strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, statements, 2)
+	assert.Equal(t, "111", statements[0].Account)
+	assert.Empty(t, statements[0].Entries)
+	assert.Equal(t, "222", statements[1].Account)
+	require.Len(t, statements[1].Entries, 1)
+	assert.Equal(t, "1", statements[1].Entries[0].Amount.String())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		errContains string
+	}{
+		{
+			name:        "empty",
+			file:        "",
+			errContains: "missing :20: field",
+		},
+		{
+			name:        "field before :20:",
+			file:        ":25:123\n",
+			errContains: "field :25: before :20:",
+		},
+		{
+			name:        "no account",
+			file:        ":20:A\n:60F:C240101EUR1,00\n",
+			errContains: "statement A has no account",
+		},
+		{
+			name:        "bad balance",
+			file:        ":20:A\n:25:1\n:60F:X240101EUR1,00\n",
+			errContains: "invalid debit/credit mark",
+		},
+		{
+			name:        "bad statement line",
+			file:        ":20:A\n:25:1\n:61:24010\n",
+			errContains: "unrecognised format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.file))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package ofx parses bank and credit card statements from OFX 1.x (SGML) and 2.x (XML) files, including QFX
+package ofx
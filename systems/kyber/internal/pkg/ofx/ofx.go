@@ -0,0 +1,326 @@
+package ofx
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kneadCODE/coruscant/shared/golib/optional"
+)
+
+// Statement is a bank (STMTRS) or credit card (CCSTMTRS) statement of one account
+type Statement struct {
+	BankID        string // Empty for credit card statements
+	AccountID     string // Account number as given by the bank
+	Currency      string // ISO 4217 code (CURDEF)
+	Start         time.Time
+	End           time.Time
+	Transactions  []Transaction
+	LedgerBalance optional.Option[Balance]
+}
+
+// Transaction is a statement transaction (STMTTRN). Amounts are signed: negative for debits.
+type Transaction struct {
+	FITID       string // Bank-assigned ID, unique per account
+	Type        string // TRNTYPE, e.g. DEBIT, CREDIT, POS, ATM, CHECK
+	Posted      time.Time
+	Amount      decimal.Decimal
+	Name        string // Payee name, from NAME or PAYEE/NAME
+	Memo        string
+	CheckNumber string
+}
+
+// Balance is the statement balance (LEDGERBAL) as of a date
+type Balance struct {
+	Amount decimal.Decimal
+	AsOf   time.Time
+}
+
+// Parse reads all statements from an OFX or QFX file
+func Parse(r io.Reader) ([]Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OFX file: %w", err)
+	}
+
+	body := string(data)
+	if !utf8.ValidString(body) {
+		// OFX 1.x files are usually CHARSET:1252; decode bytes as Latin-1, which matches it for letters
+		body = decodeLatin1(data)
+	}
+
+	start := strings.Index(strings.ToUpper(body), "<OFX>")
+	if start < 0 {
+		return nil, fmt.Errorf("not an OFX file: missing <OFX> element")
+	}
+
+	root := parseElements(body[start:])
+
+	var statements []Statement
+	var walkErr error
+	root.walk(func(n *node) {
+		if walkErr != nil {
+			return
+		}
+		switch n.name {
+		case "STMTTRNRS", "CCSTMTTRNRS":
+			if code := n.value("STATUS", "CODE"); code != "" && code != "0" {
+				walkErr = fmt.Errorf("bank returned status %s: %s", code, n.value("STATUS", "MESSAGE"))
+			}
+		case "STMTRS", "CCSTMTRS":
+			var statement Statement
+			statement, walkErr = parseStatement(n)
+			statements = append(statements, statement)
+		}
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("OFX file contains no bank or credit card statement")
+	}
+	return statements, nil
+}
+
+func parseStatement(n *node) (Statement, error) {
+	s := Statement{
+		Currency: strings.ToUpper(n.value("CURDEF")),
+	}
+
+	if account := n.child("BANKACCTFROM"); account != nil {
+		s.BankID = account.value("BANKID")
+		s.AccountID = account.value("ACCTID")
+	} else {
+		s.AccountID = n.value("CCACCTFROM", "ACCTID")
+	}
+	if s.AccountID == "" {
+		return Statement{}, fmt.Errorf("statement has no account ID")
+	}
+
+	var err error
+	if list := n.child("BANKTRANLIST"); list != nil {
+		if s.Start, err = parseOptionalDate(list.value("DTSTART")); err != nil {
+			return Statement{}, fmt.Errorf("invalid DTSTART: %w", err)
+		}
+		if s.End, err = parseOptionalDate(list.value("DTEND")); err != nil {
+			return Statement{}, fmt.Errorf("invalid DTEND: %w", err)
+		}
+
+		for _, trn := range list.children {
+			if trn.name != "STMTTRN" {
+				continue
+			}
+			tx, err := parseTransaction(trn)
+			if err != nil {
+				return Statement{}, err
+			}
+			s.Transactions = append(s.Transactions, tx)
+		}
+	}
+
+	s.LedgerBalance = optional.None[Balance]()
+	if bal := n.child("LEDGERBAL"); bal != nil {
+		amount, err := parseAmount(bal.value("BALAMT"))
+		if err != nil {
+			return Statement{}, fmt.Errorf("invalid ledger balance: %w", err)
+		}
+		asOf, err := parseOptionalDate(bal.value("DTASOF"))
+		if err != nil {
+			return Statement{}, fmt.Errorf("invalid ledger balance date: %w", err)
+		}
+		s.LedgerBalance = optional.Some(Balance{Amount: amount, AsOf: asOf})
+	}
+
+	return s, nil
+}
+
+func parseTransaction(n *node) (Transaction, error) {
+	tx := Transaction{
+		FITID:       n.value("FITID"),
+		Type:        strings.ToUpper(n.value("TRNTYPE")),
+		Name:        n.value("NAME"),
+		Memo:        n.value("MEMO"),
+		CheckNumber: n.value("CHECKNUM"),
+	}
+	if tx.Name == "" {
+		tx.Name = n.value("PAYEE", "NAME")
+	}
+
+	if tx.FITID == "" {
+		return Transaction{}, fmt.Errorf("transaction %q has no FITID", tx.Name)
+	}
+
+	var err error
+	if tx.Amount, err = parseAmount(n.value("TRNAMT")); err != nil {
+		return Transaction{}, fmt.Errorf("invalid amount of transaction %s: %w", tx.FITID, err)
+	}
+
+	if tx.Posted, err = parseDate(n.value("DTPOSTED")); err != nil {
+		return Transaction{}, fmt.Errorf("invalid posted date of transaction %s: %w", tx.FITID, err)
+	}
+
+	return tx, nil
+}
+
+// parseAmount parses an OFX amount; some banks use a decimal comma
+func parseAmount(s string) (decimal.Decimal, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, ".") {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	return decimal.NewFromString(strings.TrimPrefix(s, "+"))
+}
+
+func parseOptionalDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return parseDate(s)
+}
+
+// parseDate parses an OFX datetime: YYYYMMDD[HHMMSS[.XXX]][[gmt offset[:tz name]]], e.g. 20240115120000.000[-5:EST].
+// Dates without an offset are in UTC.
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	loc := time.UTC
+
+	if i := strings.IndexByte(s, '['); i >= 0 {
+		zone := strings.TrimSuffix(s[i+1:], "]")
+		s = s[:i]
+
+		offset, name, _ := strings.Cut(zone, ":")
+		hours, err := strconv.ParseFloat(offset, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time zone %q", zone)
+		}
+		if name == "" {
+			name = "GMT" + offset
+		}
+		loc = time.FixedZone(name, int(hours*3600))
+	}
+
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s = s[:i]
+	}
+
+	var layout string
+	switch len(s) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+
+	return time.ParseInLocation(layout, s, loc)
+}
+
+// node is an OFX element: an aggregate with children or a leaf with a value
+type node struct {
+	name     string
+	text     string
+	children []*node
+}
+
+// parseElements builds the element tree. It accepts both XML and SGML, where leaf elements
+// have no closing tag: a closing tag closes every element opened since its matching start tag.
+func parseElements(body string) *node {
+	root := &node{}
+	stack := []*node{root}
+
+	for len(body) > 0 {
+		lt := strings.IndexByte(body, '<')
+		if lt < 0 {
+			break
+		}
+		gt := strings.IndexByte(body[lt:], '>')
+		if gt < 0 {
+			break
+		}
+		tag := strings.TrimSpace(body[lt+1 : lt+gt])
+		body = body[lt+gt+1:]
+
+		switch {
+		case tag == "" || tag[0] == '?' || tag[0] == '!':
+			continue
+
+		case tag[0] == '/':
+			name := strings.ToUpper(strings.TrimSpace(tag[1:]))
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+
+		default:
+			selfClosing := strings.HasSuffix(tag, "/")
+			name, _, _ := strings.Cut(strings.TrimSuffix(tag, "/"), " ")
+			n := &node{name: strings.ToUpper(name)}
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, n)
+
+			if selfClosing {
+				continue
+			}
+
+			end := strings.IndexByte(body, '<')
+			if end < 0 {
+				end = len(body)
+			}
+			if text := strings.TrimSpace(body[:end]); text != "" {
+				n.text = html.UnescapeString(text)
+				continue
+			}
+			stack = append(stack, n)
+		}
+	}
+
+	return root
+}
+
+// child returns the first direct child with the name
+func (n *node) child(name string) *node {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// value returns the text of the element at the path below n, or "" if there is none
+func (n *node) value(path ...string) string {
+	for _, name := range path {
+		if n = n.child(name); n == nil {
+			return ""
+		}
+	}
+	return n.text
+}
+
+// walk calls fn for n and all of its descendants, depth first
+func (n *node) walk(fn func(n *node)) {
+	fn(n)
+	for _, c := range n.children {
+		c.walk(fn)
+	}
+}
+
+func decodeLatin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
@@ -0,0 +1,165 @@
+package ofx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sgmlStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+CHARSET:1252
+COMPRESSION:NONE
+OLDFILEUID:NONE
+NEWFILEUID:NONE
+
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS><DTSERVER>20240201120000<LANGUAGE>ENG</SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<TRNUID>1
+<STATUS><CODE>0<SEVERITY>INFO</STATUS>
+<STMTRS>
+<CURDEF>USD
+<BANKACCTFROM><BANKID>121000248<ACCTID>000123456789<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20240101
+<DTEND>20240131
+<STMTTRN>
+<TRNTYPE>POS
+<DTPOSTED>20240105120000.000[-5:EST]
+<TRNAMT>-42.50
+<FITID>2024010501
+<NAME>WHOLE FOODS &amp; CO
+<MEMO>Groceries
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240115
+<TRNAMT>2500,00
+<FITID>2024011501
+<NAME>ACME PAYROLL
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>3457.50<DTASOF>20240131</LEDGERBAL>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`
+
+const xmlStatement = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <CREDITCARDMSGSRSV1>
+    <CCSTMTTRNRS>
+      <TRNUID>1</TRNUID>
+      <STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+      <CCSTMTRS>
+        <CURDEF>sgd</CURDEF>
+        <CCACCTFROM><ACCTID>4111111111111111</ACCTID></CCACCTFROM>
+        <BANKTRANLIST>
+          <DTSTART>20240301000000</DTSTART>
+          <DTEND>20240331000000</DTEND>
+          <STMTTRN>
+            <TRNTYPE>DEBIT</TRNTYPE>
+            <DTPOSTED>20240310</DTPOSTED>
+            <TRNAMT>-18.90</TRNAMT>
+            <FITID>CC-1</FITID>
+            <PAYEE><NAME>Grab</NAME></PAYEE>
+            <MEMO></MEMO>
+          </STMTTRN>
+        </BANKTRANLIST>
+        <LEDGERBAL><BALAMT>-18.90</BALAMT><DTASOF>20240331</DTASOF></LEDGERBAL>
+      </CCSTMTRS>
+    </CCSTMTTRNRS>
+  </CREDITCARDMSGSRSV1>
+</OFX>
+`
+
+func TestParse(t *testing.T) {
+	t.Run("SGML bank statement", func(t *testing.T) {
+		statements, err := Parse(strings.NewReader(sgmlStatement))
+		require.NoError(t, err)
+		require.Len(t, statements, 1)
+
+		s := statements[0]
+		assert.Equal(t, "121000248", s.BankID)
+		assert.Equal(t, "000123456789", s.AccountID)
+		assert.Equal(t, "USD", s.Currency)
+		assert.Equal(t, "2024-01-31", s.End.Format(time.DateOnly))
+
+		require.Len(t, s.Transactions, 2)
+		assert.Equal(t, "2024010501", s.Transactions[0].FITID)
+		assert.Equal(t, "POS", s.Transactions[0].Type)
+		assert.Equal(t, "-42.5", s.Transactions[0].Amount.String())
+		assert.Equal(t, "WHOLE FOODS & CO", s.Transactions[0].Name)
+		assert.Equal(t, "Groceries", s.Transactions[0].Memo)
+		assert.Equal(t, "2024-01-05T12:00:00-05:00", s.Transactions[0].Posted.Format(time.RFC3339))
+		assert.Equal(t, "2500", s.Transactions[1].Amount.String())
+
+		require.True(t, s.LedgerBalance.IsSome())
+		assert.Equal(t, "3457.5", s.LedgerBalance.Unwrap().Amount.String())
+	})
+
+	t.Run("XML credit card statement", func(t *testing.T) {
+		statements, err := Parse(strings.NewReader(xmlStatement))
+		require.NoError(t, err)
+		require.Len(t, statements, 1)
+
+		s := statements[0]
+		assert.Empty(t, s.BankID)
+		assert.Equal(t, "4111111111111111", s.AccountID)
+		assert.Equal(t, "SGD", s.Currency)
+		require.Len(t, s.Transactions, 1)
+		assert.Equal(t, "Grab", s.Transactions[0].Name)
+		assert.Empty(t, s.Transactions[0].Memo)
+		assert.Equal(t, "-18.9", s.LedgerBalance.Unwrap().Amount.String())
+	})
+
+	errTests := []struct {
+		name        string
+		file        string
+		errContains string
+	}{
+		{name: "not OFX", file: "Date,Amount\n2024-01-01,10", errContains: "missing <OFX>"},
+		{name: "no statement", file: "<OFX><SIGNONMSGSRSV1></SIGNONMSGSRSV1></OFX>", errContains: "no bank or credit card statement"},
+		{
+			name:        "error status",
+			file:        "<OFX><STMTTRNRS><STATUS><CODE>2000<MESSAGE>General error</STATUS></STMTTRNRS></OFX>",
+			errContains: "status 2000: General error",
+		},
+		{
+			name:        "missing FITID",
+			file:        strings.Replace(sgmlStatement, "<FITID>2024010501", "", 1),
+			errContains: "has no FITID",
+		},
+		{
+			name:        "invalid date",
+			file:        strings.Replace(sgmlStatement, "<DTPOSTED>20240115", "<DTPOSTED>2024-01-15", 1),
+			errContains: "invalid posted date",
+		},
+	}
+
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.file))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestParse_Latin1(t *testing.T) {
+	file := strings.Replace(sgmlStatement, "WHOLE FOODS &amp; CO", "CAF\xc9 ROUGE", 1)
+
+	statements, err := Parse(strings.NewReader(file))
+	require.NoError(t, err)
+	assert.Equal(t, "CAFÉ ROUGE", statements[0].Transactions[0].Name)
+}
@@ -0,0 +1,2 @@
+// Package rrule expands RFC 5545 recurrence rules into occurrence dates
+package rrule
@@ -0,0 +1,464 @@
+package rrule
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is how often a rule repeats (RFC 5545 FREQ)
+type Frequency string
+
+// Supported frequencies
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+	FrequencyYearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry: a weekday, optionally the Nth (or Nth from last when negative) of the month
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int // 0 means every such weekday
+}
+
+// Rule is a recurrence rule following a subset of RFC 5545: FREQ, INTERVAL, COUNT, UNTIL,
+// BYDAY, BYMONTHDAY and BYMONTH. Weeks start on Monday. Occurrences keep the time of day of
+// the start date; dates that do not exist (e.g. the 31st in April) are skipped as the RFC requires.
+type Rule struct {
+	Freq       Frequency
+	Interval   int       // Defaults to 1
+	Count      int       // Total number of occurrences; 0 means no limit
+	Until      time.Time // Last possible occurrence (inclusive); zero means no limit
+	ByDay      []WeekdayNum
+	ByMonthDay []int // 1..31 or -31..-1 counted from the end of the month
+	ByMonth    []time.Month
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Parse parses an RRULE value such as "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12", with or without the "RRULE:" prefix
+func Parse(value string) (Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return Rule{}, fmt.Errorf("recurrence rule cannot be empty")
+	}
+
+	var r Rule
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return Rule{}, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		name = strings.ToUpper(name)
+		if seen[name] {
+			return Rule{}, fmt.Errorf("duplicate rule part %s", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			r.Freq = Frequency(strings.ToUpper(val))
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(val)
+		case "COUNT":
+			r.Count, err = strconv.Atoi(val)
+			if err == nil && r.Count < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "UNTIL":
+			r.Until, err = parseUntil(val)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseInts(val, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseInts(val, 1, 12)
+			for _, m := range months {
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "WKST":
+			if strings.ToUpper(val) != "MO" {
+				err = fmt.Errorf("only MO is supported")
+			}
+		default:
+			return Rule{}, fmt.Errorf("unsupported rule part %s", name)
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid %s %q: %w", name, val, err)
+		}
+	}
+
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+
+	if err := r.Validate(); err != nil {
+		return Rule{}, err
+	}
+	return r, nil
+}
+
+// Validate checks the rule is complete and consistent
+func (r Rule) Validate() error {
+	switch r.Freq {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	case "":
+		return fmt.Errorf("recurrence frequency is required")
+	default:
+		return fmt.Errorf("unsupported recurrence frequency: %s", r.Freq)
+	}
+
+	if r.Interval < 1 {
+		return fmt.Errorf("recurrence interval must be positive")
+	}
+
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fmt.Errorf("recurrence cannot set both COUNT and UNTIL")
+	}
+
+	for _, d := range r.ByMonthDay {
+		if d == 0 || d < -31 || d > 31 {
+			return fmt.Errorf("invalid BYMONTHDAY: %d", d)
+		}
+	}
+
+	for _, wd := range r.ByDay {
+		if wd.N == 0 {
+			continue
+		}
+		if wd.N < -5 || wd.N > 5 {
+			return fmt.Errorf("invalid BYDAY ordinal: %d", wd.N)
+		}
+		if r.Freq != FrequencyMonthly && (r.Freq != FrequencyYearly || len(r.ByMonth) == 0) {
+			return fmt.Errorf("BYDAY ordinals are only supported with FREQ=MONTHLY, or FREQ=YEARLY with BYMONTH")
+		}
+	}
+
+	return nil
+}
+
+// HasEnd checks if the rule stops after a number of occurrences or a date
+func (r Rule) HasEnd() bool {
+	return r.Count > 0 || !r.Until.IsZero()
+}
+
+// String formats the rule as an RRULE value
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = wd.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// String formats the entry as a BYDAY value, e.g. "MO" or "-1FR"
+func (w WeekdayNum) String() string {
+	code := strings.ToUpper(w.Weekday.String()[:2])
+	if w.N == 0 {
+		return code
+	}
+	return strconv.Itoa(w.N) + code
+}
+
+// maxEmptyPeriods bounds the search for rules that can never match again, e.g. February 30th
+const maxEmptyPeriods = 1000
+
+// Iterate calls fn with each occurrence starting at start, in order, until fn returns false
+// or the rule ends. A rule without COUNT or UNTIL only stops when fn returns false.
+func (r Rule) Iterate(start time.Time, fn func(occurrence time.Time) bool) {
+	count, empty := 0, 0
+	for period := 0; empty < maxEmptyPeriods; period++ {
+		candidates, periodStart := r.expand(start, period*r.Interval)
+
+		if !r.Until.IsZero() && periodStart.After(r.Until) {
+			return
+		}
+
+		if len(candidates) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+
+		for _, occurrence := range candidates {
+			if occurrence.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && occurrence.After(r.Until) {
+				return
+			}
+			if !fn(occurrence) {
+				return
+			}
+			count++
+			if r.Count > 0 && count >= r.Count {
+				return
+			}
+		}
+	}
+}
+
+// Between returns the occurrences within [from, to)
+func (r Rule) Between(start, from, to time.Time) []time.Time {
+	var occurrences []time.Time
+	r.Iterate(start, func(occurrence time.Time) bool {
+		if !occurrence.Before(to) {
+			return false
+		}
+		if !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return true
+	})
+	return occurrences
+}
+
+// Includes checks if the given time is an occurrence of the rule
+func (r Rule) Includes(start, t time.Time) bool {
+	found := false
+	r.Iterate(start, func(occurrence time.Time) bool {
+		if occurrence.Equal(t) {
+			found = true
+		}
+		return occurrence.Before(t)
+	})
+	return found
+}
+
+// expand returns the sorted candidate occurrences of the period offset periods after start's, and the period's first day
+func (r Rule) expand(start time.Time, offset int) ([]time.Time, time.Time) {
+	year, month, day := start.Date()
+	hour, minute, sec := start.Clock()
+	loc := start.Location()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, minute, sec, start.Nanosecond(), loc)
+	}
+
+	var candidates []time.Time
+	var periodStart time.Time
+
+	switch r.Freq {
+	case FrequencyDaily:
+		d := at(year, month, day+offset)
+		periodStart = d
+		if r.matchesMonth(d) && r.matchesMonthDay(d) && r.matchesWeekday(d) {
+			candidates = append(candidates, d)
+		}
+
+	case FrequencyWeekly:
+		monday := at(year, month, day-daysSinceMonday(start.Weekday())+7*offset)
+		periodStart = monday
+		weekdays := []WeekdayNum{{Weekday: start.Weekday()}}
+		if len(r.ByDay) > 0 {
+			weekdays = r.ByDay
+		}
+		for _, wd := range weekdays {
+			d := monday.AddDate(0, 0, daysSinceMonday(wd.Weekday))
+			if r.matchesMonth(d) {
+				candidates = append(candidates, d)
+			}
+		}
+
+	case FrequencyMonthly:
+		first := at(year, month+time.Month(offset), 1)
+		periodStart = first
+		if r.matchesMonth(first) {
+			candidates = r.expandMonth(first, day)
+		}
+
+	case FrequencyYearly:
+		periodStart = at(year+offset, time.January, 1)
+		months := []time.Month{month}
+		if len(r.ByMonth) > 0 {
+			months = r.ByMonth
+		}
+		for _, m := range months {
+			candidates = append(candidates, r.expandMonth(at(year+offset, m, 1), day)...)
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(candidates, time.Time.Equal), periodStart
+}
+
+// expandMonth returns the candidate days of the month starting at first; startDay is used when there is no BYxxx day rule
+func (r Rule) expandMonth(first time.Time, startDay int) []time.Time {
+	lastDay := first.AddDate(0, 1, -1).Day()
+	var candidates []time.Time
+
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, d := range r.ByMonthDay {
+			if d < 0 {
+				d = lastDay + d + 1
+			}
+			if d < 1 || d > lastDay {
+				continue
+			}
+			c := first.AddDate(0, 0, d-1)
+			if r.matchesWeekday(c) {
+				candidates = append(candidates, c)
+			}
+		}
+
+	case len(r.ByDay) > 0:
+		for _, wd := range r.ByDay {
+			firstMatch := 1 + (int(wd.Weekday)-int(first.Weekday())+7)%7
+			var days []int
+			for d := firstMatch; d <= lastDay; d += 7 {
+				days = append(days, d)
+			}
+
+			switch {
+			case wd.N == 0:
+			case wd.N > 0 && wd.N <= len(days):
+				days = days[wd.N-1 : wd.N]
+			case wd.N < 0 && -wd.N <= len(days):
+				days = days[len(days)+wd.N : len(days)+wd.N+1]
+			default:
+				days = nil
+			}
+
+			for _, d := range days {
+				candidates = append(candidates, first.AddDate(0, 0, d-1))
+			}
+		}
+
+	default:
+		if startDay <= lastDay {
+			candidates = append(candidates, first.AddDate(0, 0, startDay-1))
+		}
+	}
+
+	return candidates
+}
+
+func (r Rule) matchesMonth(t time.Time) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, t.Month())
+}
+
+func (r Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	lastDay := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	for _, d := range r.ByMonthDay {
+		if d == t.Day() || (d < 0 && lastDay+d+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekday checks BYDAY entries without an ordinal, which limit other rules
+func (r Rule) matchesWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if wd.Weekday == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func daysSinceMonday(w time.Weekday) int {
+	return (int(w) + 6) % 7
+}
+
+func parseUntil(val string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, val); err == nil {
+			if layout == "20060102" {
+				// A date-only UNTIL includes the whole day
+				t = t.Add(24*time.Hour - time.Nanosecond)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected YYYYMMDD or YYYYMMDDTHHMMSSZ")
+}
+
+func parseByDay(val string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, entry := range strings.Split(strings.ToUpper(val), ",") {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", entry)
+		}
+
+		weekday, ok := weekdayCodes[entry[len(entry)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", entry)
+		}
+
+		n := 0
+		if ordinal := entry[:len(entry)-2]; ordinal != "" {
+			var err error
+			n, err = strconv.Atoi(strings.TrimPrefix(ordinal, "+"))
+			if err != nil || n == 0 {
+				return nil, fmt.Errorf("invalid weekday ordinal %q", entry)
+			}
+		}
+
+		days = append(days, WeekdayNum{Weekday: weekday, N: n})
+	}
+	return days, nil
+}
+
+func parseInts(val string, lo, hi int) ([]int, error) {
+	var values []int
+	for _, s := range strings.Split(val, ",") {
+		n, err := strconv.Atoi(strings.TrimPrefix(s, "+"))
+		if err != nil || n == 0 || n < lo || n > hi {
+			return nil, fmt.Errorf("value %q out of range", s)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
@@ -0,0 +1,135 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		want        string
+		wantErr     bool
+		errContains string
+	}{
+		{name: "monthly", value: "FREQ=MONTHLY", want: "FREQ=MONTHLY"},
+		{name: "with prefix", value: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR"},
+		{name: "last day of month", value: "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12", want: "FREQ=MONTHLY;COUNT=12;BYMONTHDAY=-1"},
+		{name: "nth weekday", value: "freq=monthly;byday=-1fr", want: "FREQ=MONTHLY;BYDAY=-1FR"},
+		{name: "until date", value: "FREQ=DAILY;UNTIL=20240131", want: "FREQ=DAILY;UNTIL=20240131T235959Z"},
+		{name: "yearly by month", value: "FREQ=YEARLY;BYMONTH=1,7", want: "FREQ=YEARLY;BYMONTH=1,7"},
+		{name: "empty", value: "", wantErr: true, errContains: "cannot be empty"},
+		{name: "missing frequency", value: "INTERVAL=2", wantErr: true, errContains: "frequency is required"},
+		{name: "unsupported frequency", value: "FREQ=HOURLY", wantErr: true, errContains: "unsupported recurrence frequency"},
+		{name: "unsupported part", value: "FREQ=DAILY;BYHOUR=9", wantErr: true, errContains: "unsupported rule part BYHOUR"},
+		{name: "count and until", value: "FREQ=DAILY;COUNT=3;UNTIL=20240131", wantErr: true, errContains: "both COUNT and UNTIL"},
+		{name: "invalid month day", value: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true, errContains: "invalid BYMONTHDAY"},
+		{name: "ordinal with weekly", value: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true, errContains: "BYDAY ordinals"},
+		{name: "duplicate part", value: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true, errContains: "duplicate rule part"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.value)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rule.String())
+		})
+	}
+}
+
+func TestRule_Between(t *testing.T) {
+	start := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []string
+	}{
+		{
+			name:  "monthly skips missing days",
+			rule:  "FREQ=MONTHLY",
+			start: start,
+			want:  []string{"2024-01-31", "2024-03-31", "2024-05-31"},
+		},
+		{
+			name:  "last day of month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=4",
+			start: start,
+			want:  []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"},
+		},
+		{
+			name:  "last friday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20240331",
+			start: start,
+			want:  []string{"2024-02-23", "2024-03-29"},
+		},
+		{
+			name:  "fortnightly on monday and friday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=5",
+			start: time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC),
+			want:  []string{"2024-01-05", "2024-01-15", "2024-01-19", "2024-01-29", "2024-02-02"},
+		},
+		{
+			name:  "quarterly",
+			rule:  "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=15",
+			start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+			want:  []string{"2024-01-15", "2024-04-15"},
+		},
+		{
+			name:  "weekdays",
+			rule:  "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=3",
+			start: time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC),
+			want:  []string{"2024-01-05", "2024-01-08", "2024-01-09"},
+		},
+		{
+			name:  "yearly twice",
+			rule:  "FREQ=YEARLY;BYMONTH=1,6;BYMONTHDAY=1",
+			start: time.Date(2023, 6, 1, 9, 0, 0, 0, time.UTC),
+			want:  []string{"2024-01-01", "2024-06-01"},
+		},
+		{
+			name:  "never matches",
+			rule:  "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			start: start,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			require.NoError(t, err)
+
+			var got []string
+			for _, occurrence := range rule.Between(tt.start, from, to) {
+				assert.Equal(t, 9, occurrence.Hour())
+				got = append(got, occurrence.Format(time.DateOnly))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRule_Includes(t *testing.T) {
+	rule, err := Parse("FREQ=MONTHLY;BYMONTHDAY=1")
+	require.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(t, rule.Includes(start, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, rule.Includes(start, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, rule.Includes(start, time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)))
+}